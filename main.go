@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
 	"github.com/k4mrul/kubernetes-mcp/src/client"
+	"github.com/k4mrul/kubernetes-mcp/src/output"
+	"github.com/k4mrul/kubernetes-mcp/src/policy"
+	"github.com/k4mrul/kubernetes-mcp/src/ratelimit"
+	"github.com/k4mrul/kubernetes-mcp/src/redact"
+	"github.com/k4mrul/kubernetes-mcp/src/telemetry"
+	"github.com/k4mrul/kubernetes-mcp/src/toolerror"
 	"github.com/k4mrul/kubernetes-mcp/src/tools"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -12,10 +21,58 @@ import (
 const Version = "0.1.0"
 
 func main() {
+	shutdownTracing, err := telemetry.Setup(context.Background(), Version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring tracing: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() { _ = shutdownTracing(context.Background()) }()
+
+	redactPolicy, err := redact.PolicyFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring redaction: %v\n", err)
+		os.Exit(1)
+	}
+
+	outputConfig, err := output.ConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring large results: %v\n", err)
+		os.Exit(1)
+	}
+
+	toolPolicy, err := policy.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tool policy: %v\n", err)
+		os.Exit(1)
+	}
+
+	limiter, err := ratelimit.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tool limits: %v\n", err)
+		os.Exit(1)
+	}
+
+	defaults := tools.NewSessionDefaults()
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(defaults.Forget)
+
 	s := server.NewMCPServer(
 		"MCP k8s Server",
 		Version,
 		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithLogging(),
+		server.WithToolHandlerMiddleware(output.Middleware(outputConfig)),
+		server.WithToolHandlerMiddleware(redact.Middleware(redactPolicy)),
+		server.WithToolHandlerMiddleware(toolerror.Middleware),
+		server.WithToolHandlerMiddleware(defaults.Middleware),
+		server.WithToolHandlerMiddleware(telemetry.ToolMiddleware),
+		server.WithToolHandlerMiddleware(auth.Middleware),
+		server.WithToolHandlerMiddleware(policy.Middleware(toolPolicy)),
+		server.WithToolHandlerMiddleware(ratelimit.Middleware(limiter)),
+		server.WithToolFilter(auth.ToolFilter),
+		server.WithHooks(hooks),
 	)
 
 	k8s, err := client.NewKubernetesClient()
@@ -24,10 +81,62 @@ func main() {
 		os.Exit(1)
 	}
 
-	tools.RegisterTools(s, k8s)
+	tools.RegisterTools(s, k8s, defaults)
+	tools.RegisterResources(s, k8s, redactPolicy)
+	tools.RegisterPrompts(s)
 
-	if err := server.ServeStdio(s); err != nil {
+	serve := func(s *server.MCPServer) error { return server.ServeStdio(s) }
+	switch transport := os.Getenv("MCP_TRANSPORT"); transport {
+	case "", "stdio":
+	case "sse":
+		serve = serveSSE
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid MCP_TRANSPORT %q: must be stdio or sse\n", transport)
+		os.Exit(1)
+	}
+	if err := serve(s); err != nil {
 		fmt.Fprintf(os.Stderr, "Error starting MCP server: %v\n", err)
+		_ = shutdownTracing(context.Background())
 		os.Exit(1)
 	}
 }
+
+// serveSSE serves the MCP server over HTTP with Server-Sent Events on MCP_HTTP_ADDR (default :8080).
+// Clients are authenticated and scoped per MCP_AUTH_FILE, and HTTPS with optional mTLS is enabled by
+// MCP_TLS_CERT_FILE, MCP_TLS_KEY_FILE and MCP_TLS_CLIENT_CA_FILE.
+func serveSSE(s *server.MCPServer) error {
+	addr := os.Getenv("MCP_HTTP_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+	var opts []server.SSEOption
+	if baseURL := os.Getenv("MCP_BASE_URL"); baseURL != "" {
+		opts = append(opts, server.WithBaseURL(baseURL))
+	}
+	var handler http.Handler = server.NewSSEServer(s, opts...)
+
+	if file := os.Getenv("MCP_AUTH_FILE"); file != "" {
+		config, err := auth.LoadConfig(file)
+		if err != nil {
+			return err
+		}
+		handler = config.Handler(handler)
+	} else {
+		fmt.Fprintln(os.Stderr, "Warning: MCP_AUTH_FILE is not set, every HTTP client has full access")
+	}
+
+	srv := &http.Server{Addr: addr, Handler: handler}
+	certFile, keyFile := os.Getenv("MCP_TLS_CERT_FILE"), os.Getenv("MCP_TLS_KEY_FILE")
+	if certFile == "" && keyFile == "" {
+		if os.Getenv("MCP_TLS_CLIENT_CA_FILE") != "" {
+			return fmt.Errorf("MCP_TLS_CLIENT_CA_FILE requires MCP_TLS_CERT_FILE and MCP_TLS_KEY_FILE")
+		}
+		return srv.ListenAndServe()
+	}
+	tlsConfig, err := auth.TLSConfig(certFile, keyFile, os.Getenv("MCP_TLS_CLIENT_CA_FILE"))
+	if err != nil {
+		return err
+	}
+	srv.TLSConfig = tlsConfig
+	return srv.ListenAndServeTLS("", "")
+}
@@ -0,0 +1,275 @@
+// Environment variables used by this package:
+// Optional:
+//   MCP_AUTH_FILE                  - Path to a YAML or JSON file mapping HTTP clients to credentials, tools and namespaces
+//   MCP_TLS_CERT_FILE              - Server certificate for HTTPS
+//   MCP_TLS_KEY_FILE               - Server private key for HTTPS
+//   MCP_TLS_CLIENT_CA_FILE         - CA bundle that client certificates are verified against (enables mTLS)
+
+// Package auth authenticates clients of the HTTP transport and scopes each of them to a tool
+// allowlist and a set of namespaces.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"sigs.k8s.io/yaml"
+)
+
+// tokenHashPrefix marks a token stored as the hex SHA-256 of its value.
+const tokenHashPrefix = "sha256:"
+
+// Client is one identity allowed to use the server. Tools, Namespaces and Contexts are glob patterns
+// as in path.Match; empty Tools and Namespaces allow everything, while Contexts, the kubeconfig
+// contexts the client may switch to, allow none unless listed.
+type Client struct {
+	Name        string   `json:"name"`
+	Tokens      []string `json:"tokens,omitempty"`
+	CommonNames []string `json:"commonNames,omitempty"`
+	Tools       []string `json:"tools,omitempty"`
+	Namespaces  []string `json:"namespaces,omitempty"`
+	Contexts    []string `json:"contexts,omitempty"`
+}
+
+// Config is the client file.
+type Config struct {
+	Clients []Client `json:"clients"`
+}
+
+// LoadConfig reads and validates the client file.
+func LoadConfig(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth file: %w", err)
+	}
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse auth file %s: %w", file, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid auth file %s: %w", file, err)
+	}
+	return &config, nil
+}
+
+// validate requires unique names, a credential per client and valid patterns and token hashes.
+func (c *Config) validate() error {
+	if len(c.Clients) == 0 {
+		return errors.New("no clients defined")
+	}
+	names := map[string]bool{}
+	for i, client := range c.Clients {
+		if client.Name == "" {
+			return fmt.Errorf("client %d: name is required", i+1)
+		}
+		if names[client.Name] {
+			return fmt.Errorf("client %s: duplicate name", client.Name)
+		}
+		names[client.Name] = true
+		if len(client.Tokens) == 0 && len(client.CommonNames) == 0 {
+			return fmt.Errorf("client %s: at least one of tokens or commonNames is required", client.Name)
+		}
+		for _, token := range client.Tokens {
+			if hash, ok := strings.CutPrefix(token, tokenHashPrefix); ok {
+				if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+					return fmt.Errorf("client %s: %s tokens must be 64 hex characters", client.Name, tokenHashPrefix)
+				}
+			}
+		}
+		patterns := append(append(append([]string{}, client.Tools...), client.Namespaces...), client.Contexts...)
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("client %s: invalid pattern %q", client.Name, p)
+			}
+		}
+	}
+	return nil
+}
+
+// clientKey is the context key of the authenticated client.
+type clientKey struct{}
+
+// WithClient returns a context carrying the authenticated client.
+func WithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the authenticated client, or nil for stdio and unauthenticated transports.
+func ClientFromContext(ctx context.Context) *Client {
+	client, _ := ctx.Value(clientKey{}).(*Client)
+	return client
+}
+
+// Authenticate identifies the client of a request by its verified TLS client certificate or its
+// bearer token.
+func (c *Config) Authenticate(r *http.Request) (*Client, error) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		for i := range c.Clients {
+			for _, name := range c.Clients[i].CommonNames {
+				if name == cn {
+					return &c.Clients[i], nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("client certificate %q is not mapped to a client", cn)
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil, errors.New("missing bearer token or client certificate")
+	}
+	sum := sha256.Sum256([]byte(token))
+	hash := hex.EncodeToString(sum[:])
+	for i := range c.Clients {
+		for _, want := range c.Clients[i].Tokens {
+			if tokenMatches(want, token, hash) {
+				return &c.Clients[i], nil
+			}
+		}
+	}
+	return nil, errors.New("invalid bearer token")
+}
+
+// tokenMatches compares a configured token, plain or hashed, in constant time.
+func tokenMatches(want, token, hash string) bool {
+	if wantHash, ok := strings.CutPrefix(want, tokenHashPrefix); ok {
+		return subtle.ConstantTimeCompare([]byte(strings.ToLower(wantHash)), []byte(hash)) == 1
+	}
+	return subtle.ConstantTimeCompare([]byte(want), []byte(token)) == 1
+}
+
+// Handler rejects unauthenticated requests and passes the client on in the request context.
+func (c *Config) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, err := c.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithClient(r.Context(), client)))
+	})
+}
+
+// Authorize checks a tool call against the client's tool allowlist and namespace scope. A client
+// scoped to namespaces may only make calls that name one of them, through the namespace argument or
+// the other namespace-bearing arguments of the tool, and no cluster-wide calls.
+func (c *Client) Authorize(tool string, args map[string]any) error {
+	if len(c.Tools) > 0 && !matchAny(c.Tools, tool) {
+		return fmt.Errorf("client %s is not allowed to use %s", c.Name, tool)
+	}
+	if len(c.Namespaces) == 0 || policy.Delegates(tool) {
+		return nil
+	}
+	if policy.ClusterWide(tool, args) {
+		return fmt.Errorf("client %s is scoped to namespaces %s: %s reaches every namespace", c.Name, strings.Join(c.Namespaces, ", "), tool)
+	}
+	namespaces := policy.Namespaces(tool, args)
+	if len(namespaces) == 0 {
+		return fmt.Errorf("client %s is scoped to namespaces %s: pass one of them as namespace", c.Name, strings.Join(c.Namespaces, ", "))
+	}
+	for _, namespace := range namespaces {
+		if !c.AllowsNamespace(namespace) {
+			return fmt.Errorf("client %s is not allowed to access namespace %s", c.Name, namespace)
+		}
+	}
+	return nil
+}
+
+// AllowsNamespace reports whether the client may access objects in the namespace.
+func (c *Client) AllowsNamespace(namespace string) bool {
+	return len(c.Namespaces) == 0 || matchAny(c.Namespaces, namespace)
+}
+
+// AuthorizeNamespace checks that the client of the context, if any, may access the namespace. Tools
+// call it for namespaces that do not come from the namespace argument, such as the namespace a probe
+// pod runs in or a Flux source is read from.
+func AuthorizeNamespace(ctx context.Context, namespace string) error {
+	if client := ClientFromContext(ctx); client != nil && !client.AllowsNamespace(namespace) {
+		return fmt.Errorf("client %s is not allowed to access namespace %s", client.Name, namespace)
+	}
+	return nil
+}
+
+// AuthorizeContext checks that the client of the context, if any, may switch to the kubeconfig
+// context. Tools call it before running calls against another context.
+func AuthorizeContext(ctx context.Context, kubeContext string) error {
+	if client := ClientFromContext(ctx); client != nil && !matchAny(client.Contexts, kubeContext) {
+		return fmt.Errorf("client %s is not allowed to use context %s", client.Name, kubeContext)
+	}
+	return nil
+}
+
+// Middleware authorizes every tool call of an authenticated client.
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if client := ClientFromContext(ctx); client != nil {
+			if err := client.Authorize(req.Params.Name, req.Params.Arguments); err != nil {
+				return nil, err
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// ToolFilter hides the tools an authenticated client may not use from tools/list.
+func ToolFilter(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	client := ClientFromContext(ctx)
+	if client == nil || len(client.Tools) == 0 {
+		return tools
+	}
+	allowed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if matchAny(client.Tools, tool.Name) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// TLSConfig loads the server certificate and, when a client CA is given, verifies client
+// certificates against it. Clients without a certificate can still use a bearer token.
+func TLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return config, nil
+}
+
+// matchAny reports whether the value matches one of the glob patterns.
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig(t *testing.T) *Config {
+	sum := sha256.Sum256([]byte("payments-token"))
+	config := &Config{Clients: []Client{
+		{Name: "payments", Tokens: []string{"sha256:" + hex.EncodeToString(sum[:])}, Tools: []string{"list_resources", "get_pod_logs"}, Namespaces: []string{"payments-*"}},
+		{Name: "platform", Tokens: []string{"platform-token"}, CommonNames: []string{"platform-bot"}},
+	}}
+	assert.NoError(t, config.validate())
+	return config
+}
+
+func TestLoadConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "auth.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte("clients:\n  - name: ci\n    tokens: [abc]\n    tools: [\"list_*\"]\n"), 0o600))
+	config, err := LoadConfig(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []Client{{Name: "ci", Tokens: []string{"abc"}, Tools: []string{"list_*"}}}, config.Clients)
+
+	assert.NoError(t, os.WriteFile(file, []byte("clients:\n  - name: ci\n"), 0o600))
+	_, err = LoadConfig(file)
+	assert.ErrorContains(t, err, "tokens or commonNames")
+
+	assert.NoError(t, os.WriteFile(file, []byte("clients:\n  - name: ci\n    tokens: [\"sha256:abc\"]\n"), 0o600))
+	_, err = LoadConfig(file)
+	assert.ErrorContains(t, err, "64 hex characters")
+}
+
+func TestAuthenticate(t *testing.T) {
+	config := testConfig(t)
+	request := func(header string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/message", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		return r
+	}
+
+	client, err := config.Authenticate(request("Bearer payments-token"))
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", client.Name)
+
+	client, err = config.Authenticate(request("Bearer platform-token"))
+	assert.NoError(t, err)
+	assert.Equal(t, "platform", client.Name)
+
+	_, err = config.Authenticate(request("Bearer wrong"))
+	assert.EqualError(t, err, "invalid bearer token")
+	_, err = config.Authenticate(request(""))
+	assert.Error(t, err)
+
+	r := request("")
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "platform-bot"}}}}}
+	client, err = config.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "platform", client.Name)
+}
+
+func TestHandler(t *testing.T) {
+	var got *Client
+	handler := testConfig(t).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ClientFromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sse", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Nil(t, got)
+
+	r := httptest.NewRequest(http.MethodGet, "/sse", nil)
+	r.Header.Set("Authorization", "Bearer platform-token")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "platform", got.Name)
+}
+
+func TestAuthorize(t *testing.T) {
+	client := &testConfig(t).Clients[0]
+	assert.NoError(t, client.Authorize("list_resources", map[string]any{"namespace": "payments-api"}))
+	assert.ErrorContains(t, client.Authorize("drain_node", map[string]any{"namespace": "payments-api"}), "not allowed to use drain_node")
+	assert.ErrorContains(t, client.Authorize("list_resources", map[string]any{"namespace": "kube-system"}), "not allowed to access namespace kube-system")
+	assert.ErrorContains(t, client.Authorize("list_resources", nil), "pass one of them")
+	assert.NoError(t, (&Client{Name: "admin"}).Authorize("drain_node", nil))
+	assert.True(t, client.AllowsNamespace("payments-api"))
+	assert.False(t, client.AllowsNamespace("kube-system"))
+	assert.True(t, (&Client{Name: "admin"}).AllowsNamespace("kube-system"))
+}
+
+func TestAuthorizeNamespaceArguments(t *testing.T) {
+	client := &Client{Name: "payments", Namespaces: []string{"payments-*"}}
+	restore := map[string]any{"backupName": "nightly", "veleroNamespace": "payments-velero"}
+	assert.ErrorContains(t, client.Authorize("velero_restore", restore), "velero_restore reaches every namespace")
+	restore["includedNamespaces"] = []any{"payments-api"}
+	assert.NoError(t, client.Authorize("velero_restore", restore))
+	restore["namespaceMapping"] = map[string]any{"payments-api": "prod-api"}
+	assert.ErrorContains(t, client.Authorize("velero_restore", restore), "not allowed to access namespace prod-api")
+	restore["includedNamespaces"] = []any{"*"}
+	assert.ErrorContains(t, client.Authorize("velero_restore", restore), "reaches every namespace")
+
+	assert.ErrorContains(t, client.Authorize("netpol_analyze", map[string]any{"sourceNamespace": "payments-api", "destinationNamespace": "kube-system"}), "namespace kube-system")
+	assert.ErrorContains(t, client.Authorize("netpol_analyze", map[string]any{"destinationNamespace": "payments-api"}), "namespace default")
+	assert.ErrorContains(t, client.Authorize("seal_secret", map[string]any{"namespace": "payments-api"}), "namespace kube-system")
+	assert.NoError(t, client.Authorize("seal_secret", map[string]any{"namespace": "payments-api", "controllerNamespace": "payments-sealed"}))
+	assert.ErrorContains(t, client.Authorize("node_provisioning_status", nil), "namespace kube-system")
+	assert.ErrorContains(t, client.Authorize("diff_environments", map[string]any{"namespaceA": "payments-api", "namespaceB": "prod-api"}), "namespace prod-api")
+	assert.NoError(t, client.Authorize("diff_environments", map[string]any{"namespaceA": "payments-api", "contextA": "staging", "contextB": "prod"}))
+
+	assert.ErrorContains(t, client.Authorize("find_pods", map[string]any{"namespace": "payments-api"}), "find_pods reaches every namespace")
+	assert.ErrorContains(t, client.Authorize("cluster_health", map[string]any{"namespace": "payments-api"}), "cluster_health reaches every namespace")
+	assert.NoError(t, client.Authorize("batch_query", map[string]any{"queries": []any{}}))
+	assert.NoError(t, (&Client{Name: "admin"}).Authorize("velero_restore", map[string]any{"backupName": "nightly"}))
+}
+
+func TestAuthorizeContext(t *testing.T) {
+	ctx := WithClient(context.Background(), &Client{Name: "platform", Contexts: []string{"staging-*"}})
+	assert.NoError(t, AuthorizeContext(ctx, "staging-eu"))
+	assert.EqualError(t, AuthorizeContext(ctx, "prod"), "client platform is not allowed to use context prod")
+	assert.Error(t, AuthorizeContext(WithClient(context.Background(), &Client{Name: "admin"}), "staging-eu"), "contexts must be granted explicitly")
+	assert.NoError(t, AuthorizeContext(context.Background(), "prod"))
+}
+
+func TestAuthorizeNamespace(t *testing.T) {
+	ctx := WithClient(context.Background(), &testConfig(t).Clients[0])
+	assert.NoError(t, AuthorizeNamespace(ctx, "payments-api"))
+	assert.EqualError(t, AuthorizeNamespace(ctx, "kube-system"), "client payments is not allowed to access namespace kube-system")
+	assert.NoError(t, AuthorizeNamespace(context.Background(), "kube-system"))
+}
+
+func TestMiddlewareAndToolFilter(t *testing.T) {
+	handler := Middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	var req mcp.CallToolRequest
+	req.Params.Name = "drain_node"
+
+	// Stdio calls carry no client and are not restricted.
+	_, err := handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	ctx := WithClient(context.Background(), &testConfig(t).Clients[0])
+	_, err = handler(ctx, req)
+	assert.Error(t, err)
+
+	tools := []mcp.Tool{mcp.NewTool("list_resources"), mcp.NewTool("drain_node")}
+	assert.Len(t, ToolFilter(context.Background(), tools), 2)
+	filtered := ToolFilter(ctx, tools)
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "list_resources", filtered[0].Name)
+}
@@ -1,10 +1,17 @@
+// Environment variables used by this package:
+// Optional:
+//   DISCOVERY_CACHE_TTL            - How long API discovery results are reused across tool calls (default: 10m, 0 disables caching)
+
 package client
 
 import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
+	"github.com/k4mrul/kubernetes-mcp/src/telemetry"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
@@ -16,8 +23,16 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// defaultDiscoveryCacheTTL is how long discovery results are reused when DISCOVERY_CACHE_TTL is not set.
+const defaultDiscoveryCacheTTL = 10 * time.Minute
+
 type KubernetesClient struct {
 	config *rest.Config
+
+	discoveryTTL   time.Duration
+	discoveryMu    sync.Mutex
+	discovery      discovery.CachedDiscoveryInterface
+	discoveryReset time.Time
 }
 
 func NewKubernetesClient() (*KubernetesClient, error) {
@@ -40,24 +55,72 @@ func NewKubernetesClient() (*KubernetesClient, error) {
 			return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
 		}
 	}
-	return &KubernetesClient{config: config}, nil
+	return newKubernetesClient(config)
+}
+
+// NewKubernetesClientForContext creates a client for a context of the kubeconfig, which is read even
+// when the server runs in a cluster.
+func NewKubernetesClientForContext(name string) (*KubernetesClient, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: name},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig context %s: %w", name, err)
+	}
+	return newKubernetesClient(config)
+}
+
+func newKubernetesClient(config *rest.Config) (*KubernetesClient, error) {
+	config.Wrap(telemetry.WrapTransport)
+
+	ttl := defaultDiscoveryCacheTTL
+	if v := os.Getenv("DISCOVERY_CACHE_TTL"); v != "" {
+		var err error
+		if ttl, err = time.ParseDuration(v); err != nil || ttl < 0 {
+			return nil, fmt.Errorf("invalid DISCOVERY_CACHE_TTL %q: expected a duration like 10m", v)
+		}
+	}
+	return &KubernetesClient{config: config, discoveryTTL: ttl}, nil
 }
 
 func (k *KubernetesClient) DynamicClient() (dynamic.Interface, error) {
 	return dynamic.NewForConfig(k.config)
 }
+
+// DiscoClient returns a discovery client shared by all tools. Its results are cached in memory and
+// dropped after DISCOVERY_CACHE_TTL or when the client is invalidated, e.g. by refresh_discovery.
 func (k *KubernetesClient) DiscoClient() (discovery.DiscoveryInterface, error) {
-	return discovery.NewDiscoveryClientForConfig(k.config)
+	return k.cachedDiscovery()
 }
+
+// cachedDiscovery creates the shared discovery cache on first use and expires it after the TTL.
+func (k *KubernetesClient) cachedDiscovery() (discovery.CachedDiscoveryInterface, error) {
+	k.discoveryMu.Lock()
+	defer k.discoveryMu.Unlock()
+	if k.discovery == nil {
+		disco, err := discovery.NewDiscoveryClientForConfig(k.config)
+		if err != nil {
+			return nil, err
+		}
+		k.discovery = memory.NewMemCacheClient(disco)
+		k.discoveryReset = time.Now()
+	} else if time.Since(k.discoveryReset) >= k.discoveryTTL {
+		k.discovery.Invalidate()
+		k.discoveryReset = time.Now()
+	}
+	return k.discovery, nil
+}
+
 func (k *KubernetesClient) Clientset() (*kubernetes.Clientset, error) {
 	return kubernetes.NewForConfig(k.config)
 }
 func (k *KubernetesClient) RESTMapper() (meta.RESTMapper, error) {
-	disco, err := k.DiscoClient()
+	disco, err := k.cachedDiscovery()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create discovery client: %w", err)
 	}
-	return restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(disco)), nil
+	return restmapper.NewDeferredDiscoveryRESTMapper(disco), nil
 }
 
 func (k *KubernetesClient) ResourceInterface(
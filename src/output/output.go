@@ -0,0 +1,286 @@
+// Environment variables used by this package:
+// Optional:
+//   RESULT_MODE                    - What to do with results above RESULT_MAX_KB: "off" (default), "chunk", "gzip" or "summarize"
+//   RESULT_MAX_KB                  - Size in KiB above which a result is chunked, compressed or summarized (default: 256)
+//   RESULT_CHUNK_KB                - Size in KiB of each part in chunk mode (default: 64)
+
+// Package output keeps large tool results, like showDetails listings of big CRDs, from
+// overflowing the transport or the model's context by splitting, compressing or summarizing them.
+package output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Mode decides how a result above the size limit is delivered.
+type Mode string
+
+const (
+	// Off returns results unchanged.
+	Off Mode = "off"
+	// Chunk splits the text into several content parts after a header.
+	Chunk Mode = "chunk"
+	// Gzip returns a summary header and the text as a gzip-compressed, base64-encoded blob.
+	Gzip Mode = "gzip"
+	// Summarize replaces the text with its outline and the beginning and end of the text.
+	Summarize Mode = "summarize"
+)
+
+const (
+	defaultMaxKB   = 256
+	defaultChunkKB = 64
+	// outlineKeys is the number of keys of an object kept in an outline.
+	outlineKeys = 50
+	// outlineString is the length after which strings are cut in an outline.
+	outlineString = 200
+)
+
+// Config selects the mode and the sizes, in bytes, that apply to it.
+type Config struct {
+	Mode       Mode
+	MaxBytes   int
+	ChunkBytes int
+}
+
+// ParseMode parses a mode name; an empty name selects Off.
+func ParseMode(name string) (Mode, error) {
+	switch m := Mode(strings.ToLower(strings.TrimSpace(name))); m {
+	case "":
+		return Off, nil
+	case Off, Chunk, Gzip, Summarize:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid result mode %q: must be off, chunk, gzip or summarize", name)
+	}
+}
+
+// ConfigFromEnv reads the configuration from RESULT_MODE, RESULT_MAX_KB and RESULT_CHUNK_KB.
+func ConfigFromEnv() (Config, error) {
+	mode, err := ParseMode(os.Getenv("RESULT_MODE"))
+	if err != nil {
+		return Config{}, err
+	}
+	maxKB, err := kilobytesFromEnv("RESULT_MAX_KB", defaultMaxKB)
+	if err != nil {
+		return Config{}, err
+	}
+	chunkKB, err := kilobytesFromEnv("RESULT_CHUNK_KB", defaultChunkKB)
+	if err != nil {
+		return Config{}, err
+	}
+	return Config{Mode: mode, MaxBytes: maxKB * 1024, ChunkBytes: chunkKB * 1024}, nil
+}
+
+func kilobytesFromEnv(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	kb, err := strconv.Atoi(value)
+	if err != nil || kb < 1 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive number of KiB", name, value)
+	}
+	return kb, nil
+}
+
+// Middleware delivers successful results whose text is larger than the limit according to the
+// mode. It must run outside the redaction middleware, so that compressed results are scrubbed first.
+func Middleware(cfg Config) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if cfg.Mode == Off {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if result == nil || result.IsError {
+				return result, err
+			}
+			text, ok := resultText(result)
+			if !ok || len(text) <= cfg.MaxBytes {
+				return result, err
+			}
+			content, shapeErr := cfg.shape(req.Params.Name, text)
+			if shapeErr != nil {
+				return nil, fmt.Errorf("failed to shape large result of %s: %w", req.Params.Name, shapeErr)
+			}
+			result.Content = content
+			return result, err
+		}
+	}
+}
+
+// resultText returns the text of a result that consists only of text content.
+func resultText(result *mcp.CallToolResult) (string, bool) {
+	var text strings.Builder
+	for _, c := range result.Content {
+		t, ok := c.(mcp.TextContent)
+		if !ok {
+			return "", false
+		}
+		text.WriteString(t.Text)
+	}
+	return text.String(), len(result.Content) > 0
+}
+
+// shape returns the content that delivers text according to the mode.
+func (cfg Config) shape(tool, text string) ([]mcp.Content, error) {
+	switch cfg.Mode {
+	case Chunk:
+		parts := split(text, cfg.ChunkBytes)
+		header, err := json.Marshal(map[string]any{
+			"chunked": true,
+			"bytes":   len(text),
+			"parts":   len(parts),
+			"hint":    "the result is split into the following parts; concatenate them in order to read it",
+		})
+		if err != nil {
+			return nil, err
+		}
+		content := []mcp.Content{mcp.NewTextContent(string(header))}
+		for _, part := range parts {
+			content = append(content, mcp.NewTextContent(part))
+		}
+		return content, nil
+	case Gzip:
+		var compressed bytes.Buffer
+		w := gzip.NewWriter(&compressed)
+		if _, err := w.Write([]byte(text)); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		summary := Summary(text, cfg.MaxBytes)
+		summary["encoding"] = "gzip+base64"
+		summary["compressedBytes"] = compressed.Len()
+		header, err := json.Marshal(summary)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.Content{
+			mcp.NewTextContent(string(header)),
+			mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+				URI:      "result://" + tool + ".json.gz",
+				MIMEType: "application/gzip",
+				Blob:     base64.StdEncoding.EncodeToString(compressed.Bytes()),
+			}),
+		}, nil
+	default:
+		summary := Summary(text, cfg.MaxBytes)
+		summary["hint"] = "the result was summarized because it is too large: narrow the call with a namespace, label selector or limit, or turn off details"
+		out, err := json.Marshal(summary)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.Content{mcp.NewTextContent(string(out))}, nil
+	}
+}
+
+// split cuts text into parts of at most size bytes without splitting a UTF-8 sequence.
+func split(text string, size int) []string {
+	var parts []string
+	for len(text) > size {
+		cut := size
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = size
+		}
+		parts = append(parts, text[:cut])
+		text = text[cut:]
+	}
+	return append(parts, text)
+}
+
+// Summary describes a large result in a fraction of maxBytes, at most about 16 KiB: the outline of a JSON document,
+// as deep as fits, or the beginning and end of any other text.
+func Summary(text string, maxBytes int) map[string]any {
+	summary := map[string]any{"bytes": len(text)}
+
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err == nil && !decoder.More() {
+		limit := min(maxBytes/2, 16*1024)
+		for depth := 4; depth >= 0; depth-- {
+			o := outline(doc, depth)
+			if out, err := json.Marshal(o); err == nil && len(out) <= limit {
+				summary["outline"] = o
+				return summary
+			}
+		}
+	}
+
+	head, tail := min(maxBytes/2, 8*1024, len(text)), min(maxBytes/8, 2*1024, len(text))
+	summary["head"] = cutString(text[:head], true)
+	summary["tail"] = cutString(text[len(text)-tail:], false)
+	return summary
+}
+
+// outline replaces arrays by their length and first element, cuts long strings and drops keys
+// beyond the first outlineKeys of an object, down to depth levels of nesting.
+func outline(v any, depth int) any {
+	switch t := v.(type) {
+	case map[string]any:
+		if depth == 0 {
+			return fmt.Sprintf("{%d keys}", len(t))
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := map[string]any{}
+		for i, k := range keys {
+			if i == outlineKeys {
+				out["..."] = fmt.Sprintf("%d more keys", len(keys)-outlineKeys)
+				break
+			}
+			out[k] = outline(t[k], depth-1)
+		}
+		return out
+	case []any:
+		if depth == 0 || len(t) == 0 {
+			return fmt.Sprintf("[%d items]", len(t))
+		}
+		return map[string]any{"items": len(t), "first": outline(t[0], depth-1)}
+	case string:
+		if len(t) > outlineString {
+			return cutString(t[:outlineString], true) + "..."
+		}
+		return t
+	default:
+		return t
+	}
+}
+
+// cutString drops the partial UTF-8 sequence left at the end (or start) of a cut string.
+func cutString(s string, atEnd bool) string {
+	if atEnd {
+		for len(s) > 0 {
+			if r, size := utf8.DecodeLastRuneInString(s); r != utf8.RuneError || size != 1 {
+				break
+			}
+			s = s[:len(s)-1]
+		}
+		return s
+	}
+	for len(s) > 0 && !utf8.RuneStart(s[0]) {
+		s = s[1:]
+	}
+	return s
+}
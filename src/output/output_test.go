@@ -0,0 +1,114 @@
+package output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMode(t *testing.T) {
+	for name, want := range map[string]Mode{"": Off, "chunk": Chunk, " GZIP ": Gzip, "summarize": Summarize} {
+		got, err := ParseMode(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+	_, err := ParseMode("zip")
+	assert.Error(t, err)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("RESULT_MODE", "chunk")
+	t.Setenv("RESULT_MAX_KB", "8")
+	cfg, err := ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, Config{Mode: Chunk, MaxBytes: 8 * 1024, ChunkBytes: 64 * 1024}, cfg)
+
+	t.Setenv("RESULT_CHUNK_KB", "0")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func largeList(items int) string {
+	list := make([]map[string]any, items)
+	for i := range list {
+		list[i] = map[string]any{"name": "crd", "spec": map[string]any{"description": strings.Repeat("é", 300)}}
+	}
+	out, _ := json.Marshal(map[string]any{"kind": "List", "items": list})
+	return string(out)
+}
+
+func call(t *testing.T, cfg Config, text string) *mcp.CallToolResult {
+	handler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(text), nil
+	}
+	var req mcp.CallToolRequest
+	req.Params.Name = "list_resources"
+	result, err := Middleware(cfg)(handler)(context.Background(), req)
+	assert.NoError(t, err)
+	return result
+}
+
+func TestMiddlewareSmallResult(t *testing.T) {
+	result := call(t, Config{Mode: Summarize, MaxBytes: 1024}, `{"items":[]}`)
+	assert.Equal(t, `{"items":[]}`, result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestMiddlewareChunk(t *testing.T) {
+	text := largeList(20)
+	result := call(t, Config{Mode: Chunk, MaxBytes: 1024, ChunkBytes: 1001}, text)
+
+	var header map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &header))
+	assert.Equal(t, float64(len(result.Content)-1), header["parts"])
+
+	var joined strings.Builder
+	for _, c := range result.Content[1:] {
+		part := c.(mcp.TextContent).Text
+		assert.LessOrEqual(t, len(part), 1001)
+		assert.True(t, strings.ToValidUTF8(part, "?") == part)
+		joined.WriteString(part)
+	}
+	assert.Equal(t, text, joined.String())
+}
+
+func TestMiddlewareGzip(t *testing.T) {
+	text := largeList(20)
+	result := call(t, Config{Mode: Gzip, MaxBytes: 1024}, text)
+	assert.Len(t, result.Content, 2)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"encoding":"gzip+base64"`)
+
+	blob := result.Content[1].(mcp.EmbeddedResource).Resource.(mcp.BlobResourceContents)
+	compressed, err := base64.StdEncoding.DecodeString(blob.Blob)
+	assert.NoError(t, err)
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	assert.NoError(t, err)
+	plain, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, text, string(plain))
+}
+
+func TestMiddlewareSummarize(t *testing.T) {
+	result := call(t, Config{Mode: Summarize, MaxBytes: 4096}, largeList(20))
+	var summary map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &summary))
+	outline := summary["outline"].(map[string]any)
+	assert.Equal(t, "List", outline["kind"])
+	assert.Equal(t, float64(20), outline["items"].(map[string]any)["items"])
+	assert.Contains(t, summary, "hint")
+}
+
+func TestSummaryText(t *testing.T) {
+	text := strings.Repeat("line\n", 1000)
+	summary := Summary(text, 1024)
+	assert.Equal(t, 5000, summary["bytes"])
+	assert.Len(t, summary["head"], 512)
+	assert.Len(t, summary["tail"], 128)
+}
@@ -0,0 +1,209 @@
+package policy
+
+import "strings"
+
+// Access levels a tool call is classified as.
+const (
+	AccessRead  = "read"
+	AccessWrite = "write"
+)
+
+// writeTools change cluster or cloud state on every call that is not a dry run.
+var writeTools = map[string]bool{
+	"cordon_node":             true,
+	"uncordon_node":           true,
+	"drain_node":              true,
+	"create_namespace":        true,
+	"rollout_restart":         true,
+	"flux_reconcile":          true,
+	"flux_suspend":            true,
+	"flux_resume":             true,
+	"certmanager_renew":       true,
+	"externalsecrets_refresh": true,
+	"velero_backup_create":    true,
+	"velero_restore":          true,
+	"knative_rollback":        true,
+	"helm_rollback":           true,
+	"helm_set_values":         true,
+	"change_env":              true,
+	"secret_rollback":         true,
+	"gcp_secret_create":       true,
+	"gcp_secret_delete":       true,
+}
+
+// readActions are the actions of multi-purpose tools that only read; their other actions write.
+var readActions = map[string]map[string]bool{
+	"configmap_edit":  {"get": true},
+	"k8s_secret":      {"list": true, "get": true},
+	"cronjob_control": {"status": true},
+	"job_control":     {"failures": true},
+	"csr_manage":      {"list": true},
+}
+
+// dryRunTools are the write tools whose handlers implement dryRun, so a dry run only reads. Other
+// tools ignore the argument and would still write.
+var dryRunTools = map[string]bool{
+	"drain_node":      true,
+	"rollout_restart": true,
+	"change_env":      true,
+	"configmap_edit":  true,
+	"k8s_secret":      true,
+	"job_control":     true,
+	"csr_manage":      true,
+	"helm_rollback":   true,
+	"helm_set_values": true,
+}
+
+// probeArguments name the argument of read tools that, when set, makes them run a probe pod from a
+// caller-supplied image.
+var probeArguments = map[string]string{
+	"check_service": "checkDNS",
+	"dns_health":    "lookup",
+}
+
+// writeArguments name the argument of otherwise read tools that, when set, makes a call write:
+// switch the context later calls act on, or write a file on the server.
+var writeArguments = map[string]string{
+	"set_defaults":     "context",
+	"export_namespace": "path",
+}
+
+// readTools never change cluster or cloud state. Only these tools, and the read actions of
+// readActions, pass ReadOnly.
+var readTools = map[string]bool{
+	"list_resources":           true,
+	"get_pod_logs":             true,
+	"describe_resource":        true,
+	"list_ingress_paths":       true,
+	"find_route":               true,
+	"refresh_discovery":        true,
+	"top_pods":                 true,
+	"top_nodes":                true,
+	"cluster_capacity":         true,
+	"why_pending":              true,
+	"daemonset_coverage":       true,
+	"diagnose_pod":             true,
+	"namespace_health":         true,
+	"cluster_health":           true,
+	"control_plane_status":     true,
+	"list_leases":              true,
+	"find_pods":                true,
+	"restart_report":           true,
+	"probe_audit":              true,
+	"security_audit":           true,
+	"resources_audit":          true,
+	"cost_report":              true,
+	"list_images":              true,
+	"vuln_report":              true,
+	"check_image_pull":         true,
+	"pdb_check":                true,
+	"check_service":            true,
+	"dns_health":               true,
+	"validate_ingress":         true,
+	"netpol_analyze":           true,
+	"can_i":                    true,
+	"who_can":                  true,
+	"sa_audit":                 true,
+	"cert_expiry":              true,
+	"webhook_audit":            true,
+	"version_report":           true,
+	"object_tree":              true,
+	"export_namespace":         true,
+	"diff_environments":        true,
+	"validate_manifest":        true,
+	"generate_manifest":        true,
+	"diff_apply":               true,
+	"watch_resources":          true,
+	"flux_status":              true,
+	"flux_tree":                true,
+	"git_drift":                true,
+	"kustomize_build":          true,
+	"istio_analyze":            true,
+	"certmanager_status":       true,
+	"externalsecrets_status":   true,
+	"seal_secret":              true,
+	"keda_status":              true,
+	"node_provisioning_status": true,
+	"storage_report":           true,
+	"pvc_usage":                true,
+	"velero_backups":           true,
+	"policy_violations":        true,
+	"loki_query":               true,
+	"gke_cluster_info":         true,
+	"gcp_logs_query":           true,
+	"gcp_list_image_tags":      true,
+	"secret_list":              true,
+	"secret_versions":          true,
+	"secret_diff":              true,
+	"set_defaults":             true,
+	"batch_query":              true,
+}
+
+// Access classifies a tool call as read or write from the tool name and its action, dryRun, probe
+// and write arguments. dryRun only makes a call a read for tools that implement it. Tools that are
+// not classified are writes, so a new tool is never allowed by a read-only rule by omission.
+func Access(tool string, args map[string]any) string {
+	if dryRun, _ := args["dryRun"].(bool); dryRun && dryRunTools[tool] {
+		return AccessRead
+	}
+	if probeRequested(tool, args) || writeRequested(tool, args) {
+		return AccessWrite
+	}
+	if actions, ok := readActions[tool]; ok {
+		action, _ := args["action"].(string)
+		if actions[strings.ToLower(action)] {
+			return AccessRead
+		}
+		return AccessWrite
+	}
+	if readTools[tool] {
+		return AccessRead
+	}
+	return AccessWrite
+}
+
+// Classified reports whether the tool is known as a read tool, a write tool or a tool with read
+// and write actions.
+func Classified(tool string) bool {
+	_, actions := readActions[tool]
+	return readTools[tool] || writeTools[tool] || actions
+}
+
+// ReadOnly reports whether a call is known not to change anything whatever its dryRun argument:
+// the tool is one of readTools and runs no probe and sets no write argument, or the action is a
+// read action. Unknown tools are not read-only.
+func ReadOnly(tool string, args map[string]any) bool {
+	if probeRequested(tool, args) || writeRequested(tool, args) {
+		return false
+	}
+	if actions, ok := readActions[tool]; ok {
+		action, _ := args["action"].(string)
+		return actions[strings.ToLower(action)]
+	}
+	return readTools[tool]
+}
+
+// probeRequested reports whether the call asks a read tool to run a probe pod.
+func probeRequested(tool string, args map[string]any) bool {
+	name, ok := probeArguments[tool]
+	if !ok {
+		return false
+	}
+	switch v := args[name].(type) {
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+	return false
+}
+
+// writeRequested reports whether the call sets the write argument of its tool.
+func writeRequested(tool string, args map[string]any) bool {
+	name, ok := writeArguments[tool]
+	if !ok {
+		return false
+	}
+	value, _ := args[name].(string)
+	return value != ""
+}
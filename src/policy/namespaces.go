@@ -0,0 +1,153 @@
+package policy
+
+import (
+	"sort"
+	"strings"
+)
+
+// namespaceArgument is an argument that names namespaces a call reads or changes, and the namespace
+// the tool uses when it is empty ("" when it then uses none, or one covered by another argument).
+type namespaceArgument struct {
+	name     string
+	fallback string
+}
+
+// namespaceArguments declare the namespace-bearing arguments of the tools that have others than
+// namespace. Values are a namespace, a list of namespaces, or a mapping whose keys and values are
+// namespaces. Tools not listed only take namespace.
+var namespaceArguments = map[string][]namespaceArgument{
+	"create_namespace":         {{name: "name"}},
+	"diff_environments":        {{name: "namespaceA"}, {name: "namespaceB"}},
+	"dns_health":               {{name: "namespace", fallback: "kube-system"}, {name: "probeNamespace", fallback: "default"}},
+	"git_drift":                {{name: "namespace", fallback: "default"}, {name: "sourceNamespace", fallback: "flux-system"}},
+	"kustomize_build":          {{name: "namespace"}, {name: "fluxNamespace", fallback: "flux-system"}},
+	"netpol_analyze":           {{name: "sourceNamespace", fallback: "default"}, {name: "destinationNamespace"}},
+	"node_provisioning_status": {{name: "autoscalerNamespace", fallback: "kube-system"}},
+	"seal_secret":              {{name: "namespace", fallback: "default"}, {name: "controllerNamespace", fallback: "kube-system"}},
+	"velero_backups":           {{name: "veleroNamespace", fallback: "velero"}},
+	"velero_backup_create":     {{name: "includedNamespaces"}, {name: "veleroNamespace", fallback: "velero"}},
+	"velero_restore":           {{name: "includedNamespaces"}, {name: "namespaceMapping"}, {name: "veleroNamespace", fallback: "velero"}},
+}
+
+// allNamespacesArguments name the list argument of tools that act on every namespace when it is
+// empty or holds a wildcard.
+var allNamespacesArguments = map[string]string{
+	"velero_backup_create": "includedNamespaces",
+	"velero_restore":       "includedNamespaces",
+}
+
+// clusterWideTools read or change objects across namespaces, or cluster-scoped state, whatever
+// namespace they are passed.
+var clusterWideTools = map[string]bool{
+	"cluster_capacity":     true,
+	"cluster_health":       true,
+	"control_plane_status": true,
+	"cordon_node":          true,
+	"uncordon_node":        true,
+	"drain_node":           true,
+	"csr_manage":           true,
+	"refresh_discovery":    true,
+	"top_nodes":            true,
+	"version_report":       true,
+	"webhook_audit":        true,
+	"find_pods":            true,
+	"find_route":           true,
+	"loki_query":           true,
+	"gke_cluster_info":     true,
+	"gcp_logs_query":       true,
+	"gcp_list_image_tags":  true,
+	"gcp_secret_create":    true,
+	"gcp_secret_delete":    true,
+}
+
+// delegatingTools only call other tools, each of which is authorized on its own.
+var delegatingTools = map[string]bool{
+	"batch_query": true,
+}
+
+// Namespaces returns the namespaces a call names, sorted and without duplicates, with the fallback
+// of every empty namespace-bearing argument.
+func Namespaces(tool string, args map[string]any) []string {
+	arguments, ok := namespaceArguments[tool]
+	if !ok {
+		arguments = []namespaceArgument{{name: "namespace"}}
+	}
+	seen := map[string]bool{}
+	for _, argument := range arguments {
+		values := namespaceValues(args[argument.name])
+		if len(values) == 0 && argument.fallback != "" {
+			values = []string{argument.fallback}
+		}
+		for _, v := range values {
+			seen[v] = true
+		}
+	}
+	namespaces := make([]string, 0, len(seen))
+	for ns := range seen {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// ClusterWide reports whether a call reaches namespaces beyond those it names: the tool is
+// cluster-wide, or its namespace list is empty or holds a wildcard.
+func ClusterWide(tool string, args map[string]any) bool {
+	if clusterWideTools[tool] {
+		return true
+	}
+	name, ok := allNamespacesArguments[tool]
+	if !ok {
+		return false
+	}
+	values := namespaceValues(args[name])
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.ContainsAny(v, "*?[") {
+			return true
+		}
+	}
+	return false
+}
+
+// Delegates reports whether the tool only calls other tools, so that its own arguments name no
+// namespaces.
+func Delegates(tool string) bool {
+	return delegatingTools[tool]
+}
+
+// namespaceValues returns the non-empty namespaces of an argument value: a string, a list of
+// strings, or a mapping of namespaces to namespaces.
+func namespaceValues(value any) []string {
+	var values []string
+	add := func(v any) {
+		if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+			values = append(values, strings.TrimSpace(s))
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		add(v)
+	case []string:
+		for _, s := range v {
+			add(s)
+		}
+	case []any:
+		for _, s := range v {
+			add(s)
+		}
+	case map[string]string:
+		for from, to := range v {
+			add(from)
+			add(to)
+		}
+	case map[string]any:
+		for from, to := range v {
+			add(from)
+			add(to)
+		}
+	}
+	return values
+}
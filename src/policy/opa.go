@@ -0,0 +1,91 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// opaTimeout bounds a policy decision so an unreachable OPA fails the call instead of hanging it.
+const opaTimeout = 5 * time.Second
+
+// OPA asks an Open Policy Agent server for a decision through its Data API. Policies and bundles are
+// loaded by OPA itself; the decision must be a boolean or an object with allow and an optional reason.
+// Calls are denied when OPA cannot be reached or returns no decision.
+type OPA struct {
+	url    string
+	client *http.Client
+}
+
+// NewOPA creates an evaluator for the decision endpoint, e.g. http://localhost:8181/v1/data/mcp/allow.
+func NewOPA(url string) *OPA {
+	return &OPA{url: strings.TrimSuffix(url, "/"), client: &http.Client{Timeout: opaTimeout}}
+}
+
+// Evaluate posts the call as the input document and reads the decision.
+func (o *OPA) Evaluate(ctx context.Context, input Input) error {
+	body, err := json.Marshal(map[string]any{"input": input})
+	if err != nil {
+		return fmt.Errorf("failed to encode policy input: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create policy request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("denied: policy server unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("denied: failed to read policy decision: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("denied: policy server returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return opaDecision(respBody)
+}
+
+// opaDecision interprets a Data API response.
+func opaDecision(body []byte) error {
+	var decision struct {
+		Result *json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &decision); err != nil {
+		return fmt.Errorf("denied: failed to decode policy decision: %w", err)
+	}
+	if decision.Result == nil {
+		return errors.New("denied: policy returned no decision (is the policy loaded?)")
+	}
+
+	var allowed bool
+	if err := json.Unmarshal(*decision.Result, &allowed); err == nil {
+		if !allowed {
+			return errors.New("denied by policy")
+		}
+		return nil
+	}
+	var result struct {
+		Allow  bool   `json:"allow"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(*decision.Result, &result); err != nil {
+		return fmt.Errorf("denied: policy decision must be a boolean or an object with allow: %w", err)
+	}
+	if !result.Allow {
+		if result.Reason != "" {
+			return fmt.Errorf("denied by policy: %s", result.Reason)
+		}
+		return errors.New("denied by policy")
+	}
+	return nil
+}
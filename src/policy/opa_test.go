@@ -0,0 +1,40 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOPAEvaluate(t *testing.T) {
+	var got map[string]Input
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if got["input"].Namespace == "prod" {
+			_, _ = w.Write([]byte(`{"result":{"allow":false,"reason":"prod is read-only"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":true}`))
+	}))
+	defer srv.Close()
+	opa := NewOPA(srv.URL)
+
+	input := Input{Tool: "rollout_restart", Access: AccessWrite, Namespace: "prod", Arguments: map[string]any{"namespace": "prod"}}
+	assert.EqualError(t, opa.Evaluate(context.Background(), input), "denied by policy: prod is read-only")
+	assert.Equal(t, "rollout_restart", got["input"].Tool)
+
+	input.Namespace = "dev"
+	assert.NoError(t, opa.Evaluate(context.Background(), input))
+}
+
+func TestOPADecision(t *testing.T) {
+	assert.NoError(t, opaDecision([]byte(`{"result":true}`)))
+	assert.NoError(t, opaDecision([]byte(`{"result":{"allow":true}}`)))
+	assert.EqualError(t, opaDecision([]byte(`{"result":false}`)), "denied by policy")
+	assert.ErrorContains(t, opaDecision([]byte(`{}`)), "no decision")
+	assert.ErrorContains(t, opaDecision([]byte(`{"result":"yes"}`)), "boolean or an object")
+}
@@ -0,0 +1,231 @@
+// Environment variables used by this package:
+// Optional:
+//   TOOL_POLICY_FILE               - Path to a YAML or JSON file of allow/deny rules for tool calls
+//   OPA_URL                        - OPA decision endpoint consulted for every tool call, e.g. http://localhost:8181/v1/data/mcp/allow
+
+// Package policy authorizes tool calls against rules from a policy file and an optional OPA server
+// before their handlers run.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"sigs.k8s.io/yaml"
+)
+
+// Rule effects.
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Input is what a policy decides on. Namespace is the namespace argument; Namespaces are all the
+// namespaces the call names, and ClusterWide is set when it reaches every namespace.
+type Input struct {
+	Tool        string         `json:"tool"`
+	Access      string         `json:"access"`
+	Namespace   string         `json:"namespace,omitempty"`
+	Namespaces  []string       `json:"namespaces,omitempty"`
+	ClusterWide bool           `json:"clusterWide,omitempty"`
+	Arguments   map[string]any `json:"arguments"`
+}
+
+// Rule allows or denies the calls it matches. Empty fields match anything; patterns are globs as in
+// path.Match, so "prod-*" matches every namespace starting with prod-. A deny rule with namespaces
+// matches calls that reach any of them, an allow rule only calls whose namespaces all match.
+type Rule struct {
+	Effect     string              `json:"effect"`
+	Tools      []string            `json:"tools,omitempty"`
+	Access     string              `json:"access,omitempty"`
+	Namespaces []string            `json:"namespaces,omitempty"`
+	Arguments  map[string][]string `json:"arguments,omitempty"`
+	Message    string              `json:"message,omitempty"`
+}
+
+// Rules is a policy file: the first matching rule decides, and calls no rule matches get Default.
+type Rules struct {
+	Default string `json:"default,omitempty"`
+	Rules   []Rule `json:"rules"`
+}
+
+// Evaluator decides whether a tool call may run; a non-nil error denies it with the reason.
+type Evaluator interface {
+	Evaluate(ctx context.Context, input Input) error
+}
+
+// Load builds the evaluators configured through TOOL_POLICY_FILE and OPA_URL. Without either every
+// call is allowed.
+func Load() ([]Evaluator, error) {
+	var evaluators []Evaluator
+	if file := os.Getenv("TOOL_POLICY_FILE"); file != "" {
+		rules, err := LoadRules(file)
+		if err != nil {
+			return nil, err
+		}
+		evaluators = append(evaluators, rules)
+	}
+	if url := os.Getenv("OPA_URL"); url != "" {
+		evaluators = append(evaluators, NewOPA(url))
+	}
+	return evaluators, nil
+}
+
+// LoadRules reads and validates a policy file.
+func LoadRules(file string) (*Rules, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	var rules Rules
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", file, err)
+	}
+	if err := rules.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", file, err)
+	}
+	return &rules, nil
+}
+
+// validate checks effects, access levels and glob patterns, and defaults Default to allow.
+func (r *Rules) validate() error {
+	if r.Default == "" {
+		r.Default = EffectAllow
+	}
+	if r.Default != EffectAllow && r.Default != EffectDeny {
+		return fmt.Errorf("default must be %s or %s", EffectAllow, EffectDeny)
+	}
+	for i, rule := range r.Rules {
+		if rule.Effect != EffectAllow && rule.Effect != EffectDeny {
+			return fmt.Errorf("rule %d: effect must be %s or %s", i+1, EffectAllow, EffectDeny)
+		}
+		if rule.Access != "" && rule.Access != AccessRead && rule.Access != AccessWrite {
+			return fmt.Errorf("rule %d: access must be %s or %s", i+1, AccessRead, AccessWrite)
+		}
+		patterns := append(append([]string{}, rule.Tools...), rule.Namespaces...)
+		for _, values := range rule.Arguments {
+			patterns = append(patterns, values...)
+		}
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("rule %d: invalid pattern %q", i+1, p)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate applies the first matching rule, or the default.
+func (r *Rules) Evaluate(_ context.Context, input Input) error {
+	for i, rule := range r.Rules {
+		if !rule.matches(input) {
+			continue
+		}
+		if rule.Effect == EffectAllow {
+			return nil
+		}
+		if rule.Message != "" {
+			return fmt.Errorf("denied by policy rule %d: %s", i+1, rule.Message)
+		}
+		return fmt.Errorf("denied by policy rule %d", i+1)
+	}
+	if r.Default == EffectDeny {
+		return fmt.Errorf("denied by policy: no rule allows %s", input.Tool)
+	}
+	return nil
+}
+
+// matches reports whether the call matches every condition of the rule.
+func (r Rule) matches(input Input) bool {
+	if len(r.Tools) > 0 && !matchAny(r.Tools, input.Tool) {
+		return false
+	}
+	if r.Access != "" && r.Access != input.Access {
+		return false
+	}
+	if len(r.Namespaces) > 0 && !r.matchesNamespaces(input) {
+		return false
+	}
+	for name, patterns := range r.Arguments {
+		value, ok := input.Arguments[name]
+		if !ok || !matchAny(patterns, fmt.Sprint(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesNamespaces reports whether the namespaces of the call match those of the rule: any of them
+// for a deny rule, so that a cluster-wide call matches too, and all of them for an allow rule.
+func (r Rule) matchesNamespaces(input Input) bool {
+	if r.Effect == EffectDeny {
+		if input.ClusterWide {
+			return true
+		}
+		for _, ns := range input.Namespaces {
+			if matchAny(r.Namespaces, ns) {
+				return true
+			}
+		}
+		return false
+	}
+	if input.ClusterWide || len(input.Namespaces) == 0 {
+		return false
+	}
+	for _, ns := range input.Namespaces {
+		if !matchAny(r.Namespaces, ns) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAny reports whether the value matches one of the glob patterns.
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NewInput describes a tool call to the evaluators.
+func NewInput(req mcp.CallToolRequest) Input {
+	args := req.Params.Arguments
+	if args == nil {
+		args = map[string]any{}
+	}
+	namespace, _ := args["namespace"].(string)
+	return Input{
+		Tool:        req.Params.Name,
+		Access:      Access(req.Params.Name, args),
+		Namespace:   strings.TrimSpace(namespace),
+		Namespaces:  Namespaces(req.Params.Name, args),
+		ClusterWide: ClusterWide(req.Params.Name, args),
+		Arguments:   args,
+	}
+}
+
+// Middleware runs every evaluator before the handler and refuses the call when one denies it.
+func Middleware(evaluators []Evaluator) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if len(evaluators) == 0 {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			input := NewInput(req)
+			for _, e := range evaluators {
+				if err := e.Evaluate(ctx, input); err != nil {
+					return nil, err
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
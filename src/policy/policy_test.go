@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRules = `
+rules:
+  - effect: deny
+    access: write
+    namespaces: ["prod-*"]
+    message: writes in production namespaces are not allowed
+  - effect: allow
+    tools: [debug_pod]
+    arguments:
+      image: ["busybox", "busybox:*"]
+  - effect: deny
+    tools: [debug_pod]
+`
+
+func TestAccess(t *testing.T) {
+	assert.Equal(t, AccessRead, Access("list_resources", nil))
+	assert.Equal(t, AccessWrite, Access("drain_node", map[string]any{"name": "node-1"}))
+	assert.Equal(t, AccessRead, Access("drain_node", map[string]any{"dryRun": true}))
+	assert.Equal(t, AccessWrite, Access("cordon_node", map[string]any{"name": "node-1", "dryRun": true}))
+	assert.Equal(t, AccessWrite, Access("velero_restore", map[string]any{"dryRun": true}))
+	assert.Equal(t, AccessRead, Access("check_service", map[string]any{"name": "api"}))
+	assert.Equal(t, AccessWrite, Access("check_service", map[string]any{"name": "api", "checkDNS": true}))
+	assert.Equal(t, AccessWrite, Access("dns_health", map[string]any{"lookup": "example.com"}))
+	assert.Equal(t, AccessRead, Access("k8s_secret", map[string]any{"action": "get"}))
+	assert.Equal(t, AccessWrite, Access("k8s_secret", map[string]any{"action": "set"}))
+	assert.Equal(t, AccessRead, Access("csr_manage", map[string]any{"action": "list"}))
+	assert.Equal(t, AccessWrite, Access("csr_manage", map[string]any{"action": "approve"}))
+	assert.Equal(t, AccessRead, Access("set_defaults", map[string]any{"namespace": "prod"}))
+	assert.Equal(t, AccessWrite, Access("set_defaults", map[string]any{"context": "prod"}))
+	assert.Equal(t, AccessRead, Access("export_namespace", map[string]any{"namespace": "shop"}))
+	assert.Equal(t, AccessWrite, Access("export_namespace", map[string]any{"namespace": "shop", "path": "/tmp/shop.yaml"}))
+	assert.Equal(t, AccessWrite, Access("unknown_tool", nil))
+
+	assert.True(t, Classified("list_resources"))
+	assert.True(t, Classified("drain_node"))
+	assert.True(t, Classified("k8s_secret"))
+	assert.False(t, Classified("unknown_tool"))
+}
+
+func TestReadOnly(t *testing.T) {
+	assert.True(t, ReadOnly("list_resources", nil))
+	assert.True(t, ReadOnly("k8s_secret", map[string]any{"action": "get"}))
+	assert.False(t, ReadOnly("k8s_secret", map[string]any{"action": "set", "dryRun": true}))
+	assert.False(t, ReadOnly("drain_node", map[string]any{"dryRun": true}))
+	assert.False(t, ReadOnly("dns_health", map[string]any{"lookup": "example.com"}))
+	assert.False(t, ReadOnly("export_namespace", map[string]any{"path": "/tmp/shop.yaml"}))
+	assert.False(t, ReadOnly("unknown_tool", nil))
+}
+
+func TestNamespaces(t *testing.T) {
+	assert.Equal(t, []string{"payments"}, Namespaces("list_resources", map[string]any{"namespace": " payments "}))
+	assert.Empty(t, Namespaces("list_resources", nil))
+	assert.Equal(t, []string{"default", "kube-system"}, Namespaces("seal_secret", nil))
+	assert.Equal(t, []string{"prod", "prod-restore", "velero"}, Namespaces("velero_restore", map[string]any{
+		"includedNamespaces": []any{"prod"},
+		"namespaceMapping":   map[string]any{"prod": "prod-restore"},
+	}))
+	assert.Equal(t, []string{"a", "b"}, Namespaces("netpol_analyze", map[string]any{"sourceNamespace": "a", "destinationNamespace": "b"}))
+
+	assert.True(t, ClusterWide("cluster_health", nil))
+	assert.True(t, ClusterWide("velero_backup_create", nil))
+	assert.True(t, ClusterWide("velero_backup_create", map[string]any{"includedNamespaces": []any{"prod-*"}}))
+	assert.False(t, ClusterWide("velero_backup_create", map[string]any{"includedNamespaces": []any{"prod"}}))
+	assert.False(t, ClusterWide("list_resources", nil))
+	assert.True(t, Delegates("batch_query"))
+}
+
+func TestLoadRules(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(testRules), 0o600))
+	rules, err := LoadRules(file)
+	assert.NoError(t, err)
+	assert.Equal(t, EffectAllow, rules.Default)
+	assert.Len(t, rules.Rules, 3)
+
+	assert.NoError(t, os.WriteFile(file, []byte("rules:\n  - effect: block\n"), 0o600))
+	_, err = LoadRules(file)
+	assert.ErrorContains(t, err, "effect must be")
+
+	assert.NoError(t, os.WriteFile(file, []byte("rules:\n  - effect: deny\n    namespace: [prod]\n"), 0o600))
+	_, err = LoadRules(file)
+	assert.Error(t, err)
+}
+
+func TestRulesEvaluate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(testRules), 0o600))
+	rules, err := LoadRules(file)
+	assert.NoError(t, err)
+	evaluate := func(tool string, args map[string]any) error {
+		var req mcp.CallToolRequest
+		req.Params.Name = tool
+		req.Params.Arguments = args
+		return rules.Evaluate(context.Background(), NewInput(req))
+	}
+
+	assert.ErrorContains(t, evaluate("rollout_restart", map[string]any{"namespace": "prod-eu"}), "writes in production namespaces are not allowed")
+	assert.NoError(t, evaluate("rollout_restart", map[string]any{"namespace": "staging"}))
+	assert.NoError(t, evaluate("list_resources", map[string]any{"namespace": "prod-eu"}))
+	assert.NoError(t, evaluate("debug_pod", map[string]any{"image": "busybox:1.36"}))
+	assert.Error(t, evaluate("debug_pod", map[string]any{"image": "alpine"}))
+	assert.ErrorContains(t, evaluate("velero_restore", map[string]any{"backupName": "nightly", "namespaceMapping": map[string]any{"staging": "prod-eu"}, "includedNamespaces": []any{"staging"}}), "writes in production namespaces")
+	assert.ErrorContains(t, evaluate("velero_restore", map[string]any{"backupName": "nightly"}), "writes in production namespaces")
+	assert.NoError(t, evaluate("velero_restore", map[string]any{"backupName": "nightly", "includedNamespaces": []any{"staging"}}))
+
+	allow := &Rules{Default: EffectDeny, Rules: []Rule{{Effect: EffectAllow, Namespaces: []string{"staging"}}}}
+	input := func(tool string, args map[string]any) Input {
+		var req mcp.CallToolRequest
+		req.Params.Name = tool
+		req.Params.Arguments = args
+		return NewInput(req)
+	}
+	assert.NoError(t, allow.Evaluate(context.Background(), input("netpol_analyze", map[string]any{"sourceNamespace": "staging"})))
+	assert.Error(t, allow.Evaluate(context.Background(), input("netpol_analyze", map[string]any{"sourceNamespace": "staging", "destinationNamespace": "prod-eu"})))
+	assert.Error(t, allow.Evaluate(context.Background(), input("find_pods", map[string]any{"namespace": "staging"})))
+
+	rules.Default = EffectDeny
+	assert.ErrorContains(t, evaluate("top_nodes", nil), "no rule allows top_nodes")
+}
+
+func TestMiddleware(t *testing.T) {
+	called := false
+	handler := Middleware([]Evaluator{&Rules{Default: EffectAllow, Rules: []Rule{{Effect: EffectDeny, Tools: []string{"drain_node"}}}}})(
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			called = true
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+	var req mcp.CallToolRequest
+	req.Params.Name = "drain_node"
+	_, err := handler(context.Background(), req)
+	assert.Error(t, err)
+	assert.False(t, called)
+
+	req.Params.Name = "top_nodes"
+	_, err = handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
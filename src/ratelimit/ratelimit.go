@@ -0,0 +1,245 @@
+// Environment variables used by this package:
+// Optional:
+//   TOOL_LIMITS_FILE               - Path to a YAML or JSON file of global and per-tool concurrency and rate limits
+
+// Package ratelimit caps how many tool calls run at once and how often they may start, so a runaway
+// agent loop cannot flood the API server.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/yaml"
+)
+
+// concurrencyRetryAfter is suggested to callers turned away because too many calls were running,
+// since when one finishes is unknown.
+const concurrencyRetryAfter = time.Second
+
+// Limit caps one tool, or all tools together. Rate is "N/duration", e.g. "1/10s" for one call every
+// ten seconds; Burst defaults to N.
+type Limit struct {
+	Concurrency int    `json:"concurrency,omitempty"`
+	Rate        string `json:"rate,omitempty"`
+	Burst       int    `json:"burst,omitempty"`
+}
+
+// Config is the limits file.
+type Config struct {
+	Global Limit            `json:"global,omitempty"`
+	Tools  map[string]Limit `json:"tools,omitempty"`
+}
+
+// Throttled is the result returned instead of running a call.
+type Throttled struct {
+	Status            string  `json:"status"`
+	Tool              string  `json:"tool"`
+	Scope             string  `json:"scope"`
+	Reason            string  `json:"reason"`
+	RetryAfter        string  `json:"retryAfter"`
+	RetryAfterSeconds float64 `json:"retryAfterSeconds"`
+}
+
+// limiter enforces one Limit.
+type limiter struct {
+	scope string
+	slots chan struct{}
+	rate  *rate.Limiter
+}
+
+// Limiter enforces the global limit and the limits of individual tools.
+type Limiter struct {
+	global *limiter
+	tools  map[string]*limiter
+	now    func() time.Time
+}
+
+// Load reads the limits from TOOL_LIMITS_FILE; without it no limits apply and Load returns nil.
+func Load() (*Limiter, error) {
+	file := os.Getenv("TOOL_LIMITS_FILE")
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read limits file: %w", err)
+	}
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse limits file %s: %w", file, err)
+	}
+	l, err := New(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid limits file %s: %w", file, err)
+	}
+	return l, nil
+}
+
+// New creates a Limiter from the config.
+func New(config Config) (*Limiter, error) {
+	global, err := newLimiter("global", config.Global)
+	if err != nil {
+		return nil, err
+	}
+	l := &Limiter{global: global, tools: map[string]*limiter{}, now: time.Now}
+	for name, limit := range config.Tools {
+		if l.tools[name], err = newLimiter(name, limit); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// newLimiter validates a Limit and creates its semaphore and token bucket.
+func newLimiter(scope string, limit Limit) (*limiter, error) {
+	if limit.Concurrency < 0 || limit.Burst < 0 {
+		return nil, fmt.Errorf("%s: concurrency and burst must not be negative", scope)
+	}
+	l := &limiter{scope: scope}
+	if limit.Concurrency > 0 {
+		l.slots = make(chan struct{}, limit.Concurrency)
+	}
+	if limit.Rate != "" {
+		n, per, err := parseRate(limit.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, err)
+		}
+		burst := limit.Burst
+		if burst == 0 {
+			burst = n
+		}
+		l.rate = rate.NewLimiter(rate.Limit(float64(n)/per.Seconds()), burst)
+	} else if limit.Burst > 0 {
+		return nil, fmt.Errorf("%s: burst requires rate", scope)
+	}
+	return l, nil
+}
+
+// parseRate parses "N/duration" such as "2/1s" or "1/10s"; the duration may be a bare unit, as in "30/m".
+func parseRate(s string) (int, time.Duration, error) {
+	count, window, ok := strings.Cut(strings.TrimSpace(s), "/")
+	n, err := strconv.Atoi(count)
+	if !ok || err != nil || n <= 0 {
+		return 0, 0, fmt.Errorf("invalid rate %q: expected N/duration like 1/10s", s)
+	}
+	if window == "s" || window == "m" || window == "h" {
+		window = "1" + window
+	}
+	per, err := time.ParseDuration(window)
+	if err != nil || per <= 0 {
+		return 0, 0, fmt.Errorf("invalid rate %q: expected N/duration like 1/10s", s)
+	}
+	return n, per, nil
+}
+
+// subCallKey is the context key marking calls made on behalf of another call.
+type subCallKey struct{}
+
+// WithSubCall returns a context for calls made on behalf of a call that already holds concurrency
+// slots, such as the queries of batch_query. Sub-calls skip the concurrency caps, which the calling
+// call would otherwise exhaust itself, but still take rate tokens.
+func WithSubCall(ctx context.Context) context.Context {
+	return context.WithValue(ctx, subCallKey{}, true)
+}
+
+// isSubCall reports whether ctx was marked by WithSubCall.
+func isSubCall(ctx context.Context) bool {
+	sub, _ := ctx.Value(subCallKey{}).(bool)
+	return sub
+}
+
+// acquire takes a concurrency slot, unless the call is a sub-call, and a rate token from each
+// limiter in order, or none of them. On success it returns a function releasing the slots;
+// otherwise the reason the call is throttled.
+func (l *Limiter) acquire(tool string, subCall bool) (func(), *Throttled) {
+	limiters := []*limiter{l.global}
+	if t, ok := l.tools[tool]; ok {
+		limiters = append(limiters, t)
+	}
+
+	var held []chan struct{}
+	release := func() {
+		for _, slots := range held {
+			<-slots
+		}
+	}
+	for _, lim := range limiters {
+		if lim.slots == nil || subCall {
+			continue
+		}
+		select {
+		case lim.slots <- struct{}{}:
+			held = append(held, lim.slots)
+		default:
+			release()
+			return nil, throttled(tool, lim.scope, fmt.Sprintf("at most %d concurrent calls", cap(lim.slots)), concurrencyRetryAfter)
+		}
+	}
+
+	now := l.now()
+	var reserved []*rate.Reservation
+	for _, lim := range limiters {
+		if lim.rate == nil {
+			continue
+		}
+		r := lim.rate.ReserveN(now, 1)
+		delay := r.DelayFrom(now)
+		if !r.OK() || delay > 0 {
+			r.CancelAt(now)
+			for _, prev := range reserved {
+				prev.CancelAt(now)
+			}
+			release()
+			if !r.OK() {
+				delay = concurrencyRetryAfter
+			}
+			return nil, throttled(tool, lim.scope, "rate limit exceeded", delay)
+		}
+		reserved = append(reserved, r)
+	}
+	return release, nil
+}
+
+// throttled describes a refused call.
+func throttled(tool, scope, reason string, retryAfter time.Duration) *Throttled {
+	retryAfter = retryAfter.Round(time.Millisecond)
+	return &Throttled{
+		Status:            "throttled",
+		Tool:              tool,
+		Scope:             scope,
+		Reason:            reason,
+		RetryAfter:        retryAfter.String(),
+		RetryAfterSeconds: retryAfter.Seconds(),
+	}
+}
+
+// Middleware refuses calls over a limit with a throttled error result instead of running them. A nil
+// Limiter applies no limits.
+func Middleware(l *Limiter) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if l == nil {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			release, t := l.acquire(req.Params.Name, isSubCall(ctx))
+			if t != nil {
+				out, err := json.Marshal(t)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal result: %w", err)
+				}
+				return mcp.NewToolResultError(string(out)), nil
+			}
+			defer release()
+			return next(ctx, req)
+		}
+	}
+}
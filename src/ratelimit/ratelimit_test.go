@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	n, per, err := parseRate("1/10s")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 10*time.Second, per)
+
+	n, per, err = parseRate("30/m")
+	assert.NoError(t, err)
+	assert.Equal(t, 30, n)
+	assert.Equal(t, time.Minute, per)
+
+	for _, invalid := range []string{"", "10", "0/1s", "1/0s", "x/1s", "1/soon"} {
+		_, _, err = parseRate(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{Tools: map[string]Limit{"list_resources": {Burst: 2}}})
+	assert.ErrorContains(t, err, "burst requires rate")
+	_, err = New(Config{Global: Limit{Concurrency: -1}})
+	assert.Error(t, err)
+}
+
+func TestAcquireConcurrency(t *testing.T) {
+	l, err := New(Config{Global: Limit{Concurrency: 3}, Tools: map[string]Limit{"list_resources": {Concurrency: 2}}})
+	assert.NoError(t, err)
+
+	release1, throttled := l.acquire("list_resources", false)
+	assert.Nil(t, throttled)
+	release2, throttled := l.acquire("list_resources", false)
+	assert.Nil(t, throttled)
+	_, throttled = l.acquire("list_resources", false)
+	assert.Equal(t, "list_resources", throttled.Scope)
+	assert.Equal(t, "1s", throttled.RetryAfter)
+
+	// The refused call must not hold a global slot.
+	release3, throttled := l.acquire("top_nodes", false)
+	assert.Nil(t, throttled)
+	_, throttled = l.acquire("top_nodes", false)
+	assert.Equal(t, "global", throttled.Scope)
+
+	// Sub-calls run on behalf of a call holding slots and skip the concurrency caps.
+	release4, throttled := l.acquire("list_resources", true)
+	assert.Nil(t, throttled)
+	release4()
+
+	release1()
+	release2()
+	release3()
+	_, throttled = l.acquire("list_resources", false)
+	assert.Nil(t, throttled)
+}
+
+func TestAcquireRate(t *testing.T) {
+	l, err := New(Config{Tools: map[string]Limit{"rollout_restart": {Rate: "1/10s"}}})
+	assert.NoError(t, err)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	release, throttled := l.acquire("rollout_restart", false)
+	assert.Nil(t, throttled)
+	release()
+
+	now = now.Add(4 * time.Second)
+	_, throttled = l.acquire("rollout_restart", false)
+	assert.Equal(t, &Throttled{Status: "throttled", Tool: "rollout_restart", Scope: "rollout_restart", Reason: "rate limit exceeded", RetryAfter: "6s", RetryAfterSeconds: 6}, throttled)
+
+	// Throttled calls do not push the next allowed call further out.
+	now = now.Add(6 * time.Second)
+	_, throttled = l.acquire("rollout_restart", false)
+	assert.Nil(t, throttled)
+}
+
+func TestMiddleware(t *testing.T) {
+	l, err := New(Config{Global: Limit{Rate: "1/1h"}})
+	assert.NoError(t, err)
+	handler := Middleware(l)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	var req mcp.CallToolRequest
+	req.Params.Name = "list_resources"
+
+	result, err := handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	result, err = handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	var out Throttled
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	assert.Equal(t, "throttled", out.Status)
+	assert.Equal(t, "global", out.Scope)
+}
+
+func TestMiddlewareSubCall(t *testing.T) {
+	l, err := New(Config{Global: Limit{Concurrency: 1}})
+	assert.NoError(t, err)
+	var req mcp.CallToolRequest
+	req.Params.Name = "batch_query"
+	var inner *mcp.CallToolResult
+	var handler server.ToolHandlerFunc
+	handler = Middleware(l)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if req.Params.Name == "batch_query" {
+			sub := req
+			sub.Params.Name = "list_resources"
+			var err error
+			inner, err = handler(WithSubCall(ctx), sub)
+			assert.NoError(t, err)
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	result, err := handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.False(t, inner.IsError, "the sub-call must not wait for the slot its caller holds")
+}
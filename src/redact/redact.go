@@ -0,0 +1,205 @@
+// Environment variables used by this package:
+// Optional:
+//   REDACT_POLICY                  - "always", "on-request" (default) or "never"
+
+// Package redact scrubs secret material from tool results before it reaches the model.
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Policy decides when tool results are scrubbed.
+type Policy string
+
+const (
+	// Always scrubs every result, including values a tool was asked to reveal.
+	Always Policy = "always"
+	// OnRequest scrubs every result except those of calls that explicitly asked to reveal values.
+	OnRequest Policy = "on-request"
+	// Never returns results unchanged.
+	Never Policy = "never"
+)
+
+// Marker replaces redacted values.
+const Marker = "(redacted)"
+
+// revealArguments name, for each tool that declares one and refuses it unless the server allows
+// revealing, the argument that asks for plaintext secret values. Other tools are always scrubbed,
+// whatever arguments they are sent.
+var revealArguments = map[string]string{
+	"k8s_secret":      "reveal",
+	"secret_list":     "includeValues",
+	"secret_versions": "reveal",
+}
+
+// valuePatterns match token and key formats wherever they appear in a string.
+var valuePatterns = []*regexp.Regexp{
+	// PEM private keys, also when their newlines are JSON-escaped.
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	// AWS access key IDs.
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	// Google API keys.
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`),
+	// GitHub and Slack tokens.
+	regexp.MustCompile(`\b(?:gh[pousr]|github_pat)_[A-Za-z0-9_]{30,}\b`),
+	regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+	// JWTs, including service account tokens.
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`),
+}
+
+// bearerPattern matches bearer tokens in Authorization headers; the scheme is kept.
+var bearerPattern = regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9._~+/-]{16,}=*`)
+
+// assignmentPattern matches KEY=value and key: value pairs whose key names a secret, as found in
+// dotenv payloads, command lines and logs; the key is kept.
+var assignmentPattern = regexp.MustCompile(`(?i)\b([A-Z0-9_.-]*(?:password|passwd|secret|token|api[_-]?key|private[_-]?key|access[_-]?key|credentials?)\s*[=:]\s*)([^\s"'\\,;&]+)`)
+
+// sensitiveKeyPattern matches JSON field and env var names whose values are secrets. The name must
+// end in the secret word, so fields like secretName or tokenExpiry are kept.
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)(?:password|passwd|secret|token|apikey|api_key|api-key|privatekey|private_key|accesskey|access_key|credentials?)$`)
+
+// nonSensitiveKeys end in a secret word but hold no secret material: pagination cursors, resource
+// names, sealed (encrypted) manifests and the single-use confirmation token the client must send back.
+var nonSensitiveKeys = map[string]bool{
+	"pageToken":         true,
+	"nextPageToken":     true,
+	"progressToken":     true,
+	"confirmationToken": true,
+	"externalSecret":    true,
+	"sealedSecret":      true,
+}
+
+// ParsePolicy parses a policy name; an empty name selects OnRequest.
+func ParsePolicy(name string) (Policy, error) {
+	switch p := Policy(strings.ToLower(strings.TrimSpace(name))); p {
+	case "":
+		return OnRequest, nil
+	case Always, OnRequest, Never:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid redaction policy %q: must be always, on-request or never", name)
+	}
+}
+
+// PolicyFromEnv reads the policy from REDACT_POLICY.
+func PolicyFromEnv() (Policy, error) {
+	return ParsePolicy(os.Getenv("REDACT_POLICY"))
+}
+
+// Middleware scrubs the text content of every tool result according to the policy. Under OnRequest
+// a call to one of the revealArguments tools that sets its reveal argument is passed through, since
+// the tool has already checked that revealing is allowed.
+func Middleware(policy Policy) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if policy == Never {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if result == nil || (policy == OnRequest && revealRequested(req.Params.Name, req.Params.Arguments)) {
+				return result, err
+			}
+			for i, c := range result.Content {
+				if text, ok := c.(mcp.TextContent); ok {
+					text.Text = Text(text.Text)
+					result.Content[i] = text
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// revealRequested reports whether the call asked a tool that gates revealing for plaintext secret values.
+func revealRequested(tool string, args map[string]any) bool {
+	name, ok := revealArguments[tool]
+	if !ok {
+		return false
+	}
+	v, _ := args[name].(bool)
+	return v
+}
+
+// Text scrubs a tool result. JSON results are walked so Secret data, env var values and fields
+// named like secrets are redacted as a whole; any other text only has its token-like values replaced.
+func Text(text string) string {
+	// Numbers are kept as written so large integers survive the round trip.
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil || decoder.More() {
+		return String(text)
+	}
+	out, err := json.Marshal(value(doc))
+	if err != nil {
+		return String(text)
+	}
+	return string(out)
+}
+
+// String replaces token-like values and secret assignments in free text.
+func String(s string) string {
+	for _, p := range valuePatterns {
+		s = p.ReplaceAllString(s, Marker)
+	}
+	s = bearerPattern.ReplaceAllString(s, "${1}"+Marker)
+	return assignmentPattern.ReplaceAllString(s, "${1}"+Marker)
+}
+
+// value scrubs a decoded JSON value.
+func value(v any) any {
+	switch v := v.(type) {
+	case string:
+		return String(v)
+	case []any:
+		for i := range v {
+			v[i] = value(v[i])
+		}
+		return v
+	case map[string]any:
+		return object(v)
+	default:
+		return v
+	}
+}
+
+// object scrubs a decoded JSON object: the data of Secret manifests, the value of env vars with a
+// secret name and string fields with a secret name are replaced outright.
+func object(obj map[string]any) map[string]any {
+	if kind, _ := obj["kind"].(string); kind == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			if data, ok := obj[field].(map[string]any); ok {
+				for k := range data {
+					data[k] = Marker
+				}
+			}
+		}
+	}
+	if name, ok := obj["name"].(string); ok && sensitiveKey(name) {
+		if _, ok := obj["value"].(string); ok {
+			obj["value"] = Marker
+		}
+	}
+	for k, v := range obj {
+		if s, ok := v.(string); ok && s != "" && sensitiveKey(k) {
+			obj[k] = Marker
+			continue
+		}
+		obj[k] = value(v)
+	}
+	return obj
+}
+
+// sensitiveKey reports whether a field or variable name holds a secret.
+func sensitiveKey(name string) bool {
+	return !nonSensitiveKeys[name] && sensitiveKeyPattern.MatchString(name)
+}
@@ -0,0 +1,106 @@
+package telemetry
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attributes recorded for Kubernetes API requests.
+const (
+	attrGroup       = attribute.Key("k8s.api.group")
+	attrVersion     = attribute.Key("k8s.api.version")
+	attrResource    = attribute.Key("k8s.api.resource")
+	attrName        = attribute.Key("k8s.api.name")
+	attrSubresource = attribute.Key("k8s.api.subresource")
+)
+
+// apiRequest is the target of a Kubernetes API request decoded from its URL path.
+type apiRequest struct {
+	Group       string
+	Version     string
+	Namespace   string
+	Resource    string
+	Name        string
+	Subresource string
+}
+
+// parseAPIPath decodes /api/v1/... and /apis/<group>/<version>/... request paths. Discovery and
+// other non-resource paths return false.
+func parseAPIPath(path string) (apiRequest, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var req apiRequest
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		req.Version, parts = parts[1], parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		req.Group, req.Version, parts = parts[1], parts[2], parts[3:]
+	default:
+		return req, false
+	}
+	// A namespace is addressed as a resource itself unless more path follows its name.
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		req.Namespace, parts = parts[1], parts[2:]
+	}
+	req.Resource = parts[0]
+	if len(parts) > 1 {
+		req.Name = parts[1]
+	}
+	if len(parts) > 2 {
+		req.Subresource = strings.Join(parts[2:], "/")
+	}
+	return req, true
+}
+
+// spanName names an API request span after the verb and resource, e.g. "GET apps/v1 deployments".
+func spanName(r *http.Request) string {
+	req, ok := parseAPIPath(r.URL.Path)
+	if !ok {
+		return r.Method + " " + r.URL.Path
+	}
+	gv := req.Version
+	if req.Group != "" {
+		gv = req.Group + "/" + req.Version
+	}
+	name := r.Method + " " + gv + " " + req.Resource
+	if req.Subresource != "" {
+		name += "/" + req.Subresource
+	}
+	return name
+}
+
+// apiAttributesTransport adds the decoded group, version, resource and namespace to the span that
+// otelhttp started for the request.
+type apiAttributesTransport struct {
+	next http.RoundTripper
+}
+
+func (t apiAttributesTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if req, ok := parseAPIPath(r.URL.Path); ok {
+		attrs := []attribute.KeyValue{attrVersion.String(req.Version), attrResource.String(req.Resource)}
+		if req.Group != "" {
+			attrs = append(attrs, attrGroup.String(req.Group))
+		}
+		if req.Namespace != "" {
+			attrs = append(attrs, attrNamespace.String(req.Namespace))
+		}
+		if req.Name != "" {
+			attrs = append(attrs, attrName.String(req.Name))
+		}
+		if req.Subresource != "" {
+			attrs = append(attrs, attrSubresource.String(req.Subresource))
+		}
+		trace.SpanFromContext(r.Context()).SetAttributes(attrs...)
+	}
+	return t.next.RoundTrip(r)
+}
+
+// WrapTransport instruments Kubernetes API requests with client spans; it is meant for rest.Config.Wrap.
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(apiAttributesTransport{next: rt}, otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return spanName(r)
+	}))
+}
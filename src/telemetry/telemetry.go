@@ -0,0 +1,128 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans created by this server.
+const instrumentationName = "github.com/k4mrul/kubernetes-mcp"
+
+// Span attributes recorded for tool calls.
+const (
+	attrToolName   = attribute.Key("mcp.tool.name")
+	attrResultSize = attribute.Key("mcp.tool.result_size")
+	attrNamespace  = attribute.Key("k8s.namespace.name")
+	attrKind       = attribute.Key("k8s.resource.kind")
+)
+
+// tracer returns the tracer of the global provider, so spans follow whatever Setup installed.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs a global tracer provider that exports spans over OTLP when an endpoint is configured
+// through the standard OTEL_EXPORTER_OTLP_* environment variables. OTEL_EXPORTER_OTLP_PROTOCOL selects
+// "grpc" or "http/protobuf" (the default); sampling and resource attributes follow the usual OTEL_*
+// variables too. Without an endpoint tracing stays a no-op. The returned function flushes pending spans.
+func Setup(ctx context.Context, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") ||
+		(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "") {
+		return noop, nil
+	}
+
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch protocol {
+	case "grpc":
+		exporter, err = otlptracegrpc.New(ctx)
+	case "", "http/protobuf":
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return noop, fmt.Errorf("unsupported OTLP protocol %q: use grpc or http/protobuf", protocol)
+	}
+	if err != nil {
+		return noop, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	// Attributes from OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", "kubernetes-mcp"),
+			attribute.String("service.version", version),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("failed to create telemetry resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// ToolMiddleware records a span for every tool call with the tool name, the namespace and kind
+// arguments, the size of the result and whether the call failed.
+func ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, span := tracer().Start(ctx, "tool "+req.Params.Name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrToolName.String(req.Params.Name)),
+		)
+		defer span.End()
+		if ns, ok := req.Params.Arguments["namespace"].(string); ok && ns != "" {
+			span.SetAttributes(attrNamespace.String(ns))
+		}
+		if kind, ok := req.Params.Arguments["kind"].(string); ok && kind != "" {
+			span.SetAttributes(attrKind.String(kind))
+		}
+
+		result, err := next(ctx, req)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return result, err
+		}
+		span.SetAttributes(attrResultSize.Int(resultSize(result)))
+		if result != nil && result.IsError {
+			span.SetStatus(codes.Error, "tool returned an error result")
+		}
+		return result, nil
+	}
+}
+
+// resultSize returns the number of bytes of text in a tool result.
+func resultSize(result *mcp.CallToolResult) int {
+	if result == nil {
+		return 0
+	}
+	size := 0
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			size += len(text.Text)
+		}
+	}
+	return size
+}
@@ -0,0 +1,74 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestParseAPIPath(t *testing.T) {
+	req, ok := parseAPIPath("/apis/apps/v1/namespaces/prod/deployments/web/scale")
+	assert.True(t, ok)
+	assert.Equal(t, apiRequest{Group: "apps", Version: "v1", Namespace: "prod", Resource: "deployments", Name: "web", Subresource: "scale"}, req)
+
+	req, ok = parseAPIPath("/api/v1/namespaces/prod")
+	assert.True(t, ok)
+	assert.Equal(t, apiRequest{Version: "v1", Resource: "namespaces", Name: "prod"}, req)
+
+	req, ok = parseAPIPath("/api/v1/nodes")
+	assert.True(t, ok)
+	assert.Equal(t, apiRequest{Version: "v1", Resource: "nodes"}, req)
+
+	_, ok = parseAPIPath("/apis/apps/v1")
+	assert.False(t, ok)
+	_, ok = parseAPIPath("/version")
+	assert.False(t, ok)
+}
+
+func TestToolMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	handler := ToolMiddleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if req.Params.Name == "broken" {
+			return nil, errors.New("boom")
+		}
+		return mcp.NewToolResultText("hello"), nil
+	})
+
+	var req mcp.CallToolRequest
+	req.Params.Name = "list_resources"
+	req.Params.Arguments = map[string]any{"namespace": "prod", "kind": "Pod"}
+	_, err := handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	req.Params.Name = "broken"
+	_, err = handler(context.Background(), req)
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "tool list_resources", spans[0].Name())
+	attrs := map[string]any{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	assert.Equal(t, map[string]any{
+		"mcp.tool.name":        "list_resources",
+		"k8s.namespace.name":   "prod",
+		"k8s.resource.kind":    "Pod",
+		"mcp.tool.result_size": int64(5),
+	}, attrs)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,205 @@
+// Package toolerror turns errors returned by tool handlers into structured error results, so the
+// model can read the Kubernetes reason, status code, resource and a remediation hint instead of
+// parsing prose.
+package toolerror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons for errors that do not come from the Kubernetes API.
+const (
+	ReasonInvalidInput = "InvalidInput"
+	ReasonTimeout      = "Timeout"
+	ReasonCanceled     = "Canceled"
+	ReasonUnknown      = "Unknown"
+)
+
+// forbiddenPattern extracts the subject, verb, resource, group and namespace from the message of a
+// Forbidden status, e.g. `pods is forbidden: User "bob" cannot list resource "pods" in API group ""
+// in the namespace "prod"`.
+var forbiddenPattern = regexp.MustCompile(`(User|ServiceAccount|Group) "([^"]*)" cannot (\w+) resource "([^"]*)" in API group "([^"]*)"(?: in the namespace "([^"]*)")?`)
+
+// Error is the structured form of a failed tool call.
+type Error struct {
+	Message           string   `json:"message"`
+	Reason            string   `json:"reason"`
+	Code              int32    `json:"code,omitempty"`
+	Group             string   `json:"group,omitempty"`
+	Resource          string   `json:"resource,omitempty"`
+	Name              string   `json:"name,omitempty"`
+	Namespace         string   `json:"namespace,omitempty"`
+	Verb              string   `json:"verb,omitempty"`
+	User              string   `json:"user,omitempty"`
+	Causes            []string `json:"causes,omitempty"`
+	Retryable         bool     `json:"retryable"`
+	RetryAfterSeconds int32    `json:"retryAfterSeconds,omitempty"`
+	Hint              string   `json:"hint,omitempty"`
+}
+
+// Describe classifies an error. The namespace argument of the call is used when the error itself
+// does not name one.
+func Describe(err error, namespace string) *Error {
+	e := &Error{Message: err.Error(), Reason: ReasonUnknown, Namespace: namespace}
+
+	var apiStatus apierrors.APIStatus
+	var grpcStatus interface{ GRPCStatus() *status.Status }
+	switch {
+	case errors.As(err, &apiStatus):
+		describeStatus(e, apiStatus.Status())
+	case errors.As(err, &grpcStatus):
+		e.Reason = grpcStatus.GRPCStatus().Code().String()
+		e.Retryable = e.Reason == "Unavailable" || e.Reason == "DeadlineExceeded" || e.Reason == "ResourceExhausted"
+	case errors.Is(err, context.DeadlineExceeded):
+		e.Reason = ReasonTimeout
+		e.Retryable = true
+		e.Hint = "the call timed out: retry, narrow the query or raise its timeout"
+	case errors.Is(err, context.Canceled):
+		e.Reason = ReasonCanceled
+	case strings.HasPrefix(e.Message, "failed to parse"), strings.HasPrefix(e.Message, "invalid "):
+		e.Reason = ReasonInvalidInput
+		e.Hint = "fix the arguments as described in the message and call the tool again"
+	}
+	return e
+}
+
+// describeStatus fills in the reason, code, resource and hint of a Kubernetes API status.
+func describeStatus(e *Error, s metav1.Status) {
+	e.Reason = string(s.Reason)
+	e.Code = s.Code
+	if e.Reason == "" {
+		e.Reason = reasonForCode(s.Code)
+	}
+	if d := s.Details; d != nil {
+		e.Group, e.Resource, e.Name = d.Group, d.Kind, d.Name
+		e.RetryAfterSeconds = d.RetryAfterSeconds
+		for _, c := range d.Causes {
+			if c.Field != "" {
+				e.Causes = append(e.Causes, c.Field+": "+c.Message)
+			} else {
+				e.Causes = append(e.Causes, c.Message)
+			}
+		}
+	}
+	if m := forbiddenPattern.FindStringSubmatch(s.Message); m != nil {
+		e.User = strings.ToLower(m[1]) + " " + m[2]
+		e.Verb, e.Resource, e.Group = m[3], m[4], m[5]
+		if m[6] != "" {
+			e.Namespace = m[6]
+		}
+	}
+
+	switch metav1.StatusReason(e.Reason) {
+	case metav1.StatusReasonNotFound:
+		e.Hint = fmt.Sprintf("%s not found%s: check the name and namespace, e.g. with list_resources", e.object(), e.inNamespace())
+	case metav1.StatusReasonForbidden:
+		e.Hint = fmt.Sprintf("missing RBAC: %s%s; grant it with a Role and RoleBinding (ClusterRole for cluster-scoped resources) or check with can_i", e.permission(), e.inNamespace())
+	case metav1.StatusReasonUnauthorized:
+		e.Hint = "the cluster rejected the credentials: refresh the kubeconfig token or client certificate"
+	case metav1.StatusReasonAlreadyExists:
+		e.Hint = fmt.Sprintf("%s already exists%s: pick another name or update the existing object", e.object(), e.inNamespace())
+	case metav1.StatusReasonConflict:
+		e.Retryable = true
+		e.Hint = "the object changed since it was read: retry the call"
+	case metav1.StatusReasonInvalid:
+		e.Hint = "the API server rejected the object: fix the fields listed in causes"
+	case metav1.StatusReasonTimeout, metav1.StatusReasonServerTimeout, metav1.StatusReasonTooManyRequests,
+		metav1.StatusReasonServiceUnavailable, metav1.StatusReasonInternalError:
+		e.Retryable = true
+		e.Hint = "the API server is busy or unavailable: retry later"
+		if e.RetryAfterSeconds > 0 {
+			e.Hint = fmt.Sprintf("the API server is busy or unavailable: retry after %d seconds", e.RetryAfterSeconds)
+		}
+	}
+}
+
+// reasonForCode infers the reason of a status that carries only an HTTP code, as returned by
+// aggregated APIs and proxies.
+func reasonForCode(code int32) string {
+	switch {
+	case code == 400:
+		return string(metav1.StatusReasonBadRequest)
+	case code == 401:
+		return string(metav1.StatusReasonUnauthorized)
+	case code == 403:
+		return string(metav1.StatusReasonForbidden)
+	case code == 404:
+		return string(metav1.StatusReasonNotFound)
+	case code == 409:
+		return string(metav1.StatusReasonConflict)
+	case code == 429:
+		return string(metav1.StatusReasonTooManyRequests)
+	case code == 503:
+		return string(metav1.StatusReasonServiceUnavailable)
+	case code >= 500:
+		return string(metav1.StatusReasonInternalError)
+	}
+	return ReasonUnknown
+}
+
+// object names the resource involved, e.g. "pods web-1".
+func (e *Error) object() string {
+	switch {
+	case e.Resource != "" && e.Name != "":
+		return e.Resource + " " + e.Name
+	case e.Resource != "":
+		return e.Resource
+	case e.Name != "":
+		return e.Name
+	}
+	return "the object"
+}
+
+// permission describes the access that was denied, e.g. `user bob cannot list pods`.
+func (e *Error) permission() string {
+	subject := "the server's identity"
+	if e.User != "" {
+		subject = e.User
+	}
+	resource := e.Resource
+	if e.Group != "" {
+		resource += "." + e.Group
+	}
+	if e.Verb == "" {
+		return subject + " cannot access " + e.object()
+	}
+	return subject + " cannot " + e.Verb + " " + resource
+}
+
+// inNamespace is " in namespace X" when a namespace is known.
+func (e *Error) inNamespace() string {
+	if e.Namespace == "" {
+		return ""
+	}
+	return " in namespace " + e.Namespace
+}
+
+// Middleware returns errors from the handler as error results carrying the structured Error.
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, req)
+		if err == nil {
+			return result, nil
+		}
+		namespace, _ := req.Params.Arguments["namespace"].(string)
+		out, marshalErr := json.Marshal(map[string]any{
+			"tool":  req.Params.Name,
+			"error": Describe(err, namespace),
+		})
+		if marshalErr != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultError(string(out)), nil
+	}
+}
@@ -0,0 +1,102 @@
+package toolerror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestDescribeNotFound(t *testing.T) {
+	err := fmt.Errorf("failed to get deployment: %w", apierrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web"))
+	e := Describe(err, "prod")
+	assert.Equal(t, "NotFound", e.Reason)
+	assert.Equal(t, int32(404), e.Code)
+	assert.Equal(t, "apps", e.Group)
+	assert.Equal(t, "deployments", e.Resource)
+	assert.Equal(t, "web", e.Name)
+	assert.Equal(t, "deployments web not found in namespace prod: check the name and namespace, e.g. with list_resources", e.Hint)
+	assert.False(t, e.Retryable)
+}
+
+func TestDescribeForbidden(t *testing.T) {
+	err := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "",
+		errors.New(`User "system:serviceaccount:mcp:agent" cannot list resource "pods" in API group "" in the namespace "payments"`))
+	e := Describe(fmt.Errorf("failed to list pods: %w", err), "")
+	assert.Equal(t, "Forbidden", e.Reason)
+	assert.Equal(t, "list", e.Verb)
+	assert.Equal(t, "pods", e.Resource)
+	assert.Equal(t, "payments", e.Namespace)
+	assert.Equal(t, "user system:serviceaccount:mcp:agent", e.User)
+	assert.Contains(t, e.Hint, "missing RBAC: user system:serviceaccount:mcp:agent cannot list pods in namespace payments")
+}
+
+func TestDescribeInvalidAndRetryable(t *testing.T) {
+	err := apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "web",
+		field.ErrorList{field.Invalid(field.NewPath("spec", "replicas"), -1, "must be greater than or equal to 0")})
+	e := Describe(err, "")
+	assert.Equal(t, "Invalid", e.Reason)
+	assert.Equal(t, []string{"spec.replicas: Invalid value: -1: must be greater than or equal to 0"}, e.Causes)
+
+	e = Describe(apierrors.NewTooManyRequests("slow down", 7), "")
+	assert.True(t, e.Retryable)
+	assert.Equal(t, int32(7), e.RetryAfterSeconds)
+	assert.Contains(t, e.Hint, "retry after 7 seconds")
+
+	e = Describe(apierrors.NewGenericServerResponse(404, "get", schema.GroupResource{}, "", "", 0, false), "")
+	assert.Equal(t, "NotFound", e.Reason)
+}
+
+func TestDescribeOther(t *testing.T) {
+	e := Describe(fmt.Errorf("failed to list secrets: %w", status.Error(codes.PermissionDenied, "denied")), "")
+	assert.Equal(t, "PermissionDenied", e.Reason)
+
+	e = Describe(fmt.Errorf("failed to wait: %w", context.DeadlineExceeded), "")
+	assert.Equal(t, ReasonTimeout, e.Reason)
+	assert.True(t, e.Retryable)
+
+	e = Describe(errors.New("failed to parse and validate drain params: node must be provided"), "")
+	assert.Equal(t, ReasonInvalidInput, e.Reason)
+
+	e = Describe(errors.New("boom"), "")
+	assert.Equal(t, ReasonUnknown, e.Reason)
+	assert.Empty(t, e.Hint)
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := Middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if req.Params.Name == "ok" {
+			return mcp.NewToolResultText("fine"), nil
+		}
+		return nil, fmt.Errorf("failed to get pod: %w", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web-1"))
+	})
+
+	var req mcp.CallToolRequest
+	req.Params.Name = "ok"
+	result, err := handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	req.Params.Name = "diagnose_pod"
+	req.Params.Arguments = map[string]any{"namespace": "prod"}
+	result, err = handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	var out struct {
+		Tool  string `json:"tool"`
+		Error Error  `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	assert.Equal(t, "diagnose_pod", out.Tool)
+	assert.Equal(t, "NotFound", out.Error.Reason)
+	assert.Equal(t, "prod", out.Error.Namespace)
+}
@@ -0,0 +1,45 @@
+package tools
+
+import "fmt"
+
+// stringMapArg reads an object argument whose values must all be strings.
+func stringMapArg(args map[string]any, key string) (map[string]string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object of string values", key)
+	}
+	result := make(map[string]string, len(obj))
+	for k, v := range obj {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s must be a string", key, k)
+		}
+		result[k] = s
+	}
+	return result, nil
+}
+
+// stringSliceArg reads an array argument whose items must all be strings.
+func stringSliceArg(args map[string]any, key string) ([]string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	result := make([]string, 0, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be a string", key, i)
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/k4mrul/kubernetes-mcp/src/policy"
+	"github.com/k4mrul/kubernetes-mcp/src/ratelimit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	batchQueryToolName = "batch_query"
+	maxBatchQueries    = 20
+	// batchConcurrency is the number of sub-requests of a batch that run at the same time.
+	batchConcurrency = 8
+)
+
+// BatchQuery is one sub-request of batch_query: a list_resources query or a call to another
+// read-only tool.
+type BatchQuery struct {
+	ID   string         `json:"id"`
+	Tool string         `json:"tool"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// BatchQueryResult is the outcome of one sub-request.
+type BatchQueryResult struct {
+	ID      string `json:"id"`
+	Tool    string `json:"tool"`
+	Result  any    `json:"result,omitempty"`
+	IsError bool   `json:"isError,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BatchQueryTool runs several read-only queries concurrently and combines their results.
+// Sub-requests are dispatched through the server, so each passes the same authorization, policy,
+// rate limit and redaction as a direct call; they are marked as sub-calls, so the concurrency caps
+// the batch itself is counted against do not turn them away.
+type BatchQueryTool struct {
+	server *server.MCPServer
+}
+
+// NewBatchQueryTool creates a new batch_query tool that dispatches to the tools of s.
+func NewBatchQueryTool(s *server.MCPServer) *BatchQueryTool {
+	return &BatchQueryTool{server: s}
+}
+
+// Tool returns the MCP tool definition for batch_query.
+func (b *BatchQueryTool) Tool() mcp.Tool {
+	return mcp.NewTool(batchQueryToolName,
+		mcp.WithDescription(fmt.Sprintf("Run up to %d read-only queries in one call, concurrently, and return their results together, e.g. the pods, events and deployment status of an app. Each query is either a list (kind with optional namespace, labelSelector, fieldSelector, limit and showDetails, as in list_resources) or a tool call (tool and args) of any tool that does not change the cluster", maxBatchQueries)),
+		mcp.WithArray("queries",
+			mcp.Required(),
+			mcp.Description("Queries to run. Results are returned in the same order, labeled with id (default: the position)"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id":            map[string]any{"type": "string", "description": "Label for the result"},
+					"kind":          map[string]any{"type": "string", "description": "Kind to list, as in list_resources"},
+					"namespace":     map[string]any{"type": "string"},
+					"labelSelector": map[string]any{"type": "string"},
+					"fieldSelector": map[string]any{"type": "string"},
+					"limit":         map[string]any{"type": "number"},
+					"showDetails":   map[string]any{"type": "boolean"},
+					"tool":          map[string]any{"type": "string", "description": "Read-only tool to call instead of listing"},
+					"args":          map[string]any{"type": "object", "description": "Arguments of the tool"},
+				},
+			}),
+		),
+	)
+}
+
+// Handler runs the queries and returns their results in order.
+func (b *BatchQueryTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	queries, err := parseAndValidateBatchQueryParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate batch_query params: %w", err)
+	}
+
+	results := make([]BatchQueryResult, len(queries))
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = b.run(ctx, i, q)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.IsError {
+			failed++
+		}
+	}
+	out, err := json.Marshal(map[string]any{"results": results, "failed": failed})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// run calls one tool through the server's message handler, which applies the tool middlewares.
+func (b *BatchQueryTool) run(ctx context.Context, i int, q BatchQuery) BatchQueryResult {
+	ctx = ratelimit.WithSubCall(ctx)
+	result := BatchQueryResult{ID: q.ID, Tool: q.Tool}
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      i + 1,
+		"method":  string(mcp.MethodToolsCall),
+		"params":  map[string]any{"name": q.Tool, "arguments": q.Args},
+	})
+	if err != nil {
+		result.IsError, result.Error = true, err.Error()
+		return result
+	}
+
+	switch response := b.server.HandleMessage(ctx, message).(type) {
+	case mcp.JSONRPCResponse:
+		callResult, ok := response.Result.(mcp.CallToolResult)
+		if !ok {
+			result.IsError, result.Error = true, fmt.Sprintf("unexpected result type %T", response.Result)
+			return result
+		}
+		result.IsError = callResult.IsError
+		result.Result = batchResultContent(callResult.Content)
+	case mcp.JSONRPCError:
+		result.IsError, result.Error = true, response.Error.Message
+	default:
+		result.IsError, result.Error = true, fmt.Sprintf("unexpected response type %T", response)
+	}
+	return result
+}
+
+// batchResultContent returns the text of a tool result, decoded when it is JSON so the combined
+// response does not nest escaped documents.
+func batchResultContent(content []mcp.Content) any {
+	var text strings.Builder
+	for _, c := range content {
+		if t, ok := c.(mcp.TextContent); ok {
+			text.WriteString(t.Text)
+		}
+	}
+	var doc any
+	if err := json.Unmarshal([]byte(text.String()), &doc); err == nil {
+		return doc
+	}
+	return text.String()
+}
+
+func parseAndValidateBatchQueryParams(args map[string]any) ([]BatchQuery, error) {
+	raw, ok := args["queries"].([]any)
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("queries must be a non-empty list")
+	}
+	if len(raw) > maxBatchQueries {
+		return nil, fmt.Errorf("at most %d queries can be batched, got %d", maxBatchQueries, len(raw))
+	}
+
+	queries := make([]BatchQuery, 0, len(raw))
+	for i, item := range raw {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("query %d must be an object", i)
+		}
+		q := BatchQuery{Args: map[string]any{}}
+		q.ID, _ = fields["id"].(string)
+		if q.ID == "" {
+			q.ID = fmt.Sprint(i)
+		}
+		q.Tool, _ = fields["tool"].(string)
+		if q.Tool != "" {
+			if toolArgs, ok := fields["args"].(map[string]any); ok {
+				q.Args = toolArgs
+			}
+		} else {
+			kind, _ := fields["kind"].(string)
+			if kind == "" {
+				return nil, fmt.Errorf("query %s needs a kind or a tool", q.ID)
+			}
+			q.Tool = "list_resources"
+			for _, key := range []string{"kind", "namespace", "labelSelector", "fieldSelector", "limit", "showDetails"} {
+				if v, ok := fields[key]; ok {
+					q.Args[key] = v
+				}
+			}
+		}
+		if q.Tool == batchQueryToolName || q.Tool == setDefaultsToolName {
+			return nil, fmt.Errorf("query %s: %s cannot be batched", q.ID, q.Tool)
+		}
+		if !policy.ReadOnly(q.Tool, q.Args) {
+			return nil, fmt.Errorf("query %s: %s is not a read-only tool and cannot be batched", q.ID, q.Tool)
+		}
+		queries = append(queries, q)
+	}
+	return queries, nil
+}
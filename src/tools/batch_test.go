@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/k4mrul/kubernetes-mcp/src/ratelimit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchQueryHandler(t *testing.T) {
+	s := server.NewMCPServer("test", "0", server.WithToolCapabilities(false))
+	s.AddTool(NewListTool(FakeKubernetesClient{}).Tool(), NewListTool(FakeKubernetesClient{}).Handler)
+	s.AddTool(mcp.NewTool("describe_resource"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		out, _ := json.Marshal(req.Params.Arguments)
+		return mcp.NewToolResultText(string(out)), nil
+	})
+	batch := NewBatchQueryTool(s)
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"queries": []any{
+		map[string]any{"id": "deployments", "kind": "Deployment", "namespace": "default"},
+		map[string]any{"tool": "describe_resource", "args": map[string]any{"value": "x"}},
+		map[string]any{"tool": "top_pods"},
+	}}
+	result, err := batch.Handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	var response struct {
+		Results []BatchQueryResult `json:"results"`
+		Failed  int                `json:"failed"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response))
+	assert.Equal(t, 1, response.Failed)
+	assert.Len(t, response.Results, 3)
+
+	assert.Equal(t, "deployments", response.Results[0].ID)
+	assert.Equal(t, "list_resources", response.Results[0].Tool)
+	deployments := response.Results[0].Result.([]any)
+	assert.Equal(t, "foo-deployment", deployments[0].(map[string]any)["name"])
+
+	assert.Equal(t, "1", response.Results[1].ID)
+	assert.Equal(t, map[string]any{"value": "x"}, response.Results[1].Result)
+
+	assert.True(t, response.Results[2].IsError)
+	assert.Contains(t, response.Results[2].Error, "not found")
+}
+
+func TestBatchQueryConcurrencyLimit(t *testing.T) {
+	limiter, err := ratelimit.New(ratelimit.Config{Global: ratelimit.Limit{Concurrency: 1}})
+	assert.NoError(t, err)
+	s := server.NewMCPServer("test", "0", server.WithToolCapabilities(false), server.WithToolHandlerMiddleware(ratelimit.Middleware(limiter)))
+	s.AddTool(NewListTool(FakeKubernetesClient{}).Tool(), NewListTool(FakeKubernetesClient{}).Handler)
+	batch := NewBatchQueryTool(s)
+	s.AddTool(batch.Tool(), batch.Handler)
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      1,
+		"method":  string(mcp.MethodToolsCall),
+		"params": map[string]any{"name": batchQueryToolName, "arguments": map[string]any{"queries": []any{
+			map[string]any{"kind": "Deployment", "namespace": "default"},
+			map[string]any{"kind": "Deployment", "namespace": "default", "limit": float64(1)},
+		}}},
+	})
+	assert.NoError(t, err)
+	response, ok := s.HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+	assert.True(t, ok)
+	result := response.Result.(mcp.CallToolResult)
+	assert.False(t, result.IsError)
+
+	var out struct {
+		Results []BatchQueryResult `json:"results"`
+		Failed  int                `json:"failed"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	assert.Equal(t, 0, out.Failed, "queries must not be throttled by the slot the batch holds: %+v", out.Results)
+	assert.Len(t, out.Results, 2)
+}
+
+func TestParseAndValidateBatchQueryParams(t *testing.T) {
+	_, err := parseAndValidateBatchQueryParams(map[string]any{"queries": []any{}})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateBatchQueryParams(map[string]any{"queries": []any{map[string]any{"tool": "drain_node", "args": map[string]any{"node": "n1"}}}})
+	assert.EqualError(t, err, "query 0: drain_node is not a read-only tool and cannot be batched")
+
+	_, err = parseAndValidateBatchQueryParams(map[string]any{"queries": []any{map[string]any{"tool": "velero_restore", "args": map[string]any{"backup": "nightly", "dryRun": true}}}})
+	assert.EqualError(t, err, "query 0: velero_restore is not a read-only tool and cannot be batched")
+
+	_, err = parseAndValidateBatchQueryParams(map[string]any{"queries": []any{map[string]any{"tool": "drain_node", "args": map[string]any{"node": "n1", "dryRun": true}}}})
+	assert.Error(t, err)
+
+	queries, err := parseAndValidateBatchQueryParams(map[string]any{"queries": []any{map[string]any{"tool": "k8s_secret", "args": map[string]any{"action": "list"}}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "k8s_secret", queries[0].Tool)
+
+	_, err = parseAndValidateBatchQueryParams(map[string]any{"queries": []any{map[string]any{"tool": "batch_query"}}})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateBatchQueryParams(map[string]any{"queries": []any{map[string]any{"namespace": "default"}}})
+	assert.EqualError(t, err, "query 0 needs a kind or a tool")
+}
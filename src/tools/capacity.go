@@ -0,0 +1,356 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NodeUsage reports a node's live usage and scheduled requests against its allocatable capacity.
+type NodeUsage struct {
+	Name                 string  `json:"name"`
+	Ready                bool    `json:"ready"`
+	Unschedulable        bool    `json:"unschedulable,omitempty"`
+	CPU                  string  `json:"cpu,omitempty"`
+	Memory               string  `json:"memory,omitempty"`
+	CPUAllocatable       string  `json:"cpuAllocatable"`
+	MemoryAllocatable    string  `json:"memoryAllocatable"`
+	CPUUsagePercent      float64 `json:"cpuUsagePercent,omitempty"`
+	MemoryUsagePercent   float64 `json:"memoryUsagePercent,omitempty"`
+	CPURequestPercent    float64 `json:"cpuRequestPercent"`
+	MemoryRequestPercent float64 `json:"memoryRequestPercent"`
+	Pods                 int     `json:"pods"`
+	PodCapacity          int64   `json:"podCapacity"`
+}
+
+// nodeCapacity gathers allocatable, requested and used resources for each node.
+type nodeCapacity struct {
+	node        *corev1.Node
+	allocatable resourceTotals
+	requested   resourceTotals
+	used        *resourceTotals
+	pods        int
+}
+
+// TopNodesTool reports per-node usage from the metrics API alongside scheduled requests.
+type TopNodesTool struct {
+	client Client
+}
+
+// NewTopNodesTool creates a new TopNodesTool with the provided Kubernetes client.
+func NewTopNodesTool(client Client) *TopNodesTool {
+	return &TopNodesTool{client: client}
+}
+
+// Tool returns the MCP tool definition for top_nodes.
+func (t *TopNodesTool) Tool() mcp.Tool {
+	return mcp.NewTool("top_nodes",
+		mcp.WithDescription("Show per-node CPU and memory usage (like 'kubectl top nodes') together with the total pod requests scheduled on each node, as percentages of allocatable"),
+		mcp.WithString("labelSelector",
+			mcp.Description("Filter nodes by label selector (e.g., 'node-role.kubernetes.io/worker=')"),
+		),
+		mcp.WithString("sortBy",
+			mcp.Description("Sort by 'cpu' or 'memory' usage, highest first (default: cpu)"),
+			mcp.Enum("cpu", "memory"),
+		),
+	)
+}
+
+// Handler reports node usage.
+func (t *TopNodesTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	selector, _ := args["labelSelector"].(string)
+	sortBy, _ := args["sortBy"].(string)
+	sortBy = strings.ToLower(sortBy)
+	if sortBy == "" {
+		sortBy = "cpu"
+	}
+	if sortBy != "cpu" && sortBy != "memory" {
+		return nil, fmt.Errorf("sortBy must be 'cpu' or 'memory'")
+	}
+
+	capacities, metricsWarning, err := collectNodeCapacity(ctx, t.client, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]NodeUsage, 0, len(capacities))
+	for _, nc := range capacities {
+		nodes = append(nodes, nc.usage())
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if sortBy == "memory" {
+			return nodes[i].MemoryUsagePercent > nodes[j].MemoryUsagePercent
+		}
+		return nodes[i].CPUUsagePercent > nodes[j].CPUUsagePercent
+	})
+
+	result := map[string]any{
+		"nodes":    nodes,
+		"sortedBy": sortBy,
+	}
+	if metricsWarning != "" {
+		result["metricsWarning"] = metricsWarning
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// ClusterCapacityTool summarizes cluster-wide allocatable, requested and used resources.
+type ClusterCapacityTool struct {
+	client Client
+}
+
+// NewClusterCapacityTool creates a new ClusterCapacityTool with the provided Kubernetes client.
+func NewClusterCapacityTool(client Client) *ClusterCapacityTool {
+	return &ClusterCapacityTool{client: client}
+}
+
+// Tool returns the MCP tool definition for cluster_capacity.
+func (c *ClusterCapacityTool) Tool() mcp.Tool {
+	return mcp.NewTool("cluster_capacity",
+		mcp.WithDescription("Capacity-planning report: total allocatable vs requested vs used CPU/memory across schedulable nodes, remaining headroom, and optionally how many more pods of a given size would fit"),
+		mcp.WithString("labelSelector",
+			mcp.Description("Only include nodes matching this label selector (e.g., a node pool label)"),
+		),
+		mcp.WithString("podCPU",
+			mcp.Description("CPU request of a hypothetical pod for the fit estimate, e.g. '500m'"),
+		),
+		mcp.WithString("podMemory",
+			mcp.Description("Memory request of a hypothetical pod for the fit estimate, e.g. '1Gi'"),
+		),
+	)
+}
+
+// Handler builds the capacity report.
+func (c *ClusterCapacityTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	selector, _ := args["labelSelector"].(string)
+
+	var podSize resourceTotals
+	if v, ok := args["podCPU"].(string); ok && v != "" {
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid podCPU: %w", err)
+		}
+		podSize.CPU = q.MilliValue()
+	}
+	if v, ok := args["podMemory"].(string); ok && v != "" {
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid podMemory: %w", err)
+		}
+		podSize.Memory = q.Value()
+	}
+
+	capacities, metricsWarning, err := collectNodeCapacity(ctx, c.client, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var allocatable, requested, used resourceTotals
+	schedulable, withMetrics, fits := 0, 0, int64(0)
+	for _, nc := range capacities {
+		if nc.node.Spec.Unschedulable || !nodeReady(nc.node) {
+			continue
+		}
+		schedulable++
+		allocatable.add(nc.allocatable)
+		requested.add(nc.requested)
+		if nc.used != nil {
+			used.add(*nc.used)
+			withMetrics++
+		}
+		if podSize.CPU > 0 || podSize.Memory > 0 {
+			fits += podsThatFit(nc, podSize)
+		}
+	}
+
+	headroom := resourceTotals{CPU: allocatable.CPU - requested.CPU, Memory: allocatable.Memory - requested.Memory}
+	result := map[string]any{
+		"nodes":            len(capacities),
+		"schedulableNodes": schedulable,
+		"allocatable":      map[string]string{"cpu": formatCPU(allocatable.CPU), "memory": formatMemory(allocatable.Memory)},
+		"requested": map[string]any{
+			"cpu":           formatCPU(requested.CPU),
+			"memory":        formatMemory(requested.Memory),
+			"cpuPercent":    percentOf(requested.CPU, allocatable.CPU),
+			"memoryPercent": percentOf(requested.Memory, allocatable.Memory),
+		},
+		"headroom": map[string]string{"cpu": formatCPU(headroom.CPU), "memory": formatMemory(headroom.Memory)},
+	}
+	if withMetrics > 0 {
+		result["used"] = map[string]any{
+			"cpu":           formatCPU(used.CPU),
+			"memory":        formatMemory(used.Memory),
+			"cpuPercent":    percentOf(used.CPU, allocatable.CPU),
+			"memoryPercent": percentOf(used.Memory, allocatable.Memory),
+			"nodesReported": withMetrics,
+		}
+	}
+	if metricsWarning != "" {
+		result["metricsWarning"] = metricsWarning
+	}
+	if podSize.CPU > 0 || podSize.Memory > 0 {
+		result["fitEstimate"] = map[string]any{
+			"podCPU":      formatCPU(podSize.CPU),
+			"podMemory":   formatMemory(podSize.Memory),
+			"podsThatFit": fits,
+		}
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// collectNodeCapacity lists nodes, their scheduled pod requests and, when available, live metrics.
+// A metrics failure is returned as a warning so callers can still report requests.
+func collectNodeCapacity(ctx context.Context, client Client, selector string) ([]*nodeCapacity, string, error) {
+	clientset, err := client.Clientset()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	requested, podCounts, err := requestsByNode(ctx, clientset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	capacities := make([]*nodeCapacity, 0, len(nodes.Items))
+	byName := make(map[string]*nodeCapacity, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		nc := &nodeCapacity{
+			node:        node,
+			allocatable: resourceListTotals(node.Status.Allocatable),
+			requested:   requested[node.Name],
+			pods:        podCounts[node.Name],
+		}
+		capacities = append(capacities, nc)
+		byName[node.Name] = nc
+	}
+
+	ri, err := client.ResourceInterface(metricsGVR("nodes"), false, "")
+	if err != nil {
+		return capacities, fmt.Sprintf("node metrics unavailable: %v", err), nil
+	}
+	list, err := ri.List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return capacities, fmt.Sprintf("node metrics unavailable (is metrics-server installed?): %v", err), nil
+	}
+	for _, item := range list.Items {
+		nc, ok := byName[item.GetName()]
+		if !ok {
+			continue
+		}
+		usage, _, _ := unstructured.NestedStringMap(item.Object, "usage")
+		totals := usageTotals(usage)
+		nc.used = &totals
+	}
+
+	return capacities, "", nil
+}
+
+// requestsByNode sums the requests of all non-terminated pods per node.
+func requestsByNode(ctx context.Context, clientset kubernetes.Interface) (map[string]resourceTotals, map[string]int, error) {
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	requested := map[string]resourceTotals{}
+	counts := map[string]int{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		podRequests, _ := podRequestsAndLimits(pod)
+		totals := requested[pod.Spec.NodeName]
+		totals.add(podRequests)
+		requested[pod.Spec.NodeName] = totals
+		counts[pod.Spec.NodeName]++
+	}
+	return requested, counts, nil
+}
+
+// usage converts the gathered capacity into the reported NodeUsage.
+func (nc *nodeCapacity) usage() NodeUsage {
+	podCapacity := int64(0)
+	if q, ok := nc.node.Status.Allocatable[corev1.ResourcePods]; ok {
+		podCapacity = q.Value()
+	}
+	usage := NodeUsage{
+		Name:                 nc.node.Name,
+		Ready:                nodeReady(nc.node),
+		Unschedulable:        nc.node.Spec.Unschedulable,
+		CPUAllocatable:       formatCPU(nc.allocatable.CPU),
+		MemoryAllocatable:    formatMemory(nc.allocatable.Memory),
+		CPURequestPercent:    percentOf(nc.requested.CPU, nc.allocatable.CPU),
+		MemoryRequestPercent: percentOf(nc.requested.Memory, nc.allocatable.Memory),
+		Pods:                 nc.pods,
+		PodCapacity:          podCapacity,
+	}
+	if nc.used != nil {
+		usage.CPU = formatCPU(nc.used.CPU)
+		usage.Memory = formatMemory(nc.used.Memory)
+		usage.CPUUsagePercent = percentOf(nc.used.CPU, nc.allocatable.CPU)
+		usage.MemoryUsagePercent = percentOf(nc.used.Memory, nc.allocatable.Memory)
+	}
+	return usage
+}
+
+// podsThatFit estimates how many more pods of the given size the node can accept based on requests.
+func podsThatFit(nc *nodeCapacity, podSize resourceTotals) int64 {
+	fit := int64(-1)
+	if podSize.CPU > 0 {
+		fit = max((nc.allocatable.CPU-nc.requested.CPU)/podSize.CPU, 0)
+	}
+	if podSize.Memory > 0 {
+		memFit := max((nc.allocatable.Memory-nc.requested.Memory)/podSize.Memory, 0)
+		if fit < 0 || memFit < fit {
+			fit = memFit
+		}
+	}
+	if q, ok := nc.node.Status.Allocatable[corev1.ResourcePods]; ok {
+		slots := max(q.Value()-int64(nc.pods), 0)
+		if fit < 0 || slots < fit {
+			fit = slots
+		}
+	}
+	return max(fit, 0)
+}
+
+// nodeReady reports whether the node's Ready condition is True.
+func nodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
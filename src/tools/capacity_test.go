@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPodsThatFit(t *testing.T) {
+	node := &corev1.Node{Status: corev1.NodeStatus{
+		Allocatable: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+	}}
+	nc := &nodeCapacity{
+		node:        node,
+		allocatable: resourceTotals{CPU: 4000, Memory: 8 << 30},
+		requested:   resourceTotals{CPU: 1000, Memory: 2 << 30},
+		pods:        4,
+	}
+
+	testCases := []struct {
+		name     string
+		podSize  resourceTotals
+		expected int64
+	}{
+		{name: "CPUBound", podSize: resourceTotals{CPU: 1000, Memory: 512 << 20}, expected: 3},
+		{name: "MemoryBound", podSize: resourceTotals{CPU: 100, Memory: 2 << 30}, expected: 3},
+		{name: "PodSlotBound", podSize: resourceTotals{CPU: 10}, expected: 6},
+		{name: "NoneFit", podSize: resourceTotals{CPU: 8000}, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, podsThatFit(nc, tc.podSize))
+		})
+	}
+}
+
+func TestNodeReady(t *testing.T) {
+	ready := &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+		{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+		{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+	}}}
+	notReady := &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionUnknown},
+	}}}
+
+	assert.True(t, nodeReady(ready))
+	assert.False(t, nodeReady(notReady))
+	assert.False(t, nodeReady(&corev1.Node{}))
+}
@@ -0,0 +1,301 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// cert-manager issuer resources.
+var (
+	certManagerIssuerGVR        = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "issuers"}
+	certManagerClusterIssuerGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "clusterissuers"}
+)
+
+// certManagerIssuerTypes are the issuer types built into cert-manager, in the order they are checked.
+var certManagerIssuerTypes = []string{"acme", "ca", "selfSigned", "vault", "venafi"}
+
+// CertManagerCertificate is the status of one cert-manager Certificate.
+type CertManagerCertificate struct {
+	CertExpiry
+	SecretName             string `json:"secretName,omitempty"`
+	Issuing                bool   `json:"issuing,omitempty"`
+	Revision               int64  `json:"revision,omitempty"`
+	FailedIssuanceAttempts int64  `json:"failedIssuanceAttempts,omitempty"`
+	LastFailureTime        string `json:"lastFailureTime,omitempty"`
+}
+
+// CertManagerIssuer is the status of one Issuer or ClusterIssuer.
+type CertManagerIssuer struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Type      string `json:"type"`
+	Server    string `json:"server,omitempty"`
+	Ready     bool   `json:"ready"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// certManagerCertificateStatus extends the expiry of a Certificate with its issuance state.
+func certManagerCertificateStatus(item *unstructured.Unstructured, now time.Time, warningDays int) CertManagerCertificate {
+	cert := CertManagerCertificate{CertExpiry: certManagerExpiry(item, now, warningDays)}
+	cert.SecretName, _, _ = unstructured.NestedString(item.Object, "spec", "secretName")
+	cert.Revision, _, _ = unstructured.NestedInt64(item.Object, "status", "revision")
+	cert.FailedIssuanceAttempts, _, _ = unstructured.NestedInt64(item.Object, "status", "failedIssuanceAttempts")
+	cert.LastFailureTime, _, _ = unstructured.NestedString(item.Object, "status", "lastFailureTime")
+
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		if cond, ok := c.(map[string]any); ok && cond["type"] == "Issuing" && cond["status"] == "True" {
+			cert.Issuing = true
+			if cert.Error == "" {
+				cert.Error, _ = cond["message"].(string)
+			}
+		}
+	}
+	if cert.Issuing && cert.Status == "notIssued" {
+		cert.Status = "issuing"
+	}
+	return cert
+}
+
+// certManagerIssuerStatus reads the type and Ready condition of an Issuer or ClusterIssuer.
+func certManagerIssuerStatus(item *unstructured.Unstructured) CertManagerIssuer {
+	issuer := CertManagerIssuer{Kind: item.GetKind(), Name: item.GetName(), Namespace: item.GetNamespace(), Type: "unknown"}
+	spec, _, _ := unstructured.NestedMap(item.Object, "spec")
+	for _, t := range certManagerIssuerTypes {
+		if _, ok := spec[t]; ok {
+			issuer.Type = t
+			break
+		}
+	}
+	switch issuer.Type {
+	case "acme", "vault":
+		issuer.Server, _, _ = unstructured.NestedString(spec, issuer.Type, "server")
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		issuer.Ready = cond["status"] == "True"
+		issuer.Reason, _ = cond["reason"].(string)
+		if !issuer.Ready {
+			issuer.Message, _ = cond["message"].(string)
+		}
+	}
+	return issuer
+}
+
+// certManagerRenewConditions returns the Certificate conditions with Issuing set to True, which is how
+// 'cmctl renew' asks cert-manager to reissue a certificate. It fails if an issuance is already running.
+func certManagerRenewConditions(conditions []any, now time.Time) ([]any, error) {
+	renewed := make([]any, 0, len(conditions)+1)
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if ok && cond["type"] == "Issuing" {
+			if cond["status"] == "True" {
+				return nil, errors.New("certificate is already being issued")
+			}
+			continue
+		}
+		renewed = append(renewed, c)
+	}
+	return append(renewed, map[string]any{
+		"type":               "Issuing",
+		"status":             "True",
+		"reason":             "ManuallyTriggered",
+		"message":            "Certificate re-issuance manually triggered",
+		"lastTransitionTime": now.UTC().Format(time.RFC3339),
+	}), nil
+}
+
+// CertManagerStatusTool summarizes cert-manager Certificates, Issuers and ClusterIssuers.
+type CertManagerStatusTool struct {
+	client Client
+}
+
+// NewCertManagerStatusTool creates a new CertManagerStatusTool with the provided Kubernetes client.
+func NewCertManagerStatusTool(client Client) *CertManagerStatusTool {
+	return &CertManagerStatusTool{client: client}
+}
+
+// Tool returns the MCP tool definition for certmanager_status.
+func (c *CertManagerStatusTool) Tool() mcp.Tool {
+	return mcp.NewTool("certmanager_status",
+		mcp.WithDescription("Summarize cert-manager: readiness, expiry, renewal time, in-progress issuance and failed attempts of every Certificate, and the type (ACME, CA, Vault, ...) and readiness of Issuers and ClusterIssuers"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the Certificates and Issuers (leave empty for all namespaces); ClusterIssuers are always included"),
+		),
+		mcp.WithNumber("warningDays",
+			mcp.Description("Flag certificates expiring within this many days (default: 30)"),
+		),
+	)
+}
+
+// Handler lists the cert-manager resources and reports their status.
+func (c *CertManagerStatusTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	warningDays := 30
+	if v, ok := args["warningDays"].(float64); ok && v >= 0 {
+		warningDays = int(v)
+	}
+
+	list := func(gvr schema.GroupVersionResource, namespaced bool, ns string) ([]unstructured.Unstructured, error) {
+		ri, err := c.client.ResourceInterface(gvr, namespaced, ns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resource interface: %w", err)
+		}
+		items, err := ri.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s (is cert-manager installed?): %w", gvr.Resource, err)
+		}
+		return items.Items, nil
+	}
+
+	certificates, err := list(certManagerCertificateGVR, true, namespace)
+	if err != nil {
+		return nil, err
+	}
+	issuerItems, err := list(certManagerIssuerGVR, true, namespace)
+	if err != nil {
+		return nil, err
+	}
+	clusterIssuerItems, err := list(certManagerClusterIssuerGVR, false, "")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	certs := make([]CertManagerCertificate, 0, len(certificates))
+	counts := map[string]int{}
+	for i := range certificates {
+		cert := certManagerCertificateStatus(&certificates[i], now, warningDays)
+		counts[cert.Status]++
+		certs = append(certs, cert)
+	}
+	sort.SliceStable(certs, func(i, j int) bool { return certExpiresBefore(&certs[i].CertExpiry, &certs[j].CertExpiry) })
+
+	issuers := make([]CertManagerIssuer, 0, len(issuerItems)+len(clusterIssuerItems))
+	notReadyIssuers := 0
+	for _, items := range [][]unstructured.Unstructured{clusterIssuerItems, issuerItems} {
+		for i := range items {
+			issuer := certManagerIssuerStatus(&items[i])
+			if !issuer.Ready {
+				notReadyIssuers++
+			}
+			issuers = append(issuers, issuer)
+		}
+	}
+
+	result := map[string]any{
+		"namespace":       namespace,
+		"warningDays":     warningDays,
+		"certificates":    certs,
+		"counts":          counts,
+		"issuers":         issuers,
+		"notReadyIssuers": notReadyIssuers,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// CertManagerRenewTool forces cert-manager to reissue a Certificate.
+type CertManagerRenewTool struct {
+	client Client
+}
+
+// NewCertManagerRenewTool creates a new CertManagerRenewTool with the provided Kubernetes client.
+func NewCertManagerRenewTool(client Client) *CertManagerRenewTool {
+	return &CertManagerRenewTool{client: client}
+}
+
+// Tool returns the MCP tool definition for certmanager_renew.
+func (c *CertManagerRenewTool) Tool() mcp.Tool {
+	return mcp.NewTool("certmanager_renew",
+		mcp.WithDescription("Force cert-manager to renew a Certificate now (like 'cmctl renew') by setting its Issuing condition; the issuer is asked for a new certificate and the Secret is updated once it is issued"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the Certificate (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("certificate",
+			mcp.Required(),
+			mcp.Description("Name of the Certificate to renew"),
+		),
+	)
+}
+
+// Handler sets the Issuing condition on the Certificate status.
+func (c *CertManagerRenewTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	name, _ := args["certificate"].(string)
+	if name == "" {
+		return nil, errors.New("certificate must be provided")
+	}
+	if err := validation.ValidateResourceName(name); err != nil {
+		return nil, fmt.Errorf("invalid certificate name: %w", err)
+	}
+
+	ri, err := c.client.ResourceInterface(certManagerCertificateGVR, true, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource interface: %w", err)
+	}
+	cert, err := ri.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate %s/%s: %w", namespace, name, err)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+	renewed, err := certManagerRenewConditions(conditions, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("cannot renew certificate %s/%s: %w", namespace, name, err)
+	}
+	if err := unstructured.SetNestedSlice(cert.Object, renewed, "status", "conditions"); err != nil {
+		return nil, fmt.Errorf("failed to set conditions: %w", err)
+	}
+	// Updating the status with the fetched resourceVersion fails on conflict instead of overwriting a concurrent change.
+	if _, err := ri.UpdateStatus(ctx, cert, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to update certificate status: %w", err)
+	}
+
+	result := map[string]any{
+		"status":      "Renewal triggered",
+		"certificate": name,
+		"namespace":   namespace,
+	}
+	if secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName"); secretName != "" {
+		result["secretName"] = secretName
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
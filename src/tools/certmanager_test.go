@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCertManagerCertificateStatus(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "web", "namespace": "app"},
+		"spec":     map[string]any{"secretName": "web-tls", "issuerRef": map[string]any{"name": "letsencrypt", "kind": "ClusterIssuer"}},
+		"status": map[string]any{
+			"failedIssuanceAttempts": int64(2),
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "False", "message": "Issuing certificate as Secret does not exist"},
+				map[string]any{"type": "Issuing", "status": "True", "message": "order is pending"},
+			},
+		},
+	}}
+
+	cert := certManagerCertificateStatus(item, now, 30)
+	assert.Equal(t, "issuing", cert.Status)
+	assert.True(t, cert.Issuing)
+	assert.Equal(t, "web-tls", cert.SecretName)
+	assert.Equal(t, int64(2), cert.FailedIssuanceAttempts)
+	assert.Equal(t, "Issuing certificate as Secret does not exist", cert.Error)
+}
+
+func TestCertManagerIssuerStatus(t *testing.T) {
+	item := &unstructured.Unstructured{Object: map[string]any{
+		"kind":     "ClusterIssuer",
+		"metadata": map[string]any{"name": "letsencrypt"},
+		"spec":     map[string]any{"acme": map[string]any{"server": "https://acme-v02.api.letsencrypt.org/directory"}},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Ready", "status": "False", "reason": "ErrRegisterACMEAccount", "message": "rate limited"},
+		}},
+	}}
+
+	issuer := certManagerIssuerStatus(item)
+	assert.Equal(t, "acme", issuer.Type)
+	assert.Equal(t, "https://acme-v02.api.letsencrypt.org/directory", issuer.Server)
+	assert.False(t, issuer.Ready)
+	assert.Equal(t, "rate limited", issuer.Message)
+}
+
+func TestCertManagerRenewConditions(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	conditions := []any{
+		map[string]any{"type": "Ready", "status": "True"},
+		map[string]any{"type": "Issuing", "status": "False"},
+	}
+
+	renewed, err := certManagerRenewConditions(conditions, now)
+	assert.NoError(t, err)
+	assert.Len(t, renewed, 2)
+	issuing := renewed[1].(map[string]any)
+	assert.Equal(t, "True", issuing["status"])
+	assert.Equal(t, "ManuallyTriggered", issuing["reason"])
+
+	_, err = certManagerRenewConditions(renewed, now)
+	assert.EqualError(t, err, "certificate is already being issued")
+}
@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// certManagerCertificateGVR is the cert-manager Certificate resource.
+var certManagerCertificateGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+// CertExpiry is the expiry information of one certificate, from a TLS Secret or a cert-manager Certificate.
+type CertExpiry struct {
+	Source        string   `json:"source"`
+	Namespace     string   `json:"namespace"`
+	Name          string   `json:"name"`
+	Subject       string   `json:"subject,omitempty"`
+	DNSNames      []string `json:"dnsNames,omitempty"`
+	Issuer        string   `json:"issuer,omitempty"`
+	NotAfter      string   `json:"notAfter,omitempty"`
+	DaysRemaining int      `json:"daysRemaining"`
+	RenewalTime   string   `json:"renewalTime,omitempty"`
+	Ready         *bool    `json:"ready,omitempty"`
+	Status        string   `json:"status"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// CertExpiryTool reports certificate expiry across TLS Secrets and cert-manager Certificates.
+type CertExpiryTool struct {
+	client Client
+}
+
+// NewCertExpiryTool creates a new CertExpiryTool with the provided Kubernetes client.
+func NewCertExpiryTool(client Client) *CertExpiryTool {
+	return &CertExpiryTool{client: client}
+}
+
+// Tool returns the MCP tool definition for cert_expiry.
+func (c *CertExpiryTool) Tool() mcp.Tool {
+	return mcp.NewTool("cert_expiry",
+		mcp.WithDescription("Report certificate expiry: decodes kubernetes.io/tls Secrets and reads cert-manager Certificate statuses, returning expiry dates, issuers and days remaining sorted soonest first, with entries inside the warning threshold flagged"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to scan (leave empty for all namespaces)"),
+		),
+		mcp.WithNumber("warningDays",
+			mcp.Description("Flag certificates expiring within this many days (default: 30)"),
+		),
+		mcp.WithBoolean("onlyWarnings",
+			mcp.Description("Only return expired, expiring or invalid certificates (default: false)"),
+		),
+	)
+}
+
+// Handler collects and sorts the certificate expiries.
+func (c *CertExpiryTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	warningDays := 30
+	if v, ok := args["warningDays"].(float64); ok && v >= 0 {
+		warningDays = int(v)
+	}
+	onlyWarnings, _ := args["onlyWarnings"].(bool)
+
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	now := time.Now()
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + string(corev1.SecretTypeTLS),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list TLS secrets: %w", err)
+	}
+	certs := make([]CertExpiry, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		certs = append(certs, tlsSecretExpiry(&secrets.Items[i], now, warningDays))
+	}
+
+	result := map[string]any{"namespace": namespace, "warningDays": warningDays}
+	ri, err := c.client.ResourceInterface(certManagerCertificateGVR, true, namespace)
+	if err == nil {
+		var list *unstructured.UnstructuredList
+		if list, err = ri.List(ctx, metav1.ListOptions{}); err == nil {
+			for i := range list.Items {
+				certs = append(certs, certManagerExpiry(&list.Items[i], now, warningDays))
+			}
+		}
+	}
+	result["certManager"] = err == nil
+	if err != nil {
+		result["certManagerNote"] = "cert-manager Certificates not listed: " + err.Error()
+	}
+
+	if onlyWarnings {
+		filtered := certs[:0]
+		for _, cert := range certs {
+			if cert.Status != "ok" {
+				filtered = append(filtered, cert)
+			}
+		}
+		certs = filtered
+	}
+	sortCertExpiries(certs)
+
+	counts := map[string]int{}
+	for _, cert := range certs {
+		counts[cert.Status]++
+	}
+	result["certificates"] = certs
+	result["counts"] = counts
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// tlsSecretExpiry decodes the leaf certificate of a TLS Secret.
+func tlsSecretExpiry(secret *corev1.Secret, now time.Time, warningDays int) CertExpiry {
+	expiry := CertExpiry{Source: "Secret", Namespace: secret.Namespace, Name: secret.Name}
+	cert, err := parseCertificatePEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		expiry.Status = "invalid"
+		expiry.Error = err.Error()
+		return expiry
+	}
+	expiry.Subject = cert.Subject.CommonName
+	expiry.DNSNames = cert.DNSNames
+	expiry.Issuer = cert.Issuer.CommonName
+	if expiry.Issuer == "" {
+		expiry.Issuer = strings.Join(cert.Issuer.Organization, ",")
+	}
+	if issuer := secret.Annotations["cert-manager.io/issuer-name"]; issuer != "" {
+		expiry.Issuer = secret.Annotations["cert-manager.io/issuer-kind"] + "/" + issuer + " (" + expiry.Issuer + ")"
+	}
+	setCertExpiry(&expiry, cert.NotAfter, now, warningDays)
+	return expiry
+}
+
+// certManagerExpiry reads the expiry of a cert-manager Certificate from its status.
+func certManagerExpiry(item *unstructured.Unstructured, now time.Time, warningDays int) CertExpiry {
+	expiry := CertExpiry{Source: "Certificate", Namespace: item.GetNamespace(), Name: item.GetName()}
+	expiry.Subject, _, _ = unstructured.NestedString(item.Object, "spec", "commonName")
+	expiry.DNSNames, _, _ = unstructured.NestedStringSlice(item.Object, "spec", "dnsNames")
+	kind, _, _ := unstructured.NestedString(item.Object, "spec", "issuerRef", "kind")
+	name, _, _ := unstructured.NestedString(item.Object, "spec", "issuerRef", "name")
+	if kind == "" {
+		kind = "Issuer"
+	}
+	expiry.Issuer = kind + "/" + name
+	expiry.RenewalTime, _, _ = unstructured.NestedString(item.Object, "status", "renewalTime")
+
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		ready := cond["status"] == "True"
+		expiry.Ready = &ready
+		if !ready {
+			expiry.Error, _ = cond["message"].(string)
+		}
+	}
+
+	notAfter, _, _ := unstructured.NestedString(item.Object, "status", "notAfter")
+	t, err := time.Parse(time.RFC3339, notAfter)
+	if err != nil {
+		expiry.Status = "notIssued"
+		return expiry
+	}
+	setCertExpiry(&expiry, t, now, warningDays)
+	if expiry.Status == "ok" && expiry.Ready != nil && !*expiry.Ready {
+		expiry.Status = "notReady"
+	}
+	return expiry
+}
+
+// setCertExpiry fills in the expiry date, days remaining and status.
+func setCertExpiry(expiry *CertExpiry, notAfter, now time.Time, warningDays int) {
+	expiry.NotAfter = notAfter.UTC().Format(time.RFC3339)
+	expiry.DaysRemaining = int(math.Floor(notAfter.Sub(now).Hours() / 24))
+	switch {
+	case !now.Before(notAfter):
+		expiry.Status = "expired"
+	case expiry.DaysRemaining < warningDays:
+		expiry.Status = "expiring"
+	default:
+		expiry.Status = "ok"
+	}
+}
+
+// sortCertExpiries orders certificates soonest-expiring first; entries without an expiry date go last.
+func sortCertExpiries(certs []CertExpiry) {
+	sort.SliceStable(certs, func(i, j int) bool { return certExpiresBefore(&certs[i], &certs[j]) })
+}
+
+// certExpiresBefore reports whether a expires before b; entries without an expiry date sort last.
+func certExpiresBefore(a, b *CertExpiry) bool {
+	if (a.NotAfter == "") != (b.NotAfter == "") {
+		return a.NotAfter != ""
+	}
+	return a.NotAfter < b.NotAfter
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCertManagerExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "web", "namespace": "app"},
+		"spec": map[string]any{
+			"dnsNames":  []any{"web.example.com"},
+			"issuerRef": map[string]any{"kind": "ClusterIssuer", "name": "letsencrypt"},
+		},
+		"status": map[string]any{
+			"notAfter":   "2025-01-11T00:00:00Z",
+			"conditions": []any{map[string]any{"type": "Ready", "status": "True"}},
+		},
+	}}
+
+	expiry := certManagerExpiry(item, now, 30)
+	assert.Equal(t, "ClusterIssuer/letsencrypt", expiry.Issuer)
+	assert.Equal(t, []string{"web.example.com"}, expiry.DNSNames)
+	assert.Equal(t, 10, expiry.DaysRemaining)
+	assert.Equal(t, "expiring", expiry.Status)
+
+	assert.Equal(t, "ok", certManagerExpiry(item, now, 7).Status)
+	assert.Equal(t, "expired", certManagerExpiry(item, now.AddDate(0, 1, 0), 7).Status)
+
+	unstructured.RemoveNestedField(item.Object, "status", "notAfter")
+	assert.Equal(t, "notIssued", certManagerExpiry(item, now, 30).Status)
+}
+
+func TestSortCertExpiries(t *testing.T) {
+	certs := []CertExpiry{
+		{Name: "later", NotAfter: "2025-06-01T00:00:00Z"},
+		{Name: "pending"},
+		{Name: "sooner", NotAfter: "2025-02-01T00:00:00Z"},
+	}
+
+	sortCertExpiries(certs)
+
+	assert.Equal(t, "sooner", certs[0].Name)
+	assert.Equal(t, "later", certs[1].Name)
+	assert.Equal(t, "pending", certs[2].Name)
+}
@@ -5,41 +5,76 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
+	"sort"
+	"strings"
 
-	secretmanager "cloud.google.com/go/secretmanager/apiv1"
-	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // Environment variables used by this tool:
 // Required:
-//   GOOGLE_APPLICATION_CREDENTIALS - Path to the GCP service account JSON file (for local/outside GCP)
+//   Credentials of the selected backend (see secret_backend_<name>.go), e.g. Application Default Credentials for gcp
 // Optional:
-//   GOOGLE_CLOUD_PROJECT           - GCP Project ID (used if not provided in input)
-//   GCP_SECRET_NAME                - Secret name (used if not provided in input)
+//   SECRET_BACKEND                 - gcp, azure, aws, vault or k8s (used if backend is not provided in input)
+//   SECRET_NAME                    - Secret name (used if not provided in input)
+//   SECRET_ALLOWED_NAMES           - Comma-separated secret names or glob patterns change_env may modify (default: any)
+//   GCP_SECRET_NAME                - Former name of SECRET_NAME, read when it is not set
+//   GCP_ALLOWED_SECRETS            - Former name of SECRET_ALLOWED_NAMES, read when it is not set
 
-// ChangeEnvInput represents the input for changing a key in a GCP secret.
+// ChangeEnvInput represents the input for changing keys in a secret of any backend.
 type ChangeEnvInput struct {
-	ProjectID  string `json:"projectId,omitempty"`
-	SecretName string `json:"secretName"`
-	Key        string `json:"key"`
-	NewValue   string `json:"newValue"`
+	SecretBackendInput
+	SecretName        string            `json:"secretName"`
+	Key               string            `json:"key,omitempty"`
+	NewValue          string            `json:"newValue,omitempty"`
+	Changes           map[string]string `json:"changes,omitempty"`
+	DeleteKeys        []string          `json:"deleteKeys,omitempty"`
+	CreateIfMissing   bool              `json:"createIfMissing,omitempty"`
+	Format            string            `json:"format,omitempty"`
+	RestartDependents bool              `json:"restartDependents,omitempty"`
+	K8sSecretName     string            `json:"k8sSecretName,omitempty"`
+	DryRun            bool              `json:"dryRun,omitempty"`
 }
 
-// ChangeEnvTool provides functionality to update a key in a GCP secret.
-type ChangeEnvTool struct{}
+// envChangeSummary lists the keys an update touches, by kind of change.
+type envChangeSummary struct {
+	Updated   []string `json:"updated,omitempty"`
+	Created   []string `json:"created,omitempty"`
+	Deleted   []string `json:"deleted,omitempty"`
+	Unchanged []string `json:"unchanged,omitempty"`
+}
+
+// ChangeEnvTool updates, creates or deletes keys in a secret of any secret backend.
+type ChangeEnvTool struct {
+	client Client
+}
 
-func NewChangeEnvTool() *ChangeEnvTool {
-	return &ChangeEnvTool{}
+func NewChangeEnvTool(client Client) *ChangeEnvTool {
+	return &ChangeEnvTool{client: client}
 }
 
 func (t *ChangeEnvTool) Tool() mcp.Tool {
 	return mcp.NewTool("change_env",
-		mcp.WithDescription("Update a key in a Google Cloud Secret (JSON) and create a new version."),
-		mcp.WithString("projectId", mcp.Description("GCP Project ID (optional, will use GOOGLE_CLOUD_PROJECT env if not set)")),
-		mcp.WithString("secretName", mcp.Required(), mcp.Description("Name of the secret in Secret Manager")),
-		mcp.WithString("key", mcp.Required(), mcp.Description("Key in the JSON secret to update")),
-		mcp.WithString("newValue", mcp.Required(), mcp.Description("New value for the key")),
+		mcp.WithDescription("Update, create or delete keys in a secret (JSON or dotenv) in Google Cloud Secret Manager, Azure Key Vault, AWS Secrets Manager, Vault KV or Kubernetes and create a single new version with all changes."),
+		withSecretBackend(),
+		mcp.WithString("secretName", mcp.Description("Name of the secret (optional, will use SECRET_NAME env if not set)")),
+		mcp.WithString("key", mcp.Description("Key in the JSON secret to update (use with newValue)")),
+		mcp.WithString("newValue", mcp.Description("New value for the key")),
+		mcp.WithObject("changes",
+			mcp.Description("Keys to set and their new values, e.g. {\"LOG_LEVEL\": \"debug\", \"API_URL\": \"https://api\"}"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("deleteKeys", mcp.Description("Keys to remove from the secret"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithBoolean("createIfMissing", mcp.Description("Add keys from changes that do not exist yet instead of failing (default: false)")),
+		mcp.WithString("format",
+			mcp.Description("Payload format: a JSON object, dotenv KEY=VALUE lines, or raw to replace the whole payload with newValue (default: detected from the payload)"),
+			mcp.Enum(secretFormatJSON, secretFormatDotenv, secretFormatRaw),
+		),
+		mcp.WithBoolean("restartDependents", mcp.Description("Rollout restart Deployments and StatefulSets that reference the in-cluster Secret synced from this secret (default: false)")),
+		mcp.WithString("namespace", mcp.Description("Namespace of the Secret for the k8s backend, and of the in-cluster Secret used with restartDependents (defaults to 'default')")),
+		mcp.WithString("k8sSecretName", mcp.Description("Name of the in-cluster Secret used with restartDependents (defaults to the secret name)")),
+		mcp.WithBoolean("dryRun", mcp.Description("Only validate the changes and report which keys would change, and with restartDependents which workloads would restart (server-side dry run), without creating a new version (default: false)")),
 	)
 }
 
@@ -49,79 +84,176 @@ func (t *ChangeEnvTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*
 		return nil, fmt.Errorf("failed to parse input: %w", err)
 	}
 
-	// Get secret name from env
-	input.SecretName = os.Getenv("GCP_SECRET_NAME")
-	if input.SecretName == "" {
-		return nil, fmt.Errorf("GCP_SECRET_NAME environment variable must be set for secret name")
+	backend, err := newSecretBackend(ctx, t.client, input.SecretBackendInput)
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Close()
+
+	current, previousVersion, err := backend.Get(ctx, input.SecretName, "latest")
+	if err != nil {
+		return nil, err
+	}
+
+	format := input.Format
+	if format == "" {
+		format = detectSecretFormat(current)
+	}
+	updated, summary, err := updateSecretPayload(current, format, input)
+	if err != nil {
+		return nil, err
 	}
 
-	// Always try to get projectID from env if not set in input
-	if input.ProjectID == "" {
-		input.ProjectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	output := map[string]any{
+		"secretName":      input.SecretName,
+		"format":          format,
+		"previousVersion": previousVersion,
+		"changes":         summary,
 	}
-	if input.ProjectID == "" {
-		return nil, fmt.Errorf("projectId must be provided (either as input or environment variable)")
+	if input.DryRun {
+		output["status"] = "Dry run: secret not changed"
+		t.addRestarts(ctx, input, output)
+		out, err := json.Marshal(output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal result: %w", err)
+		}
+		return mcp.NewToolResultText(string(out)), nil
 	}
 
-	// Require GOOGLE_APPLICATION_CREDENTIALS to be set when running outside GCP
-	if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") == "" {
-		return nil, fmt.Errorf("google credentials not found: set GOOGLE_APPLICATION_CREDENTIALS to a service account JSON file")
+	// Push the updated secret as a new version
+	version, err := backend.Update(ctx, input.SecretName, updated)
+	if err != nil {
+		return nil, err
 	}
 
-	client, err := secretmanager.NewClient(ctx)
+	output["status"] = "Secret updated and new version created"
+	output["version"] = version
+	t.addRestarts(ctx, input, output)
+
+	out, err := json.Marshal(output)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create secretmanager client: %w", err)
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
 	}
-	defer client.Close()
+	return mcp.NewToolResultText(string(out)), nil
+}
 
-	secretPath := fmt.Sprintf("projects/%s/secrets/%s/versions/latest", input.ProjectID, input.SecretName)
-	accessReq := &secretmanagerpb.AccessSecretVersionRequest{Name: secretPath}
-	result, err := client.AccessSecretVersion(ctx, accessReq)
+// addRestarts restarts the dependents when asked to and records them in output under the same key
+// as the other tools that restart consumers, "wouldRestart" for a dry run and "restarted" otherwise.
+func (t *ChangeEnvTool) addRestarts(ctx context.Context, input *ChangeEnvInput, output map[string]any) {
+	if !input.RestartDependents {
+		return
+	}
+	restarted, err := t.restartDependents(ctx, input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to access secret: %w", err)
+		output["restartError"] = err.Error()
 	}
+	output[restartedKey(input.DryRun)] = strings.Join(restarted, ",")
+}
 
-	var secretData map[string]interface{}
-	if err := json.Unmarshal(result.Payload.Data, &secretData); err != nil {
-		return nil, fmt.Errorf("failed to parse secret JSON: %w", err)
+// restartDependents restarts the workloads that consume the in-cluster copy of the secret, or with
+// dryRun only validates the restarts.
+func (t *ChangeEnvTool) restartDependents(ctx context.Context, input *ChangeEnvInput) ([]string, error) {
+	clientset, err := t.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
 	}
 
-	if _, ok := secretData[input.Key]; !ok {
-		return nil, fmt.Errorf("key '%s' not found in secret", input.Key)
+	name := input.K8sSecretName
+	if name == "" {
+		name = input.SecretName
 	}
-	secretData[input.Key] = input.NewValue
+	return restartDependents(ctx, clientset, input.Namespace, configKindSecret, name, input.DryRun)
+}
 
-	json.MarshalIndent(secretData, "", "  ")
-	// updatedJSON, err := json.MarshalIndent(secretData, "", "  ")
-	// if err != nil {
-	// 	return nil, fmt.Errorf("failed to marshal updated secret: %w", err)
-	// }
+// applyEnvChanges sets and deletes keys of the decoded secret in place. Every key is checked before
+// the result is used, so a missing key fails the whole update rather than writing part of it.
+func applyEnvChanges(secretData map[string]any, changes map[string]string, deleteKeys []string, createIfMissing bool) (*envChangeSummary, error) {
+	summary := &envChangeSummary{}
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		current, ok := secretData[k]
+		switch {
+		case !ok && !createIfMissing:
+			return nil, fmt.Errorf("key '%s' not found in secret (set createIfMissing to add it)", k)
+		case !ok:
+			summary.Created = append(summary.Created, k)
+		case current == changes[k]:
+			summary.Unchanged = append(summary.Unchanged, k)
+		default:
+			summary.Updated = append(summary.Updated, k)
+		}
+	}
+	for _, k := range deleteKeys {
+		if _, ok := secretData[k]; !ok {
+			return nil, fmt.Errorf("key '%s' to delete not found in secret", k)
+		}
+		summary.Deleted = append(summary.Deleted, k)
+	}
 
-	// Push the updated secret as a new version
-	// addReq := &secretmanagerpb.AddSecretVersionRequest{
-	// 	Parent:  fmt.Sprintf("projects/%s/secrets/%s", input.ProjectID, input.SecretName),
-	// 	Payload: &secretmanagerpb.SecretPayload{Data: updatedJSON},
-	// }
-	// _, err = client.AddSecretVersion(ctx, addReq)
-	// if err != nil {
-	// 	return nil, fmt.Errorf("failed to add new secret version: %w", err)
-	// }
-
-	output := map[string]string{
-		"status": "Secret updated and new version created",
-		// "secretName":  input.SecretName,
-		// "key":         input.Key,
-		// "newValue":    input.NewValue,
-		// "updatedJson": string(updatedJSON),
-	}
-	out, _ := json.Marshal(output)
-	return mcp.NewToolResultText(string(out)), nil
+	for _, k := range keys {
+		secretData[k] = changes[k]
+	}
+	for _, k := range deleteKeys {
+		delete(secretData, k)
+	}
+	return summary, nil
+}
+
+// resolveSecretName falls back to SECRET_NAME when no secret name was given and checks the name
+// against SECRET_ALLOWED_NAMES.
+func resolveSecretName(name string) (string, error) {
+	if name == "" {
+		name = secretEnv("SECRET_NAME", "GCP_SECRET_NAME")
+	}
+	if name == "" {
+		return "", fmt.Errorf("secretName is required (either as input or SECRET_NAME environment variable)")
+	}
+	allowed := allowedSecretsFromEnv()
+	if len(allowed) > 0 && !secretNameAllowed(name, allowed) {
+		return "", fmt.Errorf("secret %q is not in SECRET_ALLOWED_NAMES", name)
+	}
+	return name, nil
+}
+
+// secretEnv returns the environment variable name, or the former GCP-specific variable it replaces
+// when name is not set.
+func secretEnv(name, former string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return os.Getenv(former)
+}
+
+// allowedSecretsFromEnv returns the secret names or patterns listed in SECRET_ALLOWED_NAMES.
+func allowedSecretsFromEnv() []string {
+	var secrets []string
+	for _, s := range strings.Split(secretEnv("SECRET_ALLOWED_NAMES", "GCP_ALLOWED_SECRETS"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			secrets = append(secrets, s)
+		}
+	}
+	return secrets
+}
+
+// secretNameAllowed reports whether the name equals or matches a glob pattern of the allowlist.
+func secretNameAllowed(name string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
 }
 
 func parseAndValidateChangeEnvParams(args map[string]any) (*ChangeEnvInput, error) {
 	input := &ChangeEnvInput{}
-	if v, ok := args["projectId"]; ok && v != nil {
-		input.ProjectID = v.(string)
+	var err error
+	if input.SecretBackendInput, err = parseSecretBackendInput(args); err != nil {
+		return nil, err
 	}
 	if v, ok := args["secretName"]; ok && v != nil {
 		input.SecretName = v.(string)
@@ -132,8 +264,63 @@ func parseAndValidateChangeEnvParams(args map[string]any) (*ChangeEnvInput, erro
 	if v, ok := args["newValue"]; ok && v != nil {
 		input.NewValue = v.(string)
 	}
-	if input.SecretName == "" || input.Key == "" || input.NewValue == "" {
-		return nil, fmt.Errorf("secretName, key, and newValue are required")
+	if v, ok := args["restartDependents"].(bool); ok {
+		input.RestartDependents = v
+	}
+	if v, ok := args["k8sSecretName"].(string); ok {
+		input.K8sSecretName = v
+	}
+	if v, ok := args["dryRun"].(bool); ok {
+		input.DryRun = v
+	}
+	if v, ok := args["createIfMissing"].(bool); ok {
+		input.CreateIfMissing = v
+	}
+
+	if input.Changes, err = stringMapArg(args, "changes"); err != nil {
+		return nil, err
+	}
+	if input.DeleteKeys, err = stringSliceArg(args, "deleteKeys"); err != nil {
+		return nil, err
+	}
+	if v, ok := args["format"].(string); ok {
+		input.Format = strings.ToLower(v)
+	}
+	switch input.Format {
+	case "", secretFormatJSON, secretFormatDotenv:
+	case secretFormatRaw:
+		// A raw payload has no keys: newValue replaces it entirely.
+		if input.Key != "" || input.NewValue == "" || len(input.Changes) > 0 || len(input.DeleteKeys) > 0 {
+			return nil, fmt.Errorf("format raw takes only newValue, which replaces the whole payload")
+		}
+		if input.SecretName, err = resolveSecretName(input.SecretName); err != nil {
+			return nil, err
+		}
+		return input, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q: use json, dotenv or raw", input.Format)
+	}
+
+	if input.Key != "" || input.NewValue != "" {
+		if input.Key == "" || input.NewValue == "" {
+			return nil, fmt.Errorf("key and newValue must be provided together")
+		}
+		if input.Changes == nil {
+			input.Changes = map[string]string{}
+		}
+		input.Changes[input.Key] = input.NewValue
+	}
+
+	if input.SecretName, err = resolveSecretName(input.SecretName); err != nil {
+		return nil, err
+	}
+	if len(input.Changes) == 0 && len(input.DeleteKeys) == 0 {
+		return nil, fmt.Errorf("at least one of key/newValue, changes or deleteKeys is required")
+	}
+	for _, k := range input.DeleteKeys {
+		if _, ok := input.Changes[k]; ok {
+			return nil, fmt.Errorf("key '%s' cannot be both changed and deleted", k)
+		}
 	}
 	return input, nil
 }
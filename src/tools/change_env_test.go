@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndValidateChangeEnvParams(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+	input, err := parseAndValidateChangeEnvParams(map[string]any{
+		"secretName": "app-env",
+		"key":        "LOG_LEVEL",
+		"newValue":   "debug",
+		"dryRun":     true,
+	})
+	assert.NoError(t, err)
+	assert.True(t, input.DryRun)
+	assert.False(t, input.RestartDependents)
+
+	input, err = parseAndValidateChangeEnvParams(map[string]any{"secretName": "app-env", "key": "LOG_LEVEL", "newValue": "info"})
+	assert.NoError(t, err)
+	assert.False(t, input.DryRun)
+
+	_, err = parseAndValidateChangeEnvParams(map[string]any{"secretName": "app-env", "key": "LOG_LEVEL"})
+	assert.Error(t, err)
+}
+
+func TestParseAndValidateChangeEnvMultiKey(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+	input, err := parseAndValidateChangeEnvParams(map[string]any{
+		"secretName":      "app-env",
+		"key":             "LOG_LEVEL",
+		"newValue":        "debug",
+		"changes":         map[string]any{"API_URL": "https://api"},
+		"deleteKeys":      []any{"OLD_FLAG"},
+		"createIfMissing": true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "API_URL": "https://api"}, input.Changes)
+	assert.Equal(t, []string{"OLD_FLAG"}, input.DeleteKeys)
+	assert.True(t, input.CreateIfMissing)
+
+	_, err = parseAndValidateChangeEnvParams(map[string]any{"secretName": "app-env", "changes": map[string]any{"A": "1"}, "deleteKeys": []any{"A"}})
+	assert.Error(t, err)
+	_, err = parseAndValidateChangeEnvParams(map[string]any{"secretName": "app-env"})
+	assert.Error(t, err)
+}
+
+func TestApplyEnvChanges(t *testing.T) {
+	data := map[string]any{"LOG_LEVEL": "info", "API_URL": "https://api", "OLD_FLAG": "1"}
+	summary, err := applyEnvChanges(data, map[string]string{"LOG_LEVEL": "debug", "API_URL": "https://api", "NEW_KEY": "x"}, []string{"OLD_FLAG"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, &envChangeSummary{Updated: []string{"LOG_LEVEL"}, Created: []string{"NEW_KEY"}, Deleted: []string{"OLD_FLAG"}, Unchanged: []string{"API_URL"}}, summary)
+	assert.Equal(t, map[string]any{"LOG_LEVEL": "debug", "API_URL": "https://api", "NEW_KEY": "x"}, data)
+
+	// A missing key leaves the secret untouched.
+	data = map[string]any{"LOG_LEVEL": "info"}
+	_, err = applyEnvChanges(data, map[string]string{"LOG_LEVEL": "debug", "MISSING": "x"}, nil, false)
+	assert.Error(t, err)
+	assert.Equal(t, map[string]any{"LOG_LEVEL": "info"}, data)
+	_, err = applyEnvChanges(data, nil, []string{"MISSING"}, false)
+	assert.Error(t, err)
+}
+
+func TestChangeEnvSecretName(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+	t.Setenv("SECRET_NAME", "default-env")
+	t.Setenv("SECRET_ALLOWED_NAMES", "default-env, app-*")
+
+	input, err := parseAndValidateChangeEnvParams(map[string]any{"key": "A", "newValue": "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "default-env", input.SecretName)
+
+	input, err = parseAndValidateChangeEnvParams(map[string]any{"secretName": "app-payments", "key": "A", "newValue": "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "app-payments", input.SecretName)
+
+	_, err = parseAndValidateChangeEnvParams(map[string]any{"secretName": "db-root", "key": "A", "newValue": "1"})
+	assert.EqualError(t, err, `secret "db-root" is not in SECRET_ALLOWED_NAMES`)
+
+	// The former GCP-specific names are read when the new ones are not set.
+	t.Setenv("SECRET_NAME", "")
+	t.Setenv("SECRET_ALLOWED_NAMES", "")
+	t.Setenv("GCP_SECRET_NAME", "legacy-env")
+	t.Setenv("GCP_ALLOWED_SECRETS", "legacy-*")
+	input, err = parseAndValidateChangeEnvParams(map[string]any{"key": "A", "newValue": "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy-env", input.SecretName)
+	_, err = parseAndValidateChangeEnvParams(map[string]any{"secretName": "app-payments", "key": "A", "newValue": "1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,317 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nodePressureConditions are node conditions that signal a problem when True.
+var nodePressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+	corev1.NodeNetworkUnavailable,
+}
+
+// DeprecatedAPIUsage is a deprecated API that clients have requested since the API server started.
+type DeprecatedAPIUsage struct {
+	Group          string `json:"group,omitempty"`
+	Version        string `json:"version"`
+	Resource       string `json:"resource"`
+	RemovedRelease string `json:"removedRelease,omitempty"`
+}
+
+// ClusterHealthTool produces a compact cluster-wide health overview.
+type ClusterHealthTool struct {
+	client Client
+}
+
+// NewClusterHealthTool creates a new ClusterHealthTool with the provided Kubernetes client.
+func NewClusterHealthTool(client Client) *ClusterHealthTool {
+	return &ClusterHealthTool{client: client}
+}
+
+// Tool returns the MCP tool definition for cluster_health.
+func (c *ClusterHealthTool) Tool() mcp.Tool {
+	return mcp.NewTool("cluster_health",
+		mcp.WithDescription("Compact cluster-wide health overview: node conditions, control-plane health checks, unschedulable pod count, crashlooping pods per namespace, deprecated API usage seen by the API server and pending CertificateSigningRequests"),
+		mcp.WithNumber("maxItems",
+			mcp.Description("Maximum number of entries to list in each section (default: 10)"),
+		),
+	)
+}
+
+// Handler gathers the cluster health signals. Individual checks that fail are reported under
+// "errors" so that one missing permission does not hide the rest of the report.
+func (c *ClusterHealthTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	maxItems := 10
+	if v, ok := req.Params.Arguments["maxItems"].(float64); ok && v > 0 {
+		maxItems = int(v)
+	}
+
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	result := map[string]any{}
+	var checkErrors []string
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	result["nodes"] = summarizeNodeHealth(nodes.Items, maxItems)
+
+	controlPlane, err := controlPlaneChecks(ctx, clientset)
+	if err != nil {
+		checkErrors = append(checkErrors, err.Error())
+	} else {
+		result["controlPlane"] = controlPlane
+	}
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		checkErrors = append(checkErrors, fmt.Sprintf("failed to list pods: %v", err))
+	} else {
+		unschedulable, crashLooping := podHealthCounts(pods.Items)
+		result["unschedulablePods"] = unschedulable
+		result["crashLoopingPods"] = topCounts(crashLooping, maxItems)
+	}
+
+	deprecated, err := deprecatedAPIUsage(ctx, clientset)
+	if err != nil {
+		checkErrors = append(checkErrors, err.Error())
+	} else {
+		if len(deprecated) > maxItems {
+			deprecated = deprecated[:maxItems]
+		}
+		result["deprecatedAPIs"] = deprecated
+	}
+
+	csrs, err := clientset.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		checkErrors = append(checkErrors, fmt.Sprintf("failed to list certificate signing requests: %v", err))
+	} else {
+		pending := []string{}
+		for i := range csrs.Items {
+			if csrPending(&csrs.Items[i]) {
+				pending = append(pending, csrs.Items[i].Name)
+			}
+		}
+		result["pendingCSRs"] = map[string]any{"count": len(pending), "names": capStrings(pending, maxItems)}
+	}
+
+	if len(checkErrors) > 0 {
+		result["errors"] = checkErrors
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// summarizeNodeHealth counts ready nodes and lists nodes that are not ready, cordoned or under pressure.
+func summarizeNodeHealth(nodes []corev1.Node, maxItems int) map[string]any {
+	ready := 0
+	problems := []HealthIssue{}
+	for i := range nodes {
+		node := &nodes[i]
+		var reasons []string
+		if nodeReady(node) {
+			ready++
+		} else {
+			reasons = append(reasons, "NotReady")
+		}
+		if node.Spec.Unschedulable {
+			reasons = append(reasons, "Unschedulable")
+		}
+		for _, cond := range node.Status.Conditions {
+			for _, t := range nodePressureConditions {
+				if cond.Type == t && cond.Status == corev1.ConditionTrue {
+					reasons = append(reasons, string(cond.Type))
+				}
+			}
+		}
+		if len(reasons) > 0 {
+			problems = append(problems, HealthIssue{Kind: "Node", Name: node.Name, Reason: strings.Join(reasons, ",")})
+		}
+	}
+
+	summary := map[string]any{
+		"total":    len(nodes),
+		"ready":    ready,
+		"problems": len(problems),
+	}
+	if len(problems) > maxItems {
+		problems = problems[:maxItems]
+	}
+	summary["problemNodes"] = problems
+	return summary
+}
+
+// controlPlaneChecks runs the API server's verbose readiness check and returns the status of each check.
+func controlPlaneChecks(ctx context.Context, clientset kubernetes.Interface) (map[string]any, error) {
+	body, err := clientset.Discovery().RESTClient().Get().AbsPath("/readyz").Param("verbose", "").DoRaw(ctx)
+	// /readyz answers 500 with the same verbose body when a check fails
+	if err != nil && len(body) == 0 {
+		return nil, fmt.Errorf("failed to query /readyz: %w", err)
+	}
+
+	failed := parseReadyzFailures(body)
+	return map[string]any{
+		"ready":        len(failed) == 0,
+		"failedChecks": failed,
+	}, nil
+}
+
+// parseReadyzFailures extracts the names of failed checks from verbose /readyz or /livez output,
+// where each check is reported as "[+]name ok" or "[-]name failed: reason withheld".
+func parseReadyzFailures(body []byte) []string {
+	failed := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "[-]") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(line, "[-]"), " ")
+		failed = append(failed, name)
+	}
+	return failed
+}
+
+// podHealthCounts returns the number of unschedulable pods and the crashlooping pods per namespace.
+func podHealthCounts(pods []corev1.Pod) (int, map[string]int) {
+	unschedulable := 0
+	crashLooping := map[string]int{}
+	for i := range pods {
+		pod := &pods[i]
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable {
+				unschedulable++
+			}
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+				crashLooping[pod.Namespace]++
+				break
+			}
+		}
+	}
+	return unschedulable, crashLooping
+}
+
+// topCounts returns the largest counts as a map limited to maxItems entries.
+func topCounts(counts map[string]int, maxItems int) map[string]int {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > maxItems {
+		keys = keys[:maxItems]
+	}
+	top := make(map[string]int, len(keys))
+	for _, k := range keys {
+		top[k] = counts[k]
+	}
+	return top
+}
+
+// deprecatedAPIUsage reads the apiserver_requested_deprecated_apis metric from the API server.
+func deprecatedAPIUsage(ctx context.Context, clientset kubernetes.Interface) ([]DeprecatedAPIUsage, error) {
+	body, err := clientset.Discovery().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API server metrics: %w", err)
+	}
+	return parseDeprecatedAPIMetrics(body), nil
+}
+
+// parseDeprecatedAPIMetrics parses Prometheus text lines such as
+// apiserver_requested_deprecated_apis{group="extensions",removed_release="1.22",resource="ingresses",subresource="",version="v1beta1"} 1
+func parseDeprecatedAPIMetrics(body []byte) []DeprecatedAPIUsage {
+	usages := []DeprecatedAPIUsage{}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "apiserver_requested_deprecated_apis{") {
+			continue
+		}
+		end := strings.LastIndex(line, "}")
+		if end < 0 {
+			continue
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(line[end+1:]), 64); err != nil || v == 0 {
+			continue
+		}
+		labels := parseMetricLabels(line[strings.Index(line, "{")+1 : end])
+		usages = append(usages, DeprecatedAPIUsage{
+			Group:          labels["group"],
+			Version:        labels["version"],
+			Resource:       labels["resource"],
+			RemovedRelease: labels["removed_release"],
+		})
+	}
+	return usages
+}
+
+// parseMetricLabels parses the comma-separated key="value" pairs of a Prometheus sample.
+func parseMetricLabels(s string) map[string]string {
+	labels := map[string]string{}
+	for s != "" {
+		key, rest, ok := strings.Cut(s, "=\"")
+		if !ok {
+			break
+		}
+		value, rest, ok := strings.Cut(rest, "\"")
+		if !ok {
+			break
+		}
+		labels[strings.TrimSpace(key)] = value
+		s = strings.TrimPrefix(rest, ",")
+	}
+	return labels
+}
+
+// csrPending reports whether a CertificateSigningRequest has been neither approved nor denied.
+func csrPending(csr *certificatesv1.CertificateSigningRequest) bool {
+	for _, cond := range csr.Status.Conditions {
+		switch cond.Type {
+		case certificatesv1.CertificateApproved, certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return false
+		}
+	}
+	// CSRs that were never acted upon are garbage collected after a day; ignore stale ones
+	return time.Since(csr.CreationTimestamp.Time) < 24*time.Hour
+}
+
+// capStrings returns at most n items of values.
+func capStrings(values []string, n int) []string {
+	if len(values) > n {
+		return values[:n]
+	}
+	return values
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseReadyzFailures(t *testing.T) {
+	body := []byte("[+]ping ok\n[+]log ok\n[-]etcd failed: reason withheld\n[+]poststarthook/start-informers ok\nreadyz check failed\n")
+
+	assert.Equal(t, []string{"etcd"}, parseReadyzFailures(body))
+	assert.Empty(t, parseReadyzFailures([]byte("[+]ping ok\nreadyz check passed\n")))
+}
+
+func TestParseDeprecatedAPIMetrics(t *testing.T) {
+	body := []byte(`# HELP apiserver_requested_deprecated_apis [STABLE] Gauge of deprecated APIs that have been requested
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="podsecuritypolicies",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="",removed_release="",resource="componentstatuses",subresource="",version="v1"} 0
+apiserver_request_total{code="200"} 42
+`)
+
+	usages := parseDeprecatedAPIMetrics(body)
+
+	assert.Equal(t, []DeprecatedAPIUsage{
+		{Group: "policy", Version: "v1beta1", Resource: "podsecuritypolicies", RemovedRelease: "1.25"},
+	}, usages)
+}
+
+func TestPodHealthCounts(t *testing.T) {
+	crashing := corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+		{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+		{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+	}}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "web"}, Status: crashing},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "web"}, Status: crashing},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "jobs"}, Status: crashing},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "jobs"}, Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable},
+		}}},
+	}
+
+	unschedulable, crashLooping := podHealthCounts(pods)
+
+	assert.Equal(t, 1, unschedulable)
+	assert.Equal(t, map[string]int{"web": 2, "jobs": 1}, crashLooping)
+	assert.Equal(t, map[string]int{"web": 2}, topCounts(crashLooping, 1))
+}
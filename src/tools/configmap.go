@@ -0,0 +1,293 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxShownValueLength is the number of characters of a value shown before it is truncated.
+const maxShownValueLength = 200
+
+// ConfigMapEditInput represents the input for reading or editing a ConfigMap.
+type ConfigMapEditInput struct {
+	Namespace         string            `json:"namespace"`
+	Name              string            `json:"name"`
+	Action            string            `json:"action"`
+	Data              map[string]string `json:"data,omitempty"`
+	Keys              []string          `json:"keys,omitempty"`
+	ShowValues        bool              `json:"showValues,omitempty"`
+	RestartDependents bool              `json:"restartDependents,omitempty"`
+	DryRun            bool              `json:"dryRun,omitempty"`
+}
+
+// KeyChange describes a single key-level change to a ConfigMap or Secret.
+type KeyChange struct {
+	Key      string `json:"key"`
+	Change   string `json:"change"`
+	OldValue string `json:"oldValue,omitempty"`
+	NewValue string `json:"newValue,omitempty"`
+}
+
+// ConfigMapEditTool reads ConfigMaps and edits individual keys.
+type ConfigMapEditTool struct {
+	client Client
+}
+
+// NewConfigMapEditTool creates a new ConfigMapEditTool with the provided Kubernetes client.
+func NewConfigMapEditTool(client Client) *ConfigMapEditTool {
+	return &ConfigMapEditTool{client: client}
+}
+
+// Tool returns the MCP tool definition for configmap_edit.
+func (c *ConfigMapEditTool) Tool() mcp.Tool {
+	return mcp.NewTool("configmap_edit",
+		mcp.WithDescription("Read a Kubernetes ConfigMap or update/remove individual keys, returning a key-level diff. Can restart the Deployments and StatefulSets that consume the ConfigMap afterwards."),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the ConfigMap (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the ConfigMap"),
+		),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("Action to perform: 'get', 'set' or 'remove'"),
+			mcp.Enum("get", "set", "remove"),
+		),
+		mcp.WithObject("data",
+			mcp.Description("For 'set': keys and their new string values, e.g. {\"LOG_LEVEL\": \"debug\"}"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("keys",
+			mcp.Description("For 'remove': keys to delete from the ConfigMap"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("showValues",
+			mcp.Description("For 'get': include values (truncated to 200 characters) instead of only key names (default: false)"),
+		),
+		mcp.WithBoolean("restartDependents",
+			mcp.Description("For 'set' and 'remove': rollout restart Deployments and StatefulSets in the namespace that reference this ConfigMap (default: false)"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("For 'set' and 'remove': validate the update and restarts with a server-side dry run and report the changes and workloads that would restart, without persisting anything (default: false)"),
+		),
+	)
+}
+
+// Handler reads or edits the ConfigMap.
+func (c *ConfigMapEditTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateConfigMapEditParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate configmap params: %w", err)
+	}
+
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	configMaps := clientset.CoreV1().ConfigMaps(input.Namespace)
+	cm, err := configMaps.Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap %s/%s: %w", input.Namespace, input.Name, err)
+	}
+
+	result := map[string]any{
+		"name":      input.Name,
+		"namespace": input.Namespace,
+	}
+
+	if input.Action == "get" {
+		result["data"] = describeConfigMapKeys(cm, input.ShowValues)
+		if len(cm.BinaryData) > 0 {
+			binaryKeys := make([]string, 0, len(cm.BinaryData))
+			for k := range cm.BinaryData {
+				binaryKeys = append(binaryKeys, k)
+			}
+			sort.Strings(binaryKeys)
+			result["binaryDataKeys"] = binaryKeys
+		}
+		return marshalConfigMapResult(result)
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	var changes []KeyChange
+	switch input.Action {
+	case "set":
+		changes = applyKeyUpdates(cm.Data, input.Data)
+	case "remove":
+		changes, err = applyKeyRemovals(cm.Data, input.Keys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result["changes"] = changes
+	if len(changes) == 0 {
+		result["status"] = "No changes"
+		return marshalConfigMapResult(result)
+	}
+
+	if _, err := configMaps.Update(ctx, cm, metav1.UpdateOptions{DryRun: serverDryRun(input.DryRun)}); err != nil {
+		return nil, fmt.Errorf("failed to update configmap: %w", err)
+	}
+	result["status"] = "ConfigMap updated"
+	if input.DryRun {
+		result["status"] = "Dry run: configmap not changed"
+		result["dryRun"] = true
+	}
+
+	if input.RestartDependents {
+		restarted, err := restartDependents(ctx, clientset, input.Namespace, configKindConfigMap, input.Name, input.DryRun)
+		result[restartedKey(input.DryRun)] = restarted
+		if err != nil {
+			result["restartError"] = err.Error()
+		}
+	}
+
+	return marshalConfigMapResult(result)
+}
+
+// describeConfigMapKeys returns the ConfigMap keys with (truncated) values or sizes.
+func describeConfigMapKeys(cm *corev1.ConfigMap, showValues bool) map[string]any {
+	keys := make(map[string]any, len(cm.Data))
+	for k, v := range cm.Data {
+		if showValues {
+			keys[k] = truncateValue(v)
+		} else {
+			keys[k] = fmt.Sprintf("(%d bytes)", len(v))
+		}
+	}
+	return keys
+}
+
+// applyKeyUpdates sets each key in data and returns the changes, ordered by key.
+func applyKeyUpdates(data map[string]string, updates map[string]string) []KeyChange {
+	keys := make([]string, 0, len(updates))
+	for k := range updates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var changes []KeyChange
+	for _, k := range keys {
+		newValue := updates[k]
+		oldValue, exists := data[k]
+		if exists && oldValue == newValue {
+			continue
+		}
+		change := KeyChange{Key: k, Change: "added", NewValue: truncateValue(newValue)}
+		if exists {
+			change.Change = "updated"
+			change.OldValue = truncateValue(oldValue)
+		}
+		data[k] = newValue
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// applyKeyRemovals deletes the given keys from data; every key must exist.
+func applyKeyRemovals(data map[string]string, keys []string) ([]KeyChange, error) {
+	var changes []KeyChange
+	for _, k := range keys {
+		oldValue, exists := data[k]
+		if !exists {
+			return nil, fmt.Errorf("key '%s' not found", k)
+		}
+		delete(data, k)
+		changes = append(changes, KeyChange{Key: k, Change: "removed", OldValue: truncateValue(oldValue)})
+	}
+	return changes, nil
+}
+
+// truncateValue shortens long values so responses stay readable.
+func truncateValue(v string) string {
+	if len(v) <= maxShownValueLength {
+		return v
+	}
+	return v[:maxShownValueLength] + fmt.Sprintf("... (%d bytes total)", len(v))
+}
+
+func marshalConfigMapResult(result map[string]any) (*mcp.CallToolResult, error) {
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseAndValidateConfigMapEditParams validates and parses the input parameters.
+func parseAndValidateConfigMapEditParams(args map[string]any) (*ConfigMapEditInput, error) {
+	input := &ConfigMapEditInput{}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	if name, ok := args["name"].(string); ok {
+		input.Name = name
+	}
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid configmap name: %w", err)
+	}
+
+	if action, ok := args["action"].(string); ok {
+		input.Action = strings.ToLower(action)
+	}
+
+	var err error
+	switch input.Action {
+	case "get":
+	case "set":
+		input.Data, err = stringMapArg(args, "data")
+		if err != nil {
+			return nil, err
+		}
+		if len(input.Data) == 0 {
+			return nil, errors.New("data must be provided for action set")
+		}
+	case "remove":
+		input.Keys, err = stringSliceArg(args, "keys")
+		if err != nil {
+			return nil, err
+		}
+		if len(input.Keys) == 0 {
+			return nil, errors.New("keys must be provided for action remove")
+		}
+	default:
+		return nil, errors.New("action must be one of get, set or remove")
+	}
+
+	if showValues, ok := args["showValues"].(bool); ok {
+		input.ShowValues = showValues
+	}
+	if restart, ok := args["restartDependents"].(bool); ok {
+		input.RestartDependents = restart
+	}
+	if dryRun, ok := args["dryRun"].(bool); ok {
+		input.DryRun = dryRun
+	}
+
+	return input, nil
+}
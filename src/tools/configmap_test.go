@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyKeyUpdates(t *testing.T) {
+	data := map[string]string{"LOG_LEVEL": "info", "PORT": "8080"}
+
+	changes := applyKeyUpdates(data, map[string]string{
+		"LOG_LEVEL": "debug",
+		"PORT":      "8080",
+		"FEATURE_X": "on",
+	})
+
+	assert.Equal(t, []KeyChange{
+		{Key: "FEATURE_X", Change: "added", NewValue: "on"},
+		{Key: "LOG_LEVEL", Change: "updated", OldValue: "info", NewValue: "debug"},
+	}, changes)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "PORT": "8080", "FEATURE_X": "on"}, data)
+}
+
+func TestApplyKeyRemovals(t *testing.T) {
+	data := map[string]string{"A": "1", "B": "2"}
+
+	changes, err := applyKeyRemovals(data, []string{"A"})
+	assert.NoError(t, err)
+	assert.Equal(t, []KeyChange{{Key: "A", Change: "removed", OldValue: "1"}}, changes)
+	assert.Equal(t, map[string]string{"B": "2"}, data)
+
+	_, err = applyKeyRemovals(data, []string{"missing"})
+	assert.Error(t, err)
+}
+
+func TestTruncateValue(t *testing.T) {
+	assert.Equal(t, "short", truncateValue("short"))
+
+	long := strings.Repeat("x", 250)
+	truncated := truncateValue(long)
+	assert.True(t, strings.HasPrefix(truncated, strings.Repeat("x", maxShownValueLength)))
+	assert.Contains(t, truncated, "(250 bytes total)")
+}
@@ -0,0 +1,222 @@
+// Environment variables used by the confirmation workflow:
+// Optional:
+//   REQUIRE_CONFIRMATION           - Set to "true" to require a confirmation token for high-impact tools
+//   CONFIRM_TOOLS                  - Comma-separated tool names that require confirmation, replacing the default list
+
+package tools
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// confirmationTokenTTL is how long a confirmation token can be redeemed.
+const confirmationTokenTTL = 5 * time.Minute
+
+// defaultConfirmTools are the high-impact tools that require confirmation when REQUIRE_CONFIRMATION is set.
+var defaultConfirmTools = []string{
+	"drain_node",
+	"job_control",
+	"rollout_restart",
+	"knative_rollback",
+	"helm_rollback",
+	"helm_set_values",
+	"velero_restore",
+	"secret_rollback",
+	"gcp_secret_delete",
+	"csr_manage",
+}
+
+// confirmToolsFromEnv returns the tools that require confirmation: CONFIRM_TOOLS when set, the
+// default list when REQUIRE_CONFIRMATION is true, none otherwise.
+func confirmToolsFromEnv() map[string]bool {
+	names := defaultConfirmTools
+	if v := os.Getenv("CONFIRM_TOOLS"); v != "" {
+		names = strings.Split(v, ",")
+	} else if required, _ := strconv.ParseBool(os.Getenv("REQUIRE_CONFIRMATION")); !required {
+		return nil
+	}
+	tools := map[string]bool{}
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			tools[name] = true
+		}
+	}
+	return tools
+}
+
+// confirmer issues and redeems single-use confirmation tokens. A token is an HMAC over the tool name,
+// the arguments and the expiry, so it only executes the exact call that was previewed.
+type confirmer struct {
+	key  []byte
+	now  func() time.Time
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+// newConfirmer creates a confirmer with a random key, so tokens do not survive a server restart.
+func newConfirmer() *confirmer {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key) // never fails since Go 1.24
+	return &confirmer{key: key, now: time.Now, used: map[string]time.Time{}}
+}
+
+// wrap adds the confirmationToken parameter to the tool and a handler that previews the call
+// instead of running it until a valid token is passed back. Dry runs of tools that implement dryRun
+// and read-only actions execute without a token.
+func (c *confirmer) wrap(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	_, hasDryRun := tool.InputSchema.Properties["dryRun"]
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+	for k, v := range tool.InputSchema.Properties {
+		properties[k] = v
+	}
+	properties["confirmationToken"] = map[string]any{
+		"type":        "string",
+		"description": "Token returned by a previous call to confirm and execute exactly that call; the server requires it for this tool",
+	}
+	tool.InputSchema.Properties = properties
+
+	wrapped := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.Params.Arguments
+		// dryRun is only trusted when the tool declares it; the action is classified without it.
+		if dryRun, _ := args["dryRun"].(bool); dryRun && hasDryRun {
+			return handler(ctx, req)
+		}
+		if policy.Access(req.Params.Name, withoutArgument(args, "dryRun")) == policy.AccessRead {
+			return handler(ctx, req)
+		}
+		// The token also covers the kubeconfig context, so it cannot confirm the call on another cluster,
+		// and the session, so it cannot confirm the call for another client.
+		signed := req.Params.Name
+		kubeContext := kubeContextFrom(ctx)
+		if kubeContext != "" {
+			signed += "@" + kubeContext
+		}
+		if token, _ := args["confirmationToken"].(string); token != "" {
+			if err := c.redeem(ctx, signed, args, token); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}
+
+		out := map[string]any{
+			"status":            "Confirmation required",
+			"tool":              req.Params.Name,
+			"arguments":         args,
+			"confirmationToken": c.issue(ctx, signed, args),
+			"expiresIn":         confirmationTokenTTL.String(),
+			"next":              "Show this preview to the operator and, once they approve, call the tool again with the same arguments and confirmationToken",
+		}
+		if kubeContext != "" {
+			out["context"] = kubeContext
+		}
+		if hasDryRun {
+			previewReq := req
+			previewReq.Params.Arguments = withArgument(args, "dryRun", true)
+			preview, err := handler(ctx, previewReq)
+			if err != nil {
+				out["previewError"] = err.Error()
+			} else if text := toolResultText(preview); json.Valid([]byte(text)) {
+				out["preview"] = json.RawMessage(text)
+			} else {
+				out["preview"] = text
+			}
+		}
+		result, err := json.Marshal(out)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal result: %w", err)
+		}
+		return mcp.NewToolResultText(string(result)), nil
+	}
+	return tool, wrapped
+}
+
+// issue returns a token for the call that expires after confirmationTokenTTL.
+func (c *confirmer) issue(ctx context.Context, name string, args map[string]any) string {
+	expires := c.now().Add(confirmationTokenTTL).Unix()
+	return strconv.FormatInt(expires, 10) + "." + c.sign(ctx, name, args, expires)
+}
+
+// redeem checks that the token was issued for this exact call in this session, has not expired and
+// was not used before.
+func (c *confirmer) redeem(ctx context.Context, name string, args map[string]any, token string) error {
+	expiresPart, signature, ok := strings.Cut(token, ".")
+	expires, err := strconv.ParseInt(expiresPart, 10, 64)
+	if !ok || err != nil || !hmac.Equal([]byte(signature), []byte(c.sign(ctx, name, args, expires))) {
+		return errors.New("invalid confirmationToken: it must come from a preview of this tool with the same arguments")
+	}
+	now := c.now()
+	if now.Unix() > expires {
+		return errors.New("confirmationToken has expired: call the tool without it for a new preview")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for t, exp := range c.used {
+		if now.After(exp) {
+			delete(c.used, t)
+		}
+	}
+	if _, seen := c.used[token]; seen {
+		return errors.New("confirmationToken was already used: call the tool without it for a new preview")
+	}
+	c.used[token] = time.Unix(expires, 0)
+	return nil
+}
+
+// sign computes the HMAC of the MCP session, the tool name, the arguments and the expiry. The token
+// itself and dryRun, which is false for every call that needs a token, are left out.
+func (c *confirmer) sign(ctx context.Context, name string, args map[string]any, expires int64) string {
+	// encoding/json sorts map keys, so equal arguments always encode the same way.
+	encoded, _ := json.Marshal(withoutArgument(withoutArgument(args, "confirmationToken"), "dryRun"))
+	mac := hmac.New(sha256.New, c.key)
+	fmt.Fprintf(mac, "%s\n%s\n%d\n%s", sessionID(ctx), name, expires, encoded)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// withArgument returns a copy of the arguments with one argument set.
+func withArgument(args map[string]any, name string, value any) map[string]any {
+	result := withoutArgument(args, name)
+	result[name] = value
+	return result
+}
+
+// withoutArgument returns a copy of the arguments without one argument.
+func withoutArgument(args map[string]any, name string) map[string]any {
+	result := make(map[string]any, len(args))
+	for k, v := range args {
+		if k != name {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// toolResultText joins the text content of a tool result.
+func toolResultText(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	var parts []string
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmToolsFromEnv(t *testing.T) {
+	t.Setenv("REQUIRE_CONFIRMATION", "")
+	t.Setenv("CONFIRM_TOOLS", "")
+	assert.Empty(t, confirmToolsFromEnv())
+
+	t.Setenv("REQUIRE_CONFIRMATION", "true")
+	tools := confirmToolsFromEnv()
+	assert.True(t, tools["drain_node"])
+	assert.False(t, tools["list_resources"])
+
+	t.Setenv("CONFIRM_TOOLS", "drain_node, k8s_secret")
+	assert.Equal(t, map[string]bool{"drain_node": true, "k8s_secret": true}, confirmToolsFromEnv())
+}
+
+func TestConfirmerWrap(t *testing.T) {
+	c := newConfirmer()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	var calls []map[string]any
+	tool := mcp.NewTool("drain_node", mcp.WithString("name"), mcp.WithBoolean("dryRun"))
+	tool, handler := c.wrap(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls = append(calls, req.Params.Arguments)
+		return mcp.NewToolResultText(`{"pods":["web-1"]}`), nil
+	})
+	assert.Contains(t, tool.InputSchema.Properties, "confirmationToken")
+
+	call := func(args map[string]any) (map[string]any, error) {
+		var req mcp.CallToolRequest
+		req.Params.Name = "drain_node"
+		req.Params.Arguments = args
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		_ = json.Unmarshal([]byte(toolResultText(result)), &out)
+		return out, nil
+	}
+
+	// The first call only previews through a dry run.
+	out, err := call(map[string]any{"name": "node-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Confirmation required", out["status"])
+	assert.Equal(t, map[string]any{"pods": []any{"web-1"}}, out["preview"])
+	assert.Equal(t, []map[string]any{{"name": "node-1", "dryRun": true}}, calls)
+	token := out["confirmationToken"].(string)
+
+	// The token only confirms the previewed arguments.
+	_, err = call(map[string]any{"name": "node-2", "confirmationToken": token})
+	assert.ErrorContains(t, err, "invalid confirmationToken")
+
+	_, err = call(map[string]any{"name": "node-1", "dryRun": false, "confirmationToken": token})
+	assert.NoError(t, err)
+	assert.Len(t, calls, 2)
+
+	_, err = call(map[string]any{"name": "node-1", "confirmationToken": token})
+	assert.ErrorContains(t, err, "already used")
+
+	out, _ = call(map[string]any{"name": "node-1"})
+	now = now.Add(confirmationTokenTTL + time.Second)
+	_, err = call(map[string]any{"name": "node-1", "confirmationToken": out["confirmationToken"]})
+	assert.ErrorContains(t, err, "expired")
+
+	// Dry runs need no token.
+	_, err = call(map[string]any{"name": "node-1", "dryRun": true})
+	assert.NoError(t, err)
+	assert.Len(t, calls, 4)
+}
+
+func TestConfirmerWrapKubeContext(t *testing.T) {
+	c := newConfirmer()
+	tool := mcp.NewTool("cordon_node", mcp.WithString("name"))
+	_, handler := c.wrap(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("{}"), nil
+	})
+	call := func(ctx context.Context, args map[string]any) (map[string]any, error) {
+		var req mcp.CallToolRequest
+		req.Params.Name = "cordon_node"
+		req.Params.Arguments = args
+		result, err := handler(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		_ = json.Unmarshal([]byte(toolResultText(result)), &out)
+		return out, nil
+	}
+
+	staging := withKubeContext(context.Background(), "staging")
+	out, err := call(staging, map[string]any{"name": "node-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "staging", out["context"])
+	token := out["confirmationToken"].(string)
+
+	// A token previewed on one cluster does not confirm the call on another.
+	_, err = call(withKubeContext(context.Background(), "prod"), map[string]any{"name": "node-1", "confirmationToken": token})
+	assert.ErrorContains(t, err, "invalid confirmationToken")
+	_, err = call(context.Background(), map[string]any{"name": "node-1", "confirmationToken": token})
+	assert.ErrorContains(t, err, "invalid confirmationToken")
+	_, err = call(staging, map[string]any{"name": "node-1", "confirmationToken": token})
+	assert.NoError(t, err)
+}
+
+func TestConfirmerWrapSession(t *testing.T) {
+	c := newConfirmer()
+	tool := mcp.NewTool("cordon_node", mcp.WithString("name"))
+	_, handler := c.wrap(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("{}"), nil
+	})
+	call := func(ctx context.Context, args map[string]any) (map[string]any, error) {
+		var req mcp.CallToolRequest
+		req.Params.Name = "cordon_node"
+		req.Params.Arguments = args
+		result, err := handler(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]any
+		_ = json.Unmarshal([]byte(toolResultText(result)), &out)
+		return out, nil
+	}
+
+	s := server.NewMCPServer("test", "0.0.0")
+	alice := s.WithContext(context.Background(), fakeSession{id: "a"})
+	out, err := call(alice, map[string]any{"name": "node-1"})
+	require.NoError(t, err)
+	token := out["confirmationToken"].(string)
+
+	// A token previewed in one session does not confirm the call in another.
+	_, err = call(s.WithContext(context.Background(), fakeSession{id: "b"}), map[string]any{"name": "node-1", "confirmationToken": token})
+	assert.ErrorContains(t, err, "invalid confirmationToken")
+	_, err = call(alice, map[string]any{"name": "node-1", "confirmationToken": token})
+	assert.NoError(t, err)
+}
+
+func TestConfirmerWrapReadAction(t *testing.T) {
+	c := newConfirmer()
+	called := 0
+	tool := mcp.NewTool("csr_manage", mcp.WithString("action"), mcp.WithString("name"))
+	_, handler := c.wrap(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called++
+		return mcp.NewToolResultText(`{}`), nil
+	})
+	call := func(args map[string]any) string {
+		var req mcp.CallToolRequest
+		req.Params.Name = "csr_manage"
+		req.Params.Arguments = args
+		result, err := handler(context.Background(), req)
+		assert.NoError(t, err)
+		return toolResultText(result)
+	}
+
+	// Read-only actions run without a token, write actions are previewed.
+	assert.Equal(t, "{}", call(map[string]any{"action": "list"}))
+	assert.Equal(t, 1, called)
+	assert.Contains(t, call(map[string]any{"action": "approve", "name": "csr-1"}), "Confirmation required")
+	assert.Equal(t, 1, called)
+}
+
+func TestConfirmerWrapIgnoresUndeclaredDryRun(t *testing.T) {
+	c := newConfirmer()
+	called := 0
+	tool := mcp.NewTool("velero_restore", mcp.WithString("backup"))
+	_, handler := c.wrap(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called++
+		return mcp.NewToolResultText(`{}`), nil
+	})
+
+	// velero_restore has no dryRun, so the flag must not skip the confirmation.
+	var req mcp.CallToolRequest
+	req.Params.Name = "velero_restore"
+	req.Params.Arguments = map[string]any{"backup": "nightly", "dryRun": true}
+	result, err := handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Contains(t, toolResultText(result), "Confirmation required")
+	assert.Equal(t, 0, called)
+}
+
+func TestConfirmerWrapThroughRedaction(t *testing.T) {
+	c := newConfirmer()
+	called := 0
+	tool := mcp.NewTool("drain_node", mcp.WithString("name"), mcp.WithBoolean("dryRun"))
+	_, handler := c.wrap(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called++
+		return mcp.NewToolResultText(`{"pods":["web-1"]}`), nil
+	})
+	handler = redact.Middleware(redact.OnRequest)(handler)
+
+	var req mcp.CallToolRequest
+	req.Params.Name = "drain_node"
+	req.Params.Arguments = map[string]any{"name": "node-1"}
+	result, err := handler(context.Background(), req)
+	assert.NoError(t, err)
+	var out map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(toolResultText(result)), &out))
+	token, _ := out["confirmationToken"].(string)
+	assert.NotEqual(t, redact.Marker, token)
+
+	req.Params.Arguments = map[string]any{"name": "node-1", "confirmationToken": token}
+	_, err = handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, called)
+}
@@ -0,0 +1,270 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// apiServerLatencySamples is the number of round trips timed per request type.
+	apiServerLatencySamples = 3
+	// slowAPIServerLatency is the average round trip above which the API server is reported as slow.
+	slowAPIServerLatency = time.Second
+	// defaultLeaseDuration applies to leader leases that do not record their duration.
+	defaultLeaseDuration = 15 * time.Second
+	maxEtcdEvents        = 10
+)
+
+// controlPlaneLeases are the leader election leases of the control plane components in kube-system.
+var controlPlaneLeases = []string{"kube-scheduler", "kube-controller-manager", "cloud-controller-manager"}
+
+// etcdEventMarkers identify warning events caused by etcd.
+var etcdEventMarkers = []string{"etcd", "mvcc", "database space exceeded", "leader changed", "apply request took too long"}
+
+// LatencyStats summarizes timed round trips in milliseconds.
+type LatencyStats struct {
+	MinMs int64 `json:"minMs"`
+	AvgMs int64 `json:"avgMs"`
+	MaxMs int64 `json:"maxMs"`
+}
+
+// LeaderLease is the state of a control plane component's leader election lease.
+type LeaderLease struct {
+	Component  string `json:"component"`
+	Holder     string `json:"holder,omitempty"`
+	RenewedAgo string `json:"renewedAgo,omitempty"`
+	Duration   string `json:"leaseDuration,omitempty"`
+	Fresh      bool   `json:"fresh"`
+	Visible    bool   `json:"visible"`
+}
+
+// ControlPlanePod is a self-hosted control plane pod in kube-system.
+type ControlPlanePod struct {
+	Component string `json:"component"`
+	Name      string `json:"name"`
+	Node      string `json:"node"`
+	Ready     bool   `json:"ready"`
+	Restarts  int32  `json:"restarts"`
+}
+
+// ControlPlaneStatusTool summarizes the health of the API server, leader leases and etcd.
+type ControlPlaneStatusTool struct {
+	client Client
+}
+
+// NewControlPlaneStatusTool creates a new ControlPlaneStatusTool with the provided Kubernetes client.
+func NewControlPlaneStatusTool(client Client) *ControlPlaneStatusTool {
+	return &ControlPlaneStatusTool{client: client}
+}
+
+// Tool returns the MCP tool definition for control_plane_status.
+func (c *ControlPlaneStatusTool) Tool() mcp.Tool {
+	return mcp.NewTool("control_plane_status",
+		mcp.WithDescription("Compact control plane health for managed and self-hosted clusters: API server readiness checks and round-trip latency measured from this server (a health check and an etcd-backed list), freshness of the kube-scheduler and kube-controller-manager leader leases, self-hosted control plane pods in kube-system, and recent warning events mentioning etcd"),
+	)
+}
+
+// Handler runs the checks; a check that cannot run is reported under "errors" instead of failing the call.
+func (c *ControlPlaneStatusTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	result := map[string]any{}
+	var problems, checkErrors []string
+
+	apiServer := map[string]any{}
+	if checks, err := controlPlaneChecks(ctx, clientset); err != nil {
+		checkErrors = append(checkErrors, err.Error())
+	} else {
+		apiServer["readyz"] = checks
+		if failed, _ := checks["failedChecks"].([]string); len(failed) > 0 {
+			problems = append(problems, "API server readiness checks failing: "+strings.Join(failed, ", "))
+		}
+	}
+	health, err := timeRequests(func() error {
+		return clientset.Discovery().RESTClient().Get().AbsPath("/livez").Do(ctx).Error()
+	})
+	if err != nil {
+		checkErrors = append(checkErrors, fmt.Sprintf("failed to query /livez: %v", err))
+	} else {
+		apiServer["healthLatency"] = health
+	}
+	list, err := timeRequests(func() error {
+		_, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{Limit: 1})
+		return err
+	})
+	if err != nil {
+		checkErrors = append(checkErrors, fmt.Sprintf("failed to list namespaces: %v", err))
+	} else {
+		apiServer["listLatency"] = list
+		if time.Duration(list.AvgMs)*time.Millisecond > slowAPIServerLatency {
+			problems = append(problems, fmt.Sprintf("API server lists take %dms on average: the API server or etcd is overloaded", list.AvgMs))
+		}
+	}
+	result["apiServer"] = apiServer
+
+	now := time.Now()
+	var leases []LeaderLease
+	for _, component := range controlPlaneLeases {
+		lease, err := clientset.CoordinationV1().Leases(metav1.NamespaceSystem).Get(ctx, component, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err) || apierrors.IsForbidden(err):
+			// Managed control planes often hide the leases; the cloud controller manager is optional.
+			if component != "cloud-controller-manager" {
+				leases = append(leases, LeaderLease{Component: component})
+			}
+		case err != nil:
+			checkErrors = append(checkErrors, fmt.Sprintf("failed to get lease %s: %v", component, err))
+		default:
+			status := leaderLeaseStatus(component, lease, now)
+			if !status.Fresh {
+				problems = append(problems, fmt.Sprintf("%s leader lease was last renewed %s ago (duration %s): no healthy leader", component, status.RenewedAgo, status.Duration))
+			}
+			leases = append(leases, status)
+		}
+	}
+	result["leaderLeases"] = leases
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: "tier=control-plane"})
+	if err != nil {
+		checkErrors = append(checkErrors, fmt.Sprintf("failed to list control plane pods: %v", err))
+	} else if len(pods.Items) > 0 {
+		controlPlanePods := summarizeControlPlanePods(pods.Items)
+		for _, p := range controlPlanePods {
+			if !p.Ready {
+				problems = append(problems, fmt.Sprintf("%s pod %s on %s is not ready", p.Component, p.Name, p.Node))
+			}
+		}
+		result["pods"] = controlPlanePods
+	} else {
+		result["pods"] = "none visible (managed control plane)"
+	}
+
+	etcdEvents, err := recentEtcdEvents(ctx, clientset)
+	if err != nil {
+		checkErrors = append(checkErrors, err.Error())
+	} else {
+		if len(etcdEvents) > 0 {
+			problems = append(problems, fmt.Sprintf("%d warning events mention etcd", len(etcdEvents)))
+		}
+		result["etcdEvents"] = etcdEvents
+	}
+
+	result["healthy"] = len(problems) == 0
+	result["problems"] = problems
+	if len(checkErrors) > 0 {
+		result["errors"] = checkErrors
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// timeRequests runs request apiServerLatencySamples times and returns the round-trip times.
+func timeRequests(request func() error) (*LatencyStats, error) {
+	durations := make([]time.Duration, 0, apiServerLatencySamples)
+	for range apiServerLatencySamples {
+		start := time.Now()
+		if err := request(); err != nil {
+			return nil, err
+		}
+		durations = append(durations, time.Since(start))
+	}
+	return latencyStats(durations), nil
+}
+
+// latencyStats returns the minimum, average and maximum of the durations.
+func latencyStats(durations []time.Duration) *LatencyStats {
+	if len(durations) == 0 {
+		return &LatencyStats{}
+	}
+	minimum, maximum, total := durations[0], durations[0], time.Duration(0)
+	for _, d := range durations {
+		minimum, maximum, total = min(minimum, d), max(maximum, d), total+d
+	}
+	return &LatencyStats{
+		MinMs: minimum.Milliseconds(),
+		AvgMs: (total / time.Duration(len(durations))).Milliseconds(),
+		MaxMs: maximum.Milliseconds(),
+	}
+}
+
+// leaderLeaseStatus reports the holder of a leader lease and whether it was renewed within its
+// duration; a lease that is not renewed means the component has no active leader.
+func leaderLeaseStatus(component string, lease *coordinationv1.Lease, now time.Time) LeaderLease {
+	status := LeaderLease{Component: component, Visible: true}
+	if lease.Spec.HolderIdentity != nil {
+		status.Holder = *lease.Spec.HolderIdentity
+	}
+	duration := defaultLeaseDuration
+	if lease.Spec.LeaseDurationSeconds != nil {
+		duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	status.Duration = duration.String()
+	if lease.Spec.RenewTime == nil {
+		return status
+	}
+	ago := now.Sub(lease.Spec.RenewTime.Time)
+	status.RenewedAgo = ago.Round(time.Second).String()
+	status.Fresh = ago <= duration
+	return status
+}
+
+// summarizeControlPlanePods describes the static control plane pods of a self-hosted cluster.
+func summarizeControlPlanePods(pods []corev1.Pod) []ControlPlanePod {
+	summaries := make([]ControlPlanePod, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		component := pod.Labels["component"]
+		if component == "" {
+			component = pod.Name
+		}
+		summary := ControlPlanePod{Component: component, Name: pod.Name, Node: pod.Spec.NodeName, Ready: podReady(pod)}
+		for _, cs := range pod.Status.ContainerStatuses {
+			summary.Restarts += cs.RestartCount
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// recentEtcdEvents returns the newest warning events, in any namespace, whose reason or message
+// points at etcd.
+func recentEtcdEvents(ctx context.Context, clientset kubernetes.Interface) ([]EventSummary, error) {
+	events, err := listEvents(ctx, clientset, metav1.NamespaceAll, "", "", true)
+	if err != nil {
+		return nil, err
+	}
+	return etcdRelatedEvents(events, maxEtcdEvents), nil
+}
+
+// etcdRelatedEvents keeps the first max events that mention etcd.
+func etcdRelatedEvents(events []EventSummary, max int) []EventSummary {
+	matched := []EventSummary{}
+	for _, ev := range events {
+		text := strings.ToLower(ev.Reason + " " + ev.Message)
+		for _, marker := range etcdEventMarkers {
+			if strings.Contains(text, marker) {
+				matched = append(matched, ev)
+				break
+			}
+		}
+		if len(matched) == max {
+			break
+		}
+	}
+	return matched
+}
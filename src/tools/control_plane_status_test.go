@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLatencyStats(t *testing.T) {
+	assert.Equal(t, &LatencyStats{MinMs: 10, AvgMs: 20, MaxMs: 40}, latencyStats([]time.Duration{
+		40 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond,
+	}))
+	assert.Equal(t, &LatencyStats{}, latencyStats(nil))
+}
+
+func TestLeaderLeaseStatus(t *testing.T) {
+	now := time.Now()
+	holder, duration := "master-1_4f2c", int32(15)
+	lease := func(renewed time.Duration) *coordinationv1.Lease {
+		renewTime := metav1.NewMicroTime(now.Add(-renewed))
+		return &coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &duration,
+			RenewTime:            &renewTime,
+		}}
+	}
+
+	assert.Equal(t, LeaderLease{
+		Component: "kube-scheduler", Holder: "master-1_4f2c", RenewedAgo: "2s", Duration: "15s", Fresh: true, Visible: true,
+	}, leaderLeaseStatus("kube-scheduler", lease(2*time.Second), now))
+
+	stale := leaderLeaseStatus("kube-controller-manager", lease(5*time.Minute), now)
+	assert.False(t, stale.Fresh)
+	assert.Equal(t, "5m0s", stale.RenewedAgo)
+
+	never := leaderLeaseStatus("kube-scheduler", &coordinationv1.Lease{}, now)
+	assert.False(t, never.Fresh)
+	assert.Equal(t, "15s", never.Duration)
+}
+
+func TestSummarizeControlPlanePods(t *testing.T) {
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcd-master-1", Labels: map[string]string{"component": "etcd", "tier": "control-plane"}},
+			Spec:       corev1.PodSpec{NodeName: "master-1"},
+			Status: corev1.PodStatus{
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+				ContainerStatuses: []corev1.ContainerStatus{{RestartCount: 3}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver-master-1", Labels: map[string]string{"tier": "control-plane"}},
+			Spec:       corev1.PodSpec{NodeName: "master-1"},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		},
+	}
+
+	assert.Equal(t, []ControlPlanePod{
+		{Component: "etcd", Name: "etcd-master-1", Node: "master-1", Ready: false, Restarts: 3},
+		{Component: "kube-apiserver-master-1", Name: "kube-apiserver-master-1", Node: "master-1", Ready: true},
+	}, summarizeControlPlanePods(pods))
+}
+
+func TestEtcdRelatedEvents(t *testing.T) {
+	events := []EventSummary{
+		{Type: "Warning", Reason: "Unhealthy", Message: "Liveness probe failed: etcdserver: request timed out", Object: "Pod/etcd-master-1"},
+		{Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Object: "Pod/web-1"},
+		{Type: "Warning", Reason: "FailedCreate", Message: "mvcc: database space exceeded", Object: "ReplicaSet/api-7d9"},
+		{Type: "Warning", Reason: "Unhealthy", Message: "etcd leader changed", Object: "Pod/etcd-master-2"},
+	}
+
+	matched := etcdRelatedEvents(events, 2)
+	assert.Len(t, matched, 2)
+	assert.Equal(t, "Pod/etcd-master-1", matched[0].Object)
+	assert.Equal(t, "ReplicaSet/api-7d9", matched[1].Object)
+	assert.Empty(t, etcdRelatedEvents(events[1:2], 10))
+}
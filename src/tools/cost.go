@@ -0,0 +1,343 @@
+// Environment variables used by this tool:
+// Optional:
+//   COST_CPU_HOURLY                - Price of one vCPU per hour used by cost_report (default: 0.021811)
+//   COST_MEMORY_GB_HOURLY          - Price of one GiB of memory per hour used by cost_report (default: 0.002923)
+//   COST_SPOT_RATIO                - Price of spot or preemptible capacity relative to on-demand (default: 0.35)
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Default prices are roughly the on-demand list prices of general purpose cloud VMs (GCP E2 in
+// us-central1); set the COST_* variables or the tool arguments to your own rates.
+const (
+	defaultCPUHourly      = 0.021811
+	defaultMemoryGBHourly = 0.002923
+	defaultSpotRatio      = 0.35
+	hoursPerMonth         = 730
+	defaultCostLimit      = 20
+)
+
+// spotNodeLabels mark nodes running on spot or preemptible capacity, by label and value.
+var spotNodeLabels = map[string]string{
+	karpenterCapacityTypeLabel:              "spot",
+	"eks.amazonaws.com/capacityType":        "SPOT",
+	"cloud.google.com/gke-spot":             "true",
+	"cloud.google.com/gke-preemptible":      "true",
+	"kubernetes.azure.com/scalesetpriority": "spot",
+}
+
+// CostPrices is the price table of a cost report.
+type CostPrices struct {
+	CPUHourly      float64 `json:"cpuHourly"`
+	MemoryGBHourly float64 `json:"memoryGBHourly"`
+	SpotRatio      float64 `json:"spotRatio"`
+}
+
+// CostLine is the estimated monthly cost of a namespace or workload. Cost charges the larger of
+// requests and usage; IdleCost is the part of the requests the pods did not use.
+type CostLine struct {
+	Name          string  `json:"name"`
+	Pods          int     `json:"pods"`
+	CPURequest    string  `json:"cpuRequest"`
+	MemoryRequest string  `json:"memoryRequest"`
+	CPUUsage      string  `json:"cpuUsage,omitempty"`
+	MemoryUsage   string  `json:"memoryUsage,omitempty"`
+	MonthlyCost   float64 `json:"monthlyCost"`
+	IdleCost      float64 `json:"idleMonthlyCost,omitempty"`
+
+	requests, usage resourceTotals
+	sampled         bool
+	cost, idle      float64
+	requestCost     float64
+}
+
+// CostReportInput represents the input for cost_report.
+type CostReportInput struct {
+	Namespace string     `json:"namespace,omitempty"`
+	GroupBy   string     `json:"groupBy"`
+	Prices    CostPrices `json:"prices"`
+	Limit     int        `json:"limit"`
+}
+
+// CostReportTool estimates monthly cost per namespace or workload from requests and usage.
+type CostReportTool struct {
+	client Client
+}
+
+// NewCostReportTool creates a new CostReportTool with the provided Kubernetes client.
+func NewCostReportTool(client Client) *CostReportTool {
+	return &CostReportTool{client: client}
+}
+
+// Tool returns the MCP tool definition for cost_report.
+func (c *CostReportTool) Tool() mcp.Tool {
+	return mcp.NewTool("cost_report",
+		mcp.WithDescription("Estimate the monthly cost of each namespace or workload from CPU and memory requests and live usage (metrics API) priced with a per-vCPU-hour and per-GiB-hour table, with pods on spot or preemptible nodes (Karpenter, EKS, GKE and AKS node labels) discounted. Highlights over-provisioned workloads whose requests far exceed usage, ranked by idle cost, and, cluster-wide, node capacity no pod requested. Prices default to COST_CPU_HOURLY, COST_MEMORY_GB_HOURLY and COST_SPOT_RATIO or rough cloud list prices; the result is an estimate, not a bill"),
+		mcp.WithString("namespace",
+			mcp.Description("Only report this namespace (default: all namespaces)"),
+		),
+		mcp.WithString("groupBy",
+			mcp.Description("Aggregate cost per 'namespace' or per 'workload' (default: namespace, or workload when namespace is set)"),
+			mcp.Enum("namespace", "workload"),
+		),
+		mcp.WithNumber("cpuHourly",
+			mcp.Description("Price of one vCPU per hour"),
+		),
+		mcp.WithNumber("memoryGBHourly",
+			mcp.Description("Price of one GiB of memory per hour"),
+		),
+		mcp.WithNumber("spotRatio",
+			mcp.Description("Price of spot capacity relative to on-demand, e.g. 0.35"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of cost lines and over-provisioned candidates to return (default: %d)", defaultCostLimit)),
+		),
+	)
+}
+
+// Handler prices the running pods and aggregates them.
+func (c *CostReportTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateCostReportParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate cost_report params: %w", err)
+	}
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	result := map[string]any{"prices": input.Prices, "groupBy": input.GroupBy}
+	usage := map[string]resourceTotals{}
+	if ri, err := c.client.ResourceInterface(metricsGVR("pods"), true, input.Namespace); err != nil {
+		result["metricsWarning"] = fmt.Sprintf("pod metrics unavailable: %v", err)
+	} else if metricsList, err := ri.List(ctx, metav1.ListOptions{}); err != nil {
+		result["metricsWarning"] = fmt.Sprintf("pod metrics unavailable (is metrics-server installed?): %v", err)
+	} else {
+		for i := range metricsList.Items {
+			item := &metricsList.Items[i]
+			usage[item.GetNamespace()+"/"+item.GetName()] = podMetricsUsage(item)
+		}
+	}
+
+	spot := spotNodes(nodes.Items)
+	groupOf := func(pod *corev1.Pod) string { return pod.Namespace }
+	if input.GroupBy == "workload" {
+		replicaSets := replicaSetsByKey(ctx, clientset, input.Namespace)
+		groupOf = func(pod *corev1.Pod) string {
+			kind, name := workloadOwner(pod, replicaSets)
+			return pod.Namespace + "/" + kind + "/" + name
+		}
+	}
+	lines := costLines(pods.Items, usage, spot, input.Prices, groupOf)
+
+	total, requested := 0.0, 0.0
+	for _, line := range lines {
+		total += line.cost
+		requested += line.requestCost
+	}
+	candidates := overProvisioned(lines)
+	result["totalMonthlyCost"] = roundCost(total)
+	result["lines"] = capCostLines(lines, input.Limit)
+	result["overProvisioned"] = capCostLines(candidates, input.Limit)
+	if input.Namespace == "" {
+		capacity := 0.0
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			ratio := 1.0
+			if spot[node.Name] {
+				ratio = input.Prices.SpotRatio
+			}
+			capacity += resourceCost(resourceListTotals(node.Status.Allocatable), input.Prices, ratio)
+		}
+		result["cluster"] = map[string]any{
+			"nodeMonthlyCost":        roundCost(capacity),
+			"unrequestedMonthlyCost": roundCost(max(capacity-requested, 0)),
+			"spotNodes":              len(spot),
+		}
+	}
+	result["note"] = "usage is a single metrics-server sample; estimates exclude storage, network, load balancers and control plane fees"
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// costLines prices the scheduled, unfinished pods and aggregates them by group, most expensive first.
+func costLines(pods []corev1.Pod, usage map[string]resourceTotals, spot map[string]bool, prices CostPrices, groupOf func(*corev1.Pod) string) []CostLine {
+	byGroup := map[string]*CostLine{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		name := groupOf(pod)
+		line, ok := byGroup[name]
+		if !ok {
+			line = &CostLine{Name: name}
+			byGroup[name] = line
+		}
+		ratio := 1.0
+		if spot[pod.Spec.NodeName] {
+			ratio = prices.SpotRatio
+		}
+		requests, _ := podRequestsAndLimits(pod)
+		used, sampled := usage[pod.Namespace+"/"+pod.Name]
+		billed := resourceTotals{CPU: max(requests.CPU, used.CPU), Memory: max(requests.Memory, used.Memory)}
+		line.Pods++
+		line.requests.add(requests)
+		line.cost += resourceCost(billed, prices, ratio)
+		line.requestCost += resourceCost(requests, prices, ratio)
+		if sampled {
+			line.sampled = true
+			line.usage.add(used)
+			idle := resourceTotals{CPU: max(requests.CPU-used.CPU, 0), Memory: max(requests.Memory-used.Memory, 0)}
+			line.idle += resourceCost(idle, prices, ratio)
+		}
+	}
+
+	lines := make([]CostLine, 0, len(byGroup))
+	for _, line := range byGroup {
+		line.CPURequest, line.MemoryRequest = formatCPU(line.requests.CPU), formatMemory(line.requests.Memory)
+		line.MonthlyCost = roundCost(line.cost)
+		if line.sampled {
+			line.CPUUsage, line.MemoryUsage = formatCPU(line.usage.CPU), formatMemory(line.usage.Memory)
+			line.IdleCost = roundCost(line.idle)
+		}
+		lines = append(lines, *line)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].cost != lines[j].cost {
+			return lines[i].cost > lines[j].cost
+		}
+		return lines[i].Name < lines[j].Name
+	})
+	return lines
+}
+
+// overProvisioned returns the lines whose CPU or memory requests exceed usage by overRequestRatio,
+// highest idle cost first.
+func overProvisioned(lines []CostLine) []CostLine {
+	var candidates []CostLine
+	for _, line := range lines {
+		if !line.sampled {
+			continue
+		}
+		cpu := line.requests.CPU > minCPURequest && float64(line.requests.CPU) > overRequestRatio*float64(max(line.usage.CPU, 1))
+		memory := line.requests.Memory > minMemoryRequest && float64(line.requests.Memory) > overRequestRatio*float64(max(line.usage.Memory, 1))
+		if cpu || memory {
+			candidates = append(candidates, line)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].idle > candidates[j].idle })
+	return candidates
+}
+
+// resourceCost prices CPU and memory for a month at the given fraction of the on-demand price.
+func resourceCost(t resourceTotals, prices CostPrices, ratio float64) float64 {
+	cores := float64(t.CPU) / 1000
+	gib := float64(t.Memory) / (1 << 30)
+	return (cores*prices.CPUHourly + gib*prices.MemoryGBHourly) * hoursPerMonth * ratio
+}
+
+// spotNodes returns the nodes labeled as spot or preemptible capacity.
+func spotNodes(nodes []corev1.Node) map[string]bool {
+	spot := map[string]bool{}
+	for i := range nodes {
+		for label, value := range spotNodeLabels {
+			if nodes[i].Labels[label] == value {
+				spot[nodes[i].Name] = true
+			}
+		}
+	}
+	return spot
+}
+
+// capCostLines returns at most n lines, never nil.
+func capCostLines(lines []CostLine, n int) []CostLine {
+	if len(lines) > n {
+		return lines[:n]
+	}
+	if lines == nil {
+		return []CostLine{}
+	}
+	return lines
+}
+
+// roundCost rounds to cents.
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
+
+// pricesFromEnv returns the price table configured by the COST_* variables.
+func pricesFromEnv() CostPrices {
+	prices := CostPrices{CPUHourly: defaultCPUHourly, MemoryGBHourly: defaultMemoryGBHourly, SpotRatio: defaultSpotRatio}
+	for name, target := range map[string]*float64{
+		"COST_CPU_HOURLY":       &prices.CPUHourly,
+		"COST_MEMORY_GB_HOURLY": &prices.MemoryGBHourly,
+		"COST_SPOT_RATIO":       &prices.SpotRatio,
+	} {
+		if v, err := strconv.ParseFloat(os.Getenv(name), 64); err == nil && v >= 0 {
+			*target = v
+		}
+	}
+	return prices
+}
+
+func parseAndValidateCostReportParams(args map[string]any) (*CostReportInput, error) {
+	input := &CostReportInput{Prices: pricesFromEnv(), Limit: defaultCostLimit}
+	input.Namespace, _ = args["namespace"].(string)
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	input.GroupBy, _ = args["groupBy"].(string)
+	switch input.GroupBy {
+	case "":
+		input.GroupBy = "namespace"
+		if input.Namespace != "" {
+			input.GroupBy = "workload"
+		}
+	case "namespace", "workload":
+	default:
+		return nil, fmt.Errorf("groupBy must be namespace or workload")
+	}
+	for name, target := range map[string]*float64{
+		"cpuHourly":      &input.Prices.CPUHourly,
+		"memoryGBHourly": &input.Prices.MemoryGBHourly,
+		"spotRatio":      &input.Prices.SpotRatio,
+	} {
+		if v, ok := args[name].(float64); ok {
+			if v < 0 {
+				return nil, fmt.Errorf("%s must not be negative", name)
+			}
+			*target = v
+		}
+	}
+	if limit, ok := args["limit"].(float64); ok && limit > 0 {
+		input.Limit = int(limit)
+	}
+	return input, nil
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func costPod(namespace, name, node, cpu, memory string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestCostLines(t *testing.T) {
+	prices := CostPrices{CPUHourly: 0.04, MemoryGBHourly: 0.005, SpotRatio: 0.5}
+	pending := costPod("web", "pending", "", "4", "8Gi")
+	done := costPod("web", "done", "node-a", "4", "8Gi")
+	done.Status.Phase = corev1.PodSucceeded
+	pods := []corev1.Pod{
+		costPod("web", "api", "node-a", "2", "4Gi"),
+		costPod("web", "worker", "node-spot", "2", "4Gi"),
+		costPod("batch", "job", "node-a", "500m", "1Gi"),
+		pending,
+		done,
+	}
+	usage := map[string]resourceTotals{
+		"web/api":    {CPU: 200, Memory: 1 << 30},
+		"web/worker": {CPU: 3000, Memory: 1 << 30},
+	}
+
+	lines := costLines(pods, usage, map[string]bool{"node-spot": true}, prices, func(pod *corev1.Pod) string { return pod.Namespace })
+
+	require.Len(t, lines, 2)
+	web := lines[0]
+	assert.Equal(t, "web", web.Name)
+	assert.Equal(t, 2, web.Pods)
+	assert.Equal(t, "4000m", web.CPURequest)
+	// api: (2 cores * 0.04 + 4 GiB * 0.005) * 730 = 73; worker bills 3 cores at half price: 51.1
+	assert.Equal(t, 124.1, web.MonthlyCost)
+	// api idles 1.8 cores and 3 GiB, worker 3 GiB at half price
+	assert.Equal(t, 68.99, web.IdleCost)
+	assert.Equal(t, "3200m", web.CPUUsage)
+
+	batch := lines[1]
+	assert.Equal(t, 18.25, batch.MonthlyCost)
+	assert.Empty(t, batch.CPUUsage)
+	assert.Zero(t, batch.IdleCost)
+}
+
+func TestOverProvisioned(t *testing.T) {
+	lines := []CostLine{
+		{Name: "unsampled", requests: resourceTotals{CPU: 4000, Memory: 8 << 30}},
+		{Name: "busy", sampled: true, idle: 1, requests: resourceTotals{CPU: 1000, Memory: 1 << 30}, usage: resourceTotals{CPU: 900, Memory: 900 << 20}},
+		{Name: "cpu-heavy", sampled: true, idle: 10, requests: resourceTotals{CPU: 2000, Memory: 1 << 30}, usage: resourceTotals{CPU: 100, Memory: 1 << 30}},
+		{Name: "memory-heavy", sampled: true, idle: 30, requests: resourceTotals{CPU: 100, Memory: 8 << 30}, usage: resourceTotals{CPU: 100, Memory: 1 << 30}},
+	}
+
+	candidates := overProvisioned(lines)
+
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "memory-heavy", candidates[0].Name)
+	assert.Equal(t, "cpu-heavy", candidates[1].Name)
+}
+
+func TestSpotNodes(t *testing.T) {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "karpenter", Labels: map[string]string{karpenterCapacityTypeLabel: "spot"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "eks", Labels: map[string]string{"eks.amazonaws.com/capacityType": "SPOT"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "on-demand", Labels: map[string]string{karpenterCapacityTypeLabel: "on-demand"}}},
+	}
+
+	assert.Equal(t, map[string]bool{"karpenter": true, "eks": true}, spotNodes(nodes))
+}
+
+func TestParseAndValidateCostReportParams(t *testing.T) {
+	t.Setenv("COST_CPU_HOURLY", "0.05")
+	t.Setenv("COST_SPOT_RATIO", "not-a-number")
+
+	input, err := parseAndValidateCostReportParams(map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, "namespace", input.GroupBy)
+	assert.Equal(t, CostPrices{CPUHourly: 0.05, MemoryGBHourly: defaultMemoryGBHourly, SpotRatio: defaultSpotRatio}, input.Prices)
+
+	input, err = parseAndValidateCostReportParams(map[string]any{"namespace": "web", "memoryGBHourly": 0.01, "limit": float64(5)})
+	require.NoError(t, err)
+	assert.Equal(t, "workload", input.GroupBy)
+	assert.Equal(t, 0.01, input.Prices.MemoryGBHourly)
+	assert.Equal(t, 5, input.Limit)
+
+	_, err = parseAndValidateCostReportParams(map[string]any{"groupBy": "node"})
+	assert.Error(t, err)
+	_, err = parseAndValidateCostReportParams(map[string]any{"cpuHourly": -1.0})
+	assert.Error(t, err)
+}
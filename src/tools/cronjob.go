@@ -0,0 +1,289 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CronJobControlInput represents the input for controlling a CronJob.
+type CronJobControlInput struct {
+	Namespace string `json:"namespace"`
+	CronJob   string `json:"cronJob"`
+	Action    string `json:"action"`
+	History   int    `json:"history,omitempty"`
+}
+
+// JobRun summarizes a Job created by a CronJob.
+type JobRun struct {
+	Name           string `json:"name"`
+	Outcome        string `json:"outcome"`
+	StartTime      string `json:"startTime,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+	Succeeded      int32  `json:"succeeded"`
+	Failed         int32  `json:"failed"`
+	Active         int32  `json:"active"`
+	Reason         string `json:"reason,omitempty"`
+	Manual         bool   `json:"manual,omitempty"`
+}
+
+// CronJobControlTool triggers, suspends, resumes and reports on CronJobs.
+type CronJobControlTool struct {
+	client Client
+}
+
+// NewCronJobControlTool creates a new CronJobControlTool with the provided Kubernetes client.
+func NewCronJobControlTool(client Client) *CronJobControlTool {
+	return &CronJobControlTool{client: client}
+}
+
+// Tool returns the MCP tool definition for cronjob_control.
+func (c *CronJobControlTool) Tool() mcp.Tool {
+	return mcp.NewTool("cronjob_control",
+		mcp.WithDescription("Control a Kubernetes CronJob: trigger a run now (like 'kubectl create job --from=cronjob/NAME'), suspend or resume its schedule, or report recent run outcomes"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the CronJob (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("cronJob",
+			mcp.Required(),
+			mcp.Description("Name of the CronJob"),
+		),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("Action to perform: 'trigger', 'suspend', 'resume' or 'status'"),
+			mcp.Enum("trigger", "suspend", "resume", "status"),
+		),
+		mcp.WithNumber("history",
+			mcp.Description("Number of most recent runs to include in the report (default: 5)"),
+		),
+	)
+}
+
+// Handler performs the requested CronJob action.
+func (c *CronJobControlTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateCronJobControlParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate cronjob params: %w", err)
+	}
+
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	result, err := c.run(ctx, clientset, input, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// run performs the CronJob action; now names the Job of a manual trigger.
+func (c *CronJobControlTool) run(ctx context.Context, clientset kubernetes.Interface, input *CronJobControlInput, now time.Time) (map[string]any, error) {
+	cronJobs := clientset.BatchV1().CronJobs(input.Namespace)
+	cronJob, err := cronJobs.Get(ctx, input.CronJob, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cronjob %s/%s: %w", input.Namespace, input.CronJob, err)
+	}
+
+	result := map[string]any{
+		"cronJob":   input.CronJob,
+		"namespace": input.Namespace,
+		"action":    input.Action,
+	}
+
+	switch input.Action {
+	case "trigger":
+		job, err := clientset.BatchV1().Jobs(input.Namespace).Create(ctx, jobFromCronJob(cronJob, now), metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create job from cronjob: %w", err)
+		}
+		result["status"] = "Job created from CronJob"
+		result["job"] = job.Name
+	case "suspend", "resume":
+		suspend := input.Action == "suspend"
+		patch := []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))
+		if _, err := cronJobs.Patch(ctx, input.CronJob, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to patch cronjob: %w", err)
+		}
+		result["suspend"] = suspend
+		if suspend {
+			result["status"] = "CronJob suspended"
+		} else {
+			result["status"] = "CronJob resumed"
+		}
+	case "status":
+		runs, err := listCronJobRuns(ctx, clientset, cronJob, input.History)
+		if err != nil {
+			return nil, err
+		}
+		result["schedule"] = cronJob.Spec.Schedule
+		result["suspend"] = cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend
+		if t := cronJob.Status.LastScheduleTime; t != nil {
+			result["lastScheduleTime"] = t.Format(time.RFC3339)
+		}
+		if t := cronJob.Status.LastSuccessfulTime; t != nil {
+			result["lastSuccessfulTime"] = t.Format(time.RFC3339)
+		}
+		result["activeJobs"] = len(cronJob.Status.Active)
+		result["recentRuns"] = runs
+	}
+	return result, nil
+}
+
+// jobFromCronJob builds a Job from the CronJob's jobTemplate, mirroring 'kubectl create job --from'.
+func jobFromCronJob(cronJob *batchv1.CronJob, now time.Time) *batchv1.Job {
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	isController := true
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        manualJobName(cronJob.Name, now),
+			Namespace:   cronJob.Namespace,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: batchv1.SchemeGroupVersion.String(),
+					Kind:       "CronJob",
+					Name:       cronJob.Name,
+					UID:        cronJob.UID,
+					Controller: &isController,
+				},
+			},
+		},
+		Spec: *cronJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+}
+
+// manualJobName returns a job name of the form <cronjob>-manual-<unix time>, truncated to fit the 63 character limit.
+func manualJobName(cronJobName string, now time.Time) string {
+	suffix := fmt.Sprintf("-manual-%d", now.Unix())
+	if len(cronJobName)+len(suffix) > 63 {
+		cronJobName = strings.TrimRight(cronJobName[:63-len(suffix)], "-.")
+	}
+	return cronJobName + suffix
+}
+
+// listCronJobRuns returns the most recent Jobs owned by the CronJob, newest first.
+func listCronJobRuns(ctx context.Context, clientset kubernetes.Interface, cronJob *batchv1.CronJob, limit int) ([]JobRun, error) {
+	jobs, err := clientset.BatchV1().Jobs(cronJob.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	var owned []batchv1.Job
+	for _, job := range jobs.Items {
+		for _, ref := range job.OwnerReferences {
+			if ref.Kind == "CronJob" && ref.UID == cronJob.UID {
+				owned = append(owned, job)
+				break
+			}
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[j].CreationTimestamp.Before(&owned[i].CreationTimestamp)
+	})
+	if limit > 0 && len(owned) > limit {
+		owned = owned[:limit]
+	}
+
+	runs := make([]JobRun, 0, len(owned))
+	for _, job := range owned {
+		runs = append(runs, summarizeJobRun(&job))
+	}
+	return runs, nil
+}
+
+// summarizeJobRun extracts the outcome of a Job from its status and conditions.
+func summarizeJobRun(job *batchv1.Job) JobRun {
+	run := JobRun{
+		Name:      job.Name,
+		Outcome:   "Running",
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+		Active:    job.Status.Active,
+		Manual:    job.Annotations["cronjob.kubernetes.io/instantiate"] == "manual",
+	}
+	if job.Status.StartTime != nil {
+		run.StartTime = job.Status.StartTime.Format(time.RFC3339)
+	}
+	if job.Status.CompletionTime != nil {
+		run.CompletionTime = job.Status.CompletionTime.Format(time.RFC3339)
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			run.Outcome = "Succeeded"
+		case batchv1.JobFailed:
+			run.Outcome = "Failed"
+			run.Reason = strings.TrimSpace(cond.Reason + ": " + cond.Message)
+		case batchv1.JobSuspended:
+			run.Outcome = "Suspended"
+		}
+	}
+	return run
+}
+
+// parseAndValidateCronJobControlParams validates and parses the input parameters.
+func parseAndValidateCronJobControlParams(args map[string]any) (*CronJobControlInput, error) {
+	input := &CronJobControlInput{History: 5}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	if name, ok := args["cronJob"].(string); ok {
+		input.CronJob = name
+	}
+	if input.CronJob == "" {
+		return nil, errors.New("cronJob must be provided")
+	}
+	if err := validation.ValidateResourceName(input.CronJob); err != nil {
+		return nil, fmt.Errorf("invalid cronJob name: %w", err)
+	}
+
+	if action, ok := args["action"].(string); ok {
+		input.Action = strings.ToLower(action)
+	}
+	switch input.Action {
+	case "trigger", "suspend", "resume", "status":
+	default:
+		return nil, fmt.Errorf("action must be one of trigger, suspend, resume or status")
+	}
+
+	if history, ok := args["history"].(float64); ok && history > 0 {
+		input.History = int(history)
+	}
+
+	return input, nil
+}
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func fakeCronJobClientset() *fake.Clientset {
+	return fake.NewSimpleClientset(&batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "shop", UID: "cron-uid"},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 * * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "report"}},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To(int32(2)),
+					Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyNever,
+						Containers:    []corev1.Container{{Name: "report", Image: "report:1.0"}},
+					}},
+				},
+			},
+		},
+	})
+}
+
+func TestCronJobControlTrigger(t *testing.T) {
+	clientset := fakeCronJobClientset()
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+
+	result, err := NewCronJobControlTool(nil).run(ctx, clientset, &CronJobControlInput{Namespace: "shop", CronJob: "report", Action: "trigger"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "report-manual-1700000000", result["job"])
+
+	job, err := clientset.BatchV1().Jobs("shop").Get(ctx, "report-manual-1700000000", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "report"}, job.Labels)
+	assert.Equal(t, "manual", job.Annotations["cronjob.kubernetes.io/instantiate"])
+	assert.Equal(t, "report:1.0", job.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(2), *job.Spec.BackoffLimit)
+	require.Len(t, job.OwnerReferences, 1)
+	owner := job.OwnerReferences[0]
+	assert.Equal(t, "CronJob", owner.Kind)
+	assert.Equal(t, "report", owner.Name)
+	assert.Equal(t, "cron-uid", string(owner.UID))
+	assert.True(t, *owner.Controller)
+}
+
+func TestCronJobControlSuspendResume(t *testing.T) {
+	clientset := fakeCronJobClientset()
+	ctx := context.Background()
+	tool := NewCronJobControlTool(nil)
+	suspended := func() *bool {
+		cronJob, err := clientset.BatchV1().CronJobs("shop").Get(ctx, "report", metav1.GetOptions{})
+		require.NoError(t, err)
+		return cronJob.Spec.Suspend
+	}
+
+	result, err := tool.run(ctx, clientset, &CronJobControlInput{Namespace: "shop", CronJob: "report", Action: "suspend"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "CronJob suspended", result["status"])
+	assert.Equal(t, ptr.To(true), suspended())
+
+	result, err = tool.run(ctx, clientset, &CronJobControlInput{Namespace: "shop", CronJob: "report", Action: "resume"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "CronJob resumed", result["status"])
+	assert.Equal(t, ptr.To(false), suspended())
+
+	_, err = tool.run(ctx, clientset, &CronJobControlInput{Namespace: "shop", CronJob: "missing", Action: "suspend"}, time.Now())
+	assert.ErrorContains(t, err, "failed to get cronjob shop/missing")
+}
+
+func TestManualJobName(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	assert.Equal(t, "report-manual-1700000000", manualJobName("report", now))
+
+	name := manualJobName(strings.Repeat("a", 70), now)
+	assert.Len(t, name, 63)
+	assert.True(t, strings.HasSuffix(name, "-manual-1700000000"))
+}
@@ -0,0 +1,261 @@
+package tools
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	csrApprovedReason = "ApprovedByKubernetesMCP"
+	csrDeniedReason   = "DeniedByKubernetesMCP"
+)
+
+// CSRSummary describes a CertificateSigningRequest and the certificate it asks for.
+type CSRSummary struct {
+	Name       string   `json:"name"`
+	State      string   `json:"state"`
+	Requestor  string   `json:"requestor"`
+	SignerName string   `json:"signerName"`
+	Usages     []string `json:"usages,omitempty"`
+	Subject    string   `json:"subject,omitempty"`
+	DNSNames   []string `json:"dnsNames,omitempty"`
+	IPs        []string `json:"ipAddresses,omitempty"`
+	Age        string   `json:"age"`
+}
+
+// CSRManageInput represents the input for csr_manage.
+type CSRManageInput struct {
+	Action  string `json:"action"`
+	Name    string `json:"name,omitempty"`
+	All     bool   `json:"all,omitempty"`
+	Message string `json:"message,omitempty"`
+	DryRun  bool   `json:"dryRun,omitempty"`
+}
+
+// CSRManageTool lists CertificateSigningRequests and approves or denies pending ones.
+type CSRManageTool struct {
+	client Client
+}
+
+// NewCSRManageTool creates a new CSRManageTool with the provided Kubernetes client.
+func NewCSRManageTool(client Client) *CSRManageTool {
+	return &CSRManageTool{client: client}
+}
+
+// Tool returns the MCP tool definition for csr_manage.
+func (c *CSRManageTool) Tool() mcp.Tool {
+	return mcp.NewTool("csr_manage",
+		mcp.WithDescription("Manage CertificateSigningRequests, e.g. kubelet bootstrap and serving certificates: 'list' shows pending CSRs with requestor, signer, usages, requested subject and SANs, and age; 'approve' and 'deny' act on one pending CSR. Review the requestor and SANs before approving: an approved CSR is signed into a cluster credential"),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("Action to perform: 'list', 'approve' or 'deny'"),
+			mcp.Enum("list", "approve", "deny"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the CertificateSigningRequest (required for 'approve' and 'deny')"),
+		),
+		mcp.WithBoolean("all",
+			mcp.Description("For 'list': include approved, denied and failed CSRs, not only pending ones (default: false)"),
+		),
+		mcp.WithString("message",
+			mcp.Description("For 'approve' and 'deny': message recorded on the approval condition"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("For 'approve' and 'deny': show the CSR that would be acted on without changing it (default: false)"),
+		),
+	)
+}
+
+// Handler performs the requested CSR action.
+func (c *CSRManageTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateCSRManageParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate csr params: %w", err)
+	}
+
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	var result map[string]any
+	switch input.Action {
+	case "list":
+		result, err = c.listCSRs(ctx, clientset, input)
+	case "approve", "deny":
+		result, err = c.decideCSR(ctx, clientset, input)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// listCSRs returns the pending CSRs, or all of them, oldest first.
+func (c *CSRManageTool) listCSRs(ctx context.Context, clientset kubernetes.Interface, input *CSRManageInput) (map[string]any, error) {
+	csrs, err := clientset.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificate signing requests: %w", err)
+	}
+	sort.Slice(csrs.Items, func(i, j int) bool {
+		return csrs.Items[i].CreationTimestamp.Before(&csrs.Items[j].CreationTimestamp)
+	})
+
+	now := time.Now()
+	summaries := []CSRSummary{}
+	pending := 0
+	for i := range csrs.Items {
+		summary := summarizeCSR(&csrs.Items[i], now)
+		if summary.State == "Pending" {
+			pending++
+		} else if !input.All {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	return map[string]any{
+		"pending": pending,
+		"csrs":    summaries,
+	}, nil
+}
+
+// decideCSR approves or denies a pending CSR.
+func (c *CSRManageTool) decideCSR(ctx context.Context, clientset kubernetes.Interface, input *CSRManageInput) (map[string]any, error) {
+	csr, err := clientset.CertificatesV1().CertificateSigningRequests().Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate signing request %s: %w", input.Name, err)
+	}
+	summary := summarizeCSR(csr, time.Now())
+	if summary.State != "Pending" {
+		return nil, fmt.Errorf("certificate signing request %s is already %s", input.Name, strings.ToLower(summary.State))
+	}
+
+	result := map[string]any{
+		"csr":    summary,
+		"action": input.Action,
+		"dryRun": input.DryRun,
+	}
+	if input.DryRun {
+		result["status"] = fmt.Sprintf("Dry run: certificate signing request would be %s", decidedState(input.Action))
+		return result, nil
+	}
+
+	csr.Status.Conditions = append(csr.Status.Conditions, csrDecisionCondition(input.Action, input.Message, metav1.Now()))
+	if _, err := clientset.CertificatesV1().CertificateSigningRequests().UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to %s certificate signing request %s: %w", input.Action, input.Name, err)
+	}
+	result["status"] = "Certificate signing request " + decidedState(input.Action)
+	return result, nil
+}
+
+// decidedState returns the past tense of an approve or deny action.
+func decidedState(action string) string {
+	if action == "approve" {
+		return "approved"
+	}
+	return "denied"
+}
+
+// csrDecisionCondition returns the Approved or Denied condition recorded by an approve or deny action.
+func csrDecisionCondition(action, message string, now metav1.Time) certificatesv1.CertificateSigningRequestCondition {
+	cond := certificatesv1.CertificateSigningRequestCondition{
+		Type:           certificatesv1.CertificateApproved,
+		Status:         corev1.ConditionTrue,
+		Reason:         csrApprovedReason,
+		Message:        message,
+		LastUpdateTime: now,
+	}
+	if action == "deny" {
+		cond.Type, cond.Reason = certificatesv1.CertificateDenied, csrDeniedReason
+	}
+	if cond.Message == "" {
+		cond.Message = "Certificate signing request " + decidedState(action) + " through kubernetes-mcp"
+	}
+	return cond
+}
+
+// csrState returns Pending, Approved, Issued, Denied or Failed.
+func csrState(csr *certificatesv1.CertificateSigningRequest) string {
+	state := "Pending"
+	for _, cond := range csr.Status.Conditions {
+		switch cond.Type {
+		case certificatesv1.CertificateDenied:
+			return "Denied"
+		case certificatesv1.CertificateFailed:
+			return "Failed"
+		case certificatesv1.CertificateApproved:
+			state = "Approved"
+		}
+	}
+	if state == "Approved" && len(csr.Status.Certificate) > 0 {
+		return "Issued"
+	}
+	return state
+}
+
+// summarizeCSR describes a CSR, decoding the subject and SANs of its PEM-encoded request.
+func summarizeCSR(csr *certificatesv1.CertificateSigningRequest, now time.Time) CSRSummary {
+	summary := CSRSummary{
+		Name:       csr.Name,
+		State:      csrState(csr),
+		Requestor:  csr.Spec.Username,
+		SignerName: csr.Spec.SignerName,
+		Age:        now.Sub(csr.CreationTimestamp.Time).Round(time.Second).String(),
+	}
+	for _, usage := range csr.Spec.Usages {
+		summary.Usages = append(summary.Usages, string(usage))
+	}
+	if block, _ := pem.Decode(csr.Spec.Request); block != nil {
+		if request, err := x509.ParseCertificateRequest(block.Bytes); err == nil {
+			summary.Subject = request.Subject.String()
+			summary.DNSNames = request.DNSNames
+			for _, ip := range request.IPAddresses {
+				summary.IPs = append(summary.IPs, ip.String())
+			}
+		}
+	}
+	return summary
+}
+
+func parseAndValidateCSRManageParams(args map[string]any) (*CSRManageInput, error) {
+	input := &CSRManageInput{}
+	if action, ok := args["action"].(string); ok {
+		input.Action = strings.ToLower(action)
+	}
+	switch input.Action {
+	case "approve", "deny":
+		input.Name, _ = args["name"].(string)
+		if input.Name == "" {
+			return nil, fmt.Errorf("name must be provided for action %s", input.Action)
+		}
+		if err := validation.ValidateResourceName(input.Name); err != nil {
+			return nil, fmt.Errorf("invalid name: %w", err)
+		}
+	case "list":
+	default:
+		return nil, errors.New("action must be one of list, approve or deny")
+	}
+	input.All, _ = args["all"].(bool)
+	input.Message, _ = args["message"].(string)
+	input.DryRun, _ = args["dryRun"].(bool)
+	return input, nil
+}
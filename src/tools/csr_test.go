@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSummarizeCSR(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:     pkix.Name{CommonName: "system:node:node-1", Organization: []string{"system:nodes"}},
+		DNSNames:    []string{"node-1"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.5")},
+	}, key)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "csr-8x2kq", CreationTimestamp: metav1.NewTime(now.Add(-90 * time.Second))},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+			SignerName: certificatesv1.KubeletServingSignerName,
+			Username:   "system:node:node-1",
+			Usages:     []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth},
+		},
+	}
+
+	assert.Equal(t, CSRSummary{
+		Name:       "csr-8x2kq",
+		State:      "Pending",
+		Requestor:  "system:node:node-1",
+		SignerName: "kubernetes.io/kubelet-serving",
+		Usages:     []string{"digital signature", "server auth"},
+		Subject:    "CN=system:node:node-1,O=system:nodes",
+		DNSNames:   []string{"node-1"},
+		IPs:        []string{"10.0.0.5"},
+		Age:        "1m30s",
+	}, summarizeCSR(csr, now))
+}
+
+func TestCSRState(t *testing.T) {
+	csr := func(certificate []byte, types ...certificatesv1.RequestConditionType) *certificatesv1.CertificateSigningRequest {
+		c := &certificatesv1.CertificateSigningRequest{Status: certificatesv1.CertificateSigningRequestStatus{Certificate: certificate}}
+		for _, t := range types {
+			c.Status.Conditions = append(c.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{Type: t, Status: corev1.ConditionTrue})
+		}
+		return c
+	}
+
+	assert.Equal(t, "Pending", csrState(csr(nil)))
+	assert.Equal(t, "Approved", csrState(csr(nil, certificatesv1.CertificateApproved)))
+	assert.Equal(t, "Issued", csrState(csr([]byte("cert"), certificatesv1.CertificateApproved)))
+	assert.Equal(t, "Denied", csrState(csr(nil, certificatesv1.CertificateDenied)))
+	assert.Equal(t, "Failed", csrState(csr(nil, certificatesv1.CertificateApproved, certificatesv1.CertificateFailed)))
+}
+
+func TestCSRDecisionCondition(t *testing.T) {
+	now := metav1.Now()
+	approved := csrDecisionCondition("approve", "", now)
+	assert.Equal(t, certificatesv1.CertificateApproved, approved.Type)
+	assert.Equal(t, corev1.ConditionTrue, approved.Status)
+	assert.Equal(t, csrApprovedReason, approved.Reason)
+	assert.Equal(t, "Certificate signing request approved through kubernetes-mcp", approved.Message)
+
+	denied := csrDecisionCondition("deny", "unknown node", now)
+	assert.Equal(t, certificatesv1.CertificateDenied, denied.Type)
+	assert.Equal(t, csrDeniedReason, denied.Reason)
+	assert.Equal(t, "unknown node", denied.Message)
+}
+
+func TestParseAndValidateCSRManageParams(t *testing.T) {
+	input, err := parseAndValidateCSRManageParams(map[string]any{"action": "list", "all": true})
+	assert.NoError(t, err)
+	assert.True(t, input.All)
+
+	_, err = parseAndValidateCSRManageParams(map[string]any{"action": "approve"})
+	assert.ErrorContains(t, err, "name must be provided")
+
+	_, err = parseAndValidateCSRManageParams(map[string]any{"action": "sign", "name": "csr-1"})
+	assert.ErrorContains(t, err, "action must be one of")
+}
@@ -0,0 +1,304 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// daemonSetTolerations are added to every DaemonSet pod by the DaemonSet controller, so these
+// taints never keep a DaemonSet off a node.
+var daemonSetTolerations = []corev1.Toleration{
+	{Key: corev1.TaintNodeNotReady, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeUnreachable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeDiskPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeMemoryPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodePIDPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeUnschedulable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// pressureConditions are node conditions that make the kubelet reject or evict pods.
+var pressureConditions = []corev1.NodeConditionType{corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure}
+
+// DaemonSetGap is a node that should run a pod of the DaemonSet but has no ready one.
+type DaemonSetGap struct {
+	Node    string `json:"node"`
+	Pod     string `json:"pod,omitempty"`
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+}
+
+// DaemonSetCoverage describes which nodes run a DaemonSet, which are excluded and which are missing it.
+type DaemonSetCoverage struct {
+	Namespace     string             `json:"namespace"`
+	Name          string             `json:"name"`
+	Desired       int32              `json:"desired"`
+	Ready         int32              `json:"ready"`
+	EligibleNodes int                `json:"eligibleNodes"`
+	ReadyNodes    int                `json:"readyNodes"`
+	Missing       []DaemonSetGap     `json:"missing,omitempty"`
+	Excluded      []SchedulingReason `json:"excluded,omitempty"`
+}
+
+// DaemonSetCoverageInput represents the input for daemonset_coverage.
+type DaemonSetCoverageInput struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// DaemonSetCoverageTool reports per DaemonSet the nodes that lack a running pod and why.
+type DaemonSetCoverageTool struct {
+	client Client
+}
+
+// NewDaemonSetCoverageTool creates a new DaemonSetCoverageTool with the provided Kubernetes client.
+func NewDaemonSetCoverageTool(client Client) *DaemonSetCoverageTool {
+	return &DaemonSetCoverageTool{client: client}
+}
+
+// Tool returns the MCP tool definition for daemonset_coverage.
+func (d *DaemonSetCoverageTool) Tool() mcp.Tool {
+	return mcp.NewTool("daemonset_coverage",
+		mcp.WithDescription("Report, per DaemonSet, which nodes lack a ready pod and why: the pod is crashing or pending, the node is not ready, under memory, disk or PID pressure, or has too little free CPU or memory. Nodes the DaemonSet is not meant to run on, because of its nodeSelector, node affinity or an untolerated taint, are listed separately, grouped by reason"),
+		mcp.WithString("namespace",
+			mcp.Description("Only check DaemonSets in this namespace (default: all namespaces)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Only check the DaemonSet with this name; requires namespace"),
+		),
+	)
+}
+
+// Handler evaluates every DaemonSet against every node.
+func (d *DaemonSetCoverageTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateDaemonSetCoverageParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate daemonset_coverage params: %w", err)
+	}
+	clientset, err := d.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	var daemonSets []appsv1.DaemonSet
+	if input.Name != "" {
+		ds, err := clientset.AppsV1().DaemonSets(input.Namespace).Get(ctx, input.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get DaemonSet %s/%s: %w", input.Namespace, input.Name, err)
+		}
+		daemonSets = []appsv1.DaemonSet{*ds}
+	} else {
+		list, err := clientset.AppsV1().DaemonSets(input.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list DaemonSets: %w", err)
+		}
+		daemonSets = list.Items
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	requested, podCounts, err := requestsByNode(ctx, clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	coverage := make([]DaemonSetCoverage, 0, len(daemonSets))
+	incomplete := 0
+	for i := range daemonSets {
+		c := daemonSetCoverage(&daemonSets[i], nodes.Items, pods.Items, requested, podCounts, now)
+		if len(c.Missing) > 0 {
+			incomplete++
+		}
+		coverage = append(coverage, c)
+	}
+	// DaemonSets missing from nodes come first.
+	sort.SliceStable(coverage, func(i, j int) bool { return len(coverage[i].Missing) > len(coverage[j].Missing) })
+
+	out, err := json.Marshal(map[string]any{
+		"nodes":      len(nodes.Items),
+		"daemonSets": len(coverage),
+		"incomplete": incomplete,
+		"coverage":   coverage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// daemonSetCoverage sorts the nodes into those the DaemonSet excludes, those running a ready pod
+// and those missing one, with the reason.
+func daemonSetCoverage(ds *appsv1.DaemonSet, nodes []corev1.Node, pods []corev1.Pod, requested map[string]resourceTotals, podCounts map[string]int, now time.Time) DaemonSetCoverage {
+	coverage := DaemonSetCoverage{
+		Namespace: ds.Namespace,
+		Name:      ds.Name,
+		Desired:   ds.Status.DesiredNumberScheduled,
+		Ready:     ds.Status.NumberReady,
+	}
+
+	template := &corev1.Pod{Spec: *ds.Spec.Template.Spec.DeepCopy()}
+	template.Spec.Tolerations = append(template.Spec.Tolerations, daemonSetTolerations...)
+	podRequests, _ := podRequestsAndLimits(template)
+
+	podsByNode := map[string][]*corev1.Pod{}
+	for i := range pods {
+		pod := &pods[i]
+		if owner := metav1.GetControllerOf(pod); owner == nil || owner.UID != ds.UID {
+			continue
+		}
+		if node := daemonSetPodNode(pod); node != "" {
+			podsByNode[node] = append(podsByNode[node], pod)
+		}
+	}
+
+	excluded := map[string]*SchedulingReason{}
+	var order []string
+	for i := range nodes {
+		node := &nodes[i]
+		if reason := daemonSetExclusion(template, node); reason != "" {
+			entry, ok := excluded[reason]
+			if !ok {
+				entry = &SchedulingReason{Reason: reason}
+				excluded[reason] = entry
+				order = append(order, reason)
+			}
+			entry.Nodes++
+			if len(entry.Sample) < 3 {
+				entry.Sample = append(entry.Sample, node.Name)
+			}
+			continue
+		}
+
+		coverage.EligibleNodes++
+		gap := daemonSetGap(node, podsByNode[node.Name], podRequests, requested[node.Name], podCounts[node.Name], now)
+		if gap == nil {
+			coverage.ReadyNodes++
+			continue
+		}
+		coverage.Missing = append(coverage.Missing, *gap)
+	}
+	for _, reason := range order {
+		coverage.Excluded = append(coverage.Excluded, *excluded[reason])
+	}
+	sort.SliceStable(coverage.Excluded, func(i, j int) bool { return coverage.Excluded[i].Nodes > coverage.Excluded[j].Nodes })
+	return coverage
+}
+
+// daemonSetExclusion returns why the DaemonSet is not meant to run on the node, or "" when it is.
+func daemonSetExclusion(template *corev1.Pod, node *corev1.Node) string {
+	if !nodeMatchesPodAffinity(template, node) {
+		return "node(s) didn't match the DaemonSet's node affinity/selector"
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !podToleratesTaint(template, taint) {
+			return fmt.Sprintf("node(s) had untolerated taint {%s: %s}", taint.Key, taint.Value)
+		}
+	}
+	return ""
+}
+
+// daemonSetGap explains why an eligible node has no ready pod of the DaemonSet, or returns nil
+// when it has one.
+func daemonSetGap(node *corev1.Node, pods []*corev1.Pod, podRequests, nodeRequested resourceTotals, nodePods int, now time.Time) *DaemonSetGap {
+	for _, pod := range pods {
+		if podReady(pod) {
+			return nil
+		}
+	}
+	gap := &DaemonSetGap{Node: node.Name}
+	if len(pods) > 0 {
+		pod := pods[0]
+		gap.Pod = pod.Name
+		gap.Reason, gap.Message = podProblem(pod, now)
+		if gap.Reason != "" {
+			return gap
+		}
+		gap.Reason, gap.Message = "NotReady", fmt.Sprintf("pod is %s and not ready yet", pod.Status.Phase)
+	}
+
+	if !nodeReady(node) {
+		gap.Reason, gap.Message = "NodeNotReady", "the node is not ready"
+		return gap
+	}
+	for _, cond := range node.Status.Conditions {
+		for _, pressure := range pressureConditions {
+			if cond.Type == pressure && cond.Status == corev1.ConditionTrue {
+				gap.Reason, gap.Message = string(pressure), cond.Message
+				return gap
+			}
+		}
+	}
+	if len(pods) > 0 {
+		return gap
+	}
+
+	allocatable := resourceListTotals(node.Status.Allocatable)
+	switch {
+	case podRequests.CPU > 0 && nodeRequested.CPU+podRequests.CPU > allocatable.CPU:
+		gap.Reason, gap.Message = "InsufficientCPU", fmt.Sprintf("requests %s, %s of %s allocatable is already requested", formatCPU(podRequests.CPU), formatCPU(nodeRequested.CPU), formatCPU(allocatable.CPU))
+	case podRequests.Memory > 0 && nodeRequested.Memory+podRequests.Memory > allocatable.Memory:
+		gap.Reason, gap.Message = "InsufficientMemory", fmt.Sprintf("requests %s, %s of %s allocatable is already requested", formatMemory(podRequests.Memory), formatMemory(nodeRequested.Memory), formatMemory(allocatable.Memory))
+	default:
+		if q, ok := node.Status.Allocatable[corev1.ResourcePods]; ok && int64(nodePods) >= q.Value() {
+			gap.Reason, gap.Message = "TooManyPods", fmt.Sprintf("the node already runs %d pods", nodePods)
+		} else {
+			gap.Reason, gap.Message = "NoPod", "no pod was created for the node; check the DaemonSet's events and rollout"
+		}
+	}
+	return gap
+}
+
+// daemonSetPodNode returns the node a DaemonSet pod runs on or, before it is bound, the node the
+// controller pinned it to with a metadata.name node affinity.
+func daemonSetPodNode(pod *corev1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil || pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == "metadata.name" && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 {
+				return field.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
+func parseAndValidateDaemonSetCoverageParams(args map[string]any) (*DaemonSetCoverageInput, error) {
+	input := &DaemonSetCoverageInput{}
+	input.Namespace, _ = args["namespace"].(string)
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	input.Name, _ = args["name"].(string)
+	if input.Name != "" {
+		if input.Namespace == "" {
+			return nil, fmt.Errorf("namespace is required with name")
+		}
+		if err := validation.ValidateResourceName(input.Name); err != nil {
+			return nil, fmt.Errorf("invalid name: %w", err)
+		}
+	}
+	return input, nil
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDaemonSetCoverage(t *testing.T) {
+	now := time.Now()
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-agent", Namespace: "monitoring", UID: types.UID("ds-uid")},
+		Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			Containers: []corev1.Container{{Name: "agent", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("200m"),
+			}}}},
+		}}},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 5, NumberReady: 1},
+	}
+	ready := corev1.NodeStatus{
+		Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourcePods: resource.MustParse("110")},
+	}
+	node := func(name string, mutate func(*corev1.Node)) corev1.Node {
+		n := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"kubernetes.io/os": "linux"}}, Status: *ready.DeepCopy()}
+		if mutate != nil {
+			mutate(&n)
+		}
+		return n
+	}
+	nodes := []corev1.Node{
+		node("ok", nil),
+		node("crashing", nil),
+		node("full", nil),
+		node("pressure", func(n *corev1.Node) {
+			n.Status.Conditions = append(n.Status.Conditions, corev1.NodeCondition{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue, Message: "disk full"})
+		}),
+		node("cordoned", func(n *corev1.Node) {
+			n.Spec.Unschedulable = true
+			n.Spec.Taints = []corev1.Taint{{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}}
+		}),
+		node("gpu", func(n *corev1.Node) {
+			n.Spec.Taints = []corev1.Taint{{Key: "nvidia.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+		}),
+		node("windows", func(n *corev1.Node) { n.Labels["kubernetes.io/os"] = "windows" }),
+	}
+
+	controller := true
+	owned := metav1.OwnerReference{UID: ds.UID, Controller: &controller}
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-agent-a", OwnerReferences: []metav1.OwnerReference{owned}},
+			Spec:       corev1.PodSpec{NodeName: "ok"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-agent-b", OwnerReferences: []metav1.OwnerReference{owned}},
+			Spec:       corev1.PodSpec{NodeName: "crashing"},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "agent", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off"}},
+			}}},
+		},
+		// A pod of another DaemonSet on the full node.
+		{ObjectMeta: metav1.ObjectMeta{Name: "other", OwnerReferences: []metav1.OwnerReference{{UID: "other", Controller: &controller}}}, Spec: corev1.PodSpec{NodeName: "full"}},
+	}
+	requested := map[string]resourceTotals{"full": {CPU: 1900}}
+
+	coverage := daemonSetCoverage(ds, nodes, pods, requested, map[string]int{}, now)
+	assert.Equal(t, 5, coverage.EligibleNodes)
+	assert.Equal(t, 1, coverage.ReadyNodes)
+
+	reasons := map[string]string{}
+	for _, gap := range coverage.Missing {
+		reasons[gap.Node] = gap.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"crashing": "CrashLoopBackOff",
+		"full":     "InsufficientCPU",
+		"pressure": "DiskPressure",
+		"cordoned": "NoPod",
+	}, reasons)
+	assert.Equal(t, []SchedulingReason{
+		{Reason: "node(s) had untolerated taint {nvidia.com/gpu: true}", Nodes: 1, Sample: []string{"gpu"}},
+		{Reason: "node(s) didn't match the DaemonSet's node affinity/selector", Nodes: 1, Sample: []string{"windows"}},
+	}, coverage.Excluded)
+}
+
+func TestDaemonSetPodNode(t *testing.T) {
+	assert.Equal(t, "node-1", daemonSetPodNode(&corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-1"}}))
+
+	pending := &corev1.Pod{Spec: corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-2"}}},
+		}}},
+	}}}}
+	assert.Equal(t, "node-2", daemonSetPodNode(pending))
+	assert.Equal(t, "", daemonSetPodNode(&corev1.Pod{}))
+}
+
+func TestParseAndValidateDaemonSetCoverageParams(t *testing.T) {
+	_, err := parseAndValidateDaemonSetCoverageParams(map[string]any{"name": "agent"})
+	assert.EqualError(t, err, "namespace is required with name")
+
+	input, err := parseAndValidateDaemonSetCoverageParams(map[string]any{"namespace": "monitoring", "name": "agent"})
+	assert.NoError(t, err)
+	assert.Equal(t, &DaemonSetCoverageInput{Namespace: "monitoring", Name: "agent"}, input)
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Kinds of configuration objects whose consumers can be restarted.
+const (
+	configKindConfigMap = "ConfigMap"
+	configKindSecret    = "Secret"
+)
+
+// restartDependents rollout restarts every Deployment and StatefulSet in the namespace whose
+// pod template references the given ConfigMap or Secret, returning "Kind/name" for each one. The
+// change cause names the ConfigMap or Secret.
+// With dryRun the restarts are only validated by the API server.
+func restartDependents(ctx context.Context, clientset kubernetes.Interface, namespace, configKind, name string, dryRun bool) ([]string, error) {
+	restarted := []string{}
+	patch := restartPatch(time.Now(), changeCause(ctx, "restart", configKind+" "+name+" changed"))
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return restarted, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, dep := range deployments.Items {
+		if !podSpecReferences(&dep.Spec.Template.Spec, configKind, name) {
+			continue
+		}
+		if _, err := clientset.AppsV1().Deployments(namespace).Patch(ctx, dep.Name, types.MergePatchType, patch, metav1.PatchOptions{DryRun: serverDryRun(dryRun)}); err != nil {
+			return restarted, fmt.Errorf("failed to restart deployment %s: %w", dep.Name, err)
+		}
+		restarted = append(restarted, "Deployment/"+dep.Name)
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return restarted, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, sts := range statefulSets.Items {
+		if !podSpecReferences(&sts.Spec.Template.Spec, configKind, name) {
+			continue
+		}
+		if _, err := clientset.AppsV1().StatefulSets(namespace).Patch(ctx, sts.Name, types.MergePatchType, patch, metav1.PatchOptions{DryRun: serverDryRun(dryRun)}); err != nil {
+			return restarted, fmt.Errorf("failed to restart statefulset %s: %w", sts.Name, err)
+		}
+		restarted = append(restarted, "StatefulSet/"+sts.Name)
+	}
+
+	return restarted, nil
+}
+
+// podSpecReferences reports whether a pod spec uses the ConfigMap or Secret via envFrom, valueFrom or volumes.
+func podSpecReferences(spec *corev1.PodSpec, configKind, name string) bool {
+	containers := append([]corev1.Container{}, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	for _, c := range containers {
+		for _, ef := range c.EnvFrom {
+			switch configKind {
+			case configKindConfigMap:
+				if ef.ConfigMapRef != nil && ef.ConfigMapRef.Name == name {
+					return true
+				}
+			case configKindSecret:
+				if ef.SecretRef != nil && ef.SecretRef.Name == name {
+					return true
+				}
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			switch configKind {
+			case configKindConfigMap:
+				if env.ValueFrom.ConfigMapKeyRef != nil && env.ValueFrom.ConfigMapKeyRef.Name == name {
+					return true
+				}
+			case configKindSecret:
+				if env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == name {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, v := range spec.Volumes {
+		switch configKind {
+		case configKindConfigMap:
+			if v.ConfigMap != nil && v.ConfigMap.Name == name {
+				return true
+			}
+		case configKindSecret:
+			if v.Secret != nil && v.Secret.SecretName == name {
+				return true
+			}
+		}
+		if v.Projected == nil {
+			continue
+		}
+		for _, src := range v.Projected.Sources {
+			if configKind == configKindConfigMap && src.ConfigMap != nil && src.ConfigMap.Name == name {
+				return true
+			}
+			if configKind == configKindSecret && src.Secret != nil && src.Secret.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
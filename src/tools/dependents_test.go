@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodSpecReferences(t *testing.T) {
+	testCases := []struct {
+		name       string
+		spec       corev1.PodSpec
+		configKind string
+		expected   bool
+	}{
+		{
+			name: "ConfigMapEnvFrom",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{
+				EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}}},
+			}}},
+			configKind: configKindConfigMap,
+			expected:   true,
+		},
+		{
+			name: "ConfigMapValueFrom",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Env: []corev1.EnvVar{{Name: "X", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}, Key: "x"}}}},
+			}}},
+			configKind: configKindConfigMap,
+			expected:   true,
+		},
+		{
+			name: "ConfigMapVolume",
+			spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "cfg",
+				VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}},
+			}}},
+			configKind: configKindConfigMap,
+			expected:   true,
+		},
+		{
+			name: "OtherConfigMap",
+			spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "cfg",
+				VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "other"}}},
+			}}},
+			configKind: configKindConfigMap,
+			expected:   false,
+		},
+		{
+			name: "SecretInitContainerEnvFrom",
+			spec: corev1.PodSpec{InitContainers: []corev1.Container{{
+				EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}}},
+			}}},
+			configKind: configKindSecret,
+			expected:   true,
+		},
+		{
+			name: "SecretProjectedVolume",
+			spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name: "creds",
+				VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+					{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}},
+				}}},
+			}}},
+			configKind: configKindSecret,
+			expected:   true,
+		},
+		{
+			name: "SecretNameButConfigMapKind",
+			spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "creds",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "app-config"}},
+			}}},
+			configKind: configKindConfigMap,
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, podSpecReferences(&tc.spec, tc.configKind, "app-config"))
+		})
+	}
+}
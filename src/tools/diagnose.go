@@ -0,0 +1,385 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// exitCodeMeanings explains common container exit codes.
+var exitCodeMeanings = map[int32]string{
+	1:   "application error",
+	2:   "misuse of shell builtin or invalid arguments",
+	126: "command found but not executable",
+	127: "command not found (check image entrypoint and command)",
+	128: "invalid exit argument",
+	134: "SIGABRT: process aborted",
+	137: "SIGKILL: killed, usually by the OOM killer or after failing liveness probes",
+	139: "SIGSEGV: segmentation fault",
+	143: "SIGTERM: terminated, usually during shutdown or after a failed liveness probe",
+}
+
+// DiagnosePodInput represents the input for diagnosing a pod.
+type DiagnosePodInput struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	TailLines int64  `json:"tailLines,omitempty"`
+}
+
+// ContainerTermination describes how a container instance ended.
+type ContainerTermination struct {
+	Reason      string `json:"reason,omitempty"`
+	Message     string `json:"message,omitempty"`
+	ExitCode    int32  `json:"exitCode"`
+	ExitMeaning string `json:"exitMeaning,omitempty"`
+	Signal      int32  `json:"signal,omitempty"`
+	StartedAt   string `json:"startedAt,omitempty"`
+	FinishedAt  string `json:"finishedAt,omitempty"`
+}
+
+// ProbeSummary is a compact description of a container probe.
+type ProbeSummary struct {
+	Handler             string `json:"handler"`
+	InitialDelaySeconds int32  `json:"initialDelaySeconds"`
+	PeriodSeconds       int32  `json:"periodSeconds"`
+	TimeoutSeconds      int32  `json:"timeoutSeconds"`
+	FailureThreshold    int32  `json:"failureThreshold"`
+}
+
+// ContainerDiagnosis collects everything known about one container of a pod.
+type ContainerDiagnosis struct {
+	Name         string                   `json:"name"`
+	Init         bool                     `json:"init,omitempty"`
+	Image        string                   `json:"image"`
+	Ready        bool                     `json:"ready"`
+	RestartCount int32                    `json:"restartCount"`
+	State        string                   `json:"state"`
+	Reason       string                   `json:"reason,omitempty"`
+	Message      string                   `json:"message,omitempty"`
+	OOMKilled    bool                     `json:"oomKilled,omitempty"`
+	Current      *ContainerTermination    `json:"terminated,omitempty"`
+	LastState    *ContainerTermination    `json:"lastTermination,omitempty"`
+	MemoryLimit  string                   `json:"memoryLimit,omitempty"`
+	CPULimit     string                   `json:"cpuLimit,omitempty"`
+	Probes       map[string]*ProbeSummary `json:"probes,omitempty"`
+	PreviousLogs string                   `json:"previousLogs,omitempty"`
+	LogsError    string                   `json:"logsError,omitempty"`
+}
+
+// DiagnosePodTool gathers the signals needed to explain a crashing or unhealthy pod.
+type DiagnosePodTool struct {
+	client Client
+}
+
+// NewDiagnosePodTool creates a new DiagnosePodTool with the provided Kubernetes client.
+func NewDiagnosePodTool(client Client) *DiagnosePodTool {
+	return &DiagnosePodTool{client: client}
+}
+
+// Tool returns the MCP tool definition for diagnose_pod.
+func (d *DiagnosePodTool) Tool() mcp.Tool {
+	return mcp.NewTool("diagnose_pod",
+		mcp.WithDescription("Root-cause a crashing pod (e.g. CrashLoopBackOff): returns container states with exit codes and OOMKilled flags, last termination messages, the tail of the previous container's logs, recent Warning events and probe configuration in one payload"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the pod to diagnose"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the pod (defaults to 'default' if not specified)"),
+		),
+		mcp.WithNumber("tailLines",
+			mcp.Description("Number of lines of previous-container logs to include per restarted container (default: 30, 0 to skip logs)"),
+		),
+	)
+}
+
+// Handler builds the diagnosis for the pod.
+func (d *DiagnosePodTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateDiagnosePodParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate diagnose_pod params: %w", err)
+	}
+
+	clientset, err := d.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	pod, err := clientset.CoreV1().Pods(input.Namespace).Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s/%s: %w", input.Namespace, input.Name, err)
+	}
+
+	containers := diagnoseContainers(pod)
+	if input.TailLines > 0 {
+		for i := range containers {
+			c := &containers[i]
+			if c.LastState == nil && c.RestartCount == 0 {
+				continue
+			}
+			logs, err := tailContainerLogs(ctx, clientset, pod, c.Name, true, input.TailLines)
+			if err != nil {
+				c.LogsError = err.Error()
+				continue
+			}
+			c.PreviousLogs = logs
+		}
+	}
+
+	result := map[string]any{
+		"pod":        pod.Name,
+		"namespace":  pod.Namespace,
+		"phase":      pod.Status.Phase,
+		"node":       pod.Spec.NodeName,
+		"containers": containers,
+		"findings":   diagnosisFindings(containers),
+	}
+	if pod.Status.Reason != "" {
+		result["reason"] = pod.Status.Reason
+		result["message"] = pod.Status.Message
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		result["owner"] = owner.Kind + "/" + owner.Name
+	}
+
+	events, err := listEvents(ctx, clientset, pod.Namespace, "Pod", pod.Name, true)
+	if err != nil {
+		result["eventsError"] = err.Error()
+	} else {
+		if len(events) > 10 {
+			events = events[:10]
+		}
+		result["warningEvents"] = events
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// diagnoseContainers summarizes the init and app containers of a pod from its spec and status.
+func diagnoseContainers(pod *corev1.Pod) []ContainerDiagnosis {
+	statuses := map[string]corev1.ContainerStatus{}
+	for _, cs := range pod.Status.InitContainerStatuses {
+		statuses["init/"+cs.Name] = cs
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+
+	var result []ContainerDiagnosis
+	add := func(c *corev1.Container, init bool) {
+		key := c.Name
+		if init {
+			key = "init/" + c.Name
+		}
+		diag := ContainerDiagnosis{Name: c.Name, Init: init, Image: c.Image, State: "unknown"}
+		if q, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+			diag.MemoryLimit = q.String()
+		}
+		if q, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+			diag.CPULimit = q.String()
+		}
+		diag.Probes = containerProbes(c)
+
+		if cs, ok := statuses[key]; ok {
+			diag.Ready = cs.Ready
+			diag.RestartCount = cs.RestartCount
+			switch {
+			case cs.State.Waiting != nil:
+				diag.State = "waiting"
+				diag.Reason = cs.State.Waiting.Reason
+				diag.Message = cs.State.Waiting.Message
+			case cs.State.Running != nil:
+				diag.State = "running"
+			case cs.State.Terminated != nil:
+				diag.State = "terminated"
+				diag.Current = summarizeTermination(cs.State.Terminated)
+				diag.Reason = cs.State.Terminated.Reason
+			}
+			if cs.LastTerminationState.Terminated != nil {
+				diag.LastState = summarizeTermination(cs.LastTerminationState.Terminated)
+			}
+			diag.OOMKilled = (diag.Current != nil && diag.Current.Reason == "OOMKilled") ||
+				(diag.LastState != nil && diag.LastState.Reason == "OOMKilled")
+		}
+		result = append(result, diag)
+	}
+
+	for i := range pod.Spec.InitContainers {
+		add(&pod.Spec.InitContainers[i], true)
+	}
+	for i := range pod.Spec.Containers {
+		add(&pod.Spec.Containers[i], false)
+	}
+	return result
+}
+
+// summarizeTermination converts a terminated container state, annotating well-known exit codes.
+func summarizeTermination(t *corev1.ContainerStateTerminated) *ContainerTermination {
+	summary := &ContainerTermination{
+		Reason:      t.Reason,
+		Message:     strings.TrimSpace(t.Message),
+		ExitCode:    t.ExitCode,
+		ExitMeaning: exitCodeMeanings[t.ExitCode],
+		Signal:      t.Signal,
+	}
+	if !t.StartedAt.IsZero() {
+		summary.StartedAt = t.StartedAt.Format(time.RFC3339)
+	}
+	if !t.FinishedAt.IsZero() {
+		summary.FinishedAt = t.FinishedAt.Format(time.RFC3339)
+	}
+	return summary
+}
+
+// containerProbes describes the liveness, readiness and startup probes configured on a container.
+func containerProbes(c *corev1.Container) map[string]*ProbeSummary {
+	probes := map[string]*ProbeSummary{}
+	if c.LivenessProbe != nil {
+		probes["liveness"] = summarizeProbe(c.LivenessProbe)
+	}
+	if c.ReadinessProbe != nil {
+		probes["readiness"] = summarizeProbe(c.ReadinessProbe)
+	}
+	if c.StartupProbe != nil {
+		probes["startup"] = summarizeProbe(c.StartupProbe)
+	}
+	if len(probes) == 0 {
+		return nil
+	}
+	return probes
+}
+
+// summarizeProbe renders a probe's handler and timings, filling in the API server defaults.
+func summarizeProbe(p *corev1.Probe) *ProbeSummary {
+	summary := &ProbeSummary{
+		InitialDelaySeconds: p.InitialDelaySeconds,
+		PeriodSeconds:       p.PeriodSeconds,
+		TimeoutSeconds:      p.TimeoutSeconds,
+		FailureThreshold:    p.FailureThreshold,
+	}
+	if summary.PeriodSeconds == 0 {
+		summary.PeriodSeconds = 10
+	}
+	if summary.TimeoutSeconds == 0 {
+		summary.TimeoutSeconds = 1
+	}
+	if summary.FailureThreshold == 0 {
+		summary.FailureThreshold = 3
+	}
+
+	switch {
+	case p.HTTPGet != nil:
+		summary.Handler = fmt.Sprintf("httpGet %s:%s", p.HTTPGet.Path, p.HTTPGet.Port.String())
+	case p.TCPSocket != nil:
+		summary.Handler = "tcpSocket " + p.TCPSocket.Port.String()
+	case p.GRPC != nil:
+		summary.Handler = fmt.Sprintf("grpc %d", p.GRPC.Port)
+	case p.Exec != nil:
+		summary.Handler = "exec " + strings.Join(p.Exec.Command, " ")
+	default:
+		summary.Handler = "none"
+	}
+	return summary
+}
+
+// diagnosisFindings turns the container diagnoses into short, human-readable observations.
+func diagnosisFindings(containers []ContainerDiagnosis) []string {
+	findings := []string{}
+	for _, c := range containers {
+		if c.OOMKilled {
+			limit := c.MemoryLimit
+			if limit == "" {
+				limit = "none (node memory pressure)"
+			}
+			findings = append(findings, fmt.Sprintf("container %s was OOMKilled; memory limit: %s", c.Name, limit))
+		}
+		switch c.Reason {
+		case "CrashLoopBackOff":
+			msg := fmt.Sprintf("container %s is in CrashLoopBackOff after %d restarts", c.Name, c.RestartCount)
+			if c.LastState != nil {
+				msg += fmt.Sprintf("; last exit code %d", c.LastState.ExitCode)
+				if c.LastState.ExitMeaning != "" {
+					msg += " (" + c.LastState.ExitMeaning + ")"
+				}
+			}
+			findings = append(findings, msg)
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+			findings = append(findings, fmt.Sprintf("container %s cannot pull image %s: %s", c.Name, c.Image, c.Reason))
+		case "CreateContainerConfigError", "CreateContainerError":
+			findings = append(findings, fmt.Sprintf("container %s cannot be created (%s); check referenced ConfigMaps, Secrets and volume mounts", c.Name, c.Reason))
+		}
+		if c.Probes["liveness"] != nil && c.Probes["startup"] == nil &&
+			c.LastState != nil && (c.LastState.ExitCode == 137 || c.LastState.ExitCode == 143) && !c.OOMKilled {
+			findings = append(findings, fmt.Sprintf("container %s was killed by a signal and has a liveness probe without a startup probe; the probe may be failing before the app is ready", c.Name))
+		}
+	}
+	return findings
+}
+
+// tailContainerLogs returns the last lines of a container's current or previous logs.
+func tailContainerLogs(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, container string, previous bool, lines int64) (string, error) {
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &lines,
+	}).Stream(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to stream logs for container %s: %w", container, err)
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logs for container %s: %w", container, err)
+	}
+	return string(data), nil
+}
+
+// parseAndValidateDiagnosePodParams validates and parses the input parameters.
+func parseAndValidateDiagnosePodParams(args map[string]any) (*DiagnosePodInput, error) {
+	input := &DiagnosePodInput{TailLines: 30}
+
+	if name, ok := args["name"].(string); ok {
+		input.Name = name
+	}
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid pod name: %w", err)
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	if tail, ok := args["tailLines"].(float64); ok {
+		if tail < 0 {
+			return nil, errors.New("tailLines must not be negative")
+		}
+		input.TailLines = int64(tail)
+	}
+
+	return input, nil
+}
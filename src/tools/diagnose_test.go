@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDiagnoseContainers(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "app",
+			Image: "example/app:1.0",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+			LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(8080)},
+			}},
+		}}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "app",
+			RestartCount: 4,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 137,
+				Reason:   "OOMKilled",
+			}},
+		}}},
+	}
+
+	containers := diagnoseContainers(pod)
+
+	if assert.Len(t, containers, 1) {
+		c := containers[0]
+		assert.Equal(t, "waiting", c.State)
+		assert.True(t, c.OOMKilled)
+		assert.Equal(t, "256Mi", c.MemoryLimit)
+		assert.Equal(t, int32(137), c.LastState.ExitCode)
+		assert.Contains(t, c.LastState.ExitMeaning, "SIGKILL")
+		assert.Equal(t, &ProbeSummary{Handler: "httpGet /healthz:8080", PeriodSeconds: 10, TimeoutSeconds: 1, FailureThreshold: 3}, c.Probes["liveness"])
+	}
+
+	findings := diagnosisFindings(containers)
+	assert.Equal(t, []string{
+		"container app was OOMKilled; memory limit: 256Mi",
+		"container app is in CrashLoopBackOff after 4 restarts; last exit code 137 (SIGKILL: killed, usually by the OOM killer or after failing liveness probes)",
+	}, findings)
+}
+
+func TestParseAndValidateDiagnosePodParams(t *testing.T) {
+	input, err := parseAndValidateDiagnosePodParams(map[string]any{"name": "web-0"})
+	assert.NoError(t, err)
+	assert.Equal(t, "default", input.Namespace)
+	assert.Equal(t, int64(30), input.TailLines)
+
+	_, err = parseAndValidateDiagnosePodParams(map[string]any{})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateDiagnosePodParams(map[string]any{"name": "web-0", "tailLines": float64(-1)})
+	assert.Error(t, err)
+}
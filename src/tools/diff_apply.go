@@ -0,0 +1,300 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	defaultDiffFieldManager = "kubernetes-mcp"
+	// maxApplyChanges is the number of changed fields reported per object.
+	maxApplyChanges = 200
+)
+
+// ignoredDiffFields are maintained by the API server or by kubectl and change on every apply.
+var ignoredDiffFields = []string{
+	"metadata.managedFields",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.uid",
+	"metadata.creationTimestamp",
+	"metadata.annotations[kubectl.kubernetes.io/last-applied-configuration]",
+	"status",
+}
+
+// FieldChange is a field an apply would add, remove or change. Values are JSON.
+type FieldChange struct {
+	Path   string `json:"path"`
+	Op     string `json:"op"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// ApplyDiff is what applying one document would do to the live object.
+type ApplyDiff struct {
+	Kind      string        `json:"kind"`
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace,omitempty"`
+	Action    string        `json:"action"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+	Truncated bool          `json:"truncated,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// DiffApplyInput represents the input for diff_apply.
+type DiffApplyInput struct {
+	Manifest     string `json:"manifest"`
+	Namespace    string `json:"namespace"`
+	FieldManager string `json:"fieldManager"`
+	Force        bool   `json:"force"`
+}
+
+// DiffApplyTool previews a server-side apply as a field-by-field diff against the live objects.
+type DiffApplyTool struct {
+	client Client
+}
+
+// NewDiffApplyTool creates a new DiffApplyTool with the provided Kubernetes client.
+func NewDiffApplyTool(client Client) *DiffApplyTool {
+	return &DiffApplyTool{client: client}
+}
+
+// Tool returns the MCP tool definition for diff_apply.
+func (d *DiffApplyTool) Tool() mcp.Tool {
+	return mcp.NewTool("diff_apply",
+		mcp.WithDescription("Preview what applying a manifest would change, like kubectl diff --server-side: each document is applied with a server-side dry run, so defaulting, admission webhooks and field ownership are taken into account, and the result is compared field by field with the live object. Reports create, update or unchanged per object with the added, removed and changed fields; Secret values are shown by size and hash. Nothing is changed in the cluster"),
+		mcp.WithString("manifest",
+			mcp.Required(),
+			mcp.Description("YAML or JSON manifest; several documents are separated by ---"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for documents that do not set one (default: default)"),
+		),
+		mcp.WithString("fieldManager",
+			mcp.Description(fmt.Sprintf("Field manager to apply as; use the one of the tool that really applies, e.g. kubectl or argocd-controller, so fields it owns but the manifest drops show up as removed (default: %s)", defaultDiffFieldManager)),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Take over fields owned by other field managers instead of reporting the conflict (default: false)"),
+		),
+	)
+}
+
+// Handler dry-run applies every document and diffs it against the live object.
+func (d *DiffApplyTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateDiffApplyParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate diff_apply params: %w", err)
+	}
+	objects, err := parseYAMLObjects([]byte(input.Manifest))
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("manifest has no documents")
+	}
+	if len(objects) > maxManifestDocuments {
+		return nil, fmt.Errorf("manifest has more than %d documents", maxManifestDocuments)
+	}
+	mapper, err := d.client.RESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+
+	diffs := make([]ApplyDiff, 0, len(objects))
+	counts := map[string]int{}
+	for _, obj := range objects {
+		diff := d.diffObject(ctx, mapper, obj, input)
+		counts[diff.Action]++
+		diffs = append(diffs, diff)
+	}
+	out, err := json.Marshal(map[string]any{
+		"summary":      counts,
+		"fieldManager": input.FieldManager,
+		"objects":      diffs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// diffObject dry-run applies one object and compares the result with the live object.
+func (d *DiffApplyTool) diffObject(ctx context.Context, mapper meta.RESTMapper, obj *unstructured.Unstructured, input *DiffApplyInput) ApplyDiff {
+	diff := ApplyDiff{Kind: obj.GetKind(), Name: obj.GetName(), Action: "error"}
+	if diff.Name == "" {
+		diff.Error = "metadata.name is required"
+		return diff
+	}
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		diff.Error = fmt.Sprintf("%s is not served by the cluster: %v", gvk.String(), err)
+		return diff
+	}
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if namespaced {
+		diff.Namespace = obj.GetNamespace()
+		if diff.Namespace == "" {
+			diff.Namespace = input.Namespace
+			obj.SetNamespace(diff.Namespace)
+		}
+	}
+	ri, err := d.client.ResourceInterface(mapping.Resource, namespaced, diff.Namespace)
+	if err != nil {
+		diff.Error = fmt.Sprintf("failed to create resource interface: %v", err)
+		return diff
+	}
+
+	live, err := ri.Get(ctx, diff.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		live = nil
+	} else if err != nil {
+		diff.Error = fmt.Sprintf("failed to get live object: %v", err)
+		return diff
+	}
+	applied, err := ri.Apply(ctx, diff.Name, obj, metav1.ApplyOptions{
+		DryRun:       []string{metav1.DryRunAll},
+		FieldManager: input.FieldManager,
+		Force:        input.Force,
+	})
+	if err != nil {
+		diff.Error = strings.Join(dryRunErrors(err), "; ")
+		return diff
+	}
+
+	var before map[string]any
+	if live != nil {
+		before = live.Object
+	}
+	diff.Changes = diffObjects(before, applied.Object, diff.Kind == "Secret")
+	switch {
+	case live == nil:
+		diff.Action = "create"
+	case len(diff.Changes) == 0:
+		diff.Action = "unchanged"
+	default:
+		diff.Action = "update"
+	}
+	if len(diff.Changes) > maxApplyChanges {
+		diff.Changes, diff.Truncated = diff.Changes[:maxApplyChanges], true
+	}
+	return diff
+}
+
+// diffObjects compares two objects leaf by leaf, skipping ignoredDiffFields. List items that have
+// a name are matched by name, so reordering containers is not reported as a change.
+func diffObjects(before, after map[string]any, secret bool) []FieldChange {
+	a, b := map[string]string{}, map[string]string{}
+	flattenFields("", before, a)
+	flattenFields("", after, b)
+
+	value := func(path, v string) string {
+		if secret && (strings.HasPrefix(path, "data") || strings.HasPrefix(path, "stringData")) {
+			return fmt.Sprintf("(%d bytes, sha256 %x)", len(v), sha256.Sum256([]byte(v)))
+		}
+		return diffValue(v)
+	}
+	var changes []FieldChange
+	for path, va := range a {
+		vb, ok := b[path]
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Path: path, Op: "remove", Before: value(path, va)})
+		case va != vb:
+			changes = append(changes, FieldChange{Path: path, Op: "change", Before: value(path, va), After: value(path, vb)})
+		}
+	}
+	for path, vb := range b {
+		if _, ok := a[path]; !ok {
+			changes = append(changes, FieldChange{Path: path, Op: "add", After: value(path, vb)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// flattenFields records the JSON value of every leaf of value under its path. Map keys that are
+// not plain identifiers are written as [key].
+func flattenFields(path string, value any, fields map[string]string) {
+	for _, ignored := range ignoredDiffFields {
+		if path == ignored {
+			return
+		}
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 && path != "" {
+			fields[path] = "{}"
+		}
+		for key, item := range v {
+			flattenFields(fieldPath(path, key), item, fields)
+		}
+	case []any:
+		if len(v) == 0 {
+			fields[path] = "[]"
+		}
+		for i, item := range v {
+			key := fmt.Sprint(i)
+			if m, ok := item.(map[string]any); ok {
+				if name, ok := m["name"].(string); ok && name != "" {
+					key = name
+				}
+			}
+			flattenFields(fmt.Sprintf("%s[%s]", path, key), item, fields)
+		}
+	case nil:
+		if path != "" {
+			fields[path] = "null"
+		}
+	default:
+		encoded, _ := json.Marshal(v)
+		fields[path] = string(encoded)
+	}
+}
+
+// fieldPath appends a map key to a path.
+func fieldPath(path, key string) string {
+	if strings.ContainsAny(key, "./[]") || key == "" {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func parseAndValidateDiffApplyParams(args map[string]any) (*DiffApplyInput, error) {
+	input := &DiffApplyInput{Namespace: "default", FieldManager: defaultDiffFieldManager}
+	input.Manifest, _ = args["manifest"].(string)
+	if strings.TrimSpace(input.Manifest) == "" {
+		return nil, fmt.Errorf("manifest is required")
+	}
+	if len(input.Manifest) > maxManifestBytes {
+		return nil, fmt.Errorf("manifest is larger than %d bytes", maxManifestBytes)
+	}
+	if namespace, ok := args["namespace"].(string); ok && namespace != "" {
+		if err := validation.ValidateNamespace(namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = namespace
+	}
+	if manager, ok := args["fieldManager"].(string); ok && manager != "" {
+		if len(manager) > 128 {
+			return nil, fmt.Errorf("fieldManager must be at most 128 characters")
+		}
+		input.FieldManager = manager
+	}
+	input.Force, _ = args["force"].(bool)
+	return input, nil
+}
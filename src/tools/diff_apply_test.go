@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// stubDiffInterface serves live objects by name and answers dry-run applies with the applied
+// object plus the fields the API server would set.
+type stubDiffInterface struct {
+	dynamic.ResourceInterface
+	live map[string]*unstructured.Unstructured
+}
+
+func (s *stubDiffInterface) Get(ctx context.Context, name string, opts metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if obj, ok := s.live[name]; ok {
+		return obj, nil
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, name)
+}
+
+func (s *stubDiffInterface) Apply(ctx context.Context, name string, obj *unstructured.Unstructured, opts metav1.ApplyOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if len(opts.DryRun) == 0 {
+		panic("apply without dry run")
+	}
+	applied := obj.DeepCopy()
+	applied.SetResourceVersion("2")
+	applied.SetGeneration(2)
+	return applied, nil
+}
+
+type fakeDiffApplyClient struct {
+	fakeValidateClient
+	live map[string]*unstructured.Unstructured
+}
+
+func (f *fakeDiffApplyClient) ResourceInterface(gvr schema.GroupVersionResource, namespaced bool, ns string) (dynamic.ResourceInterface, error) {
+	return &stubDiffInterface{live: f.live}, nil
+}
+
+func TestDiffApplyHandler(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "web", "namespace": "shop", "resourceVersion": "1", "generation": int64(1)},
+		"spec": map[string]any{
+			"replicas": int64(2),
+			"template": map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "web", "image": "nginx:1.26", "args": []any{"--debug"}},
+			}}},
+		},
+		"status": map[string]any{"readyReplicas": int64(2)},
+	}}
+	client := &fakeDiffApplyClient{live: map[string]*unstructured.Unstructured{"web": live}}
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.27
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 1
+`
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"manifest": manifest}
+	result, err := NewDiffApplyTool(client).Handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	var out struct {
+		Summary map[string]int `json:"summary"`
+		Objects []ApplyDiff    `json:"objects"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	assert.Equal(t, map[string]int{"update": 1, "create": 1}, out.Summary)
+	assert.Equal(t, []FieldChange{
+		{Path: "spec.replicas", Op: "change", Before: "2", After: "3"},
+		{Path: "spec.template.spec.containers[web].args[0]", Op: "remove", Before: `"--debug"`},
+		{Path: "spec.template.spec.containers[web].image", Op: "change", Before: `"nginx:1.26"`, After: `"nginx:1.27"`},
+	}, out.Objects[0].Changes)
+	assert.Equal(t, "create", out.Objects[1].Action)
+	assert.Equal(t, "default", out.Objects[1].Namespace)
+}
+
+func TestDiffObjects(t *testing.T) {
+	before := map[string]any{
+		"metadata": map[string]any{"annotations": map[string]any{
+			"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			"team": "payments",
+		}},
+		"data": map[string]any{"password": "c2VjcmV0"},
+	}
+	after := map[string]any{
+		"metadata": map[string]any{"annotations": map[string]any{"team": "checkout"}},
+		"data":     map[string]any{"password": "bmV3"},
+	}
+
+	changes := diffObjects(before, after, true)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "data.password", changes[0].Path)
+	assert.NotContains(t, changes[0].Before, "c2VjcmV0")
+	assert.Contains(t, changes[0].After, "sha256")
+	assert.Equal(t, FieldChange{Path: "metadata.annotations.team", Op: "change", Before: `"payments"`, After: `"checkout"`}, changes[1])
+}
+
+func TestParseAndValidateDiffApplyParams(t *testing.T) {
+	input, err := parseAndValidateDiffApplyParams(map[string]any{"manifest": "kind: x", "fieldManager": "argocd-controller", "force": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "argocd-controller", input.FieldManager)
+	assert.True(t, input.Force)
+	assert.Equal(t, "default", input.Namespace)
+
+	_, err = parseAndValidateDiffApplyParams(map[string]any{"manifest": " "})
+	assert.Error(t, err)
+}
@@ -0,0 +1,350 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxDiffValue is the length above which compared values are reported by their hash.
+const maxDiffValue = 120
+
+// diffKinds are the kinds compared by diff_environments, by lowercase Kind, with the path of their
+// pod template.
+var diffKinds = map[string]struct {
+	gvr      schema.GroupVersionResource
+	template []string
+}{
+	"deployment":  {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, []string{"spec", "template", "spec"}},
+	"statefulset": {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, []string{"spec", "template", "spec"}},
+	"daemonset":   {schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, []string{"spec", "template", "spec"}},
+	"cronjob":     {schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, []string{"spec", "jobTemplate", "spec", "template", "spec"}},
+	"configmap":   {schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, nil},
+}
+
+// defaultDiffKinds are compared when the call does not choose kinds.
+var defaultDiffKinds = []string{"Deployment", "StatefulSet", "DaemonSet", "CronJob", "ConfigMap"}
+
+// DiffEnvironment is one side of a comparison: a namespace, optionally in another kubeconfig context.
+type DiffEnvironment struct {
+	Namespace string `json:"namespace"`
+	Context   string `json:"context,omitempty"`
+}
+
+// DiffEnvironmentsInput represents the input for diff_environments.
+type DiffEnvironmentsInput struct {
+	A             DiffEnvironment `json:"a"`
+	B             DiffEnvironment `json:"b"`
+	Kinds         []string        `json:"kinds"`
+	LabelSelector string          `json:"labelSelector,omitempty"`
+}
+
+// FieldDifference is a field whose value differs between the environments; an empty side means
+// the field is not set there.
+type FieldDifference struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+// ObjectDrift lists the differences of an object present in both environments.
+type ObjectDrift struct {
+	Kind        string            `json:"kind"`
+	Name        string            `json:"name"`
+	Differences []FieldDifference `json:"differences"`
+}
+
+// DiffEnvironmentsTool compares the workloads and configuration of two namespaces or clusters.
+type DiffEnvironmentsTool struct {
+	client Client
+}
+
+// NewDiffEnvironmentsTool creates a new DiffEnvironmentsTool with the provided Kubernetes client.
+func NewDiffEnvironmentsTool(client Client) *DiffEnvironmentsTool {
+	return &DiffEnvironmentsTool{client: client}
+}
+
+// Tool returns the MCP tool definition for diff_environments.
+func (d *DiffEnvironmentsTool) Tool() mcp.Tool {
+	return mcp.NewTool("diff_environments",
+		mcp.WithDescription("Compare workloads and configuration between two namespaces, in the same cluster or in two kubeconfig contexts, and report the drift: objects that exist on one side only and, for objects on both, differing image tags, replica counts, env vars, resource requests and limits, schedules and ConfigMap keys. Answers \"what's different between staging and prod?\""),
+		mcp.WithString("namespaceA",
+			mcp.Required(),
+			mcp.Description("First namespace, e.g. staging"),
+		),
+		mcp.WithString("namespaceB",
+			mcp.Description("Second namespace (default: namespaceA, to compare the same namespace in two contexts)"),
+		),
+		mcp.WithString("contextA",
+			mcp.Description("Kubeconfig context of the first namespace (default: the server's cluster); it must be listed in KUBE_CONTEXTS"),
+		),
+		mcp.WithString("contextB",
+			mcp.Description("Kubeconfig context of the second namespace (default: the server's cluster); it must be listed in KUBE_CONTEXTS"),
+		),
+		mcp.WithArray("kinds",
+			mcp.Description("Kinds to compare: Deployment, StatefulSet, DaemonSet, CronJob or ConfigMap (default: all of them)"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Only compare objects matching this label selector on both sides"),
+		),
+	)
+}
+
+// Handler lists both environments and compares the objects by kind and name.
+func (d *DiffEnvironmentsTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateDiffEnvironmentsParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate diff_environments params: %w", err)
+	}
+
+	clientA, err := d.environmentClient(ctx, input.A)
+	if err != nil {
+		return nil, err
+	}
+	clientB, err := d.environmentClient(ctx, input.B)
+	if err != nil {
+		return nil, err
+	}
+
+	var onlyA, onlyB []string
+	drift := []ObjectDrift{}
+	identical := 0
+	for _, kind := range input.Kinds {
+		spec := diffKinds[strings.ToLower(kind)]
+		objectsA, err := listEnvironment(ctx, clientA, input.A, spec.gvr, input.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		objectsB, err := listEnvironment(ctx, clientB, input.B, spec.gvr, input.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range sortedKeys(objectsA) {
+			b, ok := objectsB[name]
+			if !ok {
+				onlyA = append(onlyA, kind+"/"+name)
+				continue
+			}
+			differences := diffFields(comparableFields(objectsA[name], spec.template), comparableFields(b, spec.template))
+			if len(differences) == 0 {
+				identical++
+				continue
+			}
+			drift = append(drift, ObjectDrift{Kind: kind, Name: name, Differences: differences})
+		}
+		for _, name := range sortedKeys(objectsB) {
+			if _, ok := objectsA[name]; !ok {
+				onlyB = append(onlyB, kind+"/"+name)
+			}
+		}
+	}
+
+	out, err := json.Marshal(map[string]any{
+		"a":         input.A,
+		"b":         input.B,
+		"kinds":     input.Kinds,
+		"onlyInA":   onlyA,
+		"onlyInB":   onlyB,
+		"drift":     drift,
+		"identical": identical,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// environmentClient returns the client of an environment: the tool's own, or one for its kubeconfig
+// context if KUBE_CONTEXTS and the caller allow it.
+func (d *DiffEnvironmentsTool) environmentClient(ctx context.Context, env DiffEnvironment) (Client, error) {
+	if env.Context == "" {
+		return d.client, nil
+	}
+	if err := authorizeKubeContext(ctx, env.Context); err != nil {
+		return nil, err
+	}
+	return clientForContext(env.Context)
+}
+
+// listEnvironment returns the objects of a resource in an environment by name.
+func listEnvironment(ctx context.Context, client Client, env DiffEnvironment, gvr schema.GroupVersionResource, labelSelector string) (map[string]*unstructured.Unstructured, error) {
+	ri, err := client.ResourceInterface(gvr, true, env.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource interface: %w", err)
+	}
+
+	list, err := ri.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s in %s: %w", gvr.Resource, env, err)
+	}
+	objects := make(map[string]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		objects[list.Items[i].GetName()] = &list.Items[i]
+	}
+	return objects, nil
+}
+
+// String names an environment in messages.
+func (e DiffEnvironment) String() string {
+	if e.Context == "" {
+		return "namespace " + e.Namespace
+	}
+	return "namespace " + e.Namespace + " of context " + e.Context
+}
+
+// comparableFields flattens the settings that usually drift between environments into field paths.
+// Workloads contribute replicas, schedule and, per container, image, env vars, envFrom sources and
+// resources; ConfigMaps contribute their keys.
+func comparableFields(obj *unstructured.Unstructured, template []string) map[string]string {
+	fields := map[string]string{}
+	if template == nil {
+		data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+		for key, value := range data {
+			fields["data."+key] = diffValue(value)
+		}
+		binary, _, _ := unstructured.NestedMap(obj.Object, "binaryData")
+		for key, value := range binary {
+			fields["binaryData."+key] = diffValue(fmt.Sprint(value))
+		}
+		return fields
+	}
+
+	if replicas, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "replicas"); ok {
+		fields["replicas"] = fmt.Sprint(replicas)
+	}
+	if schedule, ok, _ := unstructured.NestedString(obj.Object, "spec", "schedule"); ok {
+		fields["schedule"] = schedule
+	}
+	if suspend, ok, _ := unstructured.NestedBool(obj.Object, "spec", "suspend"); ok {
+		fields["suspend"] = fmt.Sprint(suspend)
+	}
+	for _, group := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(obj.Object, append(template, group)...)
+		for _, c := range containers {
+			container, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			prefix := fmt.Sprintf("%s[%s].", group, container["name"])
+			if image, ok := container["image"].(string); ok {
+				fields[prefix+"image"] = image
+			}
+			env, _, _ := unstructured.NestedSlice(container, "env")
+			for _, e := range env {
+				v, ok := e.(map[string]any)
+				if !ok {
+					continue
+				}
+				if value, ok := v["value"].(string); ok {
+					fields[prefix+"env."+fmt.Sprint(v["name"])] = diffValue(value)
+				} else if from, ok := v["valueFrom"]; ok {
+					ref, _ := json.Marshal(from)
+					fields[prefix+"env."+fmt.Sprint(v["name"])] = "valueFrom " + string(ref)
+				}
+			}
+			if envFrom, ok := container["envFrom"]; ok {
+				ref, _ := json.Marshal(envFrom)
+				fields[prefix+"envFrom"] = string(ref)
+			}
+			for _, section := range []string{"requests", "limits"} {
+				values, _, _ := unstructured.NestedStringMap(container, "resources", section)
+				for resource, quantity := range values {
+					fields[prefix+"resources."+section+"."+resource] = quantity
+				}
+			}
+		}
+	}
+	return fields
+}
+
+// diffValue returns short values as they are and long ones by size and hash.
+func diffValue(value string) string {
+	if len(value) <= maxDiffValue {
+		return value
+	}
+	return fmt.Sprintf("(%d bytes, sha256 %x)", len(value), sha256.Sum256([]byte(value)))
+}
+
+// diffFields returns the fields whose values differ, sorted by field.
+func diffFields(a, b map[string]string) []FieldDifference {
+	var differences []FieldDifference
+	for field, va := range a {
+		if vb, ok := b[field]; !ok || va != vb {
+			differences = append(differences, FieldDifference{Field: field, A: va, B: vb})
+		}
+	}
+	for field, vb := range b {
+		if _, ok := a[field]; !ok {
+			differences = append(differences, FieldDifference{Field: field, B: vb})
+		}
+	}
+	sort.Slice(differences, func(i, j int) bool { return differences[i].Field < differences[j].Field })
+	return differences
+}
+
+// sortedKeys returns the names of a set of objects in order.
+func sortedKeys(objects map[string]*unstructured.Unstructured) []string {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseAndValidateDiffEnvironmentsParams(args map[string]any) (*DiffEnvironmentsInput, error) {
+	input := &DiffEnvironmentsInput{}
+	input.A.Namespace, _ = args["namespaceA"].(string)
+	input.B.Namespace, _ = args["namespaceB"].(string)
+	input.A.Context, _ = args["contextA"].(string)
+	input.B.Context, _ = args["contextB"].(string)
+	if input.A.Namespace == "" {
+		return nil, fmt.Errorf("namespaceA is required")
+	}
+	if input.B.Namespace == "" {
+		input.B.Namespace = input.A.Namespace
+	}
+	for _, namespace := range []string{input.A.Namespace, input.B.Namespace} {
+		if err := validation.ValidateNamespace(namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.A == input.B {
+		return nil, fmt.Errorf("namespaceA and namespaceB must differ unless contextA and contextB do")
+	}
+
+	kinds, err := stringSliceArg(args, "kinds")
+	if err != nil {
+		return nil, err
+	}
+	if len(kinds) == 0 {
+		kinds = defaultDiffKinds
+	}
+	for _, kind := range kinds {
+		i := slices.IndexFunc(defaultDiffKinds, func(known string) bool { return strings.EqualFold(kind, known) })
+		if i < 0 {
+			return nil, fmt.Errorf("unsupported kind %q: expected one of %s", kind, strings.Join(defaultDiffKinds, ", "))
+		}
+		input.Kinds = append(input.Kinds, defaultDiffKinds[i])
+	}
+
+	if labelSelector, ok := args["labelSelector"].(string); ok && labelSelector != "" {
+		if err := validation.ValidateLabelSelector(labelSelector); err != nil {
+			return nil, fmt.Errorf("invalid labelSelector: %w", err)
+		}
+		input.LabelSelector = labelSelector
+	}
+	return input, nil
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+type fakeDiffClient struct {
+	FakeKubernetesClient
+	objects []runtime.Object
+}
+
+func (f fakeDiffClient) ResourceInterface(gvr schema.GroupVersionResource, namespaced bool, ns string) (dynamic.ResourceInterface, error) {
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, kind := range diffKinds {
+		listKinds[kind.gvr] = "List"
+	}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, f.objects...)
+	return client.Resource(gvr).Namespace(ns), nil
+}
+
+func testDiffDeployment(namespace, name, image string, replicas int64, env map[string]string) *unstructured.Unstructured {
+	var envVars []any
+	for k, v := range env {
+		envVars = append(envVars, map[string]any{"name": k, "value": v})
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1", "kind": "Deployment",
+		"metadata": map[string]any{"name": name, "namespace": namespace},
+		"spec": map[string]any{"replicas": replicas, "template": map[string]any{"spec": map[string]any{
+			"containers": []any{map[string]any{
+				"name": "app", "image": image, "env": envVars,
+				"resources": map[string]any{"limits": map[string]any{"memory": "512Mi"}},
+			}},
+		}}},
+	}}
+}
+
+func TestDiffEnvironmentsHandler(t *testing.T) {
+	client := fakeDiffClient{objects: []runtime.Object{
+		testDiffDeployment("staging", "web", "web:1.3", 1, map[string]string{"LOG_LEVEL": "debug"}),
+		testDiffDeployment("prod", "web", "web:1.2", 3, map[string]string{"LOG_LEVEL": "info", "CACHE": "on"}),
+		testDiffDeployment("staging", "api", "api:2", 1, nil),
+		testDiffDeployment("prod", "api", "api:2", 1, nil),
+		testDiffDeployment("staging", "canary", "web:1.4", 1, nil),
+		&unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1", "kind": "ConfigMap",
+			"metadata": map[string]any{"name": "settings", "namespace": "prod"},
+			"data":     map[string]any{"region": "eu"},
+		}},
+	}}
+	tool := NewDiffEnvironmentsTool(client)
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"namespaceA": "staging", "namespaceB": "prod"}
+	result, err := tool.Handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	var report struct {
+		OnlyInA   []string      `json:"onlyInA"`
+		OnlyInB   []string      `json:"onlyInB"`
+		Drift     []ObjectDrift `json:"drift"`
+		Identical int           `json:"identical"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &report))
+	assert.Equal(t, []string{"Deployment/canary"}, report.OnlyInA)
+	assert.Equal(t, []string{"ConfigMap/settings"}, report.OnlyInB)
+	assert.Equal(t, 1, report.Identical)
+	assert.Equal(t, []ObjectDrift{{Kind: "Deployment", Name: "web", Differences: []FieldDifference{
+		{Field: "containers[app].env.CACHE", B: "on"},
+		{Field: "containers[app].env.LOG_LEVEL", A: "debug", B: "info"},
+		{Field: "containers[app].image", A: "web:1.3", B: "web:1.2"},
+		{Field: "replicas", A: "1", B: "3"},
+	}}}, report.Drift)
+}
+
+func TestDiffEnvironmentsContexts(t *testing.T) {
+	previous := clientForContext
+	clientForContext = func(name string) (Client, error) {
+		return fakeDiffClient{objects: []runtime.Object{testDiffDeployment("shop", "web", "web:"+name, 1, nil)}}, nil
+	}
+	t.Cleanup(func() { clientForContext = previous })
+	tool := NewDiffEnvironmentsTool(fakeDiffClient{})
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"namespaceA": "shop", "contextA": "staging", "contextB": "prod", "kinds": []any{"Deployment"}}
+	_, err := tool.Handler(context.Background(), req)
+	assert.EqualError(t, err, `context "staging" is not in KUBE_CONTEXTS`)
+
+	t.Setenv("KUBE_CONTEXTS", "staging,prod")
+	_, err = tool.Handler(auth.WithClient(context.Background(), &auth.Client{Name: "payments", Contexts: []string{"staging"}}), req)
+	assert.EqualError(t, err, "client payments is not allowed to use context prod")
+
+	result, err := tool.Handler(context.Background(), req)
+	require.NoError(t, err)
+	var report struct {
+		Drift []ObjectDrift `json:"drift"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(toolResultText(result)), &report))
+	assert.Equal(t, []ObjectDrift{{Kind: "Deployment", Name: "web", Differences: []FieldDifference{
+		{Field: "containers[app].image", A: "web:staging", B: "web:prod"},
+	}}}, report.Drift)
+}
+
+func TestParseAndValidateDiffEnvironmentsParams(t *testing.T) {
+	input, err := parseAndValidateDiffEnvironmentsParams(map[string]any{"namespaceA": "shop", "contextA": "staging", "contextB": "prod", "kinds": []any{"deployment"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &DiffEnvironmentsInput{
+		A:     DiffEnvironment{Namespace: "shop", Context: "staging"},
+		B:     DiffEnvironment{Namespace: "shop", Context: "prod"},
+		Kinds: []string{"Deployment"},
+	}, input)
+
+	_, err = parseAndValidateDiffEnvironmentsParams(map[string]any{"namespaceA": "shop"})
+	assert.EqualError(t, err, "namespaceA and namespaceB must differ unless contextA and contextB do")
+
+	_, err = parseAndValidateDiffEnvironmentsParams(map[string]any{"namespaceA": "a", "namespaceB": "b", "kinds": []any{"Secret"}})
+	assert.ErrorContains(t, err, "unsupported kind")
+}
+
+func TestDiffValue(t *testing.T) {
+	assert.Equal(t, "short", diffValue("short"))
+	long := diffValue(string(make([]byte, 200)))
+	assert.Contains(t, long, "(200 bytes, sha256 ")
+}
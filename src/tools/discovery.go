@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// RefreshDiscoveryTool drops the cached API discovery so newly installed CRDs and API groups are seen.
+type RefreshDiscoveryTool struct {
+	client Client
+}
+
+// NewRefreshDiscoveryTool creates a new refresh_discovery tool.
+func NewRefreshDiscoveryTool(client Client) *RefreshDiscoveryTool {
+	return &RefreshDiscoveryTool{client: client}
+}
+
+// Tool returns the MCP tool definition for refresh_discovery.
+func (r *RefreshDiscoveryTool) Tool() mcp.Tool {
+	return mcp.NewTool("refresh_discovery",
+		mcp.WithDescription("Re-fetch the resource types the API server serves. Discovery is cached across tool calls (DISCOVERY_CACHE_TTL); call this after installing CRDs or API services when a kind is reported as unknown"),
+	)
+}
+
+// Handler invalidates the discovery cache, fetches it again and summarizes the API groups found.
+func (r *RefreshDiscoveryTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	discoClient, err := r.client.DiscoClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	cached, ok := discoClient.(discovery.CachedDiscoveryInterface)
+	if ok {
+		cached.Invalidate()
+	}
+
+	apiResourceLists, err := discoClient.ServerPreferredResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
+	}
+	groups := map[string]bool{}
+	resources := 0
+	for _, list := range apiResourceLists {
+		if list == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		group := gv.Group
+		if group == "" {
+			group = "core"
+		}
+		groups[group] = true
+		resources += len(list.APIResources)
+	}
+	groupNames := make([]string, 0, len(groups))
+	for group := range groups {
+		groupNames = append(groupNames, group)
+	}
+	sort.Strings(groupNames)
+
+	out, err := json.Marshal(map[string]any{
+		"invalidated": ok,
+		"resources":   resources,
+		"groups":      groupNames,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
@@ -0,0 +1,16 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshDiscoveryHandler(t *testing.T) {
+	tool := NewRefreshDiscoveryTool(fakeIngressClient{})
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"invalidated":true,"resources":1,"groups":["networking.k8s.io"]}`, result.Content[0].(mcp.TextContent).Text)
+}
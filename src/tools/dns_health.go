@@ -0,0 +1,438 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// clusterDNSLabel selects the cluster DNS Deployment and Service, for CoreDNS and kube-dns alike.
+	clusterDNSLabel = "k8s-app=kube-dns"
+	// coreDNSMetricsPort is the port of CoreDNS's prometheus plugin.
+	coreDNSMetricsPort = "9153"
+)
+
+// corefilePluginChecks are plugins whose absence from the root server block is an anomaly.
+var corefilePluginChecks = []struct {
+	plugin  string
+	problem string
+}{
+	{"kubernetes", "no kubernetes plugin: cluster Service and pod names do not resolve"},
+	{"forward", "no forward plugin: names outside the cluster do not resolve"},
+	{"cache", "no cache plugin: every query is resolved again, raising latency and upstream load"},
+	{"loop", "no loop plugin: a forwarding loop makes CoreDNS spin instead of failing fast"},
+	{"health", "no health plugin: the liveness probe on :8080/health fails"},
+	{"ready", "no ready plugin: the readiness probe on :8181/ready fails"},
+	{"errors", "no errors plugin: resolution errors are not logged"},
+	{"prometheus", "no prometheus plugin: CoreDNS metrics are not exported"},
+}
+
+// CoreDNSPod is the state of one cluster DNS pod.
+type CoreDNSPod struct {
+	Name     string `json:"name"`
+	Node     string `json:"node"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+	Problem  string `json:"problem,omitempty"`
+}
+
+// CoreDNSMetrics are totals from the CoreDNS metrics endpoint since the pod started.
+type CoreDNSMetrics struct {
+	Pod                      string             `json:"pod"`
+	Requests                 float64            `json:"requests"`
+	Responses                map[string]float64 `json:"responsesByRcode,omitempty"`
+	ServFailPercent          float64            `json:"servfailPercent"`
+	CacheHitPercent          float64            `json:"cacheHitPercent,omitempty"`
+	ForwardHealthcheckBroken float64            `json:"forwardHealthcheckBroken,omitempty"`
+	Panics                   float64            `json:"panics,omitempty"`
+}
+
+// DNSHealthInput represents the input for dns_health.
+type DNSHealthInput struct {
+	Namespace      string        `json:"namespace"`
+	Lookup         string        `json:"lookup,omitempty"`
+	ProbeNamespace string        `json:"probeNamespace"`
+	ProbeImage     string        `json:"probeImage"`
+	ProbeTimeout   time.Duration `json:"probeTimeout"`
+}
+
+// DNSHealthTool checks the cluster DNS Deployment, Service, Corefile and metrics.
+type DNSHealthTool struct {
+	client Client
+}
+
+// NewDNSHealthTool creates a new DNSHealthTool with the provided Kubernetes client.
+func NewDNSHealthTool(client Client) *DNSHealthTool {
+	return &DNSHealthTool{client: client}
+}
+
+// Tool returns the MCP tool definition for dns_health.
+func (d *DNSHealthTool) Tool() mcp.Tool {
+	return mcp.NewTool("dns_health",
+		mcp.WithDescription("Check cluster DNS (CoreDNS or kube-dns): replicas and pod health, whether all replicas share one node, the kube-dns Service endpoints, anomalies in the Corefile (missing kubernetes, forward, cache, loop, health or ready plugins, query logging, unbalanced braces), and request, SERVFAIL and cache hit counts from the metrics endpoint. Optionally resolves a name from a short-lived probe pod. DNS is a common cause of intermittent failures between services"),
+		mcp.WithString("lookup",
+			mcp.Description("Name to resolve from inside the cluster, e.g. my-svc.my-namespace or example.com; runs a short-lived nslookup pod"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the cluster DNS deployment (default: kube-system)"),
+		),
+		mcp.WithString("probeNamespace",
+			mcp.Description("Namespace to run the lookup pod in (default: default)"),
+		),
+		mcp.WithString("probeImage",
+			mcp.Description(fmt.Sprintf("Image of the lookup pod; only the server's DNS_PROBE_IMAGE is accepted (default: %s)", defaultDNSProbeImage)),
+		),
+	)
+}
+
+// Handler gathers the DNS components and reports their state and the problems found.
+func (d *DNSHealthTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateDNSHealthParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate dns_health params: %w", err)
+	}
+	// The lookup pod is created outside the namespace argument, so its namespace is authorized here.
+	if input.Lookup != "" {
+		if err := auth.AuthorizeNamespace(ctx, input.ProbeNamespace); err != nil {
+			return nil, fmt.Errorf("invalid probeNamespace: %w", err)
+		}
+	}
+	clientset, err := d.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	result := map[string]any{"namespace": input.Namespace}
+	var problems []string
+
+	deployments, err := clientset.AppsV1().Deployments(input.Namespace).List(ctx, metav1.ListOptions{LabelSelector: clusterDNSLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DNS deployments: %w", err)
+	}
+	var pods []CoreDNSPod
+	var readyPods []string
+	if len(deployments.Items) == 0 {
+		problems = append(problems, fmt.Sprintf("no Deployment labeled %s in %s", clusterDNSLabel, input.Namespace))
+	} else {
+		deployment := &deployments.Items[0]
+		result["deployment"] = map[string]any{
+			"name":      deployment.Name,
+			"replicas":  deployment.Status.Replicas,
+			"ready":     deployment.Status.ReadyReplicas,
+			"available": deployment.Status.AvailableReplicas,
+			"images":    deploymentImages(deployment),
+		}
+		podList, err := clientset.CoreV1().Pods(input.Namespace).List(ctx, metav1.ListOptions{LabelSelector: clusterDNSLabel})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list DNS pods: %w", err)
+		}
+		pods = coreDNSPods(podList.Items, time.Now())
+		for _, p := range pods {
+			if p.Ready {
+				readyPods = append(readyPods, p.Name)
+			}
+		}
+		result["pods"] = pods
+		problems = append(problems, coreDNSPodProblems(deployment, pods)...)
+	}
+
+	if svc, err := clientset.CoreV1().Services(input.Namespace).Get(ctx, "kube-dns", metav1.GetOptions{}); err != nil {
+		problems = append(problems, fmt.Sprintf("kube-dns Service: %v", err))
+	} else {
+		service := map[string]any{"clusterIP": svc.Spec.ClusterIP}
+		if counts, err := serviceEndpointCounts(ctx, clientset, svc); err != nil {
+			service["endpointsError"] = err.Error()
+		} else {
+			service["endpoints"] = counts
+			if counts.Ready == 0 {
+				problems = append(problems, "the kube-dns Service has no ready endpoints: every lookup in the cluster fails")
+			}
+		}
+		result["service"] = service
+	}
+
+	cm, err := clientset.CoreV1().ConfigMaps(input.Namespace).Get(ctx, "coredns", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		result["corefile"] = "not found (not CoreDNS, or configured elsewhere)"
+	case err != nil:
+		result["corefile"] = fmt.Sprintf("failed to read: %v", err)
+	default:
+		anomalies := corefileAnomalies(cm.Data["Corefile"])
+		result["corefileAnomalies"] = anomalies
+		problems = append(problems, anomalies...)
+	}
+
+	if len(readyPods) > 0 {
+		metrics, err := coreDNSMetrics(ctx, clientset, input.Namespace, readyPods[0])
+		if err != nil {
+			result["metricsError"] = err.Error()
+		} else {
+			result["metrics"] = metrics
+			if metrics.ServFailPercent >= 1 {
+				problems = append(problems, fmt.Sprintf("%.1f%% of responses from %s are SERVFAIL: upstream resolvers are failing or unreachable", metrics.ServFailPercent, metrics.Pod))
+			}
+			if metrics.ForwardHealthcheckBroken > 0 {
+				problems = append(problems, fmt.Sprintf("all upstream resolvers of %s were unhealthy %.0f times", metrics.Pod, metrics.ForwardHealthcheckBroken))
+			}
+			if metrics.Panics > 0 {
+				problems = append(problems, fmt.Sprintf("%s recovered from %.0f panics", metrics.Pod, metrics.Panics))
+			}
+		}
+	}
+
+	if input.Lookup != "" {
+		probe := &CheckServiceInput{Namespace: input.ProbeNamespace, ProbeImage: input.ProbeImage, ProbeTimeout: input.ProbeTimeout}
+		lookup, err := probeServiceDNS(ctx, clientset, probe, input.Lookup)
+		if err != nil {
+			result["lookup"] = map[string]any{"name": input.Lookup, "error": err.Error()}
+			problems = append(problems, fmt.Sprintf("lookup of %s failed: %v", input.Lookup, err))
+		} else {
+			lookup["name"] = input.Lookup
+			result["lookup"] = lookup
+			if resolved, _ := lookup["resolved"].(bool); !resolved {
+				problems = append(problems, fmt.Sprintf("%s did not resolve from inside the cluster", input.Lookup))
+			}
+		}
+	}
+
+	result["healthy"] = len(problems) == 0
+	result["problems"] = problems
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// deploymentImages returns the container images of a Deployment's pod template.
+func deploymentImages(deployment *appsv1.Deployment) []string {
+	var images []string
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// coreDNSPods summarizes the DNS pods.
+func coreDNSPods(items []corev1.Pod, now time.Time) []CoreDNSPod {
+	pods := make([]CoreDNSPod, 0, len(items))
+	for i := range items {
+		pod := &items[i]
+		p := CoreDNSPod{Name: pod.Name, Node: pod.Spec.NodeName, Ready: podReady(pod)}
+		for _, cs := range pod.Status.ContainerStatuses {
+			p.Restarts += cs.RestartCount
+		}
+		if reason, message := podProblem(pod, now); reason != "" {
+			p.Problem = strings.TrimSuffix(reason+": "+message, ": ")
+		}
+		pods = append(pods, p)
+	}
+	return pods
+}
+
+// coreDNSPodProblems reports missing replicas, failing pods and replicas that share a single node.
+func coreDNSPodProblems(deployment *appsv1.Deployment, pods []CoreDNSPod) []string {
+	var problems []string
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	if deployment.Status.ReadyReplicas < desired {
+		problems = append(problems, fmt.Sprintf("%d of %d DNS replicas are ready", deployment.Status.ReadyReplicas, desired))
+	}
+	if desired < 2 {
+		problems = append(problems, "a single DNS replica: every lookup fails while it restarts")
+	}
+	nodes := map[string]bool{}
+	for _, p := range pods {
+		if p.Problem != "" {
+			problems = append(problems, fmt.Sprintf("pod %s: %s", p.Name, p.Problem))
+		}
+		if p.Restarts > 0 {
+			problems = append(problems, fmt.Sprintf("pod %s restarted %d times", p.Name, p.Restarts))
+		}
+		if p.Node != "" {
+			nodes[p.Node] = true
+		}
+	}
+	if len(pods) > 1 && len(nodes) == 1 {
+		problems = append(problems, "all DNS replicas run on one node: losing it stops cluster DNS")
+	}
+	return problems
+}
+
+// corefileAnomalies checks the Corefile's syntax and the plugins of the server block for the root
+// zone, which answers both cluster and external names.
+func corefileAnomalies(corefile string) []string {
+	var anomalies []string
+	blocks := map[string][]string{}
+	var current []string
+	depth := 0
+	scanner := bufio.NewScanner(strings.NewReader(corefile))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if depth == 0 && strings.HasSuffix(line, "{") {
+			current = strings.Fields(strings.TrimSuffix(line, "{"))
+		} else if depth == 1 && len(current) > 0 {
+			plugin := strings.Fields(strings.TrimSuffix(line, "{"))
+			if len(plugin) > 0 && plugin[0] != "}" {
+				for _, zone := range current {
+					blocks[zone] = append(blocks[zone], plugin[0])
+				}
+			}
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth < 0 {
+			break
+		}
+	}
+	if depth != 0 {
+		anomalies = append(anomalies, "the Corefile has unbalanced braces and may not load")
+	}
+
+	var root []string
+	for zone, plugins := range blocks {
+		host := strings.TrimPrefix(strings.TrimPrefix(zone, "dns://"), ".")
+		if host == "" || strings.HasPrefix(host, ":") {
+			root = plugins
+		}
+	}
+	if root == nil {
+		return append(anomalies, "no server block for the root zone '.': only explicitly configured zones resolve")
+	}
+	for _, check := range corefilePluginChecks {
+		if !slices.Contains(root, check.plugin) && !(check.plugin == "forward" && slices.Contains(root, "proxy")) {
+			anomalies = append(anomalies, check.problem)
+		}
+	}
+	if slices.Contains(root, "proxy") {
+		anomalies = append(anomalies, "the proxy plugin was removed in CoreDNS 1.6; use forward")
+	}
+	if slices.Contains(root, "log") {
+		anomalies = append(anomalies, "the log plugin logs every query, which costs CPU and log volume on busy clusters")
+	}
+	return anomalies
+}
+
+// coreDNSMetrics reads the metrics of one DNS pod through the API server pod proxy.
+func coreDNSMetrics(ctx context.Context, clientset kubernetes.Interface, namespace, pod string) (*CoreDNSMetrics, error) {
+	body, err := clientset.CoreV1().Pods(namespace).ProxyGet("http", pod, coreDNSMetricsPort, "metrics", nil).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics of %s: %w", pod, err)
+	}
+	metrics := parseCoreDNSMetrics(body)
+	metrics.Pod = pod
+	return metrics, nil
+}
+
+// parseCoreDNSMetrics sums the CoreDNS counters in a Prometheus text exposition.
+func parseCoreDNSMetrics(body []byte) *CoreDNSMetrics {
+	metrics := &CoreDNSMetrics{Responses: map[string]float64{}}
+	var hits, misses float64
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "coredns_") {
+			continue
+		}
+		valueStart := strings.LastIndex(line, " ")
+		if valueStart < 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(line[valueStart+1:], 64)
+		if err != nil {
+			continue
+		}
+		name, labels := strings.TrimSpace(line[:valueStart]), map[string]string{}
+		if open := strings.Index(name, "{"); open >= 0 {
+			labels = parseMetricLabels(strings.TrimSuffix(name[open+1:], "}"))
+			name = name[:open]
+		}
+		switch name {
+		case "coredns_dns_requests_total":
+			metrics.Requests += value
+		case "coredns_dns_responses_total":
+			metrics.Responses[labels["rcode"]] += value
+		case "coredns_cache_hits_total":
+			hits += value
+		case "coredns_cache_misses_total":
+			misses += value
+		case "coredns_forward_healthcheck_broken_total":
+			metrics.ForwardHealthcheckBroken += value
+		case "coredns_panics_total":
+			metrics.Panics += value
+		}
+	}
+	var responses float64
+	for _, v := range metrics.Responses {
+		responses += v
+	}
+	if responses > 0 {
+		metrics.ServFailPercent = percentOf(int64(metrics.Responses["SERVFAIL"]), int64(responses))
+	}
+	if hits+misses > 0 {
+		metrics.CacheHitPercent = percentOf(int64(hits), int64(hits+misses))
+	}
+	return metrics
+}
+
+func parseAndValidateDNSHealthParams(args map[string]any) (*DNSHealthInput, error) {
+	input := &DNSHealthInput{
+		Namespace:      metav1.NamespaceSystem,
+		ProbeNamespace: metav1.NamespaceDefault,
+		ProbeImage:     dnsProbeImage(),
+		ProbeTimeout:   60 * time.Second,
+	}
+	if ns, ok := args["namespace"].(string); ok && ns != "" {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = ns
+	}
+	if ns, ok := args["probeNamespace"].(string); ok && ns != "" {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid probeNamespace: %w", err)
+		}
+		input.ProbeNamespace = ns
+	}
+	if image, ok := args["probeImage"].(string); ok && image != "" {
+		if err := validateProbeImage(image); err != nil {
+			return nil, err
+		}
+	}
+	if lookup, ok := args["lookup"].(string); ok && lookup != "" {
+		// The name becomes an nslookup argument, so only host name characters are accepted.
+		for _, r := range lookup {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.' || r == '_') {
+				return nil, fmt.Errorf("invalid lookup %q: must be a host name", lookup)
+			}
+		}
+		if strings.HasPrefix(lookup, "-") {
+			return nil, fmt.Errorf("invalid lookup %q: must be a host name", lookup)
+		}
+		input.Lookup = lookup
+	}
+	return input, nil
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultCorefile = `.:53 {
+    errors
+    health {
+       lameduck 5s
+    }
+    ready
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+       pods insecure
+       fallthrough in-addr.arpa ip6.arpa
+       ttl 30
+    }
+    prometheus :9153
+    forward . /etc/resolv.conf {
+       max_concurrent 1000
+    }
+    cache 30
+    loop
+    reload
+    loadbalance
+}
+`
+
+func TestCorefileAnomalies(t *testing.T) {
+	assert.Empty(t, corefileAnomalies(defaultCorefile))
+
+	assert.Equal(t, []string{
+		"no forward plugin: names outside the cluster do not resolve",
+		"no cache plugin: every query is resolved again, raising latency and upstream load",
+		"no loop plugin: a forwarding loop makes CoreDNS spin instead of failing fast",
+		"no prometheus plugin: CoreDNS metrics are not exported",
+		"the log plugin logs every query, which costs CPU and log volume on busy clusters",
+	}, corefileAnomalies(`# custom
+example.com:53 {
+    forward . 10.0.0.10
+}
+.:53 {
+    errors
+    log
+    health
+    ready
+    kubernetes cluster.local
+}
+`))
+
+	assert.Equal(t, []string{
+		"the Corefile has unbalanced braces and may not load",
+		"no server block for the root zone '.': only explicitly configured zones resolve",
+	}, corefileAnomalies("cluster.local:53 {\n    kubernetes cluster.local {\n"))
+}
+
+func TestParseCoreDNSMetrics(t *testing.T) {
+	body := []byte(`# HELP coredns_dns_requests_total Counter of DNS requests made per zone, protocol and family.
+# TYPE coredns_dns_requests_total counter
+coredns_dns_requests_total{family="1",proto="udp",server="dns://:53",type="A",zone="."} 900
+coredns_dns_requests_total{family="1",proto="udp",server="dns://:53",type="AAAA",zone="."} 100
+coredns_dns_responses_total{plugin="kubernetes",rcode="NOERROR",server="dns://:53",zone="."} 850
+coredns_dns_responses_total{plugin="forward",rcode="SERVFAIL",server="dns://:53",zone="."} 50
+coredns_dns_responses_total{plugin="kubernetes",rcode="NXDOMAIN",server="dns://:53",zone="."} 100
+coredns_cache_hits_total{server="dns://:53",type="success",zone="."} 600
+coredns_cache_misses_total{server="dns://:53",zone="."} 400
+coredns_forward_healthcheck_broken_total 2
+coredns_panics_total 0
+go_goroutines 42
+`)
+
+	metrics := parseCoreDNSMetrics(body)
+	assert.Equal(t, float64(1000), metrics.Requests)
+	assert.Equal(t, map[string]float64{"NOERROR": 850, "SERVFAIL": 50, "NXDOMAIN": 100}, metrics.Responses)
+	assert.Equal(t, 5.0, metrics.ServFailPercent)
+	assert.Equal(t, 60.0, metrics.CacheHitPercent)
+	assert.Equal(t, float64(2), metrics.ForwardHealthcheckBroken)
+	assert.Zero(t, metrics.Panics)
+}
+
+func TestCoreDNSPodProblems(t *testing.T) {
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+	pods := []CoreDNSPod{
+		{Name: "coredns-a", Node: "node-1", Ready: true},
+		{Name: "coredns-b", Node: "node-1", Restarts: 4, Problem: "CrashLoopBackOff: container coredns: back-off"},
+	}
+
+	assert.Equal(t, []string{
+		"1 of 2 DNS replicas are ready",
+		"pod coredns-b: CrashLoopBackOff: container coredns: back-off",
+		"pod coredns-b restarted 4 times",
+		"all DNS replicas run on one node: losing it stops cluster DNS",
+	}, coreDNSPodProblems(deployment, pods))
+}
+
+func TestParseAndValidateDNSHealthParams(t *testing.T) {
+	input, err := parseAndValidateDNSHealthParams(map[string]any{})
+	assert.NoError(t, err)
+	assert.Equal(t, &DNSHealthInput{Namespace: metav1.NamespaceSystem, ProbeNamespace: metav1.NamespaceDefault, ProbeImage: defaultDNSProbeImage, ProbeTimeout: 60 * time.Second}, input)
+
+	input, err = parseAndValidateDNSHealthParams(map[string]any{"lookup": "api.payments.svc.cluster.local"})
+	assert.NoError(t, err)
+	assert.Equal(t, "api.payments.svc.cluster.local", input.Lookup)
+
+	_, err = parseAndValidateDNSHealthParams(map[string]any{"lookup": "example.com", "probeImage": "attacker/miner"})
+	assert.EqualError(t, err, "probeImage must be busybox:1.36; set DNS_PROBE_IMAGE to use another image")
+
+	t.Setenv("DNS_PROBE_IMAGE", "registry.example.com/tools/dnsutils:1.3")
+	input, err = parseAndValidateDNSHealthParams(map[string]any{"lookup": "example.com", "probeImage": "registry.example.com/tools/dnsutils:1.3"})
+	assert.NoError(t, err)
+	assert.Equal(t, "registry.example.com/tools/dnsutils:1.3", input.ProbeImage)
+
+	_, err = parseAndValidateDNSHealthParams(map[string]any{"lookup": "example.com; rm -rf /"})
+	assert.EqualError(t, err, `invalid lookup "example.com; rm -rf /": must be a host name`)
+	_, err = parseAndValidateDNSHealthParams(map[string]any{"lookup": "-debug"})
+	assert.Error(t, err)
+}
+
+func TestDNSHealthProbeNamespaceScope(t *testing.T) {
+	ctx := auth.WithClient(context.Background(), &auth.Client{Name: "payments", Namespaces: []string{"payments"}})
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"namespace": "payments", "lookup": "example.com", "probeNamespace": "kube-system"}
+
+	_, err := NewDNSHealthTool(nil).Handler(ctx, req)
+
+	assert.EqualError(t, err, "invalid probeNamespace: client payments is not allowed to access namespace kube-system")
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EventSummary is a compact view of a Kubernetes Event.
+type EventSummary struct {
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Object    string `json:"object,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Count     int32  `json:"count,omitempty"`
+	LastSeen  string `json:"lastSeen,omitempty"`
+}
+
+// listEvents returns events in the namespace, newest first. When kind and name are set only events
+// for that object are returned; warningsOnly restricts the result to Warning events.
+func listEvents(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string, warningsOnly bool) ([]EventSummary, error) {
+	selector := fields.Set{}
+	if kind != "" {
+		selector["involvedObject.kind"] = kind
+	}
+	if name != "" {
+		selector["involvedObject.name"] = name
+	}
+	if warningsOnly {
+		selector["type"] = corev1.EventTypeWarning
+	}
+	return listEventsMatching(ctx, clientset, namespace, selector)
+}
+
+// listEventsMatching returns the events in the namespace that match the field selector, newest first.
+func listEventsMatching(ctx context.Context, clientset kubernetes.Interface, namespace string, selector fields.Set) ([]EventSummary, error) {
+	events, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: selector.AsSelector().String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	items := events.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		return eventTime(&items[i]).After(eventTime(&items[j]))
+	})
+
+	summaries := make([]EventSummary, 0, len(items))
+	for i := range items {
+		ev := &items[i]
+		summary := EventSummary{
+			Type:      ev.Type,
+			Reason:    ev.Reason,
+			Message:   ev.Message,
+			Object:    ev.InvolvedObject.Kind + "/" + ev.InvolvedObject.Name,
+			Namespace: ev.InvolvedObject.Namespace,
+			Count:     ev.Count,
+		}
+		if t := eventTime(ev); !t.IsZero() {
+			summary.LastSeen = t.Format(time.RFC3339)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// eventTime returns the most recent timestamp recorded on an event.
+func eventTime(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	case !ev.FirstTimestamp.IsZero():
+		return ev.FirstTimestamp.Time
+	}
+	return ev.CreationTimestamp.Time
+}
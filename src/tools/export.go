@@ -0,0 +1,320 @@
+// Environment variables used by this tool:
+// Optional:
+//   EXPORT_DIR                     - Directory export_namespace may write bundles to; without it, bundles are only returned
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/redact"
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// exportSkippedResources are namespaced resources left out of a full export because the cluster
+// recreates them, they are short-lived, or they hold no configuration.
+var exportSkippedResources = map[string]bool{
+	"events":                    true,
+	"endpoints":                 true,
+	"endpointslices":            true,
+	"controllerrevisions":       true,
+	"leases":                    true,
+	"localsubjectaccessreviews": true,
+	"pods":                      true,
+	"replicasets":               true,
+	"bindings":                  true,
+	"podmetrics":                true,
+	"secrets":                   true,
+}
+
+// exportDroppedMetadata are metadata fields assigned by the API server.
+var exportDroppedMetadata = []string{"uid", "resourceVersion", "generation", "creationTimestamp", "selfLink", "managedFields", "ownerReferences", "finalizers", "deletionTimestamp", "deletionGracePeriodSeconds"}
+
+// exportDroppedAnnotations are annotations written by clients and controllers rather than users.
+var exportDroppedAnnotations = []string{"kubectl.kubernetes.io/last-applied-configuration", "deployment.kubernetes.io/revision"}
+
+// jobControllerLabels are the labels the Job controller adds to its selector and pod template.
+var jobControllerLabels = []string{"controller-uid", "job-name", "batch.kubernetes.io/controller-uid", "batch.kubernetes.io/job-name"}
+
+// ExportNamespaceInput represents the input for export_namespace.
+type ExportNamespaceInput struct {
+	Namespace      string   `json:"namespace"`
+	Kinds          []string `json:"kinds,omitempty"`
+	IncludeSecrets bool     `json:"includeSecrets,omitempty"`
+	Path           string   `json:"path,omitempty"`
+}
+
+// ExportNamespaceTool exports the configuration of a namespace as a multi-document YAML bundle.
+type ExportNamespaceTool struct {
+	client Client
+}
+
+// NewExportNamespaceTool creates a new ExportNamespaceTool with the provided Kubernetes client.
+func NewExportNamespaceTool(client Client) *ExportNamespaceTool {
+	return &ExportNamespaceTool{client: client}
+}
+
+// Tool returns the MCP tool definition for export_namespace.
+func (e *ExportNamespaceTool) Tool() mcp.Tool {
+	return mcp.NewTool("export_namespace",
+		mcp.WithDescription("Export the resources of a namespace as one multi-document YAML bundle for backup, migration or sharing a reproduction. Status, server-assigned metadata and objects owned by a controller are removed so the bundle can be applied to another cluster. Returns the bundle, or writes it to a file under EXPORT_DIR"),
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Namespace to export"),
+		),
+		mcp.WithArray("kinds",
+			mcp.Description("Kinds to export, e.g. [\"Deployment\", \"Service\", \"ConfigMap\"] (default: every namespaced resource type except events, endpoints, pods, ReplicaSets, leases and Secrets)"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("includeSecrets",
+			mcp.Description("Also export Secrets, with their keys but every value replaced by (redacted) (default: false)"),
+		),
+		mcp.WithString("path",
+			mcp.Description("File name, relative to EXPORT_DIR, to write the bundle to instead of returning it"),
+		),
+	)
+}
+
+// Handler gathers, cleans and serializes the namespace.
+func (e *ExportNamespaceTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateExportNamespaceParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate export_namespace params: %w", err)
+	}
+	var path string
+	if input.Path != "" {
+		if path, err = exportFilePath(input.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	matches, err := e.exportResources(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []unstructured.Unstructured
+	var skipped []string
+	counts := map[string]int{}
+	for _, match := range matches {
+		ri, err := e.client.ResourceInterface(*match.ToGroupVersionResource(), true, input.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resource interface: %w", err)
+		}
+		list, err := ri.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", match.apiRes.Name, err))
+			continue
+		}
+		for _, item := range list.Items {
+			if metav1.GetControllerOf(&item) != nil {
+				continue
+			}
+			cleanExportObject(&item)
+			objects = append(objects, item)
+			counts[item.GetKind()]++
+		}
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		if objects[i].GetKind() != objects[j].GetKind() {
+			return objects[i].GetKind() < objects[j].GetKind()
+		}
+		return objects[i].GetName() < objects[j].GetName()
+	})
+
+	bundle, err := exportBundle(input.Namespace, objects, skipped)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return mcp.NewToolResultText(bundle), nil
+	}
+
+	// Files leave the server without passing the result redaction, so they are scrubbed here.
+	bundle = redact.String(bundle)
+	if err := os.WriteFile(path, []byte(bundle), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+	out, err := json.Marshal(map[string]any{
+		"namespace": input.Namespace,
+		"path":      path,
+		"bytes":     len(bundle),
+		"objects":   len(objects),
+		"kinds":     counts,
+		"skipped":   skipped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// exportResources returns the resource types to export: the requested kinds, or every namespaced
+// type that can be listed and is not skipped.
+func (e *ExportNamespaceTool) exportResources(input *ExportNamespaceInput) ([]*gvrMatch, error) {
+	discoClient, err := e.client.DiscoClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	apiResourceLists, err := discoClient.ServerPreferredNamespacedResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
+	}
+
+	var matches []*gvrMatch
+	seen := map[string]bool{}
+	add := func(match *gvrMatch) {
+		if key := match.groupVersion + "/" + match.apiRes.Name; !seen[key] {
+			seen[key] = true
+			matches = append(matches, match)
+		}
+	}
+	if len(input.Kinds) > 0 {
+		for _, kind := range input.Kinds {
+			match, err := findGVRByKind(apiResourceLists, kind)
+			if err != nil {
+				return nil, err
+			}
+			if match.apiRes.Name == "secrets" && !input.IncludeSecrets {
+				return nil, fmt.Errorf("secrets are only exported with includeSecrets=true")
+			}
+			add(match)
+		}
+		return matches, nil
+	}
+
+	for _, list := range apiResourceLists {
+		if list == nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || exportSkippedResources[r.Name] || !slices.Contains(r.Verbs, "list") {
+				continue
+			}
+			add(newGvrMatch(&r, list.GroupVersion, true))
+		}
+	}
+	if input.IncludeSecrets {
+		if match, err := findGVRByKind(apiResourceLists, "secrets"); err == nil {
+			add(match)
+		}
+	}
+	return matches, nil
+}
+
+// cleanExportObject removes status, server-assigned metadata and cluster-specific fields.
+func cleanExportObject(obj *unstructured.Unstructured) {
+	delete(obj.Object, "status")
+	for _, field := range exportDroppedMetadata {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		for _, name := range exportDroppedAnnotations {
+			delete(annotations, name)
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		obj.SetAnnotations(annotations)
+	}
+
+	switch obj.GetKind() {
+	case "Service":
+		unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+		unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+		unstructured.RemoveNestedField(obj.Object, "spec", "healthCheckNodePort")
+	case "Pod":
+		unstructured.RemoveNestedField(obj.Object, "spec", "nodeName")
+	case "Job":
+		unstructured.RemoveNestedField(obj.Object, "spec", "selector")
+		for _, label := range jobControllerLabels {
+			unstructured.RemoveNestedField(obj.Object, "spec", "template", "metadata", "labels", label)
+		}
+	case "PersistentVolumeClaim":
+		unstructured.RemoveNestedField(obj.Object, "spec", "volumeName")
+	case "Secret":
+		for _, field := range []string{"data", "stringData"} {
+			data, ok, _ := unstructured.NestedMap(obj.Object, field)
+			if !ok {
+				continue
+			}
+			for key := range data {
+				data[key] = redact.Marker
+			}
+			_ = unstructured.SetNestedMap(obj.Object, data, field)
+		}
+	}
+}
+
+// exportBundle serializes the objects as YAML documents after a header comment.
+func exportBundle(namespace string, objects []unstructured.Unstructured, skipped []string) (string, error) {
+	var bundle strings.Builder
+	fmt.Fprintf(&bundle, "# Export of namespace %s at %s: %d objects\n", namespace, time.Now().UTC().Format(time.RFC3339), len(objects))
+	for _, s := range skipped {
+		fmt.Fprintf(&bundle, "# Skipped %s\n", strings.ReplaceAll(s, "\n", " "))
+	}
+	for _, obj := range objects {
+		doc, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		bundle.WriteString("---\n")
+		bundle.Write(doc)
+	}
+	return bundle.String(), nil
+}
+
+// exportFilePath resolves a bundle file name inside EXPORT_DIR, refusing paths that leave it.
+func exportFilePath(name string) (string, error) {
+	dir := os.Getenv("EXPORT_DIR")
+	if dir == "" {
+		return "", fmt.Errorf("writing exports is disabled: set EXPORT_DIR to the directory bundles may be written to")
+	}
+	if filepath.IsAbs(name) || !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid path %q: must be a relative file name inside EXPORT_DIR", name)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return path, nil
+}
+
+func parseAndValidateExportNamespaceParams(args map[string]any) (*ExportNamespaceInput, error) {
+	input := &ExportNamespaceInput{}
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	input.Namespace = namespace
+
+	kinds, err := stringSliceArg(args, "kinds")
+	if err != nil {
+		return nil, err
+	}
+	for _, kind := range kinds {
+		if err := validation.ValidateKind(kind); err != nil {
+			return nil, fmt.Errorf("invalid kind: %w", err)
+		}
+	}
+	input.Kinds = kinds
+
+	input.IncludeSecrets, _ = args["includeSecrets"].(bool)
+	input.Path, _ = args["path"].(string)
+	return input, nil
+}
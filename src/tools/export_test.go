@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+type fakeExportClient struct {
+	FakeKubernetesClient
+	objects []runtime.Object
+}
+
+func (f fakeExportClient) DiscoClient() (discovery.DiscoveryInterface, error) {
+	verbs := metav1.Verbs{"get", "list"}
+	return &fakeDiscoveryClient{apiResourceLists: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Kind: "Service", Name: "services", Namespaced: true, Verbs: verbs},
+			{Kind: "Secret", Name: "secrets", Namespaced: true, Verbs: verbs},
+			{Kind: "Event", Name: "events", Namespaced: true, Verbs: verbs},
+			{Kind: "Namespace", Name: "namespaces", Verbs: verbs},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Kind: "Deployment", Name: "deployments", Namespaced: true, Verbs: verbs},
+			{Kind: "Deployment", Name: "deployments/scale", Namespaced: true, Verbs: verbs},
+		}},
+	}}, nil
+}
+
+func (f fakeExportClient) ResourceInterface(gvr schema.GroupVersionResource, namespaced bool, ns string) (dynamic.ResourceInterface, error) {
+	listKinds := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "services"}:                   "ServiceList",
+		{Version: "v1", Resource: "secrets"}:                    "SecretList",
+		{Version: "v1", Resource: "events"}:                     "EventList",
+		{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+	}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, f.objects...)
+	return client.Resource(gvr).Namespace(ns), nil
+}
+
+func testExportObjects() []runtime.Object {
+	return []runtime.Object{
+		&unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1", "kind": "Deployment",
+			"metadata": map[string]any{
+				"name": "web", "namespace": "shop", "uid": "123", "resourceVersion": "9",
+				"annotations": map[string]any{"deployment.kubernetes.io/revision": "3"},
+			},
+			"spec":   map[string]any{"replicas": int64(2)},
+			"status": map[string]any{"readyReplicas": int64(2)},
+		}},
+		&unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1", "kind": "Service",
+			"metadata": map[string]any{"name": "web", "namespace": "shop"},
+			"spec":     map[string]any{"clusterIP": "10.0.0.1", "ports": []any{map[string]any{"port": int64(80)}}},
+		}},
+		&unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1", "kind": "Service",
+			"metadata": map[string]any{"name": "owned", "namespace": "shop", "ownerReferences": []any{map[string]any{
+				"apiVersion": "v1", "kind": "Foo", "name": "x", "uid": "1", "controller": true,
+			}}},
+		}},
+		&unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1", "kind": "Secret",
+			"metadata": map[string]any{"name": "db", "namespace": "shop"},
+			"data":     map[string]any{"password": "aHVudGVyMg=="},
+		}},
+		&unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1", "kind": "Service",
+			"metadata": map[string]any{"name": "other", "namespace": "dev"},
+		}},
+	}
+}
+
+func TestExportNamespaceHandler(t *testing.T) {
+	tool := NewExportNamespaceTool(fakeExportClient{objects: testExportObjects()})
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"namespace": "shop"}
+	result, err := tool.Handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	bundle := result.Content[0].(mcp.TextContent).Text
+	assert.True(t, strings.HasPrefix(bundle, "# Export of namespace shop at "))
+	assert.Contains(t, bundle, ": 2 objects\n")
+	docs := strings.Split(bundle, "---\n")[1:]
+	assert.Len(t, docs, 2)
+	assert.Equal(t, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: shop\nspec:\n  replicas: 2\n", docs[0])
+	assert.Equal(t, "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n  namespace: shop\nspec:\n  ports:\n  - port: 80\n", docs[1])
+
+	req.Params.Arguments = map[string]any{"namespace": "shop", "kinds": []any{"secrets"}, "includeSecrets": true}
+	result, err = tool.Handler(context.Background(), req)
+	assert.NoError(t, err)
+	bundle = result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, bundle, "password: (redacted)")
+	assert.NotContains(t, bundle, "aHVudGVyMg==")
+
+	req.Params.Arguments = map[string]any{"namespace": "shop", "kinds": []any{"secrets"}}
+	_, err = tool.Handler(context.Background(), req)
+	assert.EqualError(t, err, "secrets are only exported with includeSecrets=true")
+}
+
+func TestExportNamespaceToFile(t *testing.T) {
+	tool := NewExportNamespaceTool(fakeExportClient{objects: testExportObjects()})
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"namespace": "shop", "path": "shop/bundle.yaml"}
+	_, err := tool.Handler(context.Background(), req)
+	assert.ErrorContains(t, err, "set EXPORT_DIR")
+
+	dir := t.TempDir()
+	t.Setenv("EXPORT_DIR", dir)
+	req.Params.Arguments = map[string]any{"namespace": "shop", "path": "../bundle.yaml"}
+	_, err = tool.Handler(context.Background(), req)
+	assert.ErrorContains(t, err, "invalid path")
+
+	req.Params.Arguments = map[string]any{"namespace": "shop", "path": "shop/bundle.yaml"}
+	result, err := tool.Handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"objects":2`)
+	written, err := os.ReadFile(filepath.Join(dir, "shop", "bundle.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(written), "kind: Deployment")
+}
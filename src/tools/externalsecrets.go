@@ -0,0 +1,315 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+)
+
+// External Secrets Operator kinds; the served version (v1 or v1beta1) is resolved through the RESTMapper.
+var (
+	externalSecretGK     = schema.GroupKind{Group: "external-secrets.io", Kind: "ExternalSecret"}
+	secretStoreGK        = schema.GroupKind{Group: "external-secrets.io", Kind: "SecretStore"}
+	clusterSecretStoreGK = schema.GroupKind{Group: "external-secrets.io", Kind: "ClusterSecretStore"}
+)
+
+// externalSecretForceSyncAnnotation makes the operator refresh an ExternalSecret whenever its value changes.
+const externalSecretForceSyncAnnotation = "force-sync"
+
+// ExternalSecretStatus is the sync state of one ExternalSecret.
+type ExternalSecretStatus struct {
+	Name            string   `json:"name"`
+	Namespace       string   `json:"namespace"`
+	Store           string   `json:"store"`
+	Target          string   `json:"target"`
+	RefreshInterval string   `json:"refreshInterval,omitempty"`
+	RefreshTime     string   `json:"refreshTime,omitempty"`
+	Ready           string   `json:"ready"`
+	Reason          string   `json:"reason,omitempty"`
+	Message         string   `json:"message,omitempty"`
+	TargetExists    *bool    `json:"targetExists,omitempty"`
+	Issues          []string `json:"issues,omitempty"`
+
+	storeKind string
+	storeName string
+}
+
+// SecretStoreStatus is the readiness of a SecretStore or ClusterSecretStore.
+type SecretStoreStatus struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Provider  string `json:"provider"`
+	Ready     string `json:"ready"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// readyCondition returns the status, reason and message of an object's Ready condition.
+func readyCondition(obj *unstructured.Unstructured) (status, reason, message string) {
+	return objectCondition(obj, "Ready")
+}
+
+// objectCondition returns the status, reason and message of the condition with the given type, or
+// "Unknown" when the object does not report it.
+func objectCondition(obj *unstructured.Unstructured, conditionType string) (status, reason, message string) {
+	status = "Unknown"
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != conditionType {
+			continue
+		}
+		status, _ = cond["status"].(string)
+		reason, _ = cond["reason"].(string)
+		message, _ = cond["message"].(string)
+	}
+	return status, reason, message
+}
+
+// externalSecretStatus reads the store, target and Ready condition of an ExternalSecret.
+func externalSecretStatus(obj *unstructured.Unstructured) ExternalSecretStatus {
+	status := ExternalSecretStatus{Name: obj.GetName(), Namespace: obj.GetNamespace(), Target: obj.GetName()}
+	status.storeKind, _, _ = unstructured.NestedString(obj.Object, "spec", "secretStoreRef", "kind")
+	if status.storeKind == "" {
+		status.storeKind = secretStoreGK.Kind
+	}
+	status.storeName, _, _ = unstructured.NestedString(obj.Object, "spec", "secretStoreRef", "name")
+	status.Store = status.storeKind + "/" + status.storeName
+	if target, _, _ := unstructured.NestedString(obj.Object, "spec", "target", "name"); target != "" {
+		status.Target = target
+	}
+	status.RefreshInterval, _, _ = unstructured.NestedString(obj.Object, "spec", "refreshInterval")
+	status.RefreshTime, _, _ = unstructured.NestedString(obj.Object, "status", "refreshTime")
+	status.Ready, status.Reason, status.Message = readyCondition(obj)
+	return status
+}
+
+// secretStoreStatus reads the provider and Ready condition of a SecretStore or ClusterSecretStore.
+func secretStoreStatus(obj *unstructured.Unstructured) SecretStoreStatus {
+	status := SecretStoreStatus{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace(), Provider: "unknown"}
+	provider, _, _ := unstructured.NestedMap(obj.Object, "spec", "provider")
+	names := make([]string, 0, len(provider))
+	for name := range provider {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		status.Provider = names[0]
+	}
+	status.Ready, status.Reason, status.Message = readyCondition(obj)
+	return status
+}
+
+// ExternalSecretsStatusTool summarizes ExternalSecrets and the stores they read from.
+type ExternalSecretsStatusTool struct {
+	client Client
+}
+
+// NewExternalSecretsStatusTool creates a new ExternalSecretsStatusTool with the provided Kubernetes client.
+func NewExternalSecretsStatusTool(client Client) *ExternalSecretsStatusTool {
+	return &ExternalSecretsStatusTool{client: client}
+}
+
+// Tool returns the MCP tool definition for externalsecrets_status.
+func (e *ExternalSecretsStatusTool) Tool() mcp.Tool {
+	return mcp.NewTool("externalsecrets_status",
+		mcp.WithDescription("Summarize External Secrets Operator: sync state, last refresh time and last sync error of every ExternalSecret, whether its target Secret exists, and the provider and readiness of SecretStores and ClusterSecretStores; ExternalSecrets whose store is missing or not ready are flagged"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the ExternalSecrets and SecretStores (leave empty for all namespaces); ClusterSecretStores are always included"),
+		),
+		mcp.WithBoolean("onlyFailing",
+			mcp.Description("Only return ExternalSecrets and stores that are not Ready (default: false)"),
+		),
+	)
+}
+
+// Handler lists the ExternalSecrets and stores and cross-checks them.
+func (e *ExternalSecretsStatusTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	onlyFailing, _ := args["onlyFailing"].(bool)
+
+	list := func(gk schema.GroupKind, ns string) ([]unstructured.Unstructured, error) {
+		ri, err := groupKindResourceInterface(e.client, gk, ns)
+		if err != nil {
+			return nil, fmt.Errorf("%w (is External Secrets Operator installed?)", err)
+		}
+		items, err := ri.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gk.Kind, err)
+		}
+		return items.Items, nil
+	}
+	externalSecrets, err := list(externalSecretGK, namespace)
+	if err != nil {
+		return nil, err
+	}
+	storeItems, err := list(secretStoreGK, namespace)
+	if err != nil {
+		return nil, err
+	}
+	clusterStoreItems, err := list(clusterSecretStoreGK, "")
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := e.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	// Stores keyed by Kind/namespace/name; ClusterSecretStores have no namespace.
+	stores := map[string]SecretStoreStatus{}
+	storeStatuses := []SecretStoreStatus{}
+	for _, items := range [][]unstructured.Unstructured{clusterStoreItems, storeItems} {
+		for i := range items {
+			s := secretStoreStatus(&items[i])
+			stores[fmt.Sprintf("%s/%s/%s", s.Kind, s.Namespace, s.Name)] = s
+			if !onlyFailing || s.Ready != "True" {
+				storeStatuses = append(storeStatuses, s)
+			}
+		}
+	}
+
+	secretStatuses := []ExternalSecretStatus{}
+	counts := map[string]int{}
+	for i := range externalSecrets {
+		es := externalSecretStatus(&externalSecrets[i])
+
+		storeKey := fmt.Sprintf("%s/%s/%s", es.storeKind, es.Namespace, es.storeName)
+		if es.storeKind == clusterSecretStoreGK.Kind {
+			storeKey = fmt.Sprintf("%s//%s", es.storeKind, es.storeName)
+		}
+		switch store, ok := stores[storeKey]; {
+		case !ok:
+			es.Issues = append(es.Issues, fmt.Sprintf("%s does not exist", es.Store))
+		case store.Ready != "True":
+			es.Issues = append(es.Issues, fmt.Sprintf("%s is not ready: %s", es.Store, store.Message))
+		}
+
+		_, err := clientset.CoreV1().Secrets(es.Namespace).Get(ctx, es.Target, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			exists := false
+			es.TargetExists = &exists
+			es.Issues = append(es.Issues, fmt.Sprintf("target Secret %s has not been created", es.Target))
+		case err == nil:
+			exists := true
+			es.TargetExists = &exists
+		}
+
+		switch es.Ready {
+		case "True":
+			counts["synced"]++
+		case "False":
+			counts["failing"]++
+		default:
+			counts["unknown"]++
+		}
+		if onlyFailing && es.Ready == "True" && len(es.Issues) == 0 {
+			continue
+		}
+		secretStatuses = append(secretStatuses, es)
+	}
+	sort.SliceStable(secretStatuses, func(i, j int) bool {
+		return (secretStatuses[i].Ready != "True") && (secretStatuses[j].Ready == "True")
+	})
+
+	result := map[string]any{
+		"namespace":       namespace,
+		"externalSecrets": secretStatuses,
+		"stores":          storeStatuses,
+		"counts":          counts,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// ExternalSecretsRefreshTool forces an ExternalSecret to sync from its provider now.
+type ExternalSecretsRefreshTool struct {
+	client Client
+}
+
+// NewExternalSecretsRefreshTool creates a new ExternalSecretsRefreshTool with the provided Kubernetes client.
+func NewExternalSecretsRefreshTool(client Client) *ExternalSecretsRefreshTool {
+	return &ExternalSecretsRefreshTool{client: client}
+}
+
+// Tool returns the MCP tool definition for externalsecrets_refresh.
+func (e *ExternalSecretsRefreshTool) Tool() mcp.Tool {
+	return mcp.NewTool("externalsecrets_refresh",
+		mcp.WithDescription("Force an External Secrets Operator ExternalSecret to re-read its provider now instead of waiting for refreshInterval, by updating its force-sync annotation"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the ExternalSecret (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("externalSecret",
+			mcp.Required(),
+			mcp.Description("Name of the ExternalSecret to refresh"),
+		),
+	)
+}
+
+// Handler sets the force-sync annotation to the current time.
+func (e *ExternalSecretsRefreshTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	name, _ := args["externalSecret"].(string)
+	if name == "" {
+		return nil, errors.New("externalSecret must be provided")
+	}
+	if err := validation.ValidateResourceName(name); err != nil {
+		return nil, fmt.Errorf("invalid externalSecret name: %w", err)
+	}
+
+	ri, err := groupKindResourceInterface(e.client, externalSecretGK, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("%w (is External Secrets Operator installed?)", err)
+	}
+	now := time.Now()
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, externalSecretForceSyncAnnotation, strconv.FormatInt(now.Unix(), 10)))
+	obj, err := ri.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to annotate externalsecret %s/%s: %w", namespace, name, err)
+	}
+
+	es := externalSecretStatus(obj)
+	result := map[string]any{
+		"status":          "Refresh requested",
+		"externalSecret":  name,
+		"namespace":       namespace,
+		"target":          es.Target,
+		"lastRefreshTime": es.RefreshTime,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
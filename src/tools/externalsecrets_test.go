@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestExternalSecretStatus(t *testing.T) {
+	es := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "db", "namespace": "app"},
+		"spec": map[string]any{
+			"refreshInterval": "1h",
+			"secretStoreRef":  map[string]any{"name": "vault", "kind": "ClusterSecretStore"},
+			"target":          map[string]any{"name": "db-credentials"},
+		},
+		"status": map[string]any{
+			"refreshTime": "2025-01-01T00:00:00Z",
+			"conditions": []any{map[string]any{
+				"type": "Ready", "status": "False", "reason": "SecretSyncedError", "message": "could not get secret data from provider",
+			}},
+		},
+	}}
+
+	status := externalSecretStatus(es)
+	assert.Equal(t, "ClusterSecretStore/vault", status.Store)
+	assert.Equal(t, "db-credentials", status.Target)
+	assert.Equal(t, "False", status.Ready)
+	assert.Equal(t, "SecretSyncedError", status.Reason)
+	assert.Equal(t, "could not get secret data from provider", status.Message)
+
+	unstructured.RemoveNestedField(es.Object, "spec", "target")
+	unstructured.RemoveNestedField(es.Object, "spec", "secretStoreRef", "kind")
+	unstructured.RemoveNestedField(es.Object, "status")
+	status = externalSecretStatus(es)
+	assert.Equal(t, "SecretStore/vault", status.Store)
+	assert.Equal(t, "db", status.Target)
+	assert.Equal(t, "Unknown", status.Ready)
+}
+
+func TestSecretStoreStatus(t *testing.T) {
+	store := &unstructured.Unstructured{Object: map[string]any{
+		"kind":     "SecretStore",
+		"metadata": map[string]any{"name": "aws", "namespace": "app"},
+		"spec":     map[string]any{"provider": map[string]any{"aws": map[string]any{"service": "SecretsManager"}}},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Ready", "status": "True", "reason": "Valid"},
+		}},
+	}}
+
+	status := secretStoreStatus(store)
+	assert.Equal(t, "aws", status.Provider)
+	assert.Equal(t, "True", status.Ready)
+	assert.Equal(t, "Valid", status.Reason)
+}
@@ -0,0 +1,449 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ingressGK is the Ingress kind; the served version is resolved through the RESTMapper.
+var ingressGK = schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"}
+
+// FindRouteInput represents the input for find_route: a Service, or a URL, to look up.
+type FindRouteInput struct {
+	Service   string `json:"service,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Host      string `json:"-"`
+	Path      string `json:"-"`
+}
+
+// RouteMatch is an Ingress rule or HTTPRoute rule that exposes the Service or serves the URL.
+type RouteMatch struct {
+	Kind      string   `json:"kind"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Class     string   `json:"class,omitempty"`
+	Parents   []string `json:"parents,omitempty"`
+	Host      string   `json:"host"`
+	Match     string   `json:"match"`
+	Backends  []string `json:"backends"`
+
+	// hostScore and pathScore rank URL matches by specificity, the way controllers pick a rule.
+	hostScore int
+	pathScore int
+}
+
+// FindRouteTool finds the Ingresses and HTTPRoutes that expose a Service or serve a URL.
+type FindRouteTool struct {
+	client Client
+}
+
+// NewFindRouteTool creates a new find_route tool.
+func NewFindRouteTool(client Client) *FindRouteTool {
+	return &FindRouteTool{client: client}
+}
+
+// Tool returns the MCP tool definition for find_route.
+func (f *FindRouteTool) Tool() mcp.Tool {
+	return mcp.NewTool("find_route",
+		mcp.WithDescription("Reverse lookup of list_ingress_paths: find the Ingress rules and Gateway API HTTPRoute rules across namespaces that send traffic to a Service, or that serve an external URL (most specific match first), with their hosts, matches and backends"),
+		mcp.WithString("service",
+			mcp.Description("Service name to find the routes to"),
+		),
+		mcp.WithString("url",
+			mcp.Description("External URL or host and path to find the serving rules for, e.g. https://shop.example.com/api/cart or shop.example.com/api"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the Service, or for a URL the namespace to search (default: all namespaces)"),
+		),
+	)
+}
+
+// Handler searches Ingresses and HTTPRoutes for rules matching the Service or URL.
+func (f *FindRouteTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateFindRouteParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate find_route params: %w", err)
+	}
+
+	// Ingresses only reference Services in their own namespace; HTTPRoutes may reference others.
+	list := func(gk schema.GroupKind, namespace string) ([]unstructured.Unstructured, error) {
+		ri, err := groupKindResourceInterface(f.client, gk, namespace)
+		if err != nil {
+			return nil, err
+		}
+		items, err := ri.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gk.Kind, err)
+		}
+		return items.Items, nil
+	}
+	routeNamespace := input.Namespace
+	if input.Service != "" {
+		routeNamespace = ""
+	}
+
+	matches := []RouteMatch{}
+	var problems []string
+	ingresses, err := list(ingressGK, input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ingresses {
+		matches = append(matches, ingressRouteMatches(&ingresses[i], input)...)
+	}
+	routes, err := list(httpRouteGK, routeNamespace)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("HTTPRoutes not searched: %v", err))
+	}
+	for i := range routes {
+		matches = append(matches, httpRouteMatches(&routes[i], input)...)
+	}
+	sortRouteMatches(matches)
+
+	out, err := json.Marshal(map[string]any{
+		"query":    input,
+		"matches":  matches,
+		"problems": problems,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// ingressRouteMatches returns the rules of an Ingress, including its default backend, that send
+// traffic to the Service or serve the URL of the input.
+func ingressRouteMatches(ing *unstructured.Unstructured, input *FindRouteInput) []RouteMatch {
+	class, _, _ := unstructured.NestedString(ing.Object, "spec", "ingressClassName")
+	if class == "" {
+		class = ing.GetAnnotations()["kubernetes.io/ingress.class"]
+	}
+	newMatch := func(host, match string, backend map[string]any) RouteMatch {
+		if host == "" {
+			host = "*"
+		}
+		return RouteMatch{
+			Kind:      "Ingress",
+			Name:      ing.GetName(),
+			Namespace: ing.GetNamespace(),
+			Class:     class,
+			Host:      host,
+			Match:     match,
+			Backends:  []string{ingressBackendString(ing.GetNamespace(), backend)},
+		}
+	}
+
+	var result []RouteMatch
+	rules, _, _ := unstructured.NestedSlice(ing.Object, "spec", "rules")
+	for _, r := range rules {
+		rule, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		host, _ := rule["host"].(string)
+		paths, _, _ := unstructured.NestedSlice(rule, "http", "paths")
+		for _, p := range paths {
+			path, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			value, _ := path["path"].(string)
+			if value == "" {
+				value = "/"
+			}
+			pathType, _ := path["pathType"].(string)
+			if pathType == "" {
+				pathType = "ImplementationSpecific"
+			}
+			backend, _, _ := unstructured.NestedMap(path, "backend")
+			m := newMatch(host, pathType+" "+value, backend)
+
+			if input.Service != "" {
+				if ingressBackendService(backend) == input.Service {
+					result = append(result, m)
+				}
+				continue
+			}
+			hostScore, ok := routeHostScore(host, input.Host)
+			if !ok {
+				continue
+			}
+			pathScore, ok := routePathScore(pathType, value, input.Path)
+			if !ok {
+				continue
+			}
+			m.hostScore, m.pathScore = hostScore, pathScore
+			result = append(result, m)
+		}
+	}
+
+	if backend, found, _ := unstructured.NestedMap(ing.Object, "spec", "defaultBackend"); found {
+		m := newMatch("", "defaultBackend", backend)
+		m.pathScore = -1
+		if input.Service == "" || ingressBackendService(backend) == input.Service {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// httpRouteMatches returns the rules of an HTTPRoute that send traffic to the Service or serve the
+// URL of the input. Header, query and method matches are shown but not evaluated.
+func httpRouteMatches(route *unstructured.Unstructured, input *FindRouteInput) []RouteMatch {
+	hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	var parents []string
+	for _, parent := range httpRouteParentRefs(route) {
+		parents = append(parents, parent.Namespace+"/"+parent.Name)
+	}
+
+	host, hostScore := "*", 0
+	if len(hostnames) > 0 {
+		host = strings.Join(hostnames, ",")
+		if input.Service == "" {
+			matched := false
+			for _, h := range hostnames {
+				if score, ok := routeHostScore(h, input.Host); ok && (!matched || score > hostScore) {
+					matched, hostScore, host = true, score, h
+				}
+			}
+			if !matched {
+				return nil
+			}
+		}
+	}
+
+	var result []RouteMatch
+	rawRules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	for i, rule := range httpRouteRules(route) {
+		backends := make([]string, 0, len(rule.BackendRefs))
+		exposesService := false
+		for _, ref := range rule.BackendRefs {
+			backend := ref.Namespace + "/" + ref.Name
+			if ref.Port != 0 {
+				backend += fmt.Sprintf(":%d", ref.Port)
+			}
+			backends = append(backends, backend)
+			if ref.Kind == "Service" && ref.Name == input.Service && (input.Namespace == "" || ref.Namespace == input.Namespace) {
+				exposesService = true
+			}
+		}
+		m := RouteMatch{
+			Kind:      "HTTPRoute",
+			Name:      route.GetName(),
+			Namespace: route.GetNamespace(),
+			Parents:   parents,
+			Host:      host,
+			Match:     strings.Join(rule.Matches, " | "),
+			Backends:  backends,
+			hostScore: hostScore,
+		}
+
+		if input.Service != "" {
+			if exposesService {
+				result = append(result, m)
+			}
+			continue
+		}
+
+		var rawRule map[string]any
+		if i < len(rawRules) {
+			rawRule, _ = rawRules[i].(map[string]any)
+		}
+		pathScore, matched := httpRouteRulePathScore(rawRule, input.Path)
+		if matched {
+			m.pathScore = pathScore
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// httpRouteRulePathScore returns the best score of the path matches of an HTTPRoute rule; a rule
+// without matches matches every path.
+func httpRouteRulePathScore(rule map[string]any, path string) (int, bool) {
+	matches, _, _ := unstructured.NestedSlice(rule, "matches")
+	if len(matches) == 0 {
+		return routePathScore("PathPrefix", "/", path)
+	}
+	best, matched := 0, false
+	for _, m := range matches {
+		match, ok := m.(map[string]any)
+		if !ok {
+			continue
+		}
+		pathType, value := "PathPrefix", "/"
+		if v, found, _ := unstructured.NestedString(match, "path", "type"); found {
+			pathType = v
+		}
+		if v, found, _ := unstructured.NestedString(match, "path", "value"); found {
+			value = v
+		}
+		if score, ok := routePathScore(pathType, value, path); ok && (!matched || score > best) {
+			best, matched = score, true
+		}
+	}
+	return best, matched
+}
+
+// routeHostScore reports whether a rule host matches the requested host and how specifically: an
+// exact host beats a wildcard, which beats a rule without host.
+func routeHostScore(pattern, host string) (int, bool) {
+	pattern = strings.ToLower(pattern)
+	switch {
+	case pattern == "" || pattern == "*":
+		return 0, true
+	case pattern == host:
+		return 2, true
+	case strings.HasPrefix(pattern, "*."):
+		// A wildcard covers exactly one additional label.
+		suffix := pattern[1:]
+		if strings.HasSuffix(host, suffix) && !strings.Contains(strings.TrimSuffix(host, suffix), ".") && len(host) > len(suffix) {
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+// routePathScore reports whether a rule path matches the requested path and how specifically:
+// exact matches beat prefixes, and longer prefixes beat shorter ones. Prefix matching is per path
+// element, so /api matches /api/v1 but not /apis.
+func routePathScore(pathType, pattern, path string) (int, bool) {
+	switch pathType {
+	case "Exact":
+		if pattern == path {
+			return 100000 + len(pattern), true
+		}
+		return 0, false
+	case "RegularExpression":
+		re, err := regexp.Compile("^(?:" + pattern + ")")
+		if err == nil && re.MatchString(path) {
+			return len(pattern), true
+		}
+		return 0, false
+	case "ImplementationSpecific":
+		// Most controllers treat these as plain string prefixes.
+		if strings.HasPrefix(path, pattern) {
+			return len(pattern), true
+		}
+		return 0, false
+	}
+	prefix := strings.TrimSuffix(pattern, "/")
+	if prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/") {
+		return len(prefix), true
+	}
+	return 0, false
+}
+
+// sortRouteMatches orders matches from most to least specific, then by namespace and name.
+func sortRouteMatches(matches []RouteMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.hostScore != b.hostScore {
+			return a.hostScore > b.hostScore
+		}
+		if a.pathScore != b.pathScore {
+			return a.pathScore > b.pathScore
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+// ingressBackendService returns the Service name of an Ingress backend in either API version.
+func ingressBackendService(backend map[string]any) string {
+	if name, found, _ := unstructured.NestedString(backend, "service", "name"); found {
+		return name
+	}
+	name, _, _ := unstructured.NestedString(backend, "serviceName")
+	return name
+}
+
+// ingressBackendString renders an Ingress backend as namespace/service:port, or the resource it
+// points at.
+func ingressBackendString(namespace string, backend map[string]any) string {
+	if name := ingressBackendService(backend); name != "" {
+		port := ""
+		if n, found, _ := unstructured.NestedInt64(backend, "service", "port", "number"); found {
+			port = fmt.Sprintf(":%d", n)
+		} else if s, found, _ := unstructured.NestedString(backend, "service", "port", "name"); found {
+			port = ":" + s
+		} else if v, found, _ := unstructured.NestedFieldNoCopy(backend, "servicePort"); found {
+			port = fmt.Sprintf(":%v", v)
+		}
+		return namespace + "/" + name + port
+	}
+	if kind, found, _ := unstructured.NestedString(backend, "resource", "kind"); found {
+		name, _, _ := unstructured.NestedString(backend, "resource", "name")
+		return kind + " " + namespace + "/" + name
+	}
+	return ""
+}
+
+// parseRouteURL splits a URL, or a host with an optional path, into a lowercase host and a path.
+func parseRouteURL(raw string) (string, string, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", "", errors.New("invalid url: host is missing")
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return strings.ToLower(u.Hostname()), path, nil
+}
+
+func parseAndValidateFindRouteParams(args map[string]any) (*FindRouteInput, error) {
+	input := &FindRouteInput{}
+	if service, ok := args["service"].(string); ok {
+		input.Service = strings.TrimSpace(service)
+	}
+	if u, ok := args["url"].(string); ok {
+		input.URL = strings.TrimSpace(u)
+	}
+	if namespace, ok := args["namespace"].(string); ok {
+		input.Namespace = namespace
+	}
+
+	if (input.Service == "") == (input.URL == "") {
+		return nil, errors.New("exactly one of service or url must be provided")
+	}
+	if input.Service != "" {
+		if err := validation.ValidateResourceName(input.Service); err != nil {
+			return nil, fmt.Errorf("invalid service: %w", err)
+		}
+	}
+	if input.URL != "" {
+		host, path, err := parseRouteURL(input.URL)
+		if err != nil {
+			return nil, err
+		}
+		input.Host, input.Path = host, path
+	}
+	if input.Namespace != "" {
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	return input, nil
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func findRouteIngress() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "shop", "namespace": "prod"},
+		"spec": map[string]any{
+			"ingressClassName": "nginx",
+			"defaultBackend":   map[string]any{"service": map[string]any{"name": "fallback", "port": map[string]any{"number": int64(80)}}},
+			"rules": []any{
+				map[string]any{"host": "shop.example.com", "http": map[string]any{"paths": []any{
+					map[string]any{"path": "/", "pathType": "Prefix", "backend": map[string]any{"service": map[string]any{"name": "web", "port": map[string]any{"number": int64(80)}}}},
+					map[string]any{"path": "/api", "pathType": "Prefix", "backend": map[string]any{"service": map[string]any{"name": "api", "port": map[string]any{"name": "http"}}}},
+				}}},
+				map[string]any{"host": "*.example.com", "http": map[string]any{"paths": []any{
+					map[string]any{"path": "/", "pathType": "Prefix", "backend": map[string]any{"service": map[string]any{"name": "web", "port": map[string]any{"number": int64(80)}}}},
+				}}},
+			},
+		},
+	}}
+}
+
+func findRouteHTTPRoute() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "shop-api", "namespace": "edge"},
+		"spec": map[string]any{
+			"parentRefs": []any{map[string]any{"name": "public", "namespace": "gateways"}},
+			"hostnames":  []any{"shop.example.com"},
+			"rules": []any{
+				map[string]any{
+					"matches":     []any{map[string]any{"path": map[string]any{"type": "PathPrefix", "value": "/api/v2"}}},
+					"backendRefs": []any{map[string]any{"name": "api-v2", "namespace": "prod", "port": int64(8080)}},
+				},
+			},
+		},
+	}}
+}
+
+func TestParseAndValidateFindRouteParams(t *testing.T) {
+	input, err := parseAndValidateFindRouteParams(map[string]any{"url": "https://Shop.Example.com/api/cart?x=1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "shop.example.com", input.Host)
+	assert.Equal(t, "/api/cart", input.Path)
+
+	input, err = parseAndValidateFindRouteParams(map[string]any{"url": "shop.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/", input.Path)
+
+	_, err = parseAndValidateFindRouteParams(map[string]any{})
+	assert.ErrorContains(t, err, "exactly one of service or url")
+	_, err = parseAndValidateFindRouteParams(map[string]any{"service": "web", "url": "shop.example.com"})
+	assert.ErrorContains(t, err, "exactly one of service or url")
+	_, err = parseAndValidateFindRouteParams(map[string]any{"service": "Web_1"})
+	assert.ErrorContains(t, err, "invalid service")
+}
+
+func TestRoutePathScore(t *testing.T) {
+	_, ok := routePathScore("Prefix", "/api", "/api/v1")
+	assert.True(t, ok)
+	_, ok = routePathScore("Prefix", "/api", "/apis")
+	assert.False(t, ok)
+	_, ok = routePathScore("Prefix", "/api/", "/api")
+	assert.True(t, ok)
+	_, ok = routePathScore("Exact", "/api", "/api/v1")
+	assert.False(t, ok)
+	_, ok = routePathScore("RegularExpression", "/users/[0-9]+", "/users/42/orders")
+	assert.True(t, ok)
+	exact, _ := routePathScore("Exact", "/api", "/api")
+	prefix, _ := routePathScore("PathPrefix", "/api", "/api")
+	assert.Greater(t, exact, prefix)
+}
+
+func TestRouteHostScore(t *testing.T) {
+	score, ok := routeHostScore("shop.example.com", "shop.example.com")
+	assert.True(t, ok)
+	assert.Equal(t, 2, score)
+	score, ok = routeHostScore("*.example.com", "shop.example.com")
+	assert.True(t, ok)
+	assert.Equal(t, 1, score)
+	_, ok = routeHostScore("*.example.com", "a.shop.example.com")
+	assert.False(t, ok)
+	_, ok = routeHostScore("other.example.com", "shop.example.com")
+	assert.False(t, ok)
+	score, ok = routeHostScore("", "shop.example.com")
+	assert.True(t, ok)
+	assert.Equal(t, 0, score)
+}
+
+func TestFindRouteByURL(t *testing.T) {
+	input, err := parseAndValidateFindRouteParams(map[string]any{"url": "https://shop.example.com/api/v2/cart"})
+	assert.NoError(t, err)
+
+	matches := append(ingressRouteMatches(findRouteIngress(), input), httpRouteMatches(findRouteHTTPRoute(), input)...)
+	sortRouteMatches(matches)
+
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Kind+" "+m.Host+" "+m.Match+" -> "+m.Backends[0])
+	}
+	assert.Equal(t, []string{
+		"HTTPRoute shop.example.com PathPrefix /api/v2 -> prod/api-v2:8080",
+		"Ingress shop.example.com Prefix /api -> prod/api:http",
+		"Ingress shop.example.com Prefix / -> prod/web:80",
+		"Ingress *.example.com Prefix / -> prod/web:80",
+		"Ingress * defaultBackend -> prod/fallback:80",
+	}, got)
+	assert.Equal(t, []string{"gateways/public"}, matches[0].Parents)
+	assert.Equal(t, "nginx", matches[1].Class)
+
+	input, _ = parseAndValidateFindRouteParams(map[string]any{"url": "other.example.org/api"})
+	assert.Empty(t, httpRouteMatches(findRouteHTTPRoute(), input))
+	assert.Len(t, ingressRouteMatches(findRouteIngress(), input), 1)
+}
+
+func TestFindRouteByService(t *testing.T) {
+	input, err := parseAndValidateFindRouteParams(map[string]any{"service": "web"})
+	assert.NoError(t, err)
+	matches := ingressRouteMatches(findRouteIngress(), input)
+	assert.Len(t, matches, 2)
+	assert.Equal(t, "shop.example.com", matches[0].Host)
+	assert.Equal(t, "*.example.com", matches[1].Host)
+
+	input, _ = parseAndValidateFindRouteParams(map[string]any{"service": "api-v2", "namespace": "prod"})
+	matches = httpRouteMatches(findRouteHTTPRoute(), input)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, "shop-api", matches[0].Name)
+
+	input, _ = parseAndValidateFindRouteParams(map[string]any{"service": "api-v2", "namespace": "staging"})
+	assert.Empty(t, httpRouteMatches(findRouteHTTPRoute(), input))
+}
@@ -0,0 +1,490 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// fluxReconcileAnnotation asks a Flux controller to reconcile an object outside its interval.
+const fluxReconcileAnnotation = "reconcile.fluxcd.io/requestedAt"
+
+// fluxNamespace is where the Flux CLI creates its objects by default.
+const fluxNamespace = "flux-system"
+
+// fluxKinds are the Flux kinds the tools act on, keyed by lowercase kind. The served version is
+// resolved through the RESTMapper so the tools work across Flux releases.
+var fluxKinds = map[string]schema.GroupKind{
+	"kustomization":  {Group: "kustomize.toolkit.fluxcd.io", Kind: "Kustomization"},
+	"helmrelease":    {Group: "helm.toolkit.fluxcd.io", Kind: "HelmRelease"},
+	"gitrepository":  {Group: "source.toolkit.fluxcd.io", Kind: "GitRepository"},
+	"helmrepository": {Group: "source.toolkit.fluxcd.io", Kind: "HelmRepository"},
+	"ocirepository":  {Group: "source.toolkit.fluxcd.io", Kind: "OCIRepository"},
+	"bucket":         {Group: "source.toolkit.fluxcd.io", Kind: "Bucket"},
+	"helmchart":      {Group: "source.toolkit.fluxcd.io", Kind: "HelmChart"},
+}
+
+// fluxKindNames lists the supported kinds in a stable order for tool descriptions and status reports.
+var fluxKindNames = []string{"Kustomization", "HelmRelease", "GitRepository", "HelmRepository", "OCIRepository", "Bucket", "HelmChart"}
+
+// FluxObjectInput represents the input for acting on a single Flux object.
+type FluxObjectInput struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	WithSource bool   `json:"withSource,omitempty"`
+}
+
+// FluxStatus summarizes the reconciliation state of one Flux object.
+type FluxStatus struct {
+	Kind                  string `json:"kind"`
+	Name                  string `json:"name"`
+	Namespace             string `json:"namespace"`
+	Ready                 string `json:"ready"`
+	Reason                string `json:"reason,omitempty"`
+	Message               string `json:"message,omitempty"`
+	Suspended             bool   `json:"suspended,omitempty"`
+	Source                string `json:"source,omitempty"`
+	LastAppliedRevision   string `json:"lastAppliedRevision,omitempty"`
+	LastAttemptedRevision string `json:"lastAttemptedRevision,omitempty"`
+	LastHandledReconcile  string `json:"lastHandledReconcileAt,omitempty"`
+}
+
+// fluxObjectRef identifies a Flux object, e.g. the source of a Kustomization.
+type fluxObjectRef struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+func (r fluxObjectRef) String() string {
+	return fmt.Sprintf("%s/%s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// fluxResourceInterface resolves a Flux kind to a resource interface in the given namespace.
+func fluxResourceInterface(client Client, kind, namespace string) (dynamic.ResourceInterface, error) {
+	gk, ok := fluxKinds[strings.ToLower(kind)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported Flux kind %q (supported: %s)", kind, strings.Join(fluxKindNames, ", "))
+	}
+	ri, err := groupKindResourceInterface(client, gk, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("%w (is Flux installed?)", err)
+	}
+	return ri, nil
+}
+
+// fluxReconcilePatch returns the merge patch 'flux reconcile' applies to request a reconciliation.
+func fluxReconcilePatch(now time.Time) []byte {
+	return []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, fluxReconcileAnnotation, now.Format(time.RFC3339Nano)))
+}
+
+// fluxSourceRef returns the source a Kustomization or HelmRelease pulls from, if any.
+func fluxSourceRef(obj *unstructured.Unstructured) (fluxObjectRef, bool) {
+	ref, found, _ := unstructured.NestedMap(obj.Object, "spec", "sourceRef")
+	if !found {
+		ref, found, _ = unstructured.NestedMap(obj.Object, "spec", "chart", "spec", "sourceRef")
+	}
+	if !found {
+		ref, found, _ = unstructured.NestedMap(obj.Object, "spec", "chartRef")
+	}
+	if !found {
+		return fluxObjectRef{}, false
+	}
+	kind, _ := ref["kind"].(string)
+	name, _ := ref["name"].(string)
+	namespace, _ := ref["namespace"].(string)
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+	if kind == "" || name == "" {
+		return fluxObjectRef{}, false
+	}
+	return fluxObjectRef{Kind: kind, Name: name, Namespace: namespace}, true
+}
+
+// fluxObjectStatus extracts the Ready condition, suspension and revisions of a Flux object.
+func fluxObjectStatus(obj *unstructured.Unstructured) FluxStatus {
+	status := FluxStatus{
+		Kind:      obj.GetKind(),
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Ready:     "Unknown",
+	}
+	status.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+	if ref, ok := fluxSourceRef(obj); ok {
+		status.Source = ref.String()
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		status.Ready, _ = cond["status"].(string)
+		status.Reason, _ = cond["reason"].(string)
+		status.Message, _ = cond["message"].(string)
+	}
+
+	status.LastAppliedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAppliedRevision")
+	if status.LastAppliedRevision == "" {
+		status.LastAppliedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "artifact", "revision")
+	}
+	if status.LastAppliedRevision == "" {
+		// helm.toolkit.fluxcd.io/v2 records applied releases in status.history, newest first.
+		if history, _, _ := unstructured.NestedSlice(obj.Object, "status", "history"); len(history) > 0 {
+			if latest, ok := history[0].(map[string]any); ok {
+				chart, _ := latest["chartName"].(string)
+				version, _ := latest["chartVersion"].(string)
+				if chart != "" || version != "" {
+					status.LastAppliedRevision = chart + "@" + version
+				}
+			}
+		}
+	}
+	status.LastAttemptedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAttemptedRevision")
+	status.LastHandledReconcile, _, _ = unstructured.NestedString(obj.Object, "status", "lastHandledReconcileAt")
+	return status
+}
+
+// FluxReconcileTool requests an immediate reconciliation of a Flux object.
+type FluxReconcileTool struct {
+	client Client
+}
+
+// NewFluxReconcileTool creates a new FluxReconcileTool with the provided Kubernetes client.
+func NewFluxReconcileTool(client Client) *FluxReconcileTool {
+	return &FluxReconcileTool{client: client}
+}
+
+// Tool returns the MCP tool definition for flux_reconcile.
+func (f *FluxReconcileTool) Tool() mcp.Tool {
+	return mcp.NewTool("flux_reconcile",
+		mcp.WithDescription("Trigger a FluxCD reconciliation now (like 'flux reconcile') by setting the reconcile.fluxcd.io/requestedAt annotation on a "+strings.Join(fluxKindNames, ", ")),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description("Flux kind of the object, e.g. Kustomization, HelmRelease or GitRepository"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Flux object"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the Flux object (defaults to 'flux-system' if not specified)"),
+		),
+		mcp.WithBoolean("withSource",
+			mcp.Description("For a Kustomization or HelmRelease, reconcile its source first (default: false)"),
+		),
+	)
+}
+
+// Handler annotates the object, and optionally its source, to request a reconciliation.
+func (f *FluxReconcileTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateFluxObjectParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate flux params: %w", err)
+	}
+
+	ri, err := fluxResourceInterface(f.client, input.Kind, input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := ri.Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", input.Kind, input.Namespace, input.Name, err)
+	}
+
+	now := time.Now()
+	result := map[string]any{
+		"kind":        obj.GetKind(),
+		"name":        input.Name,
+		"namespace":   input.Namespace,
+		"requestedAt": now.Format(time.RFC3339Nano),
+	}
+	if suspended, _, _ := unstructured.NestedBool(obj.Object, "spec", "suspend"); suspended {
+		result["warning"] = "object is suspended; the controller ignores reconcile requests until it is resumed"
+	}
+
+	if input.WithSource {
+		ref, ok := fluxSourceRef(obj)
+		if !ok {
+			return nil, fmt.Errorf("%s %s/%s has no sourceRef", obj.GetKind(), input.Namespace, input.Name)
+		}
+		sourceRI, err := fluxResourceInterface(f.client, ref.Kind, ref.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := sourceRI.Patch(ctx, ref.Name, types.MergePatchType, fluxReconcilePatch(now), metav1.PatchOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to annotate source %s: %w", ref, err)
+		}
+		result["source"] = ref.String()
+	}
+
+	if _, err := ri.Patch(ctx, input.Name, types.MergePatchType, fluxReconcilePatch(now), metav1.PatchOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to annotate %s: %w", input.Kind, err)
+	}
+	result["status"] = "Reconciliation requested"
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// FluxSuspendTool suspends (or resumes) reconciliation of a Flux object.
+type FluxSuspendTool struct {
+	client  Client
+	suspend bool
+}
+
+// NewFluxSuspendTool creates a tool that suspends a Flux object.
+func NewFluxSuspendTool(client Client) *FluxSuspendTool {
+	return &FluxSuspendTool{client: client, suspend: true}
+}
+
+// NewFluxResumeTool creates a tool that resumes a Flux object.
+func NewFluxResumeTool(client Client) *FluxSuspendTool {
+	return &FluxSuspendTool{client: client, suspend: false}
+}
+
+// Tool returns the MCP tool definition for flux_suspend or flux_resume.
+func (f *FluxSuspendTool) Tool() mcp.Tool {
+	name, description := "flux_resume", "Resume reconciliation of a suspended FluxCD object and request a reconciliation (like 'flux resume')"
+	if f.suspend {
+		name, description = "flux_suspend", "Suspend reconciliation of a FluxCD object by setting spec.suspend (like 'flux suspend'), e.g. to hold a manual hotfix in place"
+	}
+	return mcp.NewTool(name,
+		mcp.WithDescription(description),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description("Flux kind of the object, e.g. Kustomization, HelmRelease or GitRepository"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Flux object"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the Flux object (defaults to 'flux-system' if not specified)"),
+		),
+	)
+}
+
+// Handler patches spec.suspend on the object.
+func (f *FluxSuspendTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateFluxObjectParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate flux params: %w", err)
+	}
+
+	ri, err := fluxResourceInterface(f.client, input.Kind, input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, f.suspend))
+	if !f.suspend {
+		// Like 'flux resume', ask for a reconciliation so changes made while suspended are applied right away.
+		patch = []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}},"spec":{"suspend":false}}`, fluxReconcileAnnotation, time.Now().Format(time.RFC3339Nano)))
+	}
+	obj, err := ri.Patch(ctx, input.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch %s %s/%s: %w", input.Kind, input.Namespace, input.Name, err)
+	}
+
+	status := "Reconciliation resumed"
+	if f.suspend {
+		status = "Reconciliation suspended"
+	}
+	result := map[string]any{
+		"status":    status,
+		"kind":      obj.GetKind(),
+		"name":      input.Name,
+		"namespace": input.Namespace,
+		"suspend":   f.suspend,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// FluxStatusTool summarizes the readiness of Flux sources and deployers.
+type FluxStatusTool struct {
+	client Client
+}
+
+// NewFluxStatusTool creates a new FluxStatusTool with the provided Kubernetes client.
+func NewFluxStatusTool(client Client) *FluxStatusTool {
+	return &FluxStatusTool{client: client}
+}
+
+// Tool returns the MCP tool definition for flux_status.
+func (f *FluxStatusTool) Tool() mcp.Tool {
+	return mcp.NewTool("flux_status",
+		mcp.WithDescription("Summarize FluxCD objects ("+strings.Join(fluxKindNames, ", ")+"): Ready condition, failure reason and message, suspension, source reference and last applied/attempted revision, with failing objects listed first"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to report on (leave empty for all namespaces)"),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Only report this Flux kind, e.g. Kustomization (default: all kinds)"),
+		),
+		mcp.WithBoolean("onlyFailing",
+			mcp.Description("Only return objects that are not Ready or are suspended (default: false)"),
+		),
+	)
+}
+
+// Handler lists the Flux objects and reports their status.
+func (f *FluxStatusTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	onlyFailing, _ := args["onlyFailing"].(bool)
+	kinds := fluxKindNames
+	if kind, _ := args["kind"].(string); kind != "" {
+		gk, ok := fluxKinds[strings.ToLower(kind)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported Flux kind %q (supported: %s)", kind, strings.Join(fluxKindNames, ", "))
+		}
+		kinds = []string{gk.Kind}
+	}
+
+	statuses := []FluxStatus{}
+	unavailable := map[string]string{}
+	for _, kind := range kinds {
+		ri, err := fluxResourceInterface(f.client, kind, namespace)
+		if err != nil {
+			unavailable[kind] = err.Error()
+			continue
+		}
+		list, err := ri.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			unavailable[kind] = err.Error()
+			continue
+		}
+		for i := range list.Items {
+			status := fluxObjectStatus(&list.Items[i])
+			if onlyFailing && status.Ready == "True" && !status.Suspended {
+				continue
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	if len(unavailable) == len(kinds) {
+		return nil, errors.New("no Flux resources are served by the cluster, is Flux installed?")
+	}
+	sortFluxStatuses(statuses)
+
+	counts := map[string]int{}
+	for _, s := range statuses {
+		switch s.Ready {
+		case "True":
+			counts["ready"]++
+		case "False":
+			counts["notReady"]++
+		default:
+			counts["unknown"]++
+		}
+		if s.Suspended {
+			counts["suspended"]++
+		}
+	}
+	result := map[string]any{
+		"namespace": namespace,
+		"objects":   statuses,
+		"counts":    counts,
+	}
+	if len(unavailable) > 0 {
+		result["unavailableKinds"] = unavailable
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// sortFluxStatuses orders failing objects first, then unknown, suspended and ready ones, each by kind and name.
+func sortFluxStatuses(statuses []FluxStatus) {
+	rank := func(s FluxStatus) int {
+		switch {
+		case s.Ready == "False":
+			return 0
+		case s.Ready != "True":
+			return 1
+		case s.Suspended:
+			return 2
+		}
+		return 3
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		a, b := statuses[i], statuses[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+// parseAndValidateFluxObjectParams validates and parses the input parameters.
+func parseAndValidateFluxObjectParams(args map[string]any) (*FluxObjectInput, error) {
+	input := &FluxObjectInput{}
+
+	input.Kind, _ = args["kind"].(string)
+	if input.Kind == "" {
+		return nil, errors.New("kind must be provided")
+	}
+	if _, ok := fluxKinds[strings.ToLower(input.Kind)]; !ok {
+		return nil, fmt.Errorf("unsupported Flux kind %q (supported: %s)", input.Kind, strings.Join(fluxKindNames, ", "))
+	}
+
+	input.Name, _ = args["name"].(string)
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid name: %w", err)
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = fluxNamespace
+	}
+
+	if withSource, ok := args["withSource"].(bool); ok {
+		input.WithSource = withSource
+	}
+
+	return input, nil
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFluxObjectStatus(t *testing.T) {
+	ks := &unstructured.Unstructured{Object: map[string]any{
+		"kind":     "Kustomization",
+		"metadata": map[string]any{"name": "apps", "namespace": "flux-system"},
+		"spec": map[string]any{
+			"suspend":   true,
+			"sourceRef": map[string]any{"kind": "GitRepository", "name": "fleet"},
+		},
+		"status": map[string]any{
+			"lastAppliedRevision":   "main@sha1:abc",
+			"lastAttemptedRevision": "main@sha1:def",
+			"conditions": []any{
+				map[string]any{"type": "Reconciling", "status": "True"},
+				map[string]any{"type": "Ready", "status": "False", "reason": "BuildFailed", "message": "kustomization path not found"},
+			},
+		},
+	}}
+
+	status := fluxObjectStatus(ks)
+	assert.Equal(t, "False", status.Ready)
+	assert.Equal(t, "BuildFailed", status.Reason)
+	assert.Equal(t, "kustomization path not found", status.Message)
+	assert.True(t, status.Suspended)
+	assert.Equal(t, "GitRepository/flux-system/fleet", status.Source)
+	assert.Equal(t, "main@sha1:abc", status.LastAppliedRevision)
+	assert.Equal(t, "main@sha1:def", status.LastAttemptedRevision)
+
+	hr := &unstructured.Unstructured{Object: map[string]any{
+		"kind":     "HelmRelease",
+		"metadata": map[string]any{"name": "podinfo", "namespace": "apps"},
+		"spec": map[string]any{
+			"chart": map[string]any{"spec": map[string]any{
+				"sourceRef": map[string]any{"kind": "HelmRepository", "name": "podinfo", "namespace": "flux-system"},
+			}},
+		},
+		"status": map[string]any{
+			"history": []any{
+				map[string]any{"chartName": "podinfo", "chartVersion": "6.5.0"},
+				map[string]any{"chartName": "podinfo", "chartVersion": "6.4.0"},
+			},
+		},
+	}}
+
+	status = fluxObjectStatus(hr)
+	assert.Equal(t, "Unknown", status.Ready)
+	assert.Equal(t, "HelmRepository/flux-system/podinfo", status.Source)
+	assert.Equal(t, "podinfo@6.5.0", status.LastAppliedRevision)
+
+	repo := &unstructured.Unstructured{Object: map[string]any{
+		"kind":     "GitRepository",
+		"metadata": map[string]any{"name": "fleet", "namespace": "flux-system"},
+		"status":   map[string]any{"artifact": map[string]any{"revision": "main@sha1:abc"}},
+	}}
+	_, ok := fluxSourceRef(repo)
+	assert.False(t, ok)
+	assert.Equal(t, "main@sha1:abc", fluxObjectStatus(repo).LastAppliedRevision)
+}
+
+func TestSortFluxStatuses(t *testing.T) {
+	statuses := []FluxStatus{
+		{Kind: "Kustomization", Name: "ok", Ready: "True"},
+		{Kind: "Kustomization", Name: "paused", Ready: "True", Suspended: true},
+		{Kind: "HelmRelease", Name: "pending", Ready: "Unknown"},
+		{Kind: "Kustomization", Name: "broken", Ready: "False"},
+	}
+
+	sortFluxStatuses(statuses)
+
+	names := []string{}
+	for _, s := range statuses {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"broken", "pending", "paused", "ok"}, names)
+}
+
+func TestParseAndValidateFluxObjectParams(t *testing.T) {
+	input, err := parseAndValidateFluxObjectParams(map[string]any{"kind": "kustomization", "name": "apps"})
+	assert.NoError(t, err)
+	assert.Equal(t, "flux-system", input.Namespace)
+
+	_, err = parseAndValidateFluxObjectParams(map[string]any{"kind": "Deployment", "name": "web"})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateFluxObjectParams(map[string]any{"kind": "HelmRelease"})
+	assert.EqualError(t, err, "name must be provided")
+}
@@ -0,0 +1,428 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// FluxTreeNode is a Flux object or an object it manages, with the health of the live object.
+type FluxTreeNode struct {
+	Kind      string          `json:"kind"`
+	Name      string          `json:"name"`
+	Namespace string          `json:"namespace,omitempty"`
+	Status    string          `json:"status"`
+	Message   string          `json:"message,omitempty"`
+	Revision  string          `json:"revision,omitempty"`
+	Children  []*FluxTreeNode `json:"children,omitempty"`
+}
+
+// FluxTreeIssue is a managed object that is missing, failing or drifted.
+type FluxTreeIssue struct {
+	Object  string `json:"object"`
+	Owner   string `json:"owner"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// fluxManagedRef identifies an object applied by a Kustomization or HelmRelease.
+type fluxManagedRef struct {
+	Group     string
+	Version   string
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// fluxOwnership is the metadata a Flux owner stamps on every object it applies.
+type fluxOwnership struct {
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// fluxInventoryRef parses a Kustomization inventory entry, whose id has the form
+// <namespace>_<name>_<group>_<kind> with an empty namespace for cluster-scoped objects.
+func fluxInventoryRef(id, version string) (fluxManagedRef, error) {
+	parts := strings.Split(id, "_")
+	if len(parts) != 4 {
+		return fluxManagedRef{}, fmt.Errorf("malformed inventory id %q", id)
+	}
+	return fluxManagedRef{Namespace: parts[0], Name: parts[1], Group: parts[2], Kind: parts[3], Version: version}, nil
+}
+
+// decodeHelmRelease extracts the rendered manifest and namespace from the "release" key of a
+// Helm storage Secret.
+func decodeHelmRelease(data []byte) (manifest, namespace string, err error) {
+	release, err := decodeHelmRecord(data)
+	if err != nil {
+		return "", "", err
+	}
+	return release.Manifest, release.Namespace, nil
+}
+
+// fluxManagedHealth describes why a live object is unhealthy, or returns "".
+func fluxManagedHealth(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		condType, _ := cond["type"].(string)
+		status, _ := cond["status"].(string)
+		if ((condType == "Ready" || condType == "Available") && status == "False") || (condType == "Failed" && status == "True") {
+			msg := condType + "=" + status
+			for _, field := range []string{"reason", "message"} {
+				if v, _ := cond[field].(string); v != "" {
+					msg += ": " + v
+				}
+			}
+			return msg
+		}
+	}
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase == "Failed" {
+		return "phase Failed"
+	}
+	if replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		if ready < replicas {
+			return fmt.Sprintf("%d/%d replicas ready", ready, replicas)
+		}
+	}
+	return ""
+}
+
+// fluxOwnershipDrift reports when a live object no longer carries its owner's labels or annotations,
+// i.e. it was re-created, adopted or edited outside Flux. It returns "" when ownership matches.
+func fluxOwnershipDrift(obj *unstructured.Unstructured, owner fluxOwnership) string {
+	check := func(what string, actual, expected map[string]string) string {
+		keys := make([]string, 0, len(expected))
+		for k := range expected {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch v, ok := actual[k]; {
+			case !ok:
+				return fmt.Sprintf("%s %s missing", what, k)
+			case v != expected[k]:
+				return fmt.Sprintf("%s %s is %q, expected %q", what, k, v, expected[k])
+			}
+		}
+		return ""
+	}
+	if drift := check("label", obj.GetLabels(), owner.Labels); drift != "" {
+		return drift
+	}
+	return check("annotation", obj.GetAnnotations(), owner.Annotations)
+}
+
+// fluxTreeWalker walks Flux owners down to the objects they manage.
+type fluxTreeWalker struct {
+	client    Client
+	clientset kubernetes.Interface
+	mapper    meta.RESTMapper
+	seen      map[string]bool
+	issues    []FluxTreeIssue
+	counts    map[string]int
+}
+
+// owner builds the node for a Kustomization or HelmRelease and walks the objects it manages.
+func (w *fluxTreeWalker) owner(ctx context.Context, obj *unstructured.Unstructured) *FluxTreeNode {
+	status := fluxObjectStatus(obj)
+	node := &FluxTreeNode{
+		Kind:      obj.GetKind(),
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Status:    "ok",
+		Revision:  status.LastAppliedRevision,
+	}
+	switch {
+	case status.Suspended:
+		node.Status, node.Message = "suspended", "reconciliation suspended"
+	case status.Ready == "False":
+		node.Status, node.Message = "failing", strings.Trim(status.Reason+": "+status.Message, ": ")
+	case status.Ready != "True":
+		node.Status, node.Message = "progressing", status.Message
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", node.Kind, node.Namespace, node.Name)
+	if w.seen[key] {
+		return node
+	}
+	w.seen[key] = true
+
+	var refs []fluxManagedRef
+	var ownership fluxOwnership
+	var err error
+	switch node.Kind {
+	case "Kustomization":
+		refs, err = kustomizationInventory(obj)
+		ownership = fluxOwnership{Labels: map[string]string{
+			"kustomize.toolkit.fluxcd.io/name":      node.Name,
+			"kustomize.toolkit.fluxcd.io/namespace": node.Namespace,
+		}}
+	case "HelmRelease":
+		var releaseName, releaseNamespace string
+		refs, releaseName, releaseNamespace, err = w.helmReleaseObjects(ctx, obj)
+		ownership = fluxOwnership{Annotations: map[string]string{
+			"meta.helm.sh/release-name":      releaseName,
+			"meta.helm.sh/release-namespace": releaseNamespace,
+		}}
+	}
+	if err != nil {
+		if node.Message != "" {
+			node.Message += "; "
+		}
+		node.Message += err.Error()
+		return node
+	}
+
+	for _, ref := range refs {
+		node.Children = append(node.Children, w.managed(ctx, ref, ownership, key))
+	}
+	return node
+}
+
+// managed builds the node for an object applied by a Flux owner, recursing into nested owners.
+func (w *fluxTreeWalker) managed(ctx context.Context, ref fluxManagedRef, ownership fluxOwnership, ownerKey string) *FluxTreeNode {
+	node := &FluxTreeNode{Kind: ref.Kind, Name: ref.Name, Namespace: ref.Namespace, Status: "ok"}
+	defer func() {
+		w.counts[node.Status]++
+		if node.Status == "missing" || node.Status == "failing" || node.Status == "drifted" {
+			w.issues = append(w.issues, FluxTreeIssue{
+				Object:  fmt.Sprintf("%s/%s/%s", ref.Kind, ref.Namespace, ref.Name),
+				Owner:   ownerKey,
+				Status:  node.Status,
+				Message: node.Message,
+			})
+		}
+	}()
+
+	mapping, err := w.mapper.RESTMapping(schema.GroupKind{Group: ref.Group, Kind: ref.Kind}, ref.Version)
+	if err != nil {
+		node.Status, node.Message = "unknown", err.Error()
+		return node
+	}
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if !namespaced {
+		node.Namespace = ""
+	}
+	ri, err := w.client.ResourceInterface(mapping.Resource, namespaced, node.Namespace)
+	if err != nil {
+		node.Status, node.Message = "unknown", err.Error()
+		return node
+	}
+	obj, err := ri.Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		node.Status, node.Message = "missing", "object in the inventory does not exist in the cluster"
+		return node
+	}
+	if err != nil {
+		node.Status, node.Message = "unknown", err.Error()
+		return node
+	}
+
+	if isFluxOwnerKind(ref.Group, ref.Kind) {
+		nested := w.owner(ctx, obj)
+		node.Status, node.Message, node.Revision, node.Children = nested.Status, nested.Message, nested.Revision, nested.Children
+		return node
+	}
+	if msg := fluxManagedHealth(obj); msg != "" {
+		node.Status, node.Message = "failing", msg
+	} else if msg := fluxOwnershipDrift(obj, ownership); msg != "" {
+		node.Status, node.Message = "drifted", msg
+	}
+	return node
+}
+
+// isFluxOwnerKind reports whether a managed object is itself a Flux Kustomization or HelmRelease.
+func isFluxOwnerKind(group, kind string) bool {
+	return (group == fluxKinds["kustomization"].Group && kind == "Kustomization") ||
+		(group == fluxKinds["helmrelease"].Group && kind == "HelmRelease")
+}
+
+// kustomizationInventory returns the objects recorded in a Kustomization's status.inventory.
+func kustomizationInventory(obj *unstructured.Unstructured) ([]fluxManagedRef, error) {
+	entries, found, _ := unstructured.NestedSlice(obj.Object, "status", "inventory", "entries")
+	if !found {
+		return nil, fmt.Errorf("no inventory recorded yet")
+	}
+	refs := make([]fluxManagedRef, 0, len(entries))
+	for _, e := range entries {
+		entry, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, _ := entry["id"].(string)
+		version, _ := entry["v"].(string)
+		ref, err := fluxInventoryRef(id, version)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// helmReleaseObjects reads the manifest of the latest Helm release of a HelmRelease from its
+// storage Secret and returns the objects it contains, with the release name and namespace.
+func (w *fluxTreeWalker) helmReleaseObjects(ctx context.Context, hr *unstructured.Unstructured) ([]fluxManagedRef, string, string, error) {
+	releaseName, _, _ := unstructured.NestedString(hr.Object, "spec", "releaseName")
+	targetNamespace, _, _ := unstructured.NestedString(hr.Object, "spec", "targetNamespace")
+	storageNamespace, _, _ := unstructured.NestedString(hr.Object, "status", "storageNamespace")
+	if storageNamespace == "" {
+		storageNamespace, _, _ = unstructured.NestedString(hr.Object, "spec", "storageNamespace")
+	}
+	if storageNamespace == "" {
+		storageNamespace = hr.GetNamespace()
+	}
+	if releaseName == "" {
+		releaseName = hr.GetName()
+		if targetNamespace != "" {
+			releaseName = targetNamespace + "-" + releaseName
+		}
+	}
+	// helm.toolkit.fluxcd.io/v2 records the exact release name and version in status.history.
+	if history, _, _ := unstructured.NestedSlice(hr.Object, "status", "history"); len(history) > 0 {
+		if latest, ok := history[0].(map[string]any); ok {
+			if name, _ := latest["name"].(string); name != "" {
+				releaseName = name
+			}
+		}
+	}
+
+	secrets, err := w.clientset.CoreV1().Secrets(storageNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "owner=helm,name=" + releaseName,
+	})
+	if err != nil {
+		return nil, releaseName, "", fmt.Errorf("failed to list Helm release secrets: %w", err)
+	}
+	latest, latestVersion := -1, -1
+	for i, s := range secrets.Items {
+		version, err := strconv.Atoi(s.Labels["version"])
+		if err == nil && version > latestVersion && s.Labels["status"] == "deployed" {
+			latest, latestVersion = i, version
+		}
+	}
+	if latest < 0 {
+		return nil, releaseName, "", fmt.Errorf("no deployed Helm release %s found in %s", releaseName, storageNamespace)
+	}
+
+	manifest, releaseNamespace, err := decodeHelmRelease(secrets.Items[latest].Data["release"])
+	if err != nil {
+		return nil, releaseName, "", err
+	}
+	if releaseNamespace == "" {
+		releaseNamespace = hr.GetNamespace()
+		if targetNamespace != "" {
+			releaseNamespace = targetNamespace
+		}
+	}
+	objects, err := parseYAMLObjects([]byte(manifest))
+	if err != nil {
+		return nil, releaseName, releaseNamespace, fmt.Errorf("failed to parse release manifest: %w", err)
+	}
+
+	refs := make([]fluxManagedRef, 0, len(objects))
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		namespace := obj.GetNamespace()
+		if namespace == "" {
+			namespace = releaseNamespace
+		}
+		refs = append(refs, fluxManagedRef{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Name: obj.GetName(), Namespace: namespace})
+	}
+	return refs, releaseName, releaseNamespace, nil
+}
+
+// FluxTreeTool walks a Kustomization or HelmRelease down to the objects it manages.
+type FluxTreeTool struct {
+	client Client
+}
+
+// NewFluxTreeTool creates a new FluxTreeTool with the provided Kubernetes client.
+func NewFluxTreeTool(client Client) *FluxTreeTool {
+	return &FluxTreeTool{client: client}
+}
+
+// Tool returns the MCP tool definition for flux_tree.
+func (f *FluxTreeTool) Tool() mcp.Tool {
+	return mcp.NewTool("flux_tree",
+		mcp.WithDescription("Walk a FluxCD Kustomization or HelmRelease down to the objects it manages (like 'flux tree'), using the Kustomization inventory and the Helm release manifest, nesting child Kustomizations and HelmReleases. Reports managed objects that are missing from the cluster, failing (Ready/Available false, replicas not ready) or drifted (Flux ownership labels or Helm release annotations changed)"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description("Kustomization or HelmRelease"),
+			mcp.Enum("Kustomization", "HelmRelease"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Flux object"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the Flux object (defaults to 'flux-system' if not specified)"),
+		),
+	)
+}
+
+// Handler builds the tree and collects the issues found in it.
+func (f *FluxTreeTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateFluxObjectParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate flux_tree params: %w", err)
+	}
+	if gk := fluxKinds[strings.ToLower(input.Kind)]; !isFluxOwnerKind(gk.Group, gk.Kind) {
+		return nil, fmt.Errorf("kind must be Kustomization or HelmRelease, got %s", gk.Kind)
+	}
+
+	ri, err := fluxResourceInterface(f.client, input.Kind, input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	root, err := ri.Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", input.Kind, input.Namespace, input.Name, err)
+	}
+
+	clientset, err := f.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	mapper, err := f.client.RESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+
+	walker := &fluxTreeWalker{
+		client:    f.client,
+		clientset: clientset,
+		mapper:    mapper,
+		seen:      map[string]bool{},
+		issues:    []FluxTreeIssue{},
+		counts:    map[string]int{},
+	}
+	tree := walker.owner(ctx, root)
+
+	result := map[string]any{
+		"tree":   tree,
+		"issues": walker.issues,
+		"counts": walker.counts,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
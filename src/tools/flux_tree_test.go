@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFluxInventoryRef(t *testing.T) {
+	ref, err := fluxInventoryRef("apps_web_apps_Deployment", "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, fluxManagedRef{Namespace: "apps", Name: "web", Group: "apps", Kind: "Deployment", Version: "v1"}, ref)
+
+	ref, err = fluxInventoryRef("_apps__Namespace", "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, fluxManagedRef{Name: "apps", Kind: "Namespace", Version: "v1"}, ref)
+
+	_, err = fluxInventoryRef("apps_web", "v1")
+	assert.Error(t, err)
+}
+
+func TestDecodeHelmRelease(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(`{"name":"podinfo","namespace":"apps","manifest":"---\n# Source: podinfo/templates/service.yaml\napiVersion: v1\nkind: Service\nmetadata:\n  name: podinfo\n"}`))
+	assert.NoError(t, zw.Close())
+	data := []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))
+
+	manifest, namespace, err := decodeHelmRelease(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "apps", namespace)
+	objects, err := parseYAMLObjects([]byte(manifest))
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "Service", objects[0].GetKind())
+
+	_, _, err = decodeHelmRelease([]byte("not base64!"))
+	assert.Error(t, err)
+}
+
+func TestFluxManagedHealth(t *testing.T) {
+	deployment := &unstructured.Unstructured{Object: map[string]any{
+		"spec":   map[string]any{"replicas": int64(3)},
+		"status": map[string]any{"readyReplicas": int64(1)},
+	}}
+	assert.Equal(t, "1/3 replicas ready", fluxManagedHealth(deployment))
+
+	deployment.Object["status"] = map[string]any{
+		"readyReplicas": int64(3),
+		"conditions": []any{
+			map[string]any{"type": "Available", "status": "False", "reason": "MinimumReplicasUnavailable"},
+		},
+	}
+	assert.Equal(t, "Available=False: MinimumReplicasUnavailable", fluxManagedHealth(deployment))
+
+	job := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{"conditions": []any{map[string]any{"type": "Complete", "status": "True"}}},
+	}}
+	assert.Empty(t, fluxManagedHealth(job))
+}
+
+func TestFluxOwnershipDrift(t *testing.T) {
+	owner := fluxOwnership{Labels: map[string]string{
+		"kustomize.toolkit.fluxcd.io/name":      "apps",
+		"kustomize.toolkit.fluxcd.io/namespace": "flux-system",
+	}}
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetLabels(map[string]string{
+		"kustomize.toolkit.fluxcd.io/name":      "apps",
+		"kustomize.toolkit.fluxcd.io/namespace": "flux-system",
+	})
+	assert.Empty(t, fluxOwnershipDrift(obj, owner))
+
+	obj.SetLabels(map[string]string{"kustomize.toolkit.fluxcd.io/name": "infra", "kustomize.toolkit.fluxcd.io/namespace": "flux-system"})
+	assert.Equal(t, `label kustomize.toolkit.fluxcd.io/name is "infra", expected "apps"`, fluxOwnershipDrift(obj, owner))
+
+	helmOwner := fluxOwnership{Annotations: map[string]string{"meta.helm.sh/release-name": "podinfo"}}
+	assert.Equal(t, "annotation meta.helm.sh/release-name missing", fluxOwnershipDrift(obj, helmOwner))
+}
@@ -0,0 +1,333 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// gatewayAPIGroup is the API group of the Kubernetes Gateway API.
+const gatewayAPIGroup = "gateway.networking.k8s.io"
+
+// Gateway API kinds; the served version is resolved through the RESTMapper.
+var (
+	gatewayGK   = schema.GroupKind{Group: gatewayAPIGroup, Kind: "Gateway"}
+	httpRouteGK = schema.GroupKind{Group: gatewayAPIGroup, Kind: "HTTPRoute"}
+)
+
+// GatewayBackendRef is a backend an HTTPRoute rule forwards to.
+type GatewayBackendRef struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Port      int64  `json:"port,omitempty"`
+	Weight    *int64 `json:"weight,omitempty"`
+}
+
+// HTTPRouteRule is one rule of an HTTPRoute: what it matches and where it sends traffic.
+type HTTPRouteRule struct {
+	Matches     []string            `json:"matches"`
+	Filters     []string            `json:"filters,omitempty"`
+	BackendRefs []GatewayBackendRef `json:"backendRefs"`
+}
+
+// gatewayPolicyTarget is an object a policy attaches to through targetRef or targetRefs.
+type gatewayPolicyTarget struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// gatewayParentRef is a parentRef of a route with the defaults of the Gateway API filled in.
+type gatewayParentRef struct {
+	Group       string
+	Kind        string
+	Namespace   string
+	Name        string
+	SectionName string
+}
+
+// String names the parent as namespace/name, prefixed with its kind unless it is a Gateway.
+func (p gatewayParentRef) String() string {
+	if p.Group == gatewayAPIGroup && p.Kind == "Gateway" {
+		return p.Namespace + "/" + p.Name
+	}
+	return p.Kind + "/" + p.Namespace + "/" + p.Name
+}
+
+// httpRouteParentRefs returns the parentRefs of a route; group, kind and namespace default to a
+// Gateway in the route's namespace.
+func httpRouteParentRefs(route *unstructured.Unstructured) []gatewayParentRef {
+	parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	refs := make([]gatewayParentRef, 0, len(parents))
+	for _, p := range parents {
+		parent, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		ref := gatewayParentRef{Group: gatewayAPIGroup, Kind: "Gateway", Namespace: route.GetNamespace()}
+		if group, found := parent["group"].(string); found {
+			ref.Group = group
+		}
+		if kind, found := parent["kind"].(string); found {
+			ref.Kind = kind
+		}
+		if namespace, _ := parent["namespace"].(string); namespace != "" {
+			ref.Namespace = namespace
+		}
+		ref.Name, _ = parent["name"].(string)
+		ref.SectionName, _ = parent["sectionName"].(string)
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// renderHTTPRouteMatch renders an HTTPRouteMatch as e.g. "PathPrefix /api GET header:x-env=canary".
+func renderHTTPRouteMatch(match map[string]any) string {
+	pathType, value := httpRouteMatchPath(match)
+	return strings.Join(append([]string{pathType + " " + value}, httpRouteMatchConditions(match)...), " ")
+}
+
+// httpRouteMatchPath returns the path type and value of an HTTPRouteMatch, PathPrefix / by default.
+func httpRouteMatchPath(match map[string]any) (pathType, value string) {
+	pathType, value = "PathPrefix", "/"
+	if v, found, _ := unstructured.NestedString(match, "path", "type"); found {
+		pathType = v
+	}
+	if v, found, _ := unstructured.NestedString(match, "path", "value"); found {
+		value = v
+	}
+	return pathType, value
+}
+
+// httpRouteMatchConditions renders the method, header and query parameter conditions of an
+// HTTPRouteMatch, e.g. "GET" and "header:x-env=canary".
+func httpRouteMatchConditions(match map[string]any) []string {
+	var parts []string
+	if method, _ := match["method"].(string); method != "" {
+		parts = append(parts, method)
+	}
+	for _, field := range []string{"headers", "queryParams"} {
+		prefix := "header:"
+		if field == "queryParams" {
+			prefix = "query:"
+		}
+		list, _, _ := unstructured.NestedSlice(match, field)
+		for _, item := range list {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _ := m["name"].(string)
+			value, _ := m["value"].(string)
+			op := "="
+			if t, _ := m["type"].(string); t == "RegularExpression" {
+				op = "~="
+			}
+			parts = append(parts, prefix+name+op+value)
+		}
+	}
+	return parts
+}
+
+// httpRouteRules extracts the matches, filters and backends of every rule of an HTTPRoute.
+func httpRouteRules(route *unstructured.Unstructured) []HTTPRouteRule {
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	result := make([]HTTPRouteRule, 0, len(rules))
+	for _, r := range rules {
+		if rule, ok := r.(map[string]any); ok {
+			result = append(result, httpRouteRule(route, rule))
+		}
+	}
+	return result
+}
+
+// httpRouteRule extracts the matches, filters and backends of one rule of an HTTPRoute.
+func httpRouteRule(route *unstructured.Unstructured, rule map[string]any) HTTPRouteRule {
+	out := HTTPRouteRule{Matches: []string{}, BackendRefs: []GatewayBackendRef{}}
+	matches, _, _ := unstructured.NestedSlice(rule, "matches")
+	for _, m := range matches {
+		if match, ok := m.(map[string]any); ok {
+			out.Matches = append(out.Matches, renderHTTPRouteMatch(match))
+		}
+	}
+	if len(out.Matches) == 0 {
+		out.Matches = append(out.Matches, "PathPrefix /")
+	}
+	filters, _, _ := unstructured.NestedSlice(rule, "filters")
+	for _, f := range filters {
+		if filter, ok := f.(map[string]any); ok {
+			if t, _ := filter["type"].(string); t != "" {
+				out.Filters = append(out.Filters, t)
+			}
+		}
+	}
+	backends, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+	for _, b := range backends {
+		backend, ok := b.(map[string]any)
+		if !ok {
+			continue
+		}
+		ref := GatewayBackendRef{Kind: "Service", Namespace: route.GetNamespace()}
+		if kind, _ := backend["kind"].(string); kind != "" {
+			ref.Kind = kind
+		}
+		if ns, _ := backend["namespace"].(string); ns != "" {
+			ref.Namespace = ns
+		}
+		ref.Name, _ = backend["name"].(string)
+		ref.Port, _, _ = unstructured.NestedInt64(backend, "port")
+		if weight, found, _ := unstructured.NestedInt64(backend, "weight"); found {
+			ref.Weight = &weight
+		}
+		out.BackendRefs = append(out.BackendRefs, ref)
+	}
+	return out
+}
+
+// parentConditions renders the status conditions an HTTPRoute reports for one Gateway.
+func parentConditions(route *unstructured.Unstructured, parent gatewayParentRef) []string {
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	for _, p := range parents {
+		status, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(status, "parentRef", "name")
+		namespace, _, _ := unstructured.NestedString(status, "parentRef", "namespace")
+		if namespace == "" {
+			namespace = route.GetNamespace()
+		}
+		sectionName, _, _ := unstructured.NestedString(status, "parentRef", "sectionName")
+		if name != parent.Name || namespace != parent.Namespace || sectionName != parent.SectionName {
+			continue
+		}
+		conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+		return renderConditions(conditions)
+	}
+	return nil
+}
+
+// renderConditions renders status conditions as "Type=Status" with the reason when the status is not True.
+func renderConditions(conditions []any) []string {
+	var out []string
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		condType, _ := cond["type"].(string)
+		status, _ := cond["status"].(string)
+		s := condType + "=" + status
+		if reason, _ := cond["reason"].(string); status != "True" && reason != "" {
+			s += ": " + reason
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// gatewayPolicyTargets returns the objects a policy attaches to through spec.targetRef or spec.targetRefs.
+func gatewayPolicyTargets(policy *unstructured.Unstructured) []gatewayPolicyTarget {
+	var refs []any
+	if ref, found, _ := unstructured.NestedMap(policy.Object, "spec", "targetRef"); found {
+		refs = append(refs, ref)
+	}
+	list, _, _ := unstructured.NestedSlice(policy.Object, "spec", "targetRefs")
+	refs = append(refs, list...)
+
+	targets := make([]gatewayPolicyTarget, 0, len(refs))
+	for _, r := range refs {
+		ref, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		target := gatewayPolicyTarget{Namespace: policy.GetNamespace()}
+		target.Kind, _ = ref["kind"].(string)
+		target.Name, _ = ref["name"].(string)
+		if ns, _ := ref["namespace"].(string); ns != "" {
+			target.Namespace = ns
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// isGatewayPolicyResource reports whether a discovered resource is a Gateway API style policy:
+// a *Policy kind in a gateway-related group (Gateway API, Envoy Gateway, GKE, Istio, ...).
+func isGatewayPolicyResource(group string, res metav1.APIResource) bool {
+	if !strings.HasSuffix(res.Kind, "Policy") || strings.Contains(res.Name, "/") {
+		return false
+	}
+	return strings.Contains(group, "gateway") || group == "networking.gke.io"
+}
+
+// gatewayAPIResource returns the resource of a Gateway API kind in the preferred version the
+// cluster serves.
+func gatewayAPIResource(apiResourceLists []*metav1.APIResourceList, kind string) (schema.GroupVersionResource, error) {
+	for _, resList := range apiResourceLists {
+		if resList == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(resList.GroupVersion)
+		if err != nil || gv.Group != gatewayAPIGroup {
+			continue
+		}
+		for _, res := range resList.APIResources {
+			if res.Kind == kind && !strings.Contains(res.Name, "/") {
+				return gv.WithResource(res.Name), nil
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("%s.%s is not served by the cluster (are the Gateway API CRDs installed?)", kind, gatewayAPIGroup)
+}
+
+// listGatewayPolicies lists every policy resource served by the cluster that can attach to Gateways
+// or routes. Policies that cannot be listed are reported instead of failing the whole call.
+func listGatewayPolicies(ctx context.Context, client Client, apiResourceLists []*metav1.APIResourceList) ([]unstructured.Unstructured, []string) {
+	var policies []unstructured.Unstructured
+	var errs []string
+	for _, resList := range apiResourceLists {
+		if resList == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(resList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, res := range resList.APIResources {
+			if !isGatewayPolicyResource(gv.Group, res) {
+				continue
+			}
+			ri, err := client.ResourceInterface(gv.WithResource(res.Name), res.Namespaced, "")
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", res.Kind, err))
+				continue
+			}
+			items, err := ri.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", res.Kind, err))
+				continue
+			}
+			policies = append(policies, items.Items...)
+		}
+	}
+	return policies, errs
+}
+
+// gatewayPoliciesByTarget names the policies as Kind/namespace/name keyed by the Kind/namespace/name
+// of each object they attach to.
+func gatewayPoliciesByTarget(policies []unstructured.Unstructured) map[string][]string {
+	byTarget := map[string][]string{}
+	for i := range policies {
+		for _, target := range gatewayPolicyTargets(&policies[i]) {
+			key := fmt.Sprintf("%s/%s/%s", target.Kind, target.Namespace, target.Name)
+			byTarget[key] = append(byTarget[key], fmt.Sprintf("%s/%s/%s", policies[i].GetKind(), policies[i].GetNamespace(), policies[i].GetName()))
+		}
+	}
+	return byTarget
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestHTTPRouteParentRefs(t *testing.T) {
+	route := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "web", "namespace": "shop"},
+		"spec": map[string]any{"parentRefs": []any{
+			map[string]any{"name": "public"},
+			map[string]any{"name": "public", "namespace": "infra", "sectionName": "https"},
+			map[string]any{"name": "web", "kind": "Service", "group": ""},
+		}},
+		"status": map[string]any{"parents": []any{
+			map[string]any{
+				"parentRef":  map[string]any{"name": "public", "namespace": "infra", "sectionName": "https"},
+				"conditions": []any{map[string]any{"type": "Accepted", "status": "False", "reason": "NotAllowedByListeners"}},
+			},
+		}},
+	}}
+
+	parents := httpRouteParentRefs(route)
+	assert.Equal(t, []gatewayParentRef{
+		{Group: gatewayAPIGroup, Kind: "Gateway", Namespace: "shop", Name: "public"},
+		{Group: gatewayAPIGroup, Kind: "Gateway", Namespace: "infra", Name: "public", SectionName: "https"},
+		{Group: "", Kind: "Service", Namespace: "shop", Name: "web"},
+	}, parents)
+	assert.Equal(t, "infra/public", parents[1].String())
+	assert.Equal(t, "Service/shop/web", parents[2].String())
+
+	assert.Nil(t, parentConditions(route, parents[0]))
+	assert.Equal(t, []string{"Accepted=False: NotAllowedByListeners"}, parentConditions(route, parents[1]))
+}
+
+func TestHTTPRouteRules(t *testing.T) {
+	route := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "web", "namespace": "shop"},
+		"spec": map[string]any{"rules": []any{
+			map[string]any{
+				"matches": []any{map[string]any{
+					"path":    map[string]any{"type": "Exact", "value": "/api"},
+					"method":  "GET",
+					"headers": []any{map[string]any{"name": "x-env", "value": "canary"}},
+				}},
+				"filters": []any{map[string]any{"type": "RequestHeaderModifier"}},
+				"backendRefs": []any{
+					map[string]any{"name": "api-v1", "port": int64(8080), "weight": int64(90)},
+					map[string]any{"name": "api-v2", "namespace": "canary", "port": int64(8080), "weight": int64(10)},
+				},
+			},
+			map[string]any{"backendRefs": []any{map[string]any{"name": "web", "port": int64(80)}}},
+		}},
+	}}
+
+	rules := httpRouteRules(route)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, []string{"Exact /api GET header:x-env=canary"}, rules[0].Matches)
+	assert.Equal(t, []string{"RequestHeaderModifier"}, rules[0].Filters)
+	assert.Equal(t, "shop", rules[0].BackendRefs[0].Namespace)
+	assert.Equal(t, int64(90), *rules[0].BackendRefs[0].Weight)
+	assert.Equal(t, "canary", rules[0].BackendRefs[1].Namespace)
+	assert.Equal(t, []string{"PathPrefix /"}, rules[1].Matches)
+	assert.Equal(t, GatewayBackendRef{Kind: "Service", Name: "web", Namespace: "shop", Port: 80}, rules[1].BackendRefs[0])
+}
+
+func TestGatewayPolicyTargets(t *testing.T) {
+	policy := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "tls", "namespace": "shop"},
+		"spec": map[string]any{
+			"targetRef":  map[string]any{"kind": "Gateway", "name": "public", "namespace": "infra"},
+			"targetRefs": []any{map[string]any{"kind": "HTTPRoute", "name": "web"}},
+		},
+	}}
+	assert.Equal(t, []gatewayPolicyTarget{
+		{Kind: "Gateway", Name: "public", Namespace: "infra"},
+		{Kind: "HTTPRoute", Name: "web", Namespace: "shop"},
+	}, gatewayPolicyTargets(policy))
+
+	assert.True(t, isGatewayPolicyResource("gateway.networking.k8s.io", metav1.APIResource{Name: "backendtlspolicies", Kind: "BackendTLSPolicy"}))
+	assert.True(t, isGatewayPolicyResource("gateway.envoyproxy.io", metav1.APIResource{Name: "securitypolicies", Kind: "SecurityPolicy"}))
+	assert.False(t, isGatewayPolicyResource("networking.k8s.io", metav1.APIResource{Name: "networkpolicies", Kind: "NetworkPolicy"}))
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+)
+
+// gcpCredentials finds Application Default Credentials the way the Google client libraries do:
+// GOOGLE_APPLICATION_CREDENTIALS, the gcloud ADC file, or the metadata server for GKE Workload
+// Identity and Compute Engine. It returns a client option pinned to those credentials and the
+// mechanism that supplied them.
+func gcpCredentials(ctx context.Context) (option.ClientOption, string, error) {
+	creds, err := google.FindDefaultCredentials(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		return nil, "", fmt.Errorf("google credentials not found: set GOOGLE_APPLICATION_CREDENTIALS, run 'gcloud auth application-default login' or use Workload Identity: %w", err)
+	}
+	return option.WithCredentials(creds), gcpCredentialsSource(creds.JSON, os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")), nil
+}
+
+// gcpCredentialsSource names the mechanism behind the credentials: a key file from the environment,
+// the gcloud ADC file, or the metadata server when there is no JSON at all.
+func gcpCredentialsSource(credsJSON []byte, envPath string) string {
+	if len(credsJSON) == 0 {
+		return "metadata server"
+	}
+	var file struct {
+		Type string `json:"type"`
+	}
+	source := "gcloud application default credentials"
+	if envPath != "" {
+		source = "GOOGLE_APPLICATION_CREDENTIALS"
+	}
+	if err := json.Unmarshal(credsJSON, &file); err == nil && file.Type != "" {
+		source += " (" + file.Type + ")"
+	}
+	return source
+}
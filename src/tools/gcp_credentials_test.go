@@ -0,0 +1,14 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCPCredentialsSource(t *testing.T) {
+	assert.Equal(t, "metadata server", gcpCredentialsSource(nil, ""))
+	assert.Equal(t, "GOOGLE_APPLICATION_CREDENTIALS (service_account)", gcpCredentialsSource([]byte(`{"type": "service_account"}`), "/var/run/sa.json"))
+	assert.Equal(t, "gcloud application default credentials (authorized_user)", gcpCredentialsSource([]byte(`{"type": "authorized_user"}`), ""))
+	assert.Equal(t, "gcloud application default credentials", gcpCredentialsSource([]byte(`not json`), ""))
+}
@@ -0,0 +1,249 @@
+// Environment variables used by this tool:
+// Optional:
+//   GOOGLE_APPLICATION_CREDENTIALS - Path to a GCP service account JSON file; without it gcloud ADC or Workload Identity is used
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	artifactregistry "cloud.google.com/go/artifactregistry/apiv1"
+	artifactregistrypb "cloud.google.com/go/artifactregistry/apiv1/artifactregistrypb"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/iterator"
+)
+
+// Limits applied to gcp_list_image_tags results.
+const (
+	defaultImageTagsLimit = 20
+	maxImageTagsLimit     = 100
+)
+
+// gcrLocations maps Container Registry hosts to the multi-region of their Artifact Registry gcr.io repository.
+var gcrLocations = map[string]string{
+	"gcr.io":      "us",
+	"us.gcr.io":   "us",
+	"eu.gcr.io":   "europe",
+	"asia.gcr.io": "asia",
+}
+
+// GCPListImageTagsInput represents the input for the gcp_list_image_tags tool.
+type GCPListImageTagsInput struct {
+	Image           string `json:"image"`
+	TagPrefix       string `json:"tagPrefix,omitempty"`
+	IncludeUntagged bool   `json:"includeUntagged,omitempty"`
+	Limit           int    `json:"limit"`
+}
+
+// ImageVersion is one pushed image digest with its tags.
+type ImageVersion struct {
+	Digest    string   `json:"digest"`
+	Tags      []string `json:"tags,omitempty"`
+	Pushed    string   `json:"pushed,omitempty"`
+	Updated   string   `json:"updated,omitempty"`
+	SizeBytes int64    `json:"sizeBytes,omitempty"`
+}
+
+// GCPListImageTagsTool lists tags and digests of an image in Artifact Registry or Container Registry.
+type GCPListImageTagsTool struct{}
+
+// NewGCPListImageTagsTool creates a new GCPListImageTagsTool.
+func NewGCPListImageTagsTool() *GCPListImageTagsTool {
+	return &GCPListImageTagsTool{}
+}
+
+// Tool returns the MCP tool definition for gcp_list_image_tags.
+func (g *GCPListImageTagsTool) Tool() mcp.Tool {
+	return mcp.NewTool("gcp_list_image_tags",
+		mcp.WithDescription("List the tags and digests of an image in Google Artifact Registry (or gcr.io) with their push times, newest first, to find the latest tag to roll out. Uses Google Application Default Credentials"),
+		mcp.WithString("image",
+			mcp.Required(),
+			mcp.Description("Image repository, e.g. europe-west1-docker.pkg.dev/my-project/apps/api or gcr.io/my-project/api; a tag or digest is ignored except to mark the current version"),
+		),
+		mcp.WithString("tagPrefix", mcp.Description("Only return versions with a tag starting with this prefix, e.g. v2.")),
+		mcp.WithBoolean("includeUntagged", mcp.Description("Include digests without tags (default: false)")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of versions to return (default: 20, max: 100)")),
+	)
+}
+
+// Handler lists the versions of the image package, newest first.
+func (g *GCPListImageTagsTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateGCPListImageTagsParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate gcp_list_image_tags params: %w", err)
+	}
+	ref := parseImageRef(input.Image)
+	parent, err := artifactRegistryPackage(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, credsSource, err := gcpCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := artifactregistry.NewClient(ctx, creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create artifact registry client: %w", err)
+	}
+	defer client.Close()
+
+	it := client.ListVersions(ctx, &artifactregistrypb.ListVersionsRequest{
+		Parent:  parent,
+		View:    artifactregistrypb.VersionView_FULL,
+		OrderBy: "create_time desc",
+	})
+	var versions []ImageVersion
+	var current *ImageVersion
+	for len(versions) < input.Limit {
+		v, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions of %s: %w", parent, err)
+		}
+		version := imageVersion(v)
+		if matchesImageRef(version, ref) {
+			c := version
+			current = &c
+		}
+		if !imageVersionWanted(version, input) {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	result := map[string]any{
+		"image":             ref.Registry + "/" + ref.Repository,
+		"package":           parent,
+		"versions":          versions,
+		"count":             len(versions),
+		"credentialsSource": credsSource,
+	}
+	if latest := latestImageTag(versions); latest != "" {
+		result["latestTag"] = latest
+	}
+	if current != nil {
+		result["current"] = current
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// artifactRegistryPackage returns the Artifact Registry package resource name for a Docker image.
+// gcr.io images are served from the gcr.io repositories that Artifact Registry creates per project.
+func artifactRegistryPackage(ref imageRef) (string, error) {
+	var project, location, repository, pkg string
+	switch {
+	case strings.HasSuffix(ref.Registry, "-docker.pkg.dev"):
+		parts := strings.SplitN(ref.Repository, "/", 3)
+		if len(parts) != 3 {
+			return "", fmt.Errorf("invalid artifact registry image %s/%s: expected LOCATION-docker.pkg.dev/PROJECT/REPOSITORY/IMAGE", ref.Registry, ref.Repository)
+		}
+		location = strings.TrimSuffix(ref.Registry, "-docker.pkg.dev")
+		project, repository, pkg = parts[0], parts[1], parts[2]
+	case gcrLocations[ref.Registry] != "":
+		p, image, ok := strings.Cut(ref.Repository, "/")
+		if !ok {
+			return "", fmt.Errorf("invalid gcr.io image %s/%s: expected %s/PROJECT/IMAGE", ref.Registry, ref.Repository, ref.Registry)
+		}
+		location, repository, project, pkg = gcrLocations[ref.Registry], ref.Registry, p, image
+	default:
+		return "", fmt.Errorf("image registry %s is not Artifact Registry or gcr.io", ref.Registry)
+	}
+	// Nested image paths are a single package whose name has its slashes escaped.
+	return fmt.Sprintf("projects/%s/locations/%s/repositories/%s/packages/%s",
+		project, location, repository, strings.ReplaceAll(pkg, "/", "%2F")), nil
+}
+
+// imageVersion converts an Artifact Registry version into its digest, tags and times.
+func imageVersion(v *artifactregistrypb.Version) ImageVersion {
+	version := ImageVersion{Digest: path.Base(v.GetName())}
+	for _, tag := range v.GetRelatedTags() {
+		version.Tags = append(version.Tags, path.Base(tag.GetName()))
+	}
+	if v.GetCreateTime() != nil {
+		version.Pushed = v.GetCreateTime().AsTime().UTC().Format(time.RFC3339)
+	}
+	if v.GetUpdateTime() != nil {
+		version.Updated = v.GetUpdateTime().AsTime().UTC().Format(time.RFC3339)
+	}
+	if size, ok := v.GetMetadata().GetFields()["imageSizeBytes"]; ok {
+		// Docker version metadata reports the size as a decimal string.
+		if n, err := strconv.ParseInt(size.GetStringValue(), 10, 64); err == nil {
+			version.SizeBytes = n
+		}
+	}
+	return version
+}
+
+// matchesImageRef reports whether the version is the one the image reference points at.
+func matchesImageRef(version ImageVersion, ref imageRef) bool {
+	if ref.Digest != "" {
+		return version.Digest == ref.Digest
+	}
+	for _, tag := range version.Tags {
+		if tag == ref.Tag {
+			return true
+		}
+	}
+	return false
+}
+
+// imageVersionWanted applies the untagged and tag prefix filters.
+func imageVersionWanted(version ImageVersion, input *GCPListImageTagsInput) bool {
+	if len(version.Tags) == 0 {
+		return input.IncludeUntagged && input.TagPrefix == ""
+	}
+	if input.TagPrefix == "" {
+		return true
+	}
+	for _, tag := range version.Tags {
+		if strings.HasPrefix(tag, input.TagPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// latestImageTag returns the newest tag other than "latest", which floats and does not pin a rollout.
+func latestImageTag(versions []ImageVersion) string {
+	for _, v := range versions {
+		for _, tag := range v.Tags {
+			if tag != "latest" {
+				return tag
+			}
+		}
+	}
+	return ""
+}
+
+// parseAndValidateGCPListImageTagsParams validates and parses the input parameters.
+func parseAndValidateGCPListImageTagsParams(args map[string]any) (*GCPListImageTagsInput, error) {
+	input := &GCPListImageTagsInput{Limit: defaultImageTagsLimit}
+	input.Image, _ = args["image"].(string)
+	input.Image = strings.TrimSpace(input.Image)
+	if input.Image == "" {
+		return nil, errors.New("image is required")
+	}
+	input.TagPrefix, _ = args["tagPrefix"].(string)
+	input.IncludeUntagged, _ = args["includeUntagged"].(bool)
+	if v, ok := args["limit"].(float64); ok && v > 0 {
+		input.Limit = int(v)
+	}
+	if input.Limit > maxImageTagsLimit {
+		input.Limit = maxImageTagsLimit
+	}
+	return input, nil
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	artifactregistrypb "cloud.google.com/go/artifactregistry/apiv1/artifactregistrypb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestArtifactRegistryPackage(t *testing.T) {
+	parent, err := artifactRegistryPackage(parseImageRef("europe-west1-docker.pkg.dev/my-project/apps/team/api:v1.2.0"))
+	assert.NoError(t, err)
+	assert.Equal(t, "projects/my-project/locations/europe-west1/repositories/apps/packages/team%2Fapi", parent)
+
+	parent, err = artifactRegistryPackage(parseImageRef("eu.gcr.io/my-project/api"))
+	assert.NoError(t, err)
+	assert.Equal(t, "projects/my-project/locations/europe/repositories/eu.gcr.io/packages/api", parent)
+
+	_, err = artifactRegistryPackage(parseImageRef("nginx:1.27"))
+	assert.Error(t, err)
+	_, err = artifactRegistryPackage(parseImageRef("us-docker.pkg.dev/my-project/api"))
+	assert.Error(t, err)
+}
+
+func TestImageVersion(t *testing.T) {
+	metadata, _ := structpb.NewStruct(map[string]any{"imageSizeBytes": "52428800"})
+	version := imageVersion(&artifactregistrypb.Version{
+		Name:       "projects/p/locations/us/repositories/r/packages/api/versions/sha256:abc",
+		CreateTime: timestamppb.New(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)),
+		RelatedTags: []*artifactregistrypb.Tag{
+			{Name: "projects/p/locations/us/repositories/r/packages/api/tags/latest"},
+			{Name: "projects/p/locations/us/repositories/r/packages/api/tags/v1.3.0"},
+		},
+		Metadata: metadata,
+	})
+	assert.Equal(t, ImageVersion{Digest: "sha256:abc", Tags: []string{"latest", "v1.3.0"}, Pushed: "2025-01-02T03:04:05Z", SizeBytes: 52428800}, version)
+	assert.True(t, matchesImageRef(version, parseImageRef("gcr.io/p/api:v1.3.0")))
+	assert.True(t, matchesImageRef(version, parseImageRef("gcr.io/p/api@sha256:abc")))
+	assert.False(t, matchesImageRef(version, parseImageRef("gcr.io/p/api:v1.2.0")))
+}
+
+func TestImageVersionFilters(t *testing.T) {
+	versions := []ImageVersion{
+		{Digest: "sha256:c", Tags: []string{"latest"}},
+		{Digest: "sha256:b"},
+		{Digest: "sha256:a", Tags: []string{"v2.0.0"}},
+	}
+	assert.Equal(t, "v2.0.0", latestImageTag(versions))
+
+	input := &GCPListImageTagsInput{TagPrefix: "v2."}
+	assert.False(t, imageVersionWanted(versions[0], input))
+	assert.False(t, imageVersionWanted(versions[1], input))
+	assert.True(t, imageVersionWanted(versions[2], input))
+	assert.True(t, imageVersionWanted(versions[1], &GCPListImageTagsInput{IncludeUntagged: true}))
+}
@@ -0,0 +1,289 @@
+// Environment variables used by this tool:
+// Optional:
+//   GOOGLE_APPLICATION_CREDENTIALS - Path to a GCP service account JSON file; without it gcloud ADC or Workload Identity is used
+//   GOOGLE_CLOUD_PROJECT           - GCP Project ID (used if not provided in input)
+//   GKE_CLUSTER_NAME               - Cluster name to filter on (used if not provided in input)
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"cloud.google.com/go/logging/logadmin"
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Limits applied to gcp_logs_query results.
+const (
+	defaultGCPLogsLimit = 100
+	maxGCPLogsLimit     = 1000
+)
+
+// gcpLogSeverities are the severities accepted by the severity filter, lowest first.
+var gcpLogSeverities = []string{"DEFAULT", "DEBUG", "INFO", "NOTICE", "WARNING", "ERROR", "CRITICAL", "ALERT", "EMERGENCY"}
+
+// GCPLogsQueryInput represents the input for a Cloud Logging query.
+type GCPLogsQueryInput struct {
+	ProjectID string    `json:"projectId"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Pod       string    `json:"pod,omitempty"`
+	Workload  string    `json:"workload,omitempty"`
+	Container string    `json:"container,omitempty"`
+	Severity  string    `json:"severity,omitempty"`
+	Contains  string    `json:"contains,omitempty"`
+	Filter    string    `json:"filter,omitempty"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Limit     int       `json:"limit"`
+	Direction string    `json:"direction"`
+}
+
+// GCPLogEntry is one log entry returned by Cloud Logging.
+type GCPLogEntry struct {
+	Time      string `json:"time"`
+	Severity  string `json:"severity"`
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	Message   string `json:"message"`
+}
+
+// GCPLogsQueryTool runs Cloud Logging queries for GKE workloads.
+type GCPLogsQueryTool struct{}
+
+// NewGCPLogsQueryTool creates a new GCPLogsQueryTool.
+func NewGCPLogsQueryTool() *GCPLogsQueryTool {
+	return &GCPLogsQueryTool{}
+}
+
+// Tool returns the MCP tool definition for gcp_logs_query.
+func (g *GCPLogsQueryTool) Tool() mcp.Tool {
+	return mcp.NewTool("gcp_logs_query",
+		mcp.WithDescription("Query GKE container logs in Google Cloud Logging (Stackdriver) by namespace, pod, workload or container over a time range. Useful when pod logs have already rotated out of the kubelet. Uses Google Application Default Credentials"),
+		mcp.WithString("projectId", mcp.Description("GCP Project ID (optional, defaults to GOOGLE_CLOUD_PROJECT or the gke_ kubeconfig context)")),
+		mcp.WithString("cluster", mcp.Description("GKE cluster name (optional, defaults to GKE_CLUSTER_NAME or the gke_ kubeconfig context)")),
+		mcp.WithString("namespace", mcp.Description("Kubernetes namespace of the workload")),
+		mcp.WithString("pod", mcp.Description("Exact pod name")),
+		mcp.WithString("workload", mcp.Description("Deployment, StatefulSet or Job name; matches pods named after it")),
+		mcp.WithString("container", mcp.Description("Container name")),
+		mcp.WithString("severity", mcp.Description("Minimum severity, e.g. WARNING or ERROR"), mcp.Enum(gcpLogSeverities...)),
+		mcp.WithString("contains", mcp.Description("Only return entries containing this text")),
+		mcp.WithString("filter", mcp.Description("Additional Cloud Logging filter expression, ANDed with the others")),
+		mcp.WithString("since", mcp.Description("Relative start of the time range like 15m or 6h (default: 1h)")),
+		mcp.WithString("start", mcp.Description("Start of the time range (RFC3339), overrides since")),
+		mcp.WithString("end", mcp.Description("End of the time range (RFC3339, default: now)")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of entries to return (default: 100, max: 1000)")),
+		mcp.WithString("direction",
+			mcp.Description("'backward' returns the newest entries first (default), 'forward' the oldest first"),
+			mcp.Enum("backward", "forward"),
+		),
+	)
+}
+
+// Handler runs the filter against Cloud Logging and returns the matching entries.
+func (g *GCPLogsQueryTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateGCPLogsQueryParams(req.Params.Arguments, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate gcp_logs_query params: %w", err)
+	}
+
+	creds, credsSource, err := gcpCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := logadmin.NewClient(ctx, input.ProjectID, creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logging client: %w", err)
+	}
+	defer client.Close()
+
+	filter := buildGCPLogFilter(input)
+	opts := []logadmin.EntriesOption{logadmin.Filter(filter), logadmin.PageSize(int32(input.Limit))}
+	if input.Direction == "backward" {
+		opts = append(opts, logadmin.NewestFirst())
+	}
+	it := client.Entries(ctx, opts...)
+
+	entries := make([]GCPLogEntry, 0, input.Limit)
+	for len(entries) < input.Limit {
+		entry, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list log entries: %w", err)
+		}
+		entries = append(entries, gcpLogEntry(entry))
+	}
+
+	out, err := json.Marshal(map[string]any{
+		"projectId":         input.ProjectID,
+		"filter":            filter,
+		"entries":           entries,
+		"count":             len(entries),
+		"limitReached":      len(entries) >= input.Limit,
+		"credentialsSource": credsSource,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// buildGCPLogFilter builds a Cloud Logging filter for the k8s_container resource of the workload.
+func buildGCPLogFilter(input *GCPLogsQueryInput) string {
+	clauses := []string{`resource.type="k8s_container"`}
+	if input.Cluster != "" {
+		clauses = append(clauses, "resource.labels.cluster_name="+strconv.Quote(input.Cluster))
+	}
+	if input.Namespace != "" {
+		clauses = append(clauses, "resource.labels.namespace_name="+strconv.Quote(input.Namespace))
+	}
+	if input.Pod != "" {
+		clauses = append(clauses, "resource.labels.pod_name="+strconv.Quote(input.Pod))
+	}
+	if input.Workload != "" {
+		// Pods of Deployments, StatefulSets and Jobs are named <workload>-<suffix>.
+		clauses = append(clauses, "resource.labels.pod_name=~"+strconv.Quote("^"+input.Workload+"-"))
+	}
+	if input.Container != "" {
+		clauses = append(clauses, "resource.labels.container_name="+strconv.Quote(input.Container))
+	}
+	if input.Severity != "" {
+		clauses = append(clauses, "severity>="+input.Severity)
+	}
+	if input.Contains != "" {
+		clauses = append(clauses, strconv.Quote(input.Contains))
+	}
+	clauses = append(clauses,
+		"timestamp>="+strconv.Quote(input.Start.UTC().Format(time.RFC3339)),
+		"timestamp<"+strconv.Quote(input.End.UTC().Format(time.RFC3339)),
+	)
+	if input.Filter != "" {
+		clauses = append(clauses, "("+input.Filter+")")
+	}
+	return strings.Join(clauses, " AND ")
+}
+
+// gcpLogEntry converts a log entry, using the message field of structured payloads when present.
+func gcpLogEntry(entry *logging.Entry) GCPLogEntry {
+	result := GCPLogEntry{
+		Time:     entry.Timestamp.UTC().Format(time.RFC3339Nano),
+		Severity: entry.Severity.String(),
+	}
+	if entry.Resource != nil {
+		result.Pod = entry.Resource.Labels["pod_name"]
+		result.Container = entry.Resource.Labels["container_name"]
+	}
+	switch payload := entry.Payload.(type) {
+	case string:
+		result.Message = payload
+	case *structpb.Struct:
+		if msg, ok := payload.GetFields()["message"]; ok && msg.GetStringValue() != "" {
+			result.Message = msg.GetStringValue()
+		} else if b, err := protojson.Marshal(payload); err == nil {
+			result.Message = string(b)
+		}
+	case proto.Message:
+		if b, err := protojson.Marshal(payload); err == nil {
+			result.Message = string(b)
+		}
+	}
+	if len(result.Message) > maxLokiLineLength {
+		result.Message = result.Message[:maxLokiLineLength] + "...(truncated)"
+	}
+	return result
+}
+
+// parseAndValidateGCPLogsQueryParams validates and parses the input parameters.
+func parseAndValidateGCPLogsQueryParams(args map[string]any, now time.Time) (*GCPLogsQueryInput, error) {
+	input := &GCPLogsQueryInput{
+		ProjectID: os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		Cluster:   os.Getenv("GKE_CLUSTER_NAME"),
+		Limit:     defaultGCPLogsLimit,
+		Direction: "backward",
+	}
+	if v, _ := args["projectId"].(string); v != "" {
+		input.ProjectID = v
+	}
+	if v, _ := args["cluster"].(string); v != "" {
+		input.Cluster = v
+	}
+	if input.ProjectID == "" || input.Cluster == "" {
+		if project, _, cluster, ok := currentGKEContext(); ok {
+			if input.ProjectID == "" {
+				input.ProjectID = project
+			}
+			if input.Cluster == "" {
+				input.Cluster = cluster
+			}
+		}
+	}
+	if input.ProjectID == "" {
+		return nil, errors.New("projectId must be provided (as input, GOOGLE_CLOUD_PROJECT or a gke_ kubeconfig context)")
+	}
+
+	input.Namespace, _ = args["namespace"].(string)
+	input.Pod, _ = args["pod"].(string)
+	input.Workload, _ = args["workload"].(string)
+	input.Container, _ = args["container"].(string)
+	input.Contains, _ = args["contains"].(string)
+	input.Filter, _ = args["filter"].(string)
+	if input.Namespace == "" && input.Pod == "" && input.Workload == "" && input.Filter == "" {
+		return nil, errors.New("at least one of namespace, pod, workload or filter must be provided")
+	}
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{input.Pod, input.Workload, input.Container} {
+		if name == "" {
+			continue
+		}
+		if err := validation.ValidateResourceName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if severity, _ := args["severity"].(string); severity != "" {
+		input.Severity = strings.ToUpper(severity)
+		valid := false
+		for _, s := range gcpLogSeverities {
+			valid = valid || s == input.Severity
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid severity %q: must be one of %s", severity, strings.Join(gcpLogSeverities, ", "))
+		}
+	}
+
+	var err error
+	if input.Start, input.End, err = parseTimeRangeArgs(args, now); err != nil {
+		return nil, err
+	}
+
+	if v, ok := args["limit"].(float64); ok && v > 0 {
+		input.Limit = int(v)
+	}
+	if input.Limit > maxGCPLogsLimit {
+		input.Limit = maxGCPLogsLimit
+	}
+	if direction, _ := args["direction"].(string); direction != "" {
+		input.Direction = strings.ToLower(direction)
+		if input.Direction != "backward" && input.Direction != "forward" {
+			return nil, errors.New("direction must be backward or forward")
+		}
+	}
+	return input, nil
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"github.com/stretchr/testify/assert"
+	mrpb "google.golang.org/genproto/googleapis/api/monitoredres"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestBuildGCPLogFilter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	input := &GCPLogsQueryInput{
+		Cluster:   "prod",
+		Namespace: "shop",
+		Workload:  "checkout",
+		Severity:  "ERROR",
+		Contains:  "timeout",
+		Start:     now.Add(-time.Hour),
+		End:       now,
+	}
+	assert.Equal(t, `resource.type="k8s_container" AND resource.labels.cluster_name="prod" AND resource.labels.namespace_name="shop"`+
+		` AND resource.labels.pod_name=~"^checkout-" AND severity>=ERROR AND "timeout"`+
+		` AND timestamp>="2025-01-01T11:00:00Z" AND timestamp<"2025-01-01T12:00:00Z"`, buildGCPLogFilter(input))
+}
+
+func TestGCPLogEntry(t *testing.T) {
+	payload, _ := structpb.NewStruct(map[string]any{"message": "payment failed", "code": 502})
+	entry := gcpLogEntry(&logging.Entry{
+		Timestamp: time.Date(2025, 1, 1, 11, 30, 0, 0, time.UTC),
+		Severity:  logging.Error,
+		Payload:   payload,
+		Resource:  &mrpb.MonitoredResource{Labels: map[string]string{"pod_name": "checkout-5d8f", "container_name": "app"}},
+	})
+	assert.Equal(t, GCPLogEntry{Time: "2025-01-01T11:30:00Z", Severity: "Error", Pod: "checkout-5d8f", Container: "app", Message: "payment failed"}, entry)
+
+	assert.Equal(t, "plain line", gcpLogEntry(&logging.Entry{Payload: "plain line"}).Message)
+}
+
+func TestParseAndValidateGCPLogsQueryParams(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+	t.Setenv("GKE_CLUSTER_NAME", "prod")
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	input, err := parseAndValidateGCPLogsQueryParams(map[string]any{
+		"namespace": "shop",
+		"severity":  "warning",
+		"since":     "6h",
+		"limit":     float64(5000),
+	}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "my-project", input.ProjectID)
+	assert.Equal(t, "prod", input.Cluster)
+	assert.Equal(t, "WARNING", input.Severity)
+	assert.Equal(t, now.Add(-6*time.Hour), input.Start)
+	assert.Equal(t, maxGCPLogsLimit, input.Limit)
+
+	_, err = parseAndValidateGCPLogsQueryParams(map[string]any{}, now)
+	assert.Error(t, err)
+	_, err = parseAndValidateGCPLogsQueryParams(map[string]any{"namespace": "shop", "severity": "LOUD"}, now)
+	assert.Error(t, err)
+	_, err = parseAndValidateGCPLogsQueryParams(map[string]any{"pod": "Bad_Name"}, now)
+	assert.Error(t, err)
+}
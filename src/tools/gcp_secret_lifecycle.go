@@ -0,0 +1,272 @@
+// Environment variables used by these tools:
+// Optional:
+//   GOOGLE_APPLICATION_CREDENTIALS - Path to a GCP service account JSON file; without it gcloud ADC or Workload Identity is used
+//   GOOGLE_CLOUD_PROJECT           - GCP Project ID (used if not provided in input)
+//   SECRET_ALLOWED_NAMES           - Comma-separated secret names or glob patterns these tools may create or delete (default: any)
+//   GCP_ALLOWED_SECRETS            - Former name of SECRET_ALLOWED_NAMES, read when it is not set
+//   GCP_ALLOWED_PROJECTS           - Comma-separated project IDs these tools may access (default: any)
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// deleteProtectionLabel marks secrets that gcp_secret_delete refuses to delete.
+const deleteProtectionLabel = "delete-protection"
+
+// gcpSecretIDPattern matches valid Secret Manager secret IDs.
+var gcpSecretIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,255}$`)
+
+// GCPSecretCreateInput represents the input for creating a secret.
+type GCPSecretCreateInput struct {
+	ProjectID         string            `json:"projectId"`
+	SecretName        string            `json:"secretName"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Locations         []string          `json:"locations,omitempty"`
+	Value             string            `json:"value,omitempty"`
+	DeleteProtection  bool              `json:"deleteProtection,omitempty"`
+	VersionDestroyTTL time.Duration     `json:"versionDestroyTtl,omitempty"`
+}
+
+// GCPSecretDeleteInput represents the input for deleting a secret.
+type GCPSecretDeleteInput struct {
+	ProjectID  string `json:"projectId"`
+	SecretName string `json:"secretName"`
+	Confirm    string `json:"confirm"`
+}
+
+// GCPSecretCreateTool creates Secret Manager secrets.
+type GCPSecretCreateTool struct{}
+
+// NewGCPSecretCreateTool creates a new GCPSecretCreateTool.
+func NewGCPSecretCreateTool() *GCPSecretCreateTool {
+	return &GCPSecretCreateTool{}
+}
+
+// Tool returns the MCP tool definition for gcp_secret_create.
+func (g *GCPSecretCreateTool) Tool() mcp.Tool {
+	return mcp.NewTool("gcp_secret_create",
+		mcp.WithDescription("Create a Google Cloud Secret Manager secret with labels, a replication policy and optional delete protection, optionally adding its first version"),
+		mcp.WithString("projectId", mcp.Description("GCP Project ID (optional, will use GOOGLE_CLOUD_PROJECT env if not set)")),
+		mcp.WithString("secretName", mcp.Required(), mcp.Description("ID of the new secret (letters, digits, - and _)")),
+		mcp.WithObject("labels",
+			mcp.Description("Labels to set on the secret, e.g. {\"env\": \"prod\"}"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("locations", mcp.Description("Replicate only to these regions (user-managed replication); default is automatic replication"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithString("value", mcp.Description("Payload of the first version (optional)")),
+		mcp.WithBoolean("deleteProtection", mcp.Description("Label the secret so gcp_secret_delete refuses to delete it (default: false)")),
+		mcp.WithString("versionDestroyTtl", mcp.Description("Delay before destroyed versions are actually destroyed, e.g. 24h (optional, minimum 24h)")),
+	)
+}
+
+// Handler creates the secret and, when a value is given, its first version.
+func (g *GCPSecretCreateTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateGCPSecretCreateParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+	client, err := newSecretManagerClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	secret, err := client.CreateSecret(ctx, &secretmanagerpb.CreateSecretRequest{
+		Parent:   "projects/" + input.ProjectID,
+		SecretId: input.SecretName,
+		Secret:   gcpSecretSpec(input),
+	})
+	if err != nil {
+		return nil, gcpSecretError(err, "create secret "+input.SecretName, "roles/secretmanager.admin")
+	}
+
+	output := map[string]any{
+		"secret": summarizeGCPSecret(secret),
+		"status": "Secret created",
+	}
+	if input.Value != "" {
+		version, err := client.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
+			Parent:  secret.GetName(),
+			Payload: &secretmanagerpb.SecretPayload{Data: []byte(input.Value)},
+		})
+		if err != nil {
+			return nil, gcpSecretError(err, "add the first version of "+input.SecretName, "roles/secretmanager.secretVersionAdder")
+		}
+		output["version"] = path.Base(version.GetName())
+		output["status"] = "Secret created with its first version"
+	}
+
+	out, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// GCPSecretDeleteTool deletes Secret Manager secrets.
+type GCPSecretDeleteTool struct{}
+
+// NewGCPSecretDeleteTool creates a new GCPSecretDeleteTool.
+func NewGCPSecretDeleteTool() *GCPSecretDeleteTool {
+	return &GCPSecretDeleteTool{}
+}
+
+// Tool returns the MCP tool definition for gcp_secret_delete.
+func (g *GCPSecretDeleteTool) Tool() mcp.Tool {
+	return mcp.NewTool("gcp_secret_delete",
+		mcp.WithDescription("Delete a Google Cloud Secret Manager secret and all of its versions. Refuses secrets labeled delete-protection=true"),
+		mcp.WithString("projectId", mcp.Description("GCP Project ID (optional, will use GOOGLE_CLOUD_PROJECT env if not set)")),
+		mcp.WithString("secretName", mcp.Required(), mcp.Description("ID of the secret to delete")),
+		mcp.WithString("confirm", mcp.Required(), mcp.Description("Must repeat the secret name to confirm the deletion")),
+	)
+}
+
+// Handler checks delete protection and deletes the secret at the etag it was read at.
+func (g *GCPSecretDeleteTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateGCPSecretDeleteParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+	client, err := newSecretManagerClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	name := fmt.Sprintf("projects/%s/secrets/%s", input.ProjectID, input.SecretName)
+	secret, err := client.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{Name: name})
+	if err != nil {
+		return nil, gcpSecretError(err, "read secret "+input.SecretName, "roles/secretmanager.viewer")
+	}
+	if secret.GetLabels()[deleteProtectionLabel] == "true" {
+		return nil, fmt.Errorf("secret %s is protected by the %s=true label: remove the label first to delete it", input.SecretName, deleteProtectionLabel)
+	}
+	// The etag makes the delete fail if the secret changed since it was checked.
+	if err := client.DeleteSecret(ctx, &secretmanagerpb.DeleteSecretRequest{Name: name, Etag: secret.GetEtag()}); err != nil {
+		return nil, gcpSecretError(err, "delete secret "+input.SecretName, "roles/secretmanager.admin")
+	}
+
+	out, err := json.Marshal(map[string]any{
+		"secretName": input.SecretName,
+		"status":     "Secret and all of its versions deleted",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// gcpSecretSpec builds the secret resource for a create request.
+func gcpSecretSpec(input *GCPSecretCreateInput) *secretmanagerpb.Secret {
+	labels := map[string]string{}
+	for k, v := range input.Labels {
+		labels[k] = v
+	}
+	if input.DeleteProtection {
+		labels[deleteProtectionLabel] = "true"
+	}
+
+	secret := &secretmanagerpb.Secret{Labels: labels}
+	if len(input.Locations) == 0 {
+		secret.Replication = &secretmanagerpb.Replication{Replication: &secretmanagerpb.Replication_Automatic_{Automatic: &secretmanagerpb.Replication_Automatic{}}}
+	} else {
+		replicas := make([]*secretmanagerpb.Replication_UserManaged_Replica, 0, len(input.Locations))
+		for _, location := range input.Locations {
+			replicas = append(replicas, &secretmanagerpb.Replication_UserManaged_Replica{Location: location})
+		}
+		secret.Replication = &secretmanagerpb.Replication{Replication: &secretmanagerpb.Replication_UserManaged_{UserManaged: &secretmanagerpb.Replication_UserManaged{Replicas: replicas}}}
+	}
+	if input.VersionDestroyTTL > 0 {
+		secret.VersionDestroyTtl = durationpb.New(input.VersionDestroyTTL)
+	}
+	return secret
+}
+
+// gcpSecretError turns API errors into actionable messages, naming the IAM role that grants the
+// missing permission when the call was denied.
+func gcpSecretError(err error, action, role string) error {
+	switch status.Code(err) {
+	case codes.PermissionDenied:
+		return fmt.Errorf("permission denied to %s: grant the service account %s on the project or secret (%w)", action, role, err)
+	case codes.AlreadyExists:
+		return fmt.Errorf("failed to %s: it already exists (%w)", action, err)
+	case codes.NotFound:
+		return fmt.Errorf("failed to %s: not found (%w)", action, err)
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+// resolveGCPSecretTarget checks the project and the explicit secret name against the allowlists.
+func resolveGCPSecretTarget(args map[string]any) (string, string, error) {
+	projectID, _ := args["projectId"].(string)
+	projectID, err := resolveGCPProjectID(projectID)
+	if err != nil {
+		return "", "", err
+	}
+	secretName, _ := args["secretName"].(string)
+	if !gcpSecretIDPattern.MatchString(secretName) {
+		return "", "", fmt.Errorf("invalid secretName %q: use 1-255 letters, digits, - or _", secretName)
+	}
+	if allowed := allowedSecretsFromEnv(); len(allowed) > 0 && !secretNameAllowed(secretName, allowed) {
+		return "", "", fmt.Errorf("secret %q is not in SECRET_ALLOWED_NAMES", secretName)
+	}
+	return projectID, secretName, nil
+}
+
+func parseAndValidateGCPSecretCreateParams(args map[string]any) (*GCPSecretCreateInput, error) {
+	input := &GCPSecretCreateInput{}
+	var err error
+	if input.ProjectID, input.SecretName, err = resolveGCPSecretTarget(args); err != nil {
+		return nil, err
+	}
+	if input.Labels, err = stringMapArg(args, "labels"); err != nil {
+		return nil, err
+	}
+	for k := range input.Labels {
+		if !gcpLabelKeyPattern.MatchString(k) {
+			return nil, fmt.Errorf("invalid label name %q", k)
+		}
+	}
+	if input.Locations, err = stringSliceArg(args, "locations"); err != nil {
+		return nil, err
+	}
+	input.Value, _ = args["value"].(string)
+	input.DeleteProtection, _ = args["deleteProtection"].(bool)
+	if ttl, _ := args["versionDestroyTtl"].(string); ttl != "" {
+		if input.VersionDestroyTTL, err = time.ParseDuration(ttl); err != nil {
+			return nil, fmt.Errorf("invalid versionDestroyTtl %q: %w", ttl, err)
+		}
+		if input.VersionDestroyTTL < 24*time.Hour {
+			return nil, errors.New("versionDestroyTtl must be at least 24h")
+		}
+	}
+	return input, nil
+}
+
+func parseAndValidateGCPSecretDeleteParams(args map[string]any) (*GCPSecretDeleteInput, error) {
+	input := &GCPSecretDeleteInput{}
+	var err error
+	if input.ProjectID, input.SecretName, err = resolveGCPSecretTarget(args); err != nil {
+		return nil, err
+	}
+	input.Confirm, _ = args["confirm"].(string)
+	if strings.TrimSpace(input.Confirm) != input.SecretName {
+		return nil, fmt.Errorf("confirm must repeat the secret name %q to delete it", input.SecretName)
+	}
+	return input, nil
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGCPSecretSpec(t *testing.T) {
+	secret := gcpSecretSpec(&GCPSecretCreateInput{
+		Labels:            map[string]string{"env": "prod"},
+		Locations:         []string{"europe-west1"},
+		DeleteProtection:  true,
+		VersionDestroyTTL: 48 * time.Hour,
+	})
+	assert.Equal(t, map[string]string{"env": "prod", deleteProtectionLabel: "true"}, secret.GetLabels())
+	assert.Equal(t, "europe-west1", secret.GetReplication().GetUserManaged().GetReplicas()[0].GetLocation())
+	assert.Equal(t, 48*time.Hour, secret.GetVersionDestroyTtl().AsDuration())
+
+	assert.NotNil(t, gcpSecretSpec(&GCPSecretCreateInput{}).GetReplication().GetAutomatic())
+}
+
+func TestGCPSecretError(t *testing.T) {
+	err := gcpSecretError(status.Error(codes.PermissionDenied, "denied"), "create secret app-env", "roles/secretmanager.admin")
+	assert.Contains(t, err.Error(), "permission denied to create secret app-env: grant the service account roles/secretmanager.admin")
+	assert.Equal(t, codes.PermissionDenied, status.Code(errors.Unwrap(err)))
+
+	err = gcpSecretError(errors.New("boom"), "delete secret app-env", "roles/secretmanager.admin")
+	assert.Equal(t, "failed to delete secret app-env: boom", err.Error())
+}
+
+func TestParseAndValidateGCPSecretLifecycleParams(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+	t.Setenv("GCP_ALLOWED_SECRETS", "app-*")
+
+	input, err := parseAndValidateGCPSecretCreateParams(map[string]any{"secretName": "app-env", "labels": map[string]any{"env": "prod"}, "versionDestroyTtl": "24h"})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-project", input.ProjectID)
+	assert.Equal(t, 24*time.Hour, input.VersionDestroyTTL)
+
+	_, err = parseAndValidateGCPSecretCreateParams(map[string]any{"secretName": "app-env", "versionDestroyTtl": "1h"})
+	assert.Error(t, err)
+	_, err = parseAndValidateGCPSecretCreateParams(map[string]any{"secretName": "db-root"})
+	assert.Error(t, err)
+	_, err = parseAndValidateGCPSecretCreateParams(map[string]any{"secretName": "app/env"})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateGCPSecretDeleteParams(map[string]any{"secretName": "app-env", "confirm": "app-env"})
+	assert.NoError(t, err)
+	_, err = parseAndValidateGCPSecretDeleteParams(map[string]any{"secretName": "app-env", "confirm": "yes"})
+	assert.Error(t, err)
+}
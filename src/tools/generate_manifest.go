@@ -0,0 +1,435 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultGeneratePort     = 8080
+	defaultGenerateSchedule = "0 * * * *"
+	// podSecurityEnforceLabel is the namespace label with the Pod Security Standard pods must meet.
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+)
+
+var (
+	ingressClassGVR = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingressclasses"}
+	storageClassGVR = schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1", Resource: "storageclasses"}
+	namespaceGVR    = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+)
+
+// generateKinds are the kinds generate_manifest can produce, in the order they are emitted.
+var generateKinds = []string{"Deployment", "Service", "Ingress", "CronJob"}
+
+// defaultClassAnnotations mark the default IngressClass and StorageClass.
+var defaultClassAnnotations = []string{"ingressclass.kubernetes.io/is-default-class", "storageclass.kubernetes.io/is-default-class"}
+
+// GenerateManifestInput represents the input for generate_manifest.
+type GenerateManifestInput struct {
+	Kinds     []string `json:"kinds"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Image     string   `json:"image,omitempty"`
+	Port      int      `json:"port"`
+	Host      string   `json:"host,omitempty"`
+	Schedule  string   `json:"schedule,omitempty"`
+	Storage   string   `json:"storage,omitempty"`
+	Validate  bool     `json:"validate"`
+}
+
+// ClusterDefaults are the settings of the cluster and namespace a generated manifest is fitted to.
+type ClusterDefaults struct {
+	IngressClass    string            `json:"ingressClass,omitempty"`
+	StorageClass    string            `json:"storageClass,omitempty"`
+	PodSecurity     string            `json:"podSecurity,omitempty"`
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+	Notes           []string          `json:"notes,omitempty"`
+}
+
+// GenerateManifestTool writes manifest skeletons that fit the cluster they are generated for.
+type GenerateManifestTool struct {
+	client Client
+}
+
+// NewGenerateManifestTool creates a new GenerateManifestTool with the provided Kubernetes client.
+func NewGenerateManifestTool(client Client) *GenerateManifestTool {
+	return &GenerateManifestTool{client: client}
+}
+
+// Tool returns the MCP tool definition for generate_manifest.
+func (g *GenerateManifestTool) Tool() mcp.Tool {
+	return mcp.NewTool("generate_manifest",
+		mcp.WithDescription("Generate Deployment, Service, Ingress and CronJob skeletons for an app, filled in from the cluster: the default IngressClass, the default StorageClass for a volume, and a security context that meets the namespace's Pod Security level. Containers get resource requests, a memory limit and probes. The result is validated with a server-side dry run, so it applies cleanly. Nothing is created"),
+		mcp.WithArray("kinds",
+			mcp.Required(),
+			mcp.Description("Kinds to generate: Deployment, Service, Ingress and/or CronJob"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the app, used for the objects and the app.kubernetes.io/name label"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the objects (default: default)"),
+		),
+		mcp.WithString("image",
+			mcp.Description("Container image; required for a Deployment or CronJob"),
+		),
+		mcp.WithNumber("port",
+			mcp.Description(fmt.Sprintf("Port the container listens on (default: %d)", defaultGeneratePort)),
+		),
+		mcp.WithString("host",
+			mcp.Description("Host name of the Ingress (default: <name>.example.com)"),
+		),
+		mcp.WithString("schedule",
+			mcp.Description(fmt.Sprintf("Cron schedule of the CronJob (default: %q)", defaultGenerateSchedule)),
+		),
+		mcp.WithString("storage",
+			mcp.Description("Size of a persistent volume for the Deployment, e.g. 1Gi; adds a PersistentVolumeClaim of the default StorageClass mounted at /data"),
+		),
+		mcp.WithBoolean("validate",
+			mcp.Description("Validate the result with a server-side dry-run apply (default: true)"),
+		),
+	)
+}
+
+// Handler reads the cluster defaults, builds the objects and validates them.
+func (g *GenerateManifestTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateGenerateManifestParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate generate_manifest params: %w", err)
+	}
+
+	defaults, err := g.clusterDefaults(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	objects := generateObjects(input, defaults)
+
+	docs := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		doc, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", obj.GetKind(), err)
+		}
+		docs = append(docs, string(doc))
+	}
+	result := map[string]any{
+		"manifest": strings.Join(docs, "---\n"),
+		"cluster":  defaults,
+	}
+
+	if input.Validate {
+		mapper, err := g.client.RESTMapper()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+		}
+		validator := NewValidateManifestTool(g.client)
+		options := &ValidateManifestInput{Namespace: input.Namespace, ServerSide: true}
+		results := make([]ManifestDocumentResult, 0, len(objects))
+		valid := true
+		for i, obj := range objects {
+			r := validator.validateDocument(ctx, mapper, i, obj.DeepCopy(), options)
+			valid = valid && r.Valid
+			results = append(results, r)
+		}
+		result["valid"] = valid
+		result["validation"] = results
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// clusterDefaults looks up the namespace labels and, when the manifest needs them, the default
+// IngressClass and StorageClass.
+func (g *GenerateManifestTool) clusterDefaults(ctx context.Context, input *GenerateManifestInput) (*ClusterDefaults, error) {
+	defaults := &ClusterDefaults{}
+
+	ri, err := g.client.ResourceInterface(namespaceGVR, false, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource interface: %w", err)
+	}
+	ns, err := ri.Get(ctx, input.Namespace, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		defaults.Notes = append(defaults.Notes, fmt.Sprintf("namespace %s does not exist yet; create it before applying", input.Namespace))
+	case err != nil:
+		defaults.Notes = append(defaults.Notes, fmt.Sprintf("namespace %s could not be read: %v", input.Namespace, err))
+	default:
+		defaults.NamespaceLabels = ns.GetLabels()
+		defaults.PodSecurity = ns.GetLabels()[podSecurityEnforceLabel]
+	}
+
+	if slices.Contains(input.Kinds, "Ingress") {
+		name, note, err := g.defaultClass(ctx, ingressClassGVR, "IngressClass")
+		if err != nil {
+			return nil, err
+		}
+		defaults.IngressClass = name
+		if note != "" {
+			defaults.Notes = append(defaults.Notes, note)
+		}
+	}
+	if input.Storage != "" && slices.Contains(input.Kinds, "Deployment") {
+		name, note, err := g.defaultClass(ctx, storageClassGVR, "StorageClass")
+		if err != nil {
+			return nil, err
+		}
+		defaults.StorageClass = name
+		if note != "" {
+			defaults.Notes = append(defaults.Notes, note)
+		}
+	}
+	return defaults, nil
+}
+
+// defaultClass returns the IngressClass or StorageClass marked as default, the only one when there
+// is a single class, and otherwise the first by name with a note explaining the choice.
+func (g *GenerateManifestTool) defaultClass(ctx context.Context, gvr schema.GroupVersionResource, kind string) (string, string, error) {
+	ri, err := g.client.ResourceInterface(gvr, false, "")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create resource interface: %w", err)
+	}
+	list, err := ri.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Sprintf("%s list failed, the field is left out: %v", kind, err), nil
+	}
+	var names []string
+	for _, item := range list.Items {
+		for _, annotation := range defaultClassAnnotations {
+			if item.GetAnnotations()[annotation] == "true" {
+				return item.GetName(), "", nil
+			}
+		}
+		names = append(names, item.GetName())
+	}
+	switch len(names) {
+	case 0:
+		return "", fmt.Sprintf("the cluster has no %s; the cluster default, if any, applies", kind), nil
+	case 1:
+		return names[0], "", nil
+	}
+	sort.Strings(names)
+	return names[0], fmt.Sprintf("no %s is marked as default; chose %s of %s", kind, names[0], strings.Join(names, ", ")), nil
+}
+
+// generateObjects builds the requested objects, in the order of generateKinds, plus a
+// PersistentVolumeClaim when the Deployment has storage.
+func generateObjects(input *GenerateManifestInput, defaults *ClusterDefaults) []*unstructured.Unstructured {
+	labels := map[string]any{"app.kubernetes.io/name": input.Name}
+	metadata := func(name string) map[string]any {
+		return map[string]any{"name": name, "namespace": input.Namespace, "labels": labels}
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, kind := range generateKinds {
+		if !slices.Contains(input.Kinds, kind) {
+			continue
+		}
+		switch kind {
+		case "Deployment":
+			podSpec := generatePodSpec(input, defaults, true)
+			if input.Storage != "" {
+				claim := map[string]any{
+					"accessModes": []any{"ReadWriteOnce"},
+					"resources":   map[string]any{"requests": map[string]any{"storage": input.Storage}},
+				}
+				if defaults.StorageClass != "" {
+					claim["storageClassName"] = defaults.StorageClass
+				}
+				objects = append(objects, &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "v1",
+					"kind":       "PersistentVolumeClaim",
+					"metadata":   metadata(input.Name + "-data"),
+					"spec":       claim,
+				}})
+				podSpec["volumes"] = []any{map[string]any{
+					"name":                  "data",
+					"persistentVolumeClaim": map[string]any{"claimName": input.Name + "-data"},
+				}}
+				container := podSpec["containers"].([]any)[0].(map[string]any)
+				container["volumeMounts"] = []any{map[string]any{"name": "data", "mountPath": "/data"}}
+			}
+			objects = append(objects, &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   metadata(input.Name),
+				"spec": map[string]any{
+					"replicas": int64(1),
+					"selector": map[string]any{"matchLabels": labels},
+					"template": map[string]any{
+						"metadata": map[string]any{"labels": labels},
+						"spec":     podSpec,
+					},
+				},
+			}})
+		case "Service":
+			objects = append(objects, &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   metadata(input.Name),
+				"spec": map[string]any{
+					"selector": labels,
+					"ports": []any{map[string]any{
+						"name":       "http",
+						"port":       int64(80),
+						"targetPort": "http",
+					}},
+				},
+			}})
+		case "Ingress":
+			spec := map[string]any{
+				"rules": []any{map[string]any{
+					"host": input.Host,
+					"http": map[string]any{"paths": []any{map[string]any{
+						"path":     "/",
+						"pathType": "Prefix",
+						"backend": map[string]any{"service": map[string]any{
+							"name": input.Name,
+							"port": map[string]any{"name": "http"},
+						}},
+					}}},
+				}},
+			}
+			if defaults.IngressClass != "" {
+				spec["ingressClassName"] = defaults.IngressClass
+			}
+			objects = append(objects, &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "networking.k8s.io/v1",
+				"kind":       "Ingress",
+				"metadata":   metadata(input.Name),
+				"spec":       spec,
+			}})
+		case "CronJob":
+			podSpec := generatePodSpec(input, defaults, false)
+			podSpec["restartPolicy"] = "OnFailure"
+			objects = append(objects, &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "batch/v1",
+				"kind":       "CronJob",
+				"metadata":   metadata(input.Name),
+				"spec": map[string]any{
+					"schedule":          input.Schedule,
+					"concurrencyPolicy": "Forbid",
+					"jobTemplate": map[string]any{"spec": map[string]any{
+						"backoffLimit": int64(2),
+						"template": map[string]any{
+							"metadata": map[string]any{"labels": labels},
+							"spec":     podSpec,
+						},
+					}},
+				},
+			}})
+		}
+	}
+	return objects
+}
+
+// generatePodSpec returns a pod spec with one container that has requests, a memory limit and, for
+// long-running pods, a named port and TCP probes. Namespaces enforcing the restricted Pod Security
+// Standard also get a compliant security context.
+func generatePodSpec(input *GenerateManifestInput, defaults *ClusterDefaults, serving bool) map[string]any {
+	container := map[string]any{
+		"name":  input.Name,
+		"image": input.Image,
+		"resources": map[string]any{
+			"requests": map[string]any{"cpu": "100m", "memory": "128Mi"},
+			"limits":   map[string]any{"memory": "256Mi"},
+		},
+	}
+	if serving {
+		container["ports"] = []any{map[string]any{"name": "http", "containerPort": int64(input.Port)}}
+		container["readinessProbe"] = map[string]any{"tcpSocket": map[string]any{"port": "http"}}
+		container["livenessProbe"] = map[string]any{"tcpSocket": map[string]any{"port": "http"}, "initialDelaySeconds": int64(10)}
+	}
+
+	podSpec := map[string]any{"containers": []any{container}}
+	if defaults.PodSecurity == "restricted" {
+		podSpec["securityContext"] = map[string]any{
+			"runAsNonRoot":   true,
+			"seccompProfile": map[string]any{"type": "RuntimeDefault"},
+		}
+		container["securityContext"] = map[string]any{
+			"allowPrivilegeEscalation": false,
+			"capabilities":             map[string]any{"drop": []any{"ALL"}},
+		}
+	}
+	return podSpec
+}
+
+func parseAndValidateGenerateManifestParams(args map[string]any) (*GenerateManifestInput, error) {
+	input := &GenerateManifestInput{Namespace: "default", Port: defaultGeneratePort, Schedule: defaultGenerateSchedule, Validate: true}
+
+	kinds, err := stringSliceArg(args, "kinds")
+	if err != nil {
+		return nil, err
+	}
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("kinds is required")
+	}
+	for _, kind := range kinds {
+		i := slices.IndexFunc(generateKinds, func(k string) bool { return strings.EqualFold(k, kind) })
+		if i < 0 {
+			return nil, fmt.Errorf("unsupported kind %q: must be one of %s", kind, strings.Join(generateKinds, ", "))
+		}
+		input.Kinds = append(input.Kinds, generateKinds[i])
+	}
+
+	input.Name, _ = args["name"].(string)
+	if input.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid name: %w", err)
+	}
+	if namespace, ok := args["namespace"].(string); ok && namespace != "" {
+		if err := validation.ValidateNamespace(namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = namespace
+	}
+
+	input.Image, _ = args["image"].(string)
+	if input.Image == "" && (slices.Contains(input.Kinds, "Deployment") || slices.Contains(input.Kinds, "CronJob")) {
+		return nil, fmt.Errorf("image is required for a Deployment or CronJob")
+	}
+	if port, ok := args["port"].(float64); ok {
+		if port < 1 || port > 65535 || port != float64(int(port)) {
+			return nil, fmt.Errorf("port must be an integer between 1 and 65535")
+		}
+		input.Port = int(port)
+	}
+	input.Host, _ = args["host"].(string)
+	if input.Host == "" {
+		input.Host = input.Name + ".example.com"
+	}
+	if schedule, ok := args["schedule"].(string); ok && schedule != "" {
+		input.Schedule = schedule
+	}
+	if storage, ok := args["storage"].(string); ok && storage != "" {
+		if _, err := resource.ParseQuantity(storage); err != nil {
+			return nil, fmt.Errorf("invalid storage %q: %w", storage, err)
+		}
+		input.Storage = storage
+	}
+	if validate, ok := args["validate"].(bool); ok {
+		input.Validate = validate
+	}
+	return input, nil
+}
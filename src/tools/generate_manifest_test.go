@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+type fakeGenerateClient struct {
+	fakeValidateClient
+	objects []runtime.Object
+}
+
+func (f *fakeGenerateClient) RESTMapper() (meta.RESTMapper, error) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Version: "v1", Kind: "Service"},
+		{Version: "v1", Kind: "PersistentVolumeClaim"},
+		{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+		{Group: "batch", Version: "v1", Kind: "CronJob"},
+	} {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	return mapper, nil
+}
+
+func (f *fakeGenerateClient) ResourceInterface(gvr schema.GroupVersionResource, namespaced bool, ns string) (dynamic.ResourceInterface, error) {
+	if gvr == namespaceGVR || gvr == ingressClassGVR || gvr == storageClassGVR {
+		client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			namespaceGVR:    "NamespaceList",
+			ingressClassGVR: "IngressClassList",
+			storageClassGVR: "StorageClassList",
+		}, f.objects...)
+		return client.Resource(gvr), nil
+	}
+	return f.fakeValidateClient.ResourceInterface(gvr, namespaced, ns)
+}
+
+func clusterObject(apiVersion, kind, name string, labels, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{"apiVersion": apiVersion, "kind": kind}}
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func TestGenerateManifest(t *testing.T) {
+	client := &fakeGenerateClient{objects: []runtime.Object{
+		clusterObject("v1", "Namespace", "shop", map[string]string{podSecurityEnforceLabel: "restricted"}, nil),
+		clusterObject("networking.k8s.io/v1", "IngressClass", "internal", nil, nil),
+		clusterObject("networking.k8s.io/v1", "IngressClass", "nginx", nil, map[string]string{"ingressclass.kubernetes.io/is-default-class": "true"}),
+		clusterObject("storage.k8s.io/v1", "StorageClass", "standard", nil, nil),
+		clusterObject("storage.k8s.io/v1", "StorageClass", "fast", nil, nil),
+	}}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"kinds":     []any{"ingress", "Deployment", "Service"},
+		"name":      "web",
+		"namespace": "shop",
+		"image":     "nginx:1.27",
+		"storage":   "1Gi",
+	}
+	result, err := NewGenerateManifestTool(client).Handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	var out struct {
+		Manifest   string                   `json:"manifest"`
+		Cluster    ClusterDefaults          `json:"cluster"`
+		Valid      bool                     `json:"valid"`
+		Validation []ManifestDocumentResult `json:"validation"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+
+	assert.Equal(t, "nginx", out.Cluster.IngressClass)
+	assert.Equal(t, "fast", out.Cluster.StorageClass)
+	assert.Equal(t, "restricted", out.Cluster.PodSecurity)
+	assert.Equal(t, []string{"no StorageClass is marked as default; chose fast of fast, standard"}, out.Cluster.Notes)
+	assert.True(t, out.Valid)
+	assert.Equal(t, []string{"shop/web-data", "shop/web", "shop/web", "shop/web"}, client.applied)
+
+	objects, err := parseYAMLObjects([]byte(out.Manifest))
+	assert.NoError(t, err)
+	var kinds []string
+	for _, obj := range objects {
+		kinds = append(kinds, obj.GetKind())
+	}
+	assert.Equal(t, []string{"PersistentVolumeClaim", "Deployment", "Service", "Ingress"}, kinds)
+
+	for _, r := range out.Validation {
+		assert.Empty(t, r.Warnings, r.Kind)
+	}
+	class, _, _ := unstructured.NestedString(objects[0].Object, "spec", "storageClassName")
+	assert.Equal(t, "fast", class)
+	nonRoot, _, _ := unstructured.NestedBool(objects[1].Object, "spec", "template", "spec", "securityContext", "runAsNonRoot")
+	assert.True(t, nonRoot)
+	ingressClass, _, _ := unstructured.NestedString(objects[3].Object, "spec", "ingressClassName")
+	assert.Equal(t, "nginx", ingressClass)
+}
+
+func TestGenerateManifestWithoutClusterDefaults(t *testing.T) {
+	client := &fakeGenerateClient{}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"kinds": []any{"CronJob", "Ingress"}, "name": "report", "image": "report:2", "validate": false}
+	result, err := NewGenerateManifestTool(client).Handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	var out map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	assert.Equal(t, []any{
+		"namespace default does not exist yet; create it before applying",
+		"the cluster has no IngressClass; the cluster default, if any, applies",
+	}, out["cluster"].(map[string]any)["notes"])
+	assert.Nil(t, out["validation"])
+	assert.Empty(t, client.applied)
+
+	objects, err := parseYAMLObjects([]byte(out["manifest"].(string)))
+	assert.NoError(t, err)
+	assert.Equal(t, "CronJob", objects[1].GetKind())
+	schedule, _, _ := unstructured.NestedString(objects[1].Object, "spec", "schedule")
+	assert.Equal(t, defaultGenerateSchedule, schedule)
+	assert.Nil(t, lintManifestObject(objects[1]))
+	_, found, _ := unstructured.NestedString(objects[0].Object, "spec", "ingressClassName")
+	assert.False(t, found)
+}
+
+func TestParseAndValidateGenerateManifestParams(t *testing.T) {
+	testCases := []struct {
+		name string
+		args map[string]any
+		err  string
+	}{
+		{name: "no kinds", args: map[string]any{"name": "web"}, err: "kinds is required"},
+		{name: "unsupported kind", args: map[string]any{"kinds": []any{"StatefulSet"}, "name": "web"}, err: `unsupported kind "StatefulSet": must be one of Deployment, Service, Ingress, CronJob`},
+		{name: "no image", args: map[string]any{"kinds": []any{"Deployment"}, "name": "web"}, err: "image is required for a Deployment or CronJob"},
+		{name: "bad port", args: map[string]any{"kinds": []any{"Service"}, "name": "web", "port": float64(70000)}, err: "port must be an integer between 1 and 65535"},
+		{name: "bad storage", args: map[string]any{"kinds": []any{"Deployment"}, "name": "web", "image": "a", "storage": "lots"}, err: `invalid storage "lots": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`},
+		{name: "service only", args: map[string]any{"kinds": []any{"service"}, "name": "web"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input, err := parseAndValidateGenerateManifestParams(tc.args)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Service"}, input.Kinds)
+			assert.Equal(t, "web.example.com", input.Host)
+		})
+	}
+}
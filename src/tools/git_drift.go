@@ -0,0 +1,577 @@
+// Environment variables used by this tool:
+// Optional:
+//   GIT_DRIFT_ROOT                 - Directory holding repository checkouts git_drift and kustomize_build may read
+//   GIT_DRIFT_ALLOWED_HOSTS        - Comma-separated hosts git_drift and kustomize_build may download repository archives (.tar.gz) from
+
+package tools
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	maxGitDriftObjects = 500
+	// maxArchiveBytes bounds downloaded repository archives and Flux artifacts.
+	maxArchiveBytes = 50 << 20
+	// maxRepositoryFiles bounds the files read from an archive or checkout.
+	maxRepositoryFiles = 5000
+	archiveTimeout     = 30 * time.Second
+)
+
+// gitDriftIgnoredFields are set by the API server or by the tools that apply the manifests.
+var gitDriftIgnoredFields = []string{"metadata.namespace", "status"}
+
+// GitDriftField is a field whose live value differs from the repository; an empty live value
+// means the field is not set in the cluster.
+type GitDriftField struct {
+	Field string `json:"field"`
+	Repo  string `json:"repo"`
+	Live  string `json:"live"`
+}
+
+// GitDriftObject is a repository object that is missing from the cluster or changed out of band.
+type GitDriftObject struct {
+	File        string          `json:"file"`
+	Kind        string          `json:"kind"`
+	Name        string          `json:"name"`
+	Namespace   string          `json:"namespace,omitempty"`
+	Differences []GitDriftField `json:"differences,omitempty"`
+}
+
+// GitDriftInput represents the input for git_drift.
+type GitDriftInput struct {
+	Path            string `json:"path,omitempty"`
+	GitRepository   string `json:"gitRepository,omitempty"`
+	SourceNamespace string `json:"sourceNamespace,omitempty"`
+	URL             string `json:"url,omitempty"`
+	Namespace       string `json:"namespace"`
+}
+
+// GitDriftTool compares the manifests of a Git repository with the live cluster objects.
+type GitDriftTool struct {
+	client Client
+}
+
+// NewGitDriftTool creates a new GitDriftTool with the provided Kubernetes client.
+func NewGitDriftTool(client Client) *GitDriftTool {
+	return &GitDriftTool{client: client}
+}
+
+// Tool returns the MCP tool definition for git_drift.
+func (g *GitDriftTool) Tool() mcp.Tool {
+	return mcp.NewTool("git_drift",
+		mcp.WithDescription("Compare the manifests of a Git repository with the live cluster objects and report resources modified out of band: fields whose live value differs from the repository, and repository objects missing from the cluster. Manifests come from the artifact of a Flux GitRepository (gitRepository), a .tar.gz archive of the repository from a host in GIT_DRIFT_ALLOWED_HOSTS (url), or a checkout under GIT_DRIFT_ROOT (default). Plain YAML and JSON files are compared; kustomization.yaml, Helm templates and SOPS-encrypted files are skipped because they need rendering"),
+		mcp.WithString("path",
+			mcp.Description("Directory of the manifests, relative to the repository root (or to GIT_DRIFT_ROOT for a checkout)"),
+		),
+		mcp.WithString("gitRepository",
+			mcp.Description("Name of a Flux GitRepository whose latest artifact is compared"),
+		),
+		mcp.WithString("sourceNamespace",
+			mcp.Description(fmt.Sprintf("Namespace of the GitRepository (default: %s)", fluxNamespace)),
+		),
+		mcp.WithString("url",
+			mcp.Description("HTTPS URL of a .tar.gz archive of the repository, e.g. https://github.com/org/repo/archive/refs/heads/main.tar.gz; the host must be listed in GIT_DRIFT_ALLOWED_HOSTS"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of manifests that do not set one (default: default)"),
+		),
+	)
+}
+
+// Handler loads the manifests and compares each object with its live counterpart.
+func (g *GitDriftTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateGitDriftParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate git_drift params: %w", err)
+	}
+
+	var files map[string][]byte
+	source := map[string]any{}
+	switch {
+	case input.GitRepository != "":
+		if err := auth.AuthorizeNamespace(ctx, input.SourceNamespace); err != nil {
+			return nil, fmt.Errorf("invalid sourceNamespace: %w", err)
+		}
+		archive, revision, err := fetchFluxArtifact(ctx, g.client, "GitRepository", input.SourceNamespace, input.GitRepository)
+		if err != nil {
+			return nil, err
+		}
+		if files, err = manifestFilesFromArchive(archive, input.Path, false); err != nil {
+			return nil, err
+		}
+		source["gitRepository"] = input.SourceNamespace + "/" + input.GitRepository
+		source["revision"] = revision
+	case input.URL != "":
+		archive, err := downloadArchive(ctx, input.URL)
+		if err != nil {
+			return nil, err
+		}
+		if files, err = manifestFilesFromArchive(archive, input.Path, true); err != nil {
+			return nil, err
+		}
+		source["url"] = input.URL
+	default:
+		if files, err = manifestFilesFromCheckout(input.Path); err != nil {
+			return nil, err
+		}
+		source["checkout"] = input.Path
+	}
+	source["path"] = input.Path
+
+	mapper, err := g.client.RESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+	drifted, missing := []GitDriftObject{}, []GitDriftObject{}
+	skipped := map[string]string{}
+	failed := map[string]string{}
+	compared := 0
+	for _, file := range sortedFileNames(files) {
+		objects, reason := manifestObjects(files[file])
+		if reason != "" {
+			skipped[file] = reason
+			continue
+		}
+		for _, obj := range objects {
+			if compared == maxGitDriftObjects {
+				return nil, fmt.Errorf("repository has more than %d objects: narrow it down with path", maxGitDriftObjects)
+			}
+			compared++
+			result, found, err := g.compareObject(ctx, mapper, obj, input.Namespace)
+			result.File = file
+			switch {
+			case err != nil:
+				failed[fmt.Sprintf("%s %s/%s", file, result.Kind, result.Name)] = err.Error()
+			case !found:
+				missing = append(missing, result)
+			case len(result.Differences) > 0:
+				drifted = append(drifted, result)
+			}
+		}
+	}
+
+	out := map[string]any{
+		"source":   source,
+		"compared": compared,
+		"inSync":   compared - len(drifted) - len(missing) - len(failed),
+		"drifted":  drifted,
+		"missing":  missing,
+	}
+	if len(skipped) > 0 {
+		out["skipped"] = skipped
+	}
+	if len(failed) > 0 {
+		out["errors"] = failed
+	}
+	result, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// compareObject gets the live counterpart of a repository object and returns the fields that differ.
+func (g *GitDriftTool) compareObject(ctx context.Context, mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace string) (GitDriftObject, bool, error) {
+	result := GitDriftObject{Kind: obj.GetKind(), Name: obj.GetName()}
+	if result.Name == "" {
+		return result, false, errors.New("metadata.name is not set")
+	}
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return result, false, fmt.Errorf("%s is not served by the cluster: %w", gvk.String(), err)
+	}
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if namespaced {
+		result.Namespace = obj.GetNamespace()
+		if result.Namespace == "" {
+			result.Namespace = namespace
+		}
+	}
+	ri, err := g.client.ResourceInterface(mapping.Resource, namespaced, result.Namespace)
+	if err != nil {
+		return result, false, fmt.Errorf("failed to create resource interface: %w", err)
+	}
+	live, err := ri.Get(ctx, result.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return result, false, nil
+	}
+	if err != nil {
+		return result, false, fmt.Errorf("failed to get live object: %w", err)
+	}
+	result.Differences = gitDriftFields(obj.Object, live.Object, result.Kind == "Secret")
+	return result, true, nil
+}
+
+// gitDriftFields returns the fields set in the repository object whose live value differs. Fields
+// only set in the cluster, such as defaults, are not drift; quantities are compared by value.
+func gitDriftFields(desired, live map[string]any, secret bool) []GitDriftField {
+	want, have := map[string]string{}, map[string]string{}
+	flattenFields("", desired, want)
+	flattenFields("", live, have)
+
+	var differences []GitDriftField
+	for field, repo := range want {
+		if ignoredGitDriftField(field) {
+			continue
+		}
+		current := have[field]
+		if current == repo || equalQuantities(repo, current) {
+			continue
+		}
+		if secret && (strings.HasPrefix(field, "data") || strings.HasPrefix(field, "stringData")) {
+			repo, current = "(redacted)", "(redacted)"
+		}
+		differences = append(differences, GitDriftField{Field: field, Repo: diffValue(repo), Live: diffValue(current)})
+	}
+	sort.Slice(differences, func(i, j int) bool { return differences[i].Field < differences[j].Field })
+	return differences
+}
+
+// ignoredGitDriftField reports whether a field is gitDriftIgnoredFields or below one of them.
+func ignoredGitDriftField(field string) bool {
+	for _, ignored := range gitDriftIgnoredFields {
+		if field == ignored || strings.HasPrefix(field, ignored+".") || strings.HasPrefix(field, ignored+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// equalQuantities reports whether two JSON values are the same resource quantity written
+// differently, e.g. "1000m" and "1", as the API server normalizes them.
+func equalQuantities(a, b string) bool {
+	parse := func(v string) (resource.Quantity, bool) {
+		if unquoted, err := strconv.Unquote(v); err == nil {
+			v = unquoted
+		}
+		q, err := resource.ParseQuantity(v)
+		return q, err == nil
+	}
+	qa, okA := parse(a)
+	qb, okB := parse(b)
+	return okA && okB && qa.Cmp(qb) == 0
+}
+
+// manifestObjects parses a manifest file, returning why it was skipped when it cannot be compared
+// as is.
+func manifestObjects(data []byte) ([]*unstructured.Unstructured, string) {
+	if bytes.Contains(data, []byte("{{")) {
+		return nil, "template: needs rendering"
+	}
+	objects, err := parseYAMLObjects(data)
+	if err != nil {
+		return nil, err.Error()
+	}
+	var kept []*unstructured.Unstructured
+	for _, obj := range objects {
+		if _, encrypted := obj.Object["sops"]; encrypted {
+			return nil, "SOPS-encrypted"
+		}
+		if obj.GetKind() == "Kustomization" && obj.GroupVersionKind().Group == "kustomize.config.k8s.io" {
+			return nil, "kustomization: needs rendering"
+		}
+		kept = append(kept, obj)
+	}
+	return kept, ""
+}
+
+// isManifestFile reports whether a repository file can hold manifests, skipping hidden files and
+// directories.
+func isManifestFile(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") && part != "." {
+			return false
+		}
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// manifestFilesFromArchive reads the manifest files under dir of a .tar.gz archive. With strip,
+// the single top-level directory of GitHub and GitLab archives is removed first.
+func manifestFilesFromArchive(archive []byte, dir string, strip bool) (map[string][]byte, error) {
+	files, err := readArchive(archive, isManifestFile, strip)
+	if err != nil {
+		return nil, err
+	}
+	return filesUnder(files, dir), nil
+}
+
+// readArchive returns the regular files of a .tar.gz archive that keep accepts, skipping files
+// larger than maxManifestBytes.
+func readArchive(archive []byte, keep func(name string) bool, strip bool) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if header.Typeflag != tar.TypeReg || header.Size > maxManifestBytes || !filepath.IsLocal(name) || !keep(name) {
+			continue
+		}
+		if len(files) == maxRepositoryFiles {
+			return nil, fmt.Errorf("archive has more than %d files", maxRepositoryFiles)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from archive: %w", name, err)
+		}
+		files[name] = data
+	}
+	if strip {
+		files = stripArchivePrefix(files)
+	}
+	return files, nil
+}
+
+// stripArchivePrefix removes a top-level directory shared by every file, as in repo-main/.
+func stripArchivePrefix(files map[string][]byte) map[string][]byte {
+	prefix := ""
+	for name := range files {
+		top, _, ok := strings.Cut(name, "/")
+		if !ok || (prefix != "" && top != prefix) {
+			return files
+		}
+		prefix = top
+	}
+	if prefix == "" {
+		return files
+	}
+	stripped := make(map[string][]byte, len(files))
+	for name, data := range files {
+		stripped[strings.TrimPrefix(name, prefix+"/")] = data
+	}
+	return stripped
+}
+
+// filesUnder keeps the files below dir, named relative to the repository root.
+func filesUnder(files map[string][]byte, dir string) map[string][]byte {
+	dir = strings.Trim(path.Clean("/"+dir), "/")
+	if dir == "" {
+		return files
+	}
+	kept := map[string][]byte{}
+	for name, data := range files {
+		if strings.HasPrefix(name, dir+"/") {
+			kept[name] = data
+		}
+	}
+	return kept
+}
+
+// manifestFilesFromCheckout reads the manifest files under dir of GIT_DRIFT_ROOT.
+func manifestFilesFromCheckout(dir string) (map[string][]byte, error) {
+	return readCheckout(dir, isManifestFile)
+}
+
+// readCheckout reads the files under dir of GIT_DRIFT_ROOT that keep accepts, named relative to
+// GIT_DRIFT_ROOT. Hidden directories and files larger than maxManifestBytes are skipped.
+func readCheckout(dir string, keep func(name string) bool) (map[string][]byte, error) {
+	root := os.Getenv("GIT_DRIFT_ROOT")
+	if root == "" {
+		return nil, fmt.Errorf("reading checkouts is disabled: set GIT_DRIFT_ROOT, or pass gitRepository or url")
+	}
+	if dir != "" && !filepath.IsLocal(dir) {
+		return nil, fmt.Errorf("invalid path %q: must be a relative directory inside GIT_DRIFT_ROOT", dir)
+	}
+	files := map[string][]byte{}
+	err := filepath.WalkDir(filepath.Join(root, dir), func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			if rel != "." && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !keep(rel) {
+			return nil
+		}
+		if info, err := entry.Info(); err != nil || info.Size() > maxManifestBytes {
+			return nil
+		}
+		if len(files) == maxRepositoryFiles {
+			return fmt.Errorf("more than %d files: narrow it down with path", maxRepositoryFiles)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[rel] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+	return files, nil
+}
+
+// fetchFluxArtifact downloads the latest artifact of a Flux source from source-controller through
+// the API server's service proxy, so it works from outside the cluster.
+func fetchFluxArtifact(ctx context.Context, client Client, kind, namespace, name string) ([]byte, string, error) {
+	ri, err := fluxResourceInterface(client, kind, namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	source, err := ri.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get %s %s/%s: %w", kind, namespace, name, err)
+	}
+	artifactURL, _, _ := unstructured.NestedString(source.Object, "status", "artifact", "url")
+	revision, _, _ := unstructured.NestedString(source.Object, "status", "artifact", "revision")
+	if artifactURL == "" {
+		return nil, "", fmt.Errorf("%s %s/%s has no artifact yet", kind, namespace, name)
+	}
+	service, serviceNamespace, port, artifactPath, err := serviceProxyTarget(artifactURL)
+	if err != nil {
+		return nil, "", err
+	}
+	clientset, err := client.Clientset()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get clientset: %w", err)
+	}
+	data, err := clientset.CoreV1().Services(serviceNamespace).ProxyGet("http", service, port, artifactPath, nil).DoRaw(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download artifact from %s: %w", artifactURL, err)
+	}
+	return data, revision, nil
+}
+
+// serviceProxyTarget splits an in-cluster URL such as
+// http://source-controller.flux-system.svc.cluster.local./gitrepository/... into the service,
+// namespace, port and path to reach it through the service proxy.
+func serviceProxyTarget(rawURL string) (service, namespace, port, urlPath string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", "", "", fmt.Errorf("invalid artifact URL %q: %w", rawURL, err)
+	}
+	parts := strings.Split(strings.TrimSuffix(u.Hostname(), "."), ".")
+	if len(parts) < 2 {
+		return "", "", "", "", fmt.Errorf("artifact URL %q does not name a service and namespace", rawURL)
+	}
+	return parts[0], parts[1], u.Port(), u.Path, nil
+}
+
+// downloadArchive fetches a repository archive from a host in GIT_DRIFT_ALLOWED_HOSTS.
+func downloadArchive(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" {
+		return nil, fmt.Errorf("url must be an https URL of a .tar.gz archive")
+	}
+	allowed := false
+	for _, host := range strings.Split(os.Getenv("GIT_DRIFT_ALLOWED_HOSTS"), ",") {
+		if strings.EqualFold(strings.TrimSpace(host), u.Hostname()) {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("host %s is not in GIT_DRIFT_ALLOWED_HOSTS", u.Hostname())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, archiveTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArchiveBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	if len(data) > maxArchiveBytes {
+		return nil, fmt.Errorf("archive is larger than %d bytes", maxArchiveBytes)
+	}
+	return data, nil
+}
+
+// sortedFileNames returns the names of a set of files in order.
+func sortedFileNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseAndValidateGitDriftParams(args map[string]any) (*GitDriftInput, error) {
+	input := &GitDriftInput{Namespace: "default", SourceNamespace: fluxNamespace}
+	input.Path, _ = args["path"].(string)
+	input.GitRepository, _ = args["gitRepository"].(string)
+	input.URL, _ = args["url"].(string)
+	if input.GitRepository != "" && input.URL != "" {
+		return nil, errors.New("set only one of gitRepository and url")
+	}
+	if input.GitRepository != "" {
+		if err := validation.ValidateResourceName(input.GitRepository); err != nil {
+			return nil, fmt.Errorf("invalid gitRepository: %w", err)
+		}
+	}
+	if ns, ok := args["sourceNamespace"].(string); ok && ns != "" {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid sourceNamespace: %w", err)
+		}
+		input.SourceNamespace = ns
+	}
+	if ns, ok := args["namespace"].(string); ok && ns != "" {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = ns
+	}
+	return input, nil
+}
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGitDriftHandler(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("GIT_DRIFT_ROOT", root)
+	apps := filepath.Join(root, "clusters", "prod")
+	assert.NoError(t, os.MkdirAll(filepath.Join(apps, ".github"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(apps, "web.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.27
+        resources:
+          requests: {cpu: 1000m}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: shop
+spec:
+  replicas: 1
+`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(apps, "kustomization.yaml"), []byte("apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources: [web.yaml]\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(apps, ".github", "ci.yaml"), []byte("on: push\n"), 0o600))
+
+	live := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "web", "namespace": "shop", "uid": "1"},
+		"spec": map[string]any{
+			"replicas":             int64(5),
+			"revisionHistoryLimit": int64(10),
+			"template": map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "web", "image": "nginx:1.27", "resources": map[string]any{"requests": map[string]any{"cpu": "1"}}},
+			}}},
+		},
+	}}
+	client := &fakeDiffApplyClient{live: map[string]*unstructured.Unstructured{"web": live}}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"path": "clusters/prod"}
+	result, err := NewGitDriftTool(client).Handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	var out struct {
+		Compared int               `json:"compared"`
+		InSync   int               `json:"inSync"`
+		Drifted  []GitDriftObject  `json:"drifted"`
+		Missing  []GitDriftObject  `json:"missing"`
+		Skipped  map[string]string `json:"skipped"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	assert.Equal(t, 2, out.Compared)
+	assert.Equal(t, 0, out.InSync)
+	assert.Equal(t, []GitDriftObject{{
+		File: "clusters/prod/web.yaml", Kind: "Deployment", Name: "web", Namespace: "shop",
+		Differences: []GitDriftField{{Field: "spec.replicas", Repo: "2", Live: "5"}},
+	}}, out.Drifted)
+	assert.Len(t, out.Missing, 1)
+	assert.Equal(t, "api", out.Missing[0].Name)
+	assert.Equal(t, map[string]string{"clusters/prod/kustomization.yaml": "kustomization: needs rendering"}, out.Skipped)
+
+	req.Params.Arguments = map[string]any{"path": "../etc"}
+	_, err = NewGitDriftTool(client).Handler(context.Background(), req)
+	assert.ErrorContains(t, err, "must be a relative directory")
+}
+
+func TestManifestFilesFromArchive(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"fleet-main/apps/web.yaml":       "kind: Deployment",
+		"fleet-main/apps/README.md":      "docs",
+		"fleet-main/infra/ingress.yml":   "kind: Ingress",
+		"fleet-main/.github/ci.yaml":     "on: push",
+		"fleet-main/apps/nested/db.json": "{}",
+	} {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+
+	files, err := manifestFilesFromArchive(buf.Bytes(), "apps", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apps/nested/db.json", "apps/web.yaml"}, sortedFileNames(files))
+
+	files, err = manifestFilesFromArchive(buf.Bytes(), "", true)
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+}
+
+func TestGitDriftFields(t *testing.T) {
+	desired := map[string]any{
+		"metadata": map[string]any{"name": "creds", "namespace": "shop"},
+		"data":     map[string]any{"password": "c2VjcmV0"},
+		"status":   map[string]any{"phase": "x"},
+	}
+	live := map[string]any{
+		"metadata": map[string]any{"name": "creds", "namespace": "other"},
+		"data":     map[string]any{"password": "bmV3"},
+	}
+	assert.Equal(t, []GitDriftField{{Field: "data.password", Repo: "(redacted)", Live: "(redacted)"}}, gitDriftFields(desired, live, true))
+	assert.True(t, equalQuantities(`"500m"`, `"0.5"`))
+	assert.False(t, equalQuantities(`"nginx"`, `"nginx:1"`))
+}
+
+func TestServiceProxyTarget(t *testing.T) {
+	service, namespace, port, path, err := serviceProxyTarget("http://source-controller.flux-system.svc.cluster.local./gitrepository/flux-system/fleet/abc.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"source-controller", "flux-system", "", "/gitrepository/flux-system/fleet/abc.tar.gz"}, []string{service, namespace, port, path})
+
+	_, _, _, _, err = serviceProxyTarget("http://localhost/artifact.tar.gz")
+	assert.Error(t, err)
+}
+
+func TestGitDriftSourceNamespaceScope(t *testing.T) {
+	ctx := auth.WithClient(context.Background(), &auth.Client{Name: "shop", Namespaces: []string{"shop"}})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"namespace": "shop", "gitRepository": "fleet", "sourceNamespace": "kube-system"}
+
+	_, err := NewGitDriftTool(nil).Handler(ctx, req)
+
+	assert.EqualError(t, err, "invalid sourceNamespace: client shop is not allowed to access namespace kube-system")
+}
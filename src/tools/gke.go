@@ -0,0 +1,351 @@
+// Environment variables used by this tool:
+// Optional:
+//   GOOGLE_APPLICATION_CREDENTIALS - Path to a GCP service account JSON file; without it gcloud ADC or Workload Identity is used
+//   GOOGLE_CLOUD_PROJECT           - GCP Project ID (used if not provided in input)
+//   GKE_CLUSTER_LOCATION           - Cluster region or zone (used if not provided in input)
+//   GKE_CLUSTER_NAME               - Cluster name (used if not provided in input)
+// Without input or environment the cluster is taken from a gke_<project>_<location>_<cluster> kubeconfig context.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	container "cloud.google.com/go/container/apiv1"
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// maxMaintenanceOccurrences caps how many upcoming maintenance windows are listed.
+const maxMaintenanceOccurrences = 3
+
+// GKEClusterInfoInput represents the input for the gke_cluster_info tool.
+type GKEClusterInfoInput struct {
+	ProjectID string `json:"projectId,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Cluster   string `json:"cluster,omitempty"`
+}
+
+// GKENodePool summarizes a node pool and its autoscaling settings.
+type GKENodePool struct {
+	Name             string             `json:"name"`
+	Status           string             `json:"status"`
+	Version          string             `json:"version,omitempty"`
+	MachineType      string             `json:"machineType,omitempty"`
+	DiskSizeGb       int32              `json:"diskSizeGb,omitempty"`
+	Spot             bool               `json:"spot,omitempty"`
+	Preemptible      bool               `json:"preemptible,omitempty"`
+	Locations        []string           `json:"locations,omitempty"`
+	InitialNodeCount int32              `json:"initialNodeCount,omitempty"`
+	Autoscaling      *GKEPoolAutoscaler `json:"autoscaling,omitempty"`
+	AutoUpgrade      bool               `json:"autoUpgrade"`
+	AutoRepair       bool               `json:"autoRepair"`
+	UpcomingUpgrade  string             `json:"upcomingUpgrade,omitempty"`
+}
+
+// GKEPoolAutoscaler is the autoscaling configuration of a node pool.
+type GKEPoolAutoscaler struct {
+	MinNodeCount      int32  `json:"minNodeCount,omitempty"`
+	MaxNodeCount      int32  `json:"maxNodeCount,omitempty"`
+	TotalMinNodeCount int32  `json:"totalMinNodeCount,omitempty"`
+	TotalMaxNodeCount int32  `json:"totalMaxNodeCount,omitempty"`
+	LocationPolicy    string `json:"locationPolicy,omitempty"`
+	Autoprovisioned   bool   `json:"autoprovisioned,omitempty"`
+}
+
+// GKEMaintenance describes the maintenance policy and the next windows it allows.
+type GKEMaintenance struct {
+	Policy     string                 `json:"policy"`
+	Recurrence string                 `json:"recurrence,omitempty"`
+	Upcoming   []GKEMaintenanceWindow `json:"upcoming,omitempty"`
+	Exclusions []GKEMaintenanceWindow `json:"exclusions,omitempty"`
+}
+
+// GKEMaintenanceWindow is a single maintenance window or exclusion.
+type GKEMaintenanceWindow struct {
+	Name     string `json:"name,omitempty"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Excluded bool   `json:"excluded,omitempty"`
+}
+
+type GKEClusterInfoTool struct{}
+
+func NewGKEClusterInfoTool() *GKEClusterInfoTool {
+	return &GKEClusterInfoTool{}
+}
+
+func (t *GKEClusterInfoTool) Tool() mcp.Tool {
+	return mcp.NewTool("gke_cluster_info",
+		mcp.WithDescription("Fetch GKE metadata for the connected cluster from the GCP Container API: node pools, autoscaling settings, release channel and upcoming maintenance windows."),
+		mcp.WithString("projectId", mcp.Description("GCP Project ID (optional, defaults to GOOGLE_CLOUD_PROJECT or the gke_ kubeconfig context)")),
+		mcp.WithString("location", mcp.Description("Cluster region or zone (optional, defaults to GKE_CLUSTER_LOCATION or the gke_ kubeconfig context)")),
+		mcp.WithString("cluster", mcp.Description("Cluster name (optional, defaults to GKE_CLUSTER_NAME or the gke_ kubeconfig context)")),
+	)
+}
+
+func (t *GKEClusterInfoTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateGKEClusterInfoParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	creds, credsSource, err := gcpCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := container.NewClusterManagerClient(ctx, creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container client: %w", err)
+	}
+	defer client.Close()
+
+	name := fmt.Sprintf("projects/%s/locations/%s/clusters/%s", input.ProjectID, input.Location, input.Cluster)
+	cluster, err := client.GetCluster(ctx, &containerpb.GetClusterRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+
+	output := gkeClusterSummary(cluster, time.Now())
+	output["projectId"] = input.ProjectID
+	output["credentialsSource"] = credsSource
+	out, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// gkeClusterSummary condenses the parts of a cluster that matter for capacity and upgrade planning.
+func gkeClusterSummary(cluster *containerpb.Cluster, now time.Time) map[string]any {
+	pools := make([]GKENodePool, 0, len(cluster.GetNodePools()))
+	for _, np := range cluster.GetNodePools() {
+		pools = append(pools, gkeNodePoolSummary(np))
+	}
+
+	summary := map[string]any{
+		"name":           cluster.GetName(),
+		"location":       cluster.GetLocation(),
+		"status":         cluster.GetStatus().String(),
+		"masterVersion":  cluster.GetCurrentMasterVersion(),
+		"nodeCount":      cluster.GetCurrentNodeCount(),
+		"releaseChannel": cluster.GetReleaseChannel().GetChannel().String(),
+		"autopilot":      cluster.GetAutopilot().GetEnabled(),
+		"nodePools":      pools,
+		"maintenance":    gkeMaintenanceSummary(cluster.GetMaintenancePolicy(), now),
+	}
+	if autoscaling := cluster.GetAutoscaling(); autoscaling != nil {
+		limits := map[string]string{}
+		for _, l := range autoscaling.GetResourceLimits() {
+			limits[l.GetResourceType()] = fmt.Sprintf("%d-%d", l.GetMinimum(), l.GetMaximum())
+		}
+		summary["clusterAutoscaling"] = map[string]any{
+			"nodeAutoprovisioning": autoscaling.GetEnableNodeAutoprovisioning(),
+			"profile":              autoscaling.GetAutoscalingProfile().String(),
+			"resourceLimits":       limits,
+		}
+	}
+	return summary
+}
+
+func gkeNodePoolSummary(np *containerpb.NodePool) GKENodePool {
+	pool := GKENodePool{
+		Name:             np.GetName(),
+		Status:           np.GetStatus().String(),
+		Version:          np.GetVersion(),
+		MachineType:      np.GetConfig().GetMachineType(),
+		DiskSizeGb:       np.GetConfig().GetDiskSizeGb(),
+		Spot:             np.GetConfig().GetSpot(),
+		Preemptible:      np.GetConfig().GetPreemptible(),
+		Locations:        np.GetLocations(),
+		InitialNodeCount: np.GetInitialNodeCount(),
+		AutoUpgrade:      np.GetManagement().GetAutoUpgrade(),
+		AutoRepair:       np.GetManagement().GetAutoRepair(),
+	}
+	if opts := np.GetManagement().GetUpgradeOptions(); opts.GetAutoUpgradeStartTime() != "" {
+		pool.UpcomingUpgrade = strings.TrimSpace(opts.GetAutoUpgradeStartTime() + " " + opts.GetDescription())
+	}
+	if a := np.GetAutoscaling(); a.GetEnabled() {
+		pool.Autoscaling = &GKEPoolAutoscaler{
+			MinNodeCount:      a.GetMinNodeCount(),
+			MaxNodeCount:      a.GetMaxNodeCount(),
+			TotalMinNodeCount: a.GetTotalMinNodeCount(),
+			TotalMaxNodeCount: a.GetTotalMaxNodeCount(),
+			LocationPolicy:    a.GetLocationPolicy().String(),
+			Autoprovisioned:   a.GetAutoprovisioned(),
+		}
+	}
+	return pool
+}
+
+// gkeMaintenanceSummary lists the next maintenance windows of a daily or recurring policy, marking
+// those covered by an exclusion, along with the exclusions that have not ended yet.
+func gkeMaintenanceSummary(policy *containerpb.MaintenancePolicy, now time.Time) GKEMaintenance {
+	window := policy.GetWindow()
+	summary := GKEMaintenance{Policy: "none"}
+
+	for name, ex := range window.GetMaintenanceExclusions() {
+		end := ex.GetEndTime().AsTime()
+		if end.Before(now) {
+			continue
+		}
+		summary.Exclusions = append(summary.Exclusions, GKEMaintenanceWindow{
+			Name:  name,
+			Start: ex.GetStartTime().AsTime().Format(time.RFC3339),
+			End:   end.Format(time.RFC3339),
+		})
+	}
+	sort.Slice(summary.Exclusions, func(i, j int) bool { return summary.Exclusions[i].Start < summary.Exclusions[j].Start })
+
+	var start time.Time
+	var duration time.Duration
+	var days map[time.Weekday]bool
+	switch {
+	case window.GetDailyMaintenanceWindow() != nil:
+		daily := window.GetDailyMaintenanceWindow()
+		summary.Policy = "daily"
+		t, err := time.Parse("15:04", daily.GetStartTime())
+		if err != nil {
+			return summary
+		}
+		start = t
+		// The API reports the duration in RFC3339 form, e.g. "PT4H0M0S".
+		if duration, err = time.ParseDuration(strings.ToLower(strings.TrimPrefix(daily.GetDuration(), "PT"))); err != nil {
+			duration = 4 * time.Hour
+		}
+	case window.GetRecurringWindow() != nil:
+		recurring := window.GetRecurringWindow()
+		summary.Policy = "recurring"
+		summary.Recurrence = recurring.GetRecurrence()
+		start = recurring.GetWindow().GetStartTime().AsTime()
+		duration = recurring.GetWindow().GetEndTime().AsTime().Sub(start)
+		var ok bool
+		if days, ok = parseRecurrenceDays(recurring.GetRecurrence()); !ok {
+			return summary
+		}
+	default:
+		return summary
+	}
+
+	// Walk forward day by day from today until enough windows have been found.
+	day := time.Date(now.Year(), now.Month(), now.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+	for i := 0; i < 366 && len(summary.Upcoming) < maxMaintenanceOccurrences; i, day = i+1, day.AddDate(0, 0, 1) {
+		end := day.Add(duration)
+		if !end.After(now) || (days != nil && !days[day.Weekday()]) || (summary.Policy == "recurring" && day.Before(start)) {
+			continue
+		}
+		occurrence := GKEMaintenanceWindow{Start: day.Format(time.RFC3339), End: end.Format(time.RFC3339)}
+		for _, ex := range window.GetMaintenanceExclusions() {
+			if day.Before(ex.GetEndTime().AsTime()) && end.After(ex.GetStartTime().AsTime()) {
+				occurrence.Excluded = true
+				break
+			}
+		}
+		summary.Upcoming = append(summary.Upcoming, occurrence)
+	}
+	return summary
+}
+
+// parseRecurrenceDays reads the weekdays of a FREQ=DAILY or FREQ=WEEKLY;BYDAY=... RRULE. A nil map
+// means every day; other recurrences are reported as unsupported.
+func parseRecurrenceDays(rrule string) (map[time.Weekday]bool, bool) {
+	weekdays := map[string]time.Weekday{
+		"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+		"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+	}
+	var freq, byDay string
+	for _, part := range strings.Split(strings.TrimPrefix(rrule, "RRULE:"), ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freq = strings.ToUpper(value)
+		case "BYDAY":
+			byDay = strings.ToUpper(value)
+		case "INTERVAL":
+			if value != "1" {
+				return nil, false
+			}
+		case "COUNT", "UNTIL", "BYMONTHDAY", "BYSETPOS":
+			return nil, false
+		}
+	}
+	switch {
+	case freq == "DAILY" && byDay == "":
+		return nil, true
+	case freq == "WEEKLY" || (freq == "DAILY" && byDay != ""):
+		if byDay == "" {
+			return nil, false
+		}
+		days := map[time.Weekday]bool{}
+		for _, d := range strings.Split(byDay, ",") {
+			wd, ok := weekdays[d]
+			if !ok {
+				return nil, false
+			}
+			days[wd] = true
+		}
+		return days, true
+	}
+	return nil, false
+}
+
+// parseGKEContext splits a kubeconfig context named by gcloud, gke_<project>_<location>_<cluster>.
+func parseGKEContext(name string) (project, location, cluster string, ok bool) {
+	parts := strings.SplitN(name, "_", 4)
+	if len(parts) != 4 || parts[0] != "gke" || parts[1] == "" || parts[2] == "" || parts[3] == "" {
+		return "", "", "", false
+	}
+	return parts[1], parts[2], parts[3], true
+}
+
+// currentGKEContext parses the current kubeconfig context when it was created by gcloud.
+func currentGKEContext() (project, location, cluster string, ok bool) {
+	cfg, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return "", "", "", false
+	}
+	return parseGKEContext(cfg.CurrentContext)
+}
+
+func parseAndValidateGKEClusterInfoParams(args map[string]any) (*GKEClusterInfoInput, error) {
+	input := &GKEClusterInfoInput{
+		ProjectID: os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		Location:  os.Getenv("GKE_CLUSTER_LOCATION"),
+		Cluster:   os.Getenv("GKE_CLUSTER_NAME"),
+	}
+	if v, ok := args["projectId"].(string); ok && v != "" {
+		input.ProjectID = v
+	}
+	if v, ok := args["location"].(string); ok && v != "" {
+		input.Location = v
+	}
+	if v, ok := args["cluster"].(string); ok && v != "" {
+		input.Cluster = v
+	}
+
+	if input.ProjectID == "" || input.Location == "" || input.Cluster == "" {
+		if project, location, cluster, ok := currentGKEContext(); ok {
+			if input.ProjectID == "" {
+				input.ProjectID = project
+			}
+			if input.Location == "" {
+				input.Location = location
+			}
+			if input.Cluster == "" {
+				input.Cluster = cluster
+			}
+		}
+	}
+
+	if input.ProjectID == "" || input.Location == "" || input.Cluster == "" {
+		return nil, fmt.Errorf("projectId, location and cluster must be provided (as input, environment variables or a gke_ kubeconfig context)")
+	}
+	return input, nil
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestParseGKEContext(t *testing.T) {
+	project, location, cluster, ok := parseGKEContext("gke_my-project_europe-west1_prod_blue")
+	assert.True(t, ok)
+	assert.Equal(t, "my-project", project)
+	assert.Equal(t, "europe-west1", location)
+	assert.Equal(t, "prod_blue", cluster)
+
+	_, _, _, ok = parseGKEContext("kind-dev")
+	assert.False(t, ok)
+}
+
+func TestParseRecurrenceDays(t *testing.T) {
+	days, ok := parseRecurrenceDays("FREQ=WEEKLY;BYDAY=SA,SU")
+	assert.True(t, ok)
+	assert.Equal(t, map[time.Weekday]bool{time.Saturday: true, time.Sunday: true}, days)
+
+	days, ok = parseRecurrenceDays("FREQ=DAILY")
+	assert.True(t, ok)
+	assert.Nil(t, days)
+
+	_, ok = parseRecurrenceDays("FREQ=MONTHLY;BYSETPOS=1;BYDAY=MO")
+	assert.False(t, ok)
+}
+
+func TestGKEMaintenanceSummary(t *testing.T) {
+	// Wednesday, 2025-01-01.
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := &containerpb.MaintenancePolicy{Window: &containerpb.MaintenanceWindow{
+		Policy: &containerpb.MaintenanceWindow_RecurringWindow{RecurringWindow: &containerpb.RecurringTimeWindow{
+			Window: &containerpb.TimeWindow{
+				StartTime: timestamppb.New(time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)),
+				EndTime:   timestamppb.New(time.Date(2024, 6, 1, 6, 0, 0, 0, time.UTC)),
+			},
+			Recurrence: "FREQ=WEEKLY;BYDAY=SA,SU",
+		}},
+		MaintenanceExclusions: map[string]*containerpb.TimeWindow{
+			"holidays": {
+				StartTime: timestamppb.New(time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)),
+				EndTime:   timestamppb.New(time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)),
+			},
+			"expired": {
+				StartTime: timestamppb.New(time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)),
+				EndTime:   timestamppb.New(time.Date(2024, 12, 2, 0, 0, 0, 0, time.UTC)),
+			},
+		},
+	}}
+
+	summary := gkeMaintenanceSummary(policy, now)
+	assert.Equal(t, "recurring", summary.Policy)
+	assert.Equal(t, []GKEMaintenanceWindow{
+		{Start: "2025-01-04T02:00:00Z", End: "2025-01-04T06:00:00Z", Excluded: true},
+		{Start: "2025-01-05T02:00:00Z", End: "2025-01-05T06:00:00Z"},
+		{Start: "2025-01-11T02:00:00Z", End: "2025-01-11T06:00:00Z"},
+	}, summary.Upcoming)
+	assert.Equal(t, []GKEMaintenanceWindow{{Name: "holidays", Start: "2025-01-04T00:00:00Z", End: "2025-01-05T00:00:00Z"}}, summary.Exclusions)
+
+	daily := gkeMaintenanceSummary(&containerpb.MaintenancePolicy{Window: &containerpb.MaintenanceWindow{
+		Policy: &containerpb.MaintenanceWindow_DailyMaintenanceWindow{DailyMaintenanceWindow: &containerpb.DailyMaintenanceWindow{StartTime: "03:30", Duration: "PT4H0M0S"}},
+	}}, now)
+	assert.Equal(t, "daily", daily.Policy)
+	assert.Equal(t, GKEMaintenanceWindow{Start: "2025-01-02T03:30:00Z", End: "2025-01-02T07:30:00Z"}, daily.Upcoming[0])
+
+	assert.Equal(t, "none", gkeMaintenanceSummary(nil, now).Policy)
+}
+
+func TestGKENodePoolSummary(t *testing.T) {
+	pool := gkeNodePoolSummary(&containerpb.NodePool{
+		Name:        "default-pool",
+		Status:      containerpb.NodePool_RUNNING,
+		Version:     "1.30.5-gke.1014001",
+		Config:      &containerpb.NodeConfig{MachineType: "e2-standard-4", Spot: true},
+		Autoscaling: &containerpb.NodePoolAutoscaling{Enabled: true, MinNodeCount: 1, MaxNodeCount: 5},
+		Management: &containerpb.NodeManagement{AutoUpgrade: true, UpgradeOptions: &containerpb.AutoUpgradeOptions{
+			AutoUpgradeStartTime: "2025-01-04T02:00:00Z", Description: "upgrade to 1.30.6",
+		}},
+	})
+	assert.Equal(t, "RUNNING", pool.Status)
+	assert.Equal(t, "e2-standard-4", pool.MachineType)
+	assert.True(t, pool.Spot)
+	assert.Equal(t, int32(5), pool.Autoscaling.MaxNodeCount)
+	assert.Equal(t, "LOCATION_POLICY_UNSPECIFIED", pool.Autoscaling.LocationPolicy)
+	assert.Equal(t, "2025-01-04T02:00:00Z upgrade to 1.30.6", pool.UpcomingUpgrade)
+}
@@ -0,0 +1,355 @@
+package tools
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// pendingPodGracePeriod is how long a pod may sit in Pending before it is reported as failing.
+const pendingPodGracePeriod = 5 * time.Minute
+
+// failingWaitingReasons are container waiting reasons that indicate the pod will not recover on its own.
+var failingWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// NamespaceHealthInput represents the input for the namespace health summary.
+type NamespaceHealthInput struct {
+	Namespace       string `json:"namespace"`
+	CertWarningDays int    `json:"certWarningDays,omitempty"`
+	MaxEvents       int    `json:"maxEvents,omitempty"`
+}
+
+// HealthIssue is a single unhealthy object found during a health check.
+type HealthIssue struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+}
+
+// NamespaceHealthTool summarizes the health of the workloads in a namespace.
+type NamespaceHealthTool struct {
+	client Client
+}
+
+// NewNamespaceHealthTool creates a new NamespaceHealthTool with the provided Kubernetes client.
+func NewNamespaceHealthTool(client Client) *NamespaceHealthTool {
+	return &NamespaceHealthTool{client: client}
+}
+
+// Tool returns the MCP tool definition for namespace_health.
+func (n *NamespaceHealthTool) Tool() mcp.Tool {
+	return mcp.NewTool("namespace_health",
+		mcp.WithDescription("One-call health summary of a namespace: failing pods, stalled Deployment/StatefulSet/DaemonSet rollouts, HPAs pinned at max replicas, pending PVCs, TLS certificates close to expiry and recent Warning events"),
+		mcp.WithString("namespace",
+			mcp.Required(),
+			mcp.Description("Kubernetes namespace to check"),
+		),
+		mcp.WithNumber("certWarningDays",
+			mcp.Description("Report TLS certificates expiring within this many days (default: 14)"),
+		),
+		mcp.WithNumber("maxEvents",
+			mcp.Description("Maximum number of recent Warning events to include (default: 20)"),
+		),
+	)
+}
+
+// Handler gathers the health signals for the namespace.
+func (n *NamespaceHealthTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateNamespaceHealthParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate namespace_health params: %w", err)
+	}
+
+	clientset, err := n.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	now := time.Now()
+	result := map[string]any{"namespace": input.Namespace}
+	var checkErrors []string
+	issues := 0
+
+	pods, err := clientset.CoreV1().Pods(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	failingPods := []HealthIssue{}
+	for i := range pods.Items {
+		if reason, message := podProblem(&pods.Items[i], now); reason != "" {
+			failingPods = append(failingPods, HealthIssue{Kind: "Pod", Name: pods.Items[i].Name, Reason: reason, Message: message})
+		}
+	}
+	result["pods"] = map[string]any{"total": len(pods.Items), "failing": failingPods}
+	issues += len(failingPods)
+
+	rollouts, err := stalledRollouts(ctx, clientset, input.Namespace)
+	if err != nil {
+		checkErrors = append(checkErrors, err.Error())
+	}
+	result["stalledRollouts"] = rollouts
+	issues += len(rollouts)
+
+	hpas, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(input.Namespace).List(ctx, metav1.ListOptions{})
+	atMax := []HealthIssue{}
+	if err != nil {
+		checkErrors = append(checkErrors, fmt.Sprintf("failed to list HPAs: %v", err))
+	} else {
+		for _, hpa := range hpas.Items {
+			if hpa.Status.CurrentReplicas >= hpa.Spec.MaxReplicas {
+				atMax = append(atMax, HealthIssue{
+					Kind:    "HorizontalPodAutoscaler",
+					Name:    hpa.Name,
+					Reason:  "AtMaxReplicas",
+					Message: fmt.Sprintf("%d/%d replicas targeting %s/%s", hpa.Status.CurrentReplicas, hpa.Spec.MaxReplicas, hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name),
+				})
+			}
+		}
+	}
+	result["hpasAtMax"] = atMax
+	issues += len(atMax)
+
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(input.Namespace).List(ctx, metav1.ListOptions{})
+	pendingPVCs := []HealthIssue{}
+	if err != nil {
+		checkErrors = append(checkErrors, fmt.Sprintf("failed to list PVCs: %v", err))
+	} else {
+		for _, pvc := range pvcs.Items {
+			if pvc.Status.Phase != corev1.ClaimBound {
+				pendingPVCs = append(pendingPVCs, HealthIssue{Kind: "PersistentVolumeClaim", Name: pvc.Name, Reason: string(pvc.Status.Phase)})
+			}
+		}
+	}
+	result["pendingPVCs"] = pendingPVCs
+	issues += len(pendingPVCs)
+
+	certs, err := expiringTLSSecrets(ctx, clientset, input.Namespace, now.AddDate(0, 0, input.CertWarningDays))
+	if err != nil {
+		checkErrors = append(checkErrors, err.Error())
+	}
+	result["expiringCertificates"] = certs
+	issues += len(certs)
+
+	events, err := listEvents(ctx, clientset, input.Namespace, "", "", true)
+	if err != nil {
+		checkErrors = append(checkErrors, err.Error())
+	}
+	if len(events) > input.MaxEvents {
+		events = events[:input.MaxEvents]
+	}
+	result["warningEvents"] = events
+
+	result["healthy"] = issues == 0
+	result["issues"] = issues
+	if len(checkErrors) > 0 {
+		result["errors"] = checkErrors
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// podProblem returns a reason and message when the pod is failing, or an empty reason when it is healthy.
+// Completed pods are healthy; pods stuck in Pending are only reported after pendingPodGracePeriod.
+func podProblem(pod *corev1.Pod, now time.Time) (string, string) {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return "", ""
+	case corev1.PodFailed:
+		reason := pod.Status.Reason
+		if reason == "" {
+			reason = "Failed"
+		}
+		return reason, pod.Status.Message
+	}
+
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.State.Waiting != nil && failingWaitingReasons[cs.State.Waiting.Reason] {
+			return cs.State.Waiting.Reason, fmt.Sprintf("container %s: %s", cs.Name, cs.State.Waiting.Message)
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodPending {
+		if now.Sub(pod.CreationTimestamp.Time) > pendingPodGracePeriod {
+			return "Pending", fmt.Sprintf("pending for %s", now.Sub(pod.CreationTimestamp.Time).Round(time.Second))
+		}
+		return "", ""
+	}
+
+	if pod.DeletionTimestamp == nil {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue && now.Sub(cond.LastTransitionTime.Time) > pendingPodGracePeriod {
+				return "NotReady", cond.Message
+			}
+		}
+	}
+	return "", ""
+}
+
+// stalledRollouts reports Deployments, StatefulSets and DaemonSets whose rollout is not complete.
+func stalledRollouts(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]HealthIssue, error) {
+	issues := []HealthIssue{}
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return issues, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		if issue := deploymentRolloutIssue(&deployments.Items[i]); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return issues, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, sts := range statefulSets.Items {
+		replicas := int32(1)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		if sts.Status.ReadyReplicas < replicas || sts.Status.UpdatedReplicas < replicas {
+			issues = append(issues, HealthIssue{
+				Kind:    "StatefulSet",
+				Name:    sts.Name,
+				Reason:  "RolloutIncomplete",
+				Message: fmt.Sprintf("%d/%d ready, %d updated", sts.Status.ReadyReplicas, replicas, sts.Status.UpdatedReplicas),
+			})
+		}
+	}
+
+	daemonSets, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return issues, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for _, ds := range daemonSets.Items {
+		if ds.Status.NumberUnavailable > 0 || ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
+			issues = append(issues, HealthIssue{
+				Kind:    "DaemonSet",
+				Name:    ds.Name,
+				Reason:  "RolloutIncomplete",
+				Message: fmt.Sprintf("%d unavailable, %d/%d updated", ds.Status.NumberUnavailable, ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled),
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+// deploymentRolloutIssue reports a Deployment that exceeded its progress deadline or lacks available replicas.
+func deploymentRolloutIssue(d *appsv1.Deployment) *HealthIssue {
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			return &HealthIssue{Kind: "Deployment", Name: d.Name, Reason: cond.Reason, Message: cond.Message}
+		}
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	if d.Spec.Paused || replicas == 0 {
+		return nil
+	}
+	if d.Status.AvailableReplicas < replicas || d.Status.UpdatedReplicas < replicas {
+		return &HealthIssue{
+			Kind:    "Deployment",
+			Name:    d.Name,
+			Reason:  "RolloutIncomplete",
+			Message: fmt.Sprintf("%d/%d available, %d updated", d.Status.AvailableReplicas, replicas, d.Status.UpdatedReplicas),
+		}
+	}
+	return nil
+}
+
+// expiringTLSSecrets reports kubernetes.io/tls Secrets whose certificate expires before the deadline.
+func expiringTLSSecrets(ctx context.Context, clientset kubernetes.Interface, namespace string, deadline time.Time) ([]HealthIssue, error) {
+	issues := []HealthIssue{}
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + string(corev1.SecretTypeTLS),
+	})
+	if err != nil {
+		return issues, fmt.Errorf("failed to list TLS secrets: %w", err)
+	}
+
+	for _, secret := range secrets.Items {
+		cert, err := parseCertificatePEM(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			issues = append(issues, HealthIssue{Kind: "Secret", Name: secret.Name, Reason: "InvalidCertificate", Message: err.Error()})
+			continue
+		}
+		if cert.NotAfter.Before(deadline) {
+			issues = append(issues, HealthIssue{
+				Kind:    "Secret",
+				Name:    secret.Name,
+				Reason:  "CertificateExpiring",
+				Message: fmt.Sprintf("%s expires %s", cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339)),
+			})
+		}
+	}
+	return issues, nil
+}
+
+// parseCertificatePEM returns the first certificate in a PEM bundle.
+func parseCertificatePEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// parseAndValidateNamespaceHealthParams validates and parses the input parameters.
+func parseAndValidateNamespaceHealthParams(args map[string]any) (*NamespaceHealthInput, error) {
+	input := &NamespaceHealthInput{CertWarningDays: 14, MaxEvents: 20}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+	}
+	if input.Namespace == "" {
+		return nil, errors.New("namespace must be provided")
+	}
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+
+	if days, ok := args["certWarningDays"].(float64); ok && days > 0 {
+		input.CertWarningDays = int(days)
+	}
+	if maxEvents, ok := args["maxEvents"].(float64); ok && maxEvents >= 0 {
+		input.MaxEvents = int(maxEvents)
+	}
+
+	return input, nil
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodProblem(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	created := func(ago time.Duration) metav1.ObjectMeta {
+		return metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-ago))}
+	}
+
+	testCases := []struct {
+		name     string
+		pod      corev1.Pod
+		expected string
+	}{
+		{name: "Succeeded", pod: corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodSucceeded}}, expected: ""},
+		{name: "Evicted", pod: corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}}, expected: "Evicted"},
+		{
+			name: "CrashLoop",
+			pod: corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			}}},
+			expected: "CrashLoopBackOff",
+		},
+		{name: "RecentlyPending", pod: corev1.Pod{ObjectMeta: created(time.Minute), Status: corev1.PodStatus{Phase: corev1.PodPending}}, expected: ""},
+		{name: "StuckPending", pod: corev1.Pod{ObjectMeta: created(time.Hour), Status: corev1.PodStatus{Phase: corev1.PodPending}}, expected: "Pending"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, _ := podProblem(&tc.pod, now)
+			assert.Equal(t, tc.expected, reason)
+		})
+	}
+}
+
+func TestDeploymentRolloutIssue(t *testing.T) {
+	replicas := int32(3)
+	healthy := &appsv1.Deployment{
+		Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{AvailableReplicas: 3, UpdatedReplicas: 3},
+	}
+	assert.Nil(t, deploymentRolloutIssue(healthy))
+
+	stalled := healthy.DeepCopy()
+	stalled.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded", Message: "timed out"},
+	}
+	if issue := deploymentRolloutIssue(stalled); assert.NotNil(t, issue) {
+		assert.Equal(t, "ProgressDeadlineExceeded", issue.Reason)
+	}
+
+	degraded := healthy.DeepCopy()
+	degraded.Status.AvailableReplicas = 1
+	if issue := deploymentRolloutIssue(degraded); assert.NotNil(t, issue) {
+		assert.Equal(t, "1/3 available, 3 updated", issue.Message)
+	}
+}
+
+func TestParseCertificatePEM(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	notAfter := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	cert, err := parseCertificatePEM(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", cert.Subject.CommonName)
+	assert.Equal(t, notAfter, cert.NotAfter)
+
+	_, err = parseCertificatePEM([]byte("not a certificate"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,588 @@
+package tools
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	helmReleaseSecretType = "helm.sh/release.v1"
+	helmFieldManager      = "helm"
+	// helmResourcePolicy is the annotation that, set to keep, stops Helm from deleting a resource.
+	helmResourcePolicy = "helm.sh/resource-policy"
+)
+
+// helmRelease is the part of a Helm release record the tools read. The whole record is kept in raw,
+// so a rollback stores the target revision unchanged apart from its version and info.
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		Status       string `json:"status"`
+		Description  string `json:"description,omitempty"`
+		LastDeployed string `json:"last_deployed,omitempty"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion,omitempty"`
+		} `json:"metadata"`
+	} `json:"chart"`
+	Manifest string `json:"manifest"`
+
+	raw    map[string]any
+	secret *corev1.Secret
+}
+
+// HelmRevision summarizes one revision of a release.
+type HelmRevision struct {
+	Revision    int    `json:"revision"`
+	Status      string `json:"status"`
+	Chart       string `json:"chart"`
+	AppVersion  string `json:"appVersion,omitempty"`
+	Updated     string `json:"updated,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// HelmRollbackInput represents the input for helm_rollback.
+type HelmRollbackInput struct {
+	Namespace string `json:"namespace"`
+	Release   string `json:"release"`
+	Revision  int    `json:"revision,omitempty"`
+	DryRun    bool   `json:"dryRun,omitempty"`
+}
+
+// HelmRollbackTool rolls a Helm release back to a previous revision from its release secrets.
+type HelmRollbackTool struct {
+	client Client
+}
+
+// NewHelmRollbackTool creates a new HelmRollbackTool with the provided Kubernetes client.
+func NewHelmRollbackTool(client Client) *HelmRollbackTool {
+	return &HelmRollbackTool{client: client}
+}
+
+// Tool returns the MCP tool definition for helm_rollback.
+func (h *HelmRollbackTool) Tool() mcp.Tool {
+	return mcp.NewTool("helm_rollback",
+		mcp.WithDescription("Roll a Helm release back to a previous revision, like 'helm rollback' without hooks: reads the release history from its Helm release secrets, server-side applies the manifest stored with the target revision as the helm field manager, deletes resources the current revision added (unless annotated helm.sh/resource-policy: keep) and records a new revision. Use dryRun to preview the per-object changes without touching the cluster"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("release",
+			mcp.Required(),
+			mcp.Description("Name of the Helm release"),
+		),
+		mcp.WithNumber("revision",
+			mcp.Description("Revision to roll back to (default: the revision before the current one)"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Only report the revision history and what the rollback would change, with a server-side dry run (default: false)"),
+		),
+	)
+}
+
+// Handler applies the target revision's manifest and records the rollback as a new revision.
+func (h *HelmRollbackTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateHelmRollbackParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate helm_rollback params: %w", err)
+	}
+	clientset, err := h.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	releases, err := listHelmReleases(ctx, clientset, input.Namespace, input.Release)
+	if err != nil {
+		return nil, err
+	}
+	current, target, err := helmRollbackRevisions(releases, input.Revision)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := parseYAMLObjects([]byte(target.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of revision %d: %w", target.Version, err)
+	}
+	currentObjects, err := parseYAMLObjects([]byte(current.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of revision %d: %w", current.Version, err)
+	}
+	removed := removedHelmObjects(currentObjects, objects)
+	if err := authorizeHelmObjects(ctx, input.Namespace, objects, removed); err != nil {
+		return nil, err
+	}
+	mapper, err := h.client.RESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+
+	history := make([]HelmRevision, 0, len(releases))
+	for _, r := range releases {
+		history = append(history, helmRevisionSummary(r))
+	}
+	result := map[string]any{
+		"release":      input.Release,
+		"namespace":    input.Namespace,
+		"fromRevision": current.Version,
+		"toRevision":   target.Version,
+		"newRevision":  releases[len(releases)-1].Version + 1,
+		"history":      history,
+	}
+
+	if input.DryRun {
+		result["dryRun"] = true
+		result["status"] = "Dry run: release not changed"
+		result["objects"] = previewHelmObjects(ctx, h.client, mapper, input.Namespace, objects)
+		result["wouldDelete"] = helmObjectNames(removed, input.Namespace)
+		return marshalHelmResult(result)
+	}
+
+	applied, deleted, failed := deployHelmObjects(ctx, h.client, mapper, input.Namespace, objects, removed)
+	status := "deployed"
+	if len(failed) > 0 {
+		status = "failed"
+	}
+	record, err := recordHelmRollback(ctx, clientset, releases, target, status, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result["status"] = "Rolled back to revision " + strconv.Itoa(target.Version)
+	if len(failed) > 0 {
+		result["status"] = "Rollback failed; recorded as a failed revision"
+		result["failed"] = failed
+	}
+	result["newRevision"] = record.Version
+	result["applied"] = applied
+	result["deleted"] = deleted
+	return marshalHelmResult(result)
+}
+
+// authorizeHelmObjects checks that the caller may access the namespaces of release objects outside
+// the release namespace.
+func authorizeHelmObjects(ctx context.Context, namespace string, objects, removed []*unstructured.Unstructured) error {
+	for _, obj := range append(objects[:len(objects):len(objects)], removed...) {
+		if ns := obj.GetNamespace(); ns != "" && ns != namespace {
+			if err := auth.AuthorizeNamespace(ctx, ns); err != nil {
+				return fmt.Errorf("%s: %w", helmObjectName(obj, namespace), err)
+			}
+		}
+	}
+	return nil
+}
+
+// previewHelmObjects reports what applying each object as the helm field manager would change.
+func previewHelmObjects(ctx context.Context, client Client, mapper meta.RESTMapper, namespace string, objects []*unstructured.Unstructured) []ApplyDiff {
+	diffs := make([]ApplyDiff, 0, len(objects))
+	preview := NewDiffApplyTool(client)
+	for _, obj := range objects {
+		diffs = append(diffs, preview.diffObject(ctx, mapper, obj, &DiffApplyInput{Namespace: namespace, FieldManager: helmFieldManager, Force: true}))
+	}
+	return diffs
+}
+
+// deployHelmObjects applies the objects of a manifest and, once all of them applied, deletes the
+// removed ones, as Helm does on upgrade and rollback.
+func deployHelmObjects(ctx context.Context, client Client, mapper meta.RESTMapper, namespace string, objects, removed []*unstructured.Unstructured) (applied, deleted, failed []string) {
+	for _, obj := range objects {
+		name := helmObjectName(obj, namespace)
+		if err := applyHelmObject(ctx, client, mapper, obj, namespace); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		applied = append(applied, name)
+	}
+	if len(failed) > 0 {
+		return applied, deleted, failed
+	}
+	for _, obj := range removed {
+		name := helmObjectName(obj, namespace)
+		if err := deleteHelmObject(ctx, client, mapper, obj, namespace); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		deleted = append(deleted, name)
+	}
+	return applied, deleted, failed
+}
+
+// applyHelmObject server-side applies one manifest object as the helm field manager.
+func applyHelmObject(ctx context.Context, client Client, mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace string) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("%s is not served by the cluster: %w", gvk.String(), err)
+	}
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if namespaced && obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+	}
+	ri, err := client.ResourceInterface(mapping.Resource, namespaced, obj.GetNamespace())
+	if err != nil {
+		return fmt.Errorf("failed to create resource interface: %w", err)
+	}
+	_, err = ri.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: helmFieldManager, Force: true})
+	return err
+}
+
+// deleteHelmObject deletes a resource the new manifest does not contain; a missing one is not an error.
+func deleteHelmObject(ctx context.Context, client Client, mapper meta.RESTMapper, obj *unstructured.Unstructured, namespace string) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("%s is not served by the cluster: %w", gvk.String(), err)
+	}
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	ns := ""
+	if namespaced {
+		ns = obj.GetNamespace()
+		if ns == "" {
+			ns = namespace
+		}
+	}
+	ri, err := client.ResourceInterface(mapping.Resource, namespaced, ns)
+	if err != nil {
+		return fmt.Errorf("failed to create resource interface: %w", err)
+	}
+	propagation := metav1.DeletePropagationBackground
+	if err := ri.Delete(ctx, obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// listHelmReleases returns the decoded release records of a release, oldest revision first.
+func listHelmReleases(ctx context.Context, clientset kubernetes.Interface, namespace, name string) ([]*helmRelease, error) {
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: "owner=helm,name=" + name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Helm release secrets: %w", err)
+	}
+	releases := make([]*helmRelease, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Type != helmReleaseSecretType {
+			continue
+		}
+		release, err := decodeHelmRecord(secret.Data["release"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Helm release secret %s: %w", secret.Name, err)
+		}
+		release.secret = secret
+		releases = append(releases, release)
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no Helm release %s in namespace %s (no %s secrets labeled owner=helm)", name, namespace, helmReleaseSecretType)
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Version < releases[j].Version })
+	return releases, nil
+}
+
+// decodeHelmRecord decodes the "release" key of a Helm storage Secret: base64-encoded, usually
+// gzipped, JSON.
+func decodeHelmRecord(data []byte) (*helmRelease, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+	if bytes.HasPrefix(decoded, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress release: %w", err)
+		}
+		defer reader.Close()
+		if decoded, err = io.ReadAll(io.LimitReader(reader, maxArchiveBytes)); err != nil {
+			return nil, fmt.Errorf("failed to decompress release: %w", err)
+		}
+	}
+	release := &helmRelease{}
+	if err := json.Unmarshal(decoded, release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	if err := json.Unmarshal(decoded, &release.raw); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return release, nil
+}
+
+// encodeHelmRelease encodes a release record the way decodeHelmRecord reads it.
+func encodeHelmRelease(raw map[string]any) ([]byte, error) {
+	doc, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal release: %w", err)
+	}
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(doc); err != nil {
+		return nil, fmt.Errorf("failed to compress release: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress release: %w", err)
+	}
+	return []byte(base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// helmRollbackRevisions returns the deployed revision and the one to roll back to: revision, or the
+// one before the deployed revision. A release with an operation in progress is refused.
+func helmRollbackRevisions(releases []*helmRelease, revision int) (*helmRelease, *helmRelease, error) {
+	current, err := deployedHelmRelease(releases)
+	if err != nil {
+		return nil, nil, err
+	}
+	if revision == 0 {
+		revision = current.Version - 1
+	}
+	if revision == current.Version {
+		return nil, nil, fmt.Errorf("revision %d is already the deployed revision", revision)
+	}
+	for _, r := range releases {
+		if r.Version == revision {
+			return current, r, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("release %s has no revision %d; Helm keeps only the last revisions (--history-max)", current.Name, revision)
+}
+
+// deployedHelmRelease returns the deployed revision, or the latest one when none is deployed. A
+// release with an operation in progress is refused.
+func deployedHelmRelease(releases []*helmRelease) (*helmRelease, error) {
+	latest := releases[len(releases)-1]
+	if strings.HasPrefix(latest.Info.Status, "pending-") {
+		return nil, fmt.Errorf("release %s has an operation in progress (revision %d is %s)", latest.Name, latest.Version, latest.Info.Status)
+	}
+	for i := len(releases) - 1; i >= 0; i-- {
+		if releases[i].Info.Status == "deployed" {
+			return releases[i], nil
+		}
+	}
+	return latest, nil
+}
+
+// removedHelmObjects returns the objects of the current manifest that the target manifest does not
+// contain, except those Helm is told to keep.
+func removedHelmObjects(current, target []*unstructured.Unstructured) []*unstructured.Unstructured {
+	keep := map[string]bool{}
+	for _, obj := range target {
+		keep[helmObjectKey(obj)] = true
+	}
+	var removed []*unstructured.Unstructured
+	for _, obj := range current {
+		if keep[helmObjectKey(obj)] || obj.GetAnnotations()[helmResourcePolicy] == "keep" {
+			continue
+		}
+		removed = append(removed, obj)
+	}
+	return removed
+}
+
+// helmObjectKey identifies an object by group, kind, namespace and name, ignoring the API version.
+func helmObjectKey(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group + "/" + gvk.Kind + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// helmObjectName names an object as Kind/name, or Kind/namespace/name outside the release namespace.
+func helmObjectName(obj *unstructured.Unstructured, namespace string) string {
+	if ns := obj.GetNamespace(); ns != "" && ns != namespace {
+		return obj.GetKind() + "/" + ns + "/" + obj.GetName()
+	}
+	return obj.GetKind() + "/" + obj.GetName()
+}
+
+func helmObjectNames(objects []*unstructured.Unstructured, namespace string) []string {
+	names := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		names = append(names, helmObjectName(obj, namespace))
+	}
+	return names
+}
+
+// recordHelmRollback stores the target revision as a new revision with the given status, and marks
+// the previously deployed revisions superseded, as helm rollback does.
+func recordHelmRollback(ctx context.Context, clientset kubernetes.Interface, releases []*helmRelease, target *helmRelease, status string, now time.Time) (*helmRelease, error) {
+	raw, err := copyHelmRecord(target.raw)
+	if err != nil {
+		return nil, err
+	}
+	return recordHelmRevision(ctx, clientset, releases, raw, status, fmt.Sprintf("Rollback to %d", target.Version), now)
+}
+
+// recordHelmRevision stores a release record as the next revision with the given status and
+// description. A deployed revision supersedes the previously deployed ones.
+func recordHelmRevision(ctx context.Context, clientset kubernetes.Interface, releases []*helmRelease, raw map[string]any, status, description string, now time.Time) (*helmRelease, error) {
+	latest := releases[len(releases)-1]
+	version := latest.Version + 1
+	secrets := clientset.CoreV1().Secrets(latest.secret.Namespace)
+
+	if status == "deployed" {
+		for _, r := range releases {
+			if r.Info.Status != "deployed" {
+				continue
+			}
+			if err := setHelmReleaseStatus(r, "superseded"); err != nil {
+				return nil, err
+			}
+			if _, err := secrets.Update(ctx, r.secret, metav1.UpdateOptions{}); err != nil {
+				return nil, fmt.Errorf("failed to mark revision %d superseded: %w", r.Version, err)
+			}
+		}
+	}
+
+	raw["version"] = version
+	info, _ := raw["info"].(map[string]any)
+	if info == nil {
+		info = map[string]any{}
+	}
+	if first, ok := latest.raw["info"].(map[string]any); ok && first["first_deployed"] != nil {
+		info["first_deployed"] = first["first_deployed"]
+	}
+	info["last_deployed"] = now.UTC().Format(time.RFC3339Nano)
+	info["status"] = status
+	info["description"] = description
+	delete(info, "deleted")
+	raw["info"] = info
+
+	data, err := encodeHelmRelease(raw)
+	if err != nil {
+		return nil, err
+	}
+	name := latest.Name
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, version),
+			Namespace: latest.secret.Namespace,
+			Labels: map[string]string{
+				"name":       name,
+				"owner":      "helm",
+				"status":     status,
+				"version":    strconv.Itoa(version),
+				"modifiedAt": strconv.FormatInt(now.Unix(), 10),
+			},
+		},
+		Type: helmReleaseSecretType,
+		Data: map[string][]byte{"release": data},
+	}
+	created, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record revision %d: %w", version, err)
+	}
+	record, err := decodeHelmRecord(created.Data["release"])
+	if err != nil {
+		return nil, err
+	}
+	record.secret = created
+	return record, nil
+}
+
+// setHelmReleaseStatus changes the status of a release record in its secret data and labels.
+func setHelmReleaseStatus(release *helmRelease, status string) error {
+	info, _ := release.raw["info"].(map[string]any)
+	if info == nil {
+		info = map[string]any{}
+		release.raw["info"] = info
+	}
+	info["status"] = status
+	data, err := encodeHelmRelease(release.raw)
+	if err != nil {
+		return err
+	}
+	release.Info.Status = status
+	release.secret = release.secret.DeepCopy()
+	release.secret.Data["release"] = data
+	if release.secret.Labels == nil {
+		release.secret.Labels = map[string]string{}
+	}
+	release.secret.Labels["status"] = status
+	return nil
+}
+
+// copyHelmRecord deep-copies a release record through JSON.
+func copyHelmRecord(raw map[string]any) (map[string]any, error) {
+	doc, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy release: %w", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(doc, &out); err != nil {
+		return nil, fmt.Errorf("failed to copy release: %w", err)
+	}
+	return out, nil
+}
+
+// helmRevisionSummary describes one revision of the history.
+func helmRevisionSummary(r *helmRelease) HelmRevision {
+	return HelmRevision{
+		Revision:    r.Version,
+		Status:      r.Info.Status,
+		Chart:       r.Chart.Metadata.Name + "-" + r.Chart.Metadata.Version,
+		AppVersion:  r.Chart.Metadata.AppVersion,
+		Updated:     r.Info.LastDeployed,
+		Description: r.Info.Description,
+	}
+}
+
+func marshalHelmResult(result map[string]any) (*mcp.CallToolResult, error) {
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+func parseAndValidateHelmRollbackParams(args map[string]any) (*HelmRollbackInput, error) {
+	namespace, release, err := parseHelmReleaseParams(args)
+	if err != nil {
+		return nil, err
+	}
+	input := &HelmRollbackInput{Namespace: namespace, Release: release}
+	if v, ok := args["revision"].(float64); ok {
+		if v < 1 || v != float64(int(v)) {
+			return nil, errors.New("revision must be a positive integer")
+		}
+		input.Revision = int(v)
+	}
+	input.DryRun, _ = args["dryRun"].(bool)
+	return input, nil
+}
+
+// parseHelmReleaseParams returns the namespace, default unless given, and the name of the release a
+// call acts on.
+func parseHelmReleaseParams(args map[string]any) (string, string, error) {
+	namespace := metav1.NamespaceDefault
+	if ns, ok := args["namespace"].(string); ok && ns != "" {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return "", "", fmt.Errorf("invalid namespace: %w", err)
+		}
+		namespace = ns
+	}
+	release, _ := args["release"].(string)
+	if release == "" {
+		return "", "", errors.New("release must be provided")
+	}
+	// Helm release names are DNS-1123 labels of at most 53 characters.
+	if err := validation.ValidateResourceName(release); err != nil || len(release) > 53 {
+		return "", "", fmt.Errorf("invalid release name %q", release)
+	}
+	return namespace, release, nil
+}
@@ -0,0 +1,444 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/strvals"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// HelmSetValuesInput represents the input for helm_set_values.
+type HelmSetValuesInput struct {
+	Namespace string         `json:"namespace"`
+	Release   string         `json:"release"`
+	Values    map[string]any `json:"values,omitempty"`
+	Set       []string       `json:"set,omitempty"`
+	DryRun    bool           `json:"dryRun,omitempty"`
+}
+
+// HelmSetValuesTool upgrades a Helm release with changed values, rendering the chart stored with the
+// deployed revision through the Helm SDK.
+type HelmSetValuesTool struct {
+	client Client
+}
+
+// NewHelmSetValuesTool creates a new HelmSetValuesTool with the provided Kubernetes client.
+func NewHelmSetValuesTool(client Client) *HelmSetValuesTool {
+	return &HelmSetValuesTool{client: client}
+}
+
+// Tool returns the MCP tool definition for helm_set_values.
+func (h *HelmSetValuesTool) Tool() mcp.Tool {
+	return mcp.NewTool("helm_set_values",
+		mcp.WithDescription("Change values of a Helm release, like 'helm upgrade --reuse-values --no-hooks' with the chart it is deployed with: merges values and set onto the release's current values, renders the chart stored with the deployed revision through the Helm SDK (lookup reads the live cluster), server-side applies the manifest as the helm field manager, deletes resources the new manifest drops (unless annotated helm.sh/resource-policy: keep) and records a new revision. Charts with subcharts are refused because Helm does not store them with the release. Use dryRun to preview the value and per-object changes"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("release",
+			mcp.Required(),
+			mcp.Description("Name of the Helm release"),
+		),
+		mcp.WithObject("values",
+			mcp.Description("Values merged onto the current values, like a values file; null removes a key"),
+		),
+		mcp.WithArray("set",
+			mcp.Description("Values in helm --set syntax applied after values, e.g. [\"image.tag=1.4.2\", \"replicaCount=3\"]"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Only report the changed values and what the upgrade would change, with a server-side dry run (default: false)"),
+		),
+	)
+}
+
+// Handler renders the release with the new values, applies the manifest and records the upgrade as a
+// new revision.
+func (h *HelmSetValuesTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateHelmSetValuesParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate helm_set_values params: %w", err)
+	}
+	clientset, err := h.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	releases, err := listHelmReleases(ctx, clientset, input.Namespace, input.Release)
+	if err != nil {
+		return nil, err
+	}
+	current, err := deployedHelmRelease(releases)
+	if err != nil {
+		return nil, err
+	}
+	chrt, err := helmReleaseChart(current)
+	if err != nil {
+		return nil, err
+	}
+	currentValues, _ := current.raw["config"].(map[string]any)
+	values, err := mergeHelmValues(currentValues, input.Values, input.Set)
+	if err != nil {
+		return nil, err
+	}
+
+	disco, err := h.client.DiscoClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	caps, err := helmCapabilities(disco)
+	if err != nil {
+		return nil, err
+	}
+	dyn, err := h.client.DynamicClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	mapper, err := h.client.RESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+	revision := releases[len(releases)-1].Version + 1
+	options := chartutil.ReleaseOptions{Name: input.Release, Namespace: input.Namespace, Revision: revision, IsUpgrade: true}
+	manifest, hooks, err := renderHelmRelease(chrt, values, options, caps, helmLookup{ctx: ctx, client: dyn, mapper: mapper})
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := parseYAMLObjects([]byte(manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the rendered manifest: %w", err)
+	}
+	currentObjects, err := parseYAMLObjects([]byte(current.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of revision %d: %w", current.Version, err)
+	}
+	removed := removedHelmObjects(currentObjects, objects)
+	if err := authorizeHelmObjects(ctx, input.Namespace, objects, removed); err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{
+		"release":      input.Release,
+		"namespace":    input.Namespace,
+		"chart":        helmRevisionSummary(current).Chart,
+		"fromRevision": current.Version,
+		"newRevision":  revision,
+		"values":       helmValueChanges(currentValues, values),
+	}
+	if len(hooks) > 0 {
+		result["skippedHooks"] = helmHookNames(hooks)
+	}
+
+	if input.DryRun {
+		result["dryRun"] = true
+		result["status"] = "Dry run: release not changed"
+		result["objects"] = previewHelmObjects(ctx, h.client, mapper, input.Namespace, objects)
+		result["wouldDelete"] = helmObjectNames(removed, input.Namespace)
+		return marshalHelmResult(result)
+	}
+
+	applied, deleted, failed := deployHelmObjects(ctx, h.client, mapper, input.Namespace, objects, removed)
+	status, description := "deployed", "Upgrade complete"
+	if len(failed) > 0 {
+		status, description = "failed", "Upgrade failed: "+strings.Join(failed, "; ")
+	}
+	raw, err := helmUpgradeRecord(current, values, manifest, hooks)
+	if err != nil {
+		return nil, err
+	}
+	record, err := recordHelmRevision(ctx, clientset, releases, raw, status, description, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result["status"] = "Upgraded to revision " + strconv.Itoa(record.Version)
+	if len(failed) > 0 {
+		result["status"] = "Upgrade failed; recorded as a failed revision"
+		result["failed"] = failed
+	}
+	result["newRevision"] = record.Version
+	result["applied"] = applied
+	result["deleted"] = deleted
+	return marshalHelmResult(result)
+}
+
+// helmReleaseChart decodes the chart stored with a release record. Helm stores the chart without its
+// subcharts, so a chart that declares dependencies cannot be rendered from it.
+func helmReleaseChart(r *helmRelease) (*chart.Chart, error) {
+	doc, err := json.Marshal(r.raw["chart"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the chart of revision %d: %w", r.Version, err)
+	}
+	chrt := &chart.Chart{}
+	if err := json.Unmarshal(doc, chrt); err != nil {
+		return nil, fmt.Errorf("failed to read the chart of revision %d: %w", r.Version, err)
+	}
+	if chrt.Metadata == nil || len(chrt.Templates) == 0 {
+		return nil, fmt.Errorf("revision %d of release %s does not store its chart templates", r.Version, r.Name)
+	}
+	if len(chrt.Metadata.Dependencies) > 0 {
+		names := make([]string, 0, len(chrt.Metadata.Dependencies))
+		for _, dep := range chrt.Metadata.Dependencies {
+			names = append(names, dep.Name)
+		}
+		return nil, fmt.Errorf("chart %s has subcharts (%s), which Helm does not store with the release; upgrade it with helm", chrt.Metadata.Name, strings.Join(names, ", "))
+	}
+	return chrt, nil
+}
+
+// mergeHelmValues returns the release values with values merged onto them and the set expressions
+// applied, like helm upgrade --reuse-values -f values --set set. A null in values removes the key.
+func mergeHelmValues(current, values map[string]any, set []string) (map[string]any, error) {
+	merged, err := copyHelmRecord(current)
+	if err != nil {
+		return nil, err
+	}
+	if merged == nil {
+		merged = map[string]any{}
+	}
+	if len(values) > 0 {
+		overrides, err := copyHelmRecord(values)
+		if err != nil {
+			return nil, err
+		}
+		merged = chartutil.CoalesceTables(overrides, merged)
+	}
+	for _, expr := range set {
+		if err := strvals.ParseInto(expr, merged); err != nil {
+			return nil, fmt.Errorf("invalid set %q: %w", expr, err)
+		}
+	}
+	return merged, nil
+}
+
+// helmValueChanges lists the values that differ between the current and the new values by path.
+func helmValueChanges(current, values map[string]any) []FieldDifference {
+	before, after := map[string]string{}, map[string]string{}
+	for k, v := range current {
+		flattenFields(k, v, before)
+	}
+	for k, v := range values {
+		flattenFields(k, v, after)
+	}
+	return diffFields(before, after)
+}
+
+// helmCapabilities describes the cluster to chart templates, as Helm does from discovery.
+func helmCapabilities(disco discovery.DiscoveryInterface) (*chartutil.Capabilities, error) {
+	version, err := disco.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	groups, resources, err := disco.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("failed to discover API versions: %w", err)
+	}
+	apiVersions := chartutil.DefaultVersionSet
+	if len(groups) > 0 || len(resources) > 0 {
+		seen := map[string]bool{}
+		apiVersions = nil
+		add := func(v string) {
+			if !seen[v] {
+				seen[v] = true
+				apiVersions = append(apiVersions, v)
+			}
+		}
+		for _, g := range groups {
+			for _, gv := range g.Versions {
+				add(gv.GroupVersion)
+			}
+		}
+		for _, r := range resources {
+			for _, res := range r.APIResources {
+				add(path.Join(r.GroupVersion, res.Kind))
+			}
+		}
+	}
+	return &chartutil.Capabilities{
+		APIVersions: apiVersions,
+		KubeVersion: chartutil.KubeVersion{Version: version.GitVersion, Major: version.Major, Minor: version.Minor},
+		HelmVersion: chartutil.DefaultCapabilities.HelmVersion,
+	}, nil
+}
+
+// renderHelmRelease renders a chart the way helm upgrade does and returns the manifest, in install
+// order, and the hooks. lookup serves the lookup template function; without it lookups find nothing.
+func renderHelmRelease(chrt *chart.Chart, values map[string]any, options chartutil.ReleaseOptions, caps *chartutil.Capabilities, lookup engine.ClientProvider) (string, []*release.Hook, error) {
+	if chrt.Metadata.KubeVersion != "" && !chartutil.IsCompatibleRange(chrt.Metadata.KubeVersion, caps.KubeVersion.String()) {
+		return "", nil, fmt.Errorf("chart requires kubeVersion %s, which is incompatible with Kubernetes %s", chrt.Metadata.KubeVersion, caps.KubeVersion.String())
+	}
+	renderValues, err := chartutil.ToRenderValues(chrt, values, options, caps)
+	if err != nil {
+		return "", nil, err
+	}
+	var files map[string]string
+	if lookup != nil {
+		files, err = engine.RenderWithClientProvider(chrt, renderValues, lookup)
+	} else {
+		files, err = engine.Render(chrt, renderValues)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to render chart: %w", err)
+	}
+	for name := range files {
+		if strings.HasSuffix(name, "NOTES.txt") {
+			delete(files, name)
+		}
+	}
+	hooks, manifests, err := releaseutil.SortManifests(files, nil, releaseutil.InstallOrder)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse the rendered chart: %w", err)
+	}
+	var b strings.Builder
+	for _, m := range manifests {
+		fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+	}
+	return b.String(), hooks, nil
+}
+
+func helmHookNames(hooks []*release.Hook) []string {
+	names := make([]string, 0, len(hooks))
+	for _, hook := range hooks {
+		names = append(names, hook.Kind+"/"+hook.Name)
+	}
+	return names
+}
+
+// helmUpgradeRecord returns the record of the deployed revision with the new values, manifest and
+// hooks.
+func helmUpgradeRecord(current *helmRelease, values map[string]any, manifest string, hooks []*release.Hook) (map[string]any, error) {
+	raw, err := copyHelmRecord(current.raw)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := json.Marshal(hooks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal hooks: %w", err)
+	}
+	var rawHooks []any
+	if err := json.Unmarshal(doc, &rawHooks); err != nil {
+		return nil, fmt.Errorf("failed to marshal hooks: %w", err)
+	}
+	raw["config"] = values
+	raw["manifest"] = manifest
+	raw["hooks"] = rawHooks
+	return raw, nil
+}
+
+// helmLookup serves the lookup function of chart templates from the cluster, limited to the
+// namespaces the caller may access.
+type helmLookup struct {
+	ctx    context.Context
+	client dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+func (l helmLookup) GetClientFor(apiVersion, kind string) (dynamic.NamespaceableResourceInterface, bool, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, false, err
+	}
+	mapping, err := l.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kind}, gv.Version)
+	if err != nil {
+		return nil, false, err
+	}
+	resource := l.client.Resource(mapping.Resource)
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return resource, false, nil
+	}
+	return helmLookupResource{NamespaceableResourceInterface: resource, ctx: l.ctx}, true, nil
+}
+
+// helmLookupResource checks the namespace of namespaced lookups. A lookup without a namespace reads
+// every namespace and is only allowed to clients that are not scoped to namespaces.
+type helmLookupResource struct {
+	dynamic.NamespaceableResourceInterface
+	ctx context.Context
+}
+
+func (r helmLookupResource) Namespace(namespace string) dynamic.ResourceInterface {
+	if err := auth.AuthorizeNamespace(r.ctx, namespace); err != nil {
+		return helmDeniedLookup{err: err}
+	}
+	return r.NamespaceableResourceInterface.Namespace(namespace)
+}
+
+func (r helmLookupResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if err := r.authorizeAll(); err != nil {
+		return nil, err
+	}
+	return r.NamespaceableResourceInterface.Get(ctx, name, options, subresources...)
+}
+
+func (r helmLookupResource) List(ctx context.Context, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if err := r.authorizeAll(); err != nil {
+		return nil, err
+	}
+	return r.NamespaceableResourceInterface.List(ctx, options)
+}
+
+func (r helmLookupResource) authorizeAll() error {
+	if client := auth.ClientFromContext(r.ctx); client != nil && len(client.Namespaces) > 0 {
+		return fmt.Errorf("client %s is scoped to namespaces: lookup must name a namespace", client.Name)
+	}
+	return nil
+}
+
+// helmDeniedLookup fails the reads of a lookup in a namespace the caller may not access.
+type helmDeniedLookup struct {
+	dynamic.ResourceInterface
+	err error
+}
+
+func (d helmDeniedLookup) Get(context.Context, string, metav1.GetOptions, ...string) (*unstructured.Unstructured, error) {
+	return nil, d.err
+}
+
+func (d helmDeniedLookup) List(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return nil, d.err
+}
+
+func parseAndValidateHelmSetValuesParams(args map[string]any) (*HelmSetValuesInput, error) {
+	namespace, release, err := parseHelmReleaseParams(args)
+	if err != nil {
+		return nil, err
+	}
+	input := &HelmSetValuesInput{Namespace: namespace, Release: release}
+	if v, ok := args["values"]; ok && v != nil {
+		values, ok := v.(map[string]any)
+		if !ok {
+			return nil, errors.New("values must be an object")
+		}
+		input.Values = values
+	}
+	if input.Set, err = stringSliceArg(args, "set"); err != nil {
+		return nil, err
+	}
+	for _, expr := range input.Set {
+		if !strings.Contains(expr, "=") {
+			return nil, fmt.Errorf("invalid set %q: expected path=value", expr)
+		}
+	}
+	if len(input.Values) == 0 && len(input.Set) == 0 {
+		return nil, errors.New("values or set must be provided")
+	}
+	input.DryRun, _ = args["dryRun"].(bool)
+	return input, nil
+}
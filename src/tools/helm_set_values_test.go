@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chartutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	helmConfigMapTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data:
+  tag: {{ .Values.image.tag | quote }}
+  revision: "{{ .Release.Revision }}"
+`
+	helmHookTemplate = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Release.Name }}-migrate
+  annotations:
+    helm.sh/hook: pre-upgrade
+`
+)
+
+// helmChartRecord is the chart of a release record as Helm stores it, with base64 file data.
+func helmChartRecord(dependencies ...string) map[string]any {
+	file := func(name, data string) map[string]any {
+		return map[string]any{"name": name, "data": base64.StdEncoding.EncodeToString([]byte(data))}
+	}
+	metadata := map[string]any{"name": "web", "version": "1.2.0", "apiVersion": "v2"}
+	if len(dependencies) > 0 {
+		var deps []any
+		for _, d := range dependencies {
+			deps = append(deps, map[string]any{"name": d, "version": "1.0.0"})
+		}
+		metadata["dependencies"] = deps
+	}
+	return map[string]any{
+		"metadata": metadata,
+		"templates": []any{
+			file("templates/configmap.yaml", helmConfigMapTemplate),
+			file("templates/migrate.yaml", helmHookTemplate),
+			file("templates/NOTES.txt", "Installed {{ .Release.Name }}"),
+		},
+		"values": map[string]any{"image": map[string]any{"tag": "1.0", "pullPolicy": "IfNotPresent"}},
+	}
+}
+
+func TestHelmReleaseChart(t *testing.T) {
+	release := &helmRelease{Name: "web", Version: 2, raw: map[string]any{"chart": helmChartRecord()}}
+	chrt, err := helmReleaseChart(release)
+	require.NoError(t, err)
+	assert.Equal(t, "web", chrt.Metadata.Name)
+	assert.Len(t, chrt.Templates, 3)
+	assert.Equal(t, helmConfigMapTemplate, string(chrt.Templates[0].Data))
+
+	release.raw["chart"] = helmChartRecord("redis", "postgresql")
+	_, err = helmReleaseChart(release)
+	assert.EqualError(t, err, "chart web has subcharts (redis, postgresql), which Helm does not store with the release; upgrade it with helm")
+
+	release.raw["chart"] = map[string]any{"metadata": map[string]any{"name": "web"}}
+	_, err = helmReleaseChart(release)
+	assert.EqualError(t, err, "revision 2 of release web does not store its chart templates")
+}
+
+func TestMergeHelmValues(t *testing.T) {
+	current := map[string]any{"image": map[string]any{"tag": "1.0", "repository": "web"}, "replicas": float64(2), "debug": true}
+	values, err := mergeHelmValues(current, map[string]any{"image": map[string]any{"tag": "1.1"}, "debug": nil}, []string{"replicas=3", "ingress.enabled=true"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"image":    map[string]any{"tag": "1.1", "repository": "web"},
+		"replicas": int64(3),
+		"ingress":  map[string]any{"enabled": true},
+	}, values)
+	assert.Equal(t, "1.0", current["image"].(map[string]any)["tag"], "the current values must not be modified")
+
+	values, err = mergeHelmValues(nil, nil, []string{"image.tag=2.0"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"image": map[string]any{"tag": "2.0"}}, values)
+
+	_, err = mergeHelmValues(nil, nil, []string{"a[x]=1"})
+	assert.ErrorContains(t, err, `invalid set "a[x]=1"`)
+}
+
+func TestHelmValueChanges(t *testing.T) {
+	changes := helmValueChanges(
+		map[string]any{"image": map[string]any{"tag": "1.0"}, "debug": true},
+		map[string]any{"image": map[string]any{"tag": "1.1"}, "replicas": int64(3)},
+	)
+	assert.Equal(t, []FieldDifference{
+		{Field: "debug", A: "true"},
+		{Field: "image.tag", A: `"1.0"`, B: `"1.1"`},
+		{Field: "replicas", B: "3"},
+	}, changes)
+}
+
+func TestRenderHelmRelease(t *testing.T) {
+	chrt, err := helmReleaseChart(&helmRelease{raw: map[string]any{"chart": helmChartRecord()}})
+	require.NoError(t, err)
+	options := chartutil.ReleaseOptions{Name: "web", Namespace: "shop", Revision: 3, IsUpgrade: true}
+
+	manifest, hooks, err := renderHelmRelease(chrt, map[string]any{"image": map[string]any{"tag": "1.1"}}, options, chartutil.DefaultCapabilities, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "---\n# Source: web/templates/configmap.yaml\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\ndata:\n  tag: \"1.1\"\n  revision: \"3\"\n", manifest)
+	assert.Equal(t, []string{"Job/web-migrate"}, helmHookNames(hooks))
+
+	chrt.Metadata.KubeVersion = ">=99.0.0"
+	_, _, err = renderHelmRelease(chrt, nil, options, chartutil.DefaultCapabilities, nil)
+	assert.ErrorContains(t, err, "chart requires kubeVersion >=99.0.0")
+}
+
+func TestHelmCapabilities(t *testing.T) {
+	disco := fake.NewSimpleClientset().Discovery()
+	caps, err := helmCapabilities(disco)
+	require.NoError(t, err)
+	assert.Equal(t, chartutil.DefaultVersionSet, caps.APIVersions, "without discovered groups the default API versions apply")
+	assert.Equal(t, chartutil.DefaultCapabilities.HelmVersion, caps.HelmVersion)
+}
+
+func TestHelmUpgradeRecord(t *testing.T) {
+	releases, err := listHelmReleases(context.Background(), fakeHelmClientset(t), "shop", "web")
+	require.NoError(t, err)
+	current := releases[1]
+	raw, err := helmUpgradeRecord(current, map[string]any{"replicas": 5}, helmServiceManifest, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"replicas": 5}, raw["config"])
+	assert.Equal(t, helmServiceManifest, raw["manifest"])
+	assert.Equal(t, "1.2.0", raw["chart"].(map[string]any)["metadata"].(map[string]any)["version"])
+	assert.Equal(t, float64(2), current.raw["config"].(map[string]any)["replicas"], "the deployed record must not be modified")
+
+	record, err := recordHelmRevision(context.Background(), fakeHelmClientset(t), releases, raw, "deployed", "Upgrade complete", metav1.Now().Time)
+	require.NoError(t, err)
+	assert.Equal(t, 3, record.Version)
+	assert.Equal(t, "Upgrade complete", record.Info.Description)
+	assert.Equal(t, helmServiceManifest, record.Manifest)
+}
+
+func TestHelmLookup(t *testing.T) {
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	client := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{configMaps: "ConfigMapList"})
+	resource := helmLookupResource{NamespaceableResourceInterface: client.Resource(configMaps)}
+
+	resource.ctx = auth.WithClient(context.Background(), &auth.Client{Name: "payments", Namespaces: []string{"shop"}})
+	_, err := resource.Namespace("shop").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	_, err = resource.Namespace("kube-system").Get(context.Background(), "cluster-info", metav1.GetOptions{})
+	assert.EqualError(t, err, "client payments is not allowed to access namespace kube-system")
+	_, err = resource.List(context.Background(), metav1.ListOptions{})
+	assert.EqualError(t, err, "client payments is scoped to namespaces: lookup must name a namespace")
+
+	resource.ctx = context.Background()
+	_, err = resource.List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+}
+
+func TestParseAndValidateHelmSetValuesParams(t *testing.T) {
+	input, err := parseAndValidateHelmSetValuesParams(map[string]any{
+		"namespace": "shop", "release": "web", "values": map[string]any{"replicas": float64(3)}, "set": []any{"image.tag=1.1"}, "dryRun": true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &HelmSetValuesInput{Namespace: "shop", Release: "web", Values: map[string]any{"replicas": float64(3)}, Set: []string{"image.tag=1.1"}, DryRun: true}, input)
+
+	_, err = parseAndValidateHelmSetValuesParams(map[string]any{"release": "web"})
+	assert.EqualError(t, err, "values or set must be provided")
+	_, err = parseAndValidateHelmSetValuesParams(map[string]any{"release": "web", "set": []any{"image.tag"}})
+	assert.EqualError(t, err, `invalid set "image.tag": expected path=value`)
+	_, err = parseAndValidateHelmSetValuesParams(map[string]any{"release": "web", "values": "replicas: 3"})
+	assert.EqualError(t, err, "values must be an object")
+	_, err = parseAndValidateHelmSetValuesParams(map[string]any{"set": []any{"a=1"}})
+	assert.EqualError(t, err, "release must be provided")
+}
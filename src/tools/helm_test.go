@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func helmReleaseSecret(t *testing.T, version int, status, manifest string) *corev1.Secret {
+	t.Helper()
+	data, err := encodeHelmRelease(map[string]any{
+		"name":      "web",
+		"namespace": "shop",
+		"version":   version,
+		"info": map[string]any{
+			"status":         status,
+			"first_deployed": "2026-01-01T00:00:00Z",
+			"last_deployed":  "2026-01-0" + strconv.Itoa(version) + "T00:00:00Z",
+		},
+		"chart":    map[string]any{"metadata": map[string]any{"name": "web", "version": "1." + strconv.Itoa(version) + ".0"}},
+		"config":   map[string]any{"replicas": version},
+		"manifest": manifest,
+	})
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1.web.v" + strconv.Itoa(version),
+			Namespace: "shop",
+			Labels:    map[string]string{"name": "web", "owner": "helm", "status": status, "version": strconv.Itoa(version)},
+		},
+		Type: helmReleaseSecretType,
+		Data: map[string][]byte{"release": data},
+	}
+}
+
+const (
+	helmServiceManifest = "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"
+	helmConfigManifest  = "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-extra\n"
+)
+
+func fakeHelmClientset(t *testing.T) *fake.Clientset {
+	return fake.NewSimpleClientset(
+		helmReleaseSecret(t, 2, "deployed", helmServiceManifest+helmConfigManifest),
+		helmReleaseSecret(t, 1, "superseded", helmServiceManifest),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "shop", Labels: map[string]string{"name": "web", "owner": "helm"}}, Type: corev1.SecretTypeOpaque},
+	)
+}
+
+func TestListHelmReleases(t *testing.T) {
+	releases, err := listHelmReleases(context.Background(), fakeHelmClientset(t), "shop", "web")
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	assert.Equal(t, 1, releases[0].Version)
+	assert.Equal(t, 2, releases[1].Version)
+	assert.Equal(t, "deployed", releases[1].Info.Status)
+	assert.Equal(t, HelmRevision{Revision: 2, Status: "deployed", Chart: "web-1.2.0", Updated: "2026-01-02T00:00:00Z"}, helmRevisionSummary(releases[1]))
+
+	_, err = listHelmReleases(context.Background(), fakeHelmClientset(t), "shop", "api")
+	assert.ErrorContains(t, err, "no Helm release api in namespace shop")
+}
+
+func TestHelmRollbackRevisions(t *testing.T) {
+	releases, err := listHelmReleases(context.Background(), fakeHelmClientset(t), "shop", "web")
+	require.NoError(t, err)
+
+	current, target, err := helmRollbackRevisions(releases, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, current.Version)
+	assert.Equal(t, 1, target.Version)
+
+	_, _, err = helmRollbackRevisions(releases, 2)
+	assert.EqualError(t, err, "revision 2 is already the deployed revision")
+	_, _, err = helmRollbackRevisions(releases, 7)
+	assert.ErrorContains(t, err, "release web has no revision 7")
+
+	releases[1].Info.Status = "pending-upgrade"
+	_, _, err = helmRollbackRevisions(releases, 1)
+	assert.ErrorContains(t, err, "release web has an operation in progress")
+}
+
+func TestRemovedHelmObjects(t *testing.T) {
+	target, err := parseYAMLObjects([]byte(helmServiceManifest))
+	require.NoError(t, err)
+	current, err := parseYAMLObjects([]byte(helmServiceManifest + helmConfigManifest + "---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: kept\n  annotations:\n    helm.sh/resource-policy: keep\n"))
+	require.NoError(t, err)
+
+	removed := removedHelmObjects(current, target)
+	assert.Equal(t, []string{"ConfigMap/web-extra"}, helmObjectNames(removed, "shop"))
+
+	obj := &unstructured.Unstructured{}
+	obj.SetKind("Role")
+	obj.SetName("reader")
+	obj.SetNamespace("kube-system")
+	assert.Equal(t, "Role/kube-system/reader", helmObjectName(obj, "shop"))
+}
+
+func TestRecordHelmRollback(t *testing.T) {
+	clientset := fakeHelmClientset(t)
+	ctx := context.Background()
+	releases, err := listHelmReleases(ctx, clientset, "shop", "web")
+	require.NoError(t, err)
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	record, err := recordHelmRollback(ctx, clientset, releases, releases[0], "deployed", now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, record.Version)
+	assert.Equal(t, "deployed", record.Info.Status)
+	assert.Equal(t, "Rollback to 1", record.Info.Description)
+	assert.Equal(t, "2026-02-01T12:00:00Z", record.Info.LastDeployed)
+	assert.Equal(t, helmServiceManifest, record.Manifest)
+	assert.Equal(t, map[string]any{"replicas": float64(1)}, record.raw["config"])
+	assert.Equal(t, "2026-01-01T00:00:00Z", record.raw["info"].(map[string]any)["first_deployed"])
+
+	secret, err := clientset.CoreV1().Secrets("shop").Get(ctx, "sh.helm.release.v1.web.v3", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.SecretType(helmReleaseSecretType), secret.Type)
+	assert.Equal(t, "deployed", secret.Labels["status"])
+	assert.Equal(t, "3", secret.Labels["version"])
+
+	releases, err = listHelmReleases(ctx, clientset, "shop", "web")
+	require.NoError(t, err)
+	require.Len(t, releases, 3)
+	assert.Equal(t, "superseded", releases[1].Info.Status)
+	assert.Equal(t, "superseded", releases[1].secret.Labels["status"])
+
+	// A failed rollback leaves the deployed revision alone.
+	record, err = recordHelmRollback(ctx, clientset, releases, releases[1], "failed", now)
+	require.NoError(t, err)
+	assert.Equal(t, 4, record.Version)
+	assert.Equal(t, "failed", record.Info.Status)
+	releases, err = listHelmReleases(ctx, clientset, "shop", "web")
+	require.NoError(t, err)
+	assert.Equal(t, "deployed", releases[2].Info.Status)
+}
+
+func TestParseAndValidateHelmRollbackParams(t *testing.T) {
+	input, err := parseAndValidateHelmRollbackParams(map[string]any{"namespace": "shop", "release": "web", "revision": float64(3), "dryRun": true})
+	require.NoError(t, err)
+	assert.Equal(t, &HelmRollbackInput{Namespace: "shop", Release: "web", Revision: 3, DryRun: true}, input)
+
+	input, err = parseAndValidateHelmRollbackParams(map[string]any{"release": "web"})
+	require.NoError(t, err)
+	assert.Equal(t, "default", input.Namespace)
+
+	_, err = parseAndValidateHelmRollbackParams(map[string]any{})
+	assert.EqualError(t, err, "release must be provided")
+	_, err = parseAndValidateHelmRollbackParams(map[string]any{"release": "web", "revision": float64(1.5)})
+	assert.EqualError(t, err, "revision must be a positive integer")
+	_, err = parseAndValidateHelmRollbackParams(map[string]any{"release": "Web_1"})
+	assert.Error(t, err)
+}
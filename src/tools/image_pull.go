@@ -0,0 +1,607 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const registryCheckTimeout = 15 * time.Second
+
+// errRegistryTokenDenied is returned when the registry's token endpoint refuses the credentials.
+var errRegistryTokenDenied = errors.New("the registry token endpoint refused the credentials")
+
+// manifestMediaTypes are accepted by the registry check so multi-arch and OCI images resolve.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// cloudRegistryHosts are registries whose credentials usually come from the node's credential
+// provider (instance role or Workload Identity) rather than an imagePullSecret.
+var cloudRegistryHosts = []string{".dkr.ecr.", "gcr.io", "-docker.pkg.dev", ".azurecr.io"}
+
+// registryCredential is the auth an imagePullSecret holds for one registry. Password is never returned.
+type registryCredential struct {
+	Secret   string `json:"secret"`
+	Entry    string `json:"entry"`
+	Username string `json:"username,omitempty"`
+	Kind     string `json:"kind"`
+
+	password string
+	token    string
+}
+
+// PullSecretStatus is an imagePullSecret referenced by the pod spec or its service account.
+type PullSecretStatus struct {
+	Name       string   `json:"name"`
+	Exists     bool     `json:"exists"`
+	Type       string   `json:"type,omitempty"`
+	Registries []string `json:"registries,omitempty"`
+	Issue      string   `json:"issue,omitempty"`
+}
+
+// RegistryCheck is the result of resolving an image's manifest in its registry.
+type RegistryCheck struct {
+	Status string `json:"status"`
+	HTTP   int    `json:"httpStatus,omitempty"`
+	Digest string `json:"digest,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImagePullCheck is the pull readiness of one image.
+type ImagePullCheck struct {
+	Image         string              `json:"image"`
+	Registry      string              `json:"registry"`
+	Containers    []string            `json:"containers,omitempty"`
+	Credentials   *registryCredential `json:"credentials,omitempty"`
+	RegistryCheck *RegistryCheck      `json:"registryCheck,omitempty"`
+	Issues        []string            `json:"issues,omitempty"`
+}
+
+// CheckImagePullInput represents the input for check_image_pull.
+type CheckImagePullInput struct {
+	Namespace        string   `json:"namespace"`
+	Kind             string   `json:"kind,omitempty"`
+	Name             string   `json:"name,omitempty"`
+	Image            string   `json:"image,omitempty"`
+	ServiceAccount   string   `json:"serviceAccount,omitempty"`
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+	CheckRegistry    bool     `json:"checkRegistry,omitempty"`
+}
+
+// CheckImagePullTool verifies that a workload's images can be pulled with its imagePullSecrets.
+type CheckImagePullTool struct {
+	client Client
+}
+
+// NewCheckImagePullTool creates a new CheckImagePullTool with the provided Kubernetes client.
+func NewCheckImagePullTool(client Client) *CheckImagePullTool {
+	return &CheckImagePullTool{client: client}
+}
+
+// Tool returns the MCP tool definition for check_image_pull.
+func (c *CheckImagePullTool) Tool() mcp.Tool {
+	return mcp.NewTool("check_image_pull",
+		mcp.WithDescription("Check why an image may fail to pull (ErrImagePull/ImagePullBackOff): for a workload or a single image reference, verifies that the imagePullSecrets of the pod spec, or of its service account, exist and are docker config secrets, decodes which registries they hold credentials for and picks the one matching each image's registry (usernames only, never passwords). With checkRegistry it also requests the image manifest from the registry with those credentials to confirm the tag exists and is pullable"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Kind of the workload: Deployment, StatefulSet, DaemonSet, Job, CronJob or Pod (default: Deployment)"),
+			mcp.Enum("Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob", "Pod"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the workload whose images are checked"),
+		),
+		mcp.WithString("image",
+			mcp.Description("Image reference to check instead of a workload, e.g. registry.example.com/team/app:1.2"),
+		),
+		mcp.WithString("serviceAccount",
+			mcp.Description("With image: service account whose imagePullSecrets are used (default: default)"),
+		),
+		mcp.WithArray("imagePullSecrets",
+			mcp.Description("With image: imagePullSecrets to check instead of the service account's"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("checkRegistry",
+			mcp.Description("Request each image manifest from its registry to confirm the tag exists and the credentials are accepted (default: false)"),
+		),
+	)
+}
+
+// Handler resolves the pull secrets and checks every image against them.
+func (c *CheckImagePullTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateCheckImagePullParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate check_image_pull params: %w", err)
+	}
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	spec := &corev1.PodSpec{ServiceAccountName: input.ServiceAccount}
+	for _, name := range input.ImagePullSecrets {
+		spec.ImagePullSecrets = append(spec.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
+	}
+	if input.Image != "" {
+		spec.Containers = []corev1.Container{{Name: "image", Image: input.Image}}
+	} else if spec, err = workloadPodSpec(ctx, clientset, input.Namespace, input.Kind, input.Name); err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{"namespace": input.Namespace}
+	secretRefs, from, err := effectivePullSecrets(ctx, clientset, input.Namespace, spec)
+	if err != nil {
+		result["serviceAccountError"] = err.Error()
+	}
+	result["pullSecretsFrom"] = from
+
+	secrets := make([]PullSecretStatus, 0, len(secretRefs))
+	var credentials []registryCredential
+	for _, name := range secretRefs {
+		status := PullSecretStatus{Name: name}
+		secret, err := clientset.CoreV1().Secrets(input.Namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			status.Issue = "secret does not exist; the kubelet ignores it and pulls anonymously"
+		case err != nil:
+			status.Issue = fmt.Sprintf("failed to get secret: %v", err)
+		default:
+			status.Exists, status.Type = true, string(secret.Type)
+			creds, err := pullSecretCredentials(secret)
+			if err != nil {
+				status.Issue = err.Error()
+			}
+			for _, cred := range creds {
+				status.Registries = append(status.Registries, cred.Entry)
+			}
+			credentials = append(credentials, creds...)
+		}
+		secrets = append(secrets, status)
+	}
+	result["imagePullSecrets"] = secrets
+
+	checks := imagePullChecks(spec, credentials)
+	if input.CheckRegistry {
+		client := &http.Client{Timeout: registryCheckTimeout}
+		for i := range checks {
+			ref := parseImageRef(checks[i].Image)
+			checks[i].RegistryCheck = checkRegistryManifest(ctx, client, registryBaseURL(ref.Registry), ref, checks[i].Credentials)
+			if issue := registryCheckIssue(checks[i].RegistryCheck); issue != "" {
+				checks[i].Issues = append(checks[i].Issues, issue)
+			}
+		}
+	}
+	result["images"] = checks
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// workloadPodSpec returns the pod spec of a workload or pod.
+func workloadPodSpec(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string) (*corev1.PodSpec, error) {
+	var (
+		spec *corev1.PodSpec
+		err  error
+	)
+	switch kind {
+	case "Deployment":
+		d, e := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = e; err == nil {
+			spec = &d.Spec.Template.Spec
+		}
+	case "StatefulSet":
+		s, e := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = e; err == nil {
+			spec = &s.Spec.Template.Spec
+		}
+	case "DaemonSet":
+		d, e := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = e; err == nil {
+			spec = &d.Spec.Template.Spec
+		}
+	case "Job":
+		j, e := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = e; err == nil {
+			spec = &j.Spec.Template.Spec
+		}
+	case "CronJob":
+		cj, e := clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = e; err == nil {
+			spec = &cj.Spec.JobTemplate.Spec.Template.Spec
+		}
+	case "Pod":
+		p, e := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = e; err == nil {
+			spec = &p.Spec
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", strings.ToLower(kind), namespace, name, err)
+	}
+	return spec, nil
+}
+
+// effectivePullSecrets returns the imagePullSecrets the kubelet uses: the pod spec's, or the service
+// account's when the spec has none, which is what the ServiceAccount admission plugin injects.
+func effectivePullSecrets(ctx context.Context, clientset kubernetes.Interface, namespace string, spec *corev1.PodSpec) ([]string, string, error) {
+	var names []string
+	for _, ref := range spec.ImagePullSecrets {
+		names = append(names, ref.Name)
+	}
+	if len(names) > 0 {
+		return names, "podSpec", nil
+	}
+	saName := spec.ServiceAccountName
+	if saName == "" {
+		saName = "default"
+	}
+	from := "serviceAccount/" + saName
+	sa, err := clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, saName, metav1.GetOptions{})
+	if err != nil {
+		return nil, from, fmt.Errorf("failed to get service account %s: %w", saName, err)
+	}
+	for _, ref := range sa.ImagePullSecrets {
+		names = append(names, ref.Name)
+	}
+	return names, from, nil
+}
+
+// dockerConfigEntry is one registry of a .dockerconfigjson or .dockercfg secret.
+type dockerConfigEntry struct {
+	Auth          string `json:"auth"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+	RegistryToken string `json:"registrytoken"`
+}
+
+// pullSecretCredentials decodes the registry credentials of a docker config secret.
+func pullSecretCredentials(secret *corev1.Secret) ([]registryCredential, error) {
+	var auths map[string]dockerConfigEntry
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		var config struct {
+			Auths map[string]dockerConfigEntry `json:"auths"`
+		}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return nil, fmt.Errorf("%s is not valid JSON: %v", corev1.DockerConfigJsonKey, err)
+		}
+		auths = config.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+			return nil, fmt.Errorf("%s is not valid JSON: %v", corev1.DockerConfigKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("type %s is not %s; the kubelet ignores it", secret.Type, corev1.SecretTypeDockerConfigJson)
+	}
+	if len(auths) == 0 {
+		return nil, errors.New("docker config has no registry entries")
+	}
+
+	var creds []registryCredential
+	var problems []string
+	entries := make([]string, 0, len(auths))
+	for entry := range auths {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	for _, entry := range entries {
+		auth := auths[entry]
+		cred := registryCredential{Secret: secret.Name, Entry: entry, Username: auth.Username, password: auth.Password}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			user, pass, ok := strings.Cut(string(decoded), ":")
+			if err != nil || !ok {
+				problems = append(problems, fmt.Sprintf("auth of %s is not base64 user:password", entry))
+				continue
+			}
+			cred.Username, cred.password = user, pass
+		}
+		switch {
+		case auth.RegistryToken != "":
+			cred.Kind, cred.token = "bearer", auth.RegistryToken
+		case auth.IdentityToken != "":
+			cred.Kind, cred.password = "identityToken", auth.IdentityToken
+		case cred.Username != "":
+			cred.Kind = "basic"
+		default:
+			problems = append(problems, fmt.Sprintf("entry %s has no credentials", entry))
+			continue
+		}
+		creds = append(creds, cred)
+	}
+	if len(problems) > 0 {
+		return creds, errors.New(strings.Join(problems, "; "))
+	}
+	return creds, nil
+}
+
+// registryEntryMatches reports whether a docker config entry applies to an image, the way the
+// kubelet matches them: scheme and /v1/ suffixes are ignored, the host may use * wildcards and a
+// path limits the entry to repositories under it.
+func registryEntryMatches(entry string, ref imageRef) bool {
+	entry = strings.TrimPrefix(strings.TrimPrefix(entry, "https://"), "http://")
+	host, repoPath, _ := strings.Cut(entry, "/")
+	if repoPath == "v1" || repoPath == "v1/" || repoPath == "v2" || repoPath == "v2/" {
+		repoPath = ""
+	}
+	if normalizeRegistry(host) != normalizeRegistry(ref.Registry) {
+		matched, err := path.Match(host, ref.Registry)
+		if err != nil || !matched {
+			return false
+		}
+	}
+	repoPath = strings.TrimSuffix(repoPath, "/")
+	return repoPath == "" || ref.Repository == repoPath || strings.HasPrefix(ref.Repository, repoPath+"/")
+}
+
+// imagePullChecks matches each image of the pod spec with the most specific credential.
+func imagePullChecks(spec *corev1.PodSpec, credentials []registryCredential) []ImagePullCheck {
+	byImage := map[string]*ImagePullCheck{}
+	var order []string
+	containers := append([]corev1.Container{}, spec.InitContainers...)
+	for _, c := range append(containers, spec.Containers...) {
+		check, ok := byImage[c.Image]
+		if !ok {
+			check = &ImagePullCheck{Image: c.Image, Registry: parseImageRef(c.Image).Registry}
+			byImage[c.Image] = check
+			order = append(order, c.Image)
+		}
+		check.Containers = append(check.Containers, c.Name)
+	}
+
+	checks := make([]ImagePullCheck, 0, len(order))
+	for _, image := range order {
+		check := byImage[image]
+		ref := parseImageRef(image)
+		for i := range credentials {
+			cred := &credentials[i]
+			if registryEntryMatches(cred.Entry, ref) && (check.Credentials == nil || len(cred.Entry) > len(check.Credentials.Entry)) {
+				check.Credentials = cred
+			}
+		}
+		if check.Credentials == nil {
+			issue := "no imagePullSecret has credentials for " + ref.Registry + "; the image is pulled anonymously"
+			for _, host := range cloudRegistryHosts {
+				if strings.Contains(ref.Registry, host) {
+					issue += " unless the node's credential provider supplies them"
+					break
+				}
+			}
+			check.Issues = append(check.Issues, issue)
+		}
+		if ref.Tag == "latest" && ref.Digest == "" {
+			check.Issues = append(check.Issues, "uses the latest tag; a changed image is only pulled when imagePullPolicy is Always")
+		}
+		checks = append(checks, *check)
+	}
+	return checks
+}
+
+// registryBaseURL returns the registry API endpoint of a registry host.
+func registryBaseURL(registry string) string {
+	if normalizeRegistry(registry) == defaultRegistry {
+		return "https://registry-1.docker.io"
+	}
+	return "https://" + registry
+}
+
+// checkRegistryManifest requests the image manifest, answering a Bearer challenge with a token
+// obtained with the credential, or a Basic challenge with the credential itself.
+func checkRegistryManifest(ctx context.Context, client *http.Client, baseURL string, ref imageRef, cred *registryCredential) *RegistryCheck {
+	reference := ref.Tag
+	if ref.Digest != "" {
+		reference = ref.Digest
+	}
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", baseURL, ref.Repository, reference)
+	head := func(authorization string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		return resp, nil
+	}
+
+	authorization := ""
+	if cred != nil && cred.token != "" {
+		authorization = "Bearer " + cred.token
+	}
+	resp, err := head(authorization)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && authorization == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if authorization, err = registryAuthorization(ctx, client, challenge, ref, cred); err == nil && authorization != "" {
+			resp, err = head(authorization)
+		}
+	}
+	switch {
+	case errors.Is(err, errRegistryTokenDenied):
+		return &RegistryCheck{Status: "unauthorized", Error: err.Error()}
+	case err != nil:
+		return &RegistryCheck{Status: "error", Error: err.Error()}
+	}
+
+	check := &RegistryCheck{HTTP: resp.StatusCode, Digest: resp.Header.Get("Docker-Content-Digest")}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		check.Status = "ok"
+	case http.StatusNotFound:
+		check.Status = "notFound"
+	case http.StatusUnauthorized, http.StatusForbidden:
+		check.Status = "unauthorized"
+	case http.StatusTooManyRequests:
+		check.Status = "rateLimited"
+	default:
+		check.Status = "error"
+		check.Error = resp.Status
+	}
+	return check
+}
+
+// registryAuthorization answers a WWW-Authenticate challenge with an Authorization header value.
+func registryAuthorization(ctx context.Context, client *http.Client, challenge string, ref imageRef, cred *registryCredential) (string, error) {
+	scheme, params := parseAuthChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if cred == nil || cred.Username == "" {
+			return "", nil
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(cred.Username+":"+cred.password)), nil
+	case "bearer":
+	default:
+		return "", nil
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme != "https" && realm.Scheme != "http" {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+	query := realm.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", "repository:"+ref.Repository+":pull")
+	realm.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if cred != nil && cred.Username != "" {
+		req.SetBasicAuth(cred.Username, cred.password)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", fmt.Errorf("%w (%s)", errRegistryTokenDenied, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseAuthChallenge splits a WWW-Authenticate header into its scheme and parameters.
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, ", "), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			params[strings.ToLower(key)] = value
+		}
+	}
+	return scheme, params
+}
+
+// registryCheckIssue explains a failed registry check.
+func registryCheckIssue(check *RegistryCheck) string {
+	switch check.Status {
+	case "notFound":
+		return "the registry has no manifest for this tag or digest"
+	case "unauthorized":
+		return "the registry rejected the credentials, or the repository needs credentials none of the pull secrets hold"
+	case "rateLimited":
+		return "the registry is rate limiting pulls"
+	case "error":
+		return "registry check failed: " + check.Error
+	}
+	return ""
+}
+
+func parseAndValidateCheckImagePullParams(args map[string]any) (*CheckImagePullInput, error) {
+	input := &CheckImagePullInput{Namespace: metav1.NamespaceDefault, Kind: "Deployment"}
+	if namespace, ok := args["namespace"].(string); ok && namespace != "" {
+		if err := validation.ValidateNamespace(namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = namespace
+	}
+	if kind, ok := args["kind"].(string); ok && kind != "" {
+		switch kind {
+		case "Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob", "Pod":
+			input.Kind = kind
+		default:
+			return nil, errors.New("kind must be one of Deployment, StatefulSet, DaemonSet, Job, CronJob or Pod")
+		}
+	}
+	input.Name, _ = args["name"].(string)
+	input.Image, _ = args["image"].(string)
+	switch {
+	case input.Name == "" && input.Image == "":
+		return nil, errors.New("either name or image must be provided")
+	case input.Name != "" && input.Image != "":
+		return nil, errors.New("name and image are mutually exclusive")
+	case input.Name != "":
+		if err := validation.ValidateResourceName(input.Name); err != nil {
+			return nil, fmt.Errorf("invalid name: %w", err)
+		}
+	}
+	input.ServiceAccount, _ = args["serviceAccount"].(string)
+	if input.ServiceAccount != "" {
+		if err := validation.ValidateResourceName(input.ServiceAccount); err != nil {
+			return nil, fmt.Errorf("invalid serviceAccount: %w", err)
+		}
+	}
+	secrets, err := stringSliceArg(args, "imagePullSecrets")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range secrets {
+		if err := validation.ValidateResourceName(name); err != nil {
+			return nil, fmt.Errorf("invalid imagePullSecret %q: %w", name, err)
+		}
+	}
+	input.ImagePullSecrets = secrets
+	input.CheckRegistry, _ = args["checkRegistry"].(bool)
+	return input, nil
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func dockerConfigSecret(name, config string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(config)},
+	}
+}
+
+func TestPullSecretCredentials(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:s3cret"))
+	secret := dockerConfigSecret("regcred", `{"auths":{
+		"https://index.docker.io/v1/":{"auth":"`+auth+`"},
+		"registry.example.com/team":{"username":"ci","password":"pw"},
+		"broken.example.com":{"auth":"bm90LWJhc2U2NA"}
+	}}`)
+
+	creds, err := pullSecretCredentials(secret)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "auth of broken.example.com is not base64 user:password")
+	require.Len(t, creds, 2)
+	assert.Equal(t, "https://index.docker.io/v1/", creds[0].Entry)
+	assert.Equal(t, "robot", creds[0].Username)
+	assert.Equal(t, "s3cret", creds[0].password)
+	assert.Equal(t, "basic", creds[0].Kind)
+	assert.Equal(t, "registry.example.com/team", creds[1].Entry)
+
+	_, err = pullSecretCredentials(&corev1.Secret{Type: corev1.SecretTypeOpaque})
+	assert.ErrorContains(t, err, "the kubelet ignores it")
+}
+
+func TestRegistryEntryMatches(t *testing.T) {
+	nginx := parseImageRef("nginx:1.25")
+	team := parseImageRef("registry.example.com/team/app:1.0")
+	gcr := parseImageRef("eu.gcr.io/project/app")
+
+	assert.True(t, registryEntryMatches("https://index.docker.io/v1/", nginx))
+	assert.True(t, registryEntryMatches("docker.io", nginx))
+	assert.True(t, registryEntryMatches("registry.example.com/team", team))
+	assert.False(t, registryEntryMatches("registry.example.com/other", team))
+	assert.True(t, registryEntryMatches("*.gcr.io", gcr))
+	assert.False(t, registryEntryMatches("gcr.io", gcr))
+}
+
+func TestImagePullChecks(t *testing.T) {
+	spec := &corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "migrate", Image: "registry.example.com/team/app:1.0"}},
+		Containers: []corev1.Container{
+			{Name: "app", Image: "registry.example.com/team/app:1.0"},
+			{Name: "proxy", Image: "europe-docker.pkg.dev/project/repo/proxy"},
+		},
+	}
+	credentials := []registryCredential{
+		{Secret: "wide", Entry: "registry.example.com", Username: "ci", Kind: "basic"},
+		{Secret: "narrow", Entry: "registry.example.com/team", Username: "team-ci", Kind: "basic"},
+	}
+
+	checks := imagePullChecks(spec, credentials)
+
+	require.Len(t, checks, 2)
+	assert.Equal(t, []string{"migrate", "app"}, checks[0].Containers)
+	require.NotNil(t, checks[0].Credentials)
+	assert.Equal(t, "narrow", checks[0].Credentials.Secret)
+	assert.Empty(t, checks[0].Issues)
+	assert.Nil(t, checks[1].Credentials)
+	assert.Equal(t, []string{
+		"no imagePullSecret has credentials for europe-docker.pkg.dev; the image is pulled anonymously unless the node's credential provider supplies them",
+		"uses the latest tag; a changed image is only pulled when imagePullPolicy is Always",
+	}, checks[1].Issues)
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`)
+
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/nginx:pull",
+	}, params)
+}
+
+func TestCheckRegistryManifest(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			user, pass, _ := r.BasicAuth()
+			if user != "robot" || pass != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "repository:team/app:pull", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token":"registry-token"}`))
+		case "/v2/team/app/manifests/1.0", "/v2/team/app/manifests/missing":
+			if r.Header.Get("Authorization") != "Bearer registry-token" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Path == "/v2/team/app/manifests/missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", "sha256:abc")
+		}
+	}))
+	defer server.Close()
+	cred := &registryCredential{Username: "robot", password: "s3cret", Kind: "basic"}
+	ctx := context.Background()
+
+	check := checkRegistryManifest(ctx, server.Client(), server.URL, imageRef{Repository: "team/app", Tag: "1.0"}, cred)
+	assert.Equal(t, &RegistryCheck{Status: "ok", HTTP: http.StatusOK, Digest: "sha256:abc"}, check)
+
+	check = checkRegistryManifest(ctx, server.Client(), server.URL, imageRef{Repository: "team/app", Tag: "missing"}, cred)
+	assert.Equal(t, "notFound", check.Status)
+
+	check = checkRegistryManifest(ctx, server.Client(), server.URL, imageRef{Repository: "team/app", Tag: "1.0"}, &registryCredential{Username: "robot", password: "wrong"})
+	assert.Equal(t, "unauthorized", check.Status)
+	assert.Contains(t, check.Error, "refused the credentials")
+
+	check = checkRegistryManifest(ctx, server.Client(), server.URL, imageRef{Repository: "team/app", Tag: "1.0"}, nil)
+	assert.Equal(t, "unauthorized", check.Status)
+}
+
+func TestParseAndValidateCheckImagePullParams(t *testing.T) {
+	input, err := parseAndValidateCheckImagePullParams(map[string]any{"image": "nginx", "imagePullSecrets": []any{"regcred"}})
+	require.NoError(t, err)
+	assert.Equal(t, &CheckImagePullInput{Namespace: "default", Kind: "Deployment", Image: "nginx", ImagePullSecrets: []string{"regcred"}}, input)
+
+	_, err = parseAndValidateCheckImagePullParams(map[string]any{})
+	assert.Error(t, err)
+	_, err = parseAndValidateCheckImagePullParams(map[string]any{"name": "api", "image": "nginx"})
+	assert.Error(t, err)
+	_, err = parseAndValidateCheckImagePullParams(map[string]any{"name": "api", "kind": "ReplicaSet"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,256 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Environment variables used by this tool:
+// Optional:
+//   K8S_TRUSTED_REGISTRIES - Comma-separated registries or registry/path prefixes that images may be pulled from
+
+// defaultRegistry is the registry used by container runtimes for images without an explicit host.
+const defaultRegistry = "docker.io"
+
+// imageRef is a parsed container image reference.
+type imageRef struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ImageUsage describes one image reference in use and the workloads that run it.
+type ImageUsage struct {
+	Image      string   `json:"image"`
+	Registry   string   `json:"registry"`
+	Repository string   `json:"repository"`
+	Tag        string   `json:"tag,omitempty"`
+	Digest     string   `json:"digest,omitempty"`
+	Pinned     bool     `json:"pinnedByDigest"`
+	Trusted    *bool    `json:"trusted,omitempty"`
+	Pods       int      `json:"pods"`
+	Workloads  []string `json:"workloads"`
+}
+
+// ListImagesTool inventories the container images running in the cluster.
+type ListImagesTool struct {
+	client Client
+}
+
+// NewListImagesTool creates a new ListImagesTool with the provided Kubernetes client.
+func NewListImagesTool(client Client) *ListImagesTool {
+	return &ListImagesTool{client: client}
+}
+
+// Tool returns the MCP tool definition for list_images.
+func (l *ListImagesTool) Tool() mcp.Tool {
+	return mcp.NewTool("list_images",
+		mcp.WithDescription("Inventory the container images used by running pods, grouped by namespace and workload: tag vs digest pinning, 'latest' or untagged images, repositories running several versions at once, and images from registries outside the trusted allowlist"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to inventory (leave empty for all namespaces)"),
+		),
+		mcp.WithArray("trustedRegistries",
+			mcp.Description("Registries or registry/path prefixes considered trusted, e.g. ['gcr.io/my-project', 'registry.example.com']. Defaults to K8S_TRUSTED_REGISTRIES; when neither is set the trust check is skipped"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+	)
+}
+
+// Handler builds the image inventory.
+func (l *ListImagesTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	trusted, err := stringSliceArg(args, "trustedRegistries")
+	if err != nil {
+		return nil, err
+	}
+	if len(trusted) == 0 {
+		trusted = trustedRegistriesFromEnv()
+	}
+
+	clientset, err := l.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	replicaSets := replicaSetsByKey(ctx, clientset, namespace)
+
+	byImage := map[string]*ImageUsage{}
+	workloadSeen := map[string]map[string]bool{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		kind, name := workloadOwner(pod, replicaSets)
+		workload := pod.Namespace + "/" + kind + "/" + name
+
+		podImages := map[string]bool{}
+		containers := append([]corev1.Container{}, pod.Spec.InitContainers...)
+		for _, c := range append(containers, pod.Spec.Containers...) {
+			podImages[c.Image] = true
+		}
+		for image := range podImages {
+			usage, ok := byImage[image]
+			if !ok {
+				ref := parseImageRef(image)
+				usage = &ImageUsage{
+					Image:      image,
+					Registry:   ref.Registry,
+					Repository: ref.Repository,
+					Tag:        ref.Tag,
+					Digest:     ref.Digest,
+					Pinned:     ref.Digest != "",
+					Workloads:  []string{},
+				}
+				if len(trusted) > 0 {
+					ok := imageTrusted(ref, trusted)
+					usage.Trusted = &ok
+				}
+				byImage[image] = usage
+				workloadSeen[image] = map[string]bool{}
+			}
+			usage.Pods++
+			if !workloadSeen[image][workload] {
+				workloadSeen[image][workload] = true
+				usage.Workloads = append(usage.Workloads, workload)
+			}
+		}
+	}
+
+	images := make([]ImageUsage, 0, len(byImage))
+	for _, usage := range byImage {
+		sort.Strings(usage.Workloads)
+		images = append(images, *usage)
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].Image < images[j].Image })
+
+	var untrusted, unpinnedLatest []string
+	for _, img := range images {
+		if img.Trusted != nil && !*img.Trusted {
+			untrusted = append(untrusted, img.Image)
+		}
+		if img.Digest == "" && (img.Tag == "" || img.Tag == "latest") {
+			unpinnedLatest = append(unpinnedLatest, img.Image)
+		}
+	}
+
+	result := map[string]any{
+		"namespace":         namespace,
+		"images":            images,
+		"totalImages":       len(images),
+		"multipleVersions":  imageVersionsByRepository(images),
+		"latestOrUntagged":  unpinnedLatest,
+		"trustCheckEnabled": len(trusted) > 0,
+		"untrustedImages":   untrusted,
+		"trustedRegistries": trusted,
+		"pinnedByDigest":    countPinned(images),
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseImageRef splits an image reference into registry, repository, tag and digest using the
+// same defaulting rules as container runtimes: no host means docker.io, and single-segment
+// Docker Hub repositories live under "library/".
+func parseImageRef(image string) imageRef {
+	var ref imageRef
+	name := image
+	if at := strings.Index(name, "@"); at >= 0 {
+		ref.Digest = name[at+1:]
+		name = name[:at]
+	}
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		ref.Tag = name[colon+1:]
+		name = name[:colon]
+	}
+
+	first, rest, found := strings.Cut(name, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry = first
+		ref.Repository = rest
+	} else {
+		ref.Registry = defaultRegistry
+		ref.Repository = name
+	}
+	if ref.Registry == defaultRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref
+}
+
+// imageTrusted reports whether the image's registry, or registry/repository path, starts with a trusted entry.
+func imageTrusted(ref imageRef, trusted []string) bool {
+	full := ref.Registry + "/" + ref.Repository
+	for _, entry := range trusted {
+		entry = strings.TrimSuffix(strings.TrimSpace(entry), "/")
+		if entry == "" {
+			continue
+		}
+		if full == entry || strings.HasPrefix(full, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// imageVersionsByRepository returns repositories that are running more than one tag or digest.
+func imageVersionsByRepository(images []ImageUsage) map[string][]string {
+	versions := map[string][]string{}
+	for _, img := range images {
+		repo := img.Registry + "/" + img.Repository
+		version := img.Tag
+		if img.Digest != "" {
+			version = strings.TrimPrefix(version+"@"+img.Digest, "@")
+		}
+		versions[repo] = append(versions[repo], version)
+	}
+	for repo, v := range versions {
+		if len(v) < 2 {
+			delete(versions, repo)
+		}
+	}
+	return versions
+}
+
+func countPinned(images []ImageUsage) int {
+	n := 0
+	for _, img := range images {
+		if img.Pinned {
+			n++
+		}
+	}
+	return n
+}
+
+// trustedRegistriesFromEnv reads the comma-separated K8S_TRUSTED_REGISTRIES allowlist.
+func trustedRegistriesFromEnv() []string {
+	var registries []string
+	for _, r := range strings.Split(os.Getenv("K8S_TRUSTED_REGISTRIES"), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			registries = append(registries, r)
+		}
+	}
+	return registries
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImageRef(t *testing.T) {
+	testCases := []struct {
+		image    string
+		expected imageRef
+	}{
+		{image: "nginx", expected: imageRef{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}},
+		{image: "bitnami/redis:7.2", expected: imageRef{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.2"}},
+		{image: "localhost:5000/app:dev", expected: imageRef{Registry: "localhost:5000", Repository: "app", Tag: "dev"}},
+		{
+			image:    "gcr.io/my-project/api@sha256:abc123",
+			expected: imageRef{Registry: "gcr.io", Repository: "my-project/api", Digest: "sha256:abc123"},
+		},
+		{
+			image:    "registry.k8s.io/pause:3.9@sha256:def456",
+			expected: imageRef{Registry: "registry.k8s.io", Repository: "pause", Tag: "3.9", Digest: "sha256:def456"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseImageRef(tc.image))
+		})
+	}
+}
+
+func TestImageTrusted(t *testing.T) {
+	trusted := []string{"gcr.io/my-project", "registry.example.com/"}
+
+	assert.True(t, imageTrusted(parseImageRef("gcr.io/my-project/api:1.0"), trusted))
+	assert.True(t, imageTrusted(parseImageRef("registry.example.com/team/app:2"), trusted))
+	assert.False(t, imageTrusted(parseImageRef("gcr.io/my-project-evil/api:1.0"), trusted))
+	assert.False(t, imageTrusted(parseImageRef("nginx:1.25"), trusted))
+}
+
+func TestImageVersionsByRepository(t *testing.T) {
+	images := []ImageUsage{
+		{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"},
+		{Registry: "docker.io", Repository: "library/nginx", Tag: "1.27"},
+		{Registry: "gcr.io", Repository: "p/api", Digest: "sha256:abc"},
+	}
+
+	assert.Equal(t, map[string][]string{"docker.io/library/nginx": {"1.25", "1.27"}}, imageVersionsByRepository(images))
+}
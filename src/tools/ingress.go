@@ -0,0 +1,356 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ValidateIngressInput represents the input for validating Ingress backends.
+type ValidateIngressInput struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace"`
+}
+
+// IngressBackendCheck reports the Service → Endpoints → Pods chain behind one Ingress path.
+type IngressBackendCheck struct {
+	Host       string          `json:"host,omitempty"`
+	Path       string          `json:"path,omitempty"`
+	Service    string          `json:"service,omitempty"`
+	Port       string          `json:"port,omitempty"`
+	TargetPort string          `json:"targetPort,omitempty"`
+	Endpoints  *EndpointCounts `json:"endpoints,omitempty"`
+	Pods       int             `json:"pods"`
+	Problems   []string        `json:"problems"`
+}
+
+// IngressTLSCheck reports the state of the Secret behind one Ingress TLS entry.
+type IngressTLSCheck struct {
+	SecretName string   `json:"secretName,omitempty"`
+	Hosts      []string `json:"hosts,omitempty"`
+	NotAfter   string   `json:"notAfter,omitempty"`
+	Problems   []string `json:"problems"`
+}
+
+// IngressValidation is the validation result for a single Ingress.
+type IngressValidation struct {
+	Name         string                `json:"name"`
+	IngressClass string                `json:"ingressClass,omitempty"`
+	Backends     []IngressBackendCheck `json:"backends"`
+	TLS          []IngressTLSCheck     `json:"tls,omitempty"`
+	Problems     []string              `json:"problems"`
+	Healthy      bool                  `json:"healthy"`
+}
+
+// ValidateIngressTool walks every Ingress path down to the pods that should serve it.
+type ValidateIngressTool struct {
+	client Client
+}
+
+// NewValidateIngressTool creates a new ValidateIngressTool with the provided Kubernetes client.
+func NewValidateIngressTool(client Client) *ValidateIngressTool {
+	return &ValidateIngressTool{client: client}
+}
+
+// Tool returns the MCP tool definition for validate_ingress.
+func (v *ValidateIngressTool) Tool() mcp.Tool {
+	return mcp.NewTool("validate_ingress",
+		mcp.WithDescription("Validate the Ingress → Service → Endpoints → Pods chain for every rule and path of an Ingress (or all Ingresses in a namespace), reporting missing Services, unknown Service ports, Services without ready endpoints, targetPorts the pods do not expose, missing IngressClasses and missing or invalid TLS secrets. Use it to explain 404/502/503 responses at the edge."),
+		mcp.WithString("name",
+			mcp.Description("Name of the Ingress (leave empty to validate every Ingress in the namespace)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the Ingress (defaults to 'default' if not specified)"),
+		),
+	)
+}
+
+// Handler validates the requested Ingresses.
+func (v *ValidateIngressTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateValidateIngressParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate validate_ingress params: %w", err)
+	}
+
+	clientset, err := v.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	var ingresses []networkingv1.Ingress
+	if input.Name != "" {
+		ing, err := clientset.NetworkingV1().Ingresses(input.Namespace).Get(ctx, input.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ingress %s/%s: %w", input.Namespace, input.Name, err)
+		}
+		ingresses = append(ingresses, *ing)
+	} else {
+		list, err := clientset.NetworkingV1().Ingresses(input.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ingresses: %w", err)
+		}
+		ingresses = list.Items
+	}
+
+	checker := &ingressChecker{clientset: clientset, namespace: input.Namespace, services: map[string]*corev1.Service{}}
+	results := make([]IngressValidation, 0, len(ingresses))
+	broken := 0
+	for i := range ingresses {
+		checked := checker.validate(ctx, &ingresses[i])
+		if !checked.Healthy {
+			broken++
+		}
+		results = append(results, checked)
+	}
+
+	result := map[string]any{
+		"namespace": input.Namespace,
+		"ingresses": results,
+		"total":     len(results),
+		"broken":    broken,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// ingressChecker caches Service lookups while validating the Ingresses of one namespace.
+type ingressChecker struct {
+	clientset kubernetes.Interface
+	namespace string
+	services  map[string]*corev1.Service
+}
+
+// validate checks the class, every backend and every TLS entry of an Ingress.
+func (c *ingressChecker) validate(ctx context.Context, ing *networkingv1.Ingress) IngressValidation {
+	result := IngressValidation{Name: ing.Name, Backends: []IngressBackendCheck{}, Problems: []string{}}
+
+	if ing.Spec.IngressClassName != nil {
+		result.IngressClass = *ing.Spec.IngressClassName
+		if _, err := c.clientset.NetworkingV1().IngressClasses().Get(ctx, result.IngressClass, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			result.Problems = append(result.Problems, fmt.Sprintf("IngressClass %q does not exist; no controller will serve this Ingress", result.IngressClass))
+		}
+	} else if class := ing.Annotations["kubernetes.io/ingress.class"]; class != "" {
+		result.IngressClass = class
+	}
+
+	if ing.Spec.DefaultBackend != nil {
+		result.Backends = append(result.Backends, c.checkBackend(ctx, "", "(default backend)", ing.Spec.DefaultBackend))
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for i := range rule.HTTP.Paths {
+			path := &rule.HTTP.Paths[i]
+			result.Backends = append(result.Backends, c.checkBackend(ctx, rule.Host, path.Path, &path.Backend))
+		}
+	}
+	if len(result.Backends) == 0 {
+		result.Problems = append(result.Problems, "Ingress has no default backend and no HTTP paths")
+	}
+
+	for _, tls := range ing.Spec.TLS {
+		result.TLS = append(result.TLS, c.checkTLS(ctx, tls))
+	}
+
+	result.Healthy = len(result.Problems) == 0
+	for _, b := range result.Backends {
+		result.Healthy = result.Healthy && len(b.Problems) == 0
+	}
+	for _, t := range result.TLS {
+		result.Healthy = result.Healthy && len(t.Problems) == 0
+	}
+	return result
+}
+
+// checkBackend follows a backend to its Service, the Service port, its endpoints and the selected pods.
+func (c *ingressChecker) checkBackend(ctx context.Context, host, path string, backend *networkingv1.IngressBackend) IngressBackendCheck {
+	check := IngressBackendCheck{Host: host, Path: path, Problems: []string{}}
+	if backend.Resource != nil {
+		check.Service = backend.Resource.Kind + "/" + backend.Resource.Name
+		return check
+	}
+	if backend.Service == nil {
+		check.Problems = append(check.Problems, "backend has neither a service nor a resource")
+		return check
+	}
+	check.Service = backend.Service.Name
+	check.Port = ingressBackendPort(backend.Service.Port)
+
+	svc, err := c.service(ctx, backend.Service.Name)
+	if err != nil {
+		check.Problems = append(check.Problems, err.Error())
+		return check
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		check.TargetPort = svc.Spec.ExternalName
+		return check
+	}
+
+	sp, err := resolveIngressServicePort(svc, backend.Service.Port)
+	if err != nil {
+		check.Problems = append(check.Problems, err.Error())
+		return check
+	}
+	check.TargetPort = sp.TargetPort.String()
+	if sp.TargetPort.Type == intstr.Int && sp.TargetPort.IntVal == 0 {
+		check.TargetPort = fmt.Sprint(sp.Port)
+	}
+
+	counts, err := serviceEndpointCounts(ctx, c.clientset, svc)
+	if err != nil {
+		check.Problems = append(check.Problems, err.Error())
+	} else {
+		check.Endpoints = counts
+		if counts.Ready == 0 {
+			check.Problems = append(check.Problems, fmt.Sprintf("Service %s has no ready endpoints; the controller will answer 502/503", svc.Name))
+		}
+	}
+
+	if len(svc.Spec.Selector) == 0 {
+		return check
+	}
+	pods, err := c.clientset.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		check.Problems = append(check.Problems, fmt.Sprintf("failed to list pods: %v", err))
+		return check
+	}
+	check.Pods = len(pods.Items)
+	if check.Pods == 0 {
+		check.Problems = append(check.Problems, fmt.Sprintf("Service %s selector matches no pods", svc.Name))
+		return check
+	}
+	portCheck := checkServicePorts(&corev1.Service{Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{*sp}}}, pods.Items)[0]
+	if len(portCheck.Mismatched) > 0 {
+		check.Problems = append(check.Problems, fmt.Sprintf("targetPort %s is not exposed by %d of %d pod(s)", portCheck.TargetPort, len(portCheck.Mismatched), check.Pods))
+	}
+	return check
+}
+
+// service returns the named Service, caching both hits and misses.
+func (c *ingressChecker) service(ctx context.Context, name string) (*corev1.Service, error) {
+	if svc, ok := c.services[name]; ok {
+		if svc == nil {
+			return nil, fmt.Errorf("service %s does not exist", name)
+		}
+		return svc, nil
+	}
+	svc, err := c.clientset.CoreV1().Services(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		c.services[name] = nil
+		return nil, fmt.Errorf("service %s does not exist", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s: %w", name, err)
+	}
+	c.services[name] = svc
+	return svc, nil
+}
+
+// checkTLS verifies that a TLS entry's Secret exists, holds a valid certificate and covers the listed hosts.
+func (c *ingressChecker) checkTLS(ctx context.Context, tls networkingv1.IngressTLS) IngressTLSCheck {
+	check := IngressTLSCheck{SecretName: tls.SecretName, Hosts: tls.Hosts, Problems: []string{}}
+	if tls.SecretName == "" {
+		return check
+	}
+	secret, err := c.clientset.CoreV1().Secrets(c.namespace).Get(ctx, tls.SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		check.Problems = append(check.Problems, fmt.Sprintf("TLS secret %s does not exist; the controller will serve its default certificate", tls.SecretName))
+		return check
+	}
+	if err != nil {
+		check.Problems = append(check.Problems, fmt.Sprintf("failed to get secret %s: %v", tls.SecretName, err))
+		return check
+	}
+	return checkTLSSecret(check, secret, time.Now())
+}
+
+// checkTLSSecret validates the certificate in a TLS secret against the hosts that use it.
+func checkTLSSecret(check IngressTLSCheck, secret *corev1.Secret, now time.Time) IngressTLSCheck {
+	if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		check.Problems = append(check.Problems, fmt.Sprintf("secret %s is missing %s or %s", secret.Name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey))
+		return check
+	}
+	cert, err := parseCertificatePEM(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		check.Problems = append(check.Problems, fmt.Sprintf("secret %s: %v", secret.Name, err))
+		return check
+	}
+	check.NotAfter = cert.NotAfter.Format(time.RFC3339)
+	if now.After(cert.NotAfter) {
+		check.Problems = append(check.Problems, fmt.Sprintf("certificate in %s expired %s", secret.Name, check.NotAfter))
+	}
+	for _, host := range check.Hosts {
+		if err := cert.VerifyHostname(host); err != nil {
+			check.Problems = append(check.Problems, fmt.Sprintf("certificate in %s does not cover host %s", secret.Name, host))
+		}
+	}
+	return check
+}
+
+// resolveIngressServicePort finds the Service port an Ingress backend refers to by number or name.
+func resolveIngressServicePort(svc *corev1.Service, port networkingv1.ServiceBackendPort) (*corev1.ServicePort, error) {
+	for i := range svc.Spec.Ports {
+		sp := &svc.Spec.Ports[i]
+		if (port.Name != "" && sp.Name == port.Name) || (port.Name == "" && sp.Port == port.Number) {
+			return sp, nil
+		}
+	}
+	available := make([]string, 0, len(svc.Spec.Ports))
+	for _, sp := range svc.Spec.Ports {
+		if sp.Name != "" {
+			available = append(available, fmt.Sprintf("%s(%d)", sp.Name, sp.Port))
+		} else {
+			available = append(available, fmt.Sprint(sp.Port))
+		}
+	}
+	return nil, fmt.Errorf("service %s has no port %s (available: %v)", svc.Name, ingressBackendPort(port), available)
+}
+
+// ingressBackendPort renders a backend port as its name or number.
+func ingressBackendPort(port networkingv1.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return fmt.Sprint(port.Number)
+}
+
+// parseAndValidateValidateIngressParams validates and parses the input parameters.
+func parseAndValidateValidateIngressParams(args map[string]any) (*ValidateIngressInput, error) {
+	input := &ValidateIngressInput{}
+
+	if name, ok := args["name"].(string); ok && name != "" {
+		if err := validation.ValidateResourceName(name); err != nil {
+			return nil, fmt.Errorf("invalid ingress name: %w", err)
+		}
+		input.Name = name
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+	return input, nil
+}
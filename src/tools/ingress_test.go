@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolveIngressServicePort(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80},
+			{Port: 9090},
+		}},
+	}
+
+	sp, err := resolveIngressServicePort(svc, networkingv1.ServiceBackendPort{Name: "http"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(80), sp.Port)
+	}
+	sp, err = resolveIngressServicePort(svc, networkingv1.ServiceBackendPort{Number: 9090})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(9090), sp.Port)
+	}
+	_, err = resolveIngressServicePort(svc, networkingv1.ServiceBackendPort{Number: 8080})
+	assert.EqualError(t, err, "service web has no port 8080 (available: [http(80) 9090])")
+}
+
+func TestCheckTLSSecret(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	notAfter := time.Now().Add(24 * time.Hour)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"*.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-tls"},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	}
+
+	check := checkTLSSecret(IngressTLSCheck{SecretName: "web-tls", Hosts: []string{"app.example.com"}, Problems: []string{}}, secret, time.Now())
+	assert.Empty(t, check.Problems)
+
+	check = checkTLSSecret(IngressTLSCheck{SecretName: "web-tls", Hosts: []string{"example.org"}, Problems: []string{}}, secret, notAfter.Add(time.Hour))
+	assert.Len(t, check.Problems, 2)
+
+	delete(secret.Data, corev1.TLSPrivateKeyKey)
+	check = checkTLSSecret(IngressTLSCheck{SecretName: "web-tls", Problems: []string{}}, secret, time.Now())
+	assert.Equal(t, []string{"secret web-tls is missing tls.crt or tls.key"}, check.Problems)
+}
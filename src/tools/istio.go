@@ -0,0 +1,498 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Istio networking kinds; the served version (v1, v1beta1 or v1alpha3) is resolved through the RESTMapper.
+var (
+	istioVirtualServiceGK  = schema.GroupKind{Group: "networking.istio.io", Kind: "VirtualService"}
+	istioDestinationRuleGK = schema.GroupKind{Group: "networking.istio.io", Kind: "DestinationRule"}
+	istioGatewayGK         = schema.GroupKind{Group: "networking.istio.io", Kind: "Gateway"}
+)
+
+// istioMeshGateway is the reserved gateway name for sidecars in the mesh.
+const istioMeshGateway = "mesh"
+
+// IstioIssue is a problem found in the Istio traffic configuration, named after the matching istioctl analyzer.
+type IstioIssue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Resource string `json:"resource"`
+	Message  string `json:"message"`
+}
+
+// istioDestination is a route destination of a VirtualService.
+type istioDestination struct {
+	Host   string `json:"host"`
+	Subset string `json:"subset,omitempty"`
+	Port   int64  `json:"port,omitempty"`
+}
+
+// istioConfig is the Istio configuration relevant to one namespace.
+type istioConfig struct {
+	namespace        string
+	virtualServices  []unstructured.Unstructured
+	destinationRules []unstructured.Unstructured
+	gateways         []unstructured.Unstructured
+	// services holds the Services referenced by destinations, keyed by namespace/name; nil means not found.
+	services map[string]*corev1.Service
+}
+
+// istioHostFQDN expands a short host name relative to the namespace of the resource that uses it.
+func istioHostFQDN(host, namespace string) string {
+	if host == "" || strings.Contains(host, ".") || strings.Contains(host, "*") {
+		return host
+	}
+	return fmt.Sprintf("%s.%s.svc.cluster.local", host, namespace)
+}
+
+// istioHostService returns the namespace/name key of the Service a cluster-local host refers to.
+func istioHostService(fqdn string) (string, bool) {
+	name, ok := strings.CutSuffix(fqdn, ".svc.cluster.local")
+	if !ok {
+		return "", false
+	}
+	parts := strings.Split(name, ".")
+	if len(parts) != 2 || strings.Contains(name, "*") {
+		return "", false
+	}
+	return parts[1] + "/" + parts[0], true
+}
+
+// istioGatewayRef qualifies a gateway reference of a VirtualService with its namespace.
+func istioGatewayRef(ref, namespace string) string {
+	if ref == istioMeshGateway || strings.Contains(ref, "/") {
+		return ref
+	}
+	return namespace + "/" + ref
+}
+
+// istioVirtualServiceGateways returns the qualified gateways a VirtualService binds to, "mesh" by default.
+func istioVirtualServiceGateways(vs *unstructured.Unstructured) []string {
+	gateways, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "gateways")
+	if len(gateways) == 0 {
+		return []string{istioMeshGateway}
+	}
+	refs := make([]string, 0, len(gateways))
+	for _, gw := range gateways {
+		refs = append(refs, istioGatewayRef(gw, vs.GetNamespace()))
+	}
+	return refs
+}
+
+// istioVirtualServiceDestinations returns the http, tcp and tls route destinations with fully qualified hosts.
+func istioVirtualServiceDestinations(vs *unstructured.Unstructured) []istioDestination {
+	var destinations []istioDestination
+	for _, section := range []string{"http", "tcp", "tls"} {
+		routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", section)
+		for _, r := range routes {
+			route, ok := r.(map[string]any)
+			if !ok {
+				continue
+			}
+			targets, _, _ := unstructured.NestedSlice(route, "route")
+			for _, t := range targets {
+				target, ok := t.(map[string]any)
+				if !ok {
+					continue
+				}
+				host, _, _ := unstructured.NestedString(target, "destination", "host")
+				subset, _, _ := unstructured.NestedString(target, "destination", "subset")
+				port, _, _ := unstructured.NestedInt64(target, "destination", "port", "number")
+				destinations = append(destinations, istioDestination{Host: istioHostFQDN(host, vs.GetNamespace()), Subset: subset, Port: port})
+			}
+		}
+	}
+	return destinations
+}
+
+// istioResource renders a resource reference as Kind/namespace/name.
+func istioResource(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// analyze runs the static checks over the configuration, similar to 'istioctl analyze'.
+func (c *istioConfig) analyze() []IstioIssue {
+	var issues []IstioIssue
+
+	subsets := map[string]map[string]bool{}
+	ruleOwners := map[string][]string{}
+	for i := range c.destinationRules {
+		dr := &c.destinationRules[i]
+		host, _, _ := unstructured.NestedString(dr.Object, "spec", "host")
+		host = istioHostFQDN(host, dr.GetNamespace())
+		if subsets[host] == nil {
+			subsets[host] = map[string]bool{}
+		}
+		list, _, _ := unstructured.NestedSlice(dr.Object, "spec", "subsets")
+		for _, s := range list {
+			if subset, ok := s.(map[string]any); ok {
+				if name, _ := subset["name"].(string); name != "" {
+					subsets[host][name] = true
+				}
+			}
+		}
+		ruleOwners[host] = append(ruleOwners[host], dr.GetNamespace()+"/"+dr.GetName())
+	}
+	for i := range c.destinationRules {
+		dr := &c.destinationRules[i]
+		if dr.GetNamespace() != c.namespace {
+			continue
+		}
+		host, _, _ := unstructured.NestedString(dr.Object, "spec", "host")
+		if owners := ruleOwners[istioHostFQDN(host, dr.GetNamespace())]; len(owners) > 1 && owners[0] == dr.GetNamespace()+"/"+dr.GetName() {
+			issues = append(issues, IstioIssue{
+				Code: "ConflictingDestinationRulesHost", Severity: "warning", Resource: istioResource(dr),
+				Message: fmt.Sprintf("host %s has multiple DestinationRules (%s); only one is applied to a given sidecar", host, strings.Join(owners, ", ")),
+			})
+		}
+	}
+
+	gateways := map[string]bool{}
+	for i := range c.gateways {
+		gateways[c.gateways[i].GetNamespace()+"/"+c.gateways[i].GetName()] = true
+	}
+
+	hostOwners := map[string][]string{}
+	for i := range c.virtualServices {
+		vs := &c.virtualServices[i]
+		resource := istioResource(vs)
+
+		vsGateways := istioVirtualServiceGateways(vs)
+		hosts, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "hosts")
+		for _, gw := range vsGateways {
+			if gw != istioMeshGateway && !gateways[gw] {
+				issues = append(issues, IstioIssue{
+					Code: "ReferencedResourceNotFound", Severity: "error", Resource: resource,
+					Message: fmt.Sprintf("referenced gateway %s not found", gw),
+				})
+			}
+			for _, host := range hosts {
+				key := gw + " " + istioHostFQDN(host, vs.GetNamespace())
+				hostOwners[key] = append(hostOwners[key], vs.GetName())
+			}
+		}
+
+		for _, dest := range istioVirtualServiceDestinations(vs) {
+			if dest.Subset != "" && !subsets[dest.Host][dest.Subset] {
+				issues = append(issues, IstioIssue{
+					Code: "ReferencedResourceNotFound", Severity: "error", Resource: resource,
+					Message: fmt.Sprintf("subset %q of host %s is not defined by any DestinationRule", dest.Subset, dest.Host),
+				})
+			}
+			key, ok := istioHostService(dest.Host)
+			if !ok {
+				continue
+			}
+			svc, checked := c.services[key]
+			if !checked {
+				continue
+			}
+			if svc == nil {
+				issues = append(issues, IstioIssue{
+					Code: "ReferencedResourceNotFound", Severity: "error", Resource: resource,
+					Message: fmt.Sprintf("destination host %s has no Service %s", dest.Host, key),
+				})
+				continue
+			}
+			if dest.Port == 0 {
+				if len(svc.Spec.Ports) > 1 {
+					issues = append(issues, IstioIssue{
+						Code: "VirtualServiceDestinationPortSelectorRequired", Severity: "error", Resource: resource,
+						Message: fmt.Sprintf("destination host %s exposes %d ports; a destination port must be set", dest.Host, len(svc.Spec.Ports)),
+					})
+				}
+				continue
+			}
+			found := false
+			for _, p := range svc.Spec.Ports {
+				if int64(p.Port) == dest.Port {
+					found = true
+					break
+				}
+			}
+			if !found {
+				issues = append(issues, IstioIssue{
+					Code: "ReferencedResourceNotFound", Severity: "error", Resource: resource,
+					Message: fmt.Sprintf("destination port %d is not a port of Service %s", dest.Port, key),
+				})
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(hostOwners))
+	for key := range hostOwners {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		owners := hostOwners[key]
+		if len(owners) < 2 {
+			continue
+		}
+		gw, host, _ := strings.Cut(key, " ")
+		issue := IstioIssue{
+			Code: "ConflictingMeshGatewayVirtualServiceHosts", Severity: "error", Resource: fmt.Sprintf("VirtualService/%s/%s", c.namespace, owners[0]),
+			Message: fmt.Sprintf("VirtualServices %s all define host %s for sidecars; only one of them takes effect", strings.Join(owners, ", "), host),
+		}
+		if gw != istioMeshGateway {
+			issue.Code, issue.Severity = "ConflictingGatewayVirtualServiceHosts", "warning"
+			issue.Message = fmt.Sprintf("VirtualServices %s all define host %s on gateway %s; their routes are merged in an undefined order", strings.Join(owners, ", "), host, gw)
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// istioNamespaceInjection describes how sidecar injection is configured for a namespace.
+func istioNamespaceInjection(ns *corev1.Namespace) string {
+	if ns.Labels["istio-injection"] == "disabled" {
+		return "disabled"
+	}
+	if ns.Labels["istio-injection"] == "enabled" {
+		return "enabled"
+	}
+	if rev, ok := ns.Labels["istio.io/rev"]; ok {
+		return "revision " + rev
+	}
+	return "not enabled"
+}
+
+// podHasIstioProxy reports whether the pod runs the istio-proxy sidecar, as a container or native sidecar.
+func podHasIstioProxy(pod *corev1.Pod) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == "istio-proxy" {
+			return true
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == "istio-proxy" {
+			return true
+		}
+	}
+	return false
+}
+
+// IstioAnalyzeTool summarizes Istio traffic configuration and detects conflicts in it.
+type IstioAnalyzeTool struct {
+	client Client
+}
+
+// NewIstioAnalyzeTool creates a new IstioAnalyzeTool with the provided Kubernetes client.
+func NewIstioAnalyzeTool(client Client) *IstioAnalyzeTool {
+	return &IstioAnalyzeTool{client: client}
+}
+
+// Tool returns the MCP tool definition for istio_analyze.
+func (i *IstioAnalyzeTool) Tool() mcp.Tool {
+	return mcp.NewTool("istio_analyze",
+		mcp.WithDescription("Summarize Istio VirtualServices, DestinationRules, Gateways and sidecar injection for a namespace and detect conflicts similar to 'istioctl analyze': duplicate VirtualService hosts on the same gateway, subsets missing from DestinationRules, destination hosts or ports that do not match a Service, gateways that do not exist or select no pods, and pods missing the istio-proxy sidecar"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to analyze (defaults to 'default' if not specified)"),
+		),
+	)
+}
+
+// Handler loads the Istio configuration, runs the checks and reports the result.
+func (i *IstioAnalyzeTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace, _ := req.Params.Arguments["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	clientset, err := i.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	list := func(gk schema.GroupKind, ns string) ([]unstructured.Unstructured, error) {
+		ri, err := groupKindResourceInterface(i.client, gk, ns)
+		if err != nil {
+			return nil, fmt.Errorf("%w (is Istio installed?)", err)
+		}
+		items, err := ri.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gk.Kind, err)
+		}
+		return items.Items, nil
+	}
+	config := &istioConfig{namespace: namespace, services: map[string]*corev1.Service{}}
+	if config.virtualServices, err = list(istioVirtualServiceGK, namespace); err != nil {
+		return nil, err
+	}
+	// DestinationRules and Gateways apply across namespaces, so all of them are considered.
+	if config.destinationRules, err = list(istioDestinationRuleGK, ""); err != nil {
+		return nil, err
+	}
+	if config.gateways, err = list(istioGatewayGK, ""); err != nil {
+		return nil, err
+	}
+
+	for idx := range config.virtualServices {
+		for _, dest := range istioVirtualServiceDestinations(&config.virtualServices[idx]) {
+			key, ok := istioHostService(dest.Host)
+			if !ok {
+				continue
+			}
+			if _, done := config.services[key]; done {
+				continue
+			}
+			ns, name, _ := strings.Cut(key, "/")
+			svc, err := clientset.CoreV1().Services(ns).Get(ctx, name, metav1.GetOptions{})
+			switch {
+			case apierrors.IsNotFound(err):
+				config.services[key] = nil
+			case err == nil:
+				config.services[key] = svc
+			}
+		}
+	}
+
+	issues := config.analyze()
+
+	// Gateways used by this namespace must select a running gateway workload.
+	gatewaySummaries := []map[string]any{}
+	used := map[string]bool{}
+	for idx := range config.virtualServices {
+		for _, gw := range istioVirtualServiceGateways(&config.virtualServices[idx]) {
+			used[gw] = true
+		}
+	}
+	for idx := range config.gateways {
+		gw := &config.gateways[idx]
+		ref := gw.GetNamespace() + "/" + gw.GetName()
+		if gw.GetNamespace() != namespace && !used[ref] {
+			continue
+		}
+		selector, _, _ := unstructured.NestedStringMap(gw.Object, "spec", "selector")
+		servers, _, _ := unstructured.NestedSlice(gw.Object, "spec", "servers")
+		serverSummaries := []string{}
+		for _, s := range servers {
+			server, ok := s.(map[string]any)
+			if !ok {
+				continue
+			}
+			protocol, _, _ := unstructured.NestedString(server, "port", "protocol")
+			number, _, _ := unstructured.NestedInt64(server, "port", "number")
+			hosts, _, _ := unstructured.NestedStringSlice(server, "hosts")
+			serverSummaries = append(serverSummaries, fmt.Sprintf("%s %d %s", protocol, number, strings.Join(hosts, ",")))
+		}
+		summary := map[string]any{"gateway": ref, "selector": selector, "servers": serverSummaries}
+		if len(selector) > 0 {
+			pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(selector).String()})
+			if err == nil {
+				summary["workloadPods"] = len(pods.Items)
+				if len(pods.Items) == 0 {
+					issues = append(issues, IstioIssue{
+						Code: "ReferencedResourceNotFound", Severity: "error", Resource: istioResource(gw),
+						Message: fmt.Sprintf("selector %s matches no gateway pods", labels.SelectorFromSet(selector)),
+					})
+				}
+			}
+		}
+		gatewaySummaries = append(gatewaySummaries, summary)
+	}
+
+	// Sidecar injection status of the namespace and its pods.
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	injection := istioNamespaceInjection(ns)
+	injected := injection == "enabled" || strings.HasPrefix(injection, "revision ")
+	withProxy, withoutProxy := 0, []string{}
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if podHasIstioProxy(pod) {
+			withProxy++
+			continue
+		}
+		withoutProxy = append(withoutProxy, pod.Name)
+		if injected && pod.Labels["sidecar.istio.io/inject"] != "false" && pod.Annotations["sidecar.istio.io/inject"] != "false" {
+			issues = append(issues, IstioIssue{
+				Code: "PodMissingProxy", Severity: "warning", Resource: "Pod/" + namespace + "/" + pod.Name,
+				Message: "sidecar injection is enabled for the namespace but the pod has no istio-proxy; restart it to inject",
+			})
+		}
+	}
+
+	severityRank := map[string]int{"error": 0, "warning": 1, "info": 2}
+	sort.SliceStable(issues, func(a, b int) bool { return severityRank[issues[a].Severity] < severityRank[issues[b].Severity] })
+	if issues == nil {
+		issues = []IstioIssue{}
+	}
+
+	virtualServices := []map[string]any{}
+	for idx := range config.virtualServices {
+		vs := &config.virtualServices[idx]
+		hosts, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "hosts")
+		virtualServices = append(virtualServices, map[string]any{
+			"name":         vs.GetName(),
+			"hosts":        hosts,
+			"gateways":     istioVirtualServiceGateways(vs),
+			"destinations": istioVirtualServiceDestinations(vs),
+		})
+	}
+	destinationRules := []map[string]any{}
+	for idx := range config.destinationRules {
+		dr := &config.destinationRules[idx]
+		if dr.GetNamespace() != namespace {
+			continue
+		}
+		host, _, _ := unstructured.NestedString(dr.Object, "spec", "host")
+		subsetNames := []string{}
+		list, _, _ := unstructured.NestedSlice(dr.Object, "spec", "subsets")
+		for _, s := range list {
+			if subset, ok := s.(map[string]any); ok {
+				if name, _ := subset["name"].(string); name != "" {
+					subsetNames = append(subsetNames, name)
+				}
+			}
+		}
+		destinationRules = append(destinationRules, map[string]any{"name": dr.GetName(), "host": host, "subsets": subsetNames})
+	}
+
+	result := map[string]any{
+		"namespace":        namespace,
+		"virtualServices":  virtualServices,
+		"destinationRules": destinationRules,
+		"gateways":         gatewaySummaries,
+		"sidecarInjection": map[string]any{
+			"namespace":        injection,
+			"podsWithProxy":    withProxy,
+			"podsWithoutProxy": withoutProxy,
+		},
+		"issues": issues,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
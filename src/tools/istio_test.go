@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func istioObject(kind, namespace, name string, spec map[string]any) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"kind":     kind,
+		"metadata": map[string]any{"name": name, "namespace": namespace},
+		"spec":     spec,
+	}}
+}
+
+func TestIstioHosts(t *testing.T) {
+	assert.Equal(t, "reviews.shop.svc.cluster.local", istioHostFQDN("reviews", "shop"))
+	assert.Equal(t, "reviews.other", istioHostFQDN("reviews.other", "shop"))
+	assert.Equal(t, "*", istioHostFQDN("*", "shop"))
+
+	key, ok := istioHostService("reviews.shop.svc.cluster.local")
+	assert.True(t, ok)
+	assert.Equal(t, "shop/reviews", key)
+	_, ok = istioHostService("api.example.com")
+	assert.False(t, ok)
+
+	assert.Equal(t, "shop/web", istioGatewayRef("web", "shop"))
+	assert.Equal(t, "istio-system/ingress", istioGatewayRef("istio-system/ingress", "shop"))
+	assert.Equal(t, "mesh", istioGatewayRef("mesh", "shop"))
+}
+
+func TestIstioConfigAnalyze(t *testing.T) {
+	route := func(host, subset string, port int64) map[string]any {
+		dest := map[string]any{"host": host}
+		if subset != "" {
+			dest["subset"] = subset
+		}
+		if port != 0 {
+			dest["port"] = map[string]any{"number": port}
+		}
+		return map[string]any{"route": []any{map[string]any{"destination": dest}}}
+	}
+	config := &istioConfig{
+		namespace: "shop",
+		virtualServices: []unstructured.Unstructured{
+			istioObject("VirtualService", "shop", "reviews", map[string]any{
+				"hosts": []any{"reviews"},
+				"http":  []any{route("reviews", "v2", 0), route("reviews", "v1", 0)},
+			}),
+			istioObject("VirtualService", "shop", "reviews-canary", map[string]any{
+				"hosts": []any{"reviews.shop.svc.cluster.local"},
+				"http":  []any{route("ratings", "", 8080)},
+			}),
+			istioObject("VirtualService", "shop", "public", map[string]any{
+				"hosts":    []any{"shop.example.com"},
+				"gateways": []any{"istio-system/ingress", "missing"},
+				"http":     []any{route("cart", "", 0)},
+			}),
+		},
+		destinationRules: []unstructured.Unstructured{
+			istioObject("DestinationRule", "shop", "reviews", map[string]any{
+				"host":    "reviews",
+				"subsets": []any{map[string]any{"name": "v1"}},
+			}),
+		},
+		gateways: []unstructured.Unstructured{istioObject("Gateway", "istio-system", "ingress", map[string]any{})},
+		services: map[string]*corev1.Service{
+			"shop/reviews": {Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 9080}}}},
+			"shop/ratings": {Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 9080}}}},
+			"shop/cart":    {Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}, {Port: 443}}}},
+		},
+	}
+
+	messages := map[string][]string{}
+	for _, issue := range config.analyze() {
+		messages[issue.Code] = append(messages[issue.Code], issue.Message)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"referenced gateway shop/missing not found",
+		`subset "v2" of host reviews.shop.svc.cluster.local is not defined by any DestinationRule`,
+		"destination port 8080 is not a port of Service shop/ratings",
+	}, messages["ReferencedResourceNotFound"])
+	assert.Equal(t, []string{"destination host cart.shop.svc.cluster.local exposes 2 ports; a destination port must be set"}, messages["VirtualServiceDestinationPortSelectorRequired"])
+	assert.Equal(t, []string{"VirtualServices reviews, reviews-canary all define host reviews.shop.svc.cluster.local for sidecars; only one of them takes effect"}, messages["ConflictingMeshGatewayVirtualServiceHosts"])
+}
+
+func TestIstioSidecarInjection(t *testing.T) {
+	ns := func(labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+	}
+	assert.Equal(t, "enabled", istioNamespaceInjection(ns(map[string]string{"istio-injection": "enabled"})))
+	assert.Equal(t, "revision 1-22", istioNamespaceInjection(ns(map[string]string{"istio.io/rev": "1-22"})))
+	assert.Equal(t, "disabled", istioNamespaceInjection(ns(map[string]string{"istio-injection": "disabled", "istio.io/rev": "1-22"})))
+	assert.Equal(t, "not enabled", istioNamespaceInjection(ns(nil)))
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+	assert.False(t, podHasIstioProxy(pod))
+	pod.Spec.InitContainers = []corev1.Container{{Name: "istio-proxy"}}
+	assert.True(t, podHasIstioProxy(pod))
+}
@@ -0,0 +1,379 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// generatedJobLabels are added by the Job controller and must not be copied to a new Job.
+var generatedJobLabels = []string{
+	"controller-uid",
+	"batch.kubernetes.io/controller-uid",
+	"job-name",
+	"batch.kubernetes.io/job-name",
+}
+
+// JobControlInput represents the input for managing Jobs.
+type JobControlInput struct {
+	Namespace string        `json:"namespace"`
+	Job       string        `json:"job,omitempty"`
+	Action    string        `json:"action"`
+	OlderThan time.Duration `json:"olderThan,omitempty"`
+	State     string        `json:"state,omitempty"`
+	DryRun    bool          `json:"dryRun,omitempty"`
+}
+
+// PodFailure describes why a pod belonging to a Job failed.
+type PodFailure struct {
+	Pod       string `json:"pod"`
+	Phase     string `json:"phase"`
+	Container string `json:"container,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+	ExitCode  int32  `json:"exitCode,omitempty"`
+	Restarts  int32  `json:"restarts,omitempty"`
+}
+
+// JobControlTool retries failed Jobs, cleans up finished Jobs and reports failure reasons.
+type JobControlTool struct {
+	client Client
+}
+
+// NewJobControlTool creates a new JobControlTool with the provided Kubernetes client.
+func NewJobControlTool(client Client) *JobControlTool {
+	return &JobControlTool{client: client}
+}
+
+// Tool returns the MCP tool definition for job_control.
+func (j *JobControlTool) Tool() mcp.Tool {
+	return mcp.NewTool("job_control",
+		mcp.WithDescription("Manage Kubernetes Jobs: 'retry' recreates a Job from its spec as a fresh run, 'cleanup' deletes finished Jobs older than a duration, 'failures' reports why a Job's pods failed"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the Job(s) (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("Action to perform: 'retry', 'cleanup' or 'failures'"),
+			mcp.Enum("retry", "cleanup", "failures"),
+		),
+		mcp.WithString("job",
+			mcp.Description("Name of the Job (required for 'retry' and 'failures')"),
+		),
+		mcp.WithString("olderThan",
+			mcp.Description("For 'cleanup': only delete Jobs that finished longer ago than this duration, e.g. 1h or 72h (default: 24h)"),
+		),
+		mcp.WithString("state",
+			mcp.Description("For 'cleanup': which finished Jobs to delete: 'completed', 'failed' or 'all' (default: all)"),
+			mcp.Enum("completed", "failed", "all"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("For 'retry' and 'cleanup': report what would happen without making changes (default: false)"),
+		),
+	)
+}
+
+// Handler performs the requested Job action.
+func (j *JobControlTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateJobControlParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate job params: %w", err)
+	}
+
+	clientset, err := j.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	var result map[string]any
+	switch input.Action {
+	case "retry":
+		result, err = j.retryJob(ctx, clientset, input)
+	case "cleanup":
+		result, err = j.cleanupJobs(ctx, clientset, input)
+	case "failures":
+		result, err = j.jobFailures(ctx, clientset, input)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// retryJob creates a new Job from the spec of an existing one.
+func (j *JobControlTool) retryJob(ctx context.Context, clientset kubernetes.Interface, input *JobControlInput) (map[string]any, error) {
+	job, err := clientset.BatchV1().Jobs(input.Namespace).Get(ctx, input.Job, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s/%s: %w", input.Namespace, input.Job, err)
+	}
+
+	retry := retryJobFromJob(job, time.Now())
+	result := map[string]any{
+		"job":         input.Job,
+		"namespace":   input.Namespace,
+		"previousRun": summarizeJobRun(job),
+		"newJob":      retry.Name,
+		"dryRun":      input.DryRun,
+	}
+	if input.DryRun {
+		result["status"] = "Dry run: job not created"
+		return result, nil
+	}
+
+	if _, err := clientset.BatchV1().Jobs(input.Namespace).Create(ctx, retry, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create retry job: %w", err)
+	}
+	result["status"] = "Job recreated"
+	return result, nil
+}
+
+// cleanupJobs deletes finished Jobs that match the requested state and age.
+func (j *JobControlTool) cleanupJobs(ctx context.Context, clientset kubernetes.Interface, input *JobControlInput) (map[string]any, error) {
+	jobs, err := clientset.BatchV1().Jobs(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	cutoff := time.Now().Add(-input.OlderThan)
+	var deleted, failed []map[string]any
+	propagation := metav1.DeletePropagationBackground
+	for _, job := range jobs.Items {
+		state, finishedAt := jobFinishState(&job)
+		if state == "" || finishedAt.After(cutoff) {
+			continue
+		}
+		if input.State != "all" && input.State != state {
+			continue
+		}
+
+		entry := map[string]any{
+			"name":       job.Name,
+			"state":      state,
+			"finishedAt": finishedAt.Format(time.RFC3339),
+		}
+		if !input.DryRun {
+			err := clientset.BatchV1().Jobs(input.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+			if err != nil {
+				entry["error"] = err.Error()
+				failed = append(failed, entry)
+				continue
+			}
+		}
+		deleted = append(deleted, entry)
+	}
+
+	status := fmt.Sprintf("Deleted %d jobs", len(deleted))
+	if input.DryRun {
+		status = fmt.Sprintf("Dry run: %d jobs would be deleted", len(deleted))
+	}
+	return map[string]any{
+		"namespace": input.Namespace,
+		"olderThan": input.OlderThan.String(),
+		"state":     input.State,
+		"dryRun":    input.DryRun,
+		"deleted":   deleted,
+		"failed":    failed,
+		"status":    status,
+	}, nil
+}
+
+// jobFailures reports failure reasons taken from the container statuses of a Job's pods.
+func (j *JobControlTool) jobFailures(ctx context.Context, clientset kubernetes.Interface, input *JobControlInput) (map[string]any, error) {
+	job, err := clientset.BatchV1().Jobs(input.Namespace).Get(ctx, input.Job, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s/%s: %w", input.Namespace, input.Job, err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse job selector: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(input.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job pods: %w", err)
+	}
+
+	failures := make([]PodFailure, 0)
+	for _, pod := range pods.Items {
+		failures = append(failures, podFailureReasons(&pod)...)
+	}
+
+	return map[string]any{
+		"job":       input.Job,
+		"namespace": input.Namespace,
+		"run":       summarizeJobRun(job),
+		"failures":  failures,
+	}, nil
+}
+
+// retryJobFromJob copies a Job's spec into a new Job, dropping controller-generated selectors and labels.
+func retryJobFromJob(job *batchv1.Job, now time.Time) *batchv1.Job {
+	spec := job.Spec.DeepCopy()
+	spec.Selector = nil
+	spec.ManualSelector = nil
+	spec.Suspend = nil
+	for _, key := range generatedJobLabels {
+		delete(spec.Template.Labels, key)
+	}
+
+	labels := map[string]string{}
+	for k, v := range job.Labels {
+		labels[k] = v
+	}
+	for _, key := range generatedJobLabels {
+		delete(labels, key)
+	}
+
+	base := job.Name
+	suffix := fmt.Sprintf("-retry-%d", now.Unix())
+	if len(base)+len(suffix) > 63 {
+		base = strings.TrimRight(base[:63-len(suffix)], "-.")
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        base + suffix,
+			Namespace:   job.Namespace,
+			Labels:      labels,
+			Annotations: map[string]string{"kubernetes-mcp/retry-of": job.Name},
+		},
+		Spec: *spec,
+	}
+}
+
+// jobFinishState returns "completed" or "failed" with the time the Job finished, or "" if it is still running.
+func jobFinishState(job *batchv1.Job) (string, time.Time) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			if job.Status.CompletionTime != nil {
+				return "completed", job.Status.CompletionTime.Time
+			}
+			return "completed", cond.LastTransitionTime.Time
+		case batchv1.JobFailed:
+			return "failed", cond.LastTransitionTime.Time
+		}
+	}
+	return "", time.Time{}
+}
+
+// podFailureReasons extracts termination and waiting reasons from a pod's container statuses.
+func podFailureReasons(pod *corev1.Pod) []PodFailure {
+	var failures []PodFailure
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, cs := range statuses {
+		failure := PodFailure{
+			Pod:       pod.Name,
+			Phase:     string(pod.Status.Phase),
+			Container: cs.Name,
+			Restarts:  cs.RestartCount,
+		}
+		switch {
+		case cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0:
+			failure.Reason = cs.State.Terminated.Reason
+			failure.Message = cs.State.Terminated.Message
+			failure.ExitCode = cs.State.Terminated.ExitCode
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "ContainerCreating":
+			failure.Reason = cs.State.Waiting.Reason
+			failure.Message = cs.State.Waiting.Message
+		case cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.ExitCode != 0:
+			failure.Reason = cs.LastTerminationState.Terminated.Reason
+			failure.Message = cs.LastTerminationState.Terminated.Message
+			failure.ExitCode = cs.LastTerminationState.Terminated.ExitCode
+		default:
+			continue
+		}
+		failures = append(failures, failure)
+	}
+
+	if len(failures) == 0 && pod.Status.Phase == corev1.PodFailed {
+		failures = append(failures, PodFailure{
+			Pod:     pod.Name,
+			Phase:   string(pod.Status.Phase),
+			Reason:  pod.Status.Reason,
+			Message: pod.Status.Message,
+		})
+	}
+	return failures
+}
+
+// parseAndValidateJobControlParams validates and parses the input parameters.
+func parseAndValidateJobControlParams(args map[string]any) (*JobControlInput, error) {
+	input := &JobControlInput{
+		OlderThan: 24 * time.Hour,
+		State:     "all",
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	if action, ok := args["action"].(string); ok {
+		input.Action = strings.ToLower(action)
+	}
+	switch input.Action {
+	case "retry", "failures":
+		if name, ok := args["job"].(string); ok {
+			input.Job = name
+		}
+		if input.Job == "" {
+			return nil, fmt.Errorf("job must be provided for action %s", input.Action)
+		}
+		if err := validation.ValidateResourceName(input.Job); err != nil {
+			return nil, fmt.Errorf("invalid job name: %w", err)
+		}
+	case "cleanup":
+	default:
+		return nil, errors.New("action must be one of retry, cleanup or failures")
+	}
+
+	if olderThan, ok := args["olderThan"].(string); ok && olderThan != "" {
+		d, err := time.ParseDuration(olderThan)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid olderThan duration: %s", olderThan)
+		}
+		input.OlderThan = d
+	}
+
+	if state, ok := args["state"].(string); ok && state != "" {
+		input.State = strings.ToLower(state)
+	}
+	switch input.State {
+	case "completed", "failed", "all":
+	default:
+		return nil, errors.New("state must be one of completed, failed or all")
+	}
+
+	if dryRun, ok := args["dryRun"].(bool); ok {
+		input.DryRun = dryRun
+	}
+
+	return input, nil
+}
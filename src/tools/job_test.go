@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRetryJobFromJob(t *testing.T) {
+	manual := true
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":                                "backup",
+				"batch.kubernetes.io/controller-uid": "123",
+				"job-name":                           "backup",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			ManualSelector: &manual,
+			Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"controller-uid": "123"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "backup", "controller-uid": "123", "batch.kubernetes.io/job-name": "backup"},
+				},
+			},
+		},
+	}
+
+	retry := retryJobFromJob(job, time.Unix(1700000000, 0))
+
+	assert.Equal(t, "backup-retry-1700000000", retry.Name)
+	assert.Equal(t, map[string]string{"app": "backup"}, retry.Labels)
+	assert.Equal(t, map[string]string{"app": "backup"}, retry.Spec.Template.Labels)
+	assert.Nil(t, retry.Spec.Selector)
+	assert.Nil(t, retry.Spec.ManualSelector)
+	assert.Equal(t, "backup", retry.Annotations["kubernetes-mcp/retry-of"])
+	// the source job must be left untouched
+	assert.Contains(t, job.Spec.Template.Labels, "controller-uid")
+}
+
+func TestJobFinishState(t *testing.T) {
+	finished := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name     string
+		status   batchv1.JobStatus
+		expected string
+	}{
+		{
+			name:     "Running",
+			status:   batchv1.JobStatus{Active: 1},
+			expected: "",
+		},
+		{
+			name: "Completed",
+			status: batchv1.JobStatus{
+				CompletionTime: &finished,
+				Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			},
+			expected: "completed",
+		},
+		{
+			name: "Failed",
+			status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, LastTransitionTime: finished}},
+			},
+			expected: "failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, at := jobFinishState(&batchv1.Job{Status: tc.status})
+			assert.Equal(t, tc.expected, state)
+			if tc.expected != "" {
+				assert.Equal(t, finished.Time, at)
+			}
+		})
+	}
+}
+
+func TestPodFailureReasons(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-abc"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "main",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+				},
+				{
+					Name:  "sidecar",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}},
+				},
+			},
+		},
+	}
+
+	failures := podFailureReasons(pod)
+
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "main", failures[0].Container)
+	assert.Equal(t, "OOMKilled", failures[0].Reason)
+	assert.Equal(t, int32(137), failures[0].ExitCode)
+}
+
+func TestParseAndValidateJobControlParams(t *testing.T) {
+	input, err := parseAndValidateJobControlParams(map[string]any{"action": "cleanup"})
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, input.OlderThan)
+	assert.Equal(t, "all", input.State)
+	assert.Equal(t, "default", input.Namespace)
+
+	_, err = parseAndValidateJobControlParams(map[string]any{"action": "retry"})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateJobControlParams(map[string]any{"action": "cleanup", "olderThan": "7 days"})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateJobControlParams(map[string]any{"action": "delete"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,338 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// KEDA kinds; the served version is resolved through the RESTMapper.
+var (
+	kedaScaledObjectGK = schema.GroupKind{Group: "keda.sh", Kind: "ScaledObject"}
+	kedaScaledJobGK    = schema.GroupKind{Group: "keda.sh", Kind: "ScaledJob"}
+)
+
+// KEDA pause annotations.
+const (
+	kedaPausedAnnotation         = "autoscaling.keda.sh/paused"
+	kedaPausedReplicasAnnotation = "autoscaling.keda.sh/paused-replicas"
+)
+
+// KedaTrigger is one scaler of a ScaledObject or ScaledJob.
+type KedaTrigger struct {
+	Type              string            `json:"type"`
+	Name              string            `json:"name,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	AuthenticationRef string            `json:"authenticationRef,omitempty"`
+	Health            string            `json:"health,omitempty"`
+	Failures          int64             `json:"failures,omitempty"`
+}
+
+// KedaMetric is a metric of the HPA owned by a ScaledObject with its current and target value.
+type KedaMetric struct {
+	Name    string `json:"name"`
+	Current string `json:"current,omitempty"`
+	Target  string `json:"target,omitempty"`
+}
+
+// KedaScaler is the state of one ScaledObject or ScaledJob.
+type KedaScaler struct {
+	Kind            string         `json:"kind"`
+	Name            string         `json:"name"`
+	Namespace       string         `json:"namespace"`
+	Target          string         `json:"target,omitempty"`
+	MinReplicas     *int64         `json:"minReplicas,omitempty"`
+	MaxReplicas     *int64         `json:"maxReplicas,omitempty"`
+	Ready           string         `json:"ready"`
+	Active          string         `json:"active"`
+	Fallback        bool           `json:"fallback,omitempty"`
+	Paused          bool           `json:"paused,omitempty"`
+	PausedReplicas  string         `json:"pausedReplicas,omitempty"`
+	HPA             string         `json:"hpa,omitempty"`
+	CurrentReplicas *int32         `json:"currentReplicas,omitempty"`
+	DesiredReplicas *int32         `json:"desiredReplicas,omitempty"`
+	Triggers        []KedaTrigger  `json:"triggers"`
+	Metrics         []KedaMetric   `json:"metrics,omitempty"`
+	Issues          []string       `json:"issues,omitempty"`
+	Events          []EventSummary `json:"events,omitempty"`
+}
+
+// kedaScalerStatus reads the target, triggers, pause state and conditions of a ScaledObject or ScaledJob.
+func kedaScalerStatus(obj *unstructured.Unstructured) KedaScaler {
+	scaler := KedaScaler{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace(), Triggers: []KedaTrigger{}}
+
+	if obj.GetKind() == kedaScaledObjectGK.Kind {
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "kind")
+		if kind == "" {
+			kind = "Deployment"
+		}
+		name, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "name")
+		scaler.Target = kind + "/" + name
+		if v, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "minReplicaCount"); ok {
+			scaler.MinReplicas = &v
+		}
+		if v, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "maxReplicaCount"); ok {
+			scaler.MaxReplicas = &v
+		}
+		scaler.HPA, _, _ = unstructured.NestedString(obj.Object, "status", "hpaName")
+		if scaler.HPA == "" {
+			scaler.HPA = "keda-hpa-" + obj.GetName()
+		}
+	} else if v, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "maxReplicaCount"); ok {
+		scaler.MaxReplicas = &v
+	}
+
+	health, _, _ := unstructured.NestedMap(obj.Object, "status", "health")
+	triggers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "triggers")
+	for i, t := range triggers {
+		trigger, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		kt := KedaTrigger{}
+		kt.Type, _ = trigger["type"].(string)
+		kt.Name, _ = trigger["name"].(string)
+		kt.Metadata, _, _ = unstructured.NestedStringMap(trigger, "metadata")
+		kt.AuthenticationRef, _, _ = unstructured.NestedString(trigger, "authenticationRef", "name")
+		// KEDA keys health by the external metric name, which starts with s<index>-<type>.
+		prefix := fmt.Sprintf("s%d-%s", i, strings.ToLower(kt.Type))
+		for metric, h := range health {
+			m, ok := h.(map[string]any)
+			if !ok || !strings.HasPrefix(metric, prefix) {
+				continue
+			}
+			kt.Health, _, _ = unstructured.NestedString(m, "status")
+			kt.Failures, _, _ = unstructured.NestedInt64(m, "numberOfFailures")
+			if kt.Failures > 0 {
+				scaler.Issues = append(scaler.Issues, fmt.Sprintf("trigger %d (%s) failed %d times in a row", i, kt.Type, kt.Failures))
+			}
+		}
+		scaler.Triggers = append(scaler.Triggers, kt)
+	}
+
+	annotations := obj.GetAnnotations()
+	scaler.Paused = annotations[kedaPausedAnnotation] == "true"
+	if replicas, ok := annotations[kedaPausedReplicasAnnotation]; ok {
+		scaler.Paused = true
+		scaler.PausedReplicas = replicas
+	}
+	if status, _, _ := objectCondition(obj, "Paused"); status == "True" {
+		scaler.Paused = true
+	}
+
+	var reason, message string
+	scaler.Ready, reason, message = objectCondition(obj, "Ready")
+	if scaler.Ready == "False" {
+		scaler.Issues = append(scaler.Issues, fmt.Sprintf("not ready (%s): %s", reason, message))
+	}
+	scaler.Active, _, _ = objectCondition(obj, "Active")
+	if status, _, message := objectCondition(obj, "Fallback"); status == "True" {
+		scaler.Fallback = true
+		scaler.Issues = append(scaler.Issues, "using fallback replicas: "+message)
+	}
+	return scaler
+}
+
+// kedaHPAStatus adds the replica counts, metric values and failing conditions of the HPA owned by a ScaledObject.
+func kedaHPAStatus(scaler *KedaScaler, hpa *autoscalingv2.HorizontalPodAutoscaler) {
+	scaler.CurrentReplicas = &hpa.Status.CurrentReplicas
+	scaler.DesiredReplicas = &hpa.Status.DesiredReplicas
+
+	current := map[string]string{}
+	for _, m := range hpa.Status.CurrentMetrics {
+		if name, value := hpaMetricStatusValue(m); name != "" {
+			current[name] = value
+		}
+	}
+	for _, m := range hpa.Spec.Metrics {
+		name, target := hpaMetricSpecTarget(m)
+		if name == "" {
+			continue
+		}
+		scaler.Metrics = append(scaler.Metrics, KedaMetric{Name: name, Current: current[name], Target: target})
+	}
+
+	for _, cond := range hpa.Status.Conditions {
+		if cond.Status == "False" && (cond.Type == autoscalingv2.ScalingActive || cond.Type == autoscalingv2.AbleToScale) {
+			scaler.Issues = append(scaler.Issues, fmt.Sprintf("HPA %s is False (%s): %s", cond.Type, cond.Reason, cond.Message))
+		}
+	}
+}
+
+// hpaMetricSpecTarget returns the name and target of an HPA metric.
+func hpaMetricSpecTarget(m autoscalingv2.MetricSpec) (string, string) {
+	switch {
+	case m.External != nil:
+		return m.External.Metric.Name, metricTargetString(m.External.Target)
+	case m.Resource != nil:
+		return string(m.Resource.Name), metricTargetString(m.Resource.Target)
+	case m.ContainerResource != nil:
+		return string(m.ContainerResource.Name), metricTargetString(m.ContainerResource.Target)
+	}
+	return "", ""
+}
+
+// hpaMetricStatusValue returns the name and current value of an HPA metric.
+func hpaMetricStatusValue(m autoscalingv2.MetricStatus) (string, string) {
+	switch {
+	case m.External != nil:
+		return m.External.Metric.Name, metricValueString(m.External.Current)
+	case m.Resource != nil:
+		return string(m.Resource.Name), metricValueString(m.Resource.Current)
+	case m.ContainerResource != nil:
+		return string(m.ContainerResource.Name), metricValueString(m.ContainerResource.Current)
+	}
+	return "", ""
+}
+
+// metricTargetString renders an HPA metric target the way kubectl does.
+func metricTargetString(t autoscalingv2.MetricTarget) string {
+	switch {
+	case t.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *t.AverageUtilization)
+	case t.AverageValue != nil:
+		return t.AverageValue.String() + " (avg)"
+	case t.Value != nil:
+		return t.Value.String()
+	}
+	return ""
+}
+
+// metricValueString renders an HPA metric value the way kubectl does.
+func metricValueString(v autoscalingv2.MetricValueStatus) string {
+	switch {
+	case v.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *v.AverageUtilization)
+	case v.AverageValue != nil:
+		return v.AverageValue.String() + " (avg)"
+	case v.Value != nil:
+		return v.Value.String()
+	}
+	return ""
+}
+
+// KedaStatusTool summarizes KEDA ScaledObjects and ScaledJobs.
+type KedaStatusTool struct {
+	client Client
+}
+
+// NewKedaStatusTool creates a new KedaStatusTool with the provided Kubernetes client.
+func NewKedaStatusTool(client Client) *KedaStatusTool {
+	return &KedaStatusTool{client: client}
+}
+
+// Tool returns the MCP tool definition for keda_status.
+func (k *KedaStatusTool) Tool() mcp.Tool {
+	return mcp.NewTool("keda_status",
+		mcp.WithDescription("Summarize KEDA ScaledObjects and ScaledJobs to debug why a workload is not scaling: triggers and their health, current vs target metric values and replicas of the owned HPA, active/paused/fallback state, and recent scaling errors from Warning events"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the ScaledObjects and ScaledJobs (leave empty for all namespaces)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Only report the ScaledObject or ScaledJob with this name"),
+		),
+	)
+}
+
+// Handler lists the KEDA scalers and joins them with their HPAs and events.
+func (k *KedaStatusTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	name, _ := args["name"].(string)
+	if name != "" {
+		if err := validation.ValidateResourceName(name); err != nil {
+			return nil, fmt.Errorf("invalid name: %w", err)
+		}
+	}
+
+	var items []unstructured.Unstructured
+	for _, gk := range []schema.GroupKind{kedaScaledObjectGK, kedaScaledJobGK} {
+		ri, err := groupKindResourceInterface(k.client, gk, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("%w (is KEDA installed?)", err)
+		}
+		list, err := ri.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gk.Kind, err)
+		}
+		for _, item := range list.Items {
+			if name == "" || item.GetName() == name {
+				items = append(items, item)
+			}
+		}
+	}
+
+	clientset, err := k.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	hpas, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list HPAs: %w", err)
+	}
+	hpaByName := map[string]*autoscalingv2.HorizontalPodAutoscaler{}
+	for i := range hpas.Items {
+		hpaByName[hpas.Items[i].Namespace+"/"+hpas.Items[i].Name] = &hpas.Items[i]
+	}
+	warnings, err := listEvents(ctx, clientset, namespace, "", "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	scalers := make([]KedaScaler, 0, len(items))
+	counts := map[string]int{}
+	for i := range items {
+		scaler := kedaScalerStatus(&items[i])
+		if scaler.HPA != "" {
+			if hpa, ok := hpaByName[scaler.Namespace+"/"+scaler.HPA]; ok {
+				kedaHPAStatus(&scaler, hpa)
+			} else if !scaler.Paused {
+				scaler.Issues = append(scaler.Issues, fmt.Sprintf("HPA %s does not exist", scaler.HPA))
+			}
+		}
+		for _, ev := range warnings {
+			if ev.Namespace == scaler.Namespace && (ev.Object == scaler.Kind+"/"+scaler.Name || ev.Object == "HorizontalPodAutoscaler/"+scaler.HPA) {
+				scaler.Events = append(scaler.Events, ev)
+			}
+		}
+
+		switch {
+		case scaler.Paused:
+			counts["paused"]++
+		case len(scaler.Issues) > 0 || scaler.Ready == "False":
+			counts["failing"]++
+		case scaler.Active == "True":
+			counts["active"]++
+		default:
+			counts["idle"]++
+		}
+		scalers = append(scalers, scaler)
+	}
+	sort.SliceStable(scalers, func(i, j int) bool {
+		return len(scalers[i].Issues) > 0 && len(scalers[j].Issues) == 0
+	})
+
+	result := map[string]any{
+		"namespace": namespace,
+		"scalers":   scalers,
+		"counts":    counts,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestKedaScalerStatus(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"kind": "ScaledObject",
+		"metadata": map[string]any{
+			"name": "consumer", "namespace": "app",
+			"annotations": map[string]any{kedaPausedReplicasAnnotation: "0"},
+		},
+		"spec": map[string]any{
+			"scaleTargetRef":  map[string]any{"name": "consumer"},
+			"minReplicaCount": int64(0),
+			"maxReplicaCount": int64(10),
+			"triggers": []any{map[string]any{
+				"type":              "rabbitmq",
+				"metadata":          map[string]any{"queueName": "jobs", "value": "20"},
+				"authenticationRef": map[string]any{"name": "rabbitmq-auth"},
+			}},
+		},
+		"status": map[string]any{
+			"health": map[string]any{
+				"s0-rabbitmq-jobs": map[string]any{"status": "Failure", "numberOfFailures": int64(3)},
+			},
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "False", "reason": "ScaledObjectCheckFailed", "message": "connection refused"},
+				map[string]any{"type": "Active", "status": "False"},
+				map[string]any{"type": "Fallback", "status": "True", "message": "at least one trigger is falling back"},
+			},
+		},
+	}}
+
+	scaler := kedaScalerStatus(obj)
+	assert.Equal(t, "Deployment/consumer", scaler.Target)
+	assert.Equal(t, int64(0), *scaler.MinReplicas)
+	assert.Equal(t, int64(10), *scaler.MaxReplicas)
+	assert.Equal(t, "keda-hpa-consumer", scaler.HPA)
+	assert.True(t, scaler.Paused)
+	assert.Equal(t, "0", scaler.PausedReplicas)
+	assert.True(t, scaler.Fallback)
+	assert.Equal(t, "False", scaler.Ready)
+	assert.Equal(t, "False", scaler.Active)
+	assert.Equal(t, []KedaTrigger{{
+		Type:              "rabbitmq",
+		Metadata:          map[string]string{"queueName": "jobs", "value": "20"},
+		AuthenticationRef: "rabbitmq-auth",
+		Health:            "Failure",
+		Failures:          3,
+	}}, scaler.Triggers)
+	assert.Len(t, scaler.Issues, 3)
+}
+
+func TestKedaHPAStatus(t *testing.T) {
+	target := resource.MustParse("20")
+	current := resource.MustParse("135")
+	utilization := int32(80)
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{Metrics: []autoscalingv2.MetricSpec{
+			{External: &autoscalingv2.ExternalMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: "s0-rabbitmq-jobs"},
+				Target: autoscalingv2.MetricTarget{AverageValue: &target},
+			}},
+			{Resource: &autoscalingv2.ResourceMetricSource{
+				Name:   corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{AverageUtilization: &utilization},
+			}},
+		}},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 2,
+			DesiredReplicas: 7,
+			CurrentMetrics: []autoscalingv2.MetricStatus{{External: &autoscalingv2.ExternalMetricStatus{
+				Metric:  autoscalingv2.MetricIdentifier{Name: "s0-rabbitmq-jobs"},
+				Current: autoscalingv2.MetricValueStatus{AverageValue: &current},
+			}}},
+			Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+				{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionFalse, Reason: "FailedGetExternalMetric", Message: "unable to get external metric"},
+			},
+		},
+	}
+
+	scaler := KedaScaler{}
+	kedaHPAStatus(&scaler, hpa)
+	assert.Equal(t, int32(2), *scaler.CurrentReplicas)
+	assert.Equal(t, int32(7), *scaler.DesiredReplicas)
+	assert.Equal(t, []KedaMetric{
+		{Name: "s0-rabbitmq-jobs", Current: "135 (avg)", Target: "20 (avg)"},
+		{Name: "cpu", Target: "80%"},
+	}, scaler.Metrics)
+	assert.Equal(t, []string{"HPA ScalingActive is False (FailedGetExternalMetric): unable to get external metric"}, scaler.Issues)
+}
@@ -0,0 +1,173 @@
+// Environment variables used by kind lookups:
+// Optional:
+//   KIND_FUZZY_MATCH               - Set to "true" to use a single unambiguous close match for an unknown kind instead of failing
+
+package tools
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxKindSuggestions is the number of close matches listed for an unknown kind.
+const maxKindSuggestions = 5
+
+// kindSuggestion is a resource whose names are close to a requested kind.
+type kindSuggestion struct {
+	match    *gvrMatch
+	label    string
+	distance int
+}
+
+// fuzzyKindMatchAllowed reports whether an unknown kind may resolve to its only close match.
+func fuzzyKindMatchAllowed() bool {
+	allowed, _ := strconv.ParseBool(os.Getenv("KIND_FUZZY_MATCH"))
+	return allowed
+}
+
+// findGVRByQualifiedKind resolves kubectl-style "resource.group" names such as deployments.apps
+// or networkpolicies.projectcalico.org.
+func findGVRByQualifiedKind(apiResourceLists []*metav1.APIResourceList, target string) *gvrMatch {
+	name, group, ok := strings.Cut(target, ".")
+	if !ok {
+		return nil
+	}
+	for _, apiResList := range apiResourceLists {
+		if apiResList == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(apiResList.GroupVersion)
+		if err != nil || strings.ToLower(gv.Group) != group {
+			continue
+		}
+		for _, r := range apiResList.APIResources {
+			for _, candidate := range kindNames(&r) {
+				if candidate == name {
+					return newGvrMatch(&r, apiResList.GroupVersion, r.Namespaced)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// suggestKinds returns the resources whose kind, plural or short names are within a few edits of
+// the target, closest first. Subresources are skipped.
+func suggestKinds(apiResourceLists []*metav1.APIResourceList, target string) []kindSuggestion {
+	threshold := 1
+	if len(target) >= 5 {
+		threshold = 2
+	}
+	if len(target) >= 10 {
+		threshold = 3
+	}
+
+	best := map[string]kindSuggestion{}
+	for _, apiResList := range apiResourceLists {
+		if apiResList == nil {
+			continue
+		}
+		gv, _ := schema.ParseGroupVersion(apiResList.GroupVersion)
+		for _, r := range apiResList.APIResources {
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			distance := threshold + 1
+			for _, candidate := range kindNames(&r) {
+				if d := levenshtein(target, candidate); d < distance {
+					distance = d
+				}
+			}
+			if distance > threshold {
+				continue
+			}
+			label := r.Name
+			if gv.Group != "" {
+				label += "." + gv.Group
+			}
+			if prev, ok := best[label]; ok && prev.distance <= distance {
+				continue
+			}
+			best[label] = kindSuggestion{match: newGvrMatch(&r, apiResList.GroupVersion, r.Namespaced), label: label, distance: distance}
+		}
+	}
+
+	suggestions := make([]kindSuggestion, 0, len(best))
+	for _, s := range best {
+		suggestions = append(suggestions, s)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].label < suggestions[j].label
+	})
+	return suggestions
+}
+
+// unambiguousKind returns the closest suggestion when no other resource is as close. The same
+// resource served by several groups, like events and events.events.k8s.io, counts once.
+func unambiguousKind(suggestions []kindSuggestion) *gvrMatch {
+	if len(suggestions) == 0 {
+		return nil
+	}
+	first := suggestions[0]
+	for _, s := range suggestions[1:] {
+		if s.distance == first.distance && s.match.apiRes.Name != first.match.apiRes.Name {
+			return nil
+		}
+	}
+	return first.match
+}
+
+// unknownKindError lists the closest resources for a kind that was not found.
+func unknownKindError(kind string, suggestions []kindSuggestion) error {
+	if len(suggestions) == 0 {
+		return fmt.Errorf("cannot find resource '%s'; if its CRD was installed recently, call refresh_discovery", kind)
+	}
+	if len(suggestions) > maxKindSuggestions {
+		suggestions = suggestions[:maxKindSuggestions]
+	}
+	labels := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		labels[i] = fmt.Sprintf("%s (%s)", s.label, s.match.apiRes.Kind)
+	}
+	return fmt.Errorf("cannot find resource '%s'; did you mean %s?", kind, strings.Join(labels, ", "))
+}
+
+// kindNames returns the lowercase plural name, kind and short names of a resource.
+func kindNames(r *metav1.APIResource) []string {
+	names := []string{strings.ToLower(r.Name), strings.ToLower(r.Kind)}
+	for _, sn := range r.ShortNames {
+		names = append(names, strings.ToLower(sn))
+	}
+	return names
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
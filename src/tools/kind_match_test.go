@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+func loadAPIResources(t *testing.T) []*metav1.APIResourceList {
+	t.Helper()
+	data, err := os.ReadFile("testdata/apiresources.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read testdata: %v", err)
+	}
+	var apiResLists []*metav1.APIResourceList
+	if err := yaml.Unmarshal(data, &apiResLists); err != nil {
+		t.Fatalf("Failed to unmarshal Yaml: %v", err)
+	}
+	return apiResLists
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("pods", "pods"))
+	assert.Equal(t, 1, levenshtein("deploys", "deploy"))
+	assert.Equal(t, 1, levenshtein("ingresss", "ingresses"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 4, levenshtein("", "pods"))
+}
+
+func TestFindGVRByKindSuggestions(t *testing.T) {
+	apiResLists := loadAPIResources(t)
+
+	_, err := findGVRByKind(apiResLists, "deploys")
+	assert.ErrorContains(t, err, "cannot find resource 'deploys'; did you mean deployments.apps (Deployment)")
+
+	_, err = findGVRByKind(apiResLists, "unknownkind")
+	assert.EqualError(t, err, "cannot find resource 'unknownkind'; if its CRD was installed recently, call refresh_discovery")
+}
+
+func TestFindGVRByKindFuzzyMatch(t *testing.T) {
+	apiResLists := loadAPIResources(t)
+	t.Setenv("KIND_FUZZY_MATCH", "true")
+
+	tests := []struct {
+		kind     string
+		expected *schema.GroupVersionResource
+	}{
+		{"deploys", &schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+		{"ingresss", &schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}},
+		{"Pods", &schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+		{"deployments.apps", &schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			actual, err := findGVRByKind(apiResLists, tt.kind)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual.ToGroupVersionResource())
+		})
+	}
+
+	_, err := findGVRByKind(apiResLists, "unknownkind")
+	assert.Error(t, err)
+}
+
+func TestUnambiguousKind(t *testing.T) {
+	events := &gvrMatch{apiRes: &metav1.APIResource{Name: "events"}}
+	pods := &gvrMatch{apiRes: &metav1.APIResource{Name: "pods"}}
+
+	assert.Nil(t, unambiguousKind(nil))
+	assert.Equal(t, events, unambiguousKind([]kindSuggestion{{match: events, distance: 1}, {match: events, distance: 1}}))
+	assert.Equal(t, events, unambiguousKind([]kindSuggestion{{match: events, distance: 1}, {match: pods, distance: 2}}))
+	assert.Nil(t, unambiguousKind([]kindSuggestion{{match: events, distance: 1}, {match: pods, distance: 1}}))
+}
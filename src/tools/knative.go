@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// knativeServingGroup is the API group of Knative Serving.
+const knativeServingGroup = "serving.knative.dev"
+
+// Knative Serving kinds; the served version is resolved through the RESTMapper.
+var (
+	knativeServiceGK  = schema.GroupKind{Group: knativeServingGroup, Kind: "Service"}
+	knativeRevisionGK = schema.GroupKind{Group: knativeServingGroup, Kind: "Revision"}
+)
+
+// Labels Knative sets on Revisions.
+const (
+	knativeServiceLabel                 = "serving.knative.dev/service"
+	knativeConfigurationGenerationLabel = "serving.knative.dev/configurationGeneration"
+)
+
+// KnativeTrafficTarget is one entry of a Knative Service traffic split.
+type KnativeTrafficTarget struct {
+	RevisionName   string `json:"revisionName,omitempty"`
+	LatestRevision bool   `json:"latestRevision,omitempty"`
+	Percent        int64  `json:"percent"`
+	Tag            string `json:"tag,omitempty"`
+	URL            string `json:"url,omitempty"`
+}
+
+// knativeTraffic reads a traffic block (spec.traffic or status.traffic) of a Knative Service.
+func knativeTraffic(obj *unstructured.Unstructured, fields ...string) []KnativeTrafficTarget {
+	targets := []KnativeTrafficTarget{}
+	entries, _, _ := unstructured.NestedSlice(obj.Object, fields...)
+	for _, e := range entries {
+		entry, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		target := KnativeTrafficTarget{}
+		target.RevisionName, _ = entry["revisionName"].(string)
+		target.LatestRevision, _ = entry["latestRevision"].(bool)
+		target.Percent, _, _ = unstructured.NestedInt64(entry, "percent")
+		target.Tag, _ = entry["tag"].(string)
+		target.URL, _ = entry["url"].(string)
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// knativeAnnotation returns the first of the given revision template annotations that is set.
+func knativeAnnotation(annotations map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := annotations[key]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// knativeServiceSummary builds the list_resources summary of a Knative Service: readiness, URL, revisions,
+// the traffic actually served (status.traffic) and the autoscaling bounds of the revision template.
+func knativeServiceSummary(obj *unstructured.Unstructured) KnativeServiceSummary {
+	summary := KnativeServiceSummary{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+	summary.URL, _, _ = unstructured.NestedString(obj.Object, "status", "url")
+	var message string
+	summary.Ready, _, message = readyCondition(obj)
+	if summary.Ready != "True" {
+		summary.Message = message
+	}
+	summary.LatestReadyRevision, _, _ = unstructured.NestedString(obj.Object, "status", "latestReadyRevisionName")
+	summary.LatestCreatedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "latestCreatedRevisionName")
+	summary.Traffic = knativeTraffic(obj, "status", "traffic")
+
+	annotations, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "annotations")
+	summary.MinScale = knativeAnnotation(annotations, "autoscaling.knative.dev/min-scale", "autoscaling.knative.dev/minScale")
+	summary.MaxScale = knativeAnnotation(annotations, "autoscaling.knative.dev/max-scale", "autoscaling.knative.dev/maxScale")
+	summary.ScaleTarget = knativeAnnotation(annotations, "autoscaling.knative.dev/target")
+	return summary
+}
+
+// knativeRevisionGeneration returns the configuration generation a Revision was created from.
+func knativeRevisionGeneration(rev *unstructured.Unstructured) int64 {
+	generation, _ := strconv.ParseInt(rev.GetLabels()[knativeConfigurationGenerationLabel], 10, 64)
+	return generation
+}
+
+// knativePreviousRevision returns the newest Ready revision created before the current one, which is
+// what a rollback of the current revision goes back to.
+func knativePreviousRevision(revisions []unstructured.Unstructured, current string) (string, error) {
+	sorted := make([]*unstructured.Unstructured, 0, len(revisions))
+	for i := range revisions {
+		sorted = append(sorted, &revisions[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return knativeRevisionGeneration(sorted[i]) > knativeRevisionGeneration(sorted[j])
+	})
+
+	var currentGeneration int64 = -1
+	for _, rev := range sorted {
+		if rev.GetName() == current {
+			currentGeneration = knativeRevisionGeneration(rev)
+		}
+	}
+	if currentGeneration < 0 {
+		return "", fmt.Errorf("current revision %s not found", current)
+	}
+	for _, rev := range sorted {
+		if knativeRevisionGeneration(rev) >= currentGeneration {
+			continue
+		}
+		if status, _, _ := readyCondition(rev); status == "True" {
+			return rev.GetName(), nil
+		}
+	}
+	return "", fmt.Errorf("no ready revision older than %s", current)
+}
+
+// knativeServingRevision returns the revision currently receiving the most traffic.
+func knativeServingRevision(summary KnativeServiceSummary) string {
+	current, percent := summary.LatestReadyRevision, int64(-1)
+	for _, t := range summary.Traffic {
+		if t.RevisionName != "" && t.Percent > percent {
+			current, percent = t.RevisionName, t.Percent
+		}
+	}
+	return current
+}
+
+// knativeRollbackTraffic pins percent of the traffic to the revision and leaves the rest on the latest revision.
+func knativeRollbackTraffic(revision string, percent int64) []map[string]any {
+	traffic := []map[string]any{{"revisionName": revision, "percent": percent}}
+	if percent < 100 {
+		traffic = append(traffic, map[string]any{"latestRevision": true, "percent": 100 - percent})
+	}
+	return traffic
+}
+
+// KnativeRollbackInput represents the input for shifting Knative traffic to a previous revision.
+type KnativeRollbackInput struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Revision  string `json:"revision,omitempty"`
+	Percent   int64  `json:"percent"`
+}
+
+// KnativeRollbackTool shifts the traffic of a Knative Service to a previous revision.
+type KnativeRollbackTool struct {
+	client Client
+}
+
+// NewKnativeRollbackTool creates a new KnativeRollbackTool with the provided Kubernetes client.
+func NewKnativeRollbackTool(client Client) *KnativeRollbackTool {
+	return &KnativeRollbackTool{client: client}
+}
+
+// Tool returns the MCP tool definition for knative_rollback.
+func (k *KnativeRollbackTool) Tool() mcp.Tool {
+	return mcp.NewTool("knative_rollback",
+		mcp.WithDescription("Shift the traffic of a Knative Service to a previous Ready revision (like 'kn service update --traffic'). By default all traffic goes to the revision before the one currently serving; with percent < 100 the rest stays on the latest revision. Pinned traffic is not moved by later deployments until spec.traffic is changed again"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the Knative Service (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Knative Service"),
+		),
+		mcp.WithString("revision",
+			mcp.Description("Revision to send traffic to (default: the newest Ready revision older than the one currently serving)"),
+		),
+		mcp.WithNumber("percent",
+			mcp.Description("Percentage of traffic for the revision, 1-100 (default: 100)"),
+		),
+	)
+}
+
+// Handler validates the target revision and replaces spec.traffic.
+func (k *KnativeRollbackTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateKnativeRollbackParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate knative_rollback params: %w", err)
+	}
+
+	serviceRI, err := groupKindResourceInterface(k.client, knativeServiceGK, input.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("%w (is Knative Serving installed?)", err)
+	}
+	service, err := serviceRI.Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get knative service %s/%s: %w", input.Namespace, input.Name, err)
+	}
+	summary := knativeServiceSummary(service)
+
+	revisionRI, err := groupKindResourceInterface(k.client, knativeRevisionGK, input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := revisionRI.List(ctx, metav1.ListOptions{LabelSelector: knativeServiceLabel + "=" + input.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	current := knativeServingRevision(summary)
+	revision := input.Revision
+	if revision == "" {
+		if revision, err = knativePreviousRevision(revisions.Items, current); err != nil {
+			return nil, fmt.Errorf("cannot pick a revision to roll back to: %w", err)
+		}
+	} else {
+		var found *unstructured.Unstructured
+		for i := range revisions.Items {
+			if revisions.Items[i].GetName() == revision {
+				found = &revisions.Items[i]
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("revision %s does not belong to service %s/%s", revision, input.Namespace, input.Name)
+		}
+		if status, _, message := readyCondition(found); status != "True" {
+			return nil, fmt.Errorf("revision %s is not ready: %s", revision, message)
+		}
+	}
+
+	traffic := knativeRollbackTraffic(revision, input.Percent)
+	patch, err := json.Marshal(map[string]any{"spec": map[string]any{"traffic": traffic}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal patch: %w", err)
+	}
+	if _, err := serviceRI.Patch(ctx, input.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to patch traffic of %s/%s: %w", input.Namespace, input.Name, err)
+	}
+
+	result := map[string]any{
+		"status":          "Traffic shifted",
+		"service":         input.Name,
+		"namespace":       input.Namespace,
+		"fromRevision":    current,
+		"toRevision":      revision,
+		"previousTraffic": summary.Traffic,
+		"traffic":         traffic,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseAndValidateKnativeRollbackParams validates and parses the input parameters.
+func parseAndValidateKnativeRollbackParams(args map[string]any) (*KnativeRollbackInput, error) {
+	input := &KnativeRollbackInput{Percent: 100}
+
+	input.Namespace, _ = args["namespace"].(string)
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+	input.Name, _ = args["name"].(string)
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid service name: %w", err)
+	}
+	input.Revision, _ = args["revision"].(string)
+	if input.Revision != "" {
+		if err := validation.ValidateResourceName(input.Revision); err != nil {
+			return nil, fmt.Errorf("invalid revision name: %w", err)
+		}
+	}
+	if p, ok := args["percent"].(float64); ok {
+		if p < 1 || p > 100 || p != float64(int64(p)) {
+			return nil, errors.New("percent must be a whole number between 1 and 100")
+		}
+		input.Percent = int64(p)
+	}
+
+	return input, nil
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func knativeRevision(name, generation, ready string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"name":   name,
+			"labels": map[string]any{knativeServiceLabel: "hello", knativeConfigurationGenerationLabel: generation},
+		},
+		"status": map[string]any{"conditions": []any{map[string]any{"type": "Ready", "status": ready}}},
+	}}
+}
+
+func TestKnativeServiceSummary(t *testing.T) {
+	svc := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "hello", "namespace": "apps"},
+		"spec": map[string]any{"template": map[string]any{"metadata": map[string]any{"annotations": map[string]any{
+			"autoscaling.knative.dev/minScale":  "1",
+			"autoscaling.knative.dev/max-scale": "10",
+		}}}},
+		"status": map[string]any{
+			"url":                       "https://hello.apps.example.com",
+			"latestReadyRevisionName":   "hello-00003",
+			"latestCreatedRevisionName": "hello-00004",
+			"traffic": []any{
+				map[string]any{"revisionName": "hello-00003", "latestRevision": true, "percent": int64(80)},
+				map[string]any{"revisionName": "hello-00002", "percent": int64(20), "tag": "old", "url": "https://old-hello.apps.example.com"},
+			},
+			"conditions": []any{map[string]any{"type": "Ready", "status": "False", "message": "Revision hello-00004 failed"}},
+		},
+	}}
+
+	summary := knativeServiceSummary(svc)
+	assert.Equal(t, "https://hello.apps.example.com", summary.URL)
+	assert.Equal(t, "False", summary.Ready)
+	assert.Equal(t, "Revision hello-00004 failed", summary.Message)
+	assert.Equal(t, "hello-00003", summary.LatestReadyRevision)
+	assert.Equal(t, "1", summary.MinScale)
+	assert.Equal(t, "10", summary.MaxScale)
+	assert.Equal(t, []KnativeTrafficTarget{
+		{RevisionName: "hello-00003", LatestRevision: true, Percent: 80},
+		{RevisionName: "hello-00002", Percent: 20, Tag: "old", URL: "https://old-hello.apps.example.com"},
+	}, summary.Traffic)
+	assert.Equal(t, "hello-00003", knativeServingRevision(summary))
+}
+
+func TestKnativePreviousRevision(t *testing.T) {
+	revisions := []unstructured.Unstructured{
+		knativeRevision("hello-00001", "1", "True"),
+		knativeRevision("hello-00003", "3", "True"),
+		knativeRevision("hello-00002", "2", "False"),
+	}
+
+	previous, err := knativePreviousRevision(revisions, "hello-00003")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-00001", previous)
+
+	_, err = knativePreviousRevision(revisions, "hello-00001")
+	assert.Error(t, err)
+	_, err = knativePreviousRevision(revisions, "hello-00009")
+	assert.Error(t, err)
+}
+
+func TestKnativeRollbackTraffic(t *testing.T) {
+	assert.Equal(t, []map[string]any{{"revisionName": "hello-00001", "percent": int64(100)}}, knativeRollbackTraffic("hello-00001", 100))
+	assert.Equal(t, []map[string]any{
+		{"revisionName": "hello-00001", "percent": int64(30)},
+		{"latestRevision": true, "percent": int64(70)},
+	}, knativeRollbackTraffic("hello-00001", 30))
+
+	_, err := parseAndValidateKnativeRollbackParams(map[string]any{"name": "hello", "percent": float64(0)})
+	assert.Error(t, err)
+	input, err := parseAndValidateKnativeRollbackParams(map[string]any{"name": "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), input.Percent)
+	assert.Equal(t, "default", input.Namespace)
+}
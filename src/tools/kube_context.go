@@ -0,0 +1,108 @@
+// Environment variables used by this tool:
+// Optional:
+//   KUBE_CONTEXTS                  - Comma-separated kubeconfig contexts or glob patterns tool calls may switch to (default: none)
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	kubeclient "github.com/k4mrul/kubernetes-mcp/src/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// clientForContext creates a client for a kubeconfig context.
+var clientForContext = func(name string) (Client, error) {
+	return kubeclient.NewKubernetesClientForContext(name)
+}
+
+// authorizeKubeContext checks that a kubeconfig context is listed in KUBE_CONTEXTS and that the
+// client of the call, if any, may use it. Switching contexts is off unless KUBE_CONTEXTS is set.
+func authorizeKubeContext(ctx context.Context, name string) error {
+	allowed := false
+	for _, pattern := range strings.Split(os.Getenv("KUBE_CONTEXTS"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("context %q is not in KUBE_CONTEXTS", name)
+	}
+	return auth.AuthorizeContext(ctx, name)
+}
+
+type kubeContextKey struct{}
+
+// withKubeContext returns a context whose tool calls run against a kubeconfig context.
+func withKubeContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, kubeContextKey{}, name)
+}
+
+// kubeContextFrom returns the kubeconfig context a call runs against, or "" for the server's own.
+func kubeContextFrom(ctx context.Context) string {
+	name, _ := ctx.Value(kubeContextKey{}).(string)
+	return name
+}
+
+// contextTools runs tool calls against the kubeconfig context their session selected with
+// set_defaults. The tools are built again around a client for each context on its first use and
+// kept for the lifetime of the server.
+type contextTools struct {
+	build    func(Client) []Tools
+	mu       sync.Mutex
+	handlers map[string]map[string]server.ToolHandlerFunc
+}
+
+func newContextTools(build func(Client) []Tools) *contextTools {
+	return &contextTools{build: build, handlers: map[string]map[string]server.ToolHandlerFunc{}}
+}
+
+// wrap returns a handler that calls the tool of the call's kubeconfig context, or handler when the
+// call has none.
+func (c *contextTools) wrap(name string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		kubeContext := kubeContextFrom(ctx)
+		if kubeContext == "" {
+			return handler(ctx, req)
+		}
+		contextHandler, err := c.handler(kubeContext, name)
+		if err != nil {
+			return nil, err
+		}
+		return contextHandler(ctx, req)
+	}
+}
+
+// handler returns the handler of a tool built for a kubeconfig context.
+func (c *contextTools) handler(kubeContext, name string) (server.ToolHandlerFunc, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	handlers, ok := c.handlers[kubeContext]
+	if !ok {
+		client, err := clientForContext(kubeContext)
+		if err != nil {
+			return nil, err
+		}
+		handlers = map[string]server.ToolHandlerFunc{}
+		for _, t := range c.build(client) {
+			handlers[t.Tool().Name] = t.Handler
+		}
+		c.handlers[kubeContext] = handlers
+	}
+	handler, ok := handlers[name]
+	if !ok {
+		return nil, fmt.Errorf("tool %s is not available for context %s", name, kubeContext)
+	}
+	return handler, nil
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contextClient is a fake client that remembers the kubeconfig context it was created for.
+type contextClient struct {
+	FakeKubernetesClient
+	name string
+}
+
+// contextStubTool answers with the context of its client.
+type contextStubTool struct{ client Client }
+
+func (c contextStubTool) Tool() mcp.Tool { return mcp.NewTool("list_resources") }
+
+func (c contextStubTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if client, ok := c.client.(contextClient); ok {
+		return mcp.NewToolResultText(client.name), nil
+	}
+	return mcp.NewToolResultText("server"), nil
+}
+
+// fakeClientForContext replaces clientForContext for a test, knowing the contexts "staging" and "prod".
+func fakeClientForContext(t *testing.T) *int {
+	created := 0
+	previous := clientForContext
+	clientForContext = func(name string) (Client, error) {
+		if name != "staging" && name != "prod" {
+			return nil, errors.New(`context "` + name + `" does not exist`)
+		}
+		created++
+		return contextClient{name: name}, nil
+	}
+	t.Cleanup(func() { clientForContext = previous })
+	return &created
+}
+
+func TestContextToolsWrap(t *testing.T) {
+	created := fakeClientForContext(t)
+	build := func(client Client) []Tools { return []Tools{contextStubTool{client: client}} }
+	contexts := newContextTools(build)
+	handler := contexts.wrap("list_resources", contextStubTool{client: FakeKubernetesClient{}}.Handler)
+
+	call := func(ctx context.Context) (string, error) {
+		result, err := handler(ctx, mcp.CallToolRequest{})
+		if err != nil {
+			return "", err
+		}
+		return toolResultText(result), nil
+	}
+
+	out, err := call(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "server", out)
+
+	for range 2 {
+		out, err = call(withKubeContext(context.Background(), "staging"))
+		require.NoError(t, err)
+		assert.Equal(t, "staging", out)
+	}
+	assert.Equal(t, 1, *created, "the tools of a context are built once")
+
+	_, err = call(withKubeContext(context.Background(), "dev"))
+	assert.EqualError(t, err, `context "dev" does not exist`)
+
+	_, err = contexts.wrap("top_nodes", handler)(withKubeContext(context.Background(), "staging"), mcp.CallToolRequest{})
+	assert.EqualError(t, err, "tool top_nodes is not available for context staging")
+}
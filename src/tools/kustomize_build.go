@@ -0,0 +1,366 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+// kustomizationFileNames are the file names kustomize reads a kustomization from.
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// KustomizeBuildInput represents the input for kustomize_build.
+type KustomizeBuildInput struct {
+	Path               string `json:"path,omitempty"`
+	URL                string `json:"url,omitempty"`
+	FluxKustomization  string `json:"fluxKustomization,omitempty"`
+	FluxNamespace      string `json:"fluxNamespace,omitempty"`
+	Namespace          string `json:"namespace"`
+	Then               string `json:"then,omitempty"`
+	namespaceSpecified bool
+}
+
+// KustomizeBuildTool renders kustomizations and hands the result to diff_apply or validate_manifest.
+type KustomizeBuildTool struct {
+	client Client
+}
+
+// NewKustomizeBuildTool creates a new KustomizeBuildTool with the provided Kubernetes client.
+func NewKustomizeBuildTool(client Client) *KustomizeBuildTool {
+	return &KustomizeBuildTool{client: client}
+}
+
+// Tool returns the MCP tool definition for kustomize_build.
+func (k *KustomizeBuildTool) Tool() mcp.Tool {
+	return mcp.NewTool("kustomize_build",
+		mcp.WithDescription("Render a kustomization like kustomize build and return the manifest, optionally piped into diff_apply (then: diff) to preview what applying it would change, or into validate_manifest (then: validate). The repository comes from the source of a Flux Kustomization (fluxKustomization, rendered from its spec.path like Flux does, generating a kustomization when there is none), a .tar.gz archive from a host in GIT_DRIFT_ALLOWED_HOSTS (url), or a checkout under GIT_DRIFT_ROOT (default). Remote bases, Helm chart inflation and plugins are not supported; Flux post-build substitutions are not applied"),
+		mcp.WithString("path",
+			mcp.Description("Directory of the kustomization, relative to the repository root or GIT_DRIFT_ROOT (default: the Flux Kustomization's spec.path, or the root)"),
+		),
+		mcp.WithString("url",
+			mcp.Description("HTTPS URL of a .tar.gz archive of the repository; the host must be listed in GIT_DRIFT_ALLOWED_HOSTS"),
+		),
+		mcp.WithString("fluxKustomization",
+			mcp.Description("Name of a Flux Kustomization whose source artifact and path are rendered"),
+		),
+		mcp.WithString("fluxNamespace",
+			mcp.Description(fmt.Sprintf("Namespace of the Flux Kustomization (default: %s)", fluxNamespace)),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("For then: namespace of rendered objects that do not set one (default: the Flux Kustomization's targetNamespace, or default)"),
+		),
+		mcp.WithString("then",
+			mcp.Description("Pass the rendered manifest on: 'diff' runs diff_apply, 'validate' runs validate_manifest (default: only render)"),
+			mcp.Enum("diff", "validate"),
+		),
+	)
+}
+
+// Handler loads the repository, renders the kustomization and optionally diffs or validates it.
+func (k *KustomizeBuildTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateKustomizeBuildParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate kustomize_build params: %w", err)
+	}
+
+	var files map[string][]byte
+	source := map[string]any{}
+	switch {
+	case input.FluxKustomization != "":
+		if files, err = k.fluxKustomizationFiles(ctx, input, source); err != nil {
+			return nil, err
+		}
+	case input.URL != "":
+		archive, err := downloadArchive(ctx, input.URL)
+		if err != nil {
+			return nil, err
+		}
+		if files, err = readArchive(archive, notInHiddenDir, true); err != nil {
+			return nil, err
+		}
+		source["url"] = input.URL
+	default:
+		if files, err = readCheckout("", notInHiddenDir); err != nil {
+			return nil, err
+		}
+		source["checkout"] = true
+	}
+	source["path"] = input.Path
+
+	manifest, err := kustomizeBuild(files, input.Path)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := parseYAMLObjects([]byte(manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rendered manifest: %w", err)
+	}
+	names := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		name := obj.GetKind() + "/" + obj.GetName()
+		if obj.GetNamespace() != "" {
+			name = obj.GetKind() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+		}
+		names = append(names, name)
+	}
+	result := map[string]any{
+		"source":   source,
+		"objects":  names,
+		"manifest": manifest,
+	}
+
+	if input.Then != "" {
+		next := mcp.CallToolRequest{}
+		next.Params.Arguments = map[string]any{"manifest": manifest, "namespace": input.Namespace}
+		var handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		if input.Then == "diff" {
+			next.Params.Name = "diff_apply"
+			handler = NewDiffApplyTool(k.client).Handler
+		} else {
+			next.Params.Name = "validate_manifest"
+			handler = NewValidateManifestTool(k.client).Handler
+		}
+		out, err := handler(ctx, next)
+		if err != nil {
+			return nil, fmt.Errorf("%s failed on the rendered manifest: %w", next.Params.Name, err)
+		}
+		result[input.Then] = json.RawMessage(toolResultText(out))
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// fluxKustomizationFiles downloads the source artifact of a Flux Kustomization and defaults the
+// path and namespace to the Kustomization's spec.path and targetNamespace.
+func (k *KustomizeBuildTool) fluxKustomizationFiles(ctx context.Context, input *KustomizeBuildInput, source map[string]any) (map[string][]byte, error) {
+	if err := auth.AuthorizeNamespace(ctx, input.FluxNamespace); err != nil {
+		return nil, fmt.Errorf("invalid fluxNamespace: %w", err)
+	}
+	ri, err := fluxResourceInterface(k.client, "Kustomization", input.FluxNamespace)
+	if err != nil {
+		return nil, err
+	}
+	ks, err := ri.Get(ctx, input.FluxKustomization, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kustomization %s/%s: %w", input.FluxNamespace, input.FluxKustomization, err)
+	}
+	sourceKind, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "kind")
+	sourceName, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "name")
+	sourceNamespace, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "namespace")
+	if sourceNamespace == "" {
+		sourceNamespace = input.FluxNamespace
+	}
+	if input.Path == "" {
+		specPath, _, _ := unstructured.NestedString(ks.Object, "spec", "path")
+		input.Path = strings.Trim(path.Clean("/"+specPath), "/")
+	}
+	if target, _, _ := unstructured.NestedString(ks.Object, "spec", "targetNamespace"); target != "" && !input.namespaceSpecified {
+		input.Namespace = target
+	}
+
+	// The sourceRef may point to another namespace, which the client must be allowed to read as well.
+	if err := auth.AuthorizeNamespace(ctx, sourceNamespace); err != nil {
+		return nil, fmt.Errorf("invalid source of Kustomization %s/%s: %w", input.FluxNamespace, input.FluxKustomization, err)
+	}
+	archive, revision, err := fetchFluxArtifact(ctx, k.client, sourceKind, sourceNamespace, sourceName)
+	if err != nil {
+		return nil, err
+	}
+	files, err := readArchive(archive, notInHiddenDir, false)
+	if err != nil {
+		return nil, err
+	}
+	source["fluxKustomization"] = input.FluxNamespace + "/" + input.FluxKustomization
+	source["sourceRef"] = fmt.Sprintf("%s/%s/%s", sourceKind, sourceNamespace, sourceName)
+	source["revision"] = revision
+	return withGeneratedKustomization(files, input.Path)
+}
+
+// withGeneratedKustomization adds a kustomization listing every manifest below dir when dir has
+// none, as the Flux kustomize-controller does.
+func withGeneratedKustomization(files map[string][]byte, dir string) (map[string][]byte, error) {
+	if kustomizationFile(files, dir) != "" {
+		return files, nil
+	}
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	var resources []string
+	for name := range files {
+		if strings.HasPrefix(name, prefix) && isManifestFile(name) {
+			resources = append(resources, strings.TrimPrefix(name, prefix))
+		}
+	}
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("no manifests found in %q", dir)
+	}
+	sort.Strings(resources)
+	generated, err := yaml.Marshal(map[string]any{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  resources,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate kustomization: %w", err)
+	}
+	files[prefix+"kustomization.yaml"] = generated
+	return files, nil
+}
+
+// kustomizeBuild renders the kustomization in dir from an in-memory copy of the repository, so
+// nothing outside the repository can be read. References that are not part of the repository,
+// such as remote bases, are refused before kustomize could fetch them.
+func kustomizeBuild(files map[string][]byte, dir string) (string, error) {
+	if kustomizationFile(files, dir) == "" {
+		return "", fmt.Errorf("no kustomization.yaml found in %q", dir)
+	}
+	if err := checkLocalKustomizeRefs(files); err != nil {
+		return "", err
+	}
+	fs := filesys.MakeFsInMemory()
+	for name, data := range files {
+		if err := fs.MkdirAll(path.Dir("/" + name)); err != nil {
+			return "", fmt.Errorf("failed to load %s: %w", name, err)
+		}
+		if err := fs.WriteFile("/"+name, data); err != nil {
+			return "", fmt.Errorf("failed to load %s: %w", name, err)
+		}
+	}
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, path.Clean("/"+dir))
+	if err != nil {
+		return "", fmt.Errorf("failed to build kustomization: %w", err)
+	}
+	rendered, err := resources.AsYaml()
+	if err != nil {
+		return "", fmt.Errorf("failed to render kustomization: %w", err)
+	}
+	if len(rendered) > maxManifestBytes {
+		return "", fmt.Errorf("rendered manifest is larger than %d bytes", maxManifestBytes)
+	}
+	return string(rendered), nil
+}
+
+// checkLocalKustomizeRefs refuses resources, components and bases that are not files or
+// directories of the repository.
+func checkLocalKustomizeRefs(files map[string][]byte) error {
+	dirs := map[string]bool{}
+	for name := range files {
+		for d := path.Dir(name); d != "." && !dirs[d]; d = path.Dir(d) {
+			dirs[d] = true
+		}
+	}
+	for name, data := range files {
+		if !isKustomizationFile(name) {
+			continue
+		}
+		var k struct {
+			Resources  []string `json:"resources"`
+			Components []string `json:"components"`
+			Bases      []string `json:"bases"`
+		}
+		if err := yaml.Unmarshal(data, &k); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		for _, ref := range append(append(k.Resources, k.Components...), k.Bases...) {
+			target := path.Join(path.Dir(name), ref)
+			if _, ok := files[target]; ok || dirs[target] || target == "." {
+				continue
+			}
+			return fmt.Errorf("%s references %q, which is not part of the repository; remote bases are not supported", name, ref)
+		}
+	}
+	return nil
+}
+
+// kustomizationFile returns the kustomization file of dir, or "" when there is none.
+func kustomizationFile(files map[string][]byte, dir string) string {
+	for _, name := range kustomizationFileNames {
+		if _, ok := files[path.Join(dir, name)]; ok {
+			return path.Join(dir, name)
+		}
+	}
+	return ""
+}
+
+// isKustomizationFile reports whether a repository file is a kustomization.
+func isKustomizationFile(name string) bool {
+	base := path.Base(name)
+	for _, n := range kustomizationFileNames {
+		if base == n {
+			return true
+		}
+	}
+	return false
+}
+
+// notInHiddenDir accepts repository files outside hidden directories such as .git.
+func notInHiddenDir(name string) bool {
+	dir := path.Dir(name)
+	for _, part := range strings.Split(dir, "/") {
+		if strings.HasPrefix(part, ".") && part != "." {
+			return false
+		}
+	}
+	return true
+}
+
+func parseAndValidateKustomizeBuildParams(args map[string]any) (*KustomizeBuildInput, error) {
+	input := &KustomizeBuildInput{Namespace: "default", FluxNamespace: fluxNamespace}
+	if p, ok := args["path"].(string); ok && p != "" {
+		if !validRepositoryPath(p) {
+			return nil, fmt.Errorf("invalid path %q: must be a relative directory inside the repository", p)
+		}
+		input.Path = strings.Trim(path.Clean(p), "/")
+	}
+	input.URL, _ = args["url"].(string)
+	input.FluxKustomization, _ = args["fluxKustomization"].(string)
+	if input.URL != "" && input.FluxKustomization != "" {
+		return nil, errors.New("set only one of url and fluxKustomization")
+	}
+	if input.FluxKustomization != "" {
+		if err := validation.ValidateResourceName(input.FluxKustomization); err != nil {
+			return nil, fmt.Errorf("invalid fluxKustomization: %w", err)
+		}
+	}
+	if ns, ok := args["fluxNamespace"].(string); ok && ns != "" {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid fluxNamespace: %w", err)
+		}
+		input.FluxNamespace = ns
+	}
+	if ns, ok := args["namespace"].(string); ok && ns != "" {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace, input.namespaceSpecified = ns, true
+	}
+	input.Then, _ = args["then"].(string)
+	if input.Then != "" && input.Then != "diff" && input.Then != "validate" {
+		return nil, errors.New("then must be diff or validate")
+	}
+	return input, nil
+}
+
+// validRepositoryPath reports whether p stays inside the repository.
+func validRepositoryPath(p string) bool {
+	clean := path.Clean(p)
+	return !path.IsAbs(clean) && clean != ".." && !strings.HasPrefix(clean, "../")
+}
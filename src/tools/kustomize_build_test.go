@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var kustomizeRepository = map[string][]byte{
+	"base/kustomization.yaml": []byte("resources:\n- deployment.yaml\n"),
+	"base/deployment.yaml": []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.26
+`),
+	"overlays/prod/kustomization.yaml": []byte(`namespace: shop
+resources:
+- ../../base
+images:
+- name: nginx
+  newTag: "1.27"
+replicas:
+- name: web
+  count: 3
+`),
+}
+
+func TestKustomizeBuild(t *testing.T) {
+	manifest, err := kustomizeBuild(kustomizeRepository, "overlays/prod")
+	assert.NoError(t, err)
+	objects, err := parseYAMLObjects([]byte(manifest))
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "shop", objects[0].GetNamespace())
+	assert.Contains(t, manifest, "replicas: 3")
+	assert.Contains(t, manifest, "image: nginx:1.27")
+
+	_, err = kustomizeBuild(kustomizeRepository, "overlays")
+	assert.ErrorContains(t, err, "no kustomization.yaml")
+
+	remote := map[string][]byte{"kustomization.yaml": []byte("resources:\n- https://github.com/org/repo//deploy?ref=main\n")}
+	_, err = kustomizeBuild(remote, "")
+	assert.ErrorContains(t, err, "remote bases are not supported")
+}
+
+func TestWithGeneratedKustomization(t *testing.T) {
+	files, err := withGeneratedKustomization(map[string][]byte{
+		"apps/web.yaml":     []byte("kind: Deployment"),
+		"apps/db/pvc.yml":   []byte("kind: PersistentVolumeClaim"),
+		"apps/README.md":    []byte("docs"),
+		"infra/ingress.yml": []byte("kind: Ingress"),
+	}, "apps")
+	assert.NoError(t, err)
+	assert.YAMLEq(t, "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources: [db/pvc.yml, web.yaml]\n", string(files["apps/kustomization.yaml"]))
+
+	_, err = withGeneratedKustomization(map[string][]byte{"apps/README.md": []byte("docs")}, "apps")
+	assert.ErrorContains(t, err, "no manifests")
+}
+
+func TestKustomizeBuildHandlerDiff(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("GIT_DRIFT_ROOT", root)
+	for name, data := range kustomizeRepository {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0o700))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), data, 0o600))
+	}
+	client := &fakeDiffApplyClient{live: map[string]*unstructured.Unstructured{}}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"path": "overlays/prod", "then": "diff"}
+	result, err := NewKustomizeBuildTool(client).Handler(context.Background(), req)
+	assert.NoError(t, err)
+
+	var out struct {
+		Objects []string `json:"objects"`
+		Diff    struct {
+			Summary map[string]int `json:"summary"`
+		} `json:"diff"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	assert.Equal(t, []string{"Deployment/shop/web"}, out.Objects)
+	assert.Equal(t, map[string]int{"create": 1}, out.Diff.Summary)
+}
+
+func TestParseAndValidateKustomizeBuildParams(t *testing.T) {
+	input, err := parseAndValidateKustomizeBuildParams(map[string]any{"path": "overlays/prod/", "fluxKustomization": "apps", "namespace": "shop"})
+	assert.NoError(t, err)
+	assert.Equal(t, "overlays/prod", input.Path)
+	assert.True(t, input.namespaceSpecified)
+
+	_, err = parseAndValidateKustomizeBuildParams(map[string]any{"path": "../secrets"})
+	assert.Error(t, err)
+	_, err = parseAndValidateKustomizeBuildParams(map[string]any{"then": "apply"})
+	assert.ErrorContains(t, err, "then must be")
+	_, err = parseAndValidateKustomizeBuildParams(map[string]any{"url": "https://x/y.tar.gz", "fluxKustomization": "apps"})
+	assert.Error(t, err)
+}
+
+func TestKustomizeBuildFluxNamespaceScope(t *testing.T) {
+	ctx := auth.WithClient(context.Background(), &auth.Client{Name: "shop", Namespaces: []string{"shop"}})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"namespace": "shop", "fluxKustomization": "apps"}
+
+	_, err := NewKustomizeBuildTool(nil).Handler(ctx, req)
+
+	assert.EqualError(t, err, "invalid fluxNamespace: client shop is not allowed to access namespace flux-system")
+}
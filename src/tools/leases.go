@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LeaseSummary describes a coordination.k8s.io Lease and whether its holder stopped renewing it.
+type LeaseSummary struct {
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Holder      string `json:"holder,omitempty"`
+	RenewedAgo  string `json:"renewedAgo,omitempty"`
+	Duration    string `json:"leaseDuration"`
+	Age         string `json:"age"`
+	Transitions int32  `json:"transitions,omitempty"`
+	Stale       bool   `json:"stale"`
+}
+
+// ListLeasesInput represents the input for list_leases.
+type ListLeasesInput struct {
+	Namespace         string `json:"namespace,omitempty"`
+	IncludeNodeLeases bool   `json:"includeNodeLeases"`
+	StaleOnly         bool   `json:"staleOnly"`
+}
+
+// ListLeasesTool summarizes leader election and heartbeat Leases.
+type ListLeasesTool struct {
+	client Client
+}
+
+// NewListLeasesTool creates a new ListLeasesTool with the provided Kubernetes client.
+func NewListLeasesTool(client Client) *ListLeasesTool {
+	return &ListLeasesTool{client: client}
+}
+
+// Tool returns the MCP tool definition for list_leases.
+func (l *ListLeasesTool) Tool() mcp.Tool {
+	return mcp.NewTool("list_leases",
+		mcp.WithDescription("List coordination.k8s.io Leases used for leader election by operators and controllers, with holder, last renewal, lease duration, age and leader transitions. A lease whose holder has not renewed it within its duration is flagged stale: the controller holding it is stuck or gone and no replica has taken over"),
+		mcp.WithString("namespace",
+			mcp.Description("Only list leases in this namespace (default: all namespaces)"),
+		),
+		mcp.WithBoolean("includeNodeLeases",
+			mcp.Description("Include the per-node heartbeat leases in kube-node-lease (default: false)"),
+		),
+		mcp.WithBoolean("staleOnly",
+			mcp.Description("Only return stale leases (default: false)"),
+		),
+	)
+}
+
+// Handler lists the leases, stale ones first.
+func (l *ListLeasesTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateListLeasesParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate list_leases params: %w", err)
+	}
+	clientset, err := l.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	list, err := clientset.CoordinationV1().Leases(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leases: %w", err)
+	}
+
+	leases, stale := summarizeLeases(list.Items, input, time.Now())
+	out, err := json.Marshal(map[string]any{
+		"total":  len(leases),
+		"stale":  stale,
+		"leases": leases,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// summarizeLeases describes the leases selected by input, stale first, and returns the number
+// of stale leases.
+func summarizeLeases(items []coordinationv1.Lease, input *ListLeasesInput, now time.Time) ([]LeaseSummary, int) {
+	leases := []LeaseSummary{}
+	stale := 0
+	for i := range items {
+		lease := &items[i]
+		if lease.Namespace == corev1.NamespaceNodeLease && !input.IncludeNodeLeases {
+			continue
+		}
+		summary := leaseSummary(lease, now)
+		if summary.Stale {
+			stale++
+		} else if input.StaleOnly {
+			continue
+		}
+		leases = append(leases, summary)
+	}
+	sort.Slice(leases, func(i, j int) bool {
+		if leases[i].Stale != leases[j].Stale {
+			return leases[i].Stale
+		}
+		if leases[i].Namespace != leases[j].Namespace {
+			return leases[i].Namespace < leases[j].Namespace
+		}
+		return leases[i].Name < leases[j].Name
+	})
+	return leases, stale
+}
+
+// leaseSummary describes a lease. Only a held lease can be stale: a lease without a holder was
+// released on purpose.
+func leaseSummary(lease *coordinationv1.Lease, now time.Time) LeaseSummary {
+	status := leaderLeaseStatus(lease.Name, lease, now)
+	summary := LeaseSummary{
+		Namespace:  lease.Namespace,
+		Name:       lease.Name,
+		Holder:     status.Holder,
+		RenewedAgo: status.RenewedAgo,
+		Duration:   status.Duration,
+		Age:        now.Sub(lease.CreationTimestamp.Time).Round(time.Second).String(),
+		Stale:      status.Holder != "" && !status.Fresh,
+	}
+	if lease.Spec.LeaseTransitions != nil {
+		summary.Transitions = *lease.Spec.LeaseTransitions
+	}
+	return summary
+}
+
+func parseAndValidateListLeasesParams(args map[string]any) (*ListLeasesInput, error) {
+	input := &ListLeasesInput{}
+	input.Namespace, _ = args["namespace"].(string)
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	input.IncludeNodeLeases, _ = args["includeNodeLeases"].(bool)
+	input.StaleOnly, _ = args["staleOnly"].(bool)
+	// Asking for the node lease namespace means asking for node leases.
+	if input.Namespace == corev1.NamespaceNodeLease {
+		input.IncludeNodeLeases = true
+	}
+	return input, nil
+}
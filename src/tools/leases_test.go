@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSummarizeLeases(t *testing.T) {
+	now := time.Now()
+	lease := func(namespace, name, holder string, renewed time.Duration) coordinationv1.Lease {
+		duration, transitions := int32(15), int32(2)
+		renewTime := metav1.NewMicroTime(now.Add(-renewed))
+		l := coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Spec:       coordinationv1.LeaseSpec{LeaseDurationSeconds: &duration, LeaseTransitions: &transitions, RenewTime: &renewTime},
+		}
+		if holder != "" {
+			l.Spec.HolderIdentity = &holder
+		}
+		return l
+	}
+	items := []coordinationv1.Lease{
+		lease("cert-manager", "cert-manager-controller", "cm-1", 3*time.Second),
+		lease("argocd", "argocd-application-controller", "argo-0", 10*time.Minute),
+		lease("kube-node-lease", "node-1", "node-1", time.Second),
+		lease("ingress", "ingress-nginx-leader", "", time.Hour),
+	}
+
+	leases, stale := summarizeLeases(items, &ListLeasesInput{}, now)
+	assert.Equal(t, 1, stale)
+	assert.Len(t, leases, 3)
+	assert.Equal(t, LeaseSummary{
+		Namespace: "argocd", Name: "argocd-application-controller", Holder: "argo-0",
+		RenewedAgo: "10m0s", Duration: "15s", Age: "1h0m0s", Transitions: 2, Stale: true,
+	}, leases[0])
+	assert.Equal(t, "cert-manager-controller", leases[1].Name)
+	assert.False(t, leases[2].Stale, "released leases are not stale")
+
+	leases, _ = summarizeLeases(items, &ListLeasesInput{IncludeNodeLeases: true}, now)
+	assert.Len(t, leases, 4)
+
+	leases, stale = summarizeLeases(items, &ListLeasesInput{StaleOnly: true}, now)
+	assert.Equal(t, 1, stale)
+	assert.Len(t, leases, 1)
+}
+
+func TestParseAndValidateListLeasesParams(t *testing.T) {
+	input, err := parseAndValidateListLeasesParams(map[string]any{"namespace": "kube-node-lease"})
+	assert.NoError(t, err)
+	assert.True(t, input.IncludeNodeLeases)
+
+	_, err = parseAndValidateListLeasesParams(map[string]any{"namespace": "Bad_NS"})
+	assert.Error(t, err)
+}
@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 )
 
 // ListResourcesInput represents the input parameters for listing Kubernetes resources.
@@ -77,14 +78,31 @@ type IngressSummary struct {
 	Addresses []string `json:"addresses"`
 }
 
+// KnativeServiceSummary represents a minimal summary for a Knative Service
+// Only used for kind == "Service" in the serving.knative.dev group
+type KnativeServiceSummary struct {
+	Name                  string                 `json:"name"`
+	Namespace             string                 `json:"namespace"`
+	URL                   string                 `json:"url,omitempty"`
+	Ready                 string                 `json:"ready"`
+	Message               string                 `json:"message,omitempty"`
+	LatestReadyRevision   string                 `json:"latestReadyRevision,omitempty"`
+	LatestCreatedRevision string                 `json:"latestCreatedRevision,omitempty"`
+	Traffic               []KnativeTrafficTarget `json:"traffic"`
+	MinScale              string                 `json:"minScale,omitempty"`
+	MaxScale              string                 `json:"maxScale,omitempty"`
+	ScaleTarget           string                 `json:"scaleTarget,omitempty"`
+}
+
 // ListTool provides functionality to list Kubernetes resources by kind.
 type ListTool struct {
 	client Client
+	cache  *listCache
 }
 
 // NewListTool creates a new ListTool instance with the provided Kubernetes client.
 func NewListTool(client Client) ListTool {
-	return ListTool{client: client}
+	return ListTool{client: client, cache: listCacheFromEnv()}
 }
 
 // Tool returns the MCP tool definition for listing Kubernetes resources.
@@ -125,19 +143,25 @@ func (l ListTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 		return nil, err
 	}
 
+	// Discovery is resolved once per request and shared by every path below
+	apiResourceLists, err := l.serverPreferredResources()
+	if err != nil {
+		return nil, err
+	}
+
 	// Handle groupFilter functionality for discovering resources
 	if input.GroupFilter != "" {
 		if input.Kind == "all" || input.Kind == "" {
 			// Discovery mode: return all resource types for the group
-			return l.handleGroupDiscovery(input.GroupFilter)
+			return l.handleGroupDiscovery(apiResourceLists, input.GroupFilter)
 		} else {
 			// Filter mode: find specific kind within the group
-			return l.handleGroupFilteredList(ctx, input)
+			return l.handleGroupFilteredList(ctx, apiResourceLists, input)
 		}
 	}
 
 	// Original functionality for specific kind
-	gvrMatch, err := l.discoverResourceByKind(input.Kind)
+	gvrMatch, err := findGVRByKind(apiResourceLists, input.Kind)
 	if err != nil {
 		return nil, err
 	}
@@ -168,17 +192,7 @@ func (l ListTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 }
 
 // handleGroupDiscovery returns all available resource types for a given group filter
-func (l ListTool) handleGroupDiscovery(groupFilter string) (*mcp.CallToolResult, error) {
-	discoClient, err := l.client.DiscoClient()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create discovery client: %w", err)
-	}
-
-	apiResourceLists, err := discoClient.ServerPreferredResources()
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover resources: %w", err)
-	}
-
+func (l ListTool) handleGroupDiscovery(apiResourceLists []*metav1.APIResourceList, groupFilter string) (*mcp.CallToolResult, error) {
 	matches, err := findGVRsByGroupSubstring(apiResourceLists, groupFilter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find resources by group substring: %w", err)
@@ -215,17 +229,7 @@ func (l ListTool) handleGroupDiscovery(groupFilter string) (*mcp.CallToolResult,
 }
 
 // handleGroupFilteredList lists resources of a specific kind within a filtered group
-func (l ListTool) handleGroupFilteredList(ctx context.Context, input *ListResourcesInput) (*mcp.CallToolResult, error) {
-	discoClient, err := l.client.DiscoClient()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create discovery client: %w", err)
-	}
-
-	apiResourceLists, err := discoClient.ServerPreferredResources()
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover resources: %w", err)
-	}
-
+func (l ListTool) handleGroupFilteredList(ctx context.Context, apiResourceLists []*metav1.APIResourceList, input *ListResourcesInput) (*mcp.CallToolResult, error) {
 	// First find all resources in the group
 	matches, err := findGVRsByGroupSubstring(apiResourceLists, input.GroupFilter)
 	if err != nil {
@@ -280,8 +284,9 @@ func (l ListTool) handleGroupFilteredList(ctx context.Context, input *ListResour
 	}
 }
 
-// discoverResourceByKind discovers and returns the GroupVersionResource match for a given kind.
-func (l ListTool) discoverResourceByKind(kind string) (*gvrMatch, error) {
+// serverPreferredResources fetches the preferred version of every served resource. The client
+// caches discovery across calls; refresh_discovery drops the cache.
+func (l ListTool) serverPreferredResources() ([]*metav1.APIResourceList, error) {
 	discoClient, err := l.client.DiscoClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create discovery client: %w", err)
@@ -291,19 +296,25 @@ func (l ListTool) discoverResourceByKind(kind string) (*gvrMatch, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover resources: %w", err)
 	}
-
-	return findGVRByKind(apiResourceLists, kind)
+	return apiResourceLists, nil
 }
 
 // listResourceDetails retrieves full details of all resources matching the given GVR and input parameters.
 func (l ListTool) listResourceDetails(ctx context.Context, gvrMatch *gvrMatch, input *ListResourcesInput) (interface{}, error) {
-	ri, err := l.client.ResourceInterface(*gvrMatch.ToGroupVersionResource(), gvrMatch.namespaced, input.Namespace)
+	return l.listResources(ctx, gvrMatch, input)
+}
+
+// listResources lists the resources matching the given GVR and input parameters, reusing a recent
+// result of the same query when its resourceVersion is still current.
+func (l ListTool) listResources(ctx context.Context, gvrMatch *gvrMatch, input *ListResourcesInput) (*unstructured.UnstructuredList, error) {
+	gvr := *gvrMatch.ToGroupVersionResource()
+	ri, err := l.client.ResourceInterface(gvr, gvrMatch.namespaced, input.Namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource interface: %w", err)
 	}
 
 	listOptions := l.buildListOptions(input)
-	unstructList, err := ri.List(ctx, listOptions)
+	unstructList, err := l.cache.list(ctx, ri, listCacheKey(gvr, listOptions, input.Namespace), listOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list resources: %w", err)
 	}
@@ -335,21 +346,20 @@ func (l ListTool) buildListOptions(input *ListResourcesInput) metav1.ListOptions
 
 // listResourcesWithStatus retrieves resources and extracts their status information.
 func (l ListTool) listResourcesWithStatus(ctx context.Context, gvrMatch *gvrMatch, input *ListResourcesInput) ([]interface{}, error) {
-	ri, err := l.client.ResourceInterface(*gvrMatch.ToGroupVersionResource(), gvrMatch.namespaced, input.Namespace)
+	unstructList, err := l.listResources(ctx, gvrMatch, input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create resource interface: %w", err)
-	}
-
-	listOptions := l.buildListOptions(input)
-	unstructList, err := ri.List(ctx, listOptions)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list resources: %w", err)
+		return nil, err
 	}
 
 	var result []interface{}
 	kind := strings.ToLower(gvrMatch.apiRes.Kind)
+	if kind == "service" && strings.HasPrefix(gvrMatch.groupVersion, knativeServingGroup+"/") {
+		kind = "ksvc"
+	}
 	for _, item := range unstructList.Items {
 		switch kind {
+		case "ksvc":
+			result = append(result, knativeServiceSummary(&item))
 		case "pod":
 			pod := PodSummary{
 				Name:      item.GetName(),
@@ -640,9 +650,11 @@ func findGVRsByGroupSubstring(apiResourceLists []*metav1.APIResourceList, groupS
 	return matches, nil
 }
 
-// findGVRByKind finds a resource by matching against plural name, Kind, or short names (case-insensitive).
+// findGVRByKind finds a resource by matching against plural name, Kind, or short names (case-insensitive),
+// or a "resource.group" name. Unknown kinds fail with the closest matches, or resolve to the only
+// close match when KIND_FUZZY_MATCH is set.
 func findGVRByKind(apiResourceLists []*metav1.APIResourceList, kind string) (*gvrMatch, error) {
-	target := strings.ToLower(kind)
+	target := strings.ToLower(strings.TrimSpace(kind))
 	var found *gvrMatch
 
 	for _, apiResList := range apiResourceLists {
@@ -677,10 +689,34 @@ func findGVRByKind(apiResourceLists []*metav1.APIResourceList, kind string) (*gv
 	}
 
 	if found == nil {
-		return nil, fmt.Errorf("cannot find resource '%s'", kind)
+		found = findGVRByQualifiedKind(apiResourceLists, target)
+	}
+	if found == nil {
+		suggestions := suggestKinds(apiResourceLists, target)
+		if found = unambiguousKind(suggestions); found == nil || !fuzzyKindMatchAllowed() {
+			return nil, unknownKindError(kind, suggestions)
+		}
 	}
 	if found.ToGroupVersionResource() == nil {
 		return nil, fmt.Errorf("cannot find resource '%s'", kind)
 	}
 	return found, nil
 }
+
+// groupKindResourceInterface resolves a GroupKind to the version preferred by the cluster and returns
+// a resource interface for it, so callers of optional CRDs work across API versions.
+func groupKindResourceInterface(client Client, gk schema.GroupKind, namespace string) (dynamic.ResourceInterface, error) {
+	mapper, err := client.RESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+	mapping, err := mapper.RESTMapping(gk)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not served by the cluster: %w", gk.String(), err)
+	}
+	ri, err := client.ResourceInterface(mapping.Resource, true, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource interface: %w", err)
+	}
+	return ri, nil
+}
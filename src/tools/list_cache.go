@@ -0,0 +1,141 @@
+// Environment variables used by this tool:
+// Optional:
+//   LIST_CACHE_TTL                 - How long list_resources may reuse a list result after checking that it is still current (default: 60s); "0" disables reuse
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	defaultListCacheTTL = 60 * time.Second
+	// maxListCacheEntries bounds the number of cached lists; the oldest is dropped first.
+	maxListCacheEntries = 64
+	// listCacheCheckWait is how long a watch from the cached resourceVersion may stay silent before
+	// the cached list is considered current. The API server replays missed events immediately.
+	listCacheCheckWait = 100 * time.Millisecond
+)
+
+// listCache keeps recent list results with their resourceVersion, so repeated calls in a
+// conversation skip the list when nothing matching the query has changed since.
+type listCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]listCacheEntry
+}
+
+type listCacheEntry struct {
+	list    *unstructured.UnstructuredList
+	fetched time.Time
+}
+
+// newListCache creates a cache that reuses results for ttl; a ttl of zero disables it.
+func newListCache(ttl time.Duration) *listCache {
+	return &listCache{ttl: ttl, entries: map[string]listCacheEntry{}}
+}
+
+// listCacheFromEnv creates the cache configured by LIST_CACHE_TTL.
+func listCacheFromEnv() *listCache {
+	ttl := defaultListCacheTTL
+	if v := os.Getenv("LIST_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			ttl = d
+		}
+	}
+	return newListCache(ttl)
+}
+
+// listCacheKey identifies a list by resource, namespace, selectors and limit.
+func listCacheKey(gvr schema.GroupVersionResource, opts metav1.ListOptions, namespace string) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%d", gvr.String(), namespace, opts.LabelSelector, opts.FieldSelector, opts.Limit)
+}
+
+// list returns the cached list for key when it is younger than the TTL and a watch from its
+// resourceVersion reports no change, and lists from the API server otherwise. The returned list is
+// shared with later calls and must not be modified.
+func (c *listCache) list(ctx context.Context, ri dynamic.ResourceInterface, key string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if c == nil || c.ttl <= 0 {
+		return ri.List(ctx, opts)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetched) < c.ttl && !changedSince(ctx, ri, opts, entry.list.GetResourceVersion()) {
+		return entry.list, nil
+	}
+
+	list, err := ri.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if list.GetResourceVersion() != "" && list.GetContinue() == "" {
+		c.store(key, list)
+	}
+	return list, nil
+}
+
+// store adds a list, dropping expired entries and, when the cache is full, the oldest one.
+func (c *listCache) store(key string, list *unstructured.UnstructuredList) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var oldest string
+	for k, e := range c.entries {
+		if time.Since(e.fetched) >= c.ttl {
+			delete(c.entries, k)
+			continue
+		}
+		if oldest == "" || e.fetched.Before(c.entries[oldest].fetched) {
+			oldest = k
+		}
+	}
+	if len(c.entries) >= maxListCacheEntries {
+		delete(c.entries, oldest)
+	}
+	c.entries[key] = listCacheEntry{list: list, fetched: time.Now()}
+}
+
+// changedSince watches the objects matching the list options from resourceVersion and reports
+// whether any of them changed. A bookmark before any event confirms that nothing did; so does a
+// watch that stays silent for listCacheCheckWait. Errors, including an expired resourceVersion,
+// count as a change so the caller lists again.
+func changedSince(ctx context.Context, ri dynamic.ResourceInterface, opts metav1.ListOptions, resourceVersion string) bool {
+	if resourceVersion == "" {
+		return true
+	}
+	timeout := int64(1)
+	w, err := ri.Watch(ctx, metav1.ListOptions{
+		LabelSelector:       opts.LabelSelector,
+		FieldSelector:       opts.FieldSelector,
+		ResourceVersion:     resourceVersion,
+		AllowWatchBookmarks: true,
+		TimeoutSeconds:      &timeout,
+	})
+	if err != nil {
+		return true
+	}
+	defer w.Stop()
+
+	timer := time.NewTimer(listCacheCheckWait)
+	defer timer.Stop()
+	select {
+	case event, ok := <-w.ResultChan():
+		return !ok || event.Type != watch.Bookmark
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return true
+	}
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// stubListInterface counts lists and answers watches with the queued events.
+type stubListInterface struct {
+	dynamic.ResourceInterface
+	lists  int
+	events []watch.Event
+}
+
+func (s *stubListInterface) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	s.lists++
+	list := &unstructured.UnstructuredList{}
+	list.SetResourceVersion("100")
+	return list, nil
+}
+
+func (s *stubListInterface) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	w := watch.NewFakeWithChanSize(len(s.events), false)
+	for _, event := range s.events {
+		w.Action(event.Type, event.Object)
+	}
+	return w, nil
+}
+
+func TestListCacheReuse(t *testing.T) {
+	opts := metav1.ListOptions{LabelSelector: "app=web"}
+	key := listCacheKey(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, opts, "default")
+	pod := &unstructured.Unstructured{}
+
+	tests := []struct {
+		name   string
+		ttl    time.Duration
+		events []watch.Event
+		lists  int
+	}{
+		{name: "silent watch", ttl: time.Minute, lists: 1},
+		{name: "bookmark", ttl: time.Minute, events: []watch.Event{{Type: watch.Bookmark, Object: pod}}, lists: 1},
+		{name: "modified", ttl: time.Minute, events: []watch.Event{{Type: watch.Modified, Object: pod}}, lists: 2},
+		{name: "expired resourceVersion", ttl: time.Minute, events: []watch.Event{{Type: watch.Error, Object: pod}}, lists: 2},
+		{name: "disabled", ttl: 0, lists: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newListCache(tt.ttl)
+			ri := &stubListInterface{events: tt.events}
+			_, err := cache.list(context.Background(), ri, key, opts)
+			assert.NoError(t, err)
+			list, err := cache.list(context.Background(), ri, key, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, "100", list.GetResourceVersion())
+			assert.Equal(t, tt.lists, ri.lists)
+		})
+	}
+}
+
+func TestListCacheKey(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	assert.NotEqual(t,
+		listCacheKey(gvr, metav1.ListOptions{LabelSelector: "app=web"}, "default"),
+		listCacheKey(gvr, metav1.ListOptions{LabelSelector: "app=web"}, "prod"))
+	assert.NotEqual(t,
+		listCacheKey(gvr, metav1.ListOptions{FieldSelector: "spec.nodeName=a"}, ""),
+		listCacheKey(gvr, metav1.ListOptions{FieldSelector: "spec.nodeName=a", Limit: 5}, ""))
+}
@@ -1,34 +1,116 @@
+// Environment variables used by this tool:
+// Optional:
+//   INGRESS_NAME                   - Ingress used when a call passes neither name nor labelSelector, unless kind is HTTPRoute
+//   INGRESS_NAMESPACE              - Namespace used when a call does not pass one
+
 package tools
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
 	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 )
 
 // ListIngressPathsInput represents the input parameters for listing ingress paths.
 type ListIngressPathsInput struct {
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
+	Kind          string `json:"kind,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
 }
 
-// IngressPath represents a path configuration from an ingress.
+// IngressPath represents a path configuration from an ingress or an HTTPRoute rule.
 type IngressPath struct {
+	Host        string `json:"host,omitempty"`
 	Path        string `json:"path"`
 	PathType    string `json:"pathType,omitempty"`
 	ServiceName string `json:"serviceName"`
+	// Match, Filters and Backends are only set for HTTPRoute rules. Backends lists every backendRef
+	// when the rule does not simply forward to one Service of the route's namespace.
+	Match    string              `json:"match,omitempty"`
+	Filters  []string            `json:"filters,omitempty"`
+	Backends []GatewayBackendRef `json:"backends,omitempty"`
+}
+
+// IngressTLS is a TLS entry of an ingress and whether its certificate Secret exists.
+type IngressTLS struct {
+	Hosts        []string `json:"hosts,omitempty"`
+	SecretName   string   `json:"secretName,omitempty"`
+	SecretExists bool     `json:"secretExists"`
+	Error        string   `json:"error,omitempty"`
 }
 
 // IngressPathsResponse represents the response containing all paths from an ingress.
 type IngressPathsResponse struct {
-	IngressName string        `json:"ingressName"`
-	Namespace   string        `json:"namespace"`
-	Paths       []IngressPath `json:"paths"`
+	IngressName      string            `json:"ingressName"`
+	Namespace        string            `json:"namespace"`
+	IngressClassName string            `json:"ingressClassName,omitempty"`
+	Paths            []IngressPath     `json:"paths"`
+	TLS              []IngressTLS      `json:"tls,omitempty"`
+	Annotations      map[string]string `json:"annotations,omitempty"`
+}
+
+// HTTPRouteParent is a Gateway an HTTPRoute attaches to, with the route's status there.
+type HTTPRouteParent struct {
+	Gateway      string   `json:"gateway"`
+	SectionName  string   `json:"sectionName,omitempty"`
+	GatewayClass string   `json:"gatewayClass,omitempty"`
+	Addresses    []string `json:"addresses,omitempty"`
+	Conditions   []string `json:"conditions,omitempty"`
+	Policies     []string `json:"policies,omitempty"`
+}
+
+// HTTPRoutePathsResponse represents the paths of a Gateway API HTTPRoute and the Gateways it attaches to.
+type HTTPRoutePathsResponse struct {
+	RouteName string            `json:"routeName"`
+	Namespace string            `json:"namespace"`
+	Parents   []HTTPRouteParent `json:"parents"`
+	Paths     []IngressPath     `json:"paths"`
+	Policies  []string          `json:"policies,omitempty"`
+	Problems  []string          `json:"problems,omitempty"`
+}
+
+// notableIngressAnnotations are the controller annotations that change how requests are routed,
+// rewritten or redirected.
+var notableIngressAnnotations = map[string]bool{
+	"kubernetes.io/ingress.class":                      true,
+	"nginx.ingress.kubernetes.io/rewrite-target":       true,
+	"nginx.ingress.kubernetes.io/use-regex":            true,
+	"nginx.ingress.kubernetes.io/app-root":             true,
+	"nginx.ingress.kubernetes.io/ssl-redirect":         true,
+	"nginx.ingress.kubernetes.io/force-ssl-redirect":   true,
+	"nginx.ingress.kubernetes.io/backend-protocol":     true,
+	"nginx.ingress.kubernetes.io/permanent-redirect":   true,
+	"nginx.ingress.kubernetes.io/temporal-redirect":    true,
+	"nginx.ingress.kubernetes.io/canary":               true,
+	"nginx.ingress.kubernetes.io/canary-weight":        true,
+	"traefik.ingress.kubernetes.io/router.entrypoints": true,
+	"traefik.ingress.kubernetes.io/router.middlewares": true,
+	"traefik.ingress.kubernetes.io/router.tls":         true,
+	"alb.ingress.kubernetes.io/scheme":                 true,
+	"alb.ingress.kubernetes.io/listen-ports":           true,
+	"alb.ingress.kubernetes.io/ssl-redirect":           true,
+	"alb.ingress.kubernetes.io/target-type":            true,
+	"alb.ingress.kubernetes.io/group.name":             true,
+	"haproxy.org/path-rewrite":                         true,
+	"haproxy.org/ssl-redirect":                         true,
+	"cert-manager.io/cluster-issuer":                   true,
+	"cert-manager.io/issuer":                           true,
+	"kubernetes.io/ingress.global-static-ip-name":      true,
+	"networking.gke.io/managed-certificates":           true,
+	"ingress.kubernetes.io/ssl-redirect":               true,
 }
 
 // ListIngressPathsTool provides functionality to list paths from a specific ingress.
@@ -44,41 +126,109 @@ func NewListIngressPathsTool(client Client) *ListIngressPathsTool {
 // Tool returns the MCP tool definition for listing ingress paths.
 func (l *ListIngressPathsTool) Tool() mcp.Tool {
 	return mcp.NewTool("list_ingress_paths",
-		mcp.WithDescription("List all paths from a specific Kubernetes ingress resource in staging namespace"),
-		mcp.WithString("unused",
-			mcp.Description("This parameter is unused but required for schema validation"),
+		mcp.WithDescription("List the host, path and backend service of every rule of one ingress or Gateway API HTTPRoute, or of all ingresses and HTTPRoutes in a namespace (optionally selected by label) when name is omitted. Ingresses come with the ingress class, TLS secrets and whether they exist, and routing annotations such as rewrite-target and ssl-redirect; HTTPRoutes with their method/header matches, filters, weighted backendRefs, the Gateways they attach to with the route status there, and attached policies (targetRef-based policies such as BackendTLSPolicy or implementation-specific ones)"),
+		mcp.WithString("kind",
+			mcp.Description("Ingress or HTTPRoute; a named lookup defaults to Ingress, a listing returns both when the Gateway API is installed"),
+			mcp.Enum("Ingress", "HTTPRoute"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Ingress or HTTPRoute name (defaults to INGRESS_NAME for ingresses when labelSelector is not set; omit both to list every ingress and HTTPRoute)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace (defaults to INGRESS_NAMESPACE, then to default for a named lookup or all namespaces for a listing)"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Label selector for the ingresses and HTTPRoutes to list when name is omitted (e.g. \"app=web\")"),
 		),
 	)
 }
 
-// Handler processes requests to list paths from a specific ingress.
+// Handler processes requests to list paths from one ingress or HTTPRoute, or from those of a namespace.
 func (l *ListIngressPathsTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	input := &ListIngressPathsInput{}
-
-	// Get ingress name from environment variable, fallback to hardcoded value
-	ingressName := os.Getenv("INGRESS_NAME")
-	if ingressName == "" {
-		ingressName = "test-arafat-im"
+	input, err := parseAndValidateListIngressPathsParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate list_ingress_paths params: %w", err)
 	}
-	input.Name = ingressName
-
-	// Always use staging namespace
-	input.Namespace = "staging"
-
-	// Get the ingress resource
-	ingress, err := l.getIngress(ctx, input)
+	apiResourceLists, err := l.discoverResources()
 	if err != nil {
 		return nil, err
 	}
 
-	// Extract paths from the ingress
-	response, err := l.extractIngressPaths(ingress, input)
-	if err != nil {
-		return nil, err
+	var result any
+	switch {
+	case input.Name != "" && input.Kind == "HTTPRoute":
+		ri, err := l.httpRouteInterface(apiResourceLists, input.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		route, err := ri.Get(ctx, input.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get HTTPRoute %s in namespace %s: %w", input.Name, input.Namespace, err)
+		}
+		gateways := l.gatewayContext(ctx, apiResourceLists)
+		response := gateways.httpRoutePaths(route)
+		response.Problems = gateways.problems
+		result = response
+	case input.Name != "":
+		ingress, err := l.getIngress(ctx, apiResourceLists, input)
+		if err != nil {
+			return nil, err
+		}
+		if result, err = l.extractIngressPaths(ctx, ingress); err != nil {
+			return nil, err
+		}
+	default:
+		listing := map[string]any{
+			"namespace":     input.Namespace,
+			"labelSelector": input.LabelSelector,
+		}
+		if input.Kind != "HTTPRoute" {
+			ri, err := l.ingressInterface(apiResourceLists, input.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			ingresses, err := listSortedObjects(ctx, ri, input.LabelSelector)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list ingresses: %w", err)
+			}
+			responses := make([]*IngressPathsResponse, 0, len(ingresses))
+			for i := range ingresses {
+				response, err := l.extractIngressPaths(ctx, &ingresses[i])
+				if err != nil {
+					return nil, err
+				}
+				responses = append(responses, response)
+			}
+			listing["ingresses"] = responses
+		}
+		// Without a kind, HTTPRoutes are only listed where the Gateway API is installed.
+		if ri, err := l.httpRouteInterface(apiResourceLists, input.Namespace); err == nil {
+			if input.Kind != "Ingress" {
+				routes, err := listSortedObjects(ctx, ri, input.LabelSelector)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+				}
+				gateways := &gatewayContext{}
+				if len(routes) > 0 {
+					gateways = l.gatewayContext(ctx, apiResourceLists)
+				}
+				responses := make([]*HTTPRoutePathsResponse, 0, len(routes))
+				for i := range routes {
+					responses = append(responses, gateways.httpRoutePaths(&routes[i]))
+				}
+				listing["httpRoutes"] = responses
+				if len(gateways.problems) > 0 {
+					listing["problems"] = gateways.problems
+				}
+			}
+		} else if input.Kind == "HTTPRoute" {
+			return nil, err
+		}
+		result = listing
 	}
 
 	// Marshal the response
-	out, err := json.Marshal(response)
+	out, err := json.Marshal(result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal ingress paths: %w", err)
 	}
@@ -87,30 +237,68 @@ func (l *ListIngressPathsTool) Handler(ctx context.Context, req mcp.CallToolRequ
 }
 
 // getIngress retrieves the ingress resource from the cluster.
-func (l *ListIngressPathsTool) getIngress(ctx context.Context, input *ListIngressPathsInput) (*unstructured.Unstructured, error) {
+func (l *ListIngressPathsTool) getIngress(ctx context.Context, apiResourceLists []*metav1.APIResourceList, input *ListIngressPathsInput) (*unstructured.Unstructured, error) {
+	ri, err := l.ingressInterface(apiResourceLists, input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the ingress resource
+	ingress, err := ri.Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingress %s in namespace %s: %w", input.Name, input.Namespace, err)
+	}
+
+	return ingress, nil
+}
+
+// listSortedObjects lists the objects matching the label selector, sorted by namespace and name.
+func listSortedObjects(ctx context.Context, ri dynamic.ResourceInterface, labelSelector string) ([]unstructured.Unstructured, error) {
+	list, err := ri.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	items := list.Items
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+	return items, nil
+}
+
+// ingressInterface returns the resource interface for ingresses in the namespace.
+func (l *ListIngressPathsTool) ingressInterface(apiResourceLists []*metav1.APIResourceList, namespace string) (dynamic.ResourceInterface, error) {
 	// Discover the ingress resource GVR
-	gvrMatch, err := l.discoverIngressResource()
+	gvrMatch, err := findGVRByKind(apiResourceLists, "Ingress")
 	if err != nil {
 		return nil, err
 	}
 
 	// Get resource interface
-	ri, err := l.client.ResourceInterface(*gvrMatch.ToGroupVersionResource(), gvrMatch.namespaced, input.Namespace)
+	ri, err := l.client.ResourceInterface(*gvrMatch.ToGroupVersionResource(), gvrMatch.namespaced, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource interface: %w", err)
 	}
+	return ri, nil
+}
 
-	// Get the ingress resource
-	ingress, err := ri.Get(ctx, input.Name, metav1.GetOptions{})
+// httpRouteInterface returns the resource interface for HTTPRoutes in the namespace.
+func (l *ListIngressPathsTool) httpRouteInterface(apiResourceLists []*metav1.APIResourceList, namespace string) (dynamic.ResourceInterface, error) {
+	gvr, err := gatewayAPIResource(apiResourceLists, httpRouteGK.Kind)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get ingress %s in namespace %s: %w", input.Name, input.Namespace, err)
+		return nil, err
 	}
-
-	return ingress, nil
+	ri, err := l.client.ResourceInterface(gvr, true, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource interface: %w", err)
+	}
+	return ri, nil
 }
 
-// discoverIngressResource discovers the ingress resource GVR.
-func (l *ListIngressPathsTool) discoverIngressResource() (*gvrMatch, error) {
+// discoverResources returns the preferred API resources served by the cluster.
+func (l *ListIngressPathsTool) discoverResources() ([]*metav1.APIResourceList, error) {
 	discoClient, err := l.client.DiscoClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create discovery client: %w", err)
@@ -120,17 +308,154 @@ func (l *ListIngressPathsTool) discoverIngressResource() (*gvrMatch, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover resources: %w", err)
 	}
+	return apiResourceLists, nil
+}
+
+// gatewayContext is what HTTPRoute paths are reported with: the Gateways by namespace/name, the
+// policies by target and what could not be listed.
+type gatewayContext struct {
+	gateways map[string]*unstructured.Unstructured
+	policies map[string][]string
+	problems []string
+}
+
+// gatewayContext lists the Gateways of all namespaces and the policies that attach to Gateway API
+// objects. Failures are reported as problems rather than failing the call.
+func (l *ListIngressPathsTool) gatewayContext(ctx context.Context, apiResourceLists []*metav1.APIResourceList) *gatewayContext {
+	result := &gatewayContext{gateways: map[string]*unstructured.Unstructured{}}
+	gvr, err := gatewayAPIResource(apiResourceLists, gatewayGK.Kind)
+	if err == nil {
+		var ri dynamic.ResourceInterface
+		if ri, err = l.client.ResourceInterface(gvr, true, ""); err == nil {
+			var list *unstructured.UnstructuredList
+			if list, err = ri.List(ctx, metav1.ListOptions{}); err == nil {
+				for i := range list.Items {
+					gw := &list.Items[i]
+					result.gateways[gw.GetNamespace()+"/"+gw.GetName()] = gw
+				}
+			}
+		}
+	}
+	if err != nil {
+		result.problems = append(result.problems, fmt.Sprintf("Gateways not listed: %v", err))
+	}
+	policies, policyErrors := listGatewayPolicies(ctx, l.client, apiResourceLists)
+	result.policies = gatewayPoliciesByTarget(policies)
+	for _, e := range policyErrors {
+		result.problems = append(result.problems, "policies not listed: "+e)
+	}
+	return result
+}
 
-	return findGVRByKind(apiResourceLists, "Ingress")
+// httpRoutePaths reports the paths of an HTTPRoute with its parent Gateways and policies.
+func (g *gatewayContext) httpRoutePaths(route *unstructured.Unstructured) *HTTPRoutePathsResponse {
+	response := &HTTPRoutePathsResponse{
+		RouteName: route.GetName(),
+		Namespace: route.GetNamespace(),
+		Parents:   []HTTPRouteParent{},
+		Paths:     httpRoutePaths(route),
+		Policies:  g.policies[fmt.Sprintf("HTTPRoute/%s/%s", route.GetNamespace(), route.GetName())],
+	}
+	for _, ref := range httpRouteParentRefs(route) {
+		parent := HTTPRouteParent{
+			Gateway:     ref.String(),
+			SectionName: ref.SectionName,
+			Conditions:  parentConditions(route, ref),
+		}
+		if gw, ok := g.gateways[ref.Namespace+"/"+ref.Name]; ok && ref.Group == gatewayAPIGroup && ref.Kind == "Gateway" {
+			parent.GatewayClass, _, _ = unstructured.NestedString(gw.Object, "spec", "gatewayClassName")
+			addresses, _, _ := unstructured.NestedSlice(gw.Object, "status", "addresses")
+			for _, a := range addresses {
+				if addr, ok := a.(map[string]any); ok {
+					if v, _ := addr["value"].(string); v != "" {
+						parent.Addresses = append(parent.Addresses, v)
+					}
+				}
+			}
+			parent.Policies = g.policies[fmt.Sprintf("Gateway/%s/%s", ref.Namespace, ref.Name)]
+		}
+		response.Parents = append(response.Parents, parent)
+	}
+	return response
 }
 
-// extractIngressPaths extracts all paths from the ingress resource.
-func (l *ListIngressPathsTool) extractIngressPaths(ingress *unstructured.Unstructured, input *ListIngressPathsInput) (*IngressPathsResponse, error) {
+// httpRoutePaths flattens the rules of an HTTPRoute into one path per hostname and match, like the
+// rules of an ingress. A route without hostnames serves every host of its listeners.
+func httpRoutePaths(route *unstructured.Unstructured) []IngressPath {
+	hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	if len(hostnames) == 0 {
+		hostnames = []string{""}
+	}
+	paths := []IngressPath{}
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	for _, r := range rules {
+		raw, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		rule := httpRouteRule(route, raw)
+		serviceName := ""
+		for _, ref := range rule.BackendRefs {
+			if ref.Kind == "Service" {
+				serviceName = ref.Name
+				break
+			}
+		}
+		var backends []GatewayBackendRef
+		if len(rule.BackendRefs) != 1 || rule.BackendRefs[0].Kind != "Service" || rule.BackendRefs[0].Namespace != route.GetNamespace() {
+			backends = rule.BackendRefs
+		}
+
+		matches, _, _ := unstructured.NestedSlice(raw, "matches")
+		if len(matches) == 0 {
+			matches = []any{map[string]any{}}
+		}
+		for _, m := range matches {
+			match, ok := m.(map[string]any)
+			if !ok {
+				continue
+			}
+			pathType, value := httpRouteMatchPath(match)
+			for _, host := range hostnames {
+				paths = append(paths, IngressPath{
+					Host:        host,
+					Path:        value,
+					PathType:    pathType,
+					ServiceName: serviceName,
+					Match:       strings.Join(httpRouteMatchConditions(match), " "),
+					Filters:     rule.Filters,
+					Backends:    backends,
+				})
+			}
+		}
+	}
+	return paths
+}
+
+// extractIngressPaths extracts all paths, TLS entries and notable annotations from the ingress resource.
+func (l *ListIngressPathsTool) extractIngressPaths(ctx context.Context, ingress *unstructured.Unstructured) (*IngressPathsResponse, error) {
 	response := &IngressPathsResponse{
-		IngressName: input.Name,
-		Namespace:   input.Namespace,
+		IngressName: ingress.GetName(),
+		Namespace:   ingress.GetNamespace(),
 		Paths:       []IngressPath{},
 	}
+	for key, value := range ingress.GetAnnotations() {
+		if notableIngressAnnotations[key] {
+			if response.Annotations == nil {
+				response.Annotations = map[string]string{}
+			}
+			response.Annotations[key] = value
+		}
+	}
+	response.IngressClassName, _, _ = unstructured.NestedString(ingress.Object, "spec", "ingressClassName")
+	if response.IngressClassName == "" {
+		response.IngressClassName = ingress.GetAnnotations()["kubernetes.io/ingress.class"]
+	}
+	tls, err := l.extractIngressTLS(ctx, ingress)
+	if err != nil {
+		return nil, err
+	}
+	response.TLS = tls
 
 	// Extract spec from the ingress
 	spec, found, err := unstructured.NestedMap(ingress.Object, "spec")
@@ -157,6 +482,8 @@ func (l *ListIngressPathsTool) extractIngressPaths(ingress *unstructured.Unstruc
 			continue
 		}
 
+		host, _, _ := unstructured.NestedString(ruleMap, "host")
+
 		// Get HTTP paths
 		httpPaths, found, err := unstructured.NestedSlice(ruleMap, "http", "paths")
 		if err != nil || !found {
@@ -170,7 +497,7 @@ func (l *ListIngressPathsTool) extractIngressPaths(ingress *unstructured.Unstruc
 				continue
 			}
 
-			ingressPath := IngressPath{}
+			ingressPath := IngressPath{Host: host}
 
 			// Extract path
 			if path, found, _ := unstructured.NestedString(pathMap, "path"); found {
@@ -203,3 +530,104 @@ func (l *ListIngressPathsTool) extractIngressPaths(ingress *unstructured.Unstruc
 
 	return response, nil
 }
+
+// extractIngressTLS lists the TLS entries of the ingress and checks that each certificate Secret exists.
+func (l *ListIngressPathsTool) extractIngressTLS(ctx context.Context, ingress *unstructured.Unstructured) ([]IngressTLS, error) {
+	entries, found, err := unstructured.NestedSlice(ingress.Object, "spec", "tls")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingress tls: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	var secrets dynamic.ResourceInterface
+	result := make([]IngressTLS, 0, len(entries))
+	for _, entry := range entries {
+		entryMap, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tls := IngressTLS{}
+		tls.Hosts, _, _ = unstructured.NestedStringSlice(entryMap, "hosts")
+		tls.SecretName, _, _ = unstructured.NestedString(entryMap, "secretName")
+		if tls.SecretName == "" {
+			tls.Error = "no secretName: the controller serves its default certificate"
+			result = append(result, tls)
+			continue
+		}
+
+		if secrets == nil {
+			if secrets, err = l.client.ResourceInterface(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, true, ingress.GetNamespace()); err != nil {
+				return nil, fmt.Errorf("failed to create resource interface: %w", err)
+			}
+		}
+		_, err := secrets.Get(ctx, tls.SecretName, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			tls.SecretExists = true
+		case apierrors.IsNotFound(err):
+			tls.Error = fmt.Sprintf("secret %s does not exist; the controller serves its default certificate", tls.SecretName)
+		default:
+			tls.Error = fmt.Sprintf("failed to get secret %s: %v", tls.SecretName, err)
+		}
+		result = append(result, tls)
+	}
+	return result, nil
+}
+
+func parseAndValidateListIngressPathsParams(args map[string]any) (*ListIngressPathsInput, error) {
+	input := &ListIngressPathsInput{}
+
+	if kind, ok := args["kind"].(string); ok && kind != "" {
+		switch strings.ToLower(kind) {
+		case "ingress", "ingresses":
+			input.Kind = "Ingress"
+		case "httproute", "httproutes":
+			input.Kind = "HTTPRoute"
+		default:
+			return nil, fmt.Errorf("invalid kind %q: must be Ingress or HTTPRoute", kind)
+		}
+	}
+	if name, ok := args["name"].(string); ok {
+		input.Name = name
+	}
+	if namespace, ok := args["namespace"].(string); ok {
+		input.Namespace = namespace
+	}
+	if labelSelector, ok := args["labelSelector"].(string); ok {
+		input.LabelSelector = labelSelector
+	}
+
+	// Environment variables only fill in what the call leaves out
+	if input.Name == "" && input.LabelSelector == "" && input.Kind != "HTTPRoute" {
+		input.Name = os.Getenv("INGRESS_NAME")
+	}
+	if input.Namespace == "" {
+		input.Namespace = os.Getenv("INGRESS_NAMESPACE")
+	}
+	if input.Namespace == "" && input.Name != "" {
+		input.Namespace = "default"
+	}
+
+	if input.Name != "" && input.LabelSelector != "" {
+		return nil, errors.New("name and labelSelector are mutually exclusive")
+	}
+	if input.Name != "" {
+		if err := validation.ValidateResourceName(input.Name); err != nil {
+			return nil, fmt.Errorf("invalid name: %w", err)
+		}
+	}
+	if input.Namespace != "" {
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.LabelSelector != "" {
+		if err := validation.ValidateLabelSelector(input.LabelSelector); err != nil {
+			return nil, fmt.Errorf("invalid labelSelector: %w", err)
+		}
+	}
+
+	return input, nil
+}
@@ -0,0 +1,259 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+type fakeIngressClient struct {
+	FakeKubernetesClient
+	objects []runtime.Object
+}
+
+func (f fakeIngressClient) DiscoClient() (discovery.DiscoveryInterface, error) {
+	return &fakeDiscoveryClient{apiResourceLists: []*metav1.APIResourceList{{
+		GroupVersion: "networking.k8s.io/v1",
+		APIResources: []metav1.APIResource{{Kind: "Ingress", Name: "ingresses", Namespaced: true}},
+	}}}, nil
+}
+
+func (f fakeIngressClient) ResourceInterface(gvr schema.GroupVersionResource, namespaced bool, ns string) (dynamic.ResourceInterface, error) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "IngressList"}, f.objects...)
+	return client.Resource(gvr).Namespace(ns), nil
+}
+
+func testIngress(namespace, name, app, host, path, service string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind":       "Ingress",
+		"metadata":   map[string]any{"name": name, "namespace": namespace, "labels": map[string]any{"app": app}},
+		"spec": map[string]any{"rules": []any{map[string]any{
+			"host": host,
+			"http": map[string]any{"paths": []any{map[string]any{
+				"path":     path,
+				"pathType": "Prefix",
+				"backend":  map[string]any{"service": map[string]any{"name": service}},
+			}}},
+		}}},
+	}}
+}
+
+func TestParseAndValidateListIngressPathsParams(t *testing.T) {
+	input, err := parseAndValidateListIngressPathsParams(map[string]any{"name": "web"})
+	assert.NoError(t, err)
+	assert.Equal(t, &ListIngressPathsInput{Name: "web", Namespace: "default"}, input)
+
+	input, err = parseAndValidateListIngressPathsParams(map[string]any{"namespace": "prod", "labelSelector": "app=web"})
+	assert.NoError(t, err)
+	assert.Equal(t, &ListIngressPathsInput{Namespace: "prod", LabelSelector: "app=web"}, input)
+
+	t.Setenv("INGRESS_NAME", "legacy")
+	t.Setenv("INGRESS_NAMESPACE", "staging")
+	input, err = parseAndValidateListIngressPathsParams(map[string]any{})
+	assert.NoError(t, err)
+	assert.Equal(t, &ListIngressPathsInput{Name: "legacy", Namespace: "staging"}, input)
+
+	input, err = parseAndValidateListIngressPathsParams(map[string]any{"labelSelector": "app=web"})
+	assert.NoError(t, err)
+	assert.Equal(t, &ListIngressPathsInput{Namespace: "staging", LabelSelector: "app=web"}, input)
+
+	_, err = parseAndValidateListIngressPathsParams(map[string]any{"name": "web", "labelSelector": "app=web"})
+	assert.ErrorContains(t, err, "mutually exclusive")
+}
+
+func TestListIngressPathsHandler(t *testing.T) {
+	client := fakeIngressClient{objects: []runtime.Object{
+		testIngress("prod", "web", "web", "web.example.com", "/", "web"),
+		testIngress("prod", "api", "api", "api.example.com", "/v1", "api"),
+		testIngress("dev", "web", "web", "web.dev.example.com", "/", "web"),
+	}}
+	tool := NewListIngressPathsTool(client)
+
+	call := func(args map[string]any) string {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), req)
+		assert.NoError(t, err)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	assert.JSONEq(t, `{"ingressName":"api","namespace":"prod","paths":[{"host":"api.example.com","path":"/v1","pathType":"Prefix","serviceName":"api"}]}`,
+		call(map[string]any{"name": "api", "namespace": "prod"}))
+
+	assert.JSONEq(t, `{"namespace":"prod","labelSelector":"","ingresses":[
+		{"ingressName":"api","namespace":"prod","paths":[{"host":"api.example.com","path":"/v1","pathType":"Prefix","serviceName":"api"}]},
+		{"ingressName":"web","namespace":"prod","paths":[{"host":"web.example.com","path":"/","pathType":"Prefix","serviceName":"web"}]}]}`,
+		call(map[string]any{"namespace": "prod"}))
+
+	assert.JSONEq(t, `{"namespace":"","labelSelector":"app=web","ingresses":[
+		{"ingressName":"web","namespace":"dev","paths":[{"host":"web.dev.example.com","path":"/","pathType":"Prefix","serviceName":"web"}]},
+		{"ingressName":"web","namespace":"prod","paths":[{"host":"web.example.com","path":"/","pathType":"Prefix","serviceName":"web"}]}]}`,
+		call(map[string]any{"labelSelector": "app=web"}))
+}
+
+func TestListIngressPathsTLSAndAnnotations(t *testing.T) {
+	ingress := testIngress("prod", "web", "web", "web.example.com", "/api(/|$)(.*)", "web")
+	ingress.SetAnnotations(map[string]string{
+		"nginx.ingress.kubernetes.io/rewrite-target": "/$2",
+		"nginx.ingress.kubernetes.io/ssl-redirect":   "true",
+		"meta.helm.sh/release-name":                  "web",
+	})
+	_ = unstructured.SetNestedField(ingress.Object, "nginx", "spec", "ingressClassName")
+	_ = unstructured.SetNestedSlice(ingress.Object, []any{
+		map[string]any{"hosts": []any{"web.example.com"}, "secretName": "web-tls"},
+		map[string]any{"hosts": []any{"old.example.com"}, "secretName": "old-tls"},
+	}, "spec", "tls")
+	secret := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "web-tls", "namespace": "prod"},
+	}}
+
+	tool := NewListIngressPathsTool(fakeIngressClient{objects: []runtime.Object{ingress, secret}})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "web", "namespace": "prod"}
+	result, err := tool.Handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"ingressName":"web","namespace":"prod","ingressClassName":"nginx",
+		"paths":[{"host":"web.example.com","path":"/api(/|$)(.*)","pathType":"Prefix","serviceName":"web"}],
+		"tls":[
+			{"hosts":["web.example.com"],"secretName":"web-tls","secretExists":true},
+			{"hosts":["old.example.com"],"secretName":"old-tls","secretExists":false,"error":"secret old-tls does not exist; the controller serves its default certificate"}],
+		"annotations":{"nginx.ingress.kubernetes.io/rewrite-target":"/$2","nginx.ingress.kubernetes.io/ssl-redirect":"true"}}`,
+		result.Content[0].(mcp.TextContent).Text)
+}
+
+// fakeGatewayIngressClient also serves the Gateway API and a Gateway policy.
+type fakeGatewayIngressClient struct {
+	fakeIngressClient
+}
+
+func (f fakeGatewayIngressClient) DiscoClient() (discovery.DiscoveryInterface, error) {
+	return &fakeDiscoveryClient{apiResourceLists: []*metav1.APIResourceList{
+		{
+			GroupVersion: "networking.k8s.io/v1",
+			APIResources: []metav1.APIResource{{Kind: "Ingress", Name: "ingresses", Namespaced: true}},
+		},
+		{
+			GroupVersion: "gateway.networking.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Kind: "Gateway", Name: "gateways", Namespaced: true},
+				{Kind: "HTTPRoute", Name: "httproutes", Namespaced: true},
+				{Kind: "HTTPRoute", Name: "httproutes/status", Namespaced: true},
+			},
+		},
+		{
+			GroupVersion: "gateway.networking.k8s.io/v1alpha3",
+			APIResources: []metav1.APIResource{{Kind: "BackendTLSPolicy", Name: "backendtlspolicies", Namespaced: true}},
+		},
+	}}, nil
+}
+
+// ResourceInterface tracks the objects under explicit resources, since the fake dynamic client
+// would guess "gatewaies" for Gateways.
+func (f fakeGatewayIngressClient) ResourceInterface(gvr schema.GroupVersionResource, namespaced bool, ns string) (dynamic.ResourceInterface, error) {
+	resources := map[string]string{"Ingress": "ingresses", "Secret": "secrets", "HTTPRoute": "httproutes", "Gateway": "gateways", "BackendTLSPolicy": "backendtlspolicies"}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "List"})
+	for _, obj := range f.objects {
+		u := obj.(*unstructured.Unstructured)
+		if err := client.Tracker().Create(u.GroupVersionKind().GroupVersion().WithResource(resources[u.GetKind()]), u, u.GetNamespace()); err != nil {
+			return nil, err
+		}
+	}
+	return client.Resource(gvr).Namespace(ns), nil
+}
+
+func testHTTPRoute() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]any{"name": "shop", "namespace": "prod", "labels": map[string]any{"app": "web"}},
+		"spec": map[string]any{
+			"parentRefs": []any{map[string]any{"name": "public", "namespace": "infra", "sectionName": "https"}},
+			"hostnames":  []any{"shop.example.com"},
+			"rules": []any{
+				map[string]any{
+					"matches": []any{map[string]any{
+						"path":    map[string]any{"type": "PathPrefix", "value": "/api"},
+						"method":  "GET",
+						"headers": []any{map[string]any{"name": "x-env", "value": "canary"}},
+					}},
+					"filters": []any{map[string]any{"type": "URLRewrite"}},
+					"backendRefs": []any{
+						map[string]any{"name": "api-v1", "port": int64(8080), "weight": int64(90)},
+						map[string]any{"name": "api-v2", "port": int64(8080), "weight": int64(10)},
+					},
+				},
+				map[string]any{"backendRefs": []any{map[string]any{"name": "web", "port": int64(80)}}},
+			},
+		},
+		"status": map[string]any{"parents": []any{map[string]any{
+			"parentRef":  map[string]any{"name": "public", "namespace": "infra", "sectionName": "https"},
+			"conditions": []any{map[string]any{"type": "Accepted", "status": "True"}},
+		}}},
+	}}
+}
+
+func TestListIngressPathsHTTPRoutes(t *testing.T) {
+	gateway := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "Gateway",
+		"metadata":   map[string]any{"name": "public", "namespace": "infra"},
+		"spec":       map[string]any{"gatewayClassName": "envoy"},
+		"status":     map[string]any{"addresses": []any{map[string]any{"value": "203.0.113.10"}}},
+	}}
+	policy := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1alpha3",
+		"kind":       "BackendTLSPolicy",
+		"metadata":   map[string]any{"name": "shop-tls", "namespace": "prod"},
+		"spec":       map[string]any{"targetRefs": []any{map[string]any{"kind": "HTTPRoute", "name": "shop"}}},
+	}}
+	client := fakeGatewayIngressClient{fakeIngressClient{objects: []runtime.Object{
+		testIngress("prod", "web", "web", "web.example.com", "/", "web"),
+		testHTTPRoute(), gateway, policy,
+	}}}
+	tool := NewListIngressPathsTool(client)
+
+	call := func(args map[string]any) string {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), req)
+		require.NoError(t, err)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+	route := `{"routeName":"shop","namespace":"prod",
+		"parents":[{"gateway":"infra/public","sectionName":"https","gatewayClass":"envoy","addresses":["203.0.113.10"],"conditions":["Accepted=True"]}],
+		"paths":[
+			{"host":"shop.example.com","path":"/api","pathType":"PathPrefix","serviceName":"api-v1","match":"GET header:x-env=canary","filters":["URLRewrite"],
+			 "backends":[{"kind":"Service","name":"api-v1","namespace":"prod","port":8080,"weight":90},{"kind":"Service","name":"api-v2","namespace":"prod","port":8080,"weight":10}]},
+			{"host":"shop.example.com","path":"/","pathType":"PathPrefix","serviceName":"web"}],
+		"policies":["BackendTLSPolicy/prod/shop-tls"]}`
+
+	assert.JSONEq(t, route, call(map[string]any{"kind": "HTTPRoute", "name": "shop", "namespace": "prod"}))
+
+	assert.JSONEq(t, `{"namespace":"prod","labelSelector":"app=web",
+		"ingresses":[{"ingressName":"web","namespace":"prod","paths":[{"host":"web.example.com","path":"/","pathType":"Prefix","serviceName":"web"}]}],
+		"httpRoutes":[`+route+`]}`,
+		call(map[string]any{"namespace": "prod", "labelSelector": "app=web"}))
+
+	assert.JSONEq(t, `{"namespace":"prod","labelSelector":"","ingresses":[{"ingressName":"web","namespace":"prod","paths":[{"host":"web.example.com","path":"/","pathType":"Prefix","serviceName":"web"}]}]}`,
+		call(map[string]any{"namespace": "prod", "kind": "Ingress"}))
+
+	// Without the Gateway API, asking for HTTPRoutes fails.
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"kind": "HTTPRoute"}
+	_, err := NewListIngressPathsTool(client.fakeIngressClient).Handler(context.Background(), req)
+	assert.EqualError(t, err, "HTTPRoute.gateway.networking.k8s.io is not served by the cluster (are the Gateway API CRDs installed?)")
+}
@@ -0,0 +1,390 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Limits applied to loki_query results.
+const (
+	defaultLokiLimit   = 100
+	maxLokiLimit       = 1000
+	maxLokiLineLength  = 2000
+	lokiRequestTimeout = 30 * time.Second
+)
+
+// lokiLabelNamePattern matches valid Loki (Prometheus-style) label names.
+var lokiLabelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// LokiQueryInput represents the input for a Loki log query.
+type LokiQueryInput struct {
+	Query     string            `json:"query"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Contains  string            `json:"contains,omitempty"`
+	Start     time.Time         `json:"start"`
+	End       time.Time         `json:"end"`
+	Limit     int               `json:"limit"`
+	Direction string            `json:"direction"`
+}
+
+// LokiEntry is one log line returned by Loki.
+type LokiEntry struct {
+	Time   string `json:"time"`
+	Stream int    `json:"stream"`
+	Line   string `json:"line"`
+}
+
+// lokiEndpoint is where Loki is reached: LOKI_URL directly, or LOKI_SERVICE ("namespace/name:port")
+// through the API server service proxy for Loki installations that are not exposed outside the cluster.
+type lokiEndpoint struct {
+	url       string
+	namespace string
+	service   string
+	port      string
+	tenant    string
+	token     string
+	username  string
+	password  string
+}
+
+// lokiEndpointFromEnv reads the Loki endpoint configuration from the environment.
+func lokiEndpointFromEnv() (*lokiEndpoint, error) {
+	endpoint := &lokiEndpoint{
+		url:      strings.TrimRight(os.Getenv("LOKI_URL"), "/"),
+		tenant:   os.Getenv("LOKI_TENANT_ID"),
+		token:    os.Getenv("LOKI_BEARER_TOKEN"),
+		username: os.Getenv("LOKI_USERNAME"),
+		password: os.Getenv("LOKI_PASSWORD"),
+	}
+	if endpoint.url != "" {
+		return endpoint, nil
+	}
+	service := os.Getenv("LOKI_SERVICE")
+	if service == "" {
+		return nil, errors.New("loki is not configured: set LOKI_URL (e.g. http://loki-gateway.monitoring:80) or LOKI_SERVICE (e.g. monitoring/loki-gateway:80)")
+	}
+	namespace, rest, ok := strings.Cut(service, "/")
+	if !ok || namespace == "" || rest == "" {
+		return nil, fmt.Errorf("invalid LOKI_SERVICE %q: expected namespace/name[:port]", service)
+	}
+	endpoint.namespace = namespace
+	endpoint.service, endpoint.port, _ = strings.Cut(rest, ":")
+	return endpoint, nil
+}
+
+// query sends a GET request for the Loki API path and returns the response body.
+func (e *lokiEndpoint) query(ctx context.Context, clientset kubernetes.Interface, path string, params url.Values) ([]byte, error) {
+	if e.url == "" {
+		// The service proxy does not forward custom headers, so only unauthenticated gateways work this way.
+		flat := make(map[string]string, len(params))
+		for k := range params {
+			flat[k] = params.Get(k)
+		}
+		body, err := clientset.CoreV1().Services(e.namespace).ProxyGet("http", e.service, e.port, path, flat).DoRaw(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query loki through service %s/%s: %w", e.namespace, e.service, err)
+		}
+		return body, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lokiRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build loki request: %w", err)
+	}
+	if e.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", e.tenant)
+	}
+	switch {
+	case e.token != "":
+		req.Header.Set("Authorization", "Bearer "+e.token)
+	case e.username != "":
+		req.SetBasicAuth(e.username, e.password)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query loki: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loki response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loki returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// buildLogQLQuery builds a stream selector from the labels with an optional line filter.
+func buildLogQLQuery(labels map[string]string, contains string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	matchers := make([]string, 0, len(keys))
+	for _, k := range keys {
+		matchers = append(matchers, k+"="+strconv.Quote(labels[k]))
+	}
+	query := "{" + strings.Join(matchers, ", ") + "}"
+	if contains != "" {
+		query += " |= " + strconv.Quote(contains)
+	}
+	return query
+}
+
+// lokiQueryResponse is the body of /loki/api/v1/query_range.
+type lokiQueryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// lokiStream is one stream of a "streams" result.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// parseLokiStreams flattens log streams into entries ordered by time in the query direction, capped at
+// limit. Streams are returned separately and referenced by index so their labels are not repeated per line.
+func parseLokiStreams(raw json.RawMessage, direction string, limit int) ([]map[string]string, []LokiEntry, error) {
+	var streams []lokiStream
+	if err := json.Unmarshal(raw, &streams); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode loki streams: %w", err)
+	}
+
+	type entry struct {
+		ns int64
+		LokiEntry
+	}
+	labels := make([]map[string]string, 0, len(streams))
+	var entries []entry
+	for i, s := range streams {
+		labels = append(labels, s.Stream)
+		for _, v := range s.Values {
+			ns, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid loki timestamp %q: %w", v[0], err)
+			}
+			line := v[1]
+			if len(line) > maxLokiLineLength {
+				line = line[:maxLokiLineLength] + "...(truncated)"
+			}
+			entries = append(entries, entry{ns: ns, LokiEntry: LokiEntry{
+				Time:   time.Unix(0, ns).UTC().Format(time.RFC3339Nano),
+				Stream: i,
+				Line:   line,
+			}})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if direction == "forward" {
+			return entries[i].ns < entries[j].ns
+		}
+		return entries[i].ns > entries[j].ns
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	result := make([]LokiEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.LokiEntry)
+	}
+	return labels, result, nil
+}
+
+// LokiQueryTool runs LogQL queries against a configured Loki.
+type LokiQueryTool struct {
+	client Client
+}
+
+// NewLokiQueryTool creates a new LokiQueryTool with the provided Kubernetes client.
+func NewLokiQueryTool(client Client) *LokiQueryTool {
+	return &LokiQueryTool{client: client}
+}
+
+// Tool returns the MCP tool definition for loki_query.
+func (l *LokiQueryTool) Tool() mcp.Tool {
+	return mcp.NewTool("loki_query",
+		mcp.WithDescription("Query centralized logs in Grafana Loki with LogQL, or by labels such as namespace, pod and container plus a text filter. Useful for logs of pods that no longer exist or across many pods. Requires LOKI_URL or LOKI_SERVICE to be configured"),
+		mcp.WithString("query",
+			mcp.Description("LogQL query, e.g. '{namespace=\"prod\", app=\"api\"} |= \"error\"' (alternative to labels)"),
+		),
+		mcp.WithObject("labels",
+			mcp.Description("Stream labels to match exactly, e.g. {\"namespace\": \"prod\", \"pod\": \"api-7d9c\"}; used when query is not set"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("contains",
+			mcp.Description("Only return lines containing this text (used with labels)"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Relative start of the time range like 15m or 6h (default: 1h)"),
+		),
+		mcp.WithString("start",
+			mcp.Description("Start of the time range (RFC3339), overrides since"),
+		),
+		mcp.WithString("end",
+			mcp.Description("End of the time range (RFC3339, default: now)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of lines to return (default: 100, max: 1000)"),
+		),
+		mcp.WithString("direction",
+			mcp.Description("'backward' returns the newest lines first (default), 'forward' the oldest first"),
+			mcp.Enum("backward", "forward"),
+		),
+	)
+}
+
+// Handler sends the query to Loki and returns the matching lines.
+func (l *LokiQueryTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateLokiQueryParams(req.Params.Arguments, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate loki_query params: %w", err)
+	}
+	endpoint, err := lokiEndpointFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	var clientset kubernetes.Interface
+	if endpoint.url == "" {
+		if clientset, err = l.client.Clientset(); err != nil {
+			return nil, fmt.Errorf("failed to get clientset: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("query", input.Query)
+	params.Set("start", strconv.FormatInt(input.Start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(input.End.UnixNano(), 10))
+	params.Set("limit", strconv.Itoa(input.Limit))
+	params.Set("direction", input.Direction)
+	body, err := endpoint.query(ctx, clientset, "/loki/api/v1/query_range", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp lokiQueryResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode loki response: %w", err)
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("loki query failed with status %q", resp.Status)
+	}
+
+	result := map[string]any{
+		"query":      input.Query,
+		"start":      input.Start.UTC().Format(time.RFC3339),
+		"end":        input.End.UTC().Format(time.RFC3339),
+		"resultType": resp.Data.ResultType,
+	}
+	if resp.Data.ResultType == "streams" {
+		streams, entries, err := parseLokiStreams(resp.Data.Result, input.Direction, input.Limit)
+		if err != nil {
+			return nil, err
+		}
+		result["streams"] = streams
+		result["entries"] = entries
+		result["lines"] = len(entries)
+		result["limitReached"] = len(entries) >= input.Limit
+	} else {
+		// Metric queries (count_over_time, rate, ...) return matrices or vectors; pass them through.
+		result["result"] = resp.Data.Result
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseAndValidateLokiQueryParams validates and parses the input parameters.
+func parseAndValidateLokiQueryParams(args map[string]any, now time.Time) (*LokiQueryInput, error) {
+	input := &LokiQueryInput{Limit: defaultLokiLimit, Direction: "backward"}
+
+	var err error
+	input.Query, _ = args["query"].(string)
+	input.Contains, _ = args["contains"].(string)
+	if input.Labels, err = stringMapArg(args, "labels"); err != nil {
+		return nil, err
+	}
+	if input.Query == "" {
+		if len(input.Labels) == 0 {
+			return nil, errors.New("either query or labels must be provided")
+		}
+		for k := range input.Labels {
+			if !lokiLabelNamePattern.MatchString(k) {
+				return nil, fmt.Errorf("invalid label name %q", k)
+			}
+		}
+		input.Query = buildLogQLQuery(input.Labels, input.Contains)
+	}
+
+	if input.Start, input.End, err = parseTimeRangeArgs(args, now); err != nil {
+		return nil, err
+	}
+
+	if v, ok := args["limit"].(float64); ok && v > 0 {
+		input.Limit = int(v)
+	}
+	if input.Limit > maxLokiLimit {
+		input.Limit = maxLokiLimit
+	}
+	if direction, _ := args["direction"].(string); direction != "" {
+		input.Direction = strings.ToLower(direction)
+		if input.Direction != "backward" && input.Direction != "forward" {
+			return nil, errors.New("direction must be backward or forward")
+		}
+	}
+
+	return input, nil
+}
+
+// parseTimeRangeArgs reads the since, start and end arguments shared by the log query tools. The range
+// defaults to the hour before end, and end defaults to now.
+func parseTimeRangeArgs(args map[string]any, now time.Time) (time.Time, time.Time, error) {
+	var err error
+	end := now
+	if v, _ := args["end"].(string); v != "" {
+		if end, err = time.Parse(time.RFC3339, v); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end: %w", err)
+		}
+	}
+	start := end.Add(-time.Hour)
+	if since, _ := args["since"].(string); since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil || d <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid since %q: must be a positive duration such as 15m", since)
+		}
+		start = end.Add(-d)
+	}
+	if v, _ := args["start"].(string); v != "" {
+		if start, err = time.Parse(time.RFC3339, v); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start: %w", err)
+		}
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.New("start must be before end")
+	}
+	return start, end, nil
+}
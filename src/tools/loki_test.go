@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildLogQLQuery(t *testing.T) {
+	query := buildLogQLQuery(map[string]string{"pod": "api-7d9c", "namespace": "prod"}, `level="error"`)
+	assert.Equal(t, `{namespace="prod", pod="api-7d9c"} |= "level=\"error\""`, query)
+	assert.Equal(t, `{app="web"}`, buildLogQLQuery(map[string]string{"app": "web"}, ""))
+}
+
+func TestParseLokiStreams(t *testing.T) {
+	raw := json.RawMessage(`[
+		{"stream": {"pod": "api-1"}, "values": [["1700000000000000000", "first"], ["1700000002000000000", "third"]]},
+		{"stream": {"pod": "api-2"}, "values": [["1700000001000000000", "second"]]}
+	]`)
+
+	streams, entries, err := parseLokiStreams(raw, "backward", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"pod": "api-1"}, {"pod": "api-2"}}, streams)
+	assert.Equal(t, []LokiEntry{
+		{Time: "2023-11-14T22:13:22Z", Stream: 0, Line: "third"},
+		{Time: "2023-11-14T22:13:21Z", Stream: 1, Line: "second"},
+	}, entries)
+
+	_, entries, err = parseLokiStreams(raw, "forward", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", entries[0].Line)
+	assert.Len(t, entries, 3)
+}
+
+func TestParseAndValidateLokiQueryParams(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	input, err := parseAndValidateLokiQueryParams(map[string]any{
+		"labels":   map[string]any{"namespace": "prod"},
+		"contains": "timeout",
+		"since":    "15m",
+		"limit":    float64(5000),
+	}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, `{namespace="prod"} |= "timeout"`, input.Query)
+	assert.Equal(t, now.Add(-15*time.Minute), input.Start)
+	assert.Equal(t, maxLokiLimit, input.Limit)
+	assert.Equal(t, "backward", input.Direction)
+
+	_, err = parseAndValidateLokiQueryParams(map[string]any{}, now)
+	assert.Error(t, err)
+	_, err = parseAndValidateLokiQueryParams(map[string]any{"labels": map[string]any{"k8s-app": "x"}}, now)
+	assert.Error(t, err)
+	_, err = parseAndValidateLokiQueryParams(map[string]any{"query": `{app="x"}`, "start": "2025-01-02T00:00:00Z"}, now)
+	assert.Error(t, err)
+}
+
+func TestLokiEndpointQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/query_range", r.URL.Path)
+		assert.Equal(t, `{app="x"}`, r.URL.Query().Get("query"))
+		assert.Equal(t, "team-a", r.Header.Get("X-Scope-OrgID"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer server.Close()
+
+	endpoint := &lokiEndpoint{url: server.URL, tenant: "team-a", token: "secret"}
+	body, err := endpoint.query(context.Background(), nil, "/loki/api/v1/query_range", url.Values{"query": {`{app="x"}`}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"status":"success"}`, string(body))
+
+	t.Setenv("LOKI_URL", "")
+	t.Setenv("LOKI_SERVICE", "monitoring/loki-gateway:80")
+	endpoint, err = lokiEndpointFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "monitoring", endpoint.namespace)
+	assert.Equal(t, "loki-gateway", endpoint.service)
+	assert.Equal(t, "80", endpoint.port)
+}
@@ -61,7 +61,17 @@ func (f *fakeDiscoveryClient) SwaggerSchema(version string) (*metav1.APIResource
 	return nil, fmt.Errorf("not implemented")
 }
 func (f *fakeDiscoveryClient) ServerPreferredNamespacedResources() ([]*metav1.APIResourceList, error) {
-	return nil, fmt.Errorf("not implemented")
+	var namespaced []*metav1.APIResourceList
+	for _, list := range f.apiResourceLists {
+		filtered := &metav1.APIResourceList{GroupVersion: list.GroupVersion}
+		for _, r := range list.APIResources {
+			if r.Namespaced {
+				filtered.APIResources = append(filtered.APIResources, r)
+			}
+		}
+		namespaced = append(namespaced, filtered)
+	}
+	return namespaced, nil
 }
 func (f *fakeDiscoveryClient) ServerPreferredResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
 	return f.ServerResourcesForGroupVersion(groupVersion)
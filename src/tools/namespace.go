@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Environment variables used by this tool:
+// Optional:
+//   NAMESPACE_BUNDLE_FILE - Path to a multi-document YAML file with the objects to create in every
+//                           new namespace when applyBundle is set (defaults to defaultNamespaceBundle)
+
+// defaultNamespaceBundle is applied when applyBundle is requested and NAMESPACE_BUNDLE_FILE is not set.
+const defaultNamespaceBundle = `
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: default-quota
+spec:
+  hard:
+    requests.cpu: "4"
+    requests.memory: 8Gi
+    limits.cpu: "8"
+    limits.memory: 16Gi
+    pods: "50"
+---
+apiVersion: v1
+kind: LimitRange
+metadata:
+  name: default-limits
+spec:
+  limits:
+  - type: Container
+    default:
+      cpu: 500m
+      memory: 512Mi
+    defaultRequest:
+      cpu: 100m
+      memory: 128Mi
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-same-namespace
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - podSelector: {}
+`
+
+// yamlDocumentSeparator splits multi-document YAML on lines containing only '---'.
+var yamlDocumentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// CreateNamespaceInput represents the input for creating a namespace.
+type CreateNamespaceInput struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	ApplyBundle bool              `json:"applyBundle,omitempty"`
+}
+
+// CreateNamespaceTool creates namespaces with an optional standard bundle of policy objects.
+type CreateNamespaceTool struct {
+	client Client
+}
+
+// NewCreateNamespaceTool creates a new CreateNamespaceTool with the provided Kubernetes client.
+func NewCreateNamespaceTool(client Client) *CreateNamespaceTool {
+	return &CreateNamespaceTool{client: client}
+}
+
+// Tool returns the MCP tool definition for create_namespace.
+func (c *CreateNamespaceTool) Tool() mcp.Tool {
+	return mcp.NewTool("create_namespace",
+		mcp.WithDescription("Create a Kubernetes namespace with labels and annotations, optionally applying the standard ResourceQuota/LimitRange/NetworkPolicy bundle"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the namespace to create"),
+		),
+		mcp.WithObject("labels",
+			mcp.Description("Labels to set on the namespace, e.g. {\"team\": \"payments\"}"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithObject("annotations",
+			mcp.Description("Annotations to set on the namespace"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("applyBundle",
+			mcp.Description("Also create the standard ResourceQuota, LimitRange and NetworkPolicy bundle (from NAMESPACE_BUNDLE_FILE if set) in the new namespace (default: false)"),
+		),
+	)
+}
+
+// Handler creates the namespace and, if requested, the bundle objects inside it.
+func (c *CreateNamespaceTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateCreateNamespaceParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate namespace params: %w", err)
+	}
+
+	var bundle []*unstructured.Unstructured
+	if input.ApplyBundle {
+		bundle, err = loadNamespaceBundle()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        input.Name,
+			Labels:      input.Labels,
+			Annotations: input.Annotations,
+		},
+	}
+	if _, err := clientset.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create namespace %s: %w", input.Name, err)
+	}
+
+	result := map[string]any{
+		"status":    "Namespace created",
+		"namespace": input.Name,
+		"labels":    input.Labels,
+	}
+
+	if input.ApplyBundle {
+		created, failed := c.applyBundle(ctx, input.Name, bundle)
+		result["bundleCreated"] = created
+		if len(failed) > 0 {
+			result["bundleFailed"] = failed
+			result["status"] = "Namespace created but some bundle objects failed"
+		}
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// applyBundle creates each bundle object in the namespace and reports which ones succeeded.
+func (c *CreateNamespaceTool) applyBundle(ctx context.Context, namespace string, bundle []*unstructured.Unstructured) ([]string, []string) {
+	var created, failed []string
+
+	mapper, err := c.client.RESTMapper()
+	if err != nil {
+		return nil, []string{fmt.Sprintf("failed to create REST mapper: %v", err)}
+	}
+
+	for _, obj := range bundle {
+		obj = obj.DeepCopy()
+		obj.SetNamespace(namespace)
+		ref := obj.GetKind() + "/" + obj.GetName()
+
+		gvk := obj.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", ref, err))
+			continue
+		}
+		ri, err := c.client.ResourceInterface(mapping.Resource, true, namespace)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", ref, err))
+			continue
+		}
+		if _, err := ri.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", ref, err))
+			continue
+		}
+		created = append(created, ref)
+	}
+	return created, failed
+}
+
+// loadNamespaceBundle reads the bundle from NAMESPACE_BUNDLE_FILE, falling back to the default bundle.
+func loadNamespaceBundle() ([]*unstructured.Unstructured, error) {
+	data := []byte(defaultNamespaceBundle)
+	if path := os.Getenv("NAMESPACE_BUNDLE_FILE"); path != "" {
+		fileData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read NAMESPACE_BUNDLE_FILE: %w", err)
+		}
+		data = fileData
+	}
+	return parseYAMLObjects(data)
+}
+
+// parseYAMLObjects decodes a multi-document YAML stream into unstructured objects, skipping empty documents.
+func parseYAMLObjects(data []byte) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for i, doc := range yamlDocumentSeparator.Split(string(data), -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		obj := map[string]any{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML document %d: %w", i+1, err)
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		u := &unstructured.Unstructured{Object: obj}
+		if u.GetKind() == "" || u.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("YAML document %d is missing apiVersion or kind", i+1)
+		}
+		objects = append(objects, u)
+	}
+	return objects, nil
+}
+
+// parseAndValidateCreateNamespaceParams validates and parses the input parameters.
+func parseAndValidateCreateNamespaceParams(args map[string]any) (*CreateNamespaceInput, error) {
+	input := &CreateNamespaceInput{}
+
+	if name, ok := args["name"].(string); ok {
+		input.Name = name
+	}
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateNamespace(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+
+	labels, err := stringMapArg(args, "labels")
+	if err != nil {
+		return nil, err
+	}
+	input.Labels = labels
+
+	annotations, err := stringMapArg(args, "annotations")
+	if err != nil {
+		return nil, err
+	}
+	input.Annotations = annotations
+
+	if applyBundle, ok := args["applyBundle"].(bool); ok {
+		input.ApplyBundle = applyBundle
+	}
+
+	return input, nil
+}
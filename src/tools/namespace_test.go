@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseYAMLObjects(t *testing.T) {
+	objects, err := parseYAMLObjects([]byte(defaultNamespaceBundle))
+	assert.NoError(t, err)
+	if assert.Len(t, objects, 3) {
+		assert.Equal(t, "ResourceQuota", objects[0].GetKind())
+		assert.Equal(t, "LimitRange", objects[1].GetKind())
+		assert.Equal(t, "NetworkPolicy", objects[2].GetKind())
+		assert.Equal(t, "networking.k8s.io/v1", objects[2].GetAPIVersion())
+	}
+
+	objects, err = parseYAMLObjects([]byte("---\n# comment only\n---\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, objects)
+
+	_, err = parseYAMLObjects([]byte("metadata:\n  name: missing-kind\n"))
+	assert.Error(t, err)
+}
+
+func TestParseAndValidateCreateNamespaceParams(t *testing.T) {
+	input, err := parseAndValidateCreateNamespaceParams(map[string]any{
+		"name":        "team-a",
+		"labels":      map[string]any{"team": "a"},
+		"applyBundle": true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "a"}, input.Labels)
+	assert.True(t, input.ApplyBundle)
+
+	_, err = parseAndValidateCreateNamespaceParams(map[string]any{"name": "team-a", "labels": map[string]any{"replicas": 3}})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateCreateNamespaceParams(map[string]any{"name": "Team_A"})
+	assert.Error(t, err)
+}
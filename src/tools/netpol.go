@@ -0,0 +1,387 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// NetpolAnalyzeInput represents the input for analysing traffic between two pods.
+type NetpolAnalyzeInput struct {
+	SourcePod            string          `json:"sourcePod"`
+	SourceNamespace      string          `json:"sourceNamespace"`
+	DestinationPod       string          `json:"destinationPod"`
+	DestinationNamespace string          `json:"destinationNamespace"`
+	Port                 int32           `json:"port"`
+	Protocol             corev1.Protocol `json:"protocol"`
+}
+
+// NetpolRuleMatch reports how one NetworkPolicy treated the analysed traffic.
+type NetpolRuleMatch struct {
+	Policy  string `json:"policy"`
+	Rule    int    `json:"rule,omitempty"`
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+}
+
+// NetpolDirection is the verdict for one side of the connection: egress from the source or ingress to the destination.
+type NetpolDirection struct {
+	Isolated bool              `json:"isolated"`
+	Allowed  bool              `json:"allowed"`
+	Policies []NetpolRuleMatch `json:"policies"`
+}
+
+// netpolTraffic is a single connection attempt between two pods.
+type netpolTraffic struct {
+	src, dst     *corev1.Pod
+	srcNS, dstNS *corev1.Namespace
+	port         int32
+	protocol     corev1.Protocol
+}
+
+// NetpolAnalyzeTool evaluates NetworkPolicies for traffic between two pods.
+type NetpolAnalyzeTool struct {
+	client Client
+}
+
+// NewNetpolAnalyzeTool creates a new NetpolAnalyzeTool with the provided Kubernetes client.
+func NewNetpolAnalyzeTool(client Client) *NetpolAnalyzeTool {
+	return &NetpolAnalyzeTool{client: client}
+}
+
+// Tool returns the MCP tool definition for netpol_analyze.
+func (n *NetpolAnalyzeTool) Tool() mcp.Tool {
+	return mcp.NewTool("netpol_analyze",
+		mcp.WithDescription("Evaluate the NetworkPolicies that select a source and a destination pod and report whether traffic to the given port is allowed, which policies isolate each pod, and which rules allowed or failed to match the connection"),
+		mcp.WithString("sourcePod",
+			mcp.Required(),
+			mcp.Description("Name of the pod opening the connection"),
+		),
+		mcp.WithString("sourceNamespace",
+			mcp.Description("Namespace of the source pod (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("destinationPod",
+			mcp.Required(),
+			mcp.Description("Name of the pod receiving the connection"),
+		),
+		mcp.WithString("destinationNamespace",
+			mcp.Description("Namespace of the destination pod (defaults to the source namespace)"),
+		),
+		mcp.WithNumber("port",
+			mcp.Required(),
+			mcp.Description("Destination port number"),
+		),
+		mcp.WithString("protocol",
+			mcp.Description("Protocol of the connection: TCP, UDP or SCTP (default: TCP)"),
+		),
+	)
+}
+
+// Handler evaluates egress policies of the source and ingress policies of the destination.
+func (n *NetpolAnalyzeTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateNetpolAnalyzeParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate netpol_analyze params: %w", err)
+	}
+
+	clientset, err := n.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	traffic := &netpolTraffic{port: input.Port, protocol: input.Protocol}
+	if traffic.src, err = clientset.CoreV1().Pods(input.SourceNamespace).Get(ctx, input.SourcePod, metav1.GetOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to get pod %s/%s: %w", input.SourceNamespace, input.SourcePod, err)
+	}
+	if traffic.dst, err = clientset.CoreV1().Pods(input.DestinationNamespace).Get(ctx, input.DestinationPod, metav1.GetOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to get pod %s/%s: %w", input.DestinationNamespace, input.DestinationPod, err)
+	}
+	if traffic.srcNS, err = clientset.CoreV1().Namespaces().Get(ctx, input.SourceNamespace, metav1.GetOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", input.SourceNamespace, err)
+	}
+	if traffic.dstNS, err = clientset.CoreV1().Namespaces().Get(ctx, input.DestinationNamespace, metav1.GetOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", input.DestinationNamespace, err)
+	}
+
+	srcPolicies, err := clientset.NetworkingV1().NetworkPolicies(input.SourceNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list networkpolicies in %s: %w", input.SourceNamespace, err)
+	}
+	dstPolicies := srcPolicies
+	if input.DestinationNamespace != input.SourceNamespace {
+		dstPolicies, err = clientset.NetworkingV1().NetworkPolicies(input.DestinationNamespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list networkpolicies in %s: %w", input.DestinationNamespace, err)
+		}
+	}
+
+	egress := evaluateNetworkPolicies(srcPolicies.Items, traffic, networkingv1.PolicyTypeEgress)
+	ingress := evaluateNetworkPolicies(dstPolicies.Items, traffic, networkingv1.PolicyTypeIngress)
+
+	result := map[string]any{
+		"source":      input.SourceNamespace + "/" + input.SourcePod,
+		"destination": fmt.Sprintf("%s/%s (%s) port %d/%s", input.DestinationNamespace, input.DestinationPod, traffic.dst.Status.PodIP, input.Port, input.Protocol),
+		"egress":      egress,
+		"ingress":     ingress,
+		"allowed":     egress.Allowed && ingress.Allowed,
+	}
+	notes := []string{"results assume the CNI plugin enforces NetworkPolicy; without one all traffic is allowed"}
+	if traffic.src.Spec.HostNetwork || traffic.dst.Spec.HostNetwork {
+		notes = append(notes, "a hostNetwork pod is involved; NetworkPolicies generally do not apply to host-network traffic")
+	}
+	if traffic.src.Status.PodIP == "" || traffic.dst.Status.PodIP == "" {
+		notes = append(notes, "a pod has no IP yet; ipBlock peers cannot match it")
+	}
+	result["notes"] = notes
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// evaluateNetworkPolicies decides one direction of the traffic. A pod is only isolated for a
+// direction when some policy selects it for that policy type; traffic is then allowed if any
+// rule of any selecting policy matches the peer and the port.
+func evaluateNetworkPolicies(policies []networkingv1.NetworkPolicy, traffic *netpolTraffic, policyType networkingv1.PolicyType) NetpolDirection {
+	subject, peer, peerNS := traffic.dst, traffic.src, traffic.srcNS
+	if policyType == networkingv1.PolicyTypeEgress {
+		subject, peer, peerNS = traffic.src, traffic.dst, traffic.dstNS
+	}
+
+	result := NetpolDirection{Policies: []NetpolRuleMatch{}}
+	for i := range policies {
+		policy := &policies[i]
+		if policy.Namespace != subject.Namespace || !policyHasType(policy, policyType) {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+		if err != nil || !selector.Matches(labels.Set(subject.Labels)) {
+			continue
+		}
+		result.Isolated = true
+
+		var rules []netpolRule
+		if policyType == networkingv1.PolicyTypeEgress {
+			for _, r := range policy.Spec.Egress {
+				rules = append(rules, netpolRule{peers: r.To, ports: r.Ports})
+			}
+		} else {
+			for _, r := range policy.Spec.Ingress {
+				rules = append(rules, netpolRule{peers: r.From, ports: r.Ports})
+			}
+		}
+		if len(rules) == 0 {
+			result.Policies = append(result.Policies, NetpolRuleMatch{Policy: policy.Name, Reason: fmt.Sprintf("policy has no %s rules and denies all %s traffic", strings.ToLower(string(policyType)), strings.ToLower(string(policyType)))})
+			continue
+		}
+
+		matched := false
+		var misses []string
+		for ruleIndex, rule := range rules {
+			reason, ok := rule.matches(policy.Namespace, peer, peerNS, traffic)
+			if ok {
+				matched = true
+				result.Policies = append(result.Policies, NetpolRuleMatch{Policy: policy.Name, Rule: ruleIndex + 1, Allowed: true, Reason: reason})
+				break
+			}
+			misses = append(misses, fmt.Sprintf("rule %d: %s", ruleIndex+1, reason))
+		}
+		if !matched {
+			result.Policies = append(result.Policies, NetpolRuleMatch{Policy: policy.Name, Reason: strings.Join(misses, "; ")})
+		}
+	}
+
+	result.Allowed = !result.Isolated
+	for _, m := range result.Policies {
+		result.Allowed = result.Allowed || m.Allowed
+	}
+	return result
+}
+
+// policyHasType applies the API defaulting for policyTypes: Ingress is always implied, and
+// Egress is implied when the policy has egress rules.
+func policyHasType(policy *networkingv1.NetworkPolicy, policyType networkingv1.PolicyType) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return policyType == networkingv1.PolicyTypeIngress || len(policy.Spec.Egress) > 0
+	}
+	for _, t := range policy.Spec.PolicyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+// netpolRule is an ingress or egress rule with its peers and ports.
+type netpolRule struct {
+	peers []networkingv1.NetworkPolicyPeer
+	ports []networkingv1.NetworkPolicyPort
+}
+
+// matches reports whether the rule allows the peer on the traffic's port, with the reason.
+func (r netpolRule) matches(policyNamespace string, peer *corev1.Pod, peerNS *corev1.Namespace, traffic *netpolTraffic) (string, bool) {
+	if !netpolPortsMatch(r.ports, traffic) {
+		return fmt.Sprintf("port %d/%s is not listed", traffic.port, traffic.protocol), false
+	}
+	if len(r.peers) == 0 {
+		return "rule allows all peers on this port", true
+	}
+	for i, p := range r.peers {
+		if netpolPeerMatches(p, policyNamespace, peer, peerNS) {
+			return fmt.Sprintf("peer %d matches %s/%s", i+1, peer.Namespace, peer.Name), true
+		}
+	}
+	return fmt.Sprintf("no peer selects %s/%s", peer.Namespace, peer.Name), false
+}
+
+// netpolPortsMatch reports whether the port list admits the traffic. Named ports are resolved
+// against the destination pod's container ports.
+func netpolPortsMatch(ports []networkingv1.NetworkPolicyPort, traffic *netpolTraffic) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, p := range ports {
+		protocol := corev1.ProtocolTCP
+		if p.Protocol != nil {
+			protocol = *p.Protocol
+		}
+		if protocol != traffic.protocol {
+			continue
+		}
+		if p.Port == nil {
+			return true
+		}
+		if p.Port.Type == intstr.String {
+			if namedPortNumber(traffic.dst, p.Port.StrVal, protocol) == traffic.port {
+				return true
+			}
+			continue
+		}
+		end := p.Port.IntVal
+		if p.EndPort != nil {
+			end = *p.EndPort
+		}
+		if traffic.port >= p.Port.IntVal && traffic.port <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// namedPortNumber returns the container port number for a named port, or 0 when it is not defined.
+func namedPortNumber(pod *corev1.Pod, name string, protocol corev1.Protocol) int32 {
+	for _, c := range pod.Spec.Containers {
+		for _, cp := range c.Ports {
+			cpProtocol := cp.Protocol
+			if cpProtocol == "" {
+				cpProtocol = corev1.ProtocolTCP
+			}
+			if cp.Name == name && cpProtocol == protocol {
+				return cp.ContainerPort
+			}
+		}
+	}
+	return 0
+}
+
+// netpolPeerMatches evaluates a single peer. A podSelector alone selects pods in the policy's
+// namespace; combined with a namespaceSelector it selects pods in the matching namespaces.
+func netpolPeerMatches(peer networkingv1.NetworkPolicyPeer, policyNamespace string, pod *corev1.Pod, podNS *corev1.Namespace) bool {
+	if peer.IPBlock != nil {
+		return ipBlockContains(peer.IPBlock, pod.Status.PodIP)
+	}
+	if peer.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+		if err != nil || !selector.Matches(labels.Set(podNS.Labels)) {
+			return false
+		}
+	} else if pod.Namespace != policyNamespace {
+		return false
+	}
+	if peer.PodSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(peer.PodSelector)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ipBlockContains reports whether ip is inside the block's CIDR and none of its exceptions.
+func ipBlockContains(block *networkingv1.IPBlock, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	_, cidr, err := net.ParseCIDR(block.CIDR)
+	if err != nil || !cidr.Contains(addr) {
+		return false
+	}
+	for _, except := range block.Except {
+		if _, ex, err := net.ParseCIDR(except); err == nil && ex.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAndValidateNetpolAnalyzeParams validates and parses the input parameters.
+func parseAndValidateNetpolAnalyzeParams(args map[string]any) (*NetpolAnalyzeInput, error) {
+	input := &NetpolAnalyzeInput{Protocol: corev1.ProtocolTCP}
+
+	input.SourcePod, _ = args["sourcePod"].(string)
+	input.DestinationPod, _ = args["destinationPod"].(string)
+	if input.SourcePod == "" || input.DestinationPod == "" {
+		return nil, errors.New("sourcePod and destinationPod must be provided")
+	}
+	for _, name := range []string{input.SourcePod, input.DestinationPod} {
+		if err := validation.ValidateResourceName(name); err != nil {
+			return nil, fmt.Errorf("invalid pod name: %w", err)
+		}
+	}
+
+	input.SourceNamespace, _ = args["sourceNamespace"].(string)
+	if input.SourceNamespace == "" {
+		input.SourceNamespace = metav1.NamespaceDefault
+	}
+	input.DestinationNamespace, _ = args["destinationNamespace"].(string)
+	if input.DestinationNamespace == "" {
+		input.DestinationNamespace = input.SourceNamespace
+	}
+	for _, ns := range []string{input.SourceNamespace, input.DestinationNamespace} {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+
+	port, ok := args["port"].(float64)
+	if !ok || port < 1 || port > 65535 {
+		return nil, errors.New("port must be a number between 1 and 65535")
+	}
+	input.Port = int32(port)
+
+	if p, ok := args["protocol"].(string); ok && p != "" {
+		input.Protocol = corev1.Protocol(strings.ToUpper(p))
+	}
+	switch input.Protocol {
+	case corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", input.Protocol)
+	}
+
+	return input, nil
+}
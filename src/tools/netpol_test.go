@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestEvaluateNetworkPolicies(t *testing.T) {
+	traffic := &netpolTraffic{
+		src: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "web", Labels: map[string]string{"app": "frontend"}}},
+		dst: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "backend", Labels: map[string]string{"app": "api"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}}}},
+			Status:     corev1.PodStatus{PodIP: "10.1.2.3"},
+		},
+		srcNS:    &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"team": "web"}}},
+		dstNS:    &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "backend"}},
+		port:     8080,
+		protocol: corev1.ProtocolTCP,
+	}
+	httpPort := intstr.FromString("http")
+	denyAll := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-deny", Namespace: "backend"},
+		Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}},
+	}
+	allowWeb := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "backend"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+				{
+					From:  []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}}}},
+					Ports: []networkingv1.NetworkPolicyPort{{Port: &httpPort}},
+				},
+			},
+		},
+	}
+
+	ingress := evaluateNetworkPolicies([]networkingv1.NetworkPolicy{denyAll}, traffic, networkingv1.PolicyTypeIngress)
+	assert.True(t, ingress.Isolated)
+	assert.False(t, ingress.Allowed)
+
+	ingress = evaluateNetworkPolicies([]networkingv1.NetworkPolicy{denyAll, allowWeb}, traffic, networkingv1.PolicyTypeIngress)
+	assert.True(t, ingress.Allowed)
+	if assert.Len(t, ingress.Policies, 2) {
+		assert.Equal(t, NetpolRuleMatch{Policy: "allow-web", Rule: 2, Allowed: true, Reason: "peer 1 matches web/frontend"}, ingress.Policies[1])
+	}
+
+	traffic.port = 9090
+	ingress = evaluateNetworkPolicies([]networkingv1.NetworkPolicy{allowWeb}, traffic, networkingv1.PolicyTypeIngress)
+	assert.False(t, ingress.Allowed)
+
+	egress := evaluateNetworkPolicies([]networkingv1.NetworkPolicy{allowWeb}, traffic, networkingv1.PolicyTypeEgress)
+	assert.False(t, egress.Isolated)
+	assert.True(t, egress.Allowed)
+}
+
+func TestIPBlockContains(t *testing.T) {
+	block := &networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}
+
+	assert.True(t, ipBlockContains(block, "10.2.0.1"))
+	assert.False(t, ipBlockContains(block, "10.1.0.1"))
+	assert.False(t, ipBlockContains(block, "192.168.0.1"))
+	assert.False(t, ipBlockContains(block, ""))
+}
@@ -0,0 +1,464 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// mirrorPodAnnotation marks static pods mirrored by the kubelet; they cannot be evicted.
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// CordonNodeInput represents the input for cordoning or uncordoning a node.
+type CordonNodeInput struct {
+	Node string `json:"node"`
+}
+
+// DrainNodeInput represents the input for draining a node.
+type DrainNodeInput struct {
+	Node               string `json:"node"`
+	IgnoreDaemonSets   bool   `json:"ignoreDaemonSets"`
+	DeleteEmptyDirData bool   `json:"deleteEmptyDirData,omitempty"`
+	Force              bool   `json:"force,omitempty"`
+	GracePeriodSeconds int64  `json:"gracePeriodSeconds,omitempty"`
+	TimeoutSeconds     int64  `json:"timeoutSeconds,omitempty"`
+	DryRun             bool   `json:"dryRun,omitempty"`
+}
+
+// DrainPod describes a pod considered during a drain.
+type DrainPod struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// drainPlan is the result of classifying the pods running on a node.
+type drainPlan struct {
+	evict    []corev1.Pod
+	skipped  []DrainPod
+	blockers []DrainPod
+}
+
+// CordonTool marks a node as unschedulable (or schedulable again).
+type CordonTool struct {
+	client        Client
+	unschedulable bool
+}
+
+// NewCordonTool creates a tool that cordons a node.
+func NewCordonTool(client Client) *CordonTool {
+	return &CordonTool{client: client, unschedulable: true}
+}
+
+// NewUncordonTool creates a tool that uncordons a node.
+func NewUncordonTool(client Client) *CordonTool {
+	return &CordonTool{client: client, unschedulable: false}
+}
+
+// Tool returns the MCP tool definition for cordon_node or uncordon_node.
+func (c *CordonTool) Tool() mcp.Tool {
+	if c.unschedulable {
+		return mcp.NewTool("cordon_node",
+			mcp.WithDescription("Mark a Kubernetes node as unschedulable (like 'kubectl cordon')"),
+			mcp.WithString("node",
+				mcp.Required(),
+				mcp.Description("Name of the node to cordon"),
+			),
+		)
+	}
+	return mcp.NewTool("uncordon_node",
+		mcp.WithDescription("Mark a Kubernetes node as schedulable again (like 'kubectl uncordon')"),
+		mcp.WithString("node",
+			mcp.Required(),
+			mcp.Description("Name of the node to uncordon"),
+		),
+	)
+}
+
+// Handler patches spec.unschedulable on the node.
+func (c *CordonTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateCordonParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate cordon params: %w", err)
+	}
+
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	changed, err := setNodeUnschedulable(ctx, clientset, input.Node, c.unschedulable)
+	if err != nil {
+		return nil, err
+	}
+
+	status := "Node uncordoned"
+	if c.unschedulable {
+		status = "Node cordoned"
+	}
+	if !changed {
+		status += " (no change)"
+	}
+
+	result := map[string]any{
+		"status":        status,
+		"node":          input.Node,
+		"unschedulable": c.unschedulable,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// DrainTool evicts pods from a node, respecting PodDisruptionBudgets.
+type DrainTool struct {
+	client Client
+}
+
+// NewDrainTool creates a new DrainTool with the provided Kubernetes client.
+func NewDrainTool(client Client) *DrainTool {
+	return &DrainTool{client: client}
+}
+
+// Tool returns the MCP tool definition for drain_node.
+func (d *DrainTool) Tool() mcp.Tool {
+	return mcp.NewTool("drain_node",
+		mcp.WithDescription("Cordon a node and evict its pods using the Eviction API so PodDisruptionBudgets are honored (like 'kubectl drain'). Use dryRun to list the pods that would be evicted."),
+		mcp.WithString("node",
+			mcp.Required(),
+			mcp.Description("Name of the node to drain"),
+		),
+		mcp.WithBoolean("ignoreDaemonSets",
+			mcp.Description("Skip DaemonSet-managed pods instead of refusing to drain (default: true)"),
+		),
+		mcp.WithBoolean("deleteEmptyDirData",
+			mcp.Description("Allow evicting pods that use emptyDir volumes; their local data is lost (default: false)"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Allow evicting pods that are not managed by a controller (default: false)"),
+		),
+		mcp.WithNumber("gracePeriodSeconds",
+			mcp.Description("Grace period for each evicted pod in seconds (default: the pod's own terminationGracePeriodSeconds)"),
+		),
+		mcp.WithNumber("timeoutSeconds",
+			mcp.Description("How long to keep retrying evictions blocked by PodDisruptionBudgets and waiting for pods to terminate (default: 300)"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Only report which pods would be evicted or block the drain; do not cordon or evict (default: false)"),
+		),
+	)
+}
+
+// Handler cordons the node and evicts its pods.
+func (d *DrainTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateDrainParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate drain params: %w", err)
+	}
+
+	clientset, err := d.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	if _, err := clientset.CoreV1().Nodes().Get(ctx, input.Node, metav1.GetOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", input.Node, err)
+	}
+
+	podList, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", input.Node).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", input.Node, err)
+	}
+
+	plan := classifyDrainPods(podList.Items, input)
+
+	result := map[string]any{
+		"node":     input.Node,
+		"dryRun":   input.DryRun,
+		"skipped":  plan.skipped,
+		"blockers": plan.blockers,
+	}
+
+	if input.DryRun {
+		toEvict := make([]DrainPod, 0, len(plan.evict))
+		for _, pod := range plan.evict {
+			toEvict = append(toEvict, DrainPod{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+				Reason:    pdbReasonForPod(ctx, clientset, &pod),
+			})
+		}
+		result["status"] = "Dry run: no changes made"
+		result["wouldEvict"] = toEvict
+		return marshalDrainResult(result)
+	}
+
+	if len(plan.blockers) > 0 {
+		result["status"] = "Drain refused: some pods cannot be evicted with the given flags"
+		return marshalDrainResult(result)
+	}
+
+	if _, err := setNodeUnschedulable(ctx, clientset, input.Node, true); err != nil {
+		return nil, err
+	}
+
+	evicted, notTerminated, failed := evictPods(ctx, clientset, plan.evict, input)
+	result["evicted"] = evicted
+	result["notTerminated"] = notTerminated
+	result["failed"] = failed
+	if len(failed) > 0 {
+		result["status"] = "Node cordoned but some pods could not be evicted"
+	} else if len(notTerminated) > 0 {
+		result["status"] = "Node cordoned and pods evicted, but some have not terminated yet"
+	} else {
+		result["status"] = "Node drained"
+	}
+
+	return marshalDrainResult(result)
+}
+
+// setNodeUnschedulable patches spec.unschedulable and reports whether the value changed.
+func setNodeUnschedulable(ctx context.Context, clientset kubernetes.Interface, name string, unschedulable bool) (bool, error) {
+	nodes := clientset.CoreV1().Nodes()
+	node, err := nodes.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get node %s: %w", name, err)
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return false, nil
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable))
+	if _, err := nodes.Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return false, fmt.Errorf("failed to patch node %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// classifyDrainPods splits the pods on a node into those to evict, those to skip and those blocking the drain.
+func classifyDrainPods(pods []corev1.Pod, input *DrainNodeInput) drainPlan {
+	plan := drainPlan{}
+	for _, pod := range pods {
+		ref := DrainPod{Name: pod.Name, Namespace: pod.Namespace}
+
+		if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+			ref.Reason = "mirror (static) pod"
+			plan.skipped = append(plan.skipped, ref)
+			continue
+		}
+
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			plan.evict = append(plan.evict, pod)
+			continue
+		}
+
+		controller := metav1.GetControllerOf(&pod)
+		if controller != nil && controller.Kind == "DaemonSet" {
+			ref.Reason = "managed by DaemonSet " + controller.Name
+			if input.IgnoreDaemonSets {
+				plan.skipped = append(plan.skipped, ref)
+			} else {
+				plan.blockers = append(plan.blockers, ref)
+			}
+			continue
+		}
+
+		if controller == nil && !input.Force {
+			ref.Reason = "not managed by a controller (use force)"
+			plan.blockers = append(plan.blockers, ref)
+			continue
+		}
+
+		if podHasEmptyDir(&pod) && !input.DeleteEmptyDirData {
+			ref.Reason = "uses emptyDir volume (use deleteEmptyDirData)"
+			plan.blockers = append(plan.blockers, ref)
+			continue
+		}
+
+		plan.evict = append(plan.evict, pod)
+	}
+	return plan
+}
+
+// podHasEmptyDir reports whether the pod mounts any emptyDir volume.
+func podHasEmptyDir(pod *corev1.Pod) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// pdbReasonForPod returns a note naming the PodDisruptionBudgets that cover the pod, if any.
+func pdbReasonForPod(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) string {
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ""
+	}
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return fmt.Sprintf("covered by PodDisruptionBudget %s (disruptionsAllowed=%d)", pdb.Name, pdb.Status.DisruptionsAllowed)
+		}
+	}
+	return ""
+}
+
+// evictPods evicts each pod, retrying evictions rejected by a PodDisruptionBudget until the timeout
+// expires, and waits for the evicted pods to terminate. Each pod is reported once: evicted and gone,
+// evicted but not terminated by the deadline, or not evicted.
+func evictPods(ctx context.Context, clientset kubernetes.Interface, pods []corev1.Pod, input *DrainNodeInput) (evicted, notTerminated, failed []DrainPod) {
+	deadline := time.Now().Add(time.Duration(input.TimeoutSeconds) * time.Second)
+	var waiting []corev1.Pod
+
+	for _, pod := range pods {
+		ref := DrainPod{Name: pod.Name, Namespace: pod.Namespace}
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		}
+		if input.GracePeriodSeconds >= 0 {
+			grace := input.GracePeriodSeconds
+			eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: &grace}
+		}
+
+		for {
+			err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+			if err == nil || apierrors.IsNotFound(err) {
+				waiting = append(waiting, pod)
+				break
+			}
+			if apierrors.IsTooManyRequests(err) && time.Now().Before(deadline) {
+				select {
+				case <-ctx.Done():
+					ref.Reason = ctx.Err().Error()
+				case <-time.After(5 * time.Second):
+					continue
+				}
+			} else {
+				ref.Reason = err.Error()
+			}
+			failed = append(failed, ref)
+			break
+		}
+	}
+
+	for i := range waiting {
+		pod := &waiting[i]
+		ref := DrainPod{Name: pod.Name, Namespace: pod.Namespace}
+		if err := waitForPodDeletion(ctx, clientset, pod, deadline); err != nil {
+			ref.Reason = err.Error()
+			notTerminated = append(notTerminated, ref)
+			continue
+		}
+		evicted = append(evicted, ref)
+	}
+
+	return evicted, notTerminated, failed
+}
+
+// waitForPodDeletion polls until the evicted pod is gone or the deadline passes. A pod with the same
+// name but another UID is a replacement, such as a StatefulSet pod recreated on another node, so the
+// evicted pod is gone.
+func waitForPodDeletion(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, deadline time.Time) error {
+	for {
+		current, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) || err == nil && current.UID != pod.UID {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timed out waiting for pod to terminate")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func marshalDrainResult(result map[string]any) (*mcp.CallToolResult, error) {
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseAndValidateCordonParams validates and parses the input parameters.
+func parseAndValidateCordonParams(args map[string]any) (*CordonNodeInput, error) {
+	input := &CordonNodeInput{}
+
+	if node, ok := args["node"].(string); ok {
+		input.Node = node
+	}
+	if input.Node == "" {
+		return nil, errors.New("node must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Node); err != nil {
+		return nil, fmt.Errorf("invalid node name: %w", err)
+	}
+
+	return input, nil
+}
+
+// parseAndValidateDrainParams validates and parses the input parameters.
+func parseAndValidateDrainParams(args map[string]any) (*DrainNodeInput, error) {
+	input := &DrainNodeInput{
+		IgnoreDaemonSets:   true,
+		GracePeriodSeconds: -1,
+		TimeoutSeconds:     300,
+	}
+
+	if node, ok := args["node"].(string); ok {
+		input.Node = node
+	}
+	if input.Node == "" {
+		return nil, errors.New("node must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Node); err != nil {
+		return nil, fmt.Errorf("invalid node name: %w", err)
+	}
+
+	if v, ok := args["ignoreDaemonSets"].(bool); ok {
+		input.IgnoreDaemonSets = v
+	}
+	if v, ok := args["deleteEmptyDirData"].(bool); ok {
+		input.DeleteEmptyDirData = v
+	}
+	if v, ok := args["force"].(bool); ok {
+		input.Force = v
+	}
+	if v, ok := args["gracePeriodSeconds"].(float64); ok && v >= 0 {
+		input.GracePeriodSeconds = int64(v)
+	}
+	if v, ok := args["timeoutSeconds"].(float64); ok && v > 0 {
+		input.TimeoutSeconds = int64(v)
+	}
+	if v, ok := args["dryRun"].(bool); ok {
+		input.DryRun = v
+	}
+
+	return input, nil
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func drainTestPod(name string, ownerKind string) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if ownerKind != "" {
+		isController := true
+		pod.OwnerReferences = []metav1.OwnerReference{
+			{Kind: ownerKind, Name: name + "-owner", Controller: &isController},
+		}
+	}
+	return pod
+}
+
+func TestClassifyDrainPods(t *testing.T) {
+	mirror := drainTestPod("static", "")
+	mirror.Annotations = map[string]string{mirrorPodAnnotation: "abc"}
+
+	withEmptyDir := drainTestPod("cache", "ReplicaSet")
+	withEmptyDir.Spec.Volumes = []corev1.Volume{
+		{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+
+	completed := drainTestPod("done", "")
+	completed.Status.Phase = corev1.PodSucceeded
+
+	pods := []corev1.Pod{
+		drainTestPod("web", "ReplicaSet"),
+		drainTestPod("agent", "DaemonSet"),
+		drainTestPod("bare", ""),
+		mirror,
+		withEmptyDir,
+		completed,
+	}
+
+	testCases := []struct {
+		name             string
+		input            *DrainNodeInput
+		expectedEvict    []string
+		expectedSkipped  []string
+		expectedBlockers []string
+	}{
+		{
+			name:             "Defaults",
+			input:            &DrainNodeInput{IgnoreDaemonSets: true},
+			expectedEvict:    []string{"web", "done"},
+			expectedSkipped:  []string{"agent", "static"},
+			expectedBlockers: []string{"bare", "cache"},
+		},
+		{
+			name:             "ForceAndEmptyDir",
+			input:            &DrainNodeInput{IgnoreDaemonSets: true, Force: true, DeleteEmptyDirData: true},
+			expectedEvict:    []string{"web", "bare", "cache", "done"},
+			expectedSkipped:  []string{"agent", "static"},
+			expectedBlockers: nil,
+		},
+		{
+			name:             "DaemonSetsNotIgnored",
+			input:            &DrainNodeInput{Force: true, DeleteEmptyDirData: true},
+			expectedEvict:    []string{"web", "bare", "cache", "done"},
+			expectedSkipped:  []string{"static"},
+			expectedBlockers: []string{"agent"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := classifyDrainPods(pods, tc.input)
+
+			var evict, skipped, blockers []string
+			for _, p := range plan.evict {
+				evict = append(evict, p.Name)
+			}
+			for _, p := range plan.skipped {
+				skipped = append(skipped, p.Name)
+			}
+			for _, p := range plan.blockers {
+				blockers = append(blockers, p.Name)
+			}
+
+			assert.Equal(t, tc.expectedEvict, evict)
+			assert.Equal(t, tc.expectedSkipped, skipped)
+			assert.Equal(t, tc.expectedBlockers, blockers)
+		})
+	}
+}
+
+func TestParseAndValidateDrainParams(t *testing.T) {
+	input, err := parseAndValidateDrainParams(map[string]any{"node": "worker-1"})
+	assert.NoError(t, err)
+	assert.True(t, input.IgnoreDaemonSets)
+	assert.Equal(t, int64(300), input.TimeoutSeconds)
+	assert.Equal(t, int64(-1), input.GracePeriodSeconds)
+
+	input, err = parseAndValidateDrainParams(map[string]any{
+		"node":               "worker-1",
+		"ignoreDaemonSets":   false,
+		"gracePeriodSeconds": float64(0),
+		"dryRun":             true,
+	})
+	assert.NoError(t, err)
+	assert.False(t, input.IgnoreDaemonSets)
+	assert.Equal(t, int64(0), input.GracePeriodSeconds)
+	assert.True(t, input.DryRun)
+
+	_, err = parseAndValidateDrainParams(map[string]any{})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateDrainParams(map[string]any{"node": "Bad_Node"})
+	assert.Error(t, err)
+}
+
+func TestWaitForPodDeletion(t *testing.T) {
+	evicted := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default", UID: "old"}}
+	ctx := context.Background()
+	expired := time.Now().Add(-time.Second)
+
+	// A StatefulSet recreates its pod under the same name with a new UID.
+	replaced := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default", UID: "new"}})
+	assert.NoError(t, waitForPodDeletion(ctx, replaced, evicted, expired))
+
+	assert.NoError(t, waitForPodDeletion(ctx, fake.NewSimpleClientset(), evicted, expired))
+
+	terminating := fake.NewSimpleClientset(evicted.DeepCopy())
+	assert.EqualError(t, waitForPodDeletion(ctx, terminating, evicted, expired), "timed out waiting for pod to terminate")
+}
+
+func TestEvictPods(t *testing.T) {
+	stuck := drainTestPod("stuck", "ReplicaSet")
+	gone := drainTestPod("gone", "ReplicaSet")
+	protected := drainTestPod("protected", "ReplicaSet")
+	clientset := fake.NewSimpleClientset(stuck.DeepCopy(), protected.DeepCopy())
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() == "eviction" && action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction).Name == "protected" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "protected", errors.New("denied by webhook"))
+		}
+		return false, nil, nil
+	})
+
+	// The fake API accepts evictions without deleting pods, so the stuck pod outlives the deadline.
+	evicted, notTerminated, failed := evictPods(context.Background(), clientset, []corev1.Pod{stuck, gone, protected}, &DrainNodeInput{GracePeriodSeconds: -1})
+	assert.Equal(t, []DrainPod{{Name: "gone", Namespace: "default"}}, evicted)
+	assert.Equal(t, []DrainPod{{Name: "stuck", Namespace: "default", Reason: "timed out waiting for pod to terminate"}}, notTerminated)
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "protected", failed[0].Name)
+}
@@ -0,0 +1,550 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ObjectTreeInput represents the input for building the object tree of a resource.
+type ObjectTreeInput struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Format    string `json:"format"`
+}
+
+// ObjectTreeNode is one object in the nested tree, with the relation to its parent.
+type ObjectTreeNode struct {
+	Kind     string            `json:"kind"`
+	Name     string            `json:"name"`
+	Relation string            `json:"relation,omitempty"`
+	Status   string            `json:"status,omitempty"`
+	Missing  bool              `json:"missing,omitempty"`
+	SeeAbove bool              `json:"seeAbove,omitempty"`
+	Children []*ObjectTreeNode `json:"children,omitempty"`
+}
+
+// objectRef is a node of the object graph, keyed by "Kind/name".
+type objectRef struct {
+	Kind    string
+	Name    string
+	Status  string
+	Missing bool
+}
+
+// objectEdge connects two graph nodes with a relation such as "owns" or "selected by".
+type objectEdge struct {
+	From     string
+	To       string
+	Relation string
+}
+
+// objectGraph collects the objects around a resource and the relations between them.
+type objectGraph struct {
+	nodes map[string]*objectRef
+	edges []objectEdge
+	seen  map[objectEdge]bool
+}
+
+func newObjectGraph() *objectGraph {
+	return &objectGraph{nodes: map[string]*objectRef{}, seen: map[objectEdge]bool{}}
+}
+
+// add records a node, keeping the first status seen for it, and returns its key.
+func (g *objectGraph) add(kind, name, status string) string {
+	key := kind + "/" + name
+	if _, ok := g.nodes[key]; !ok {
+		g.nodes[key] = &objectRef{Kind: kind, Name: name, Status: status}
+	}
+	return key
+}
+
+// link records a relation once.
+func (g *objectGraph) link(from, to, relation string) {
+	edge := objectEdge{From: from, To: to, Relation: relation}
+	if !g.seen[edge] {
+		g.seen[edge] = true
+		g.edges = append(g.edges, edge)
+	}
+}
+
+// tree renders the graph as nested nodes starting at root. Objects reached a second time are
+// listed without children and marked seeAbove, which keeps shared ConfigMaps and Secrets compact.
+func (g *objectGraph) tree(root string) *ObjectTreeNode {
+	children := map[string][]objectEdge{}
+	for _, e := range g.edges {
+		children[e.From] = append(children[e.From], e)
+	}
+	visited := map[string]bool{}
+	var build func(key, relation string) *ObjectTreeNode
+	build = func(key, relation string) *ObjectTreeNode {
+		ref := g.nodes[key]
+		node := &ObjectTreeNode{Kind: ref.Kind, Name: ref.Name, Relation: relation, Status: ref.Status, Missing: ref.Missing}
+		if visited[key] {
+			node.SeeAbove = len(children[key]) > 0
+			return node
+		}
+		visited[key] = true
+		for _, e := range children[key] {
+			node.Children = append(node.Children, build(e.To, e.Relation))
+		}
+		return node
+	}
+	return build(root, "")
+}
+
+// dot renders the graph in Graphviz DOT format.
+func (g *objectGraph) dot() string {
+	keys := make([]string, 0, len(g.nodes))
+	for key := range g.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("digraph objects {\n  rankdir=LR;\n")
+	for _, key := range keys {
+		ref := g.nodes[key]
+		label := key
+		if ref.Status != "" {
+			label += "\n" + ref.Status
+		}
+		style := ""
+		if ref.Missing {
+			style = ", color=red, style=dashed"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q%s];\n", key, label, style)
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", e.From, e.To, e.Relation)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ObjectTreeTool builds the ownership and reference graph around a workload or pod.
+type ObjectTreeTool struct {
+	client Client
+}
+
+// NewObjectTreeTool creates a new ObjectTreeTool with the provided Kubernetes client.
+func NewObjectTreeTool(client Client) *ObjectTreeTool {
+	return &ObjectTreeTool{client: client}
+}
+
+// Tool returns the MCP tool definition for object_tree.
+func (o *ObjectTreeTool) Tool() mcp.Tool {
+	return mcp.NewTool("object_tree",
+		mcp.WithDescription("Build the ownership and reference graph around a Deployment, StatefulSet, DaemonSet or Pod: ReplicaSets and Pods it owns, the PVCs, ConfigMaps, Secrets and ServiceAccount the pods use (flagging missing ones), and the Services, Ingresses, HorizontalPodAutoscalers and PodDisruptionBudgets pointing at it. Returns nested JSON or Graphviz DOT for blast-radius analysis."),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description("Kind of the root object"),
+			mcp.Enum("Deployment", "StatefulSet", "DaemonSet", "Pod"),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the root object"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the object (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: json (nested tree, default) or dot (Graphviz)"),
+			mcp.Enum("json", "dot"),
+		),
+	)
+}
+
+// Handler builds and renders the graph.
+func (o *ObjectTreeTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateObjectTreeParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate object_tree params: %w", err)
+	}
+
+	clientset, err := o.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	g := newObjectGraph()
+	root, err := buildObjectGraph(ctx, clientset, input, g)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Format == "dot" {
+		return mcp.NewToolResultText(g.dot()), nil
+	}
+	missing := []string{}
+	for key, ref := range g.nodes {
+		if ref.Missing {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	result := map[string]any{
+		"namespace": input.Namespace,
+		"tree":      g.tree(root),
+		"objects":   len(g.nodes),
+		"missing":   missing,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// buildObjectGraph fills the graph for the requested root and returns the root's key.
+func buildObjectGraph(ctx context.Context, clientset kubernetes.Interface, input *ObjectTreeInput, g *objectGraph) (string, error) {
+	ns := input.Namespace
+	pods, err := clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", err)
+	}
+	podsOwnedBy := func(uid types.UID) []*corev1.Pod {
+		var owned []*corev1.Pod
+		for i := range pods.Items {
+			if metav1.IsControlledBy(&pods.Items[i], &metav1.ObjectMeta{UID: uid}) {
+				owned = append(owned, &pods.Items[i])
+			}
+		}
+		return owned
+	}
+
+	var root string
+	var podLabels map[string]string
+	var rootPods []*corev1.Pod
+	podParent := map[*corev1.Pod]string{}
+
+	switch input.Kind {
+	case "Deployment":
+		d, err := clientset.AppsV1().Deployments(ns).Get(ctx, input.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get deployment %s/%s: %w", ns, input.Name, err)
+		}
+		root = g.add("Deployment", d.Name, fmt.Sprintf("%d/%d ready", d.Status.ReadyReplicas, d.Status.Replicas))
+		podLabels = d.Spec.Template.Labels
+		replicaSets, err := clientset.AppsV1().ReplicaSets(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to list replicasets: %w", err)
+		}
+		for i := range replicaSets.Items {
+			rs := &replicaSets.Items[i]
+			if !metav1.IsControlledBy(rs, d) {
+				continue
+			}
+			owned := podsOwnedBy(rs.UID)
+			if rs.Status.Replicas == 0 && len(owned) == 0 {
+				continue
+			}
+			rsKey := g.add("ReplicaSet", rs.Name, fmt.Sprintf("%d/%d ready", rs.Status.ReadyReplicas, rs.Status.Replicas))
+			g.link(root, rsKey, "owns")
+			for _, pod := range owned {
+				podParent[pod] = rsKey
+				rootPods = append(rootPods, pod)
+			}
+		}
+	case "StatefulSet":
+		s, err := clientset.AppsV1().StatefulSets(ns).Get(ctx, input.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get statefulset %s/%s: %w", ns, input.Name, err)
+		}
+		root = g.add("StatefulSet", s.Name, fmt.Sprintf("%d/%d ready", s.Status.ReadyReplicas, s.Status.Replicas))
+		podLabels = s.Spec.Template.Labels
+		for _, pod := range podsOwnedBy(s.UID) {
+			podParent[pod] = root
+			rootPods = append(rootPods, pod)
+		}
+	case "DaemonSet":
+		d, err := clientset.AppsV1().DaemonSets(ns).Get(ctx, input.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get daemonset %s/%s: %w", ns, input.Name, err)
+		}
+		root = g.add("DaemonSet", d.Name, fmt.Sprintf("%d/%d ready", d.Status.NumberReady, d.Status.DesiredNumberScheduled))
+		podLabels = d.Spec.Template.Labels
+		for _, pod := range podsOwnedBy(d.UID) {
+			podParent[pod] = root
+			rootPods = append(rootPods, pod)
+		}
+	case "Pod":
+		pod, err := clientset.CoreV1().Pods(ns).Get(ctx, input.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get pod %s/%s: %w", ns, input.Name, err)
+		}
+		root = g.add("Pod", pod.Name, podTreeStatus(pod))
+		podLabels = pod.Labels
+		rootPods = append(rootPods, pod)
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			g.link(root, g.add(owner.Kind, owner.Name, ""), "owned by")
+		}
+	}
+
+	existence := map[string]bool{}
+	exists := func(kind, name string) bool {
+		key := kind + "/" + name
+		if found, ok := existence[key]; ok {
+			return found
+		}
+		var err error
+		switch kind {
+		case "ConfigMap":
+			_, err = clientset.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+		case "Secret":
+			_, err = clientset.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+		case "ServiceAccount":
+			_, err = clientset.CoreV1().ServiceAccounts(ns).Get(ctx, name, metav1.GetOptions{})
+		}
+		existence[key] = !apierrors.IsNotFound(err)
+		return existence[key]
+	}
+
+	for _, pod := range rootPods {
+		podKey := root
+		if parent, ok := podParent[pod]; ok {
+			podKey = g.add("Pod", pod.Name, podTreeStatus(pod))
+			g.link(parent, podKey, "owns")
+		}
+		for _, ref := range podSpecObjectRefs(&pod.Spec) {
+			key := g.add(ref.Kind, ref.Name, "")
+			g.link(podKey, key, ref.Relation)
+			if ref.Kind == "PersistentVolumeClaim" {
+				pvc, err := clientset.CoreV1().PersistentVolumeClaims(ns).Get(ctx, ref.Name, metav1.GetOptions{})
+				switch {
+				case apierrors.IsNotFound(err):
+					g.nodes[key].Missing, g.nodes[key].Status = true, "not found"
+				case err == nil:
+					g.nodes[key].Status = string(pvc.Status.Phase)
+				}
+				continue
+			}
+			if !exists(ref.Kind, ref.Name) {
+				g.nodes[key].Missing, g.nodes[key].Status = true, "not found"
+			}
+		}
+	}
+
+	if err := linkReferrers(ctx, clientset, g, root, input, podLabels); err != nil {
+		return "", err
+	}
+	return root, nil
+}
+
+// linkReferrers adds the Services selecting the pods, Ingresses routing to those Services, and the
+// HPAs and PDBs targeting the root object.
+func linkReferrers(ctx context.Context, clientset kubernetes.Interface, g *objectGraph, root string, input *ObjectTreeInput, podLabels map[string]string) error {
+	ns := input.Namespace
+	services, err := clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+	selecting := map[string]string{}
+	for _, svc := range services.Items {
+		if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(podLabels)) {
+			continue
+		}
+		key := g.add("Service", svc.Name, string(svc.Spec.Type))
+		g.link(root, key, "selected by")
+		selecting[svc.Name] = key
+	}
+
+	if len(selecting) > 0 {
+		ingresses, err := clientset.NetworkingV1().Ingresses(ns).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			for i := range ingresses.Items {
+				ing := &ingresses.Items[i]
+				for _, svcName := range ingressServiceNames(ing) {
+					if svcKey, ok := selecting[svcName]; ok {
+						g.link(svcKey, g.add("Ingress", ing.Name, ""), "routed by")
+					}
+				}
+			}
+		}
+	}
+
+	if input.Kind == "Pod" {
+		return nil
+	}
+	hpas, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(ns).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		for _, hpa := range hpas.Items {
+			if hpa.Spec.ScaleTargetRef.Kind == input.Kind && hpa.Spec.ScaleTargetRef.Name == input.Name {
+				status := fmt.Sprintf("%d replicas (min %d, max %d)", hpa.Status.CurrentReplicas, ptrInt32(hpa.Spec.MinReplicas, 1), hpa.Spec.MaxReplicas)
+				g.link(root, g.add("HorizontalPodAutoscaler", hpa.Name, status), "scaled by")
+			}
+		}
+	}
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(ns).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		for _, pdb := range pdbs.Items {
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(podLabels)) {
+				continue
+			}
+			status := fmt.Sprintf("%d disruptions allowed", pdb.Status.DisruptionsAllowed)
+			g.link(root, g.add("PodDisruptionBudget", pdb.Name, status), "protected by")
+		}
+	}
+	return nil
+}
+
+// podObjectRef is an object a pod spec refers to, with how it is used.
+type podObjectRef struct {
+	Kind     string
+	Name     string
+	Relation string
+}
+
+// podSpecObjectRefs lists the PVCs, ConfigMaps, Secrets and ServiceAccount a pod spec refers to.
+func podSpecObjectRefs(spec *corev1.PodSpec) []podObjectRef {
+	var refs []podObjectRef
+	add := func(kind, name, relation string) {
+		if name != "" {
+			refs = append(refs, podObjectRef{Kind: kind, Name: name, Relation: relation})
+		}
+	}
+
+	sa := spec.ServiceAccountName
+	if sa == "" {
+		sa = "default"
+	}
+	add("ServiceAccount", sa, "runs as")
+
+	for _, v := range spec.Volumes {
+		switch {
+		case v.PersistentVolumeClaim != nil:
+			add("PersistentVolumeClaim", v.PersistentVolumeClaim.ClaimName, "mounts")
+		case v.ConfigMap != nil:
+			add("ConfigMap", v.ConfigMap.Name, "mounts")
+		case v.Secret != nil:
+			add("Secret", v.Secret.SecretName, "mounts")
+		case v.Projected != nil:
+			for _, src := range v.Projected.Sources {
+				if src.ConfigMap != nil {
+					add("ConfigMap", src.ConfigMap.Name, "mounts")
+				}
+				if src.Secret != nil {
+					add("Secret", src.Secret.Name, "mounts")
+				}
+			}
+		}
+	}
+
+	containers := append([]corev1.Container{}, spec.InitContainers...)
+	for _, c := range append(containers, spec.Containers...) {
+		for _, ef := range c.EnvFrom {
+			if ef.ConfigMapRef != nil {
+				add("ConfigMap", ef.ConfigMapRef.Name, "env from")
+			}
+			if ef.SecretRef != nil {
+				add("Secret", ef.SecretRef.Name, "env from")
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				add("ConfigMap", env.ValueFrom.ConfigMapKeyRef.Name, "env from")
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				add("Secret", env.ValueFrom.SecretKeyRef.Name, "env from")
+			}
+		}
+	}
+
+	for _, ps := range spec.ImagePullSecrets {
+		add("Secret", ps.Name, "pulls with")
+	}
+	return refs
+}
+
+// ingressServiceNames returns the Services an Ingress routes to.
+func ingressServiceNames(ing *networkingv1.Ingress) []string {
+	var names []string
+	if b := ing.Spec.DefaultBackend; b != nil && b.Service != nil {
+		names = append(names, b.Service.Name)
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service != nil {
+				names = append(names, path.Backend.Service.Name)
+			}
+		}
+	}
+	return names
+}
+
+// podTreeStatus renders a pod's phase and readiness.
+func podTreeStatus(pod *corev1.Pod) string {
+	if podReady(pod) {
+		return string(pod.Status.Phase) + ", ready"
+	}
+	return string(pod.Status.Phase) + ", not ready"
+}
+
+// ptrInt32 dereferences p, returning def when it is nil.
+func ptrInt32(p *int32, def int32) int32 {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+// parseAndValidateObjectTreeParams validates and parses the input parameters.
+func parseAndValidateObjectTreeParams(args map[string]any) (*ObjectTreeInput, error) {
+	input := &ObjectTreeInput{Format: "json"}
+
+	input.Kind, _ = args["kind"].(string)
+	switch input.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "Pod":
+	default:
+		return nil, fmt.Errorf("kind must be one of Deployment, StatefulSet, DaemonSet or Pod, got %q", input.Kind)
+	}
+
+	input.Name, _ = args["name"].(string)
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid name: %w", err)
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	if format, ok := args["format"].(string); ok && format != "" {
+		if format != "json" && format != "dot" {
+			return nil, fmt.Errorf("format must be json or dot, got %q", format)
+		}
+		input.Format = format
+	}
+
+	return input, nil
+}
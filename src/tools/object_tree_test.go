@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodSpecObjectRefs(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-0"}}},
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}}},
+		},
+		Containers: []corev1.Container{{
+			EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-env"}}}},
+		}},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+	}
+
+	assert.Equal(t, []podObjectRef{
+		{Kind: "ServiceAccount", Name: "default", Relation: "runs as"},
+		{Kind: "PersistentVolumeClaim", Name: "data-0", Relation: "mounts"},
+		{Kind: "ConfigMap", Name: "app-config", Relation: "mounts"},
+		{Kind: "Secret", Name: "app-env", Relation: "env from"},
+		{Kind: "Secret", Name: "registry", Relation: "pulls with"},
+	}, podSpecObjectRefs(spec))
+}
+
+func TestObjectGraphTree(t *testing.T) {
+	g := newObjectGraph()
+	root := g.add("Deployment", "web", "2/2 ready")
+	rs := g.add("ReplicaSet", "web-abc", "")
+	g.link(root, rs, "owns")
+	cm := g.add("ConfigMap", "web-config", "")
+	for _, name := range []string{"web-abc-1", "web-abc-2"} {
+		pod := g.add("Pod", name, "Running, ready")
+		g.link(rs, pod, "owns")
+		g.link(pod, cm, "mounts")
+		g.link(pod, cm, "mounts")
+	}
+	missing := g.add("Secret", "web-tls", "not found")
+	g.nodes[missing].Missing = true
+	g.link(cm, missing, "references")
+
+	tree := g.tree(root)
+	pods := tree.Children[0].Children
+	if assert.Len(t, pods, 2) {
+		assert.Len(t, pods[0].Children, 1)
+		assert.Equal(t, "web-tls", pods[0].Children[0].Children[0].Name)
+		assert.True(t, pods[1].Children[0].SeeAbove)
+		assert.Empty(t, pods[1].Children[0].Children)
+	}
+
+	dot := g.dot()
+	assert.Contains(t, dot, `"Deployment/web" [label="Deployment/web\n2/2 ready"];`)
+	assert.Contains(t, dot, `"Secret/web-tls" [label="Secret/web-tls\nnot found", color=red, style=dashed];`)
+	assert.Contains(t, dot, `"Pod/web-abc-1" -> "ConfigMap/web-config" [label="mounts"];`)
+}
@@ -0,0 +1,321 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// PDBCheckInput represents the input for checking PodDisruptionBudgets before maintenance.
+type PDBCheckInput struct {
+	Namespace  string `json:"namespace,omitempty"`
+	Node       string `json:"node,omitempty"`
+	Deployment string `json:"deployment,omitempty"`
+}
+
+// PDBSummary reports the state of one PodDisruptionBudget.
+type PDBSummary struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	MinAvailable       string `json:"minAvailable,omitempty"`
+	MaxUnavailable     string `json:"maxUnavailable,omitempty"`
+	ExpectedPods       int32  `json:"expectedPods"`
+	CurrentHealthy     int32  `json:"currentHealthy"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+}
+
+// PDBImpact describes how a planned disruption interacts with one PodDisruptionBudget.
+type PDBImpact struct {
+	PDB                string   `json:"pdb"`
+	Namespace          string   `json:"namespace"`
+	DisruptionsAllowed int32    `json:"disruptionsAllowed"`
+	PodsDisrupted      int32    `json:"podsDisrupted"`
+	Verdict            string   `json:"verdict"`
+	Detail             string   `json:"detail"`
+	Pods               []string `json:"pods,omitempty"`
+}
+
+// Verdicts reported by pdb_check.
+const (
+	pdbVerdictSafe    = "safe"
+	pdbVerdictSlow    = "slow"
+	pdbVerdictBlocked = "blocked"
+	pdbVerdictExceeds = "exceedsBudget"
+)
+
+// PDBCheckTool lists PodDisruptionBudgets and simulates maintenance against them.
+type PDBCheckTool struct {
+	client Client
+}
+
+// NewPDBCheckTool creates a new PDBCheckTool with the provided Kubernetes client.
+func NewPDBCheckTool(client Client) *PDBCheckTool {
+	return &PDBCheckTool{client: client}
+}
+
+// Tool returns the MCP tool definition for pdb_check.
+func (p *PDBCheckTool) Tool() mcp.Tool {
+	return mcp.NewTool("pdb_check",
+		mcp.WithDescription("List PodDisruptionBudgets with their allowed disruptions and, before maintenance, simulate whether draining a node or rollout-restarting a Deployment would violate any of them. Read-only."),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the PDBs to list (leave empty for all namespaces); required with 'deployment'"),
+		),
+		mcp.WithString("node",
+			mcp.Description("Simulate draining this node"),
+		),
+		mcp.WithString("deployment",
+			mcp.Description("Simulate a rollout restart of this Deployment"),
+		),
+	)
+}
+
+// Handler lists the PDBs and runs the requested simulation.
+func (p *PDBCheckTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidatePDBCheckParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate pdb_check params: %w", err)
+	}
+
+	clientset, err := p.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	// a drain touches pods in every namespace, so every PDB is relevant
+	pdbNamespace := input.Namespace
+	if input.Node != "" {
+		pdbNamespace = metav1.NamespaceAll
+	}
+	pdbList, err := clientset.PolicyV1().PodDisruptionBudgets(pdbNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	summaries := make([]PDBSummary, 0, len(pdbList.Items))
+	for i := range pdbList.Items {
+		summaries = append(summaries, summarizePDB(&pdbList.Items[i]))
+	}
+	result := map[string]any{"pdbs": summaries}
+
+	var impacts []PDBImpact
+	switch {
+	case input.Node != "":
+		pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", input.Node).String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods on node %s: %w", input.Node, err)
+		}
+		impacts = nodeDrainPDBImpact(pdbList.Items, pods.Items)
+		result["simulation"] = "drain node " + input.Node
+	case input.Deployment != "":
+		dep, err := clientset.AppsV1().Deployments(input.Namespace).Get(ctx, input.Deployment, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment %s/%s: %w", input.Namespace, input.Deployment, err)
+		}
+		impacts = deploymentRestartPDBImpact(dep, pdbList.Items)
+		result["simulation"] = "rollout restart deployment " + input.Deployment
+		result["note"] = "rolling updates do not use the Eviction API, so PDBs never block them; exceedsBudget means the rollout takes down more pods than the PDB would allow a voluntary disruption to"
+	}
+
+	if input.Node != "" || input.Deployment != "" {
+		safe := true
+		for _, impact := range impacts {
+			if impact.Verdict != pdbVerdictSafe {
+				safe = false
+			}
+		}
+		result["impacts"] = impacts
+		result["safe"] = safe
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// summarizePDB converts a PodDisruptionBudget into its reported summary.
+func summarizePDB(pdb *policyv1.PodDisruptionBudget) PDBSummary {
+	summary := PDBSummary{
+		Name:               pdb.Name,
+		Namespace:          pdb.Namespace,
+		ExpectedPods:       pdb.Status.ExpectedPods,
+		CurrentHealthy:     pdb.Status.CurrentHealthy,
+		DesiredHealthy:     pdb.Status.DesiredHealthy,
+		DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+	}
+	if pdb.Spec.MinAvailable != nil {
+		summary.MinAvailable = pdb.Spec.MinAvailable.String()
+	}
+	if pdb.Spec.MaxUnavailable != nil {
+		summary.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+	}
+	return summary
+}
+
+// nodeDrainPDBImpact counts, for each PDB, the healthy pods on the node that a drain would evict.
+// DaemonSet, mirror and finished pods are ignored because a drain does not evict them.
+func nodeDrainPDBImpact(pdbs []policyv1.PodDisruptionBudget, pods []corev1.Pod) []PDBImpact {
+	impacts := []PDBImpact{}
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+
+		impact := PDBImpact{PDB: pdb.Name, Namespace: pdb.Namespace, DisruptionsAllowed: pdb.Status.DisruptionsAllowed}
+		for j := range pods {
+			pod := &pods[j]
+			if pod.Namespace != pdb.Namespace || !selector.Matches(labels.Set(pod.Labels)) || !drainEvicts(pod) {
+				continue
+			}
+			impact.Pods = append(impact.Pods, pod.Name)
+			if podReady(pod) {
+				impact.PodsDisrupted++
+			}
+		}
+		if len(impact.Pods) == 0 {
+			continue
+		}
+
+		switch {
+		case impact.PodsDisrupted <= impact.DisruptionsAllowed:
+			impact.Verdict = pdbVerdictSafe
+			impact.Detail = "all evictions fit in the current budget"
+		case impact.DisruptionsAllowed == 0:
+			impact.Verdict = pdbVerdictBlocked
+			impact.Detail = "no disruptions are allowed; evictions will be rejected until more pods become healthy elsewhere"
+		default:
+			impact.Verdict = pdbVerdictSlow
+			impact.Detail = fmt.Sprintf("only %d of %d evictions fit in the budget; the rest wait for replacements to become ready", impact.DisruptionsAllowed, impact.PodsDisrupted)
+		}
+		impacts = append(impacts, impact)
+	}
+	return impacts
+}
+
+// drainEvicts reports whether drain_node would evict the pod rather than skip it.
+func drainEvicts(pod *corev1.Pod) bool {
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return false
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	controller := metav1.GetControllerOf(pod)
+	return controller == nil || controller.Kind != "DaemonSet"
+}
+
+// deploymentRestartPDBImpact compares the number of pods a rolling restart takes down at once
+// with the budget of every PDB that selects the Deployment's pods.
+func deploymentRestartPDBImpact(dep *appsv1.Deployment, pdbs []policyv1.PodDisruptionBudget) []PDBImpact {
+	replicas := int32(1)
+	if dep.Spec.Replicas != nil {
+		replicas = *dep.Spec.Replicas
+	}
+	unavailable := deploymentMaxUnavailable(dep, replicas)
+
+	impacts := []PDBImpact{}
+	podLabels := labels.Set(dep.Spec.Template.Labels)
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		if pdb.Namespace != dep.Namespace {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(podLabels) {
+			continue
+		}
+
+		impact := PDBImpact{
+			PDB:                pdb.Name,
+			Namespace:          pdb.Namespace,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			PodsDisrupted:      unavailable,
+			Verdict:            pdbVerdictSafe,
+			Detail:             fmt.Sprintf("the rollout takes down at most %d pod(s) at a time", unavailable),
+		}
+		if unavailable > pdb.Status.DisruptionsAllowed {
+			impact.Verdict = pdbVerdictExceeds
+			impact.Detail = fmt.Sprintf("the rollout may take down %d pod(s) at a time but the PDB allows %d disruption(s); lower maxUnavailable or raise maxSurge", unavailable, pdb.Status.DisruptionsAllowed)
+		}
+		impacts = append(impacts, impact)
+	}
+	return impacts
+}
+
+// deploymentMaxUnavailable resolves the number of pods a rolling update may take down at once.
+// Recreate Deployments take down every replica.
+func deploymentMaxUnavailable(dep *appsv1.Deployment, replicas int32) int32 {
+	if dep.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		return replicas
+	}
+	maxUnavailable := intstr.FromString("25%")
+	if ru := dep.Spec.Strategy.RollingUpdate; ru != nil && ru.MaxUnavailable != nil {
+		maxUnavailable = *ru.MaxUnavailable
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(replicas), false)
+	if err != nil {
+		return 0
+	}
+	return int32(value)
+}
+
+// podReady reports whether the pod's Ready condition is True.
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// parseAndValidatePDBCheckParams validates and parses the input parameters.
+func parseAndValidatePDBCheckParams(args map[string]any) (*PDBCheckInput, error) {
+	input := &PDBCheckInput{}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if node, ok := args["node"].(string); ok && node != "" {
+		if err := validation.ValidateResourceName(node); err != nil {
+			return nil, fmt.Errorf("invalid node name: %w", err)
+		}
+		input.Node = node
+	}
+	if dep, ok := args["deployment"].(string); ok && dep != "" {
+		if err := validation.ValidateResourceName(dep); err != nil {
+			return nil, fmt.Errorf("invalid deployment name: %w", err)
+		}
+		input.Deployment = dep
+	}
+
+	if input.Node != "" && input.Deployment != "" {
+		return nil, errors.New("only one of node or deployment can be simulated at a time")
+	}
+	if input.Deployment != "" && input.Namespace == "" {
+		return nil, errors.New("namespace must be provided with deployment")
+	}
+
+	return input, nil
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func testPDB(name string, app string, allowed int32) policyv1.PodDisruptionBudget {
+	return policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: allowed},
+	}
+}
+
+func TestNodeDrainPDBImpact(t *testing.T) {
+	readyPod := func(name, app string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	pods := []corev1.Pod{
+		readyPod("web-1", "web"),
+		readyPod("web-2", "web"),
+		readyPod("db-0", "db"),
+		readyPod("api-1", "api"),
+	}
+	pdbs := []policyv1.PodDisruptionBudget{
+		testPDB("web", "web", 1),
+		testPDB("db", "db", 0),
+		testPDB("api", "api", 2),
+		testPDB("cache", "cache", 0),
+	}
+
+	impacts := nodeDrainPDBImpact(pdbs, pods)
+
+	if assert.Len(t, impacts, 3) {
+		assert.Equal(t, pdbVerdictSlow, impacts[0].Verdict)
+		assert.Equal(t, int32(2), impacts[0].PodsDisrupted)
+		assert.Equal(t, pdbVerdictBlocked, impacts[1].Verdict)
+		assert.Equal(t, pdbVerdictSafe, impacts[2].Verdict)
+	}
+}
+
+func TestDeploymentRestartPDBImpact(t *testing.T) {
+	replicas := int32(8)
+	maxUnavailable := intstr.FromInt32(3)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Strategy: appsv1.DeploymentStrategy{RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: &maxUnavailable}},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}}},
+		},
+	}
+
+	impacts := deploymentRestartPDBImpact(dep, []policyv1.PodDisruptionBudget{testPDB("web", "web", 1), testPDB("db", "db", 0)})
+	if assert.Len(t, impacts, 1) {
+		assert.Equal(t, pdbVerdictExceeds, impacts[0].Verdict)
+		assert.Equal(t, int32(3), impacts[0].PodsDisrupted)
+	}
+
+	dep.Spec.Strategy.RollingUpdate = nil
+	assert.Equal(t, int32(2), deploymentMaxUnavailable(dep, replicas))
+	dep.Spec.Strategy.Type = appsv1.RecreateDeploymentStrategyType
+	assert.Equal(t, int32(8), deploymentMaxUnavailable(dep, replicas))
+}
+
+func TestParseAndValidatePDBCheckParams(t *testing.T) {
+	_, err := parseAndValidatePDBCheckParams(map[string]any{"node": "n1", "deployment": "web", "namespace": "default"})
+	assert.Error(t, err)
+
+	_, err = parseAndValidatePDBCheckParams(map[string]any{"deployment": "web"})
+	assert.Error(t, err)
+
+	input, err := parseAndValidatePDBCheckParams(map[string]any{"node": "worker-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "worker-1", input.Node)
+}
@@ -0,0 +1,416 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// WhyPendingInput represents the input for diagnosing a Pending pod.
+type WhyPendingInput struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// SchedulingReason is one reason a pod cannot be scheduled, with the number of nodes it rules out.
+type SchedulingReason struct {
+	Reason string   `json:"reason"`
+	Nodes  int      `json:"nodes,omitempty"`
+	Detail string   `json:"detail,omitempty"`
+	Sample []string `json:"sampleNodes,omitempty"`
+}
+
+// WhyPendingTool explains why a pod is stuck in Pending.
+type WhyPendingTool struct {
+	client Client
+}
+
+// NewWhyPendingTool creates a new WhyPendingTool with the provided Kubernetes client.
+func NewWhyPendingTool(client Client) *WhyPendingTool {
+	return &WhyPendingTool{client: client}
+}
+
+// Tool returns the MCP tool definition for why_pending.
+func (w *WhyPendingTool) Tool() mcp.Tool {
+	return mcp.NewTool("why_pending",
+		mcp.WithDescription("Explain why a Pending pod cannot be scheduled: checks scheduler events, node selectors and affinity, taints and tolerations, free resources on each node, PVC binding, scheduling gates and topology spread constraints, and returns a ranked list of reasons"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Pending pod"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the pod (defaults to 'default' if not specified)"),
+		),
+	)
+}
+
+// Handler evaluates the pod against every node and collects pod-level blockers.
+func (w *WhyPendingTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateWhyPendingParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate why_pending params: %w", err)
+	}
+
+	clientset, err := w.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	pod, err := clientset.CoreV1().Pods(input.Namespace).Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s/%s: %w", input.Namespace, input.Name, err)
+	}
+
+	result := map[string]any{
+		"pod":       pod.Name,
+		"namespace": pod.Namespace,
+		"phase":     pod.Status.Phase,
+	}
+	if pod.Spec.NodeName != "" {
+		result["nodeName"] = pod.Spec.NodeName
+		result["message"] = "Pod is already scheduled; it is not waiting for the scheduler"
+		return marshalWhyPendingResult(result)
+	}
+
+	var podReasons []SchedulingReason
+	if len(pod.Spec.SchedulingGates) > 0 {
+		gates := make([]string, 0, len(pod.Spec.SchedulingGates))
+		for _, g := range pod.Spec.SchedulingGates {
+			gates = append(gates, g.Name)
+		}
+		podReasons = append(podReasons, SchedulingReason{
+			Reason: "Pod has scheduling gates",
+			Detail: fmt.Sprintf("gates %v must be removed before the scheduler considers the pod", gates),
+		})
+	}
+	podReasons = append(podReasons, pvcReasons(ctx, clientset, pod)...)
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable == corev1.DoNotSchedule {
+			podReasons = append(podReasons, SchedulingReason{
+				Reason: "Topology spread constraint may be unsatisfiable",
+				Detail: fmt.Sprintf("topologyKey=%s maxSkew=%d whenUnsatisfiable=DoNotSchedule", c.TopologyKey, c.MaxSkew),
+			})
+		}
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	requested, podCounts, err := requestsByNode(ctx, clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeReasons, feasible := rankNodeReasons(pod, nodes.Items, requested, podCounts)
+
+	events, err := listEvents(ctx, clientset, pod.Namespace, "Pod", pod.Name, false)
+	if err != nil {
+		result["eventsError"] = err.Error()
+	}
+	var schedulerEvents []EventSummary
+	for _, ev := range events {
+		if ev.Reason == "FailedScheduling" || ev.Reason == "NotTriggerScaleUp" || ev.Reason == "TriggeredScaleUp" {
+			schedulerEvents = append(schedulerEvents, ev)
+		}
+	}
+	// events are newest first, so the first FailedScheduling message is the scheduler's latest verdict
+	for _, ev := range schedulerEvents {
+		if ev.Reason == "FailedScheduling" {
+			result["schedulerReasons"] = parseFailedSchedulingMessage(ev.Message)
+			break
+		}
+	}
+
+	result["reasons"] = append(podReasons, nodeReasons...)
+	result["nodesEvaluated"] = len(nodes.Items)
+	result["feasibleNodes"] = feasible
+	result["schedulerEvents"] = schedulerEvents
+	return marshalWhyPendingResult(result)
+}
+
+// pvcReasons reports PersistentVolumeClaims used by the pod that are missing or not yet bound.
+func pvcReasons(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) []SchedulingReason {
+	var reasons []SchedulingReason
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+		claim := v.PersistentVolumeClaim.ClaimName
+		pvc, err := clientset.CoreV1().PersistentVolumeClaims(pod.Namespace).Get(ctx, claim, metav1.GetOptions{})
+		if err != nil {
+			reasons = append(reasons, SchedulingReason{
+				Reason: "PersistentVolumeClaim not found",
+				Detail: fmt.Sprintf("%s: %v", claim, err),
+			})
+			continue
+		}
+		if pvc.Status.Phase != corev1.ClaimBound {
+			storageClass := ""
+			if pvc.Spec.StorageClassName != nil {
+				storageClass = *pvc.Spec.StorageClassName
+			}
+			reasons = append(reasons, SchedulingReason{
+				Reason: "PersistentVolumeClaim not bound",
+				Detail: fmt.Sprintf("%s is %s (storageClass=%q)", claim, pvc.Status.Phase, storageClass),
+			})
+		}
+	}
+	return reasons
+}
+
+// rankNodeReasons checks the pod against each node and groups the failures, most common first.
+func rankNodeReasons(pod *corev1.Pod, nodes []corev1.Node, requested map[string]resourceTotals, podCounts map[string]int) ([]SchedulingReason, []string) {
+	podRequests, _ := podRequestsAndLimits(pod)
+	byReason := map[string]*SchedulingReason{}
+	var order []string
+	feasible := []string{}
+
+	for i := range nodes {
+		node := &nodes[i]
+		reasons := nodeFitReasons(pod, node, podRequests, requested[node.Name], podCounts[node.Name])
+		if len(reasons) == 0 {
+			feasible = append(feasible, node.Name)
+			continue
+		}
+		for _, r := range reasons {
+			entry, ok := byReason[r]
+			if !ok {
+				entry = &SchedulingReason{Reason: r}
+				byReason[r] = entry
+				order = append(order, r)
+			}
+			entry.Nodes++
+			if len(entry.Sample) < 3 {
+				entry.Sample = append(entry.Sample, node.Name)
+			}
+		}
+	}
+
+	ranked := make([]SchedulingReason, 0, len(order))
+	for _, r := range order {
+		ranked = append(ranked, *byReason[r])
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Nodes > ranked[j].Nodes })
+	return ranked, feasible
+}
+
+// nodeFitReasons returns every reason the node cannot run the pod, using the scheduler's wording where possible.
+func nodeFitReasons(pod *corev1.Pod, node *corev1.Node, podRequests, nodeRequested resourceTotals, nodePods int) []string {
+	var reasons []string
+
+	if node.Spec.Unschedulable {
+		reasons = append(reasons, "node(s) were unschedulable")
+	}
+	if !nodeReady(node) {
+		reasons = append(reasons, "node(s) were not ready")
+	}
+	if !nodeMatchesPodAffinity(pod, node) {
+		reasons = append(reasons, "node(s) didn't match Pod's node affinity/selector")
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !podToleratesTaint(pod, taint) {
+			reasons = append(reasons, fmt.Sprintf("node(s) had untolerated taint {%s: %s}", taint.Key, taint.Value))
+		}
+	}
+
+	allocatable := resourceListTotals(node.Status.Allocatable)
+	if podRequests.CPU > 0 && nodeRequested.CPU+podRequests.CPU > allocatable.CPU {
+		reasons = append(reasons, "Insufficient cpu")
+	}
+	if podRequests.Memory > 0 && nodeRequested.Memory+podRequests.Memory > allocatable.Memory {
+		reasons = append(reasons, "Insufficient memory")
+	}
+	if q, ok := node.Status.Allocatable[corev1.ResourcePods]; ok && int64(nodePods) >= q.Value() {
+		reasons = append(reasons, "Too many pods")
+	}
+	return reasons
+}
+
+// podToleratesTaint reports whether any of the pod's tolerations tolerates the taint.
+func podToleratesTaint(pod *corev1.Pod, taint *corev1.Taint) bool {
+	for i := range pod.Spec.Tolerations {
+		if pod.Spec.Tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMatchesPodAffinity checks the pod's nodeSelector and required node affinity against the node.
+func nodeMatchesPodAffinity(pod *corev1.Pod, node *corev1.Node) bool {
+	for k, v := range pod.Spec.NodeSelector {
+		if node.Labels[k] != v {
+			return false
+		}
+	}
+
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return true
+	}
+	required := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil {
+		return true
+	}
+	for _, term := range required.NodeSelectorTerms {
+		if nodeMatchesSelectorTerm(node, &term) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMatchesSelectorTerm evaluates one NodeSelectorTerm; all of its requirements must match.
+func nodeMatchesSelectorTerm(node *corev1.Node, term *corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, req := range term.MatchExpressions {
+		value, exists := node.Labels[req.Key]
+		if !matchNodeSelectorRequirement(req, value, exists) {
+			return false
+		}
+	}
+	for _, req := range term.MatchFields {
+		if req.Key != "metadata.name" || !matchNodeSelectorRequirement(req, node.Name, true) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchNodeSelectorRequirement applies a single requirement to a label value.
+func matchNodeSelectorRequirement(req corev1.NodeSelectorRequirement, value string, exists bool) bool {
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return exists && containsString(req.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !exists || !containsString(req.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !exists
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !exists || len(req.Values) != 1 {
+			return false
+		}
+		actual, err1 := strconv.ParseInt(value, 10, 64)
+		bound, err2 := strconv.ParseInt(req.Values[0], 10, 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if req.Operator == corev1.NodeSelectorOpGt {
+			return actual > bound
+		}
+		return actual < bound
+	}
+	return false
+}
+
+// parseFailedSchedulingMessage splits a scheduler message such as
+// "0/3 nodes are available: 2 Insufficient cpu, 1 node(s) had untolerated taint {gpu: true}."
+// into reasons with their node counts, most common first.
+func parseFailedSchedulingMessage(message string) []SchedulingReason {
+	_, detail, found := strings.Cut(message, "are available: ")
+	if !found {
+		return nil
+	}
+	// anything after the first ". " is advice such as the preemption summary
+	detail, _, _ = strings.Cut(detail, ". ")
+	detail = strings.TrimSuffix(strings.TrimSpace(detail), ".")
+
+	var reasons []SchedulingReason
+	for _, part := range splitOutsideBraces(detail) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		count, text, ok := strings.Cut(part, " ")
+		n, err := strconv.Atoi(count)
+		if !ok || err != nil {
+			reasons = append(reasons, SchedulingReason{Reason: part})
+			continue
+		}
+		reasons = append(reasons, SchedulingReason{Reason: text, Nodes: n})
+	}
+	sort.SliceStable(reasons, func(i, j int) bool { return reasons[i].Nodes > reasons[j].Nodes })
+	return reasons
+}
+
+// splitOutsideBraces splits on commas that are not inside a taint's {key: value} braces.
+func splitOutsideBraces(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func marshalWhyPendingResult(result map[string]any) (*mcp.CallToolResult, error) {
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseAndValidateWhyPendingParams validates and parses the input parameters.
+func parseAndValidateWhyPendingParams(args map[string]any) (*WhyPendingInput, error) {
+	input := &WhyPendingInput{}
+
+	if name, ok := args["name"].(string); ok {
+		input.Name = name
+	}
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid pod name: %w", err)
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	return input, nil
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func pendingTestNode(name string, labels map[string]string, taints ...corev1.Taint) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func TestRankNodeReasons(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		NodeSelector: map[string]string{"pool": "general"},
+		Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		}}},
+	}}
+
+	gpuTaint := corev1.Taint{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	nodes := []corev1.Node{
+		pendingTestNode("busy-1", map[string]string{"pool": "general"}),
+		pendingTestNode("busy-2", map[string]string{"pool": "general"}),
+		pendingTestNode("gpu-1", map[string]string{"pool": "gpu"}, gpuTaint),
+		pendingTestNode("free-1", map[string]string{"pool": "general"}),
+	}
+	requested := map[string]resourceTotals{
+		"busy-1": {CPU: 1500},
+		"busy-2": {CPU: 1800},
+	}
+
+	reasons, feasible := rankNodeReasons(pod, nodes, requested, map[string]int{})
+
+	assert.Equal(t, []string{"free-1"}, feasible)
+	if assert.Len(t, reasons, 3) {
+		assert.Equal(t, "Insufficient cpu", reasons[0].Reason)
+		assert.Equal(t, 2, reasons[0].Nodes)
+		assert.Equal(t, []string{"busy-1", "busy-2"}, reasons[0].Sample)
+		assert.Equal(t, "node(s) didn't match Pod's node affinity/selector", reasons[1].Reason)
+		assert.Equal(t, "node(s) had untolerated taint {gpu: true}", reasons[2].Reason)
+	}
+
+	pod.Spec.Tolerations = []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}}
+	pod.Spec.NodeSelector = nil
+	_, feasible = rankNodeReasons(pod, nodes, requested, map[string]int{})
+	assert.Equal(t, []string{"gpu-1", "free-1"}, feasible)
+}
+
+func TestNodeMatchesPodAffinity(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "worker-1",
+		Labels: map[string]string{"zone": "a", "cpu-gen": "5"},
+	}}
+
+	affinityPod := func(terms ...corev1.NodeSelectorTerm) *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}}}
+	}
+	expr := func(key string, op corev1.NodeSelectorOperator, values ...string) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: key, Operator: op, Values: values}}}
+	}
+
+	testCases := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected bool
+	}{
+		{name: "NoConstraints", pod: &corev1.Pod{}, expected: true},
+		{name: "In", pod: affinityPod(expr("zone", corev1.NodeSelectorOpIn, "a", "b")), expected: true},
+		{name: "NotIn", pod: affinityPod(expr("zone", corev1.NodeSelectorOpNotIn, "a")), expected: false},
+		{name: "DoesNotExist", pod: affinityPod(expr("spot", corev1.NodeSelectorOpDoesNotExist)), expected: true},
+		{name: "Gt", pod: affinityPod(expr("cpu-gen", corev1.NodeSelectorOpGt, "4")), expected: true},
+		{name: "Lt", pod: affinityPod(expr("cpu-gen", corev1.NodeSelectorOpLt, "4")), expected: false},
+		{name: "OrOfTerms", pod: affinityPod(expr("zone", corev1.NodeSelectorOpIn, "b"), expr("zone", corev1.NodeSelectorOpExists)), expected: true},
+		{
+			name: "MatchFieldsName",
+			pod: affinityPod(corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{
+				{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"worker-2"}},
+			}}),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, nodeMatchesPodAffinity(tc.pod, node))
+		})
+	}
+}
+
+func TestParseFailedSchedulingMessage(t *testing.T) {
+	msg := "0/5 nodes are available: 1 node(s) had untolerated taint {gpu: true}, 3 Insufficient cpu, 1 node(s) didn't match Pod's node affinity/selector. preemption: 0/5 nodes are available: 5 Preemption is not helpful for scheduling."
+
+	reasons := parseFailedSchedulingMessage(msg)
+
+	if assert.Len(t, reasons, 3) {
+		assert.Equal(t, SchedulingReason{Reason: "Insufficient cpu", Nodes: 3}, reasons[0])
+		assert.Equal(t, SchedulingReason{Reason: "node(s) had untolerated taint {gpu: true}", Nodes: 1}, reasons[1])
+	}
+	assert.Nil(t, parseFailedSchedulingMessage("pod has unbound immediate PersistentVolumeClaims"))
+}
@@ -0,0 +1,397 @@
+// Environment variables used by this tool:
+// Optional:
+//   POD_INDEX_RESYNC               - Resync period of the cached pod index used by find_pods (default: 10m); "0" lists pods from the API server on every call
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	podNameTokenIndex     = "nameToken"
+	podAppIndex           = "app"
+	defaultPodIndexResync = 10 * time.Minute
+	podIndexSyncTimeout   = 30 * time.Second
+	defaultFindPodsLimit  = 50
+)
+
+// podAppLabels are the labels whose values name the application a pod belongs to.
+var podAppLabels = []string{"app.kubernetes.io/name", "app", "k8s-app"}
+
+// podIndexers index pods by the tokens of their name and by their application label.
+var podIndexers = cache.Indexers{
+	podNameTokenIndex: podNameTokens,
+	podAppIndex:       podAppValues,
+}
+
+// podNameTokens splits a pod name on dashes and dots, so that web-7d9f8-abcde is found by web.
+func podNameTokens(obj any) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+	return nameTokens(strings.ToLower(pod.Name)), nil
+}
+
+// podAppValues returns the lowercase application labels of a pod.
+func podAppValues(obj any) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+	var values []string
+	for _, label := range podAppLabels {
+		if v := strings.ToLower(pod.Labels[label]); v != "" && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// nameTokens returns the non-empty dash and dot separated parts of a name.
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '.' })
+}
+
+// PodIndex is a cluster-wide pod cache indexed by name token and application label. The informer
+// starts on first use and runs for the life of the server, independent of the request that started
+// it; when it cannot sync, searches list pods from the API server instead.
+type PodIndex struct {
+	client Client
+	resync time.Duration
+
+	mu       sync.Mutex
+	informer cache.SharedIndexInformer
+	stop     chan struct{}
+	started  time.Time
+}
+
+// NewPodIndex creates a pod index that is filled on first use.
+func NewPodIndex(client Client, resync time.Duration) *PodIndex {
+	return &PodIndex{client: client, resync: resync}
+}
+
+// indexer returns the synced informer cache, starting the informer if needed. A nil indexer with a
+// nil error means the index is disabled.
+func (p *PodIndex) indexer(ctx context.Context) (cache.Indexer, error) {
+	if p.resync <= 0 {
+		return nil, nil
+	}
+	informer, started, err := p.start()
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait without the lock, so concurrent searches are not serialized behind a slow sync, and at
+	// most until the informer's own sync deadline.
+	syncCtx, cancel := context.WithDeadline(ctx, started.Add(podIndexSyncTimeout))
+	defer cancel()
+	if cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+		return informer.GetIndexer(), nil
+	}
+	if err := ctx.Err(); err != nil {
+		// The request gave up; the informer keeps syncing for the next one.
+		return nil, err
+	}
+	// Stop an informer that cannot list pods, for example without cluster-wide RBAC, so it does not
+	// retry in the background; the next call starts a fresh one.
+	p.stopInformer(informer)
+	return nil, fmt.Errorf("pod index did not sync within %s", podIndexSyncTimeout)
+}
+
+// start returns the informer and when it was started, starting it if needed.
+func (p *PodIndex) start() (cache.SharedIndexInformer, time.Time, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.informer != nil {
+		return p.informer, p.started, nil
+	}
+
+	clientset, err := p.client.Clientset()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, p.resync,
+		informers.WithTransform(trimPod))
+	informer := factory.Core().V1().Pods().Informer()
+	if err := informer.AddIndexers(podIndexers); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to add pod indexers: %w", err)
+	}
+	p.informer, p.stop, p.started = informer, make(chan struct{}), time.Now()
+	factory.Start(p.stop)
+	return p.informer, p.started, nil
+}
+
+// stopInformer stops the informer unless another call already replaced it.
+func (p *PodIndex) stopInformer(informer cache.SharedIndexInformer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.informer == informer {
+		close(p.stop)
+		p.informer, p.stop = nil, nil
+	}
+}
+
+// trimPod drops the managed fields of cached pods, which are large and never read.
+func trimPod(obj any) (any, error) {
+	if pod, ok := obj.(*corev1.Pod); ok {
+		pod.ManagedFields = nil
+	}
+	return obj, nil
+}
+
+// searchPods returns the pods whose name contains query and whose application label equals app,
+// in the namespace or in all namespaces when it is empty. Either filter may be empty, not both.
+// A name containing query has a token containing each of its dash and dot separated parts, so
+// candidates come from the distinct tokens instead of every pod.
+func searchPods(indexer cache.Indexer, query, app, namespace string) ([]*corev1.Pod, error) {
+	query, app = strings.ToLower(query), strings.ToLower(app)
+
+	var candidates map[string]*corev1.Pod
+	narrow := func(objs []any) {
+		next := map[string]*corev1.Pod{}
+		for _, obj := range objs {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			key := pod.Namespace + "/" + pod.Name
+			if _, ok := candidates[key]; candidates == nil || ok {
+				next[key] = pod
+			}
+		}
+		candidates = next
+	}
+
+	if app != "" {
+		objs, err := indexer.ByIndex(podAppIndex, app)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search pods by app: %w", err)
+		}
+		narrow(objs)
+	}
+	if query != "" {
+		parts := nameTokens(query)
+		if len(parts) == 0 {
+			narrow(indexer.List())
+		}
+		tokens := indexer.ListIndexFuncValues(podNameTokenIndex)
+		for _, part := range parts {
+			var objs []any
+			for _, token := range tokens {
+				if !strings.Contains(token, part) {
+					continue
+				}
+				matched, err := indexer.ByIndex(podNameTokenIndex, token)
+				if err != nil {
+					return nil, fmt.Errorf("failed to search pods by name: %w", err)
+				}
+				objs = append(objs, matched...)
+			}
+			narrow(objs)
+		}
+	}
+
+	pods := make([]*corev1.Pod, 0, len(candidates))
+	for _, pod := range candidates {
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(pod.Name), query) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// FindPodsInput represents the input for find_pods.
+type FindPodsInput struct {
+	Query     string `json:"query,omitempty"`
+	App       string `json:"app,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+// FoundPod summarizes a pod matched by find_pods.
+type FoundPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	App       string `json:"app,omitempty"`
+	Phase     string `json:"phase"`
+	Ready     string `json:"ready"`
+	Restarts  int32  `json:"restarts"`
+	Node      string `json:"node,omitempty"`
+}
+
+// FindPodsTool finds pods by part of their name or by application label across namespaces.
+type FindPodsTool struct {
+	client Client
+	index  *PodIndex
+}
+
+// NewFindPodsTool creates a new FindPodsTool backed by a pod index.
+func NewFindPodsTool(client Client) *FindPodsTool {
+	resync := defaultPodIndexResync
+	if v := os.Getenv("POD_INDEX_RESYNC"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			resync = d
+		}
+	}
+	return &FindPodsTool{client: client, index: NewPodIndex(client, resync)}
+}
+
+// Tool returns the MCP tool definition for find_pods.
+func (f *FindPodsTool) Tool() mcp.Tool {
+	return mcp.NewTool("find_pods",
+		mcp.WithDescription("Find pods whose name contains a string, or whose app label (app.kubernetes.io/name, app or k8s-app) matches, across all namespaces, with their phase, readiness, restarts and node. Answers questions like \"is the content service running\" from a cached index instead of listing every pod"),
+		mcp.WithString("query",
+			mcp.Description("Part of the pod name, case-insensitive, e.g. content or content-api"),
+		),
+		mcp.WithString("app",
+			mcp.Description("Exact value of the pod's app label, case-insensitive"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Only search this namespace (leave empty for all namespaces)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of pods to return (default: 50)"),
+		),
+	)
+}
+
+// Handler searches the pod index, falling back to listing pods when the index is unavailable.
+func (f *FindPodsTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateFindPodsParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate find_pods params: %w", err)
+	}
+
+	result := map[string]any{"query": input.Query, "app": input.App, "namespace": input.Namespace}
+	source := "index"
+	indexer, err := f.index.indexer(ctx)
+	if err != nil {
+		result["indexError"] = err.Error()
+	}
+	if indexer == nil {
+		source = "api"
+		indexer, err = f.listPods(ctx, input.Namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pods, err := searchPods(indexer, input.Query, input.App, input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	found := make([]FoundPod, 0, min(len(pods), input.Limit))
+	for _, pod := range pods {
+		if len(found) == input.Limit {
+			break
+		}
+		found = append(found, summarizeFoundPod(pod))
+	}
+
+	result["source"] = source
+	result["pods"] = found
+	result["total"] = len(pods)
+	result["truncated"] = len(pods) > len(found)
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// listPods lists pods from the API server into a temporary indexer so searches work the same way.
+func (f *FindPodsTool) listPods(ctx context.Context, namespace string) (cache.Indexer, error) {
+	clientset, err := f.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, podIndexers)
+	for i := range pods.Items {
+		if err := indexer.Add(&pods.Items[i]); err != nil {
+			return nil, fmt.Errorf("failed to index pods: %w", err)
+		}
+	}
+	return indexer, nil
+}
+
+// summarizeFoundPod returns the status of a pod as reported by find_pods.
+func summarizeFoundPod(pod *corev1.Pod) FoundPod {
+	found := FoundPod{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Phase:     string(pod.Status.Phase),
+		Node:      pod.Spec.NodeName,
+	}
+	for _, label := range podAppLabels {
+		if v := pod.Labels[label]; v != "" {
+			found.App = v
+			break
+		}
+	}
+	ready := 0
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+		found.Restarts += cs.RestartCount
+	}
+	found.Ready = fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+	if pod.DeletionTimestamp != nil {
+		found.Phase = "Terminating"
+	}
+	return found
+}
+
+func parseAndValidateFindPodsParams(args map[string]any) (*FindPodsInput, error) {
+	input := &FindPodsInput{Limit: defaultFindPodsLimit}
+	input.Query, _ = args["query"].(string)
+	input.App, _ = args["app"].(string)
+	input.Query, input.App = strings.TrimSpace(input.Query), strings.TrimSpace(input.App)
+	if input.Query == "" && input.App == "" {
+		return nil, fmt.Errorf("query or app is required")
+	}
+	if namespace, ok := args["namespace"].(string); ok && namespace != "" {
+		if err := validation.ValidateNamespace(namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = namespace
+	}
+	if limit, ok := args["limit"].(float64); ok {
+		if limit < 1 {
+			return nil, fmt.Errorf("limit must be at least 1")
+		}
+		input.Limit = int(limit)
+	}
+	return input, nil
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+func testPodIndexer(t *testing.T) cache.Indexer {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, podIndexers)
+	pods := []*corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "content-api-7d9f8-abcde", Namespace: "prod", Labels: map[string]string{"app": "content"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "content-api-7d9f8-fghij", Namespace: "staging", Labels: map[string]string{"app.kubernetes.io/name": "Content"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-frontend-0", Namespace: "prod", Labels: map[string]string{"app": "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "contentious-1", Namespace: "prod"}},
+	}
+	for _, pod := range pods {
+		assert.NoError(t, indexer.Add(pod))
+	}
+	return indexer
+}
+
+func podNames(pods []*corev1.Pod) []string {
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		names[i] = pod.Namespace + "/" + pod.Name
+	}
+	return names
+}
+
+// unsyncedPodIndex returns a pod index whose informer never syncs.
+func unsyncedPodIndex(started time.Time) *PodIndex {
+	lw := &cache.ListWatch{ListFunc: func(metav1.ListOptions) (runtime.Object, error) { return nil, errors.New("forbidden") }}
+	informer := cache.NewSharedIndexInformer(lw, &corev1.Pod{}, 0, podIndexers)
+	return &PodIndex{resync: time.Minute, informer: informer, stop: make(chan struct{}), started: started}
+}
+
+func TestPodIndexWaitsWithoutLock(t *testing.T) {
+	index := unsyncedPodIndex(time.Now())
+
+	// A slow sync in one call does not block another, and a request that gives up keeps the informer.
+	slow, cancelSlow := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelSlow()
+	done := make(chan error, 1)
+	go func() {
+		_, err := index.indexer(slow)
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	begin := time.Now()
+	_, err := index.indexer(cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(begin), 100*time.Millisecond)
+	assert.ErrorIs(t, <-done, context.DeadlineExceeded)
+	assert.NotNil(t, index.informer)
+}
+
+func TestPodIndexStopsUnsyncedInformer(t *testing.T) {
+	index := unsyncedPodIndex(time.Now().Add(-podIndexSyncTimeout))
+
+	_, err := index.indexer(context.Background())
+
+	assert.EqualError(t, err, "pod index did not sync within 30s")
+	assert.Nil(t, index.informer)
+}
+
+func TestSearchPods(t *testing.T) {
+	indexer := testPodIndexer(t)
+	tests := []struct {
+		name      string
+		query     string
+		app       string
+		namespace string
+		want      []string
+	}{
+		{name: "token", query: "web", want: []string{"prod/web-frontend-0"}},
+		{name: "substring of a token", query: "ntent", want: []string{"prod/content-api-7d9f8-abcde", "prod/contentious-1", "staging/content-api-7d9f8-fghij"}},
+		{name: "across tokens", query: "tent-ap", want: []string{"prod/content-api-7d9f8-abcde", "staging/content-api-7d9f8-fghij"}},
+		{name: "case insensitive", query: "WEB-Front", want: []string{"prod/web-frontend-0"}},
+		{name: "namespace", query: "content", namespace: "staging", want: []string{"staging/content-api-7d9f8-fghij"}},
+		{name: "app", app: "content", want: []string{"prod/content-api-7d9f8-abcde", "staging/content-api-7d9f8-fghij"}},
+		{name: "app and query", app: "content", query: "fghij", want: []string{"staging/content-api-7d9f8-fghij"}},
+		{name: "separators only", query: "-", want: []string{"prod/content-api-7d9f8-abcde", "prod/contentious-1", "prod/web-frontend-0", "staging/content-api-7d9f8-fghij"}},
+		{name: "no match", query: "api-web", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods, err := searchPods(indexer, tt.query, tt.app, tt.namespace)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, podNames(pods))
+		})
+	}
+}
+
+func TestSummarizeFoundPod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "prod", Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Name: "web"}, {Name: "proxy"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "web", Ready: true, RestartCount: 2},
+				{Name: "proxy", RestartCount: 1},
+			},
+		},
+	}
+	assert.Equal(t, FoundPod{Namespace: "prod", Name: "web-0", App: "web", Phase: "Running", Ready: "1/2", Restarts: 3, Node: "node-1"}, summarizeFoundPod(pod))
+}
+
+func TestParseAndValidateFindPodsParams(t *testing.T) {
+	input, err := parseAndValidateFindPodsParams(map[string]any{"query": " content ", "limit": float64(5)})
+	assert.NoError(t, err)
+	assert.Equal(t, &FindPodsInput{Query: "content", Limit: 5}, input)
+
+	_, err = parseAndValidateFindPodsParams(map[string]any{})
+	assert.EqualError(t, err, "query or app is required")
+
+	_, err = parseAndValidateFindPodsParams(map[string]any{"app": "web", "namespace": "Bad_NS"})
+	assert.Error(t, err)
+}
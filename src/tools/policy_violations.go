@@ -0,0 +1,297 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Policy report kinds from the Kubernetes policy working group, written by Kyverno and other engines.
+var (
+	policyReportGK        = schema.GroupKind{Group: "wgpolicyk8s.io", Kind: "PolicyReport"}
+	clusterPolicyReportGK = schema.GroupKind{Group: "wgpolicyk8s.io", Kind: "ClusterPolicyReport"}
+)
+
+// gatekeeperConstraintsGroupVersion serves one resource per Gatekeeper ConstraintTemplate.
+const gatekeeperConstraintsGroupVersion = "constraints.gatekeeper.sh/v1beta1"
+
+// PolicyViolation is one resource that violates a policy.
+type PolicyViolation struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Rule      string `json:"rule,omitempty"`
+	Result    string `json:"result"`
+	Message   string `json:"message,omitempty"`
+}
+
+// PolicyViolationGroup collects the violations of one policy in one namespace.
+type PolicyViolationGroup struct {
+	Engine    string            `json:"engine"`
+	Policy    string            `json:"policy"`
+	Namespace string            `json:"namespace,omitempty"`
+	Severity  string            `json:"severity,omitempty"`
+	Category  string            `json:"category,omitempty"`
+	Count     int               `json:"count"`
+	Resources []PolicyViolation `json:"resources"`
+}
+
+// policyViolationIndex groups violations by engine, policy and namespace.
+type policyViolationIndex struct {
+	namespace       string
+	policy          string
+	includeWarnings bool
+	maxResources    int
+	groups          map[string]*PolicyViolationGroup
+	notes           []string
+}
+
+// newPolicyViolationIndex creates an index that keeps violations matching the namespace and policy filters.
+func newPolicyViolationIndex(namespace, policy string, includeWarnings bool, maxResources int) *policyViolationIndex {
+	return &policyViolationIndex{
+		namespace:       namespace,
+		policy:          policy,
+		includeWarnings: includeWarnings,
+		maxResources:    maxResources,
+		groups:          map[string]*PolicyViolationGroup{},
+	}
+}
+
+// add records a violation unless it is filtered out by namespace or policy.
+func (p *policyViolationIndex) add(engine, policy, severity, category string, v PolicyViolation) {
+	if (p.namespace != "" && v.Namespace != p.namespace) || (p.policy != "" && policy != p.policy) {
+		return
+	}
+	key := engine + "/" + policy + "/" + v.Namespace
+	group, ok := p.groups[key]
+	if !ok {
+		group = &PolicyViolationGroup{Engine: engine, Policy: policy, Namespace: v.Namespace, Severity: severity, Category: category, Resources: []PolicyViolation{}}
+		p.groups[key] = group
+	}
+	group.Count++
+	if len(group.Resources) < p.maxResources {
+		group.Resources = append(group.Resources, v)
+	}
+}
+
+// addPolicyReport records the failing results of a PolicyReport or ClusterPolicyReport. Results name their
+// resources, or the report is scoped to a single resource (as Kyverno's per-resource reports are).
+func (p *policyViolationIndex) addPolicyReport(report *unstructured.Unstructured) {
+	scope, _, _ := unstructured.NestedMap(report.Object, "scope")
+	results, _, _ := unstructured.NestedSlice(report.Object, "results")
+	for _, r := range results {
+		result, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		outcome, _ := result["result"].(string)
+		if outcome != "fail" && outcome != "error" && (outcome != "warn" || !p.includeWarnings) {
+			continue
+		}
+		engine, _ := result["source"].(string)
+		if engine == "" {
+			engine = "policyreport"
+		}
+		policy, _ := result["policy"].(string)
+		severity, _ := result["severity"].(string)
+		category, _ := result["category"].(string)
+		rule, _ := result["rule"].(string)
+		message, _ := result["message"].(string)
+
+		resources, _, _ := unstructured.NestedSlice(result, "resources")
+		if len(resources) == 0 && scope != nil {
+			resources = []any{scope}
+		}
+		for _, res := range resources {
+			ref, ok := res.(map[string]any)
+			if !ok {
+				continue
+			}
+			v := PolicyViolation{Rule: rule, Result: outcome, Message: message}
+			v.Kind, _ = ref["kind"].(string)
+			v.Name, _ = ref["name"].(string)
+			v.Namespace, _ = ref["namespace"].(string)
+			p.add(engine, policy, severity, category, v)
+		}
+	}
+}
+
+// addConstraint records the audit violations of a Gatekeeper constraint. The audit only lists a limited
+// number of violations per constraint, so a note is kept when totalViolations is larger.
+func (p *policyViolationIndex) addConstraint(constraint *unstructured.Unstructured) {
+	policy := constraint.GetKind() + "/" + constraint.GetName()
+	violations, _, _ := unstructured.NestedSlice(constraint.Object, "status", "violations")
+	for _, item := range violations {
+		violation, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		v := PolicyViolation{}
+		v.Kind, _ = violation["kind"].(string)
+		v.Name, _ = violation["name"].(string)
+		v.Namespace, _ = violation["namespace"].(string)
+		v.Message, _ = violation["message"].(string)
+		v.Result, _ = violation["enforcementAction"].(string)
+		if v.Result == "warn" && !p.includeWarnings {
+			continue
+		}
+		p.add("gatekeeper", policy, "", "", v)
+	}
+	total, _, _ := unstructured.NestedInt64(constraint.Object, "status", "totalViolations")
+	if int(total) > len(violations) && (p.policy == "" || p.policy == policy) {
+		p.notes = append(p.notes, fmt.Sprintf("%s has %d violations but the audit lists %d", policy, total, len(violations)))
+	}
+}
+
+// sorted returns the groups with the most violations first.
+func (p *policyViolationIndex) sorted() []PolicyViolationGroup {
+	groups := make([]PolicyViolationGroup, 0, len(p.groups))
+	for _, g := range p.groups {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		if groups[i].Policy != groups[j].Policy {
+			return groups[i].Policy < groups[j].Policy
+		}
+		return groups[i].Namespace < groups[j].Namespace
+	})
+	return groups
+}
+
+// PolicyViolationsTool summarizes policy engine violations.
+type PolicyViolationsTool struct {
+	client Client
+}
+
+// NewPolicyViolationsTool creates a new PolicyViolationsTool with the provided Kubernetes client.
+func NewPolicyViolationsTool(client Client) *PolicyViolationsTool {
+	return &PolicyViolationsTool{client: client}
+}
+
+// Tool returns the MCP tool definition for policy_violations.
+func (p *PolicyViolationsTool) Tool() mcp.Tool {
+	return mcp.NewTool("policy_violations",
+		mcp.WithDescription("Summarize policy violations from PolicyReports/ClusterPolicyReports (Kyverno and other engines) and OPA Gatekeeper constraint audit results, grouped by policy and namespace with the offending resources, most violated first"),
+		mcp.WithString("namespace",
+			mcp.Description("Only report violations of resources in this namespace (leave empty for all)"),
+		),
+		mcp.WithString("policy",
+			mcp.Description("Only report this policy (Kyverno policy name or Gatekeeper 'Kind/name' constraint)"),
+		),
+		mcp.WithBoolean("includeWarnings",
+			mcp.Description("Also report results that only warn (default: false)"),
+		),
+		mcp.WithNumber("maxResources",
+			mcp.Description("Maximum offending resources listed per group (default: 20)"),
+		),
+	)
+}
+
+// Handler reads the reports and constraints that are installed and groups their violations.
+func (p *PolicyViolationsTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	policy, _ := args["policy"].(string)
+	includeWarnings, _ := args["includeWarnings"].(bool)
+	maxResources := 20
+	if v, ok := args["maxResources"].(float64); ok && v > 0 {
+		maxResources = int(v)
+	}
+
+	index := newPolicyViolationIndex(namespace, policy, includeWarnings, maxResources)
+	var sources []string
+
+	reportsFound := false
+	for _, gk := range []schema.GroupKind{policyReportGK, clusterPolicyReportGK} {
+		ns := namespace
+		if gk == clusterPolicyReportGK {
+			ns = ""
+		}
+		ri, err := groupKindResourceInterface(p.client, gk, ns)
+		if err != nil {
+			continue
+		}
+		reports, err := ri.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gk.Kind, err)
+		}
+		reportsFound = true
+		for i := range reports.Items {
+			index.addPolicyReport(&reports.Items[i])
+		}
+	}
+	if reportsFound {
+		sources = append(sources, "policyreports")
+	}
+
+	discoClient, err := p.client.DiscoClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	constraintResources, err := discoClient.ServerResourcesForGroupVersion(gatekeeperConstraintsGroupVersion)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to discover Gatekeeper constraints: %w", err)
+	default:
+		sources = append(sources, "gatekeeper")
+		gv, _ := schema.ParseGroupVersion(gatekeeperConstraintsGroupVersion)
+		for _, res := range constraintResources.APIResources {
+			if strings.Contains(res.Name, "/") {
+				continue
+			}
+			ri, err := p.client.ResourceInterface(gv.WithResource(res.Name), false, "")
+			if err != nil {
+				return nil, fmt.Errorf("failed to create resource interface: %w", err)
+			}
+			constraints, err := ri.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s constraints: %w", res.Kind, err)
+			}
+			for i := range constraints.Items {
+				index.addConstraint(&constraints.Items[i])
+			}
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("neither %s nor %s is served by the cluster (is Kyverno or Gatekeeper installed?)", policyReportGK.String(), gatekeeperConstraintsGroupVersion)
+	}
+
+	groups := index.sorted()
+	total := 0
+	byNamespace := map[string]int{}
+	for _, g := range groups {
+		total += g.Count
+		byNamespace[g.Namespace] += g.Count
+	}
+	result := map[string]any{
+		"sources":     sources,
+		"total":       total,
+		"byNamespace": byNamespace,
+		"violations":  groups,
+	}
+	if len(index.notes) > 0 {
+		result["notes"] = index.notes
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
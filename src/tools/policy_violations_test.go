@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPolicyViolationIndexPolicyReport(t *testing.T) {
+	report := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "polr-ns-app", "namespace": "app"},
+		"results": []any{
+			map[string]any{
+				"policy": "require-labels", "rule": "check-team", "result": "fail", "source": "kyverno",
+				"severity": "medium", "category": "Best Practices", "message": "label 'team' is required",
+				"resources": []any{
+					map[string]any{"kind": "Deployment", "name": "web", "namespace": "app"},
+					map[string]any{"kind": "Deployment", "name": "worker", "namespace": "app"},
+				},
+			},
+			map[string]any{"policy": "require-labels", "rule": "check-team", "result": "pass", "source": "kyverno",
+				"resources": []any{map[string]any{"kind": "Deployment", "name": "api", "namespace": "app"}}},
+			map[string]any{"policy": "disallow-latest", "result": "warn", "source": "kyverno",
+				"resources": []any{map[string]any{"kind": "Pod", "name": "debug", "namespace": "app"}}},
+		},
+	}}
+	// Kyverno's per-resource reports name the resource in the report scope instead of the results.
+	scoped := &unstructured.Unstructured{Object: map[string]any{
+		"scope":   map[string]any{"kind": "Pod", "name": "web-1", "namespace": "app"},
+		"results": []any{map[string]any{"policy": "require-labels", "result": "fail", "source": "kyverno"}},
+	}}
+
+	index := newPolicyViolationIndex("", "", false, 2)
+	index.addPolicyReport(report)
+	index.addPolicyReport(scoped)
+	groups := index.sorted()
+	assert.Len(t, groups, 1)
+	assert.Equal(t, "kyverno", groups[0].Engine)
+	assert.Equal(t, "require-labels", groups[0].Policy)
+	assert.Equal(t, "app", groups[0].Namespace)
+	assert.Equal(t, "medium", groups[0].Severity)
+	assert.Equal(t, 3, groups[0].Count)
+	assert.Len(t, groups[0].Resources, 2)
+
+	index = newPolicyViolationIndex("", "disallow-latest", true, 20)
+	index.addPolicyReport(report)
+	groups = index.sorted()
+	assert.Len(t, groups, 1)
+	assert.Equal(t, []PolicyViolation{{Kind: "Pod", Name: "debug", Namespace: "app", Result: "warn"}}, groups[0].Resources)
+}
+
+func TestPolicyViolationIndexConstraint(t *testing.T) {
+	constraint := &unstructured.Unstructured{Object: map[string]any{
+		"kind":     "K8sRequiredLabels",
+		"metadata": map[string]any{"name": "must-have-owner"},
+		"status": map[string]any{
+			"totalViolations": int64(25),
+			"violations": []any{
+				map[string]any{"kind": "Namespace", "name": "legacy", "message": "missing owner", "enforcementAction": "deny"},
+				map[string]any{"kind": "Deployment", "name": "web", "namespace": "app", "message": "missing owner", "enforcementAction": "dryrun"},
+				map[string]any{"kind": "Deployment", "name": "api", "namespace": "app", "message": "missing owner", "enforcementAction": "warn"},
+			},
+		},
+	}}
+
+	index := newPolicyViolationIndex("app", "", false, 20)
+	index.addConstraint(constraint)
+	groups := index.sorted()
+	assert.Len(t, groups, 1)
+	assert.Equal(t, "K8sRequiredLabels/must-have-owner", groups[0].Policy)
+	assert.Equal(t, []PolicyViolation{{Kind: "Deployment", Name: "web", Namespace: "app", Result: "dryrun", Message: "missing owner"}}, groups[0].Resources)
+	assert.Equal(t, []string{"K8sRequiredLabels/must-have-owner has 25 violations but the audit lists 3"}, index.notes)
+}
@@ -0,0 +1,224 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxLivenessDetectionSeconds is the longest a liveness probe should take to declare a container dead.
+const maxLivenessDetectionSeconds = 300
+
+// ProbeFinding is one probe problem found on a workload's container.
+type ProbeFinding struct {
+	Workload  string `json:"workload"`
+	Container string `json:"container"`
+	Probe     string `json:"probe,omitempty"`
+	Severity  string `json:"severity"`
+	Issue     string `json:"issue"`
+}
+
+// ProbeAuditTool scans workloads for missing or misconfigured probes.
+type ProbeAuditTool struct {
+	client Client
+}
+
+// NewProbeAuditTool creates a new ProbeAuditTool with the provided Kubernetes client.
+func NewProbeAuditTool(client Client) *ProbeAuditTool {
+	return &ProbeAuditTool{client: client}
+}
+
+// Tool returns the MCP tool definition for probe_audit.
+func (p *ProbeAuditTool) Tool() mcp.Tool {
+	return mcp.NewTool("probe_audit",
+		mcp.WithDescription("Audit liveness/readiness/startup probes of the Deployments, StatefulSets and DaemonSets in a namespace: missing probes, probes targeting ports the container does not expose, timeout and failureThreshold combinations that are too slow or too aggressive, and missing startup probes on containers whose liveness probe keeps failing"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to audit (defaults to 'default' if not specified)"),
+		),
+	)
+}
+
+// Handler audits the probes of every workload in the namespace.
+func (p *ProbeAuditTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace, _ := req.Params.Arguments["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	clientset, err := p.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	workloads, err := listWorkloadTemplates(ctx, clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{"namespace": namespace, "workloads": len(workloads)}
+	livenessFailures, err := livenessFailuresByWorkload(ctx, clientset, namespace)
+	if err != nil {
+		result["eventsError"] = err.Error()
+	}
+
+	findings := []ProbeFinding{}
+	for _, w := range workloads {
+		ref := w.Kind + "/" + w.Name
+		for i := range w.Template.Spec.Containers {
+			c := &w.Template.Spec.Containers[i]
+			for _, f := range auditContainerProbes(c) {
+				f.Workload = ref
+				findings = append(findings, f)
+			}
+			if c.LivenessProbe != nil && c.StartupProbe == nil && livenessFailures[ref] > 0 {
+				findings = append(findings, ProbeFinding{
+					Workload:  ref,
+					Container: c.Name,
+					Probe:     "startup",
+					Severity:  "warning",
+					Issue:     fmt.Sprintf("%d liveness probe failures observed in events; add a startupProbe so slow starts are not killed", livenessFailures[ref]),
+				})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity == "warning" && findings[j].Severity != "warning"
+	})
+
+	result["findings"] = findings
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// auditContainerProbes checks one container's probes against its declared ports and sane timings.
+func auditContainerProbes(c *corev1.Container) []ProbeFinding {
+	var findings []ProbeFinding
+	add := func(probe, severity, issue string) {
+		findings = append(findings, ProbeFinding{Container: c.Name, Probe: probe, Severity: severity, Issue: issue})
+	}
+
+	if c.ReadinessProbe == nil {
+		add("readiness", "warning", "no readiness probe; traffic is sent as soon as the container starts")
+	}
+	if c.LivenessProbe == nil {
+		add("liveness", "info", "no liveness probe; a hung process will not be restarted")
+	}
+
+	probes := []struct {
+		name  string
+		probe *corev1.Probe
+	}{
+		{"liveness", c.LivenessProbe},
+		{"readiness", c.ReadinessProbe},
+		{"startup", c.StartupProbe},
+	}
+	for _, entry := range probes {
+		if entry.probe == nil {
+			continue
+		}
+		if issue := probePortIssue(c, entry.probe); issue != "" {
+			add(entry.name, "warning", issue)
+		}
+
+		summary := summarizeProbe(entry.probe)
+		if summary.TimeoutSeconds >= summary.PeriodSeconds {
+			add(entry.name, "warning", fmt.Sprintf("timeoutSeconds (%d) is not shorter than periodSeconds (%d)", summary.TimeoutSeconds, summary.PeriodSeconds))
+		}
+		if entry.name != "liveness" {
+			continue
+		}
+		if detection := summary.FailureThreshold * summary.PeriodSeconds; detection > maxLivenessDetectionSeconds {
+			add(entry.name, "info", fmt.Sprintf("failureThreshold %d x periodSeconds %d takes %ds to detect a dead container", summary.FailureThreshold, summary.PeriodSeconds, detection))
+		}
+		if summary.FailureThreshold == 1 {
+			add(entry.name, "warning", "failureThreshold 1 restarts the container on a single failed check")
+		}
+		if c.ReadinessProbe != nil && summarizeProbe(c.ReadinessProbe).Handler == summary.Handler &&
+			c.ReadinessProbe.FailureThreshold == entry.probe.FailureThreshold && c.ReadinessProbe.PeriodSeconds == entry.probe.PeriodSeconds {
+			add(entry.name, "info", "liveness and readiness probes are identical; a failing dependency will restart the container instead of only removing it from endpoints")
+		}
+	}
+	return findings
+}
+
+// probePortIssue reports a probe that targets a port the container does not declare.
+// Numeric ports are only checked when the container declares at least one port.
+func probePortIssue(c *corev1.Container, probe *corev1.Probe) string {
+	var port *intstr.IntOrString
+	switch {
+	case probe.HTTPGet != nil:
+		port = &probe.HTTPGet.Port
+	case probe.TCPSocket != nil:
+		port = &probe.TCPSocket.Port
+	case probe.GRPC != nil:
+		p := intstr.FromInt32(probe.GRPC.Port)
+		port = &p
+	default:
+		return ""
+	}
+
+	if port.Type == intstr.String {
+		for _, cp := range c.Ports {
+			if cp.Name == port.StrVal {
+				return ""
+			}
+		}
+		return fmt.Sprintf("probe uses named port %q which the container does not define", port.StrVal)
+	}
+	if len(c.Ports) == 0 {
+		return ""
+	}
+	declared := make([]string, 0, len(c.Ports))
+	for _, cp := range c.Ports {
+		if cp.ContainerPort == port.IntVal {
+			return ""
+		}
+		declared = append(declared, fmt.Sprint(cp.ContainerPort))
+	}
+	return fmt.Sprintf("probe targets port %d but the container only exposes %s", port.IntVal, strings.Join(declared, ", "))
+}
+
+// livenessFailuresByWorkload counts liveness probe failure events per "Kind/name" workload.
+func livenessFailuresByWorkload(ctx context.Context, clientset kubernetes.Interface, namespace string) (map[string]int32, error) {
+	events, err := listEvents(ctx, clientset, namespace, "Pod", "", true)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	replicaSets := replicaSetsByKey(ctx, clientset, namespace)
+	owners := map[string]string{}
+	for i := range pods.Items {
+		kind, name := workloadOwner(&pods.Items[i], replicaSets)
+		owners["Pod/"+pods.Items[i].Name] = kind + "/" + name
+	}
+
+	failures := map[string]int32{}
+	for _, ev := range events {
+		if ev.Reason != "Unhealthy" || !strings.HasPrefix(ev.Message, "Liveness probe failed") {
+			continue
+		}
+		if owner, ok := owners[ev.Object]; ok {
+			failures[owner] += max(ev.Count, 1)
+		}
+	}
+	return failures, nil
+}
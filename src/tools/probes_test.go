@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestAuditContainerProbes(t *testing.T) {
+	httpProbe := func(port intstr.IntOrString) *corev1.Probe {
+		return &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: port}}}
+	}
+
+	testCases := []struct {
+		name      string
+		container corev1.Container
+		expected  []string
+	}{
+		{
+			name:      "NoProbes",
+			container: corev1.Container{Name: "app"},
+			expected: []string{
+				"no readiness probe; traffic is sent as soon as the container starts",
+				"no liveness probe; a hung process will not be restarted",
+			},
+		},
+		{
+			name: "Healthy",
+			container: corev1.Container{
+				Name:           "app",
+				Ports:          []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				LivenessProbe:  httpProbe(intstr.FromString("http")),
+				ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(8080)}}},
+			},
+		},
+		{
+			name: "WrongPorts",
+			container: corev1.Container{
+				Name:           "app",
+				Ports:          []corev1.ContainerPort{{ContainerPort: 8080}},
+				LivenessProbe:  httpProbe(intstr.FromString("metrics")),
+				ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(9090)}}},
+			},
+			expected: []string{
+				`probe uses named port "metrics" which the container does not define`,
+				"probe targets port 9090 but the container only exposes 8080",
+			},
+		},
+		{
+			name: "BadTimings",
+			container: corev1.Container{
+				Name: "app",
+				LivenessProbe: &corev1.Probe{
+					ProbeHandler:     corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"true"}}},
+					PeriodSeconds:    5,
+					TimeoutSeconds:   10,
+					FailureThreshold: 1,
+				},
+				ReadinessProbe: &corev1.Probe{
+					ProbeHandler:     corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"ready"}}},
+					PeriodSeconds:    60,
+					FailureThreshold: 10,
+				},
+			},
+			expected: []string{
+				"timeoutSeconds (10) is not shorter than periodSeconds (5)",
+				"failureThreshold 1 restarts the container on a single failed check",
+			},
+		},
+		{
+			name: "IdenticalProbes",
+			container: corev1.Container{
+				Name:           "app",
+				LivenessProbe:  httpProbe(intstr.FromInt32(8080)),
+				ReadinessProbe: httpProbe(intstr.FromInt32(8080)),
+			},
+			expected: []string{
+				"liveness and readiness probes are identical; a failing dependency will restart the container instead of only removing it from endpoints",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var issues []string
+			for _, f := range auditContainerProbes(&tc.container) {
+				issues = append(issues, f.Issue)
+			}
+			assert.Equal(t, tc.expected, issues)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Defaults for tools that wait for a rollout to finish.
+const (
+	defaultRolloutWaitTimeout = 5 * time.Minute
+	maxRolloutWaitTimeout     = 30 * time.Minute
+	rolloutPollInterval       = 2 * time.Second
+)
+
+// progressNotifier sends MCP progress notifications for a request that carried a progress token.
+// Without a token, or outside a client session, notifications are silently skipped.
+type progressNotifier struct {
+	ctx   context.Context
+	srv   *server.MCPServer
+	token mcp.ProgressToken
+	last  float64
+}
+
+func newProgressNotifier(ctx context.Context, req mcp.CallToolRequest) *progressNotifier {
+	p := &progressNotifier{ctx: ctx, srv: server.ServerFromContext(ctx), last: -1}
+	if req.Params.Meta != nil {
+		p.token = req.Params.Meta.ProgressToken
+	}
+	return p
+}
+
+// notify reports progress out of total. The protocol requires progress to increase with every
+// notification, so values that do not move forward are dropped.
+func (p *progressNotifier) notify(progress, total float64, message string) {
+	if p.srv == nil || p.token == nil || progress <= p.last {
+		return
+	}
+	p.last = progress
+	_ = p.srv.SendNotificationToClient(p.ctx, "notifications/progress", map[string]any{
+		"progressToken": p.token,
+		"progress":      progress,
+		"total":         total,
+		"message":       message,
+	})
+}
+
+// RolloutProgress is a snapshot of a Deployment rollout, evaluated like 'kubectl rollout status'.
+type RolloutProgress struct {
+	Replicas  int32  `json:"replicas"`
+	Updated   int32  `json:"updated"`
+	Ready     int32  `json:"ready"`
+	Available int32  `json:"available"`
+	Done      bool   `json:"done"`
+	Stalled   bool   `json:"stalled,omitempty"`
+	Message   string `json:"message"`
+}
+
+// deploymentRolloutProgress evaluates a Deployment's rollout status.
+func deploymentRolloutProgress(d *appsv1.Deployment) RolloutProgress {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	p := RolloutProgress{
+		Replicas:  replicas,
+		Updated:   d.Status.UpdatedReplicas,
+		Ready:     d.Status.ReadyReplicas,
+		Available: d.Status.AvailableReplicas,
+	}
+
+	if d.Generation > d.Status.ObservedGeneration {
+		p.Message = "waiting for the deployment spec update to be observed"
+		return p
+	}
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			p.Stalled = true
+			p.Message = fmt.Sprintf("rollout exceeded its progress deadline: %s", cond.Message)
+			return p
+		}
+	}
+	switch {
+	case p.Updated < replicas:
+		p.Message = fmt.Sprintf("%d of %d updated replicas", p.Updated, replicas)
+	case d.Status.Replicas > p.Updated:
+		p.Message = fmt.Sprintf("%d old replicas pending termination", d.Status.Replicas-p.Updated)
+	case p.Available < p.Updated:
+		p.Message = fmt.Sprintf("%d of %d updated replicas available", p.Available, p.Updated)
+	default:
+		p.Done = true
+		p.Message = "rollout complete"
+	}
+	return p
+}
+
+// waitForDeploymentRollout polls the Deployment until its rollout completes, stalls or the timeout
+// expires, sending a progress notification whenever the updated or available counts move.
+func waitForDeploymentRollout(ctx context.Context, clientset kubernetes.Interface, namespace, name string, timeout time.Duration, notifier *progressNotifier) (RolloutProgress, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		d, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return RolloutProgress{}, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		p := deploymentRolloutProgress(d)
+		if d.Generation <= d.Status.ObservedGeneration {
+			notifier.notify(float64(p.Updated+p.Available), float64(2*p.Replicas), p.Message)
+		}
+		if p.Done || p.Stalled {
+			return p, nil
+		}
+		if time.Now().After(deadline) {
+			p.Stalled = true
+			p.Message = fmt.Sprintf("timed out after %s: %s", timeout, p.Message)
+			return p, nil
+		}
+		select {
+		case <-ctx.Done():
+			return p, ctx.Err()
+		case <-time.After(rolloutPollInterval):
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeploymentRolloutProgress(t *testing.T) {
+	deployment := func(generation, observed int64, status appsv1.DeploymentStatus) *appsv1.Deployment {
+		replicas := int32(3)
+		status.ObservedGeneration = observed
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     status,
+		}
+	}
+
+	p := deploymentRolloutProgress(deployment(2, 1, appsv1.DeploymentStatus{UpdatedReplicas: 3, AvailableReplicas: 3}))
+	assert.False(t, p.Done)
+	assert.Equal(t, "waiting for the deployment spec update to be observed", p.Message)
+
+	p = deploymentRolloutProgress(deployment(2, 2, appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3}))
+	assert.False(t, p.Done)
+	assert.Equal(t, "1 of 3 updated replicas", p.Message)
+
+	p = deploymentRolloutProgress(deployment(2, 2, appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 3, AvailableReplicas: 3}))
+	assert.Equal(t, "1 old replicas pending termination", p.Message)
+
+	p = deploymentRolloutProgress(deployment(2, 2, appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2}))
+	assert.Equal(t, "2 of 3 updated replicas available", p.Message)
+
+	p = deploymentRolloutProgress(deployment(2, 2, appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3, AvailableReplicas: 3}))
+	assert.True(t, p.Done)
+	assert.Equal(t, int32(3), p.Ready)
+
+	p = deploymentRolloutProgress(deployment(2, 2, appsv1.DeploymentStatus{
+		UpdatedReplicas: 1,
+		Conditions: []appsv1.DeploymentCondition{{
+			Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded", Message: "ReplicaSet web-abc has timed out progressing.",
+		}},
+	}))
+	assert.True(t, p.Stalled)
+	assert.False(t, p.Done)
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// operationalPrompt is a prompt that expands its arguments into step-by-step instructions using this
+// server's tools.
+type operationalPrompt struct {
+	prompt   mcp.Prompt
+	defaults map[string]string
+	template *template.Template
+}
+
+// confirmationNote is appended to prompts that change the cluster.
+const confirmationNote = `If a call returns a confirmation preview instead of running, show the preview to the user and pass
+the confirmationToken back only after they approve.`
+
+var operationalPrompts = []operationalPrompt{
+	{
+		prompt: mcp.NewPrompt("diagnose_failing_pod",
+			mcp.WithPromptDescription("Find out why a pod is crashing, not ready or stuck Pending, and propose a fix"),
+			mcp.WithArgument("name", mcp.ArgumentDescription("Pod name"), mcp.RequiredArgument()),
+			mcp.WithArgument("namespace", mcp.ArgumentDescription("Pod namespace (default: default)")),
+		),
+		defaults: map[string]string{"namespace": "default"},
+		template: template.Must(template.New("diagnose_failing_pod").Parse(`Diagnose why pod {{.name}} in namespace {{.namespace}} is failing. Use the kubernetes tools and work through these steps, skipping those that do not apply:
+
+1. Call diagnose_pod with name={{.name}} and namespace={{.namespace}} for container states, exit codes, OOMKilled flags, previous-container logs, Warning events and probe settings.
+2. If the pod is Pending, call why_pending with name={{.name}} and namespace={{.namespace}} for the ranked scheduling reasons.
+3. If a container restarted, call get_pod_logs with name={{.name}}, namespace={{.namespace}} and previous=true, and look for the last error before the exit.
+4. Call object_tree with kind=Pod, name={{.name}} and namespace={{.namespace}} to find the owning workload and the ConfigMaps, Secrets and PVCs it depends on, then check that they exist.
+5. Call restart_report with namespace={{.namespace}} to see whether other pods of the same workload fail the same way.
+
+Report:
+- Root cause: one sentence, citing the evidence (exit code, event, log line or scheduling reason).
+- Impact: which workload is affected and whether other replicas are healthy.
+- Fix: the concrete change to make, such as a resource limit, probe, image, config key or node capacity, and the tool that would apply it if there is one.
+Do not change anything in the cluster unless the user asks.`)),
+	},
+	{
+		prompt: mcp.NewPrompt("prepare_node_maintenance",
+			mcp.WithPromptDescription("Check that a node can be taken out of service safely, then cordon and drain it"),
+			mcp.WithArgument("node", mcp.ArgumentDescription("Node name"), mcp.RequiredArgument()),
+		),
+		template: template.Must(template.New("prepare_node_maintenance").Parse(`Prepare node {{.node}} for maintenance. Use the kubernetes tools and work through these steps:
+
+1. Call describe_resource with kind=Node and name={{.node}}. Note its conditions, taints and allocatable resources.
+2. Call list_resources with kind=Pod and fieldSelector=spec.nodeName={{.node}} to list the pods that will be evicted, grouped by owner. Flag bare pods without a controller and pods with emptyDir or local volumes, since they will not come back.
+3. Call pdb_check with node={{.node}} to find PodDisruptionBudgets that would block eviction.
+4. Call cluster_capacity to check that the remaining nodes can absorb the evicted pods' requests.
+5. Call drain_node with node={{.node}} and dryRun=true, and summarize what would be evicted and what would block.
+
+Present a go/no-go summary with the blockers and risks found. Only after the user agrees:
+6. Call cordon_node with node={{.node}}, then drain_node with node={{.node}} and ignoreDaemonSets=true. Add deleteEmptyDirData=true only if the user accepted losing emptyDir data.
+7. Call list_resources with kind=Pod and fieldSelector=spec.nodeName={{.node}} again to confirm that only DaemonSet pods remain.
+
+` + confirmationNote + `
+After maintenance, remind the user to call uncordon_node with node={{.node}}.`)),
+	},
+	{
+		prompt: mcp.NewPrompt("review_rollout",
+			mcp.WithPromptDescription("Review the progress and health of a Deployment rollout and recommend whether to continue, wait or roll back"),
+			mcp.WithArgument("deployment", mcp.ArgumentDescription("Deployment name"), mcp.RequiredArgument()),
+			mcp.WithArgument("namespace", mcp.ArgumentDescription("Deployment namespace (default: default)")),
+		),
+		defaults: map[string]string{"namespace": "default"},
+		template: template.Must(template.New("review_rollout").Parse(`Review the rollout of Deployment {{.deployment}} in namespace {{.namespace}}. Use the kubernetes tools and work through these steps:
+
+1. Call describe_resource with kind=Deployment, name={{.deployment}} and namespace={{.namespace}}. Read the Progressing and Available conditions, the revision annotation, the strategy and the ready, updated and available replica counts.
+2. Call object_tree with kind=Deployment, name={{.deployment}} and namespace={{.namespace}} to compare the new ReplicaSet with the old ones and see which pods belong to each.
+3. For each new pod that is not Ready, call diagnose_pod with its name and namespace={{.namespace}}.
+4. Call get_pod_logs for one new pod with namespace={{.namespace}} and since=15m, and look for errors that the old pods do not show.
+5. Call pdb_check with namespace={{.namespace}} and deployment={{.deployment}} to confirm the rollout cannot take the workload below its disruption budget.
+6. Call namespace_health with namespace={{.namespace}} for related Warning events, HPAs at their maximum and dependent services.
+
+Report:
+- Status: complete, progressing, stalled or failing, with the replica counts and image change.
+- Evidence: the conditions, events and log lines that support the status.
+- Recommendation: continue, wait (and for how long), or roll back. Name the old ReplicaSet or revision to roll back to.
+Do not restart or change the Deployment unless the user asks. If they ask for a restart, call rollout_restart with dryRun=true first.
+` + confirmationNote)),
+	},
+}
+
+// RegisterPrompts adds prompts for common operational workflows to the MCP server.
+func RegisterPrompts(s *server.MCPServer) {
+	for _, p := range operationalPrompts {
+		s.AddPrompt(p.prompt, p.handler)
+	}
+}
+
+// handler validates the arguments and expands the prompt into a user message.
+func (p operationalPrompt) handler(_ context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args, err := p.arguments(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments for prompt %s: %w", p.prompt.Name, err)
+	}
+	var text strings.Builder
+	if err := p.template.Execute(&text, args); err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", p.prompt.Name, err)
+	}
+	return mcp.NewGetPromptResult(p.prompt.Description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text.String())),
+	}), nil
+}
+
+// arguments applies defaults and checks required arguments. Every argument is a Kubernetes name, so
+// values are validated before they are written into the instructions.
+func (p operationalPrompt) arguments(given map[string]string) (map[string]string, error) {
+	args := make(map[string]string, len(p.prompt.Arguments))
+	for _, arg := range p.prompt.Arguments {
+		value := strings.TrimSpace(given[arg.Name])
+		if value == "" {
+			value = p.defaults[arg.Name]
+		}
+		if value == "" {
+			if arg.Required {
+				return nil, fmt.Errorf("%s is required", arg.Name)
+			}
+			continue
+		}
+		validate := validation.ValidateResourceName
+		if arg.Name == "namespace" {
+			validate = validation.ValidateNamespace
+		}
+		if err := validate(value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", arg.Name, err)
+		}
+		args[arg.Name] = value
+	}
+	return args, nil
+}
@@ -0,0 +1,343 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// Karpenter kinds; the served version (v1 or v1beta1) is resolved through the RESTMapper.
+var (
+	karpenterNodePoolGK  = schema.GroupKind{Group: "karpenter.sh", Kind: "NodePool"}
+	karpenterNodeClaimGK = schema.GroupKind{Group: "karpenter.sh", Kind: "NodeClaim"}
+)
+
+// Labels Karpenter sets on the NodeClaims it launches.
+const (
+	karpenterNodePoolLabel     = "karpenter.sh/nodepool"
+	karpenterCapacityTypeLabel = "karpenter.sh/capacity-type"
+)
+
+// clusterAutoscalerStatusConfigMap is where cluster-autoscaler writes its status.
+const clusterAutoscalerStatusConfigMap = "cluster-autoscaler-status"
+
+// PendingCapacity is the demand of pods the scheduler could not place.
+type PendingCapacity struct {
+	Pods   int      `json:"pods"`
+	CPU    string   `json:"cpu"`
+	Memory string   `json:"memory"`
+	Sample []string `json:"sample,omitempty"`
+}
+
+// KarpenterNodePool is the state of one Karpenter NodePool.
+type KarpenterNodePool struct {
+	Name                string            `json:"name"`
+	Ready               string            `json:"ready"`
+	Message             string            `json:"message,omitempty"`
+	Weight              int64             `json:"weight,omitempty"`
+	NodeClaims          int               `json:"nodeClaims"`
+	Limits              map[string]string `json:"limits,omitempty"`
+	Usage               map[string]string `json:"usage,omitempty"`
+	ConsolidationPolicy string            `json:"consolidationPolicy,omitempty"`
+	ConsolidateAfter    string            `json:"consolidateAfter,omitempty"`
+	Budgets             []string          `json:"budgets,omitempty"`
+}
+
+// KarpenterNodeClaim is the lifecycle state of one Karpenter NodeClaim.
+type KarpenterNodeClaim struct {
+	Name           string `json:"name"`
+	NodePool       string `json:"nodePool,omitempty"`
+	NodeName       string `json:"nodeName,omitempty"`
+	InstanceType   string `json:"instanceType,omitempty"`
+	CapacityType   string `json:"capacityType,omitempty"`
+	Zone           string `json:"zone,omitempty"`
+	Age            string `json:"age"`
+	State          string `json:"state"`
+	Reason         string `json:"reason,omitempty"`
+	Message        string `json:"message,omitempty"`
+	Drifted        bool   `json:"drifted,omitempty"`
+	Consolidatable bool   `json:"consolidatable,omitempty"`
+}
+
+// pendingCapacity sums the requests of pods that the scheduler marked Unschedulable.
+func pendingCapacity(pods []corev1.Pod) PendingCapacity {
+	var totals resourceTotals
+	pending := PendingCapacity{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName != "" || !podUnschedulable(pod) {
+			continue
+		}
+		requests, _ := podRequestsAndLimits(pod)
+		totals.add(requests)
+		pending.Pods++
+		if len(pending.Sample) < 10 {
+			pending.Sample = append(pending.Sample, pod.Namespace+"/"+pod.Name)
+		}
+	}
+	pending.CPU = formatCPU(totals.CPU)
+	pending.Memory = formatMemory(totals.Memory)
+	return pending
+}
+
+// podUnschedulable reports whether the scheduler failed to place the pod.
+func podUnschedulable(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable {
+			return true
+		}
+	}
+	return false
+}
+
+// karpenterNodePoolStatus reads the limits, usage, disruption settings and readiness of a NodePool.
+func karpenterNodePoolStatus(obj *unstructured.Unstructured) KarpenterNodePool {
+	pool := KarpenterNodePool{Name: obj.GetName()}
+	pool.Weight, _, _ = unstructured.NestedInt64(obj.Object, "spec", "weight")
+	pool.Limits = nestedStringValues(obj.Object, "spec", "limits")
+	pool.Usage = nestedStringValues(obj.Object, "status", "resources")
+	pool.ConsolidationPolicy, _, _ = unstructured.NestedString(obj.Object, "spec", "disruption", "consolidationPolicy")
+	pool.ConsolidateAfter, _, _ = unstructured.NestedString(obj.Object, "spec", "disruption", "consolidateAfter")
+
+	budgets, _, _ := unstructured.NestedSlice(obj.Object, "spec", "disruption", "budgets")
+	for _, b := range budgets {
+		budget, ok := b.(map[string]any)
+		if !ok {
+			continue
+		}
+		nodes, _, _ := unstructured.NestedString(budget, "nodes")
+		parts := []string{"nodes=" + nodes}
+		if reasons, _, _ := unstructured.NestedStringSlice(budget, "reasons"); len(reasons) > 0 {
+			parts = append(parts, "reasons="+strings.Join(reasons, ","))
+		}
+		if schedule, _, _ := unstructured.NestedString(budget, "schedule"); schedule != "" {
+			duration, _, _ := unstructured.NestedString(budget, "duration")
+			parts = append(parts, fmt.Sprintf("schedule=%q for %s", schedule, duration))
+		}
+		pool.Budgets = append(pool.Budgets, strings.Join(parts, " "))
+	}
+
+	var message string
+	pool.Ready, _, message = readyCondition(obj)
+	if pool.Ready != "True" {
+		pool.Message = message
+	}
+	return pool
+}
+
+// nestedStringValues renders a map of quantities (which may be decoded as strings or numbers) as strings.
+func nestedStringValues(obj map[string]any, path ...string) map[string]string {
+	values, _, _ := unstructured.NestedMap(obj, path...)
+	if len(values) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
+
+// karpenterNodeClaimStatus derives the lifecycle state of a NodeClaim from its conditions. Launched,
+// Registered and Initialized are set in order, so the first one that is not True is where it is stuck.
+func karpenterNodeClaimStatus(obj *unstructured.Unstructured, now time.Time) KarpenterNodeClaim {
+	labels := obj.GetLabels()
+	claim := KarpenterNodeClaim{
+		Name:         obj.GetName(),
+		NodePool:     labels[karpenterNodePoolLabel],
+		InstanceType: labels[corev1.LabelInstanceTypeStable],
+		CapacityType: labels[karpenterCapacityTypeLabel],
+		Zone:         labels[corev1.LabelTopologyZone],
+		Age:          now.Sub(obj.GetCreationTimestamp().Time).Round(time.Second).String(),
+		State:        "ready",
+	}
+	claim.NodeName, _, _ = unstructured.NestedString(obj.Object, "status", "nodeName")
+
+	for _, step := range []struct{ condition, state string }{
+		{"Launched", "launching"},
+		{"Registered", "registering"},
+		{"Initialized", "initializing"},
+		{"Ready", "notReady"},
+	} {
+		status, reason, message := objectCondition(obj, step.condition)
+		if status == "True" {
+			continue
+		}
+		claim.State = step.state
+		if status == "False" && step.condition == "Launched" {
+			claim.State = "launchFailed"
+		}
+		claim.Reason, claim.Message = reason, message
+		break
+	}
+	if obj.GetDeletionTimestamp() != nil {
+		claim.State = "disrupting"
+	}
+	if status, reason, _ := objectCondition(obj, "Drifted"); status == "True" {
+		claim.Drifted = true
+		if claim.Reason == "" {
+			claim.Reason = reason
+		}
+	}
+	for _, condition := range []string{"Consolidatable", "Empty"} {
+		if status, _, _ := objectCondition(obj, condition); status == "True" {
+			claim.Consolidatable = true
+		}
+	}
+	return claim
+}
+
+// classifyProvisioningEvents splits autoscaler events into failures (Warnings) and consolidation or
+// scale-down activity, keeping at most max of each.
+func classifyProvisioningEvents(events []EventSummary, max int) (failures, consolidation []EventSummary) {
+	failures, consolidation = []EventSummary{}, []EventSummary{}
+	for _, ev := range events {
+		reason := strings.ToLower(ev.Reason)
+		switch {
+		case ev.Type == corev1.EventTypeWarning:
+			if len(failures) < max {
+				failures = append(failures, ev)
+			}
+		case strings.HasPrefix(reason, "disrupt") || strings.Contains(reason, "consolidat") || strings.HasPrefix(reason, "scaledown"):
+			if len(consolidation) < max {
+				consolidation = append(consolidation, ev)
+			}
+		}
+	}
+	return failures, consolidation
+}
+
+// NodeProvisioningStatusTool reports the state of Karpenter or cluster-autoscaler node provisioning.
+type NodeProvisioningStatusTool struct {
+	client Client
+}
+
+// NewNodeProvisioningStatusTool creates a new NodeProvisioningStatusTool with the provided Kubernetes client.
+func NewNodeProvisioningStatusTool(client Client) *NodeProvisioningStatusTool {
+	return &NodeProvisioningStatusTool{client: client}
+}
+
+// Tool returns the MCP tool definition for node_provisioning_status.
+func (n *NodeProvisioningStatusTool) Tool() mcp.Tool {
+	return mcp.NewTool("node_provisioning_status",
+		mcp.WithDescription("Diagnose node autoscaling: unschedulable pods waiting for capacity, Karpenter NodePools (limits vs usage, disruption budgets) and NodeClaims stuck launching, registering or initializing, or cluster-autoscaler's status ConfigMap when Karpenter is not installed, plus recent provisioning failures and consolidation/scale-down activity from events"),
+		mcp.WithString("autoscalerNamespace",
+			mcp.Description("Namespace of the cluster-autoscaler status ConfigMap (default: kube-system)"),
+		),
+		mcp.WithNumber("maxEvents",
+			mcp.Description("Maximum number of failure and consolidation events to return each (default: 20)"),
+		),
+	)
+}
+
+// Handler gathers pending demand, provisioner state and autoscaler events.
+func (n *NodeProvisioningStatusTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	autoscalerNamespace, _ := args["autoscalerNamespace"].(string)
+	if err := validation.ValidateNamespace(autoscalerNamespace); err != nil {
+		return nil, fmt.Errorf("invalid autoscalerNamespace: %w", err)
+	}
+	if autoscalerNamespace == "" {
+		autoscalerNamespace = metav1.NamespaceSystem
+	}
+	maxEvents := 20
+	if v, ok := args["maxEvents"].(float64); ok && v > 0 {
+		maxEvents = int(v)
+	}
+
+	clientset, err := n.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Pending"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending pods: %w", err)
+	}
+	result := map[string]any{"pendingCapacity": pendingCapacity(pods.Items)}
+
+	component := "karpenter"
+	poolRI, err := groupKindResourceInterface(n.client, karpenterNodePoolGK, "")
+	if err == nil {
+		result["provisioner"] = "karpenter"
+		poolItems, err := poolRI.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list NodePools: %w", err)
+		}
+		claimRI, err := groupKindResourceInterface(n.client, karpenterNodeClaimGK, "")
+		if err != nil {
+			return nil, err
+		}
+		claimItems, err := claimRI.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list NodeClaims: %w", err)
+		}
+
+		now := time.Now()
+		claims := make([]KarpenterNodeClaim, 0, len(claimItems.Items))
+		claimsPerPool := map[string]int{}
+		states := map[string]int{}
+		for i := range claimItems.Items {
+			claim := karpenterNodeClaimStatus(&claimItems.Items[i], now)
+			claimsPerPool[claim.NodePool]++
+			states[claim.State]++
+			claims = append(claims, claim)
+		}
+		// NodeClaims that are not ready come first; they are where capacity is stuck.
+		sort.SliceStable(claims, func(i, j int) bool { return claims[i].State != "ready" && claims[j].State == "ready" })
+
+		pools := make([]KarpenterNodePool, 0, len(poolItems.Items))
+		for i := range poolItems.Items {
+			pool := karpenterNodePoolStatus(&poolItems.Items[i])
+			pool.NodeClaims = claimsPerPool[pool.Name]
+			pools = append(pools, pool)
+		}
+		result["nodePools"] = pools
+		result["nodeClaims"] = claims
+		result["nodeClaimStates"] = states
+	} else {
+		component = "cluster-autoscaler"
+		cm, err := clientset.CoreV1().ConfigMaps(autoscalerNamespace).Get(ctx, clusterAutoscalerStatusConfigMap, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			result["provisioner"] = "none"
+			result["message"] = fmt.Sprintf("neither Karpenter NodePools nor the %s/%s ConfigMap were found", autoscalerNamespace, clusterAutoscalerStatusConfigMap)
+		case err != nil:
+			return nil, fmt.Errorf("failed to get cluster-autoscaler status: %w", err)
+		default:
+			result["provisioner"] = "cluster-autoscaler"
+			// Recent cluster-autoscaler versions write YAML, older ones a plain text report.
+			var status map[string]any
+			if yaml.Unmarshal([]byte(cm.Data["status"]), &status) == nil && status != nil {
+				result["autoscalerStatus"] = status
+			} else {
+				result["autoscalerStatus"] = cm.Data["status"]
+			}
+		}
+	}
+
+	events, err := listEventsMatching(ctx, clientset, metav1.NamespaceAll, fields.Set{"source": component})
+	if err != nil {
+		return nil, err
+	}
+	result["failures"], result["consolidation"] = classifyProvisioningEvents(events, maxEvents)
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
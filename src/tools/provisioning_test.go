@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPendingCapacity(t *testing.T) {
+	unschedulable := corev1.PodStatus{Conditions: []corev1.PodCondition{{
+		Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable,
+	}}}
+	container := corev1.Container{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}}}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "app"}, Spec: corev1.PodSpec{Containers: []corev1.Container{container}}, Status: unschedulable},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "app"}, Spec: corev1.PodSpec{Containers: []corev1.Container{container}}, Status: unschedulable},
+		// Pending while pulling images, not waiting for capacity.
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "app"}, Spec: corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{container}}},
+	}
+
+	pending := pendingCapacity(pods)
+	assert.Equal(t, 2, pending.Pods)
+	assert.Equal(t, formatCPU(1000), pending.CPU)
+	assert.Equal(t, formatMemory(2<<30), pending.Memory)
+	assert.Equal(t, []string{"app/a", "app/b"}, pending.Sample)
+}
+
+func TestKarpenterNodeClaimStatus(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	claim := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"name":              "default-x7k2p",
+			"creationTimestamp": now.Add(-5 * time.Minute).UTC().Format(time.RFC3339),
+			"labels": map[string]any{
+				karpenterNodePoolLabel:         "default",
+				corev1.LabelInstanceTypeStable: "m5.large",
+				karpenterCapacityTypeLabel:     "spot",
+			},
+		},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Launched", "status": "False", "reason": "InsufficientCapacity", "message": "no spot capacity in us-east-1a"},
+		}},
+	}}
+
+	status := karpenterNodeClaimStatus(claim, now)
+	assert.Equal(t, "default", status.NodePool)
+	assert.Equal(t, "m5.large", status.InstanceType)
+	assert.Equal(t, "spot", status.CapacityType)
+	assert.Equal(t, "5m0s", status.Age)
+	assert.Equal(t, "launchFailed", status.State)
+	assert.Equal(t, "InsufficientCapacity", status.Reason)
+
+	_ = unstructured.SetNestedSlice(claim.Object, []any{
+		map[string]any{"type": "Launched", "status": "True"},
+		map[string]any{"type": "Registered", "status": "Unknown", "message": "node not registered"},
+	}, "status", "conditions")
+	assert.Equal(t, "registering", karpenterNodeClaimStatus(claim, now).State)
+
+	_ = unstructured.SetNestedSlice(claim.Object, []any{
+		map[string]any{"type": "Launched", "status": "True"},
+		map[string]any{"type": "Registered", "status": "True"},
+		map[string]any{"type": "Initialized", "status": "True"},
+		map[string]any{"type": "Ready", "status": "True"},
+		map[string]any{"type": "Drifted", "status": "True", "reason": "NodePoolDrifted"},
+	}, "status", "conditions")
+	status = karpenterNodeClaimStatus(claim, now)
+	assert.Equal(t, "ready", status.State)
+	assert.True(t, status.Drifted)
+	assert.Equal(t, "NodePoolDrifted", status.Reason)
+}
+
+func TestKarpenterNodePoolStatus(t *testing.T) {
+	pool := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "default"},
+		"spec": map[string]any{
+			"limits": map[string]any{"cpu": "100"},
+			"disruption": map[string]any{
+				"consolidationPolicy": "WhenEmptyOrUnderutilized",
+				"consolidateAfter":    "1m",
+				"budgets": []any{
+					map[string]any{"nodes": "10%"},
+					map[string]any{"nodes": "0", "reasons": []any{"Drifted"}, "schedule": "0 9 * * mon-fri", "duration": "8h"},
+				},
+			},
+		},
+		"status": map[string]any{
+			"resources":  map[string]any{"cpu": "96", "nodes": int64(12)},
+			"conditions": []any{map[string]any{"type": "Ready", "status": "True"}},
+		},
+	}}
+
+	status := karpenterNodePoolStatus(pool)
+	assert.Equal(t, map[string]string{"cpu": "100"}, status.Limits)
+	assert.Equal(t, map[string]string{"cpu": "96", "nodes": "12"}, status.Usage)
+	assert.Equal(t, "WhenEmptyOrUnderutilized", status.ConsolidationPolicy)
+	assert.Equal(t, []string{"nodes=10%", `nodes=0 reasons=Drifted schedule="0 9 * * mon-fri" for 8h`}, status.Budgets)
+	assert.Equal(t, "True", status.Ready)
+}
+
+func TestClassifyProvisioningEvents(t *testing.T) {
+	events := []EventSummary{
+		{Type: "Warning", Reason: "FailedScaleUp"},
+		{Type: "Normal", Reason: "DisruptionTerminating"},
+		{Type: "Normal", Reason: "ScaleDownEmpty"},
+		{Type: "Normal", Reason: "TriggeredScaleUp"},
+		{Type: "Warning", Reason: "InsufficientCapacityError"},
+	}
+
+	failures, consolidation := classifyProvisioningEvents(events, 1)
+	assert.Equal(t, []EventSummary{{Type: "Warning", Reason: "FailedScaleUp"}}, failures)
+	assert.Equal(t, []EventSummary{{Type: "Normal", Reason: "DisruptionTerminating"}}, consolidation)
+}
@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultPVCUsageThreshold = 80
+	defaultPVCUsageLimit     = 50
+	// nodeStatsConcurrency is the number of kubelets queried at the same time.
+	nodeStatsConcurrency = 8
+)
+
+// kubeletStatsSummary is the part of the kubelet /stats/summary response that describes volumes.
+type kubeletStatsSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Volumes []struct {
+			Name           string  `json:"name"`
+			CapacityBytes  *uint64 `json:"capacityBytes"`
+			UsedBytes      *uint64 `json:"usedBytes"`
+			AvailableBytes *uint64 `json:"availableBytes"`
+			Inodes         *uint64 `json:"inodes"`
+			InodesUsed     *uint64 `json:"inodesUsed"`
+			PVCRef         *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// PVCUsage is the filesystem usage of a PersistentVolumeClaim as measured by the kubelet.
+type PVCUsage struct {
+	Namespace         string  `json:"namespace"`
+	Name              string  `json:"name"`
+	Node              string  `json:"node"`
+	Pod               string  `json:"pod"`
+	Used              string  `json:"used"`
+	Capacity          string  `json:"capacity"`
+	Available         string  `json:"available,omitempty"`
+	UsedPercent       float64 `json:"usedPercent"`
+	InodesUsedPercent float64 `json:"inodesUsedPercent,omitempty"`
+	AboveThreshold    bool    `json:"aboveThreshold,omitempty"`
+
+	usedBytes uint64
+}
+
+// PVCUsageInput represents the input for pvc_usage.
+type PVCUsageInput struct {
+	Namespace string  `json:"namespace,omitempty"`
+	Node      string  `json:"node,omitempty"`
+	Threshold float64 `json:"threshold"`
+	Limit     int     `json:"limit"`
+}
+
+// PVCUsageTool reports how full PersistentVolumeClaims are from the kubelet volume stats.
+type PVCUsageTool struct {
+	client Client
+}
+
+// NewPVCUsageTool creates a new PVCUsageTool with the provided Kubernetes client.
+func NewPVCUsageTool(client Client) *PVCUsageTool {
+	return &PVCUsageTool{client: client}
+}
+
+// Tool returns the MCP tool definition for pvc_usage.
+func (p *PVCUsageTool) Tool() mcp.Tool {
+	return mcp.NewTool("pvc_usage",
+		mcp.WithDescription("Report used vs capacity bytes and inodes of PersistentVolumeClaims, as measured by the kubelets (node stats/summary through the API server proxy), fullest first, and flag volumes above a threshold. Answers \"which disks are nearly full?\". Only claims mounted by a running pod, on a driver that reports volume stats, can be measured"),
+		mcp.WithString("namespace",
+			mcp.Description("Only report claims in this namespace (default: all namespaces)"),
+		),
+		mcp.WithString("node",
+			mcp.Description("Only query the kubelet of this node (default: every node)"),
+		),
+		mcp.WithNumber("threshold",
+			mcp.Description(fmt.Sprintf("Percentage of capacity or inodes used above which a volume is flagged (default: %d)", defaultPVCUsageThreshold)),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of claims to return, fullest first (default: %d)", defaultPVCUsageLimit)),
+		),
+	)
+}
+
+// Handler queries the kubelets and returns the usage of each claim.
+func (p *PVCUsageTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidatePVCUsageParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate pvc_usage params: %w", err)
+	}
+	clientset, err := p.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	var nodes []string
+	if input.Node != "" {
+		nodes = []string{input.Node}
+	} else {
+		nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+		for i := range nodeList.Items {
+			if nodeReady(&nodeList.Items[i]) {
+				nodes = append(nodes, nodeList.Items[i].Name)
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	var usages []PVCUsage
+	failed := map[string]string{}
+	sem := make(chan struct{}, nodeStatsConcurrency)
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			body, err := clientset.CoreV1().RESTClient().Get().Resource("nodes").Name(node).SubResource("proxy").Suffix("stats/summary").DoRaw(ctx)
+			var found []PVCUsage
+			if err == nil {
+				found, err = parseVolumeStats(body, node, input.Namespace)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[node] = err.Error()
+				return
+			}
+			usages = append(usages, found...)
+		}()
+	}
+	wg.Wait()
+
+	usages = rankPVCUsage(usages, input.Threshold)
+	var flagged []string
+	for _, u := range usages {
+		if u.AboveThreshold {
+			flagged = append(flagged, u.Namespace+"/"+u.Name)
+		}
+	}
+	result := map[string]any{
+		"threshold":      input.Threshold,
+		"nodesQueried":   len(nodes),
+		"claims":         len(usages),
+		"aboveThreshold": flagged,
+	}
+	if len(usages) > input.Limit {
+		result["truncated"] = true
+		usages = usages[:input.Limit]
+	}
+	result["usage"] = usages
+	if len(failed) > 0 {
+		result["failedNodes"] = failed
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseVolumeStats returns the usage of the claims in a kubelet stats summary, optionally only
+// those in namespace. Volumes without capacity or usage figures are skipped.
+func parseVolumeStats(body []byte, node, namespace string) ([]PVCUsage, error) {
+	var summary kubeletStatsSummary
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse stats summary: %w", err)
+	}
+	var usages []PVCUsage
+	for _, pod := range summary.Pods {
+		for _, v := range pod.Volumes {
+			if v.PVCRef == nil || v.CapacityBytes == nil || v.UsedBytes == nil || *v.CapacityBytes == 0 {
+				continue
+			}
+			if namespace != "" && v.PVCRef.Namespace != namespace {
+				continue
+			}
+			usage := PVCUsage{
+				Namespace:   v.PVCRef.Namespace,
+				Name:        v.PVCRef.Name,
+				Node:        node,
+				Pod:         pod.PodRef.Name,
+				Used:        formatMemory(int64(*v.UsedBytes)),
+				Capacity:    formatMemory(int64(*v.CapacityBytes)),
+				UsedPercent: percentOf(int64(*v.UsedBytes), int64(*v.CapacityBytes)),
+				usedBytes:   *v.UsedBytes,
+			}
+			if v.AvailableBytes != nil {
+				usage.Available = formatMemory(int64(*v.AvailableBytes))
+			}
+			if v.Inodes != nil && v.InodesUsed != nil && *v.Inodes > 0 {
+				usage.InodesUsedPercent = percentOf(int64(*v.InodesUsed), int64(*v.Inodes))
+			}
+			usages = append(usages, usage)
+		}
+	}
+	return usages, nil
+}
+
+// rankPVCUsage keeps one entry per claim, mounted by several pods or nodes, flags those above the
+// threshold and sorts them fullest first.
+func rankPVCUsage(usages []PVCUsage, threshold float64) []PVCUsage {
+	byClaim := map[string]PVCUsage{}
+	for _, u := range usages {
+		key := u.Namespace + "/" + u.Name
+		if existing, ok := byClaim[key]; !ok || u.usedBytes > existing.usedBytes {
+			byClaim[key] = u
+		}
+	}
+	ranked := make([]PVCUsage, 0, len(byClaim))
+	for _, u := range byClaim {
+		u.AboveThreshold = u.UsedPercent >= threshold || u.InodesUsedPercent >= threshold
+		ranked = append(ranked, u)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := max(ranked[i].UsedPercent, ranked[i].InodesUsedPercent), max(ranked[j].UsedPercent, ranked[j].InodesUsedPercent)
+		if a != b {
+			return a > b
+		}
+		return ranked[i].Namespace+"/"+ranked[i].Name < ranked[j].Namespace+"/"+ranked[j].Name
+	})
+	return ranked
+}
+
+func parseAndValidatePVCUsageParams(args map[string]any) (*PVCUsageInput, error) {
+	input := &PVCUsageInput{Threshold: defaultPVCUsageThreshold, Limit: defaultPVCUsageLimit}
+	input.Namespace, _ = args["namespace"].(string)
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	if node, ok := args["node"].(string); ok && node != "" {
+		if err := validation.ValidateResourceName(node); err != nil {
+			return nil, fmt.Errorf("invalid node: %w", err)
+		}
+		input.Node = node
+	}
+	if threshold, ok := args["threshold"].(float64); ok {
+		if threshold <= 0 || threshold > 100 {
+			return nil, fmt.Errorf("threshold must be between 0 and 100")
+		}
+		input.Threshold = threshold
+	}
+	if limit, ok := args["limit"].(float64); ok && limit > 0 {
+		input.Limit = int(limit)
+	}
+	return input, nil
+}
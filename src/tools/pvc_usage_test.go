@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const statsSummary = `{
+  "node": {"nodeName": "node-1"},
+  "pods": [
+    {
+      "podRef": {"name": "postgres-0", "namespace": "db"},
+      "volume": [
+        {"name": "data", "capacityBytes": 10737418240, "usedBytes": 9663676416, "availableBytes": 1073741824, "inodes": 655360, "inodesUsed": 1000, "pvcRef": {"name": "data-postgres-0", "namespace": "db"}},
+        {"name": "kube-api-access-x", "capacityBytes": 1000, "usedBytes": 10}
+      ]
+    },
+    {
+      "podRef": {"name": "uploader", "namespace": "web"},
+      "volume": [
+        {"name": "files", "capacityBytes": 1073741824, "usedBytes": 104857600, "inodes": 1000, "inodesUsed": 950, "pvcRef": {"name": "files", "namespace": "web"}},
+        {"name": "pending", "pvcRef": {"name": "no-stats", "namespace": "web"}}
+      ]
+    }
+  ]
+}`
+
+func TestParseVolumeStats(t *testing.T) {
+	usages, err := parseVolumeStats([]byte(statsSummary), "node-1", "")
+	assert.NoError(t, err)
+	assert.Len(t, usages, 2)
+	assert.Equal(t, PVCUsage{
+		Namespace:         "db",
+		Name:              "data-postgres-0",
+		Node:              "node-1",
+		Pod:               "postgres-0",
+		Used:              "9216Mi",
+		Capacity:          "10240Mi",
+		Available:         "1024Mi",
+		UsedPercent:       90,
+		InodesUsedPercent: 0.2,
+		usedBytes:         9663676416,
+	}, usages[0])
+
+	usages, err = parseVolumeStats([]byte(statsSummary), "node-1", "web")
+	assert.NoError(t, err)
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "files", usages[0].Name)
+
+	_, err = parseVolumeStats([]byte("not json"), "node-1", "")
+	assert.Error(t, err)
+}
+
+func TestRankPVCUsage(t *testing.T) {
+	usages := []PVCUsage{
+		{Namespace: "web", Name: "files", Pod: "uploader-a", UsedPercent: 10, InodesUsedPercent: 95, usedBytes: 100},
+		{Namespace: "web", Name: "files", Pod: "uploader-b", UsedPercent: 12, InodesUsedPercent: 95, usedBytes: 120},
+		{Namespace: "db", Name: "data-postgres-0", UsedPercent: 90, usedBytes: 900},
+		{Namespace: "cache", Name: "redis", UsedPercent: 40, usedBytes: 400},
+	}
+
+	ranked := rankPVCUsage(usages, 80)
+	assert.Len(t, ranked, 3)
+	assert.Equal(t, "files", ranked[0].Name)
+	assert.Equal(t, "uploader-b", ranked[0].Pod)
+	assert.True(t, ranked[0].AboveThreshold)
+	assert.Equal(t, "data-postgres-0", ranked[1].Name)
+	assert.True(t, ranked[1].AboveThreshold)
+	assert.Equal(t, "redis", ranked[2].Name)
+	assert.False(t, ranked[2].AboveThreshold)
+}
+
+func TestParseAndValidatePVCUsageParams(t *testing.T) {
+	input, err := parseAndValidatePVCUsageParams(map[string]any{})
+	assert.NoError(t, err)
+	assert.Equal(t, &PVCUsageInput{Threshold: defaultPVCUsageThreshold, Limit: defaultPVCUsageLimit}, input)
+
+	input, err = parseAndValidatePVCUsageParams(map[string]any{"namespace": "db", "node": "node-1", "threshold": float64(90), "limit": float64(5)})
+	assert.NoError(t, err)
+	assert.Equal(t, &PVCUsageInput{Namespace: "db", Node: "node-1", Threshold: 90, Limit: 5}, input)
+
+	_, err = parseAndValidatePVCUsageParams(map[string]any{"threshold": float64(150)})
+	assert.EqualError(t, err, "threshold must be between 0 and 100")
+}
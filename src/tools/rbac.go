@@ -0,0 +1,376 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AccessCheckInput describes the action an access check is about.
+type AccessCheckInput struct {
+	Verb         string   `json:"verb"`
+	Resource     string   `json:"resource"`
+	Subresource  string   `json:"subresource,omitempty"`
+	Group        string   `json:"group,omitempty"`
+	ResourceName string   `json:"resourceName,omitempty"`
+	Namespace    string   `json:"namespace,omitempty"`
+	User         string   `json:"user,omitempty"`
+	Groups       []string `json:"groups,omitempty"`
+}
+
+// AccessGrant is one subject that is allowed an action, and the binding and role that grant it.
+type AccessGrant struct {
+	Subject   string `json:"subject"`
+	Kind      string `json:"kind"`
+	Binding   string `json:"binding"`
+	Role      string `json:"role"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// CanITool asks the API server whether an action is allowed.
+type CanITool struct {
+	client Client
+}
+
+// NewCanITool creates a new CanITool with the provided Kubernetes client.
+func NewCanITool(client Client) *CanITool {
+	return &CanITool{client: client}
+}
+
+// Tool returns the MCP tool definition for can_i.
+func (c *CanITool) Tool() mcp.Tool {
+	return mcp.NewTool("can_i",
+		mcp.WithDescription("Check whether an action is allowed, like 'kubectl auth can-i'. Without user or groups the server's own identity is checked with a SelfSubjectAccessReview; otherwise a SubjectAccessReview is submitted for that user, e.g. 'system:serviceaccount:<namespace>:<name>'"),
+		mcp.WithString("verb",
+			mcp.Required(),
+			mcp.Description("Verb to check, e.g. get, list, watch, create, update, patch, delete"),
+		),
+		mcp.WithString("resource",
+			mcp.Required(),
+			mcp.Description("Resource to check, e.g. pods, deployments or pods/log"),
+		),
+		mcp.WithString("group",
+			mcp.Description("API group of the resource, e.g. apps (empty for the core group)"),
+		),
+		mcp.WithString("resourceName",
+			mcp.Description("Name of a specific object to check"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to check in (leave empty for cluster-wide)"),
+		),
+		mcp.WithString("user",
+			mcp.Description("User to check instead of the server's identity"),
+		),
+		mcp.WithArray("groups",
+			mcp.Description("Groups of the user to check, e.g. ['system:authenticated']"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+	)
+}
+
+// Handler submits the access review.
+func (c *CanITool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateAccessCheckParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate can_i params: %w", err)
+	}
+
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	attributes := &authorizationv1.ResourceAttributes{
+		Namespace:   input.Namespace,
+		Verb:        input.Verb,
+		Group:       input.Group,
+		Resource:    input.Resource,
+		Subresource: input.Subresource,
+		Name:        input.ResourceName,
+	}
+
+	var status authorizationv1.SubjectAccessReviewStatus
+	subject := "self"
+	if input.User == "" && len(input.Groups) == 0 {
+		review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attributes},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create selfsubjectaccessreview: %w", err)
+		}
+		status = review.Status
+	} else {
+		review, err := clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{ResourceAttributes: attributes, User: input.User, Groups: input.Groups},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create subjectaccessreview: %w", err)
+		}
+		status = review.Status
+		subject = input.User
+		if subject == "" {
+			subject = "groups " + strings.Join(input.Groups, ",")
+		}
+	}
+
+	result := map[string]any{
+		"subject": subject,
+		"action":  describeAccessCheck(input),
+		"allowed": status.Allowed,
+		"denied":  status.Denied,
+	}
+	if status.Reason != "" {
+		result["reason"] = status.Reason
+	}
+	if status.EvaluationError != "" {
+		result["evaluationError"] = status.EvaluationError
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// WhoCanTool lists the subjects whose RBAC bindings allow an action.
+type WhoCanTool struct {
+	client Client
+}
+
+// NewWhoCanTool creates a new WhoCanTool with the provided Kubernetes client.
+func NewWhoCanTool(client Client) *WhoCanTool {
+	return &WhoCanTool{client: client}
+}
+
+// Tool returns the MCP tool definition for who_can.
+func (w *WhoCanTool) Tool() mcp.Tool {
+	return mcp.NewTool("who_can",
+		mcp.WithDescription("List the users, groups and service accounts that RBAC allows to perform a verb on a resource, by scanning ClusterRoleBindings and RoleBindings and the rules of the roles they reference"),
+		mcp.WithString("verb",
+			mcp.Required(),
+			mcp.Description("Verb to check, e.g. get, list, create, delete"),
+		),
+		mcp.WithString("resource",
+			mcp.Required(),
+			mcp.Description("Resource to check, e.g. secrets, deployments or pods/exec"),
+		),
+		mcp.WithString("group",
+			mcp.Description("API group of the resource, e.g. apps (empty for the core group)"),
+		),
+		mcp.WithString("resourceName",
+			mcp.Description("Name of a specific object to check"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to check; RoleBindings in it are included along with ClusterRoleBindings (leave empty for cluster-wide grants only)"),
+		),
+	)
+}
+
+// Handler scans the bindings for subjects granted the action.
+func (w *WhoCanTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateAccessCheckParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate who_can params: %w", err)
+	}
+
+	clientset, err := w.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	clusterRoles, err := clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusterroles: %w", err)
+	}
+	clusterBindings, err := clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusterrolebindings: %w", err)
+	}
+	var roles []rbacv1.Role
+	var bindings []rbacv1.RoleBinding
+	if input.Namespace != "" {
+		roleList, err := clientset.RbacV1().Roles(input.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list roles: %w", err)
+		}
+		bindingList, err := clientset.RbacV1().RoleBindings(input.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rolebindings: %w", err)
+		}
+		roles, bindings = roleList.Items, bindingList.Items
+	}
+
+	grants := whoCan(input, clusterRoles.Items, clusterBindings.Items, roles, bindings)
+	result := map[string]any{
+		"action": describeAccessCheck(input),
+		"grants": grants,
+		"total":  len(grants),
+		"note":   "only RBAC is evaluated; other authorizers (e.g. Node, webhooks) and cluster admins via system:masters are not listed",
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// whoCan returns every binding subject whose role allows the action. RoleBindings may reference
+// either a Role in their namespace or a ClusterRole, which is then scoped to that namespace.
+func whoCan(input *AccessCheckInput, clusterRoles []rbacv1.ClusterRole, clusterBindings []rbacv1.ClusterRoleBinding, roles []rbacv1.Role, bindings []rbacv1.RoleBinding) []AccessGrant {
+	clusterRoleRules := make(map[string][]rbacv1.PolicyRule, len(clusterRoles))
+	for _, r := range clusterRoles {
+		clusterRoleRules[r.Name] = r.Rules
+	}
+	roleRules := make(map[string][]rbacv1.PolicyRule, len(roles))
+	for _, r := range roles {
+		roleRules[r.Name] = r.Rules
+	}
+
+	grants := []AccessGrant{}
+	addSubjects := func(subjects []rbacv1.Subject, binding, role, namespace string) {
+		for _, s := range subjects {
+			name := s.Name
+			if s.Kind == rbacv1.ServiceAccountKind {
+				name = s.Namespace + "/" + s.Name
+			}
+			grants = append(grants, AccessGrant{Subject: name, Kind: s.Kind, Binding: binding, Role: role, Namespace: namespace})
+		}
+	}
+
+	for _, b := range clusterBindings {
+		if rulesAllow(clusterRoleRules[b.RoleRef.Name], input) {
+			addSubjects(b.Subjects, "ClusterRoleBinding/"+b.Name, "ClusterRole/"+b.RoleRef.Name, "")
+		}
+	}
+	for _, b := range bindings {
+		rules := roleRules[b.RoleRef.Name]
+		if b.RoleRef.Kind == "ClusterRole" {
+			rules = clusterRoleRules[b.RoleRef.Name]
+		}
+		if rulesAllow(rules, input) {
+			addSubjects(b.Subjects, "RoleBinding/"+b.Name, b.RoleRef.Kind+"/"+b.RoleRef.Name, b.Namespace)
+		}
+	}
+
+	sort.SliceStable(grants, func(i, j int) bool {
+		if grants[i].Kind != grants[j].Kind {
+			return grants[i].Kind < grants[j].Kind
+		}
+		return grants[i].Subject < grants[j].Subject
+	})
+	return grants
+}
+
+// rulesAllow reports whether any rule grants the action.
+func rulesAllow(rules []rbacv1.PolicyRule, input *AccessCheckInput) bool {
+	for i := range rules {
+		if policyRuleAllows(&rules[i], input) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyRuleAllows mirrors the RBAC authorizer's matching of verbs, API groups, resources
+// (including "*" and "*/subresource" wildcards) and resource names.
+func policyRuleAllows(rule *rbacv1.PolicyRule, input *AccessCheckInput) bool {
+	if !containsOrWildcard(rule.Verbs, input.Verb) || !containsOrWildcard(rule.APIGroups, input.Group) {
+		return false
+	}
+
+	resource := input.Resource
+	if input.Subresource != "" {
+		resource += "/" + input.Subresource
+	}
+	resourceMatched := false
+	for _, r := range rule.Resources {
+		if r == rbacv1.ResourceAll || r == resource || (input.Subresource != "" && r == "*/"+input.Subresource) {
+			resourceMatched = true
+			break
+		}
+	}
+	if !resourceMatched {
+		return false
+	}
+
+	if len(rule.ResourceNames) == 0 {
+		return true
+	}
+	return input.ResourceName != "" && containsString(rule.ResourceNames, input.ResourceName)
+}
+
+// containsOrWildcard reports whether values contains value or the "*" wildcard.
+func containsOrWildcard(values []string, value string) bool {
+	return containsString(values, "*") || containsString(values, value)
+}
+
+// describeAccessCheck renders the action like kubectl does, e.g. "get apps/deployments/web in default".
+func describeAccessCheck(input *AccessCheckInput) string {
+	resource := input.Resource
+	if input.Subresource != "" {
+		resource += "/" + input.Subresource
+	}
+	if input.Group != "" {
+		resource = input.Group + "/" + resource
+	}
+	if input.ResourceName != "" {
+		resource += " " + input.ResourceName
+	}
+	scope := "cluster-wide"
+	if input.Namespace != "" {
+		scope = "in namespace " + input.Namespace
+	}
+	return fmt.Sprintf("%s %s %s", input.Verb, resource, scope)
+}
+
+// parseAndValidateAccessCheckParams validates and parses the parameters shared by can_i and who_can.
+func parseAndValidateAccessCheckParams(args map[string]any) (*AccessCheckInput, error) {
+	input := &AccessCheckInput{}
+
+	input.Verb, _ = args["verb"].(string)
+	input.Resource, _ = args["resource"].(string)
+	if input.Verb == "" || input.Resource == "" {
+		return nil, errors.New("verb and resource must be provided")
+	}
+	input.Verb = strings.ToLower(input.Verb)
+	input.Resource, input.Subresource, _ = strings.Cut(strings.ToLower(input.Resource), "/")
+
+	input.Group, _ = args["group"].(string)
+	if input.Group == "core" {
+		input.Group = ""
+	}
+
+	if name, ok := args["resourceName"].(string); ok && name != "" {
+		if err := validation.ValidateResourceName(name); err != nil {
+			return nil, fmt.Errorf("invalid resource name: %w", err)
+		}
+		input.ResourceName = name
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = ns
+	}
+
+	input.User, _ = args["user"].(string)
+	groups, err := stringSliceArg(args, "groups")
+	if err != nil {
+		return nil, err
+	}
+	input.Groups = groups
+
+	return input, nil
+}
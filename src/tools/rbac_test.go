@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPolicyRuleAllows(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  rbacv1.PolicyRule
+		input AccessCheckInput
+		want  bool
+	}{
+		{"exact", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}, AccessCheckInput{Verb: "get", Resource: "pods"}, true},
+		{"wrong verb", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}, AccessCheckInput{Verb: "delete", Resource: "pods"}, false},
+		{"wrong group", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"deployments"}}, AccessCheckInput{Verb: "get", Group: "apps", Resource: "deployments"}, false},
+		{"wildcards", rbacv1.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}, AccessCheckInput{Verb: "delete", Group: "apps", Resource: "deployments"}, true},
+		{"subresource not covered by resource", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}, AccessCheckInput{Verb: "get", Resource: "pods", Subresource: "log"}, false},
+		{"subresource wildcard", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"*/log"}}, AccessCheckInput{Verb: "get", Resource: "pods", Subresource: "log"}, true},
+		{"resource name", rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"db"}}, AccessCheckInput{Verb: "get", Resource: "secrets", ResourceName: "db"}, true},
+		{"resource name required", rbacv1.PolicyRule{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"db"}}, AccessCheckInput{Verb: "list", Resource: "secrets"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policyRuleAllows(&tt.rule, &tt.input))
+		})
+	}
+}
+
+func TestWhoCan(t *testing.T) {
+	readSecrets := []rbacv1.PolicyRule{{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"secrets"}}}
+	clusterRoles := []rbacv1.ClusterRole{
+		{ObjectMeta: metav1.ObjectMeta{Name: "admin"}, Rules: []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "view"}, Rules: []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}}},
+	}
+	clusterBindings := []rbacv1.ClusterRoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ops"}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "admin"}, Subjects: []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "ops"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "viewers"}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"}, Subjects: []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice"}}},
+	}
+	roles := []rbacv1.Role{{ObjectMeta: metav1.ObjectMeta{Name: "secret-reader", Namespace: "app"}, Rules: readSecrets}}
+	bindings := []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "app"},
+		RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "secret-reader"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "deployer", Namespace: "ci"}},
+	}}
+
+	grants := whoCan(&AccessCheckInput{Verb: "get", Resource: "secrets", Namespace: "app"}, clusterRoles, clusterBindings, roles, bindings)
+
+	assert.Equal(t, []AccessGrant{
+		{Subject: "ops", Kind: rbacv1.GroupKind, Binding: "ClusterRoleBinding/ops", Role: "ClusterRole/admin"},
+		{Subject: "ci/deployer", Kind: rbacv1.ServiceAccountKind, Binding: "RoleBinding/ci", Role: "Role/secret-reader", Namespace: "app"},
+	}, grants)
+}
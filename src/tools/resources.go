@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/k4mrul/kubernetes-mcp/src/redact"
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Cluster objects are addressed as k8s://<namespace>/<kind>/<name>; k8s://namespaces lists the namespaces.
+const (
+	namespacesResourceURI   = "k8s://namespaces"
+	namespaceResourcesURI   = "k8s://{namespace}/{kind}"
+	namespacedObjectURI     = "k8s://{namespace}/{kind}/{name}"
+	eventsResourceKind      = "events"
+	maxResourceEvents       = 50
+	resourceContentMIMEType = "application/json"
+)
+
+// resourceKinds are the workload kinds exposed as MCP resources, by plural name. Secrets and
+// ConfigMaps are left out so attaching context to a prompt never pulls configuration values.
+var resourceKinds = map[string]schema.GroupVersionResource{
+	"deployments":  {Group: "apps", Version: "v1", Resource: "deployments"},
+	"statefulsets": {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"daemonsets":   {Group: "apps", Version: "v1", Resource: "daemonsets"},
+	"jobs":         {Group: "batch", Version: "v1", Resource: "jobs"},
+	"cronjobs":     {Group: "batch", Version: "v1", Resource: "cronjobs"},
+	"pods":         {Version: "v1", Resource: "pods"},
+}
+
+// resourceStatusFields are the status fields included for each object when a kind is listed.
+var resourceStatusFields = map[string][]string{
+	"deployments":  {"replicas", "readyReplicas", "updatedReplicas", "availableReplicas"},
+	"statefulsets": {"replicas", "readyReplicas", "updatedReplicas"},
+	"daemonsets":   {"desiredNumberScheduled", "numberReady", "numberUnavailable"},
+	"jobs":         {"active", "succeeded", "failed", "completionTime"},
+	"cronjobs":     {"lastScheduleTime", "lastSuccessfulTime"},
+	"pods":         {"phase", "reason"},
+}
+
+// ResourceSummary is an entry of a listed kind.
+type ResourceSummary struct {
+	Name      string         `json:"name"`
+	URI       string         `json:"uri"`
+	CreatedAt string         `json:"createdAt,omitempty"`
+	Status    map[string]any `json:"status,omitempty"`
+}
+
+// RegisterResources exposes namespaces, workloads and recent events as MCP resources so clients can
+// attach live cluster objects to prompts without tool calls. Reads honor the namespace scope of
+// authenticated HTTP clients and are scrubbed according to the redaction policy.
+func RegisterResources(s *server.MCPServer, client Client, policy redact.Policy) {
+	r := &clusterResources{client: client, policy: policy}
+	s.AddResource(mcp.NewResource(namespacesResourceURI, "namespaces",
+		mcp.WithResourceDescription("Namespaces of the cluster with their phase"),
+		mcp.WithMIMEType(resourceContentMIMEType),
+	), r.readNamespaces)
+	s.AddResourceTemplate(mcp.NewResourceTemplate(namespaceResourcesURI, "namespace objects",
+		mcp.WithTemplateDescription(fmt.Sprintf("Objects of one kind in a namespace with their status; kind is one of %s, or events for the %d most recent events", strings.Join(sortedResourceKinds(), ", "), maxResourceEvents)),
+		mcp.WithTemplateMIMEType(resourceContentMIMEType),
+	), r.readNamespace)
+	s.AddResourceTemplate(mcp.NewResourceTemplate(namespacedObjectURI, "object",
+		mcp.WithTemplateDescription(fmt.Sprintf("One object as JSON without managed fields, e.g. k8s://default/deployments/web; kind is one of %s", strings.Join(sortedResourceKinds(), ", "))),
+		mcp.WithTemplateMIMEType(resourceContentMIMEType),
+	), r.readObject)
+}
+
+// clusterResources reads the resources registered by RegisterResources.
+type clusterResources struct {
+	client Client
+	policy redact.Policy
+}
+
+// readNamespaces lists the namespaces the caller may access.
+func (r *clusterResources) readNamespaces(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	clientset, err := r.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	caller := auth.ClientFromContext(ctx)
+	summaries := make([]ResourceSummary, 0, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		if caller != nil && !caller.AllowsNamespace(ns.Name) {
+			continue
+		}
+		summaries = append(summaries, ResourceSummary{
+			Name:      ns.Name,
+			URI:       "k8s://" + ns.Name + "/deployments",
+			CreatedAt: ns.CreationTimestamp.UTC().Format("2006-01-02T15:04:05Z"),
+			Status:    map[string]any{"phase": string(ns.Status.Phase)},
+		})
+	}
+	return r.contents(req.Params.URI, map[string]any{
+		"namespaces": summaries,
+		"kinds":      append(sortedResourceKinds(), eventsResourceKind),
+	})
+}
+
+// readNamespace lists the objects of a kind, or the recent events, in a namespace.
+func (r *clusterResources) readNamespace(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	namespace, kind, _, err := parseResourceURIArguments(ctx, req.Params.Arguments, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == eventsResourceKind {
+		clientset, err := r.client.Clientset()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create clientset: %w", err)
+		}
+		events, err := recentEvents(ctx, clientset, namespace)
+		if err != nil {
+			return nil, err
+		}
+		return r.contents(req.Params.URI, map[string]any{"namespace": namespace, "events": events})
+	}
+
+	ri, err := r.client.ResourceInterface(resourceKinds[kind], true, namespace)
+	if err != nil {
+		return nil, err
+	}
+	list, err := ri.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", kind, err)
+	}
+	return r.contents(req.Params.URI, map[string]any{
+		"namespace": namespace,
+		"kind":      kind,
+		"items":     summarizeResources(namespace, kind, list.Items),
+	})
+}
+
+// readObject returns one object without its managed fields and last-applied configuration.
+func (r *clusterResources) readObject(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	namespace, kind, name, err := parseResourceURIArguments(ctx, req.Params.Arguments, true)
+	if err != nil {
+		return nil, err
+	}
+	if kind == eventsResourceKind {
+		return nil, fmt.Errorf("events are read as a list: use k8s://%s/events", namespace)
+	}
+
+	ri, err := r.client.ResourceInterface(resourceKinds[kind], true, namespace)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := ri.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", kind, name, err)
+	}
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	return r.contents(req.Params.URI, obj.Object)
+}
+
+// contents marshals a resource body, scrubbing it unless redaction is disabled.
+func (r *clusterResources) contents(uri string, body any) ([]mcp.ResourceContents, error) {
+	out, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	text := string(out)
+	if r.policy != redact.Never {
+		text = redact.Text(text)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: resourceContentMIMEType, Text: text}}, nil
+}
+
+// parseResourceURIArguments validates the variables matched from a resource URI and checks the
+// namespace against the scope of an authenticated client.
+func parseResourceURIArguments(ctx context.Context, args map[string]any, withName bool) (string, string, string, error) {
+	namespace, _ := args["namespace"].(string)
+	kind, _ := args["kind"].(string)
+	name, _ := args["name"].(string)
+
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return "", "", "", fmt.Errorf("invalid namespace: %w", err)
+	}
+	kind = strings.ToLower(kind)
+	if _, ok := resourceKinds[kind]; !ok && kind != eventsResourceKind {
+		return "", "", "", fmt.Errorf("unsupported kind %q: expected one of %s or %s", kind, strings.Join(sortedResourceKinds(), ", "), eventsResourceKind)
+	}
+	if withName {
+		if err := validation.ValidateResourceName(name); err != nil {
+			return "", "", "", fmt.Errorf("invalid name: %w", err)
+		}
+	}
+	if caller := auth.ClientFromContext(ctx); caller != nil && !caller.AllowsNamespace(namespace) {
+		return "", "", "", fmt.Errorf("client %s is not allowed to access namespace %s", caller.Name, namespace)
+	}
+	return namespace, kind, name, nil
+}
+
+// summarizeResources returns the name, URI, age and key status fields of each object, sorted by name.
+func summarizeResources(namespace, kind string, items []unstructured.Unstructured) []ResourceSummary {
+	summaries := make([]ResourceSummary, 0, len(items))
+	for _, item := range items {
+		summary := ResourceSummary{
+			Name: item.GetName(),
+			URI:  fmt.Sprintf("k8s://%s/%s/%s", namespace, kind, item.GetName()),
+		}
+		if created := item.GetCreationTimestamp(); !created.IsZero() {
+			summary.CreatedAt = created.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		for _, field := range resourceStatusFields[kind] {
+			if v, ok, _ := unstructured.NestedFieldNoCopy(item.Object, "status", field); ok {
+				if summary.Status == nil {
+					summary.Status = map[string]any{}
+				}
+				summary.Status[field] = v
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries
+}
+
+// recentEvents returns the most recent events of a namespace, newest first.
+func recentEvents(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]EventSummary, error) {
+	events, err := listEvents(ctx, clientset, namespace, "", "", false)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > maxResourceEvents {
+		events = events[:maxResourceEvents]
+	}
+	return events, nil
+}
+
+// sortedResourceKinds returns the exposed workload kinds in alphabetical order.
+func sortedResourceKinds() []string {
+	kinds := make([]string, 0, len(resourceKinds))
+	for kind := range resourceKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
@@ -0,0 +1,261 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Thresholds used by the resources audit. Usage is a single metrics-server sample, so the
+// ratios are deliberately generous before a container is flagged.
+const (
+	overLimitRatio      = 4.0 // a limit this many times above usage is flagged
+	overRequestRatio    = 2.0 // a request this many times above usage is flagged
+	requestHeadroom     = 1.2 // suggested request = usage * requestHeadroom
+	memoryLimitHeadroom = 1.5 // suggested memory limit = usage * memoryLimitHeadroom
+	minCPURequest       = 10  // millicores
+	minMemoryRequest    = 16 << 20
+)
+
+// ContainerResourceAudit reports the requests, limits and usage of one workload container.
+type ContainerResourceAudit struct {
+	Workload  string            `json:"workload"`
+	Container string            `json:"container"`
+	Requests  map[string]string `json:"requests,omitempty"`
+	Limits    map[string]string `json:"limits,omitempty"`
+	Usage     map[string]string `json:"maxUsage,omitempty"`
+	Pods      int               `json:"podsSampled,omitempty"`
+	Issues    []string          `json:"issues"`
+	Suggested map[string]string `json:"suggested,omitempty"`
+}
+
+// ResourcesAuditTool flags containers with missing or badly sized requests and limits.
+type ResourcesAuditTool struct {
+	client Client
+}
+
+// NewResourcesAuditTool creates a new ResourcesAuditTool with the provided Kubernetes client.
+func NewResourcesAuditTool(client Client) *ResourcesAuditTool {
+	return &ResourcesAuditTool{client: client}
+}
+
+// Tool returns the MCP tool definition for resources_audit.
+func (r *ResourcesAuditTool) Tool() mcp.Tool {
+	return mcp.NewTool("resources_audit",
+		mcp.WithDescription("Audit CPU/memory requests and limits of the workloads in a namespace: containers without requests or limits, limits or requests far above live usage from the metrics API, and usage above requests, with suggested right-sized values"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to audit (defaults to 'default' if not specified)"),
+		),
+		mcp.WithBoolean("onlyIssues",
+			mcp.Description("Only return containers with at least one issue (default: true)"),
+		),
+	)
+}
+
+// Handler audits every container of every workload in the namespace.
+func (r *ResourcesAuditTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, _ := args["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	onlyIssues := true
+	if v, ok := args["onlyIssues"].(bool); ok {
+		onlyIssues = v
+	}
+
+	clientset, err := r.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	workloads, err := listWorkloadTemplates(ctx, clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{"namespace": namespace}
+	usage, podCounts, err := r.workloadContainerUsage(ctx, namespace)
+	if err != nil {
+		result["metricsWarning"] = err.Error()
+	}
+
+	audits := []ContainerResourceAudit{}
+	for _, w := range workloads {
+		ref := w.Kind + "/" + w.Name
+		for i := range w.Template.Spec.Containers {
+			c := &w.Template.Spec.Containers[i]
+			used, sampled := usage[ref+"/"+c.Name]
+			audit := auditContainerResources(c, used, sampled)
+			audit.Workload = ref
+			if sampled {
+				audit.Pods = podCounts[ref]
+			}
+			if onlyIssues && len(audit.Issues) == 0 {
+				continue
+			}
+			audits = append(audits, audit)
+		}
+	}
+
+	result["containers"] = audits
+	result["note"] = "usage is a single metrics-server sample (max across the workload's pods); validate suggestions against longer-term monitoring"
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// workloadContainerUsage returns the maximum usage of each "Kind/name/container" across the workload's
+// pods, and the number of pods sampled per workload.
+func (r *ResourcesAuditTool) workloadContainerUsage(ctx context.Context, namespace string) (map[string]resourceTotals, map[string]int, error) {
+	ri, err := r.client.ResourceInterface(metricsGVR("pods"), true, namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pod metrics unavailable: %w", err)
+	}
+	metricsList, err := ri.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("pod metrics unavailable (is metrics-server installed?): %w", err)
+	}
+
+	clientset, err := r.client.Clientset()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	replicaSets := replicaSetsByKey(ctx, clientset, namespace)
+	owners := make(map[string]string, len(pods.Items))
+	for i := range pods.Items {
+		kind, name := workloadOwner(&pods.Items[i], replicaSets)
+		owners[pods.Items[i].Name] = kind + "/" + name
+	}
+
+	usage := map[string]resourceTotals{}
+	podCounts := map[string]int{}
+	for i := range metricsList.Items {
+		item := &metricsList.Items[i]
+		owner, ok := owners[item.GetName()]
+		if !ok {
+			continue
+		}
+		podCounts[owner]++
+		for container, used := range containerMetricsUsage(item) {
+			key := owner + "/" + container
+			current := usage[key]
+			usage[key] = resourceTotals{CPU: max(current.CPU, used.CPU), Memory: max(current.Memory, used.Memory)}
+		}
+	}
+	return usage, podCounts, nil
+}
+
+// containerMetricsUsage returns the usage of each container in a PodMetrics object.
+func containerMetricsUsage(item *unstructured.Unstructured) map[string]resourceTotals {
+	result := map[string]resourceTotals{}
+	containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+	for _, c := range containers {
+		cMap, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(cMap, "name")
+		usage, _, _ := unstructured.NestedStringMap(cMap, "usage")
+		result[name] = usageTotals(usage)
+	}
+	return result
+}
+
+// auditContainerResources compares a container's requests and limits with its observed usage.
+// When sampled is false only the presence of requests and limits is checked.
+func auditContainerResources(c *corev1.Container, used resourceTotals, sampled bool) ContainerResourceAudit {
+	requests := resourceListTotals(c.Resources.Requests)
+	limits := resourceListTotals(c.Resources.Limits)
+	audit := ContainerResourceAudit{
+		Container: c.Name,
+		Requests:  totalsToStrings(requests),
+		Limits:    totalsToStrings(limits),
+		Issues:    []string{},
+	}
+
+	if requests.CPU == 0 {
+		audit.Issues = append(audit.Issues, "no CPU request; the pod is BestEffort/Burstable and scheduled without CPU guarantees")
+	}
+	if requests.Memory == 0 {
+		audit.Issues = append(audit.Issues, "no memory request")
+	}
+	if limits.Memory == 0 {
+		audit.Issues = append(audit.Issues, "no memory limit; a leak can exhaust node memory")
+	}
+	if !sampled {
+		return audit
+	}
+
+	audit.Usage = totalsToStrings(used)
+	if limits.CPU > 0 && float64(limits.CPU) > overLimitRatio*float64(max(used.CPU, 1)) {
+		audit.Issues = append(audit.Issues, fmt.Sprintf("CPU limit %s is more than %.0fx usage %s", formatCPU(limits.CPU), overLimitRatio, formatCPU(used.CPU)))
+	}
+	if limits.Memory > 0 && float64(limits.Memory) > overLimitRatio*float64(max(used.Memory, 1)) {
+		audit.Issues = append(audit.Issues, fmt.Sprintf("memory limit %s is more than %.0fx usage %s", formatMemory(limits.Memory), overLimitRatio, formatMemory(used.Memory)))
+	}
+	if requests.CPU > 0 && float64(requests.CPU) > overRequestRatio*float64(max(used.CPU, 1)) && requests.CPU > minCPURequest {
+		audit.Issues = append(audit.Issues, fmt.Sprintf("CPU request %s is more than %.0fx usage %s (over-provisioned)", formatCPU(requests.CPU), overRequestRatio, formatCPU(used.CPU)))
+	}
+	if requests.Memory > 0 && float64(requests.Memory) > overRequestRatio*float64(max(used.Memory, 1)) && requests.Memory > minMemoryRequest {
+		audit.Issues = append(audit.Issues, fmt.Sprintf("memory request %s is more than %.0fx usage %s (over-provisioned)", formatMemory(requests.Memory), overRequestRatio, formatMemory(used.Memory)))
+	}
+	if requests.CPU > 0 && used.CPU > requests.CPU {
+		audit.Issues = append(audit.Issues, fmt.Sprintf("CPU usage %s exceeds request %s", formatCPU(used.CPU), formatCPU(requests.CPU)))
+	}
+	if requests.Memory > 0 && used.Memory > requests.Memory {
+		audit.Issues = append(audit.Issues, fmt.Sprintf("memory usage %s exceeds request %s; the pod is an early eviction candidate", formatMemory(used.Memory), formatMemory(requests.Memory)))
+	}
+
+	if len(audit.Issues) > 0 {
+		audit.Suggested = suggestResources(used, limits)
+	}
+	return audit
+}
+
+// suggestResources derives requests and a memory limit from observed usage. A CPU limit is only
+// suggested when one is already set, since many clusters deliberately run without CPU limits.
+func suggestResources(used, currentLimits resourceTotals) map[string]string {
+	cpuRequest := max(int64(float64(used.CPU)*requestHeadroom), minCPURequest)
+	memoryRequest := max(int64(float64(used.Memory)*requestHeadroom), minMemoryRequest)
+	suggested := map[string]string{
+		"requests.cpu":    formatCPU(cpuRequest),
+		"requests.memory": formatMemory(memoryRequest),
+		"limits.memory":   formatMemory(max(int64(float64(used.Memory)*memoryLimitHeadroom), memoryRequest)),
+	}
+	if currentLimits.CPU > 0 {
+		suggested["limits.cpu"] = formatCPU(max(cpuRequest*2, minCPURequest))
+	}
+	return suggested
+}
+
+// totalsToStrings renders non-zero CPU and memory totals, or nil when both are unset.
+func totalsToStrings(t resourceTotals) map[string]string {
+	if t.CPU == 0 && t.Memory == 0 {
+		return nil
+	}
+	out := map[string]string{}
+	if t.CPU > 0 {
+		out["cpu"] = formatCPU(t.CPU)
+	}
+	if t.Memory > 0 {
+		out["memory"] = formatMemory(t.Memory)
+	}
+	return out
+}
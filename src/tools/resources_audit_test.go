@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestAuditContainerResources(t *testing.T) {
+	container := &corev1.Container{
+		Name: "app",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+		},
+	}
+
+	audit := auditContainerResources(container, resourceTotals{CPU: 100, Memory: 300 << 20}, true)
+
+	assert.Equal(t, []string{
+		"CPU limit 2000m is more than 4x usage 100m",
+		"memory limit 2048Mi is more than 4x usage 300Mi",
+		"CPU request 1000m is more than 2x usage 100m (over-provisioned)",
+		"memory usage 300Mi exceeds request 256Mi; the pod is an early eviction candidate",
+	}, audit.Issues)
+	assert.Equal(t, map[string]string{
+		"requests.cpu":    "120m",
+		"requests.memory": "360Mi",
+		"limits.memory":   "450Mi",
+		"limits.cpu":      "240m",
+	}, audit.Suggested)
+
+	bare := auditContainerResources(&corev1.Container{Name: "bare"}, resourceTotals{}, false)
+	assert.Len(t, bare.Issues, 3)
+	assert.Nil(t, bare.Suggested)
+}
+
+func TestContainerMetricsUsage(t *testing.T) {
+	item := &unstructured.Unstructured{Object: map[string]any{
+		"containers": []any{
+			map[string]any{"name": "app", "usage": map[string]any{"cpu": "250m", "memory": "128Mi"}},
+			map[string]any{"name": "sidecar", "usage": map[string]any{"cpu": "5m", "memory": "16Mi"}},
+		},
+	}}
+
+	assert.Equal(t, map[string]resourceTotals{
+		"app":     {CPU: 250, Memory: 128 << 20},
+		"sidecar": {CPU: 5, Memory: 16 << 20},
+	}, containerMetricsUsage(item))
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/k4mrul/kubernetes-mcp/src/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func readResource(t *testing.T, read func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error), ctx context.Context, uri string, args map[string]any) (string, error) {
+	t.Helper()
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = uri
+	req.Params.Arguments = args
+	contents, err := read(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	text := contents[0].(mcp.TextResourceContents)
+	assert.Equal(t, uri, text.URI)
+	assert.Equal(t, "application/json", text.MIMEType)
+	return text.Text, nil
+}
+
+func TestClusterResourcesReadNamespace(t *testing.T) {
+	r := &clusterResources{client: FakeKubernetesClient{}, policy: redact.OnRequest}
+
+	out, err := readResource(t, r.readNamespace, context.Background(), "k8s://default/deployments", map[string]any{"namespace": "default", "kind": "Deployments"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"namespace":"default","kind":"deployments","items":[{"name":"foo-deployment","uri":"k8s://default/deployments/foo-deployment"}]}`, out)
+
+	_, err = readResource(t, r.readNamespace, context.Background(), "k8s://default/secrets", map[string]any{"namespace": "default", "kind": "secrets"})
+	assert.ErrorContains(t, err, `unsupported kind "secrets"`)
+}
+
+func TestClusterResourcesReadObject(t *testing.T) {
+	r := &clusterResources{client: FakeKubernetesClient{}, policy: redact.OnRequest}
+
+	out, err := readResource(t, r.readObject, context.Background(), "k8s://default/deployments/foo-deployment", map[string]any{"namespace": "default", "kind": "deployments", "name": "foo-deployment"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"name":"foo-deployment"`)
+
+	_, err = readResource(t, r.readObject, context.Background(), "k8s://default/events/x", map[string]any{"namespace": "default", "kind": "events", "name": "x"})
+	assert.ErrorContains(t, err, "use k8s://default/events")
+}
+
+func TestParseResourceURIArguments(t *testing.T) {
+	ctx := auth.WithClient(context.Background(), &auth.Client{Name: "team", Namespaces: []string{"payments-*"}})
+
+	namespace, kind, name, err := parseResourceURIArguments(ctx, map[string]any{"namespace": "payments-api", "kind": "Pods", "name": "web-1"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"payments-api", "pods", "web-1"}, []string{namespace, kind, name})
+
+	_, _, _, err = parseResourceURIArguments(ctx, map[string]any{"namespace": "kube-system", "kind": "pods"}, false)
+	assert.ErrorContains(t, err, "not allowed to access namespace kube-system")
+
+	_, _, _, err = parseResourceURIArguments(context.Background(), map[string]any{"namespace": "default", "kind": "pods", "name": "Bad_Name"}, true)
+	assert.ErrorContains(t, err, "invalid name")
+}
+
+func TestSummarizeResources(t *testing.T) {
+	items := []unstructured.Unstructured{
+		{Object: map[string]any{
+			"metadata": map[string]any{"name": "web", "creationTimestamp": "2026-01-02T03:04:05Z"},
+			"status":   map[string]any{"replicas": int64(3), "readyReplicas": int64(2), "conditions": []any{}},
+		}},
+		{Object: map[string]any{"metadata": map[string]any{"name": "api"}}},
+	}
+	assert.Equal(t, []ResourceSummary{
+		{Name: "api", URI: "k8s://prod/deployments/api"},
+		{Name: "web", URI: "k8s://prod/deployments/web", CreatedAt: "2026-01-02T03:04:05Z", Status: map[string]any{"replicas": int64(3), "readyReplicas": int64(2)}},
+	}, summarizeResources("prod", "deployments", items))
+}
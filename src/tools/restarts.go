@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestartReportInput represents the input for the restart report.
+type RestartReportInput struct {
+	Namespace string        `json:"namespace,omitempty"`
+	Window    time.Duration `json:"window"`
+	Limit     int           `json:"limit,omitempty"`
+}
+
+// PodRestarts summarizes the restarts of one pod.
+type PodRestarts struct {
+	Name          string `json:"name"`
+	Restarts      int32  `json:"restarts"`
+	OOMKills      int    `json:"oomKills,omitempty"`
+	LastReason    string `json:"lastReason,omitempty"`
+	LastExitCode  int32  `json:"lastExitCode,omitempty"`
+	LastRestartAt string `json:"lastRestartAt,omitempty"`
+	BackOffEvents int32  `json:"backOffEvents,omitempty"`
+	lastRestart   time.Time
+}
+
+// WorkloadRestarts aggregates pod restarts by owning workload.
+type WorkloadRestarts struct {
+	Kind          string        `json:"kind"`
+	Name          string        `json:"name"`
+	Namespace     string        `json:"namespace"`
+	Restarts      int32         `json:"restarts"`
+	OOMKills      int           `json:"oomKills"`
+	BackOffEvents int32         `json:"backOffEvents"`
+	LastRestartAt string        `json:"lastRestartAt,omitempty"`
+	Pods          []PodRestarts `json:"pods"`
+	lastRestart   time.Time
+}
+
+// RestartReportTool reports the pods and workloads that restart most often.
+type RestartReportTool struct {
+	client Client
+}
+
+// NewRestartReportTool creates a new RestartReportTool with the provided Kubernetes client.
+func NewRestartReportTool(client Client) *RestartReportTool {
+	return &RestartReportTool{client: client}
+}
+
+// Tool returns the MCP tool definition for restart_report.
+func (r *RestartReportTool) Tool() mcp.Tool {
+	return mcp.NewTool("restart_report",
+		mcp.WithDescription("Find chronic restarters: pods with the highest restart counts and OOMKilled terminations whose last restart falls inside a time window, correlated with BackOff events and grouped by owning workload (Deployment, StatefulSet, DaemonSet, Job)"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to report on (leave empty for all namespaces)"),
+		),
+		mcp.WithString("window",
+			mcp.Description("Only include pods that restarted within this duration, e.g. 1h or 72h (default: 24h)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of workloads to return (default: 20)"),
+		),
+	)
+}
+
+// Handler builds the restart report.
+func (r *RestartReportTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateRestartReportParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate restart_report params: %w", err)
+	}
+
+	clientset, err := r.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	since := time.Now().Add(-input.Window)
+	result := map[string]any{
+		"namespace": input.Namespace,
+		"window":    input.Window.String(),
+	}
+
+	backOffs := map[string]int32{}
+	events, err := listEvents(ctx, clientset, input.Namespace, "Pod", "", true)
+	if err != nil {
+		result["eventsError"] = err.Error()
+	}
+	for _, ev := range events {
+		if ev.Reason != "BackOff" {
+			continue
+		}
+		if seen, err := time.Parse(time.RFC3339, ev.LastSeen); err != nil || seen.Before(since) {
+			continue
+		}
+		backOffs[ev.Namespace+"/"+strings.TrimPrefix(ev.Object, "Pod/")] += max(ev.Count, 1)
+	}
+
+	replicaSets := replicaSetsByKey(ctx, clientset, input.Namespace)
+
+	workloads := map[string]*WorkloadRestarts{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		entry, ok := podRestartSummary(pod, since)
+		if !ok {
+			continue
+		}
+		entry.BackOffEvents = backOffs[pod.Namespace+"/"+pod.Name]
+
+		kind, name := workloadOwner(pod, replicaSets)
+		key := pod.Namespace + "/" + kind + "/" + name
+		w, ok := workloads[key]
+		if !ok {
+			w = &WorkloadRestarts{Kind: kind, Name: name, Namespace: pod.Namespace}
+			workloads[key] = w
+		}
+		w.Restarts += entry.Restarts
+		w.OOMKills += entry.OOMKills
+		w.BackOffEvents += entry.BackOffEvents
+		if entry.lastRestart.After(w.lastRestart) {
+			w.lastRestart = entry.lastRestart
+			w.LastRestartAt = entry.LastRestartAt
+		}
+		w.Pods = append(w.Pods, entry)
+	}
+
+	ranked := make([]WorkloadRestarts, 0, len(workloads))
+	for _, w := range workloads {
+		sort.SliceStable(w.Pods, func(i, j int) bool { return w.Pods[i].Restarts > w.Pods[j].Restarts })
+		ranked = append(ranked, *w)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Restarts != ranked[j].Restarts {
+			return ranked[i].Restarts > ranked[j].Restarts
+		}
+		if ranked[i].OOMKills != ranked[j].OOMKills {
+			return ranked[i].OOMKills > ranked[j].OOMKills
+		}
+		return ranked[i].Namespace+"/"+ranked[i].Name < ranked[j].Namespace+"/"+ranked[j].Name
+	})
+	total := len(ranked)
+	if len(ranked) > input.Limit {
+		ranked = ranked[:input.Limit]
+	}
+
+	result["workloads"] = ranked
+	result["totalWorkloads"] = total
+	result["truncated"] = total > len(ranked)
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// podRestartSummary sums a pod's container restarts and reports whether its last restart was after since.
+// Restart counts are cumulative for the pod's lifetime; the window only decides which pods are included.
+func podRestartSummary(pod *corev1.Pod, since time.Time) (PodRestarts, bool) {
+	entry := PodRestarts{Name: pod.Name}
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		entry.Restarts += cs.RestartCount
+		last := cs.LastTerminationState.Terminated
+		if last == nil || last.FinishedAt.Time.Before(since) {
+			continue
+		}
+		if last.Reason == "OOMKilled" {
+			entry.OOMKills++
+		}
+		if last.FinishedAt.Time.After(entry.lastRestart) {
+			entry.lastRestart = last.FinishedAt.Time
+			entry.LastRestartAt = last.FinishedAt.Format(time.RFC3339)
+			entry.LastReason = last.Reason
+			entry.LastExitCode = last.ExitCode
+		}
+	}
+	return entry, entry.Restarts > 0 && !entry.lastRestart.IsZero()
+}
+
+// workloadOwner returns the kind and name of the workload that manages the pod, following
+// ReplicaSets up to their Deployment. Pods without a controller are reported as themselves.
+func workloadOwner(pod *corev1.Pod, replicaSets map[string]*appsv1.ReplicaSet) (string, string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod", pod.Name
+	}
+	if owner.Kind != "ReplicaSet" {
+		return owner.Kind, owner.Name
+	}
+	if rs, ok := replicaSets[pod.Namespace+"/"+owner.Name]; ok {
+		if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil {
+			return rsOwner.Kind, rsOwner.Name
+		}
+		return "ReplicaSet", owner.Name
+	}
+	// fall back to the Deployment naming convention <deployment>-<pod-template-hash>
+	if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+		return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+	return owner.Kind, owner.Name
+}
+
+// parseAndValidateRestartReportParams validates and parses the input parameters.
+func parseAndValidateRestartReportParams(args map[string]any) (*RestartReportInput, error) {
+	input := &RestartReportInput{Window: 24 * time.Hour, Limit: 20}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+
+	if window, ok := args["window"].(string); ok && window != "" {
+		d, err := time.ParseDuration(window)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid window duration: %s", window)
+		}
+		input.Window = d
+	}
+
+	if limit, ok := args["limit"].(float64); ok && limit > 0 {
+		input.Limit = int(limit)
+	}
+
+	return input, nil
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodRestartSummary(t *testing.T) {
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	terminated := func(reason string, ago time.Duration) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:     reason,
+			ExitCode:   137,
+			FinishedAt: metav1.NewTime(now.Add(-ago)),
+		}}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-0"},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "app", RestartCount: 7, LastTerminationState: terminated("OOMKilled", time.Hour)},
+			{Name: "sidecar", RestartCount: 2, LastTerminationState: terminated("Error", 72*time.Hour)},
+		}},
+	}
+
+	entry, ok := podRestartSummary(pod, now.Add(-24*time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, int32(9), entry.Restarts)
+	assert.Equal(t, 1, entry.OOMKills)
+	assert.Equal(t, "OOMKilled", entry.LastReason)
+	assert.Equal(t, "2025-01-01T23:00:00Z", entry.LastRestartAt)
+
+	_, ok = podRestartSummary(pod, now.Add(-time.Minute))
+	assert.False(t, ok)
+}
+
+func TestWorkloadOwner(t *testing.T) {
+	controller := true
+	ownedBy := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	replicaSets := map[string]*appsv1.ReplicaSet{
+		"default/web-5d8f7": {ObjectMeta: metav1.ObjectMeta{Name: "web-5d8f7", OwnerReferences: ownedBy("Deployment", "web")}},
+	}
+
+	testCases := []struct {
+		name         string
+		pod          *corev1.Pod
+		expectedKind string
+		expectedName string
+	}{
+		{
+			name:         "DeploymentViaReplicaSet",
+			pod:          &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-5d8f7-abcde", OwnerReferences: ownedBy("ReplicaSet", "web-5d8f7")}},
+			expectedKind: "Deployment",
+			expectedName: "web",
+		},
+		{
+			name: "DeploymentByNamingConvention",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "other",
+				Name:            "api-7c9b6-xyz12",
+				Labels:          map[string]string{"pod-template-hash": "7c9b6"},
+				OwnerReferences: ownedBy("ReplicaSet", "api-7c9b6"),
+			}},
+			expectedKind: "Deployment",
+			expectedName: "api",
+		},
+		{
+			name:         "StatefulSet",
+			pod:          &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", OwnerReferences: ownedBy("StatefulSet", "db")}},
+			expectedKind: "StatefulSet",
+			expectedName: "db",
+		},
+		{
+			name:         "BarePod",
+			pod:          &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "debug"}},
+			expectedKind: "Pod",
+			expectedName: "debug",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kind, name := workloadOwner(tc.pod, replicaSets)
+			assert.Equal(t, tc.expectedKind, kind)
+			assert.Equal(t, tc.expectedName, name)
+		})
+	}
+}
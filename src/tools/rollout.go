@@ -4,17 +4,47 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
 	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// restartedAtAnnotation is the pod template annotation 'kubectl rollout restart' sets.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// changeCauseAnnotation is copied by the workload controllers onto the new ReplicaSet or
+// ControllerRevision, where 'kubectl rollout history' shows it as CHANGE-CAUSE.
+const changeCauseAnnotation = "kubernetes.io/change-cause"
+
+// maxChangeCauseReason bounds the note a caller adds to the change cause.
+const maxChangeCauseReason = 200
+
 // RolloutRestartInput represents the input for restarting a deployment.
 type RolloutRestartInput struct {
-	Namespace  string `json:"namespace"`
-	Deployment string `json:"deployment"`
+	Namespace  string        `json:"namespace"`
+	Deployment string        `json:"deployment"`
+	Wait       bool          `json:"wait,omitempty"`
+	Timeout    time.Duration `json:"timeout,omitempty"`
+	DryRun     bool          `json:"dryRun,omitempty"`
+	Reason     string        `json:"reason,omitempty"`
+}
+
+// RolloutRestartPlan is the predicted effect of restarting a Deployment.
+type RolloutRestartPlan struct {
+	Strategy       string `json:"strategy"`
+	Replicas       int32  `json:"replicas"`
+	PodsToReplace  int32  `json:"podsToReplace"`
+	MaxSurge       int32  `json:"maxSurge"`
+	MaxUnavailable int32  `json:"maxUnavailable"`
+	MaxPods        int32  `json:"maxPods"`
+	MinAvailable   int32  `json:"minAvailable"`
+	RestartedAt    string `json:"restartedAt"`
 }
 
 // RolloutTool provides functionality to rollout/restart deployments.
@@ -38,6 +68,18 @@ func (r *RolloutTool) Tool() mcp.Tool {
 			mcp.Required(),
 			mcp.Description("Name of the deployment to restart"),
 		),
+		mcp.WithBoolean("wait",
+			mcp.Description("Wait for the rollout to complete or stall, sending MCP progress notifications with updated/available replica counts (default: false)"),
+		),
+		mcp.WithNumber("timeoutSeconds",
+			mcp.Description("How long to wait when wait is true (default: 300, max: 1800)"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Validate the restart with a server-side dry run and report how many pods would be replaced and how many stay available, without restarting (default: false)"),
+		),
+		mcp.WithString("reason",
+			mcp.Description("Why the deployment is restarted, e.g. a ticket; recorded with the MCP client and session in the kubernetes.io/change-cause annotation that 'kubectl rollout history' shows"),
+		),
 	)
 }
 
@@ -53,17 +95,42 @@ func (r *RolloutTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mc
 		return nil, fmt.Errorf("failed to get clientset: %w", err)
 	}
 
+	cause := changeCause(ctx, "rollout_restart", input.Reason)
 	deploymentsClient := clientset.AppsV1().Deployments(input.Namespace)
-	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`, time.Now().Format(time.RFC3339)))
-	_, err = deploymentsClient.Patch(ctx, input.Deployment, types.MergePatchType, patch, metav1.PatchOptions{})
+	patched, err := deploymentsClient.Patch(ctx, input.Deployment, types.MergePatchType, restartPatch(time.Now(), cause), metav1.PatchOptions{DryRun: serverDryRun(input.DryRun)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to patch deployment: %w", err)
 	}
 
-	result := map[string]string{
-		"status":     "Deployment restarted",
-		"deployment": input.Deployment,
-		"namespace":  input.Namespace,
+	if input.DryRun {
+		out, err := json.Marshal(map[string]any{
+			"status":     "Dry run: deployment not restarted",
+			"deployment": input.Deployment,
+			"namespace":  input.Namespace,
+			"dryRun":     true,
+			"plan":       planRolloutRestart(patched),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal result: %w", err)
+		}
+		return mcp.NewToolResultText(string(out)), nil
+	}
+
+	result := map[string]any{
+		"status":      "Deployment restarted",
+		"deployment":  input.Deployment,
+		"namespace":   input.Namespace,
+		"changeCause": cause,
+	}
+	if input.Wait {
+		progress, err := waitForDeploymentRollout(ctx, clientset, input.Namespace, input.Deployment, input.Timeout, newProgressNotifier(ctx, req))
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait for rollout: %w", err)
+		}
+		result["rollout"] = progress
+		if !progress.Done {
+			result["status"] = "Deployment restarted, rollout not complete"
+		}
 	}
 	out, err := json.Marshal(result)
 	if err != nil {
@@ -73,6 +140,105 @@ func (r *RolloutTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mc
 	return mcp.NewToolResultText(string(out)), nil
 }
 
+// restartPatch returns the merge patch 'kubectl rollout restart' applies to a pod template, also
+// recording the change cause on the workload when one is given.
+func restartPatch(now time.Time, cause string) []byte {
+	patch := map[string]any{
+		"spec": map[string]any{"template": map[string]any{"metadata": map[string]any{
+			"annotations": map[string]string{restartedAtAnnotation: now.Format(time.RFC3339)},
+		}}},
+	}
+	if cause != "" {
+		patch["metadata"] = map[string]any{"annotations": map[string]string{changeCauseAnnotation: cause}}
+	}
+	out, _ := json.Marshal(patch)
+	return out
+}
+
+// changeCause describes a change made through this server for the kubernetes.io/change-cause
+// annotation: the action, the authenticated client and MCP session when known, and the reason.
+func changeCause(ctx context.Context, action, reason string) string {
+	cause := "kubernetes-mcp " + action
+	var who []string
+	if client := auth.ClientFromContext(ctx); client != nil {
+		who = append(who, "client "+client.Name)
+	}
+	if id := sessionID(ctx); id != "" {
+		who = append(who, "session "+id)
+	}
+	if len(who) > 0 {
+		cause += " (" + strings.Join(who, ", ") + ")"
+	}
+	if reason != "" {
+		cause += ": " + reason
+	}
+	return cause
+}
+
+// serverDryRun returns the DryRun option for a write request, so the API server runs admission and
+// validation without persisting the change.
+func serverDryRun(dryRun bool) []string {
+	if dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+// restartedKey names the result field listing restarted workloads, or those a dry run would restart.
+func restartedKey(dryRun bool) string {
+	if dryRun {
+		return "wouldRestart"
+	}
+	return "restarted"
+}
+
+// planRolloutRestart predicts a restart of the Deployment from its strategy: a rolling update keeps
+// at least replicas-maxUnavailable pods available and runs at most replicas+maxSurge, while Recreate
+// stops every pod first. Surge rounds up and unavailability down, as the deployment controller does.
+func planRolloutRestart(dep *appsv1.Deployment) RolloutRestartPlan {
+	replicas := int32(1)
+	if dep.Spec.Replicas != nil {
+		replicas = *dep.Spec.Replicas
+	}
+	plan := RolloutRestartPlan{
+		Strategy:      string(dep.Spec.Strategy.Type),
+		Replicas:      replicas,
+		PodsToReplace: dep.Status.Replicas,
+		RestartedAt:   dep.Spec.Template.Annotations[restartedAtAnnotation],
+	}
+	if plan.Strategy == "" {
+		plan.Strategy = string(appsv1.RollingUpdateDeploymentStrategyType)
+	}
+	if plan.Strategy == string(appsv1.RecreateDeploymentStrategyType) {
+		plan.MaxUnavailable = replicas
+		plan.MaxPods = replicas
+		return plan
+	}
+
+	surge, unavailable := intstr.FromString("25%"), intstr.FromString("25%")
+	if ru := dep.Spec.Strategy.RollingUpdate; ru != nil {
+		if ru.MaxSurge != nil {
+			surge = *ru.MaxSurge
+		}
+		if ru.MaxUnavailable != nil {
+			unavailable = *ru.MaxUnavailable
+		}
+	}
+	maxSurge, _ := intstr.GetScaledValueFromIntOrPercent(&surge, int(replicas), true)
+	maxUnavailable, _ := intstr.GetScaledValueFromIntOrPercent(&unavailable, int(replicas), false)
+	if maxSurge == 0 && maxUnavailable == 0 {
+		maxUnavailable = 1
+	}
+	if maxUnavailable > int(replicas) {
+		maxUnavailable = int(replicas)
+	}
+	plan.MaxSurge = int32(maxSurge)
+	plan.MaxUnavailable = int32(maxUnavailable)
+	plan.MaxPods = replicas + plan.MaxSurge
+	plan.MinAvailable = replicas - plan.MaxUnavailable
+	return plan
+}
+
 // parseAndValidateRolloutParams validates and parses the input parameters.
 func parseAndValidateRolloutParams(args map[string]any) (*RolloutRestartInput, error) {
 	input := &RolloutRestartInput{}
@@ -91,5 +257,25 @@ func parseAndValidateRolloutParams(args map[string]any) (*RolloutRestartInput, e
 		return nil, fmt.Errorf("deployment must be provided")
 	}
 
+	if wait, ok := args["wait"].(bool); ok {
+		input.Wait = wait
+	}
+	if dryRun, ok := args["dryRun"].(bool); ok {
+		input.DryRun = dryRun
+	}
+	input.Timeout = defaultRolloutWaitTimeout
+	if v, ok := args["timeoutSeconds"].(float64); ok && v > 0 {
+		input.Timeout = time.Duration(v) * time.Second
+	}
+	if input.Timeout > maxRolloutWaitTimeout {
+		return nil, fmt.Errorf("timeoutSeconds must not exceed %d", int(maxRolloutWaitTimeout.Seconds()))
+	}
+	if reason, ok := args["reason"].(string); ok {
+		input.Reason = strings.Join(strings.Fields(reason), " ")
+		if len(input.Reason) > maxChangeCauseReason {
+			return nil, fmt.Errorf("reason must be at most %d characters", maxChangeCauseReason)
+		}
+	}
+
 	return input, nil
 }
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestRestartPatch(t *testing.T) {
+	patch := restartPatch(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), "")
+	assert.JSONEq(t, `{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"2025-01-02T03:04:05Z"}}}}}`, string(patch))
+
+	patch = restartPatch(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), `kubernetes-mcp rollout_restart: pick up "new" certs`)
+	assert.JSONEq(t, `{
+		"metadata":{"annotations":{"kubernetes.io/change-cause":"kubernetes-mcp rollout_restart: pick up \"new\" certs"}},
+		"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"2025-01-02T03:04:05Z"}}}}
+	}`, string(patch))
+}
+
+func TestChangeCause(t *testing.T) {
+	assert.Equal(t, "kubernetes-mcp rollout_restart", changeCause(context.Background(), "rollout_restart", ""))
+
+	ctx := auth.WithClient(context.Background(), &auth.Client{Name: "ci-bot"})
+	assert.Equal(t, "kubernetes-mcp rollout_restart (client ci-bot): INC-42", changeCause(ctx, "rollout_restart", "INC-42"))
+}
+
+func TestParseAndValidateRolloutParams(t *testing.T) {
+	input, err := parseAndValidateRolloutParams(map[string]any{"deployment": "web", "dryRun": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "default", input.Namespace)
+	assert.True(t, input.DryRun)
+
+	_, err = parseAndValidateRolloutParams(map[string]any{})
+	assert.Error(t, err)
+
+	input, err = parseAndValidateRolloutParams(map[string]any{"deployment": "web", "reason": " rotate\n credentials "})
+	assert.NoError(t, err)
+	assert.Equal(t, "rotate credentials", input.Reason)
+
+	_, err = parseAndValidateRolloutParams(map[string]any{"deployment": "web", "reason": strings.Repeat("x", maxChangeCauseReason+1)})
+	assert.Error(t, err)
+}
+
+func TestPlanRolloutRestart(t *testing.T) {
+	replicas := int32(4)
+	dep := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{restartedAtAnnotation: "2025-01-02T03:04:05Z"}}},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 4},
+	}
+
+	// Defaults of 25% surge and unavailability round to one pod each way.
+	assert.Equal(t, RolloutRestartPlan{
+		Strategy: "RollingUpdate", Replicas: 4, PodsToReplace: 4, MaxSurge: 1, MaxUnavailable: 1, MaxPods: 5, MinAvailable: 3, RestartedAt: "2025-01-02T03:04:05Z",
+	}, planRolloutRestart(dep))
+
+	zero := intstr.FromInt32(0)
+	dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType, RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &zero, MaxUnavailable: &zero}}
+	plan := planRolloutRestart(dep)
+	assert.Equal(t, int32(1), plan.MaxUnavailable)
+	assert.Equal(t, int32(3), plan.MinAvailable)
+
+	dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	plan = planRolloutRestart(dep)
+	assert.Equal(t, int32(0), plan.MinAvailable)
+	assert.Equal(t, int32(4), plan.MaxUnavailable)
+}
+
+func TestServerDryRun(t *testing.T) {
+	assert.Nil(t, serverDryRun(false))
+	assert.Equal(t, []string{metav1.DryRunAll}, serverDryRun(true))
+	assert.Equal(t, "wouldRestart", restartedKey(true))
+}
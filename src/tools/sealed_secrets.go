@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// Defaults matching kubeseal for locating the sealed-secrets controller.
+const (
+	defaultSealedSecretsNamespace  = "kube-system"
+	defaultSealedSecretsController = "sealed-secrets-controller"
+)
+
+// SealSecretInput represents the input for sealing a secret.
+type SealSecretInput struct {
+	Name                string            `json:"name"`
+	Namespace           string            `json:"namespace"`
+	Type                string            `json:"type"`
+	Scope               string            `json:"scope"`
+	Data                map[string]string `json:"-"`
+	ControllerNamespace string            `json:"controllerNamespace"`
+	ControllerName      string            `json:"controllerName"`
+}
+
+// sealedSecretLabel returns the OAEP label that binds a sealed value to its scope, as the controller expects it.
+func sealedSecretLabel(scope, namespace, name string) []byte {
+	switch scope {
+	case "cluster-wide":
+		return []byte{}
+	case "namespace-wide":
+		return []byte(namespace)
+	}
+	return []byte(namespace + "/" + name)
+}
+
+// hybridEncrypt encrypts plaintext the way sealed-secrets does: a random AES-256-GCM session key encrypts
+// the value and is itself encrypted with RSA-OAEP(SHA-256). The output is the 2-byte big-endian length of
+// the RSA ciphertext, the RSA ciphertext and the AES-GCM ciphertext (zero nonce, the key is single-use).
+func hybridEncrypt(random io.Reader, pub *rsa.PublicKey, plaintext, label []byte) ([]byte, error) {
+	sessionKey := make([]byte, 32)
+	if _, err := io.ReadFull(random, sessionKey); err != nil {
+		return nil, fmt.Errorf("failed to generate session key: %w", err)
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	aed, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	rsaCiphertext, err := rsa.EncryptOAEP(sha256.New(), random, pub, sessionKey, label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt session key: %w", err)
+	}
+
+	out := make([]byte, 2, 2+len(rsaCiphertext)+len(plaintext)+aed.Overhead())
+	binary.BigEndian.PutUint16(out, uint16(len(rsaCiphertext)))
+	out = append(out, rsaCiphertext...)
+	zeroNonce := make([]byte, aed.NonceSize())
+	return aed.Seal(out, zeroNonce, plaintext, nil), nil
+}
+
+// sealedSecretManifest builds the SealedSecret object for the encrypted values.
+func sealedSecretManifest(input *SealSecretInput, encrypted map[string]string) map[string]any {
+	metadata := map[string]any{"name": input.Name, "namespace": input.Namespace}
+	switch input.Scope {
+	case "namespace-wide":
+		metadata["annotations"] = map[string]any{"sealedsecrets.bitnami.com/namespace-wide": "true"}
+	case "cluster-wide":
+		metadata["annotations"] = map[string]any{"sealedsecrets.bitnami.com/cluster-wide": "true"}
+	}
+	return map[string]any{
+		"apiVersion": "bitnami.com/v1alpha1",
+		"kind":       "SealedSecret",
+		"metadata":   metadata,
+		"spec": map[string]any{
+			"encryptedData": encrypted,
+			"template": map[string]any{
+				"metadata": map[string]any{"name": input.Name, "namespace": input.Namespace},
+				"type":     input.Type,
+			},
+		},
+	}
+}
+
+// fetchSealingCert fetches the controller's public certificate through the API server service proxy, like kubeseal.
+func fetchSealingCert(ctx context.Context, clientset kubernetes.Interface, namespace, name string) ([]byte, error) {
+	data, err := clientset.CoreV1().Services(namespace).ProxyGet("http", name, "", "/v1/cert.pem", nil).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sealing certificate from service %s/%s: %w", namespace, name, err)
+	}
+	return data, nil
+}
+
+// SealSecretTool encrypts values into a SealedSecret manifest that is safe to commit.
+type SealSecretTool struct {
+	client Client
+}
+
+// NewSealSecretTool creates a new SealSecretTool with the provided Kubernetes client.
+func NewSealSecretTool(client Client) *SealSecretTool {
+	return &SealSecretTool{client: client}
+}
+
+// Tool returns the MCP tool definition for seal_secret.
+func (s *SealSecretTool) Tool() mcp.Tool {
+	return mcp.NewTool("seal_secret",
+		mcp.WithDescription("Encrypt key/values into a Bitnami SealedSecret manifest (like 'kubeseal') using the public certificate of the sealed-secrets controller in the cluster. The returned YAML is safe to commit; only the controller can decrypt it. Plaintext values are never returned"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Secret the controller will create"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the Secret (defaults to 'default' if not specified)"),
+		),
+		mcp.WithObject("data",
+			mcp.Required(),
+			mcp.Description("Keys and their plaintext values to seal"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("type",
+			mcp.Description("Type of the resulting Secret (default: Opaque)"),
+		),
+		mcp.WithString("scope",
+			mcp.Description("Sealing scope: 'strict' binds to name and namespace, 'namespace-wide' allows renaming, 'cluster-wide' allows any name and namespace (default: strict)"),
+			mcp.Enum("strict", "namespace-wide", "cluster-wide"),
+		),
+		mcp.WithString("controllerNamespace",
+			mcp.Description("Namespace of the sealed-secrets controller (default: kube-system)"),
+		),
+		mcp.WithString("controllerName",
+			mcp.Description("Service name of the sealed-secrets controller (default: sealed-secrets-controller)"),
+		),
+	)
+}
+
+// Handler fetches the sealing certificate and seals every value.
+func (s *SealSecretTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateSealSecretParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate seal_secret params: %w", err)
+	}
+
+	clientset, err := s.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	certPEM, err := fetchSealingCert(ctx, clientset, input.ControllerNamespace, input.ControllerName)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sealing certificate: %w", err)
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("sealing certificate does not contain an RSA public key")
+	}
+
+	label := sealedSecretLabel(input.Scope, input.Namespace, input.Name)
+	encrypted := make(map[string]string, len(input.Data))
+	keys := make([]string, 0, len(input.Data))
+	for key, value := range input.Data {
+		ciphertext, err := hybridEncrypt(rand.Reader, pub, []byte(value), label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seal key %s: %w", key, err)
+		}
+		encrypted[key] = base64.StdEncoding.EncodeToString(ciphertext)
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	manifest, err := yaml.Marshal(sealedSecretManifest(input, encrypted))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SealedSecret: %w", err)
+	}
+
+	result := map[string]any{
+		"name":         input.Name,
+		"namespace":    input.Namespace,
+		"scope":        input.Scope,
+		"keys":         keys,
+		"certNotAfter": cert.NotAfter.UTC().Format(time.RFC3339),
+		"sealedSecret": string(manifest),
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseAndValidateSealSecretParams validates and parses the input parameters.
+func parseAndValidateSealSecretParams(args map[string]any) (*SealSecretInput, error) {
+	input := &SealSecretInput{
+		Type:                string(corev1.SecretTypeOpaque),
+		Scope:               "strict",
+		ControllerNamespace: defaultSealedSecretsNamespace,
+		ControllerName:      defaultSealedSecretsController,
+	}
+
+	input.Name, _ = args["name"].(string)
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid secret name: %w", err)
+	}
+	input.Namespace, _ = args["namespace"].(string)
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	var err error
+	input.Data, err = stringMapArg(args, "data")
+	if err != nil {
+		return nil, err
+	}
+	if len(input.Data) == 0 {
+		return nil, errors.New("data must be provided")
+	}
+
+	if t, _ := args["type"].(string); t != "" {
+		input.Type = t
+	}
+	if scope, _ := args["scope"].(string); scope != "" {
+		input.Scope = strings.ToLower(scope)
+	}
+	switch input.Scope {
+	case "strict", "namespace-wide", "cluster-wide":
+	default:
+		return nil, errors.New("scope must be one of strict, namespace-wide or cluster-wide")
+	}
+
+	if ns, _ := args["controllerNamespace"].(string); ns != "" {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid controllerNamespace: %w", err)
+		}
+		input.ControllerNamespace = ns
+	}
+	if name, _ := args["controllerName"].(string); name != "" {
+		if err := validation.ValidateResourceName(name); err != nil {
+			return nil, fmt.Errorf("invalid controllerName: %w", err)
+		}
+		input.ControllerName = name
+	}
+
+	return input, nil
+}
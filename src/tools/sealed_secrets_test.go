@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHybridEncrypt(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	label := sealedSecretLabel("strict", "prod", "db")
+
+	ciphertext, err := hybridEncrypt(rand.Reader, &key.PublicKey, []byte("s3cr3t"), label)
+	assert.NoError(t, err)
+
+	rsaLen := int(binary.BigEndian.Uint16(ciphertext))
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), nil, key, ciphertext[2:2+rsaLen], label)
+	assert.NoError(t, err)
+	block, err := aes.NewCipher(sessionKey)
+	assert.NoError(t, err)
+	aed, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+	plaintext, err := aed.Open(nil, make([]byte, aed.NonceSize()), ciphertext[2+rsaLen:], nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(plaintext))
+
+	// A value sealed for one name cannot be decrypted with another name's label.
+	_, err = rsa.DecryptOAEP(sha256.New(), nil, key, ciphertext[2:2+rsaLen], sealedSecretLabel("strict", "prod", "other"))
+	assert.Error(t, err)
+}
+
+func TestSealedSecretLabel(t *testing.T) {
+	assert.Equal(t, "prod/db", string(sealedSecretLabel("strict", "prod", "db")))
+	assert.Equal(t, "prod", string(sealedSecretLabel("namespace-wide", "prod", "db")))
+	assert.Empty(t, sealedSecretLabel("cluster-wide", "prod", "db"))
+}
+
+func TestSealedSecretManifest(t *testing.T) {
+	input := &SealSecretInput{Name: "db", Namespace: "prod", Type: "Opaque", Scope: "namespace-wide"}
+	manifest := sealedSecretManifest(input, map[string]string{"password": "AgB..."})
+
+	assert.Equal(t, "SealedSecret", manifest["kind"])
+	metadata := manifest["metadata"].(map[string]any)
+	assert.Equal(t, map[string]any{"sealedsecrets.bitnami.com/namespace-wide": "true"}, metadata["annotations"])
+	spec := manifest["spec"].(map[string]any)
+	assert.Equal(t, map[string]string{"password": "AgB..."}, spec["encryptedData"])
+	assert.Equal(t, "Opaque", spec["template"].(map[string]any)["type"])
+
+	input.Scope = "strict"
+	metadata = sealedSecretManifest(input, nil)["metadata"].(map[string]any)
+	assert.NotContains(t, metadata, "annotations")
+}
+
+func TestParseAndValidateSealSecretParams(t *testing.T) {
+	input, err := parseAndValidateSealSecretParams(map[string]any{
+		"name": "db",
+		"data": map[string]any{"password": "x"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "default", input.Namespace)
+	assert.Equal(t, "strict", input.Scope)
+	assert.Equal(t, "Opaque", input.Type)
+	assert.Equal(t, defaultSealedSecretsNamespace, input.ControllerNamespace)
+	assert.Equal(t, defaultSealedSecretsController, input.ControllerName)
+
+	_, err = parseAndValidateSealSecretParams(map[string]any{"name": "db"})
+	assert.Error(t, err)
+
+	_, err = parseAndValidateSealSecretParams(map[string]any{
+		"name":  "db",
+		"data":  map[string]any{"password": "x"},
+		"scope": "global",
+	})
+	assert.Error(t, err)
+}
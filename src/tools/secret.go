@@ -0,0 +1,341 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/redact"
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Environment variables used by this tool:
+// Optional:
+//   K8S_SECRET_ALLOW_REVEAL - Set to "true" to allow returning plaintext secret values when reveal is requested
+
+// K8sSecretInput represents the input for inspecting or editing a Kubernetes Secret.
+type K8sSecretInput struct {
+	Namespace         string            `json:"namespace"`
+	Name              string            `json:"name,omitempty"`
+	Action            string            `json:"action"`
+	Key               string            `json:"key,omitempty"`
+	Reveal            bool              `json:"reveal,omitempty"`
+	Data              map[string]string `json:"data,omitempty"`
+	Keys              []string          `json:"keys,omitempty"`
+	RestartDependents bool              `json:"restartDependents,omitempty"`
+	DryRun            bool              `json:"dryRun,omitempty"`
+}
+
+// SecretSummary lists a Secret's keys without their values.
+type SecretSummary struct {
+	Name      string         `json:"name"`
+	Namespace string         `json:"namespace"`
+	Type      string         `json:"type"`
+	Keys      map[string]int `json:"keys"`
+}
+
+// K8sSecretTool lists, reads and edits in-cluster Secrets with values redacted by default.
+type K8sSecretTool struct {
+	client Client
+}
+
+// NewK8sSecretTool creates a new K8sSecretTool with the provided Kubernetes client.
+func NewK8sSecretTool(client Client) *K8sSecretTool {
+	return &K8sSecretTool{client: client}
+}
+
+// Tool returns the MCP tool definition for k8s_secret.
+func (s *K8sSecretTool) Tool() mcp.Tool {
+	return mcp.NewTool("k8s_secret",
+		mcp.WithDescription("Manage in-cluster Kubernetes Secrets: list secret names and keys, inspect a secret (values redacted unless reveal is set and the server allows it), or set/remove individual keys"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the Secret (defaults to 'default' if not specified)"),
+		),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("Action to perform: 'list', 'get', 'set' or 'remove'"),
+			mcp.Enum("list", "get", "set", "remove"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the Secret (required for 'get', 'set' and 'remove')"),
+		),
+		mcp.WithString("key",
+			mcp.Description("For 'get': the single key whose value should be revealed"),
+		),
+		mcp.WithBoolean("reveal",
+			mcp.Description("For 'get' with key: return the plaintext value; only honored when the server sets K8S_SECRET_ALLOW_REVEAL=true (default: false)"),
+		),
+		mcp.WithObject("data",
+			mcp.Description("For 'set': keys and their new plaintext values"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("keys",
+			mcp.Description("For 'remove': keys to delete from the Secret"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("restartDependents",
+			mcp.Description("For 'set' and 'remove': rollout restart Deployments and StatefulSets in the namespace that reference this Secret (default: false)"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("For 'set' and 'remove': validate the update and restarts with a server-side dry run and report the changes and workloads that would restart, without persisting anything (default: false)"),
+		),
+	)
+}
+
+// Handler performs the requested Secret action.
+func (s *K8sSecretTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateK8sSecretParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate secret params: %w", err)
+	}
+
+	clientset, err := s.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	result, err := s.run(ctx, clientset, input)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// run performs the Secret action. Revealing a value is refused unless the server allows it.
+func (s *K8sSecretTool) run(ctx context.Context, clientset kubernetes.Interface, input *K8sSecretInput) (any, error) {
+	if input.Reveal && !secretRevealAllowed() {
+		return nil, errRevealDisabled()
+	}
+	secrets := clientset.CoreV1().Secrets(input.Namespace)
+
+	var result any
+	switch input.Action {
+	case "list":
+		list, err := secrets.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+		summaries := make([]SecretSummary, 0, len(list.Items))
+		for _, secret := range list.Items {
+			summaries = append(summaries, summarizeSecret(&secret))
+		}
+		result = summaries
+	case "get":
+		secret, err := secrets.Get(ctx, input.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", input.Namespace, input.Name, err)
+		}
+		out := map[string]any{"secret": summarizeSecret(secret)}
+		if input.Key != "" {
+			value, ok := secret.Data[input.Key]
+			if !ok {
+				return nil, fmt.Errorf("key '%s' not found in secret", input.Key)
+			}
+			out["key"] = input.Key
+			out["value"] = redactedValue(value)
+			if input.Reveal {
+				out["value"] = string(value)
+			}
+		}
+		result = out
+	case "set", "remove":
+		secret, err := secrets.Get(ctx, input.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", input.Namespace, input.Name, err)
+		}
+		data := secretDataToStrings(secret.Data)
+		var changes []KeyChange
+		if input.Action == "set" {
+			changes = applyKeyUpdates(data, input.Data)
+		} else {
+			changes, err = applyKeyRemovals(data, input.Keys)
+			if err != nil {
+				return nil, err
+			}
+		}
+		redactKeyChanges(changes)
+
+		out := map[string]any{
+			"name":      input.Name,
+			"namespace": input.Namespace,
+			"changes":   changes,
+		}
+		if len(changes) == 0 {
+			out["status"] = "No changes"
+		} else {
+			secret.Data = stringsToSecretData(data)
+			secret.StringData = nil
+			if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{DryRun: serverDryRun(input.DryRun)}); err != nil {
+				return nil, fmt.Errorf("failed to update secret: %w", err)
+			}
+			out["status"] = "Secret updated"
+			if input.DryRun {
+				out["status"] = "Dry run: secret not changed"
+				out["dryRun"] = true
+			}
+
+			if input.RestartDependents {
+				restarted, err := restartDependents(ctx, clientset, input.Namespace, configKindSecret, input.Name, input.DryRun)
+				out[restartedKey(input.DryRun)] = restarted
+				if err != nil {
+					out["restartError"] = err.Error()
+				}
+			}
+		}
+		result = out
+	}
+	return result, nil
+}
+
+// secretRevealAllowed reports whether the server permits returning plaintext secret values. The
+// always redaction policy overrides K8S_SECRET_ALLOW_REVEAL.
+func secretRevealAllowed() bool {
+	if policy, err := redact.PolicyFromEnv(); err != nil || policy == redact.Always {
+		return false
+	}
+	allowed, _ := strconv.ParseBool(os.Getenv("K8S_SECRET_ALLOW_REVEAL"))
+	return allowed
+}
+
+// errRevealDisabled explains why a request to reveal secret values was refused.
+func errRevealDisabled() error {
+	if policy, _ := redact.PolicyFromEnv(); policy == redact.Always {
+		return errors.New("revealing secret values is disabled on this server: REDACT_POLICY=always")
+	}
+	return errors.New("revealing secret values is disabled on this server: set K8S_SECRET_ALLOW_REVEAL=true to allow it")
+}
+
+// summarizeSecret returns the Secret's keys and value sizes, never the values.
+func summarizeSecret(secret *corev1.Secret) SecretSummary {
+	keys := make(map[string]int, len(secret.Data))
+	for k, v := range secret.Data {
+		keys[k] = len(v)
+	}
+	return SecretSummary{
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Type:      string(secret.Type),
+		Keys:      keys,
+	}
+}
+
+// redactedValue describes a secret value without disclosing it.
+func redactedValue(value []byte) string {
+	return fmt.Sprintf("(redacted, %d bytes)", len(value))
+}
+
+// redactKeyChanges replaces the values in a diff with redaction markers.
+func redactKeyChanges(changes []KeyChange) {
+	for i := range changes {
+		if changes[i].OldValue != "" {
+			changes[i].OldValue = "(redacted)"
+		}
+		if changes[i].NewValue != "" {
+			changes[i].NewValue = "(redacted)"
+		}
+	}
+}
+
+func secretDataToStrings(data map[string][]byte) map[string]string {
+	result := make(map[string]string, len(data))
+	for k, v := range data {
+		result[k] = string(v)
+	}
+	return result
+}
+
+func stringsToSecretData(data map[string]string) map[string][]byte {
+	result := make(map[string][]byte, len(data))
+	for k, v := range data {
+		result[k] = []byte(v)
+	}
+	return result
+}
+
+// parseAndValidateK8sSecretParams validates and parses the input parameters.
+func parseAndValidateK8sSecretParams(args map[string]any) (*K8sSecretInput, error) {
+	input := &K8sSecretInput{}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	if action, ok := args["action"].(string); ok {
+		input.Action = strings.ToLower(action)
+	}
+	switch input.Action {
+	case "list", "get", "set", "remove":
+	default:
+		return nil, errors.New("action must be one of list, get, set or remove")
+	}
+
+	if name, ok := args["name"].(string); ok {
+		input.Name = name
+	}
+	if input.Action != "list" {
+		if input.Name == "" {
+			return nil, fmt.Errorf("name must be provided for action %s", input.Action)
+		}
+		if err := validation.ValidateResourceName(input.Name); err != nil {
+			return nil, fmt.Errorf("invalid secret name: %w", err)
+		}
+	}
+
+	if key, ok := args["key"].(string); ok {
+		input.Key = key
+	}
+	if reveal, ok := args["reveal"].(bool); ok {
+		input.Reveal = reveal
+	}
+	if input.Reveal && (input.Action != "get" || input.Key == "") {
+		return nil, errors.New("reveal requires action get and a key")
+	}
+
+	if restart, ok := args["restartDependents"].(bool); ok {
+		input.RestartDependents = restart
+	}
+	if dryRun, ok := args["dryRun"].(bool); ok {
+		input.DryRun = dryRun
+	}
+
+	var err error
+	switch input.Action {
+	case "set":
+		input.Data, err = stringMapArg(args, "data")
+		if err != nil {
+			return nil, err
+		}
+		if len(input.Data) == 0 {
+			return nil, errors.New("data must be provided for action set")
+		}
+	case "remove":
+		input.Keys, err = stringSliceArg(args, "keys")
+		if err != nil {
+			return nil, err
+		}
+		if len(input.Keys) == 0 {
+			return nil, errors.New("keys must be provided for action remove")
+		}
+	}
+
+	return input, nil
+}
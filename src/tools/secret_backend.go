@@ -0,0 +1,184 @@
+// Environment variables used by the secret tools to pick a backend:
+// Optional:
+//   SECRET_BACKEND                 - Secret store used when a call does not pass backend: gcp, azure, aws, vault or k8s (default: gcp)
+//
+// Each backend reads its own settings; see secret_backend_<name>.go.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Secret stores the secret tools can work against.
+const (
+	secretBackendGCP   = "gcp"
+	secretBackendAzure = "azure"
+	secretBackendAWS   = "aws"
+	secretBackendVault = "vault"
+	secretBackendK8s   = "k8s"
+)
+
+// secretVersionPatterns matches the version IDs of each backend; "latest" is accepted by all of them.
+// Kubernetes Secrets keep no history, so only "latest" is valid there.
+var secretVersionPatterns = map[string]*regexp.Regexp{
+	secretBackendGCP:   regexp.MustCompile(`^[0-9]+$`),
+	secretBackendAzure: regexp.MustCompile(`^[0-9a-f]{32}$`),
+	secretBackendAWS:   regexp.MustCompile(`^[A-Za-z0-9-]{32,64}$`),
+	secretBackendVault: regexp.MustCompile(`^[0-9]+$`),
+}
+
+// secretBackend is a secret store that holds versioned payloads. Drivers exist for Google Cloud
+// Secret Manager, Azure Key Vault, AWS Secrets Manager, HashiCorp Vault KV v2 and in-cluster
+// Kubernetes Secrets; diffs and rollbacks are built on Get and Update.
+type secretBackend interface {
+	// List returns one page of secrets matching the input and the token of the next page, if any.
+	List(ctx context.Context, input *SecretListInput) ([]BackendSecret, string, error)
+	// Get returns the payload of a version ("latest" for the current one) and the version it resolved to.
+	Get(ctx context.Context, secret, version string) ([]byte, string, error)
+	// Update stores a payload as the new latest version and returns its version.
+	Update(ctx context.Context, secret string, data []byte) (string, error)
+	// Versions returns up to limit versions of a secret, newest first.
+	Versions(ctx context.Context, secret string, limit int) ([]BackendSecretVersion, error)
+	Close() error
+}
+
+// SecretBackendInput selects the backend of a secret tool call and where in it secrets live.
+type SecretBackendInput struct {
+	Backend   string `json:"backend,omitempty"`
+	ProjectID string `json:"projectId,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// withSecretBackend adds the parameters shared by the secret tools to pick a backend.
+func withSecretBackend() mcp.ToolOption {
+	options := []mcp.ToolOption{
+		mcp.WithString("backend",
+			mcp.Description("Secret store: gcp (Secret Manager), azure (Key Vault), aws (Secrets Manager), vault (HashiCorp Vault KV v2) or k8s (in-cluster Secrets) (default: SECRET_BACKEND env, else gcp)"),
+			mcp.Enum(secretBackendGCP, secretBackendAzure, secretBackendAWS, secretBackendVault, secretBackendK8s),
+		),
+		mcp.WithString("projectId", mcp.Description("GCP Project ID for the gcp backend (optional, will use GOOGLE_CLOUD_PROJECT env if not set)")),
+		mcp.WithString("namespace", mcp.Description("Namespace of the Secrets for the k8s backend (defaults to 'default')")),
+	}
+	return func(t *mcp.Tool) {
+		for _, option := range options {
+			option(t)
+		}
+	}
+}
+
+// resolveSecretBackend falls back to SECRET_BACKEND when no backend was given and checks it is known.
+func resolveSecretBackend(name string) (string, error) {
+	if name == "" {
+		name = os.Getenv("SECRET_BACKEND")
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return secretBackendGCP, nil
+	}
+	if _, ok := secretVersionPatterns[name]; ok || name == secretBackendK8s {
+		return name, nil
+	}
+	return "", fmt.Errorf("unsupported secret backend %q: use gcp, azure, aws, vault or k8s", name)
+}
+
+// parseSecretBackendInput resolves the backend and the project or namespace it needs.
+func parseSecretBackendInput(args map[string]any) (SecretBackendInput, error) {
+	var input SecretBackendInput
+	backend, _ := args["backend"].(string)
+	var err error
+	if input.Backend, err = resolveSecretBackend(backend); err != nil {
+		return input, err
+	}
+	if input.Backend == secretBackendGCP {
+		projectID, _ := args["projectId"].(string)
+		if input.ProjectID, err = resolveGCPProjectID(projectID); err != nil {
+			return input, err
+		}
+	}
+	if ns, ok := args["namespace"].(string); ok && ns != "" {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return input, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = ns
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+	return input, nil
+}
+
+// validateSecretVersion checks a version is "latest" or in the form the backend uses.
+func validateSecretVersion(backend, version string) error {
+	if version == "latest" {
+		return nil
+	}
+	pattern, ok := secretVersionPatterns[backend]
+	if !ok {
+		return fmt.Errorf("%q: the %s backend keeps no version history, only latest is available", version, backend)
+	}
+	if !pattern.MatchString(version) {
+		return fmt.Errorf("%q is not a %s version ID or latest", version, backend)
+	}
+	return nil
+}
+
+// newSecretBackend creates a client for the selected backend.
+func newSecretBackend(ctx context.Context, client Client, input SecretBackendInput) (secretBackend, error) {
+	switch input.Backend {
+	case secretBackendAzure:
+		return newAzureSecretBackend()
+	case secretBackendAWS:
+		return newAWSSecretBackend(ctx)
+	case secretBackendVault:
+		return newVaultSecretBackend()
+	case secretBackendK8s:
+		return newK8sSecretBackend(client, input.Namespace)
+	}
+	return newGCPSecretBackend(ctx, input.ProjectID)
+}
+
+// secretVersionValues reads a version and decodes its payload into key/value pairs. The resolved
+// version is returned so "latest" is reported as the version it pointed at.
+func secretVersionValues(ctx context.Context, backend secretBackend, secret, version string) (map[string]string, string, error) {
+	data, resolved, err := backend.Get(ctx, secret, version)
+	if err != nil {
+		return nil, "", err
+	}
+	values, err := secretPayloadValues(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("version %s: %w", version, err)
+	}
+	return values, resolved, nil
+}
+
+// diffSecretVersions returns the key-level changes between two versions of a secret and the
+// versions they resolved to.
+func diffSecretVersions(ctx context.Context, backend secretBackend, secret, fromVersion, toVersion string) ([]KeyChange, string, string, error) {
+	from, fromResolved, err := secretVersionValues(ctx, backend, secret, fromVersion)
+	if err != nil {
+		return nil, "", "", err
+	}
+	to, toResolved, err := secretVersionValues(ctx, backend, secret, toVersion)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return diffSecretValues(from, to), fromResolved, toResolved, nil
+}
+
+// rollbackSecret stores the payload of an older version as the new latest version, keeping history.
+func rollbackSecret(ctx context.Context, backend secretBackend, secret, version string) (string, error) {
+	data, _, err := backend.Get(ctx, secret, version)
+	if err != nil {
+		return "", err
+	}
+	return backend.Update(ctx, secret, data)
+}
@@ -0,0 +1,164 @@
+// Environment variables used by the aws secret backend:
+// Required:
+//   AWS_REGION                     - Region of the secrets
+// Optional:
+//   AWS_PROFILE, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY - Credentials read by the default AWS config chain
+//                                    (IRSA, ECS/EC2 instance roles and SSO profiles are used otherwise)
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/smithy-go"
+)
+
+// awsSecretBackend stores secrets in AWS Secrets Manager.
+type awsSecretBackend struct {
+	client *secretsmanager.Client
+}
+
+// newAWSSecretBackend creates a Secrets Manager client from the default AWS config chain.
+func newAWSSecretBackend(ctx context.Context) (*awsSecretBackend, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("aws region not configured: set AWS_REGION")
+	}
+	return &awsSecretBackend{client: secretsmanager.NewFromConfig(cfg)}, nil
+}
+
+// List filters by name prefix on the server; labels are matched against tags on the client, so a
+// page may hold fewer secrets than pageSize.
+func (b *awsSecretBackend) List(ctx context.Context, input *SecretListInput) ([]BackendSecret, string, error) {
+	req := &secretsmanager.ListSecretsInput{MaxResults: aws.Int32(int32(input.PageSize))}
+	if input.NamePrefix != "" {
+		req.Filters = []types.Filter{{Key: types.FilterNameStringTypeName, Values: []string{input.NamePrefix}}}
+	}
+	if input.PageToken != "" {
+		req.NextToken = aws.String(input.PageToken)
+	}
+	resp, err := b.client.ListSecrets(ctx, req)
+	if err != nil {
+		return nil, "", awsSecretError(err, "list secrets", "secretsmanager:ListSecrets")
+	}
+	secrets := []BackendSecret{}
+	for _, entry := range resp.SecretList {
+		summary := summarizeAWSSecret(entry)
+		if secretMatches(summary, input) {
+			secrets = append(secrets, summary)
+		}
+	}
+	return secrets, aws.ToString(resp.NextToken), nil
+}
+
+func (b *awsSecretBackend) Get(ctx context.Context, secret, version string) ([]byte, string, error) {
+	req := &secretsmanager.GetSecretValueInput{SecretId: aws.String(secret)}
+	if version == "latest" {
+		req.VersionStage = aws.String("AWSCURRENT")
+	} else {
+		req.VersionId = aws.String(version)
+	}
+	resp, err := b.client.GetSecretValue(ctx, req)
+	if err != nil {
+		return nil, "", awsSecretError(err, "get secret value of "+secret, "secretsmanager:GetSecretValue")
+	}
+	if resp.SecretString != nil {
+		return []byte(*resp.SecretString), aws.ToString(resp.VersionId), nil
+	}
+	return resp.SecretBinary, aws.ToString(resp.VersionId), nil
+}
+
+func (b *awsSecretBackend) Update(ctx context.Context, secret string, data []byte) (string, error) {
+	resp, err := b.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(secret),
+		SecretString: aws.String(string(data)),
+	})
+	if err != nil {
+		return "", awsSecretError(err, "put secret value of "+secret, "secretsmanager:PutSecretValue")
+	}
+	return aws.ToString(resp.VersionId), nil
+}
+
+// Versions lists every version including deprecated ones, since version IDs carry no order, and
+// keeps the newest ones.
+func (b *awsSecretBackend) Versions(ctx context.Context, secret string, limit int) ([]BackendSecretVersion, error) {
+	versions := []BackendSecretVersion{}
+	paginator := secretsmanager.NewListSecretVersionIdsPaginator(b.client, &secretsmanager.ListSecretVersionIdsInput{
+		SecretId:          aws.String(secret),
+		IncludeDeprecated: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, awsSecretError(err, "list versions of "+secret, "secretsmanager:ListSecretVersionIds")
+		}
+		for _, entry := range page.Versions {
+			versions = append(versions, awsSecretVersion(entry))
+		}
+	}
+	sortSecretVersions(versions)
+	if len(versions) > limit {
+		versions = versions[:limit]
+	}
+	return versions, nil
+}
+
+func (b *awsSecretBackend) Close() error {
+	return nil
+}
+
+// summarizeAWSSecret returns the name, tags, create time and replica regions of a secret.
+func summarizeAWSSecret(entry types.SecretListEntry) BackendSecret {
+	summary := BackendSecret{Name: aws.ToString(entry.Name)}
+	for _, tag := range entry.Tags {
+		if summary.Labels == nil {
+			summary.Labels = map[string]string{}
+		}
+		summary.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	if entry.CreatedDate != nil {
+		summary.Created = entry.CreatedDate.UTC().Format(time.RFC3339)
+	}
+	if entry.PrimaryRegion != nil {
+		summary.Replication = "primary: " + aws.ToString(entry.PrimaryRegion)
+	}
+	return summary
+}
+
+// awsSecretVersion converts a version entry into its ID, staging labels as state and create time.
+// Versions without staging labels are deprecated and may be removed by AWS.
+func awsSecretVersion(entry types.SecretVersionsListEntry) BackendSecretVersion {
+	version := BackendSecretVersion{Version: aws.ToString(entry.VersionId), State: "DEPRECATED"}
+	if len(entry.VersionStages) > 0 {
+		version.State = strings.Join(entry.VersionStages, ",")
+	}
+	if entry.CreatedDate != nil {
+		version.Created = entry.CreatedDate.UTC().Format(time.RFC3339)
+	}
+	return version
+}
+
+// awsSecretError turns an access denied error into a hint about the IAM action to allow.
+func awsSecretError(err error, action, iamAction string) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "AccessDeniedException":
+			return fmt.Errorf("permission denied to %s: allow %s for the caller in IAM (%w)", action, iamAction, err)
+		case "ResourceNotFoundException":
+			return fmt.Errorf("failed to %s: not found (%w)", action, err)
+		}
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
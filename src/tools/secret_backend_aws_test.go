@@ -0,0 +1,32 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeAWSSecret(t *testing.T) {
+	summary := summarizeAWSSecret(types.SecretListEntry{
+		Name:          aws.String("app-env"),
+		CreatedDate:   aws.Time(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Tags:          []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+		PrimaryRegion: aws.String("eu-west-1"),
+	})
+	assert.Equal(t, BackendSecret{
+		Name:        "app-env",
+		Created:     "2025-01-02T03:04:05Z",
+		Labels:      map[string]string{"env": "prod"},
+		Replication: "primary: eu-west-1",
+	}, summary)
+}
+
+func TestAWSSecretVersion(t *testing.T) {
+	created := aws.Time(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	assert.Equal(t, BackendSecretVersion{Version: "v1", State: "AWSCURRENT", Created: "2025-01-02T03:04:05Z"},
+		awsSecretVersion(types.SecretVersionsListEntry{VersionId: aws.String("v1"), VersionStages: []string{"AWSCURRENT"}, CreatedDate: created}))
+	assert.Equal(t, "DEPRECATED", awsSecretVersion(types.SecretVersionsListEntry{VersionId: aws.String("v0")}).State)
+}
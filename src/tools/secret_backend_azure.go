@@ -0,0 +1,180 @@
+// Environment variables used by the Azure Key Vault secret backend:
+// Required:
+//   AZURE_KEYVAULT_URL             - URL of the vault, e.g. https://my-vault.vault.azure.net/
+// Optional:
+//   AZURE_TENANT_ID, AZURE_CLIENT_ID, AZURE_CLIENT_SECRET - Service principal read by DefaultAzureCredential
+//                                    (managed identity, workload identity or the Azure CLI login are used otherwise)
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+)
+
+// azureSecretBackend stores secrets in Azure Key Vault.
+type azureSecretBackend struct {
+	client *azsecrets.Client
+}
+
+// newAzureSecretBackend creates a Key Vault client for AZURE_KEYVAULT_URL using DefaultAzureCredential.
+func newAzureSecretBackend() (*azureSecretBackend, error) {
+	vaultURL := os.Getenv("AZURE_KEYVAULT_URL")
+	if vaultURL == "" {
+		return nil, fmt.Errorf("azure key vault not configured: set AZURE_KEYVAULT_URL to the vault URL")
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure credential: %w", err)
+	}
+	client, err := azsecrets.NewClient(vaultURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key vault client: %w", err)
+	}
+	return &azureSecretBackend{client: client}, nil
+}
+
+// List pages through the vault and filters by name prefix and tags on the client, since Key Vault
+// has no server-side filter. The page token is the number of matching secrets already returned.
+func (b *azureSecretBackend) List(ctx context.Context, input *SecretListInput) ([]BackendSecret, string, error) {
+	offset := 0
+	if input.PageToken != "" {
+		n, err := strconv.Atoi(input.PageToken)
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("invalid pageToken %q", input.PageToken)
+		}
+		offset = n
+	}
+
+	secrets := []BackendSecret{}
+	matched := 0
+	pager := b.client.NewListSecretPropertiesPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, "", azureSecretError(err, "list secrets")
+		}
+		for _, props := range page.Value {
+			summary := summarizeAzureSecret(props)
+			if !secretMatches(summary, input) {
+				continue
+			}
+			if matched++; matched <= offset {
+				continue
+			}
+			if len(secrets) == input.PageSize {
+				return secrets, strconv.Itoa(offset + len(secrets)), nil
+			}
+			secrets = append(secrets, summary)
+		}
+	}
+	return secrets, "", nil
+}
+
+func (b *azureSecretBackend) Get(ctx context.Context, secret, version string) ([]byte, string, error) {
+	if version == "latest" {
+		version = ""
+	}
+	resp, err := b.client.GetSecret(ctx, secret, version, nil)
+	if err != nil {
+		return nil, "", azureSecretError(err, "access secret "+secret)
+	}
+	if resp.Value == nil {
+		return nil, "", fmt.Errorf("secret %s has no value", secret)
+	}
+	return []byte(*resp.Value), resp.ID.Version(), nil
+}
+
+func (b *azureSecretBackend) Update(ctx context.Context, secret string, data []byte) (string, error) {
+	value := string(data)
+	resp, err := b.client.SetSecret(ctx, secret, azsecrets.SetSecretParameters{Value: &value}, nil)
+	if err != nil {
+		return "", azureSecretError(err, "set secret "+secret)
+	}
+	return resp.ID.Version(), nil
+}
+
+// Versions lists every version, since Key Vault returns them in no particular order, and keeps the
+// newest ones.
+func (b *azureSecretBackend) Versions(ctx context.Context, secret string, limit int) ([]BackendSecretVersion, error) {
+	versions := []BackendSecretVersion{}
+	pager := b.client.NewListSecretPropertiesVersionsPager(secret, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, azureSecretError(err, "list versions of "+secret)
+		}
+		for _, props := range page.Value {
+			versions = append(versions, azureSecretVersion(props))
+		}
+	}
+	sortSecretVersions(versions)
+	if len(versions) > limit {
+		versions = versions[:limit]
+	}
+	return versions, nil
+}
+
+func (b *azureSecretBackend) Close() error {
+	return nil
+}
+
+// summarizeAzureSecret returns the name, tags and create time of a Key Vault secret.
+func summarizeAzureSecret(props *azsecrets.SecretProperties) BackendSecret {
+	summary := BackendSecret{Labels: map[string]string{}}
+	if props.ID != nil {
+		summary.Name = props.ID.Name()
+	}
+	for k, v := range props.Tags {
+		if v != nil {
+			summary.Labels[k] = *v
+		}
+	}
+	if len(summary.Labels) == 0 {
+		summary.Labels = nil
+	}
+	if props.Attributes != nil && props.Attributes.Created != nil {
+		summary.Created = props.Attributes.Created.UTC().Format(time.RFC3339)
+	}
+	return summary
+}
+
+// azureSecretVersion converts Key Vault version properties into a version ID, state and create time.
+func azureSecretVersion(props *azsecrets.SecretProperties) BackendSecretVersion {
+	version := BackendSecretVersion{State: "ENABLED"}
+	if props.ID != nil {
+		version.Version = props.ID.Version()
+	}
+	if props.Attributes != nil {
+		if props.Attributes.Enabled != nil && !*props.Attributes.Enabled {
+			version.State = "DISABLED"
+		}
+		if props.Attributes.Created != nil {
+			version.Created = props.Attributes.Created.UTC().Format(time.RFC3339)
+		}
+	}
+	return version
+}
+
+// azureSecretError turns a forbidden response into a hint about the Key Vault role to grant.
+func azureSecretError(err error, action string) error {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		switch respErr.StatusCode {
+		case http.StatusForbidden:
+			return fmt.Errorf("permission denied to %s: grant the identity the Key Vault Secrets Officer role on the vault (%w)", action, err)
+		case http.StatusNotFound:
+			return fmt.Errorf("failed to %s: not found (%w)", action, err)
+		}
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
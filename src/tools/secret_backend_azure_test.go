@@ -0,0 +1,32 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeAzureSecret(t *testing.T) {
+	id := azsecrets.ID("https://my-vault.vault.azure.net/secrets/app-env")
+	env := "prod"
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	summary := summarizeAzureSecret(&azsecrets.SecretProperties{
+		ID:         &id,
+		Tags:       map[string]*string{"env": &env},
+		Attributes: &azsecrets.SecretAttributes{Created: &created},
+	})
+	assert.Equal(t, BackendSecret{Name: "app-env", Created: "2025-01-02T03:04:05Z", Labels: map[string]string{"env": "prod"}}, summary)
+}
+
+func TestAzureSecretVersion(t *testing.T) {
+	id := azsecrets.ID("https://my-vault.vault.azure.net/secrets/app-env/0123456789abcdef0123456789abcdef")
+	enabled := false
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	version := azureSecretVersion(&azsecrets.SecretProperties{
+		ID:         &id,
+		Attributes: &azsecrets.SecretAttributes{Enabled: &enabled, Created: &created},
+	})
+	assert.Equal(t, BackendSecretVersion{Version: "0123456789abcdef0123456789abcdef", State: "DISABLED", Created: "2025-01-02T03:04:05Z"}, version)
+}
@@ -0,0 +1,203 @@
+// Environment variables used by the gcp secret backend:
+// Optional:
+//   GOOGLE_APPLICATION_CREDENTIALS - Path to a GCP service account JSON file; without it gcloud ADC or Workload Identity is used
+//   GOOGLE_CLOUD_PROJECT           - GCP Project ID (used if not provided in input)
+//   GCP_ALLOWED_PROJECTS           - Comma-separated project IDs the secret tools may access (default: any)
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/api/iterator"
+)
+
+// gcpLabelKeyPattern matches valid GCP resource label keys.
+var gcpLabelKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+
+// gcpSecretBackend stores secrets in Google Cloud Secret Manager.
+type gcpSecretBackend struct {
+	client    *secretmanager.Client
+	projectID string
+}
+
+// newGCPSecretBackend creates a Secret Manager client for secrets of the project.
+func newGCPSecretBackend(ctx context.Context, projectID string) (*gcpSecretBackend, error) {
+	client, err := newSecretManagerClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &gcpSecretBackend{client: client, projectID: projectID}, nil
+}
+
+func (b *gcpSecretBackend) secretPath(secret string) string {
+	return fmt.Sprintf("projects/%s/secrets/%s", b.projectID, secret)
+}
+
+func (b *gcpSecretBackend) List(ctx context.Context, input *SecretListInput) ([]BackendSecret, string, error) {
+	it := b.client.ListSecrets(ctx, &secretmanagerpb.ListSecretsRequest{
+		Parent: "projects/" + b.projectID,
+		Filter: buildGCPSecretFilter(input),
+	})
+	var page []*secretmanagerpb.Secret
+	nextPageToken, err := iterator.NewPager(it, input.PageSize, input.PageToken).NextPage(&page)
+	if err != nil {
+		return nil, "", gcpSecretError(err, "list secrets", "roles/secretmanager.viewer")
+	}
+	secrets := []BackendSecret{}
+	for _, secret := range page {
+		summary := summarizeGCPSecret(secret)
+		// The name: filter matches substrings, so the prefix is checked again here.
+		if strings.HasPrefix(summary.Name, input.NamePrefix) {
+			secrets = append(secrets, summary)
+		}
+	}
+	return secrets, nextPageToken, nil
+}
+
+func (b *gcpSecretBackend) Get(ctx context.Context, secret, version string) ([]byte, string, error) {
+	result, err := b.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{Name: b.secretPath(secret) + "/versions/" + version})
+	if err != nil {
+		return nil, "", gcpSecretError(err, "access version "+version, "roles/secretmanager.secretAccessor")
+	}
+	return result.Payload.Data, path.Base(result.Name), nil
+}
+
+func (b *gcpSecretBackend) Update(ctx context.Context, secret string, data []byte) (string, error) {
+	version, err := b.client.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
+		Parent:  b.secretPath(secret),
+		Payload: &secretmanagerpb.SecretPayload{Data: data},
+	})
+	if err != nil {
+		return "", gcpSecretError(err, "add new secret version", "roles/secretmanager.secretVersionAdder")
+	}
+	return path.Base(version.Name), nil
+}
+
+func (b *gcpSecretBackend) Versions(ctx context.Context, secret string, limit int) ([]BackendSecretVersion, error) {
+	it := b.client.ListSecretVersions(ctx, &secretmanagerpb.ListSecretVersionsRequest{Parent: b.secretPath(secret)})
+	versions := []BackendSecretVersion{}
+	for len(versions) < limit {
+		v, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, gcpSecretError(err, "list versions of "+secret, "roles/secretmanager.viewer")
+		}
+		versions = append(versions, gcpSecretVersion(v))
+	}
+	sortSecretVersions(versions)
+	return versions, nil
+}
+
+func (b *gcpSecretBackend) Close() error {
+	return b.client.Close()
+}
+
+// newSecretManagerClient resolves Application Default Credentials and creates a Secret Manager client.
+func newSecretManagerClient(ctx context.Context) (*secretmanager.Client, error) {
+	creds, _, err := gcpCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := secretmanager.NewClient(ctx, creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secretmanager client: %w", err)
+	}
+	return client, nil
+}
+
+// summarizeGCPSecret returns the metadata of a secret: name, labels, create time and where it is replicated.
+func summarizeGCPSecret(secret *secretmanagerpb.Secret) BackendSecret {
+	summary := BackendSecret{
+		Name:        path.Base(secret.GetName()),
+		Labels:      secret.GetLabels(),
+		Replication: "automatic",
+	}
+	if secret.GetCreateTime() != nil {
+		summary.Created = secret.GetCreateTime().AsTime().UTC().Format(time.RFC3339)
+	}
+	if managed := secret.GetReplication().GetUserManaged(); managed != nil {
+		summary.Replication = "user-managed"
+		for _, replica := range managed.GetReplicas() {
+			summary.Locations = append(summary.Locations, replica.GetLocation())
+		}
+	}
+	return summary
+}
+
+// buildGCPSecretFilter combines the name prefix, labels and free-form filter into one list filter.
+func buildGCPSecretFilter(input *SecretListInput) string {
+	var terms []string
+	if input.NamePrefix != "" {
+		terms = append(terms, "name:"+strconv.Quote(input.NamePrefix))
+	}
+	keys := make([]string, 0, len(input.Labels))
+	for k := range input.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		terms = append(terms, "labels."+k+"="+strconv.Quote(input.Labels[k]))
+	}
+	if input.Filter != "" {
+		terms = append(terms, "("+input.Filter+")")
+	}
+	return strings.Join(terms, " AND ")
+}
+
+// allowedProjectsFromEnv returns the project IDs listed in GCP_ALLOWED_PROJECTS.
+func allowedProjectsFromEnv() []string {
+	var projects []string
+	for _, p := range strings.Split(os.Getenv("GCP_ALLOWED_PROJECTS"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
+
+// resolveGCPProjectID falls back to GOOGLE_CLOUD_PROJECT when no project was given and checks the
+// project against GCP_ALLOWED_PROJECTS.
+func resolveGCPProjectID(project string) (string, error) {
+	if project == "" {
+		project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	if project == "" {
+		return "", fmt.Errorf("projectId must be provided (either as input or environment variable)")
+	}
+	allowed := allowedProjectsFromEnv()
+	if len(allowed) == 0 {
+		return project, nil
+	}
+	for _, p := range allowed {
+		if p == project {
+			return project, nil
+		}
+	}
+	return "", fmt.Errorf("project %q is not in GCP_ALLOWED_PROJECTS", project)
+}
+
+// gcpSecretVersion converts a Secret Manager version into its number, state and times.
+func gcpSecretVersion(v *secretmanagerpb.SecretVersion) BackendSecretVersion {
+	version := BackendSecretVersion{Version: path.Base(v.GetName()), State: v.GetState().String()}
+	if v.GetCreateTime() != nil {
+		version.Created = v.GetCreateTime().AsTime().UTC().Format(time.RFC3339)
+	}
+	if v.GetDestroyTime() != nil {
+		version.Destroyed = v.GetDestroyTime().AsTime().UTC().Format(time.RFC3339)
+	}
+	return version
+}
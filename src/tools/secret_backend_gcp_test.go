@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSummarizeGCPSecret(t *testing.T) {
+	summary := summarizeGCPSecret(&secretmanagerpb.Secret{
+		Name:       "projects/my-project/secrets/app-env",
+		CreateTime: timestamppb.New(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Labels:     map[string]string{"env": "prod"},
+		Replication: &secretmanagerpb.Replication{Replication: &secretmanagerpb.Replication_UserManaged_{UserManaged: &secretmanagerpb.Replication_UserManaged{
+			Replicas: []*secretmanagerpb.Replication_UserManaged_Replica{{Location: "europe-west1"}, {Location: "europe-west4"}},
+		}}},
+	})
+	assert.Equal(t, BackendSecret{
+		Name:        "app-env",
+		Created:     "2025-01-02T03:04:05Z",
+		Labels:      map[string]string{"env": "prod"},
+		Replication: "user-managed",
+		Locations:   []string{"europe-west1", "europe-west4"},
+	}, summary)
+
+	assert.Equal(t, "automatic", summarizeGCPSecret(&secretmanagerpb.Secret{Name: "projects/p/secrets/x"}).Replication)
+}
+
+func TestBuildGCPSecretFilter(t *testing.T) {
+	assert.Equal(t, `name:"app-" AND labels.env="prod" AND labels.team="web" AND (labels.tier=backend OR labels.tier=api)`, buildGCPSecretFilter(&SecretListInput{
+		NamePrefix: "app-",
+		Labels:     map[string]string{"team": "web", "env": "prod"},
+		Filter:     "labels.tier=backend OR labels.tier=api",
+	}))
+	assert.Equal(t, "", buildGCPSecretFilter(&SecretListInput{}))
+}
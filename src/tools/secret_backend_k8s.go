@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// k8sSecretBackend stores secrets as in-cluster Secrets of one namespace. The keys of a Secret are
+// exchanged as a JSON object, and since Secrets keep no history the only version is the current
+// resourceVersion.
+type k8sSecretBackend struct {
+	secrets   typedcorev1.SecretInterface
+	namespace string
+}
+
+// newK8sSecretBackend uses the server's Kubernetes client for Secrets of the namespace.
+func newK8sSecretBackend(client Client, namespace string) (*k8sSecretBackend, error) {
+	clientset, err := client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	return &k8sSecretBackend{secrets: clientset.CoreV1().Secrets(namespace), namespace: namespace}, nil
+}
+
+// List filters by labels on the API server and uses its continue token for paging.
+func (b *k8sSecretBackend) List(ctx context.Context, input *SecretListInput) ([]BackendSecret, string, error) {
+	list, err := b.secrets.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(input.Labels).String(),
+		Limit:         int64(input.PageSize),
+		Continue:      input.PageToken,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list secrets in namespace %s: %w", b.namespace, err)
+	}
+	secrets := []BackendSecret{}
+	for i := range list.Items {
+		summary := summarizeK8sSecret(&list.Items[i])
+		if secretMatches(summary, input) {
+			secrets = append(secrets, summary)
+		}
+	}
+	return secrets, list.Continue, nil
+}
+
+func (b *k8sSecretBackend) Get(ctx context.Context, secret, version string) ([]byte, string, error) {
+	if version != "latest" {
+		return nil, "", fmt.Errorf("kubernetes Secrets keep no history: only the latest version is available")
+	}
+	s, err := b.secrets.Get(ctx, secret, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get secret %s/%s: %w", b.namespace, secret, err)
+	}
+	data, err := json.Marshal(secretDataToStrings(s.Data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode secret %s/%s: %w", b.namespace, secret, err)
+	}
+	return data, s.ResourceVersion, nil
+}
+
+// Update replaces the keys of the Secret with those of a JSON object of strings.
+func (b *k8sSecretBackend) Update(ctx context.Context, secret string, data []byte) (string, error) {
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return "", fmt.Errorf("kubernetes Secrets hold key/value pairs: the payload must be a JSON object of strings: %w", err)
+	}
+	s, err := b.secrets.Get(ctx, secret, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", b.namespace, secret, err)
+	}
+	s.Data = stringsToSecretData(values)
+	s.StringData = nil
+	updated, err := b.secrets.Update(ctx, s, metav1.UpdateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to update secret %s/%s: %w", b.namespace, secret, err)
+	}
+	return updated.ResourceVersion, nil
+}
+
+// Versions returns the current resourceVersion, the only version a Secret has.
+func (b *k8sSecretBackend) Versions(ctx context.Context, secret string, limit int) ([]BackendSecretVersion, error) {
+	s, err := b.secrets.Get(ctx, secret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", b.namespace, secret, err)
+	}
+	return []BackendSecretVersion{{
+		Version: s.ResourceVersion,
+		State:   "ENABLED",
+		Created: s.CreationTimestamp.UTC().Format(time.RFC3339),
+	}}, nil
+}
+
+func (b *k8sSecretBackend) Close() error {
+	return nil
+}
+
+// summarizeK8sSecret returns the name, labels and create time of a Secret.
+func summarizeK8sSecret(secret *corev1.Secret) BackendSecret {
+	return BackendSecret{
+		Name:    secret.Name,
+		Created: secret.CreationTimestamp.UTC().Format(time.RFC3339),
+		Labels:  secret.Labels,
+	}
+}
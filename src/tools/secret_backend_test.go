@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSecretBackend(t *testing.T) {
+	t.Setenv("SECRET_BACKEND", "")
+	backend, err := resolveSecretBackend("")
+	assert.NoError(t, err)
+	assert.Equal(t, secretBackendGCP, backend)
+
+	for _, name := range []string{"Azure", "aws", "vault", "k8s"} {
+		_, err = resolveSecretBackend(name)
+		assert.NoError(t, err, name)
+	}
+
+	t.Setenv("SECRET_BACKEND", "azure")
+	backend, err = resolveSecretBackend("")
+	assert.NoError(t, err)
+	assert.Equal(t, secretBackendAzure, backend)
+	backend, err = resolveSecretBackend("gcp")
+	assert.NoError(t, err)
+	assert.Equal(t, secretBackendGCP, backend)
+
+	_, err = resolveSecretBackend("keepass")
+	assert.Error(t, err)
+}
+
+func TestParseSecretBackendInput(t *testing.T) {
+	t.Setenv("SECRET_BACKEND", "")
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+
+	input, err := parseSecretBackendInput(map[string]any{})
+	assert.NoError(t, err)
+	assert.Equal(t, SecretBackendInput{Backend: secretBackendGCP, ProjectID: "my-project", Namespace: "default"}, input)
+
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	_, err = parseSecretBackendInput(map[string]any{})
+	assert.Error(t, err)
+	input, err = parseSecretBackendInput(map[string]any{"backend": "k8s", "namespace": "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, SecretBackendInput{Backend: secretBackendK8s, Namespace: "payments"}, input)
+	_, err = parseSecretBackendInput(map[string]any{"backend": "k8s", "namespace": "Not_Valid"})
+	assert.Error(t, err)
+}
+
+func TestValidateSecretVersion(t *testing.T) {
+	assert.NoError(t, validateSecretVersion(secretBackendGCP, "12"))
+	assert.NoError(t, validateSecretVersion(secretBackendVault, "latest"))
+	assert.NoError(t, validateSecretVersion(secretBackendAzure, "0123456789abcdef0123456789abcdef"))
+	assert.NoError(t, validateSecretVersion(secretBackendAWS, "a1b2c3d4-5678-90ab-cdef-EXAMPLE11111"))
+	assert.NoError(t, validateSecretVersion(secretBackendK8s, "latest"))
+
+	assert.Error(t, validateSecretVersion(secretBackendGCP, "previous"))
+	assert.Error(t, validateSecretVersion(secretBackendAzure, "3"))
+	assert.Error(t, validateSecretVersion(secretBackendK8s, "1234"))
+}
+
+func TestSecretMatches(t *testing.T) {
+	summary := BackendSecret{Name: "app-env", Labels: map[string]string{"env": "prod"}}
+	assert.True(t, secretMatches(summary, &SecretListInput{NamePrefix: "app-", Labels: map[string]string{"env": "prod"}}))
+	assert.False(t, secretMatches(summary, &SecretListInput{NamePrefix: "db-"}))
+	assert.False(t, secretMatches(summary, &SecretListInput{Labels: map[string]string{"env": "staging"}}))
+}
@@ -0,0 +1,289 @@
+// Environment variables used by the vault secret backend:
+// Required:
+//   VAULT_ADDR                     - Address of the Vault server, e.g. https://vault.example.com:8200
+//   VAULT_TOKEN                    - Token whose policy allows read, list and update on the KV mount
+// Optional:
+//   VAULT_NAMESPACE                - Vault Enterprise namespace
+//   VAULT_KV_MOUNT                 - Mount path of the KV version 2 engine (default: secret)
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// vaultSecretBackend stores secrets in a HashiCorp Vault KV version 2 engine through its HTTP API.
+// KV entries are key/value maps, so payloads are exchanged as JSON objects.
+type vaultSecretBackend struct {
+	addr      string
+	token     string
+	namespace string
+	mount     string
+}
+
+// vaultVersionMetadata is the metadata Vault keeps for each version of a KV entry.
+type vaultVersionMetadata struct {
+	CreatedTime  string `json:"created_time"`
+	DeletionTime string `json:"deletion_time"`
+	Destroyed    bool   `json:"destroyed"`
+	Version      int    `json:"version"`
+}
+
+// newVaultSecretBackend reads the Vault address, token and KV mount from the environment.
+func newVaultSecretBackend() (*vaultSecretBackend, error) {
+	b := &vaultSecretBackend{
+		addr:      strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/"),
+		token:     os.Getenv("VAULT_TOKEN"),
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		mount:     strings.Trim(os.Getenv("VAULT_KV_MOUNT"), "/"),
+	}
+	if b.addr == "" || b.token == "" {
+		return nil, fmt.Errorf("vault not configured: set VAULT_ADDR and VAULT_TOKEN")
+	}
+	if b.mount == "" {
+		b.mount = "secret"
+	}
+	return b, nil
+}
+
+// List reads the keys at the top of the mount and the metadata of each key in turn until the page
+// is full. The page token is the number of matching secrets already returned.
+func (b *vaultSecretBackend) List(ctx context.Context, input *SecretListInput) ([]BackendSecret, string, error) {
+	offset := 0
+	if input.PageToken != "" {
+		n, err := strconv.Atoi(input.PageToken)
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("invalid pageToken %q", input.PageToken)
+		}
+		offset = n
+	}
+
+	var listed struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	if err := b.do(ctx, http.MethodGet, "metadata/?list=true", nil, &listed); err != nil {
+		if isVaultNotFound(err) {
+			return []BackendSecret{}, "", nil
+		}
+		return nil, "", vaultSecretError(err, "list secrets", "list")
+	}
+	keys := listed.Data.Keys
+	sort.Strings(keys)
+
+	secrets := []BackendSecret{}
+	matched := 0
+	for _, key := range keys {
+		// Keys ending in a slash are folders, not secrets.
+		if strings.HasSuffix(key, "/") || !strings.HasPrefix(key, input.NamePrefix) {
+			continue
+		}
+		summary, _, err := b.metadata(ctx, key)
+		if err != nil {
+			return nil, "", vaultSecretError(err, "read metadata of "+key, "read")
+		}
+		if !secretMatches(summary, input) {
+			continue
+		}
+		if matched++; matched <= offset {
+			continue
+		}
+		if len(secrets) == input.PageSize {
+			return secrets, strconv.Itoa(offset + len(secrets)), nil
+		}
+		secrets = append(secrets, summary)
+	}
+	return secrets, "", nil
+}
+
+func (b *vaultSecretBackend) Get(ctx context.Context, secret, version string) ([]byte, string, error) {
+	query := ""
+	if version != "latest" {
+		query = "?version=" + version
+	}
+	var result struct {
+		Data struct {
+			Data     map[string]any       `json:"data"`
+			Metadata vaultVersionMetadata `json:"metadata"`
+		} `json:"data"`
+	}
+	if err := b.do(ctx, http.MethodGet, "data/"+secretPathEscape(secret)+query, nil, &result); err != nil {
+		return nil, "", vaultSecretError(err, "read secret "+secret, "read")
+	}
+	if result.Data.Data == nil {
+		return nil, "", fmt.Errorf("version %s of secret %s is deleted or destroyed", version, secret)
+	}
+	data, err := json.Marshal(result.Data.Data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode secret %s: %w", secret, err)
+	}
+	return data, strconv.Itoa(result.Data.Metadata.Version), nil
+}
+
+func (b *vaultSecretBackend) Update(ctx context.Context, secret string, data []byte) (string, error) {
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return "", fmt.Errorf("vault KV entries are key/value maps: the payload must be a JSON object: %w", err)
+	}
+	var result struct {
+		Data vaultVersionMetadata `json:"data"`
+	}
+	if err := b.do(ctx, http.MethodPost, "data/"+secretPathEscape(secret), map[string]any{"data": values}, &result); err != nil {
+		return "", vaultSecretError(err, "write secret "+secret, "update")
+	}
+	return strconv.Itoa(result.Data.Version), nil
+}
+
+func (b *vaultSecretBackend) Versions(ctx context.Context, secret string, limit int) ([]BackendSecretVersion, error) {
+	_, versions, err := b.metadata(ctx, secret)
+	if err != nil {
+		return nil, vaultSecretError(err, "read metadata of "+secret, "read")
+	}
+	sortSecretVersions(versions)
+	if len(versions) > limit {
+		versions = versions[:limit]
+	}
+	return versions, nil
+}
+
+func (b *vaultSecretBackend) Close() error {
+	return nil
+}
+
+// metadata reads the custom metadata, create time and versions of a KV entry.
+func (b *vaultSecretBackend) metadata(ctx context.Context, secret string) (BackendSecret, []BackendSecretVersion, error) {
+	var result struct {
+		Data struct {
+			CreatedTime    string                          `json:"created_time"`
+			CustomMetadata map[string]string               `json:"custom_metadata"`
+			Versions       map[string]vaultVersionMetadata `json:"versions"`
+		} `json:"data"`
+	}
+	if err := b.do(ctx, http.MethodGet, "metadata/"+secretPathEscape(secret), nil, &result); err != nil {
+		return BackendSecret{}, nil, err
+	}
+	summary := BackendSecret{Name: secret, Created: vaultTime(result.Data.CreatedTime)}
+	if len(result.Data.CustomMetadata) > 0 {
+		summary.Labels = result.Data.CustomMetadata
+	}
+	versions := make([]BackendSecretVersion, 0, len(result.Data.Versions))
+	for number, meta := range result.Data.Versions {
+		versions = append(versions, vaultSecretVersion(number, meta))
+	}
+	return summary, versions, nil
+}
+
+// do sends a request to the KV mount and decodes the JSON response into out.
+func (b *vaultSecretBackend) do(ctx context.Context, method, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, b.addr+"/v1/"+b.mount+"/"+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", b.token)
+	if b.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", b.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach vault: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return &vaultStatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
+	}
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// vaultStatusError is a non-2xx response from Vault.
+type vaultStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *vaultStatusError) Error() string {
+	return fmt.Sprintf("vault returned %d: %s", e.StatusCode, e.Body)
+}
+
+// isVaultNotFound reports whether Vault answered 404.
+func isVaultNotFound(err error) bool {
+	statusErr, ok := err.(*vaultStatusError)
+	return ok && statusErr.StatusCode == http.StatusNotFound
+}
+
+// vaultSecretError turns a forbidden response into a hint about the policy capability to grant.
+func vaultSecretError(err error, action, capability string) error {
+	if statusErr, ok := err.(*vaultStatusError); ok {
+		switch statusErr.StatusCode {
+		case http.StatusForbidden:
+			return fmt.Errorf("permission denied to %s: the token's policy needs the %q capability on the KV paths (%w)", action, capability, err)
+		case http.StatusNotFound:
+			return fmt.Errorf("failed to %s: not found (%w)", action, err)
+		}
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+// vaultSecretVersion converts KV version metadata into a version, state and times. Deleted versions
+// can still be undeleted; destroyed ones are gone.
+func vaultSecretVersion(number string, meta vaultVersionMetadata) BackendSecretVersion {
+	version := BackendSecretVersion{Version: number, State: "ENABLED", Created: vaultTime(meta.CreatedTime)}
+	switch {
+	case meta.Destroyed:
+		version.State = "DESTROYED"
+	case meta.DeletionTime != "":
+		version.State = "DELETED"
+	}
+	return version
+}
+
+// vaultTime normalizes a Vault timestamp to RFC 3339 in UTC.
+func vaultTime(value string) string {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return value
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// secretPathEscape escapes each segment of a secret path so nested KV paths keep their slashes.
+func secretPathEscape(secret string) string {
+	segments := strings.Split(secret, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
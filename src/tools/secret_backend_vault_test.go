@@ -0,0 +1,24 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaultSecretVersion(t *testing.T) {
+	assert.Equal(t, BackendSecretVersion{Version: "3", State: "ENABLED", Created: "2025-01-02T03:04:05Z"},
+		vaultSecretVersion("3", vaultVersionMetadata{CreatedTime: "2025-01-02T03:04:05.123456Z"}))
+	assert.Equal(t, "DELETED", vaultSecretVersion("2", vaultVersionMetadata{DeletionTime: "2025-01-03T00:00:00Z"}).State)
+	assert.Equal(t, "DESTROYED", vaultSecretVersion("1", vaultVersionMetadata{Destroyed: true}).State)
+}
+
+func TestSecretPathEscape(t *testing.T) {
+	assert.Equal(t, "team/app%20env", secretPathEscape("team/app env"))
+}
+
+func TestVaultSecretError(t *testing.T) {
+	err := vaultSecretError(&vaultStatusError{StatusCode: 403, Body: `{"errors":["permission denied"]}`}, "read secret app", "read")
+	assert.Contains(t, err.Error(), `"read" capability`)
+	assert.True(t, isVaultNotFound(&vaultStatusError{StatusCode: 404}))
+}
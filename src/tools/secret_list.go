@@ -0,0 +1,192 @@
+// Environment variables used by this tool:
+// Required:
+//   Credentials of the selected backend (see secret_backend_<name>.go), e.g. Application Default Credentials for gcp
+// Optional:
+//   SECRET_BACKEND                 - gcp, azure, aws, vault or k8s (used if backend is not provided in input)
+//   SECRET_ALLOWED_NAMES           - Comma-separated secret names or glob patterns whose values may be included (default: any)
+//   GCP_ALLOWED_SECRETS            - Former name of SECRET_ALLOWED_NAMES, read when it is not set
+//   SECRET_ALLOW_VALUES            - Set to "true" to allow includeValues to return payloads (default: false)
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Page sizes applied to secret_list results.
+const (
+	defaultSecretPageSize = 100
+	maxSecretPageSize     = 500
+)
+
+// SecretListInput represents the input for listing secrets.
+type SecretListInput struct {
+	SecretBackendInput
+	Filter        string            `json:"filter,omitempty"`
+	NamePrefix    string            `json:"namePrefix,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	IncludeValues bool              `json:"includeValues,omitempty"`
+	PageSize      int               `json:"pageSize,omitempty"`
+	PageToken     string            `json:"pageToken,omitempty"`
+}
+
+// BackendSecret describes a secret in any backend without its payload.
+type BackendSecret struct {
+	Name        string            `json:"name"`
+	Created     string            `json:"created,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Replication string            `json:"replication,omitempty"`
+	Locations   []string          `json:"locations,omitempty"`
+	Value       string            `json:"value,omitempty"`
+	ValueError  string            `json:"valueError,omitempty"`
+}
+
+type SecretListTool struct {
+	client Client
+}
+
+func NewSecretListTool(client Client) *SecretListTool {
+	return &SecretListTool{client: client}
+}
+
+func (t *SecretListTool) Tool() mcp.Tool {
+	return mcp.NewTool("secret_list",
+		mcp.WithDescription("List secrets in Google Cloud Secret Manager, Azure Key Vault, AWS Secrets Manager, Vault KV or a Kubernetes namespace with labels (tags), create time and replication. Payloads are only returned with includeValues when the server allows it."),
+		withSecretBackend(),
+		mcp.WithString("filter", mcp.Description("Secret Manager list filter expression, e.g. 'labels.env=prod OR labels.env=staging' (gcp only)")),
+		mcp.WithString("namePrefix", mcp.Description("Only list secrets whose name starts with this prefix")),
+		mcp.WithObject("labels",
+			mcp.Description("Only list secrets with all of these labels (tags on azure and aws, custom metadata on vault), e.g. {\"env\": \"prod\"}"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("includeValues", mcp.Description("Include the latest payload of each listed secret; only honored when the server sets SECRET_ALLOW_VALUES=true, and limited to SECRET_ALLOWED_NAMES when set (default: false)")),
+		mcp.WithNumber("pageSize", mcp.Description("Maximum number of secrets per page (default: 100, max: 500)")),
+		mcp.WithString("pageToken", mcp.Description("nextPageToken from a previous call to fetch the following page")),
+	)
+}
+
+func (t *SecretListTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateSecretListParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+	if input.IncludeValues && !secretValuesAllowed() {
+		return nil, errValuesDisabled()
+	}
+
+	backend, err := newSecretBackend(ctx, t.client, input.SecretBackendInput)
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Close()
+
+	secrets, nextPageToken, err := backend.List(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := allowedSecretsFromEnv()
+	for i := range secrets {
+		// Payloads are only accessed when asked for, and never for secrets outside the allowlist.
+		if input.IncludeValues && (len(allowed) == 0 || secretNameAllowed(secrets[i].Name, allowed)) {
+			data, _, err := backend.Get(ctx, secrets[i].Name, "latest")
+			if err != nil {
+				secrets[i].ValueError = err.Error()
+			} else {
+				secrets[i].Value = string(data)
+			}
+		}
+	}
+
+	output := map[string]any{
+		"backend": input.Backend,
+		"secrets": secrets,
+		"count":   len(secrets),
+	}
+	if input.Backend == secretBackendGCP {
+		output["projectId"] = input.ProjectID
+		output["filter"] = buildGCPSecretFilter(input)
+	}
+	if nextPageToken != "" {
+		output["nextPageToken"] = nextPageToken
+	}
+	out, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+func parseAndValidateSecretListParams(args map[string]any) (*SecretListInput, error) {
+	input := &SecretListInput{PageSize: defaultSecretPageSize}
+	var err error
+	if input.SecretBackendInput, err = parseSecretBackendInput(args); err != nil {
+		return nil, err
+	}
+
+	input.Filter, _ = args["filter"].(string)
+	input.NamePrefix, _ = args["namePrefix"].(string)
+	if input.Labels, err = stringMapArg(args, "labels"); err != nil {
+		return nil, err
+	}
+	if input.Backend == secretBackendGCP {
+		for k := range input.Labels {
+			if !gcpLabelKeyPattern.MatchString(k) {
+				return nil, fmt.Errorf("invalid label name %q", k)
+			}
+		}
+	} else if input.Filter != "" {
+		return nil, fmt.Errorf("filter is only supported by the gcp backend: use namePrefix and labels instead")
+	}
+	input.IncludeValues, _ = args["includeValues"].(bool)
+	input.PageToken, _ = args["pageToken"].(string)
+	if v, ok := args["pageSize"].(float64); ok && v > 0 {
+		input.PageSize = int(v)
+	}
+	if input.PageSize > maxSecretPageSize {
+		input.PageSize = maxSecretPageSize
+	}
+	return input, nil
+}
+
+// secretValuesAllowed reports whether the server permits secret_list to return payloads. It is
+// separate from K8S_SECRET_ALLOW_REVEAL, which reveals single keys of in-cluster Secrets, since
+// includeValues dumps every listed secret of a backend. The always redaction policy overrides it.
+func secretValuesAllowed() bool {
+	if policy, err := redact.PolicyFromEnv(); err != nil || policy == redact.Always {
+		return false
+	}
+	allowed, _ := strconv.ParseBool(os.Getenv("SECRET_ALLOW_VALUES"))
+	return allowed
+}
+
+// errValuesDisabled explains why a request for secret payloads was refused.
+func errValuesDisabled() error {
+	if policy, _ := redact.PolicyFromEnv(); policy == redact.Always {
+		return errors.New("listing secret values is disabled on this server: REDACT_POLICY=always")
+	}
+	return errors.New("listing secret values is disabled on this server: set SECRET_ALLOW_VALUES=true to allow it")
+}
+
+// secretMatches reports whether a secret has the name prefix and all labels of the input, for
+// backends that cannot filter on the server.
+func secretMatches(summary BackendSecret, input *SecretListInput) bool {
+	if !strings.HasPrefix(summary.Name, input.NamePrefix) {
+		return false
+	}
+	for k, v := range input.Labels {
+		if summary.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndValidateSecretListParams(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+
+	input, err := parseAndValidateSecretListParams(map[string]any{"filter": "labels.env=prod", "pageSize": float64(1000), "pageToken": "abc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-project", input.ProjectID)
+	assert.Equal(t, maxSecretPageSize, input.PageSize)
+	assert.Equal(t, "abc", input.PageToken)
+	assert.False(t, input.IncludeValues)
+
+	_, err = parseAndValidateSecretListParams(map[string]any{"labels": map[string]any{"Env": "prod"}})
+	assert.Error(t, err)
+
+	t.Setenv("GCP_ALLOWED_PROJECTS", "shared-secrets, my-project")
+	_, err = parseAndValidateSecretListParams(map[string]any{})
+	assert.NoError(t, err)
+	_, err = parseAndValidateSecretListParams(map[string]any{"projectId": "billing-prod"})
+	assert.Error(t, err)
+
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	_, err = parseAndValidateSecretListParams(map[string]any{})
+	assert.Error(t, err)
+
+	input, err = parseAndValidateSecretListParams(map[string]any{"backend": "azure", "labels": map[string]any{"Env": "prod"}})
+	assert.NoError(t, err)
+	assert.Equal(t, secretBackendAzure, input.Backend)
+	_, err = parseAndValidateSecretListParams(map[string]any{"backend": "azure", "filter": "labels.env=prod"})
+	assert.Error(t, err)
+}
+
+func TestSecretListValuesAllowed(t *testing.T) {
+	t.Setenv("K8S_SECRET_ALLOW_REVEAL", "true")
+	t.Setenv("SECRET_ALLOW_VALUES", "")
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"backend": "k8s", "namespace": "default", "includeValues": true}
+	_, err := NewSecretListTool(FakeKubernetesClient{}).Handler(context.Background(), req)
+	assert.EqualError(t, err, "listing secret values is disabled on this server: set SECRET_ALLOW_VALUES=true to allow it", "K8S_SECRET_ALLOW_REVEAL must not enable includeValues")
+
+	t.Setenv("SECRET_ALLOW_VALUES", "true")
+	assert.True(t, secretValuesAllowed())
+
+	t.Setenv("REDACT_POLICY", "always")
+	assert.False(t, secretValuesAllowed())
+	assert.EqualError(t, errValuesDisabled(), "listing secret values is disabled on this server: REDACT_POLICY=always")
+}
@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Secret payload formats understood by change_env.
+const (
+	secretFormatJSON   = "json"
+	secretFormatDotenv = "dotenv"
+	secretFormatRaw    = "raw"
+)
+
+// dotenvLinePattern matches KEY=VALUE lines, optionally prefixed with "export".
+var dotenvLinePattern = regexp.MustCompile(`^\s*(export\s+)?([A-Za-z_][A-Za-z0-9_.-]*)\s*=(.*)$`)
+
+// detectSecretFormat guesses the payload format: a JSON object, KEY=VALUE lines with optional
+// comments, or anything else as an opaque value.
+func detectSecretFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) && json.Valid(trimmed) {
+		return secretFormatJSON
+	}
+	if len(trimmed) == 0 {
+		return secretFormatRaw
+	}
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !dotenvLinePattern.MatchString(line) {
+			return secretFormatRaw
+		}
+	}
+	return secretFormatDotenv
+}
+
+// updateSecretPayload applies the changes of the input to a payload in the given format and returns
+// the new payload.
+func updateSecretPayload(data []byte, format string, input *ChangeEnvInput) ([]byte, *envChangeSummary, error) {
+	switch format {
+	case secretFormatJSON:
+		var secretData map[string]any
+		if err := json.Unmarshal(data, &secretData); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse secret JSON: %w", err)
+		}
+		summary, err := applyEnvChanges(secretData, input.Changes, input.DeleteKeys, input.CreateIfMissing)
+		if err != nil {
+			return nil, nil, err
+		}
+		updated, err := json.MarshalIndent(secretData, "", "  ")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal updated secret: %w", err)
+		}
+		return updated, summary, nil
+	case secretFormatDotenv:
+		return updateDotenv(data, input)
+	case secretFormatRaw:
+		if len(input.Changes) > 0 || len(input.DeleteKeys) > 0 {
+			return nil, nil, fmt.Errorf("secret payload is not JSON or dotenv: set format to raw and pass only newValue to replace it")
+		}
+		summary := &envChangeSummary{Updated: []string{"(payload)"}}
+		if string(data) == input.NewValue {
+			summary = &envChangeSummary{Unchanged: []string{"(payload)"}}
+		}
+		return []byte(input.NewValue), summary, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported secret format %q: use json, dotenv or raw", format)
+}
+
+// updateDotenv edits KEY=VALUE lines in place so comments, blank lines and key order survive;
+// created keys are appended at the end.
+func updateDotenv(data []byte, input *ChangeEnvInput) ([]byte, *envChangeSummary, error) {
+	lines := strings.Split(string(data), "\n")
+	values := map[string]any{}
+	for _, line := range lines {
+		if m := dotenvLinePattern.FindStringSubmatch(line); m != nil {
+			values[m[2]] = dotenvValue(m[3])
+		}
+	}
+	summary, err := applyEnvChanges(values, input.Changes, input.DeleteKeys, input.CreateIfMissing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deleted := map[string]bool{}
+	for _, k := range input.DeleteKeys {
+		deleted[k] = true
+	}
+	out := make([]string, 0, len(lines)+len(summary.Created))
+	for _, line := range lines {
+		m := dotenvLinePattern.FindStringSubmatch(line)
+		switch {
+		case m == nil:
+			out = append(out, line)
+		case deleted[m[2]]:
+		default:
+			if v, ok := input.Changes[m[2]]; ok {
+				line = m[1] + m[2] + "=" + formatDotenvValue(v)
+			}
+			out = append(out, line)
+		}
+	}
+	// Keep the trailing newline, if any, after the appended keys.
+	trailing := len(out) > 0 && out[len(out)-1] == ""
+	if trailing {
+		out = out[:len(out)-1]
+	}
+	for _, k := range summary.Created {
+		out = append(out, k+"="+formatDotenvValue(input.Changes[k]))
+	}
+	if trailing {
+		out = append(out, "")
+	}
+	return []byte(strings.Join(out, "\n")), summary, nil
+}
+
+// dotenvValue returns the value of a dotenv assignment without quotes or a trailing comment.
+func dotenvValue(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if len(raw) >= 2 && raw[0] == '"' && raw[len(raw)-1] == '"' {
+		if v, err := strconv.Unquote(raw); err == nil {
+			return v
+		}
+	}
+	if len(raw) >= 2 && raw[0] == '\'' && raw[len(raw)-1] == '\'' {
+		return raw[1 : len(raw)-1]
+	}
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = strings.TrimSpace(raw[:i])
+	}
+	return raw
+}
+
+// formatDotenvValue quotes values that would otherwise be cut at whitespace or a comment.
+func formatDotenvValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\n\"'#\\$") {
+		return strconv.Quote(v)
+	}
+	return v
+}
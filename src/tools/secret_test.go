@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func fakeSecretClientset() *fake.Clientset {
+	return fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"PASSWORD": []byte("s3cret"), "USER": []byte("app")},
+	})
+}
+
+func TestK8sSecretMasksValues(t *testing.T) {
+	tool := NewK8sSecretTool(nil)
+	ctx := context.Background()
+	clientset := fakeSecretClientset()
+
+	result, err := tool.run(ctx, clientset, &K8sSecretInput{Namespace: "shop", Action: "list"})
+	require.NoError(t, err)
+	assert.Equal(t, []SecretSummary{{Name: "db", Namespace: "shop", Type: "Opaque", Keys: map[string]int{"PASSWORD": 6, "USER": 3}}}, result)
+
+	result, err = tool.run(ctx, clientset, &K8sSecretInput{Namespace: "shop", Name: "db", Action: "get", Key: "PASSWORD"})
+	require.NoError(t, err)
+	assert.Equal(t, "(redacted, 6 bytes)", result.(map[string]any)["value"])
+
+	result, err = tool.run(ctx, clientset, &K8sSecretInput{Namespace: "shop", Name: "db", Action: "set", Data: map[string]string{"PASSWORD": "n3w"}})
+	require.NoError(t, err)
+	assert.Equal(t, []KeyChange{{Key: "PASSWORD", Change: "updated", OldValue: "(redacted)", NewValue: "(redacted)"}}, result.(map[string]any)["changes"])
+	secret, err := clientset.CoreV1().Secrets("shop").Get(ctx, "db", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "n3w", string(secret.Data["PASSWORD"]))
+}
+
+func TestK8sSecretReveal(t *testing.T) {
+	tool := NewK8sSecretTool(nil)
+	ctx := context.Background()
+	input := &K8sSecretInput{Namespace: "shop", Name: "db", Action: "get", Key: "PASSWORD", Reveal: true}
+
+	t.Setenv("REDACT_POLICY", "")
+	t.Setenv("K8S_SECRET_ALLOW_REVEAL", "")
+	_, err := tool.run(ctx, fakeSecretClientset(), input)
+	assert.EqualError(t, err, "revealing secret values is disabled on this server: set K8S_SECRET_ALLOW_REVEAL=true to allow it")
+
+	t.Setenv("K8S_SECRET_ALLOW_REVEAL", "true")
+	result, err := tool.run(ctx, fakeSecretClientset(), input)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", result.(map[string]any)["value"])
+
+	t.Setenv("REDACT_POLICY", "always")
+	_, err = tool.run(ctx, fakeSecretClientset(), input)
+	assert.EqualError(t, err, "revealing secret values is disabled on this server: REDACT_POLICY=always")
+}
+
+func TestParseAndValidateK8sSecretParams(t *testing.T) {
+	input, err := parseAndValidateK8sSecretParams(map[string]any{"action": "GET", "name": "db", "key": "PASSWORD", "reveal": true})
+	require.NoError(t, err)
+	assert.Equal(t, &K8sSecretInput{Namespace: "default", Name: "db", Action: "get", Key: "PASSWORD", Reveal: true}, input)
+
+	_, err = parseAndValidateK8sSecretParams(map[string]any{"action": "get", "name": "db", "reveal": true})
+	assert.EqualError(t, err, "reveal requires action get and a key")
+	_, err = parseAndValidateK8sSecretParams(map[string]any{"action": "set", "name": "db"})
+	assert.EqualError(t, err, "data must be provided for action set")
+	_, err = parseAndValidateK8sSecretParams(map[string]any{"action": "rotate"})
+	assert.Error(t, err)
+}
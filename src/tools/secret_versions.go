@@ -0,0 +1,304 @@
+// Environment variables used by these tools:
+// Required:
+//   Credentials of the selected backend (see secret_backend_<name>.go), e.g. Application Default Credentials for gcp
+// Optional:
+//   SECRET_BACKEND                 - gcp, azure, aws, vault or k8s (used if backend is not provided in input)
+//   SECRET_NAME                    - Secret name (used if not provided in input)
+//   SECRET_ALLOWED_NAMES           - Comma-separated secret names or glob patterns these tools may access (default: any)
+//   GCP_SECRET_NAME                - Former name of SECRET_NAME, read when it is not set
+//   GCP_ALLOWED_SECRETS            - Former name of SECRET_ALLOWED_NAMES, read when it is not set
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultSecretVersionsLimit caps how many versions secret_versions lists by default.
+const defaultSecretVersionsLimit = 20
+
+// SecretVersionsInput represents the input shared by the secret version tools.
+type SecretVersionsInput struct {
+	SecretBackendInput
+	SecretName  string `json:"secretName"`
+	Version     string `json:"version,omitempty"`
+	FromVersion string `json:"fromVersion,omitempty"`
+	ToVersion   string `json:"toVersion,omitempty"`
+	Reveal      bool   `json:"reveal,omitempty"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// BackendSecretVersion describes one version of a secret in any backend.
+type BackendSecretVersion struct {
+	Version   string `json:"version"`
+	State     string `json:"state"`
+	Created   string `json:"created,omitempty"`
+	Destroyed string `json:"destroyed,omitempty"`
+}
+
+// SecretVersionsTool lists the versions of a secret.
+type SecretVersionsTool struct {
+	client Client
+}
+
+// NewSecretVersionsTool creates a new SecretVersionsTool.
+func NewSecretVersionsTool(client Client) *SecretVersionsTool {
+	return &SecretVersionsTool{client: client}
+}
+
+// Tool returns the MCP tool definition for secret_versions.
+func (g *SecretVersionsTool) Tool() mcp.Tool {
+	return mcp.NewTool("secret_versions",
+		mcp.WithDescription("List the versions of a secret in Google Cloud Secret Manager, Azure Key Vault, AWS Secrets Manager, Vault KV or Kubernetes, newest first, with create time and state"),
+		withSecretBackend(),
+		mcp.WithString("secretName", mcp.Description("Name of the secret (optional, will use SECRET_NAME env if not set)")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of versions to return (default: 20)")),
+	)
+}
+
+// Handler lists the secret versions.
+func (g *SecretVersionsTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateSecretVersionsParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+	backend, err := newSecretBackend(ctx, g.client, input.SecretBackendInput)
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Close()
+
+	versions, err := backend.Versions(ctx, input.SecretName, input.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := json.Marshal(map[string]any{
+		"backend":    input.Backend,
+		"secretName": input.SecretName,
+		"versions":   versions,
+		"count":      len(versions),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// SecretDiffTool compares the keys of two versions of a secret.
+type SecretDiffTool struct {
+	client Client
+}
+
+// NewSecretDiffTool creates a new SecretDiffTool.
+func NewSecretDiffTool(client Client) *SecretDiffTool {
+	return &SecretDiffTool{client: client}
+}
+
+// Tool returns the MCP tool definition for secret_diff.
+func (g *SecretDiffTool) Tool() mcp.Tool {
+	return mcp.NewTool("secret_diff",
+		mcp.WithDescription("Show which keys were added, removed or changed between two versions of a JSON or dotenv secret in any secret backend; values are redacted unless reveal is set and the server allows it"),
+		withSecretBackend(),
+		mcp.WithString("secretName", mcp.Description("Name of the secret (optional, will use SECRET_NAME env if not set)")),
+		mcp.WithString("fromVersion", mcp.Required(), mcp.Description("Older version to compare from (a number for gcp and vault, a version ID for azure and aws)")),
+		mcp.WithString("toVersion", mcp.Description("Newer version to compare to (default: latest)")),
+		mcp.WithBoolean("reveal", mcp.Description("Include the old and new values; only honored when the server sets K8S_SECRET_ALLOW_REVEAL=true (default: false)")),
+	)
+}
+
+// Handler fetches both versions and diffs their keys.
+func (g *SecretDiffTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateSecretVersionsParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+	if input.FromVersion == "" {
+		return nil, errors.New("fromVersion is required")
+	}
+	if input.Reveal && !secretRevealAllowed() {
+		return nil, errRevealDisabled()
+	}
+	backend, err := newSecretBackend(ctx, g.client, input.SecretBackendInput)
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Close()
+
+	changes, fromVersion, toVersion, err := diffSecretVersions(ctx, backend, input.SecretName, input.FromVersion, input.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !input.Reveal {
+		redactKeyChanges(changes)
+	}
+
+	out, err := json.Marshal(map[string]any{
+		"backend":     input.Backend,
+		"secretName":  input.SecretName,
+		"fromVersion": fromVersion,
+		"toVersion":   toVersion,
+		"changes":     changes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// SecretRollbackTool restores an older secret payload as a new latest version.
+type SecretRollbackTool struct {
+	client Client
+}
+
+// NewSecretRollbackTool creates a new SecretRollbackTool.
+func NewSecretRollbackTool(client Client) *SecretRollbackTool {
+	return &SecretRollbackTool{client: client}
+}
+
+// Tool returns the MCP tool definition for secret_rollback.
+func (g *SecretRollbackTool) Tool() mcp.Tool {
+	return mcp.NewTool("secret_rollback",
+		mcp.WithDescription("Roll a secret back by adding the payload of an older version as the new latest version; history is kept. Works with every backend that keeps versions (gcp, azure, aws, vault)"),
+		withSecretBackend(),
+		mcp.WithString("secretName", mcp.Description("Name of the secret (optional, will use SECRET_NAME env if not set)")),
+		mcp.WithString("version", mcp.Required(), mcp.Description("Version whose payload becomes the latest version (a number for gcp and vault, a version ID for azure and aws)")),
+	)
+}
+
+// Handler copies the payload of the requested version into a new version.
+func (g *SecretRollbackTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateSecretVersionsParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input: %w", err)
+	}
+	if input.Version == "" || input.Version == "latest" {
+		return nil, errors.New("version must be an older version")
+	}
+	backend, err := newSecretBackend(ctx, g.client, input.SecretBackendInput)
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Close()
+
+	version, err := rollbackSecret(ctx, backend, input.SecretName, input.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := json.Marshal(map[string]any{
+		"backend":      input.Backend,
+		"secretName":   input.SecretName,
+		"restoredFrom": input.Version,
+		"version":      version,
+		"status":       "Secret rolled back and new version created",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// secretPayloadValues decodes a JSON or dotenv payload into key/value pairs; other payloads are
+// compared as a whole under the "(payload)" key.
+func secretPayloadValues(data []byte) (map[string]string, error) {
+	values := map[string]string{}
+	switch detectSecretFormat(data) {
+	case secretFormatJSON:
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to parse secret JSON: %w", err)
+		}
+		for k, v := range decoded {
+			if s, ok := v.(string); ok {
+				values[k] = s
+				continue
+			}
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode value of key '%s': %w", k, err)
+			}
+			values[k] = string(b)
+		}
+	case secretFormatDotenv:
+		for _, line := range strings.Split(string(data), "\n") {
+			if m := dotenvLinePattern.FindStringSubmatch(line); m != nil {
+				values[m[2]] = dotenvValue(m[3])
+			}
+		}
+	default:
+		values["(payload)"] = string(data)
+	}
+	return values, nil
+}
+
+// diffSecretValues returns the key-level changes from one version to another, ordered by key.
+func diffSecretValues(from, to map[string]string) []KeyChange {
+	changes := []KeyChange{}
+	for k, newValue := range to {
+		oldValue, exists := from[k]
+		switch {
+		case !exists:
+			changes = append(changes, KeyChange{Key: k, Change: "added", NewValue: truncateValue(newValue)})
+		case oldValue != newValue:
+			changes = append(changes, KeyChange{Key: k, Change: "updated", OldValue: truncateValue(oldValue), NewValue: truncateValue(newValue)})
+		}
+	}
+	for k, oldValue := range from {
+		if _, exists := to[k]; !exists {
+			changes = append(changes, KeyChange{Key: k, Change: "removed", OldValue: truncateValue(oldValue)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// sortSecretVersions orders versions newest first by their numeric version, or by create time when
+// versions are not numbered (Azure Key Vault, AWS Secrets Manager).
+func sortSecretVersions(versions []BackendSecretVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, errA := strconv.Atoi(versions[i].Version)
+		b, errB := strconv.Atoi(versions[j].Version)
+		if errA != nil || errB != nil {
+			return versions[i].Created > versions[j].Created
+		}
+		return a > b
+	})
+}
+
+// parseAndValidateSecretVersionsParams parses the input shared by the secret version tools.
+func parseAndValidateSecretVersionsParams(args map[string]any) (*SecretVersionsInput, error) {
+	input := &SecretVersionsInput{ToVersion: "latest", Limit: defaultSecretVersionsLimit}
+	var err error
+	if input.SecretBackendInput, err = parseSecretBackendInput(args); err != nil {
+		return nil, err
+	}
+	secretName, _ := args["secretName"].(string)
+	if input.SecretName, err = resolveSecretName(secretName); err != nil {
+		return nil, err
+	}
+
+	for field, target := range map[string]*string{"version": &input.Version, "fromVersion": &input.FromVersion, "toVersion": &input.ToVersion} {
+		v, _ := args[field].(string)
+		if v == "" {
+			continue
+		}
+		if err := validateSecretVersion(input.Backend, v); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", field, err)
+		}
+		*target = v
+	}
+	input.Reveal, _ = args["reveal"].(bool)
+	if v, ok := args["limit"].(float64); ok && v > 0 {
+		input.Limit = int(v)
+	}
+	return input, nil
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretPayloadValues(t *testing.T) {
+	values, err := secretPayloadValues([]byte(`{"A": "1", "PORT": 8080}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"A": "1", "PORT": "8080"}, values)
+
+	values, err = secretPayloadValues([]byte("A=1\nB=\"two words\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"A": "1", "B": "two words"}, values)
+
+	values, err = secretPayloadValues([]byte("opaque token"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"(payload)": "opaque token"}, values)
+}
+
+func TestDiffSecretValues(t *testing.T) {
+	changes := diffSecretValues(
+		map[string]string{"A": "1", "B": "2", "C": "3"},
+		map[string]string{"A": "1", "B": "20", "D": "4"},
+	)
+	assert.Equal(t, []KeyChange{
+		{Key: "B", Change: "updated", OldValue: "2", NewValue: "20"},
+		{Key: "C", Change: "removed", OldValue: "3"},
+		{Key: "D", Change: "added", NewValue: "4"},
+	}, changes)
+	assert.Empty(t, diffSecretValues(map[string]string{"A": "1"}, map[string]string{"A": "1"}))
+}
+
+func TestSortSecretVersions(t *testing.T) {
+	versions := []BackendSecretVersion{{Version: "2"}, {Version: "10"}, {Version: "1"}}
+	sortSecretVersions(versions)
+	assert.Equal(t, []BackendSecretVersion{{Version: "10"}, {Version: "2"}, {Version: "1"}}, versions)
+
+	versions = []BackendSecretVersion{{Version: "aa", Created: "2025-01-01T00:00:00Z"}, {Version: "bb", Created: "2025-03-01T00:00:00Z"}}
+	sortSecretVersions(versions)
+	assert.Equal(t, "bb", versions[0].Version)
+}
+
+func TestParseAndValidateSecretVersionsParams(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "my-project")
+	t.Setenv("SECRET_NAME", "app-env")
+	t.Setenv("SECRET_ALLOWED_NAMES", "")
+
+	input, err := parseAndValidateSecretVersionsParams(map[string]any{"fromVersion": "3"})
+	assert.NoError(t, err)
+	assert.Equal(t, "app-env", input.SecretName)
+	assert.Equal(t, "3", input.FromVersion)
+	assert.Equal(t, "latest", input.ToVersion)
+
+	_, err = parseAndValidateSecretVersionsParams(map[string]any{"version": "previous"})
+	assert.Error(t, err)
+
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	input, err = parseAndValidateSecretVersionsParams(map[string]any{"backend": "azure", "version": "0123456789abcdef0123456789abcdef"})
+	assert.NoError(t, err)
+	assert.Equal(t, secretBackendAzure, input.Backend)
+	assert.Empty(t, input.ProjectID)
+	_, err = parseAndValidateSecretVersionsParams(map[string]any{"backend": "azure", "version": "3"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,372 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	psaEnforceLabel = "pod-security.kubernetes.io/enforce"
+	psaAuditLabel   = "pod-security.kubernetes.io/audit"
+	psaWarnLabel    = "pod-security.kubernetes.io/warn"
+
+	defaultSecurityFindingsLimit = 200
+)
+
+// Pod Security Standards levels, from least to most restrictive.
+var pssLevels = map[string]int{"privileged": 0, "baseline": 1, "restricted": 2}
+
+var securitySeverityRank = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+// systemNamespaces are skipped unless includeSystem is set; they legitimately run privileged agents.
+var systemNamespaces = map[string]bool{"kube-system": true, "kube-public": true, "kube-node-lease": true}
+
+// baselineCapabilities are the capabilities the baseline Pod Security Standard allows adding.
+var baselineCapabilities = map[string]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true, "KILL": true,
+	"MKNOD": true, "NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true, "SETPCAP": true, "SETUID": true,
+	"SYS_CHROOT": true,
+}
+
+// criticalCapabilities amount to root on the node.
+var criticalCapabilities = map[string]bool{"ALL": true, "SYS_ADMIN": true, "SYS_MODULE": true, "SYS_PTRACE": true, "NET_ADMIN": true}
+
+// SecurityFinding is one security problem of a namespace or workload. Level is the Pod Security
+// Standard the setting violates, empty for hardening advice outside the standards.
+type SecurityFinding struct {
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload,omitempty"`
+	Container string `json:"container,omitempty"`
+	Check     string `json:"check"`
+	Severity  string `json:"severity"`
+	Level     string `json:"violates,omitempty"`
+	Issue     string `json:"issue"`
+}
+
+// NamespaceSecurity is the Pod Security Admission configuration of a namespace and the strictest
+// Pod Security Standard each of its workloads satisfies.
+type NamespaceSecurity struct {
+	Name      string         `json:"name"`
+	Enforce   string         `json:"enforce,omitempty"`
+	Audit     string         `json:"audit,omitempty"`
+	Warn      string         `json:"warn,omitempty"`
+	Workloads map[string]int `json:"workloadsByLevel,omitempty"`
+}
+
+// SecurityAuditInput represents the input for security_audit.
+type SecurityAuditInput struct {
+	Namespace     string `json:"namespace,omitempty"`
+	IncludeSystem bool   `json:"includeSystem,omitempty"`
+	MinSeverity   string `json:"minSeverity"`
+	Limit         int    `json:"limit"`
+}
+
+// SecurityAuditTool audits Pod Security Admission labels and workload securityContexts.
+type SecurityAuditTool struct {
+	client Client
+}
+
+// NewSecurityAuditTool creates a new SecurityAuditTool with the provided Kubernetes client.
+func NewSecurityAuditTool(client Client) *SecurityAuditTool {
+	return &SecurityAuditTool{client: client}
+}
+
+// Tool returns the MCP tool definition for security_audit.
+func (s *SecurityAuditTool) Tool() mcp.Tool {
+	return mcp.NewTool("security_audit",
+		mcp.WithDescription("Security review of namespaces and workloads: Pod Security Admission enforce/audit/warn labels of each namespace, and the pod templates of Deployments, StatefulSets and DaemonSets checked against the Pod Security Standards (privileged containers, hostNetwork/hostPID/hostIPC, hostPath volumes, host ports, added capabilities, privilege escalation, running as root, seccomp) plus a writable root filesystem. Returns findings graded critical, high, medium or low, the strictest standard each workload meets, and workloads whose new pods the namespace's enforce level would reject"),
+		mcp.WithString("namespace",
+			mcp.Description("Only audit this namespace (default: all namespaces)"),
+		),
+		mcp.WithBoolean("includeSystem",
+			mcp.Description("Also audit kube-system, kube-public and kube-node-lease (default: false)"),
+		),
+		mcp.WithString("minSeverity",
+			mcp.Description("Only report findings of this severity or worse (default: low)"),
+			mcp.Enum("critical", "high", "medium", "low"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of findings to return (default: %d)", defaultSecurityFindingsLimit)),
+		),
+	)
+}
+
+// Handler audits the namespaces and their workloads.
+func (s *SecurityAuditTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateSecurityAuditParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate security_audit params: %w", err)
+	}
+	clientset, err := s.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	var namespaces []corev1.Namespace
+	if input.Namespace != "" {
+		ns, err := clientset.CoreV1().Namespaces().Get(ctx, input.Namespace, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespace %s: %w", input.Namespace, err)
+		}
+		namespaces = append(namespaces, *ns)
+	} else {
+		list, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		for _, ns := range list.Items {
+			if input.IncludeSystem || !systemNamespaces[ns.Name] {
+				namespaces = append(namespaces, ns)
+			}
+		}
+	}
+	workloads, err := listWorkloadTemplates(ctx, clientset, input.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	byNamespace := map[string][]workloadTemplate{}
+	for _, w := range workloads {
+		byNamespace[w.Namespace] = append(byNamespace[w.Namespace], w)
+	}
+
+	summaries := make([]NamespaceSecurity, 0, len(namespaces))
+	findings := []SecurityFinding{}
+	audited := 0
+	for i := range namespaces {
+		audited += len(byNamespace[namespaces[i].Name])
+		summary, nsFindings := auditNamespaceSecurity(&namespaces[i], byNamespace[namespaces[i].Name])
+		summaries = append(summaries, summary)
+		findings = append(findings, nsFindings...)
+	}
+
+	counts := map[string]int{}
+	kept := []SecurityFinding{}
+	for _, f := range findings {
+		counts[f.Severity]++
+		if securitySeverityRank[f.Severity] <= securitySeverityRank[input.MinSeverity] {
+			kept = append(kept, f)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		return securitySeverityRank[kept[i].Severity] < securitySeverityRank[kept[j].Severity]
+	})
+	result := map[string]any{
+		"namespaces": summaries,
+		"workloads":  audited,
+		"counts":     counts,
+	}
+	if len(kept) > input.Limit {
+		kept, result["truncated"] = kept[:input.Limit], true
+	}
+	result["findings"] = kept
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// auditNamespaceSecurity checks a namespace's Pod Security Admission labels and its workloads.
+func auditNamespaceSecurity(ns *corev1.Namespace, workloads []workloadTemplate) (NamespaceSecurity, []SecurityFinding) {
+	summary := NamespaceSecurity{
+		Name:    ns.Name,
+		Enforce: ns.Labels[psaEnforceLabel],
+		Audit:   ns.Labels[psaAuditLabel],
+		Warn:    ns.Labels[psaWarnLabel],
+	}
+	var findings []SecurityFinding
+	add := func(severity, issue string) {
+		findings = append(findings, SecurityFinding{Namespace: ns.Name, Check: "podSecurityAdmission", Severity: severity, Issue: issue})
+	}
+	switch summary.Enforce {
+	case "":
+		add("medium", "no pod-security.kubernetes.io/enforce label; only the cluster-wide admission default applies")
+	case "privileged":
+		add("high", "enforces the privileged Pod Security Standard, which allows host access and privileged containers")
+	case "baseline":
+		add("low", "enforces baseline; consider restricted, or warn/audit at restricted first")
+	}
+
+	for _, w := range workloads {
+		ref := w.Kind + "/" + w.Name
+		wf := auditPodSecurity(&w.Template.Spec)
+		level := pssLevel(wf)
+		if summary.Workloads == nil {
+			summary.Workloads = map[string]int{}
+		}
+		summary.Workloads[level]++
+		for _, f := range wf {
+			f.Namespace, f.Workload = ns.Name, ref
+			findings = append(findings, f)
+		}
+		if enforce, ok := pssLevels[summary.Enforce]; ok && pssLevels[level] < enforce {
+			findings = append(findings, SecurityFinding{
+				Namespace: ns.Name,
+				Workload:  ref,
+				Check:     "podSecurityAdmission",
+				Severity:  "high",
+				Issue:     fmt.Sprintf("pods only meet the %s standard but the namespace enforces %s; new pods will be rejected on the next rollout", level, summary.Enforce),
+			})
+		}
+	}
+	return summary, findings
+}
+
+// pssLevel returns the strictest Pod Security Standard a pod satisfies given its findings.
+func pssLevel(findings []SecurityFinding) string {
+	level := "restricted"
+	for _, f := range findings {
+		switch f.Level {
+		case "baseline":
+			return "privileged"
+		case "restricted":
+			level = "baseline"
+		}
+	}
+	return level
+}
+
+// auditPodSecurity checks a pod spec against the Pod Security Standards. Findings carry the
+// container and check but no namespace or workload.
+func auditPodSecurity(spec *corev1.PodSpec) []SecurityFinding {
+	var findings []SecurityFinding
+	add := func(container, check, severity, level, issue string) {
+		findings = append(findings, SecurityFinding{Container: container, Check: check, Severity: severity, Level: level, Issue: issue})
+	}
+
+	if spec.HostNetwork {
+		add("", "hostNetwork", "high", "baseline", "shares the node's network namespace")
+	}
+	if spec.HostPID {
+		add("", "hostPID", "high", "baseline", "shares the node's process namespace")
+	}
+	if spec.HostIPC {
+		add("", "hostIPC", "high", "baseline", "shares the node's IPC namespace")
+	}
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			add("", "hostPath", "high", "baseline", fmt.Sprintf("volume %s mounts %s from the node", v.Name, v.HostPath.Path))
+		}
+	}
+
+	podSC := spec.SecurityContext
+	if podSC == nil {
+		podSC = &corev1.PodSecurityContext{}
+	}
+	containers := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	containers = append(containers, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	for i := range containers {
+		findings = append(findings, auditContainerSecurity(&containers[i], podSC)...)
+	}
+	return findings
+}
+
+// auditContainerSecurity checks one container's securityContext, falling back to the pod's.
+func auditContainerSecurity(c *corev1.Container, podSC *corev1.PodSecurityContext) []SecurityFinding {
+	var findings []SecurityFinding
+	add := func(check, severity, level, issue string) {
+		findings = append(findings, SecurityFinding{Container: c.Name, Check: check, Severity: severity, Level: level, Issue: issue})
+	}
+	sc := c.SecurityContext
+	if sc == nil {
+		sc = &corev1.SecurityContext{}
+	}
+
+	if sc.Privileged != nil && *sc.Privileged {
+		add("privileged", "critical", "baseline", "runs privileged with full access to the node's devices")
+	}
+	if sc.ProcMount != nil && *sc.ProcMount == corev1.UnmaskedProcMount {
+		add("procMount", "high", "baseline", "uses an unmasked /proc")
+	}
+	for _, port := range c.Ports {
+		if port.HostPort != 0 {
+			add("hostPort", "medium", "baseline", fmt.Sprintf("binds host port %d", port.HostPort))
+		}
+	}
+
+	dropsAll := false
+	if caps := sc.Capabilities; caps != nil {
+		for _, capability := range caps.Add {
+			name := strings.TrimPrefix(strings.ToUpper(string(capability)), "CAP_")
+			switch {
+			case criticalCapabilities[name]:
+				add("capabilities", "critical", "baseline", fmt.Sprintf("adds capability %s", name))
+			case !baselineCapabilities[name]:
+				add("capabilities", "high", "baseline", fmt.Sprintf("adds capability %s", name))
+			case name != "NET_BIND_SERVICE":
+				add("capabilities", "low", "restricted", fmt.Sprintf("adds capability %s; restricted only allows NET_BIND_SERVICE", name))
+			}
+		}
+		for _, capability := range caps.Drop {
+			if strings.EqualFold(string(capability), "ALL") {
+				dropsAll = true
+			}
+		}
+	}
+	if !dropsAll {
+		add("capabilities", "low", "restricted", "does not drop ALL capabilities")
+	}
+
+	if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+		add("allowPrivilegeEscalation", "medium", "restricted", "allowPrivilegeEscalation is not false; setuid binaries can gain privileges")
+	}
+
+	runAsUser, runAsNonRoot := podSC.RunAsUser, podSC.RunAsNonRoot
+	if sc.RunAsUser != nil {
+		runAsUser = sc.RunAsUser
+	}
+	if sc.RunAsNonRoot != nil {
+		runAsNonRoot = sc.RunAsNonRoot
+	}
+	switch {
+	case runAsUser != nil && *runAsUser == 0:
+		add("runAsNonRoot", "high", "restricted", "runs as UID 0 (root)")
+	case runAsNonRoot == nil || !*runAsNonRoot:
+		add("runAsNonRoot", "medium", "restricted", "runAsNonRoot is not true; the container runs as root unless its image sets a user")
+	}
+
+	seccomp := podSC.SeccompProfile
+	if sc.SeccompProfile != nil {
+		seccomp = sc.SeccompProfile
+	}
+	switch {
+	case seccomp != nil && seccomp.Type == corev1.SeccompProfileTypeUnconfined:
+		add("seccompProfile", "medium", "baseline", "seccomp profile is Unconfined")
+	case seccomp == nil:
+		add("seccompProfile", "low", "restricted", "no seccomp profile; set RuntimeDefault")
+	}
+
+	if sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+		add("readOnlyRootFilesystem", "low", "", "root filesystem is writable")
+	}
+	return findings
+}
+
+func parseAndValidateSecurityAuditParams(args map[string]any) (*SecurityAuditInput, error) {
+	input := &SecurityAuditInput{MinSeverity: "low", Limit: defaultSecurityFindingsLimit}
+	input.Namespace, _ = args["namespace"].(string)
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	input.IncludeSystem, _ = args["includeSystem"].(bool)
+	if severity, ok := args["minSeverity"].(string); ok && severity != "" {
+		if _, ok := securitySeverityRank[severity]; !ok {
+			return nil, fmt.Errorf("minSeverity must be one of critical, high, medium or low")
+		}
+		input.MinSeverity = severity
+	}
+	if limit, ok := args["limit"].(float64); ok && limit > 0 {
+		input.Limit = int(limit)
+	}
+	return input, nil
+}
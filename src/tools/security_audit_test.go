@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func securityChecks(findings []SecurityFinding) map[string]string {
+	checks := map[string]string{}
+	for _, f := range findings {
+		checks[f.Check] = f.Severity
+	}
+	return checks
+}
+
+func restrictedPodSpec() corev1.PodSpec {
+	return corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot:   ptr.To(true),
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+		Containers: []corev1.Container{{
+			Name: "app",
+			SecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: ptr.To(false),
+				ReadOnlyRootFilesystem:   ptr.To(true),
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}, Add: []corev1.Capability{"NET_BIND_SERVICE"}},
+			},
+		}},
+	}
+}
+
+func TestAuditPodSecurityRestricted(t *testing.T) {
+	spec := restrictedPodSpec()
+
+	findings := auditPodSecurity(&spec)
+
+	assert.Empty(t, findings)
+	assert.Equal(t, "restricted", pssLevel(findings))
+}
+
+func TestAuditPodSecurityPrivileged(t *testing.T) {
+	spec := corev1.PodSpec{
+		HostNetwork: true,
+		Volumes:     []corev1.Volume{{Name: "docker", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/docker.sock"}}}},
+		Containers: []corev1.Container{{
+			Name:  "agent",
+			Ports: []corev1.ContainerPort{{ContainerPort: 9100, HostPort: 9100}},
+			SecurityContext: &corev1.SecurityContext{
+				Privileged:   ptr.To(true),
+				RunAsUser:    ptr.To(int64(0)),
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"CAP_SYS_ADMIN"}},
+			},
+		}},
+	}
+
+	findings := auditPodSecurity(&spec)
+
+	assert.Equal(t, map[string]string{
+		"hostNetwork":              "high",
+		"hostPath":                 "high",
+		"privileged":               "critical",
+		"hostPort":                 "medium",
+		"capabilities":             "low",
+		"allowPrivilegeEscalation": "medium",
+		"runAsNonRoot":             "high",
+		"seccompProfile":           "low",
+		"readOnlyRootFilesystem":   "low",
+	}, securityChecks(findings))
+	assert.Contains(t, findings, SecurityFinding{Container: "agent", Check: "capabilities", Severity: "critical", Level: "baseline", Issue: "adds capability SYS_ADMIN"})
+	assert.Equal(t, "privileged", pssLevel(findings))
+}
+
+func TestAuditContainerSecurityInheritsPodContext(t *testing.T) {
+	podSC := &corev1.PodSecurityContext{RunAsNonRoot: ptr.To(true), SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}}
+	container := &corev1.Container{Name: "app"}
+
+	findings := auditContainerSecurity(container, podSC)
+
+	checks := securityChecks(findings)
+	assert.NotContains(t, checks, "runAsNonRoot")
+	assert.Equal(t, "medium", checks["seccompProfile"])
+	assert.Equal(t, "privileged", pssLevel(findings))
+}
+
+func TestAuditNamespaceSecurity(t *testing.T) {
+	restricted := restrictedPodSpec()
+	baseline := restrictedPodSpec()
+	baseline.Containers[0].SecurityContext.AllowPrivilegeEscalation = nil
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{psaEnforceLabel: "restricted", psaWarnLabel: "restricted"}}}
+	workloads := []workloadTemplate{
+		{Kind: "Deployment", Name: "api", Namespace: "web", Template: &corev1.PodTemplateSpec{Spec: restricted}},
+		{Kind: "StatefulSet", Name: "db", Namespace: "web", Template: &corev1.PodTemplateSpec{Spec: baseline}},
+	}
+
+	summary, findings := auditNamespaceSecurity(ns, workloads)
+
+	assert.Equal(t, NamespaceSecurity{Name: "web", Enforce: "restricted", Warn: "restricted", Workloads: map[string]int{"restricted": 1, "baseline": 1}}, summary)
+	require.Len(t, findings, 2)
+	assert.Equal(t, "allowPrivilegeEscalation", findings[0].Check)
+	assert.Equal(t, "StatefulSet/db", findings[1].Workload)
+	assert.Equal(t, "high", findings[1].Severity)
+	assert.Contains(t, findings[1].Issue, "only meet the baseline standard but the namespace enforces restricted")
+
+	_, findings = auditNamespaceSecurity(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "legacy"}}, nil)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "medium", findings[0].Severity)
+}
+
+func TestParseAndValidateSecurityAuditParams(t *testing.T) {
+	input, err := parseAndValidateSecurityAuditParams(map[string]any{"minSeverity": "high", "includeSystem": true})
+	require.NoError(t, err)
+	assert.Equal(t, &SecurityAuditInput{IncludeSystem: true, MinSeverity: "high", Limit: defaultSecurityFindingsLimit}, input)
+
+	_, err = parseAndValidateSecurityAuditParams(map[string]any{"minSeverity": "info"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,393 @@
+// Environment variables used by the DNS probe pods of check_service and dns_health:
+// Optional:
+//   DNS_PROBE_IMAGE                - Image of the probe pods; must provide nslookup (default: busybox:1.36)
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultDNSProbeImage is the image used for the in-cluster DNS lookup; it must provide nslookup.
+const defaultDNSProbeImage = "busybox:1.36"
+
+// dnsProbeImage returns the image of the DNS probe pods: DNS_PROBE_IMAGE or defaultDNSProbeImage.
+func dnsProbeImage() string {
+	if image := os.Getenv("DNS_PROBE_IMAGE"); image != "" {
+		return image
+	}
+	return defaultDNSProbeImage
+}
+
+// validateProbeImage only accepts the configured probe image, so callers cannot run arbitrary
+// images in the cluster.
+func validateProbeImage(image string) error {
+	if want := dnsProbeImage(); image != want {
+		return fmt.Errorf("probeImage must be %s; set DNS_PROBE_IMAGE to use another image", want)
+	}
+	return nil
+}
+
+// CheckServiceInput represents the input for diagnosing a Service.
+type CheckServiceInput struct {
+	Name          string        `json:"name"`
+	Namespace     string        `json:"namespace"`
+	CheckDNS      bool          `json:"checkDNS,omitempty"`
+	ProbeImage    string        `json:"probeImage,omitempty"`
+	ProbeTimeout  time.Duration `json:"probeTimeout,omitempty"`
+	ClusterDomain string        `json:"clusterDomain,omitempty"`
+}
+
+// ServicePortCheck reports whether a Service port's targetPort exists on the selected pods.
+type ServicePortCheck struct {
+	Name        string   `json:"name,omitempty"`
+	Port        int32    `json:"port"`
+	TargetPort  string   `json:"targetPort"`
+	Protocol    string   `json:"protocol"`
+	MatchedPods int      `json:"matchedPods"`
+	Mismatched  []string `json:"mismatchedPods,omitempty"`
+}
+
+// EndpointCounts summarizes the addresses published in a Service's EndpointSlices.
+type EndpointCounts struct {
+	Slices      int      `json:"slices"`
+	Ready       int      `json:"ready"`
+	NotReady    int      `json:"notReady"`
+	Terminating int      `json:"terminating,omitempty"`
+	NotReadyFor []string `json:"notReadyPods,omitempty"`
+}
+
+// CheckServiceTool diagnoses why a Service is not reaching its pods.
+type CheckServiceTool struct {
+	client Client
+}
+
+// NewCheckServiceTool creates a new CheckServiceTool with the provided Kubernetes client.
+func NewCheckServiceTool(client Client) *CheckServiceTool {
+	return &CheckServiceTool{client: client}
+}
+
+// Tool returns the MCP tool definition for check_service.
+func (c *CheckServiceTool) Tool() mcp.Tool {
+	return mcp.NewTool("check_service",
+		mcp.WithDescription("Diagnose Service connectivity: checks that the selector matches running pods, EndpointSlices have ready addresses and each targetPort exists on the pods. Optionally resolves the Service DNS name from inside the cluster with a short-lived probe pod."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the Service"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the Service (defaults to 'default' if not specified)"),
+		),
+		mcp.WithBoolean("checkDNS",
+			mcp.Description("Run a temporary pod in the Service's namespace that resolves the Service name with nslookup, then delete it (default: false)"),
+		),
+		mcp.WithString("probeImage",
+			mcp.Description("Image for the DNS probe pod; only the server's DNS_PROBE_IMAGE is accepted (default: busybox:1.36)"),
+		),
+		mcp.WithNumber("probeTimeoutSeconds",
+			mcp.Description("How long to wait for the DNS probe pod to finish (default: 60)"),
+		),
+		mcp.WithString("clusterDomain",
+			mcp.Description("Cluster DNS domain (default: cluster.local)"),
+		),
+	)
+}
+
+// Handler runs the Service checks.
+func (c *CheckServiceTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateCheckServiceParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate check_service params: %w", err)
+	}
+
+	clientset, err := c.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	svc, err := clientset.CoreV1().Services(input.Namespace).Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s/%s: %w", input.Namespace, input.Name, err)
+	}
+
+	fqdn := fmt.Sprintf("%s.%s.svc.%s", svc.Name, svc.Namespace, input.ClusterDomain)
+	result := map[string]any{
+		"service":   svc.Name,
+		"namespace": svc.Namespace,
+		"type":      svc.Spec.Type,
+		"clusterIP": svc.Spec.ClusterIP,
+		"dnsName":   fqdn,
+	}
+	problems := []string{}
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		result["externalName"] = svc.Spec.ExternalName
+	} else if len(svc.Spec.Selector) == 0 {
+		problems = append(problems, "Service has no selector; its endpoints must be managed manually")
+	} else {
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		pods, err := clientset.CoreV1().Pods(svc.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		running, ready := 0, 0
+		for i := range pods.Items {
+			if pods.Items[i].Status.Phase == corev1.PodRunning {
+				running++
+			}
+			if podReady(&pods.Items[i]) {
+				ready++
+			}
+		}
+		result["selector"] = selector.String()
+		result["pods"] = map[string]int{"matched": len(pods.Items), "running": running, "ready": ready}
+		switch {
+		case len(pods.Items) == 0:
+			problems = append(problems, fmt.Sprintf("selector %s matches no pods; check the labels on the workload's pod template", selector.String()))
+		case ready == 0:
+			problems = append(problems, "no selected pod is Ready; endpoints only include ready pods")
+		}
+
+		portChecks := checkServicePorts(svc, pods.Items)
+		for _, pc := range portChecks {
+			if len(pc.Mismatched) > 0 {
+				problems = append(problems, fmt.Sprintf("targetPort %s of port %d is not exposed by %d pod(s)", pc.TargetPort, pc.Port, len(pc.Mismatched)))
+			}
+		}
+		result["ports"] = portChecks
+
+		counts, err := serviceEndpointCounts(ctx, clientset, svc)
+		if err != nil {
+			result["endpointsError"] = err.Error()
+		} else {
+			result["endpoints"] = counts
+			if counts.Ready == 0 {
+				problems = append(problems, "EndpointSlices contain no ready addresses; traffic to the Service will fail")
+			}
+		}
+	}
+
+	if input.CheckDNS {
+		dns, err := probeServiceDNS(ctx, clientset, input, fqdn)
+		if err != nil {
+			result["dnsError"] = err.Error()
+			problems = append(problems, "DNS probe failed: "+err.Error())
+		} else {
+			result["dns"] = dns
+		}
+	}
+
+	result["problems"] = problems
+	result["healthy"] = len(problems) == 0
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// checkServicePorts resolves every Service port's targetPort against each selected pod.
+// Numeric targetPorts are only reported as mismatched when the pod declares ports at all,
+// because containers can listen on ports they do not declare.
+func checkServicePorts(svc *corev1.Service, pods []corev1.Pod) []ServicePortCheck {
+	checks := make([]ServicePortCheck, 0, len(svc.Spec.Ports))
+	for _, sp := range svc.Spec.Ports {
+		target := sp.TargetPort
+		if target.Type == intstr.Int && target.IntVal == 0 {
+			target = intstr.FromInt32(sp.Port)
+		}
+		check := ServicePortCheck{
+			Name:       sp.Name,
+			Port:       sp.Port,
+			TargetPort: target.String(),
+			Protocol:   string(sp.Protocol),
+		}
+		for i := range pods {
+			if podExposesPort(&pods[i], target, sp.Protocol) {
+				check.MatchedPods++
+			} else {
+				check.Mismatched = append(check.Mismatched, pods[i].Name)
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// podExposesPort reports whether a pod's containers provide the given named or numeric port.
+func podExposesPort(pod *corev1.Pod, port intstr.IntOrString, protocol corev1.Protocol) bool {
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+	declared := false
+	for _, c := range pod.Spec.Containers {
+		for _, cp := range c.Ports {
+			declared = true
+			cpProtocol := cp.Protocol
+			if cpProtocol == "" {
+				cpProtocol = corev1.ProtocolTCP
+			}
+			if cpProtocol != protocol {
+				continue
+			}
+			if port.Type == intstr.String && cp.Name == port.StrVal {
+				return true
+			}
+			if port.Type == intstr.Int && cp.ContainerPort == port.IntVal {
+				return true
+			}
+		}
+	}
+	return port.Type == intstr.Int && !declared
+}
+
+// serviceEndpointCounts counts the ready and not-ready addresses in the Service's EndpointSlices.
+func serviceEndpointCounts(ctx context.Context, clientset kubernetes.Interface, svc *corev1.Service) (*EndpointCounts, error) {
+	slices, err := clientset.DiscoveryV1().EndpointSlices(svc.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + svc.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpointslices: %w", err)
+	}
+	return countEndpoints(slices.Items), nil
+}
+
+// countEndpoints tallies endpoint readiness across EndpointSlices. An unset ready condition means ready.
+func countEndpoints(slices []discoveryv1.EndpointSlice) *EndpointCounts {
+	counts := &EndpointCounts{Slices: len(slices)}
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			n := len(ep.Addresses)
+			switch {
+			case ep.Conditions.Terminating != nil && *ep.Conditions.Terminating:
+				counts.Terminating += n
+			case ep.Conditions.Ready == nil || *ep.Conditions.Ready:
+				counts.Ready += n
+			default:
+				counts.NotReady += n
+				if ep.TargetRef != nil {
+					counts.NotReadyFor = append(counts.NotReadyFor, ep.TargetRef.Name)
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// probeServiceDNS runs a short-lived pod that resolves the Service name and returns its output.
+// The pod is always deleted, even when the lookup fails or times out.
+func probeServiceDNS(ctx context.Context, clientset kubernetes.Interface, input *CheckServiceInput, fqdn string) (map[string]any, error) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "dns-probe-",
+			Namespace:    input.Namespace,
+			Labels:       map[string]string{"app.kubernetes.io/managed-by": "kubernetes-mcp"},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "nslookup",
+				Image:   input.ProbeImage,
+				Command: []string{"nslookup", fqdn},
+			}},
+		},
+	}
+
+	created, err := clientset.CoreV1().Pods(input.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DNS probe pod: %w", err)
+	}
+	defer func() {
+		grace := int64(0)
+		_ = clientset.CoreV1().Pods(input.Namespace).Delete(context.Background(), created.Name, metav1.DeleteOptions{GracePeriodSeconds: &grace})
+	}()
+
+	deadline := time.Now().Add(input.ProbeTimeout)
+	for {
+		current, err := clientset.CoreV1().Pods(input.Namespace).Get(ctx, created.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get DNS probe pod: %w", err)
+		}
+		if current.Status.Phase == corev1.PodSucceeded || current.Status.Phase == corev1.PodFailed {
+			logs, err := tailContainerLogs(ctx, clientset, current, "nslookup", false, 50)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{
+				"resolved": current.Status.Phase == corev1.PodSucceeded,
+				"output":   strings.TrimSpace(logs),
+				"probePod": created.Name,
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("DNS probe pod did not finish within %s (phase %s)", input.ProbeTimeout, current.Status.Phase)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// parseAndValidateCheckServiceParams validates and parses the input parameters.
+func parseAndValidateCheckServiceParams(args map[string]any) (*CheckServiceInput, error) {
+	input := &CheckServiceInput{
+		ProbeImage:    dnsProbeImage(),
+		ProbeTimeout:  60 * time.Second,
+		ClusterDomain: "cluster.local",
+	}
+
+	if name, ok := args["name"].(string); ok {
+		input.Name = name
+	}
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid service name: %w", err)
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	if v, ok := args["checkDNS"].(bool); ok {
+		input.CheckDNS = v
+	}
+	if v, ok := args["probeImage"].(string); ok && v != "" {
+		if err := validateProbeImage(v); err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := args["probeTimeoutSeconds"].(float64); ok && v > 0 {
+		input.ProbeTimeout = time.Duration(v) * time.Second
+	}
+	if v, ok := args["clusterDomain"].(string); ok && v != "" {
+		input.ClusterDomain = strings.Trim(v, ".")
+	}
+
+	return input, nil
+}
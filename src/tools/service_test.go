@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestCheckServicePorts(t *testing.T) {
+	svc := &corev1.Service{Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+		{Name: "http", Port: 80, TargetPort: intstr.FromString("http"), Protocol: corev1.ProtocolTCP},
+		{Name: "metrics", Port: 9090, Protocol: corev1.ProtocolTCP},
+	}}}
+	pod := func(name string, ports ...corev1.ContainerPort) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Ports: ports}}},
+		}
+	}
+	pods := []corev1.Pod{
+		pod("good", corev1.ContainerPort{Name: "http", ContainerPort: 8080}, corev1.ContainerPort{ContainerPort: 9090}),
+		pod("renamed", corev1.ContainerPort{Name: "web", ContainerPort: 8080}),
+		pod("undeclared"),
+	}
+
+	checks := checkServicePorts(svc, pods)
+
+	if assert.Len(t, checks, 2) {
+		assert.Equal(t, "http", checks[0].TargetPort)
+		assert.Equal(t, 1, checks[0].MatchedPods)
+		assert.Equal(t, []string{"renamed", "undeclared"}, checks[0].Mismatched)
+
+		assert.Equal(t, "9090", checks[1].TargetPort)
+		assert.Equal(t, 2, checks[1].MatchedPods)
+		assert.Equal(t, []string{"renamed"}, checks[1].Mismatched)
+	}
+}
+
+func TestCountEndpoints(t *testing.T) {
+	yes, no := true, false
+	slices := []discoveryv1.EndpointSlice{{
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &yes}},
+			{Addresses: []string{"10.0.0.2"}},
+			{
+				Addresses:  []string{"10.0.0.3"},
+				Conditions: discoveryv1.EndpointConditions{Ready: &no},
+				TargetRef:  &corev1.ObjectReference{Name: "web-3"},
+			},
+			{Addresses: []string{"10.0.0.4"}, Conditions: discoveryv1.EndpointConditions{Ready: &no, Terminating: &yes}},
+		},
+	}}
+
+	assert.Equal(t, &EndpointCounts{Slices: 1, Ready: 2, NotReady: 1, Terminating: 1, NotReadyFor: []string{"web-3"}}, countEndpoints(slices))
+}
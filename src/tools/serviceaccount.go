@@ -0,0 +1,279 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// escalationVerbs are RBAC verbs that let a subject gain permissions it was not granted directly.
+var escalationVerbs = []string{"escalate", "bind", "impersonate"}
+
+// SAAuditInput represents the input for auditing a ServiceAccount.
+type SAAuditInput struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// SABoundRole is one role bound to a ServiceAccount, directly or through one of its groups.
+type SABoundRole struct {
+	Binding string              `json:"binding"`
+	Role    string              `json:"role"`
+	Scope   string              `json:"scope"`
+	Via     string              `json:"via,omitempty"`
+	Rules   []rbacv1.PolicyRule `json:"rules"`
+	Risks   []string            `json:"risks,omitempty"`
+}
+
+// SAAuditTool reports the permissions, users and tokens of a ServiceAccount.
+type SAAuditTool struct {
+	client Client
+}
+
+// NewSAAuditTool creates a new SAAuditTool with the provided Kubernetes client.
+func NewSAAuditTool(client Client) *SAAuditTool {
+	return &SAAuditTool{client: client}
+}
+
+// Tool returns the MCP tool definition for sa_audit.
+func (s *SAAuditTool) Tool() mcp.Tool {
+	return mcp.NewTool("sa_audit",
+		mcp.WithDescription("Audit a ServiceAccount for security reviews: roles bound to it directly or through its groups with the rules they grant, workloads that run as it, token automounting and legacy token secrets, and risky grants such as cluster-admin, wildcard verbs or resources, secret access, pod exec and escalate/bind/impersonate"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the ServiceAccount"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace of the ServiceAccount (defaults to 'default' if not specified)"),
+		),
+	)
+}
+
+// Handler collects the bindings, workloads and tokens of the ServiceAccount.
+func (s *SAAuditTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateSAAuditParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate sa_audit params: %w", err)
+	}
+
+	clientset, err := s.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	sa, err := clientset.CoreV1().ServiceAccounts(input.Namespace).Get(ctx, input.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get serviceaccount %s/%s: %w", input.Namespace, input.Name, err)
+	}
+
+	clusterRoles, err := clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusterroles: %w", err)
+	}
+	clusterRules := make(map[string][]rbacv1.PolicyRule, len(clusterRoles.Items))
+	for _, r := range clusterRoles.Items {
+		clusterRules[r.Name] = append([]rbacv1.PolicyRule{}, r.Rules...)
+	}
+
+	bound := []SABoundRole{}
+	clusterBindings, err := clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusterrolebindings: %w", err)
+	}
+	for _, b := range clusterBindings.Items {
+		if via, ok := bindingSelectsServiceAccount(b.Subjects, sa); ok {
+			bound = append(bound, newSABoundRole("ClusterRoleBinding/"+b.Name, "ClusterRole/"+b.RoleRef.Name, "cluster", via, clusterRules[b.RoleRef.Name]))
+		}
+	}
+
+	// RoleBindings in any namespace can grant a ServiceAccount access to that namespace.
+	bindings, err := clientset.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rolebindings: %w", err)
+	}
+	roleRules := map[string][]rbacv1.PolicyRule{}
+	for _, b := range bindings.Items {
+		via, ok := bindingSelectsServiceAccount(b.Subjects, sa)
+		if !ok {
+			continue
+		}
+		rules := clusterRules[b.RoleRef.Name]
+		if b.RoleRef.Kind == "Role" {
+			key := b.Namespace + "/" + b.RoleRef.Name
+			if _, cached := roleRules[key]; !cached {
+				role, err := clientset.RbacV1().Roles(b.Namespace).Get(ctx, b.RoleRef.Name, metav1.GetOptions{})
+				if err == nil {
+					roleRules[key] = append([]rbacv1.PolicyRule{}, role.Rules...)
+				}
+			}
+			rules = roleRules[key]
+		}
+		bound = append(bound, newSABoundRole("RoleBinding/"+b.Namespace+"/"+b.Name, b.RoleRef.Kind+"/"+b.RoleRef.Name, "namespace "+b.Namespace, via, rules))
+	}
+
+	workloads, err := listWorkloadTemplates(ctx, clientset, sa.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	users := []map[string]any{}
+	for _, w := range workloads {
+		name := w.Template.Spec.ServiceAccountName
+		if name == "" {
+			name = "default"
+		}
+		if name != sa.Name {
+			continue
+		}
+		users = append(users, map[string]any{
+			"workload":       w.Kind + "/" + w.Name,
+			"automountToken": serviceAccountTokenMounted(sa, &w.Template.Spec),
+		})
+	}
+
+	var legacyTokens []string
+	secrets, err := clientset.CoreV1().Secrets(sa.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + string(corev1.SecretTypeServiceAccountToken),
+	})
+	if err == nil {
+		for _, secret := range secrets.Items {
+			if secret.Annotations[corev1.ServiceAccountNameKey] == sa.Name {
+				legacyTokens = append(legacyTokens, secret.Name)
+			}
+		}
+	}
+
+	risks := []string{}
+	for _, b := range bound {
+		for _, r := range b.Risks {
+			risks = append(risks, fmt.Sprintf("%s via %s: %s", b.Role, b.Binding, r))
+		}
+	}
+	if len(legacyTokens) > 0 {
+		risks = append(risks, fmt.Sprintf("%d long-lived token secret(s) exist; prefer projected tokens", len(legacyTokens)))
+	}
+
+	result := map[string]any{
+		"serviceAccount":   sa.Namespace + "/" + sa.Name,
+		"automountToken":   sa.AutomountServiceAccountToken == nil || *sa.AutomountServiceAccountToken,
+		"boundRoles":       bound,
+		"workloads":        users,
+		"legacyTokens":     legacyTokens,
+		"imagePullSecrets": sa.ImagePullSecrets,
+		"risks":            risks,
+	}
+	if len(users) == 0 && sa.Name != "default" {
+		result["unused"] = true
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// newSABoundRole builds a bound role entry and flags its risky rules. Nil rules mean the role was not found.
+func newSABoundRole(binding, role, scope, via string, rules []rbacv1.PolicyRule) SABoundRole {
+	b := SABoundRole{Binding: binding, Role: role, Scope: scope, Via: via, Rules: rules}
+	if rules == nil {
+		b.Rules = []rbacv1.PolicyRule{}
+		b.Risks = append(b.Risks, "referenced role does not exist")
+	}
+	if role == "ClusterRole/cluster-admin" {
+		b.Risks = append(b.Risks, "cluster-admin grants full control of the "+scope)
+	}
+	for i := range rules {
+		b.Risks = append(b.Risks, riskyRuleFlags(&rules[i])...)
+	}
+	return b
+}
+
+// riskyRuleFlags describes the dangerous permissions granted by one rule.
+func riskyRuleFlags(rule *rbacv1.PolicyRule) []string {
+	var flags []string
+	resources := strings.Join(rule.Resources, ",")
+	if containsString(rule.Verbs, rbacv1.VerbAll) {
+		flags = append(flags, "wildcard verbs on "+resources)
+	}
+	if containsString(rule.Resources, rbacv1.ResourceAll) {
+		flags = append(flags, "wildcard resources in API groups "+strings.Join(rule.APIGroups, ","))
+	}
+	if policyRuleAllows(rule, &AccessCheckInput{Verb: "get", Resource: "secrets"}) || policyRuleAllows(rule, &AccessCheckInput{Verb: "list", Resource: "secrets"}) {
+		flags = append(flags, "can read secrets")
+	}
+	if policyRuleAllows(rule, &AccessCheckInput{Verb: "create", Resource: "pods", Subresource: "exec"}) {
+		flags = append(flags, "can exec into pods")
+	}
+	for _, verb := range escalationVerbs {
+		if containsString(rule.Verbs, verb) {
+			flags = append(flags, "can "+verb+" "+resources)
+		}
+	}
+	return flags
+}
+
+// bindingSelectsServiceAccount reports whether the binding subjects include the ServiceAccount,
+// either directly or through the groups every ServiceAccount belongs to.
+func bindingSelectsServiceAccount(subjects []rbacv1.Subject, sa *corev1.ServiceAccount) (string, bool) {
+	groups := []string{"system:serviceaccounts", "system:serviceaccounts:" + sa.Namespace, "system:authenticated"}
+	for _, s := range subjects {
+		switch s.Kind {
+		case rbacv1.ServiceAccountKind:
+			if s.Name == sa.Name && s.Namespace == sa.Namespace {
+				return "", true
+			}
+		case rbacv1.UserKind:
+			if s.Name == "system:serviceaccount:"+sa.Namespace+":"+sa.Name {
+				return "user " + s.Name, true
+			}
+		case rbacv1.GroupKind:
+			if containsString(groups, s.Name) {
+				return "group " + s.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// serviceAccountTokenMounted applies the pod-over-ServiceAccount precedence of automountServiceAccountToken.
+func serviceAccountTokenMounted(sa *corev1.ServiceAccount, spec *corev1.PodSpec) bool {
+	if spec.AutomountServiceAccountToken != nil {
+		return *spec.AutomountServiceAccountToken
+	}
+	return sa.AutomountServiceAccountToken == nil || *sa.AutomountServiceAccountToken
+}
+
+// parseAndValidateSAAuditParams validates and parses the input parameters.
+func parseAndValidateSAAuditParams(args map[string]any) (*SAAuditInput, error) {
+	input := &SAAuditInput{}
+
+	if name, ok := args["name"].(string); ok {
+		input.Name = name
+	}
+	if input.Name == "" {
+		return nil, errors.New("name must be provided")
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid serviceaccount name: %w", err)
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+	if input.Namespace == "" {
+		input.Namespace = metav1.NamespaceDefault
+	}
+
+	return input, nil
+}
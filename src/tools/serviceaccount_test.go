@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBindingSelectsServiceAccount(t *testing.T) {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "ci"}}
+
+	via, ok := bindingSelectsServiceAccount([]rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "deployer", Namespace: "ci"}}, sa)
+	assert.True(t, ok)
+	assert.Empty(t, via)
+
+	via, ok = bindingSelectsServiceAccount([]rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:ci"}}, sa)
+	assert.True(t, ok)
+	assert.Equal(t, "group system:serviceaccounts:ci", via)
+
+	_, ok = bindingSelectsServiceAccount([]rbacv1.Subject{
+		{Kind: rbacv1.ServiceAccountKind, Name: "deployer", Namespace: "prod"},
+		{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:prod"},
+	}, sa)
+	assert.False(t, ok)
+}
+
+func TestRiskyRuleFlags(t *testing.T) {
+	assert.Empty(t, riskyRuleFlags(&rbacv1.PolicyRule{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}}))
+
+	assert.Equal(t, []string{"can read secrets"},
+		riskyRuleFlags(&rbacv1.PolicyRule{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"secrets"}}))
+
+	assert.Equal(t, []string{"can exec into pods"},
+		riskyRuleFlags(&rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods/exec"}}))
+
+	assert.Equal(t, []string{"can bind clusterroles"},
+		riskyRuleFlags(&rbacv1.PolicyRule{Verbs: []string{"bind"}, APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}}))
+
+	assert.Equal(t, []string{"wildcard verbs on *", "wildcard resources in API groups *", "can read secrets", "can exec into pods"},
+		riskyRuleFlags(&rbacv1.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}))
+}
@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// setDefaultsToolName is the tool that edits session defaults; its own arguments are never defaulted.
+const setDefaultsToolName = "set_defaults"
+
+// outputFormatParams names the argument that selects the output format of each tool that has one.
+// Other tools' format arguments, like change_env's payload format, are not output formats.
+var outputFormatParams = map[string]string{
+	"object_tree": "format",
+}
+
+// Defaults are the arguments that tool calls of a session inherit when they omit them, and the
+// kubeconfig context they run against.
+type Defaults struct {
+	Namespace string `json:"namespace,omitempty"`
+	Context   string `json:"context,omitempty"`
+	Format    string `json:"format,omitempty"`
+}
+
+// SessionDefaults stores the defaults of each MCP session and fills them into tool calls.
+type SessionDefaults struct {
+	mu       sync.Mutex
+	sessions map[string]Defaults
+	schemas  map[string]map[string]any
+}
+
+// NewSessionDefaults creates an empty store.
+func NewSessionDefaults() *SessionDefaults {
+	return &SessionDefaults{sessions: map[string]Defaults{}, schemas: map[string]map[string]any{}}
+}
+
+// register records the parameters of a tool so that only arguments it accepts are defaulted.
+func (d *SessionDefaults) register(tool mcp.Tool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.schemas[tool.Name] = tool.InputSchema.Properties
+}
+
+// get returns the defaults of a session.
+func (d *SessionDefaults) get(session string) Defaults {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.sessions[session]
+}
+
+// set replaces the defaults of a session.
+func (d *SessionDefaults) set(session string, defaults Defaults) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if defaults == (Defaults{}) {
+		delete(d.sessions, session)
+		return
+	}
+	d.sessions[session] = defaults
+}
+
+// Forget drops the defaults of a session that ended; it is an OnUnregisterSession hook.
+func (d *SessionDefaults) Forget(_ context.Context, session server.ClientSession) {
+	d.set(session.SessionID(), Defaults{})
+}
+
+// apply returns the arguments with the session defaults added for the parameters the tool accepts
+// and the call omits. An argument passed explicitly, even empty, is kept, so namespace "" still
+// selects all namespaces where a tool supports it.
+func (d *SessionDefaults) apply(session, tool string, args map[string]any) map[string]any {
+	defaults := d.get(session)
+	if defaults == (Defaults{}) || tool == setDefaultsToolName {
+		return args
+	}
+	d.mu.Lock()
+	properties := d.schemas[tool]
+	d.mu.Unlock()
+
+	fill := func(name, value string) {
+		if value == "" {
+			return
+		}
+		property, ok := properties[name].(map[string]any)
+		if !ok {
+			return
+		}
+		if _, passed := args[name]; passed {
+			return
+		}
+		if enum, ok := property["enum"].([]string); ok && !slices.Contains(enum, value) {
+			return
+		}
+		args = withArgument(args, name, value)
+	}
+	fill("namespace", defaults.Namespace)
+	if param, ok := outputFormatParams[tool]; ok {
+		fill(param, defaults.Format)
+	}
+	return args
+}
+
+// Middleware fills session defaults into tool calls and selects the session's kubeconfig context.
+// It must run before middlewares that inspect arguments, such as authorization and policy, so they
+// see the namespace the call will use.
+func (d *SessionDefaults) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		session := sessionID(ctx)
+		req.Params.Arguments = d.apply(session, req.Params.Name, req.Params.Arguments)
+		if kubeContext := d.get(session).Context; kubeContext != "" {
+			ctx = withKubeContext(ctx, kubeContext)
+		}
+		return next(ctx, req)
+	}
+}
+
+// sessionID identifies the MCP session of a call; stdio has a single session.
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// SetDefaultsTool sets the namespace, kubeconfig context and output format that later calls in the
+// session inherit.
+type SetDefaultsTool struct {
+	defaults *SessionDefaults
+}
+
+// NewSetDefaultsTool creates a new set_defaults tool.
+func NewSetDefaultsTool(defaults *SessionDefaults) *SetDefaultsTool {
+	return &SetDefaultsTool{defaults: defaults}
+}
+
+// SetDefaultsInput is the input of the set_defaults tool. Nil fields keep their current default.
+type SetDefaultsInput struct {
+	Namespace *string
+	Context   *string
+	Format    *string
+	Clear     bool
+}
+
+// Tool returns the MCP tool definition.
+func (t *SetDefaultsTool) Tool() mcp.Tool {
+	return mcp.NewTool(setDefaultsToolName,
+		mcp.WithDescription("Set defaults for this session: later tool calls that omit namespace (or, for tools with an output format, format) use these values, and all tool calls run against the kubeconfig context if one is set. Pass namespace explicitly, even as \"\", to override the default in a single call. Call without arguments to show the current defaults."),
+		mcp.WithString("namespace",
+			mcp.Description("Default namespace; \"\" removes it"),
+		),
+		mcp.WithString("context",
+			mcp.Description("Kubeconfig context of the server that tool calls run against instead of the server's own cluster; it must be listed in KUBE_CONTEXTS. \"\" removes it"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Default output format for tools that have one, e.g. json or dot for object_tree; \"\" removes it"),
+		),
+		mcp.WithBoolean("clear",
+			mcp.Description("Remove all defaults of this session before applying the other arguments"),
+		),
+	)
+}
+
+// Handler updates the session defaults and returns them.
+func (t *SetDefaultsTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateSetDefaultsParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate set_defaults params: %w", err)
+	}
+
+	session := sessionID(ctx)
+	defaults := t.defaults.get(session)
+	if input.Clear {
+		defaults = Defaults{}
+	}
+	if input.Namespace != nil {
+		defaults.Namespace = *input.Namespace
+	}
+	if input.Context != nil {
+		if *input.Context != "" {
+			if err := authorizeKubeContext(ctx, *input.Context); err != nil {
+				return nil, err
+			}
+			if _, err := clientForContext(*input.Context); err != nil {
+				return nil, fmt.Errorf("invalid context: %w", err)
+			}
+		}
+		defaults.Context = *input.Context
+	}
+	if input.Format != nil {
+		defaults.Format = *input.Format
+	}
+	t.defaults.set(session, defaults)
+
+	out, err := json.Marshal(map[string]any{"defaults": defaults})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+func parseAndValidateSetDefaultsParams(args map[string]any) (*SetDefaultsInput, error) {
+	input := &SetDefaultsInput{}
+	if namespace, ok := args["namespace"].(string); ok {
+		if namespace != "" {
+			if err := validation.ValidateNamespace(namespace); err != nil {
+				return nil, fmt.Errorf("invalid namespace: %w", err)
+			}
+		}
+		input.Namespace = &namespace
+	}
+	if kubeContext, ok := args["context"].(string); ok {
+		input.Context = &kubeContext
+	}
+	if format, ok := args["format"].(string); ok {
+		input.Format = &format
+	}
+	if clear, ok := args["clear"].(bool); ok {
+		input.Clear = clear
+	}
+	return input, nil
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/k4mrul/kubernetes-mcp/src/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSession struct{ id string }
+
+func (s fakeSession) SessionID() string                                   { return s.id }
+func (s fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s fakeSession) Initialize()                                         {}
+func (s fakeSession) Initialized() bool                                   { return true }
+
+func newTestSessionDefaults() *SessionDefaults {
+	d := NewSessionDefaults()
+	d.register(mcp.NewTool("list_resources", mcp.WithString("kind"), mcp.WithString("namespace")))
+	d.register(mcp.NewTool("object_tree", mcp.WithString("namespace"), mcp.WithString("format", mcp.Enum("json", "dot"))))
+	d.register(mcp.NewTool("change_env", mcp.WithString("namespace"), mcp.WithString("format", mcp.Enum("json", "dotenv", "raw"))))
+	d.register(mcp.NewTool("top_nodes"))
+	return d
+}
+
+func TestSessionDefaultsApply(t *testing.T) {
+	d := newTestSessionDefaults()
+	d.set("a", Defaults{Namespace: "prod", Format: "dot"})
+
+	assert.Equal(t, map[string]any{"kind": "Pod", "namespace": "prod"}, d.apply("a", "list_resources", map[string]any{"kind": "Pod"}))
+	assert.Equal(t, map[string]any{"namespace": ""}, d.apply("a", "list_resources", map[string]any{"namespace": ""}))
+	assert.Equal(t, map[string]any{"namespace": "prod", "format": "dot"}, d.apply("a", "object_tree", nil))
+	assert.Equal(t, map[string]any{"namespace": "prod"}, d.apply("a", "change_env", nil))
+	assert.Nil(t, d.apply("a", "top_nodes", nil))
+	assert.Equal(t, map[string]any{"kind": "Pod"}, d.apply("b", "list_resources", map[string]any{"kind": "Pod"}))
+
+	d.set("c", Defaults{Format: "yaml"})
+	assert.Nil(t, d.apply("c", "object_tree", nil))
+}
+
+func TestSetDefaultsHandler(t *testing.T) {
+	d := newTestSessionDefaults()
+	tool := NewSetDefaultsTool(d)
+	ctx := context.Background()
+
+	call := func(args map[string]any) (string, error) {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := tool.Handler(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		return result.Content[0].(mcp.TextContent).Text, nil
+	}
+
+	out, err := call(map[string]any{"namespace": "prod", "format": "dot"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"defaults":{"namespace":"prod","format":"dot"}}`, out)
+
+	out, err = call(map[string]any{"namespace": "staging"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"defaults":{"namespace":"staging","format":"dot"}}`, out)
+
+	out, err = call(map[string]any{"clear": true, "format": "json"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"defaults":{"format":"json"}}`, out)
+
+	_, err = call(map[string]any{"namespace": "Not_Valid"})
+	assert.Error(t, err)
+}
+
+func TestSetDefaultsContext(t *testing.T) {
+	fakeClientForContext(t)
+	d := newTestSessionDefaults()
+	tool := NewSetDefaultsTool(d)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"context": "staging"}
+	_, err := tool.Handler(context.Background(), req)
+	assert.EqualError(t, err, `context "staging" is not in KUBE_CONTEXTS`, "switching contexts is off by default")
+	assert.Equal(t, Defaults{}, d.get(""))
+
+	t.Setenv("KUBE_CONTEXTS", "staging, de*")
+	_, err = tool.Handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, Defaults{Context: "staging"}, d.get(""))
+
+	ctx := auth.WithClient(context.Background(), &auth.Client{Name: "payments"})
+	_, err = tool.Handler(ctx, req)
+	assert.EqualError(t, err, "client payments is not allowed to use context staging")
+	_, err = tool.Handler(auth.WithClient(context.Background(), &auth.Client{Name: "platform", Contexts: []string{"staging"}}), req)
+	assert.NoError(t, err)
+
+	req.Params.Arguments = map[string]any{"context": "prod"}
+	_, err = tool.Handler(context.Background(), req)
+	assert.EqualError(t, err, `context "prod" is not in KUBE_CONTEXTS`)
+
+	req.Params.Arguments = map[string]any{"context": "dev"}
+	_, err = tool.Handler(context.Background(), req)
+	assert.EqualError(t, err, `invalid context: context "dev" does not exist`)
+	assert.Equal(t, Defaults{Context: "staging"}, d.get(""))
+
+	req.Params.Arguments = map[string]any{"context": ""}
+	_, err = tool.Handler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, Defaults{}, d.get(""))
+}
+
+func TestSessionDefaultsMiddleware(t *testing.T) {
+	d := newTestSessionDefaults()
+	d.set("", Defaults{Namespace: "prod"})
+
+	var seen map[string]any
+	handler := d.Middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = req.Params.Arguments
+		return nil, nil
+	})
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "list_resources"
+	_, _ = handler(context.Background(), req)
+	assert.Equal(t, map[string]any{"namespace": "prod"}, seen)
+
+	req.Params.Name = setDefaultsToolName
+	_, _ = handler(context.Background(), req)
+	assert.Nil(t, seen)
+
+	var kubeContext string
+	handler = d.Middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		kubeContext = kubeContextFrom(ctx)
+		return nil, nil
+	})
+	_, _ = handler(context.Background(), req)
+	assert.Equal(t, "", kubeContext)
+	d.set("", Defaults{Context: "staging"})
+	_, _ = handler(context.Background(), req)
+	assert.Equal(t, "staging", kubeContext)
+
+	d.Forget(context.Background(), fakeSession{})
+	assert.Equal(t, Defaults{}, d.get(""))
+}
+
+func TestSessionDefaultsPerSession(t *testing.T) {
+	d := newTestSessionDefaults()
+	tool := NewSetDefaultsTool(d)
+	s := server.NewMCPServer("test", "0.0.0")
+	ctx := s.WithContext(context.Background(), fakeSession{id: "a"})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"namespace": "prod"}
+	result, err := tool.Handler(ctx, req)
+	assert.NoError(t, err)
+	var out map[string]Defaults
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	assert.Equal(t, "prod", out["defaults"].Namespace)
+	assert.Equal(t, Defaults{Namespace: "prod"}, d.get("a"))
+	assert.Equal(t, Defaults{}, d.get(""))
+}
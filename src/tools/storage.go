@@ -0,0 +1,313 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// defaultStorageClassAnnotations mark the default StorageClass; the beta one is still set by some installers.
+var defaultStorageClassAnnotations = []string{"storageclass.kubernetes.io/is-default-class", "storageclass.beta.kubernetes.io/is-default-class"}
+
+// noStorageClass is the class name reported for volumes and claims without one.
+const noStorageClass = "(none)"
+
+// StorageClassSummary describes a StorageClass and the volumes provisioned from it.
+type StorageClassSummary struct {
+	Name                 string            `json:"name"`
+	Provisioner          string            `json:"provisioner"`
+	Default              bool              `json:"default"`
+	AllowVolumeExpansion bool              `json:"allowVolumeExpansion"`
+	ReclaimPolicy        string            `json:"reclaimPolicy,omitempty"`
+	VolumeBindingMode    string            `json:"volumeBindingMode,omitempty"`
+	AllowedTopologies    []string          `json:"allowedTopologies,omitempty"`
+	Parameters           map[string]string `json:"parameters,omitempty"`
+}
+
+// VolumeClassSummary counts the PersistentVolumes of one StorageClass by phase and zone.
+type VolumeClassSummary struct {
+	StorageClass string         `json:"storageClass"`
+	Volumes      int            `json:"volumes"`
+	Capacity     string         `json:"capacity"`
+	Phases       map[string]int `json:"phases"`
+	Zones        map[string]int `json:"zones,omitempty"`
+}
+
+// VolumeProblem is a PersistentVolume that is Released, Failed or otherwise not usable.
+type VolumeProblem struct {
+	Name          string `json:"name"`
+	StorageClass  string `json:"storageClass"`
+	Phase         string `json:"phase"`
+	Claim         string `json:"claim,omitempty"`
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// UnboundClaim is a PersistentVolumeClaim that is not bound, with the most likely reason.
+type UnboundClaim struct {
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	StorageClass string `json:"storageClass"`
+	Request      string `json:"request,omitempty"`
+	Phase        string `json:"phase"`
+	Age          string `json:"age"`
+	Reason       string `json:"reason,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
+// StorageReportTool reports StorageClasses, PersistentVolumes and unbound claims.
+type StorageReportTool struct {
+	client Client
+}
+
+// NewStorageReportTool creates a new StorageReportTool with the provided Kubernetes client.
+func NewStorageReportTool(client Client) *StorageReportTool {
+	return &StorageReportTool{client: client}
+}
+
+// Tool returns the MCP tool definition for storage_report.
+func (s *StorageReportTool) Tool() mcp.Tool {
+	return mcp.NewTool("storage_report",
+		mcp.WithDescription("Report on persistent storage in one call: StorageClasses with their provisioner, default flag, expansion support, reclaim policy, binding mode and allowed topologies; PersistentVolumes by class, phase and zone with Released or Failed volumes listed; and PersistentVolumeClaims that are not bound, with the reason from their events"),
+		mcp.WithString("namespace",
+			mcp.Description("Only report claims in this namespace (default: all namespaces)"),
+		),
+	)
+}
+
+// Handler gathers StorageClasses, volumes, claims and claim events.
+func (s *StorageReportTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace, _ := req.Params.Arguments["namespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return nil, fmt.Errorf("failed to parse and validate storage_report params: invalid namespace: %w", err)
+	}
+
+	clientset, err := s.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	classList, err := clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list StorageClasses: %w", err)
+	}
+	pvList, err := clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PersistentVolumes: %w", err)
+	}
+	pvcList, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PersistentVolumeClaims: %w", err)
+	}
+
+	classes := make([]StorageClassSummary, 0, len(classList.Items))
+	for i := range classList.Items {
+		classes = append(classes, storageClassSummary(&classList.Items[i]))
+	}
+	volumes, problems := summarizeVolumes(pvList.Items)
+
+	var unbound []UnboundClaim
+	if hasUnboundClaims(pvcList.Items) {
+		// One list of claim events serves every unbound claim; they are sorted newest first.
+		events, err := listEventsMatching(ctx, clientset, namespace, fields.Set{"involvedObject.kind": "PersistentVolumeClaim"})
+		if err != nil {
+			return nil, err
+		}
+		unbound = unboundClaims(pvcList.Items, classes, events, time.Now())
+	}
+
+	boundClaims := len(pvcList.Items) - len(unbound)
+	out, err := json.Marshal(map[string]any{
+		"storageClasses": classes,
+		"volumes":        volumes,
+		"volumeProblems": problems,
+		"claims":         map[string]int{"total": len(pvcList.Items), "bound": boundClaims, "unbound": len(unbound)},
+		"unboundClaims":  unbound,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// storageClassSummary describes a StorageClass, including the zones it may provision in.
+func storageClassSummary(sc *storagev1.StorageClass) StorageClassSummary {
+	summary := StorageClassSummary{
+		Name:        sc.Name,
+		Provisioner: sc.Provisioner,
+		Parameters:  sc.Parameters,
+	}
+	for _, annotation := range defaultStorageClassAnnotations {
+		if sc.Annotations[annotation] == "true" {
+			summary.Default = true
+		}
+	}
+	if sc.AllowVolumeExpansion != nil {
+		summary.AllowVolumeExpansion = *sc.AllowVolumeExpansion
+	}
+	if sc.ReclaimPolicy != nil {
+		summary.ReclaimPolicy = string(*sc.ReclaimPolicy)
+	}
+	if sc.VolumeBindingMode != nil {
+		summary.VolumeBindingMode = string(*sc.VolumeBindingMode)
+	}
+	for _, term := range sc.AllowedTopologies {
+		for _, expr := range term.MatchLabelExpressions {
+			summary.AllowedTopologies = append(summary.AllowedTopologies, expr.Key+" in ("+strings.Join(expr.Values, ", ")+")")
+		}
+	}
+	return summary
+}
+
+// summarizeVolumes groups PersistentVolumes by StorageClass and lists those that are Released,
+// Failed or Pending.
+func summarizeVolumes(pvs []corev1.PersistentVolume) ([]VolumeClassSummary, []VolumeProblem) {
+	byClass := map[string]*VolumeClassSummary{}
+	capacity := map[string]*resource.Quantity{}
+	var problems []VolumeProblem
+	for i := range pvs {
+		pv := &pvs[i]
+		class := pv.Spec.StorageClassName
+		if class == "" {
+			class = noStorageClass
+		}
+		summary, ok := byClass[class]
+		if !ok {
+			summary = &VolumeClassSummary{StorageClass: class, Phases: map[string]int{}}
+			byClass[class] = summary
+			capacity[class] = resource.NewQuantity(0, resource.BinarySI)
+		}
+		summary.Volumes++
+		summary.Phases[string(pv.Status.Phase)]++
+		if size, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+			capacity[class].Add(size)
+		}
+		for _, zone := range volumeZones(pv) {
+			if summary.Zones == nil {
+				summary.Zones = map[string]int{}
+			}
+			summary.Zones[zone]++
+		}
+
+		switch pv.Status.Phase {
+		case corev1.VolumeReleased, corev1.VolumeFailed, corev1.VolumePending:
+			problem := VolumeProblem{
+				Name:          pv.Name,
+				StorageClass:  class,
+				Phase:         string(pv.Status.Phase),
+				ReclaimPolicy: string(pv.Spec.PersistentVolumeReclaimPolicy),
+				Message:       pv.Status.Message,
+			}
+			if ref := pv.Spec.ClaimRef; ref != nil {
+				problem.Claim = ref.Namespace + "/" + ref.Name
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	volumes := make([]VolumeClassSummary, 0, len(byClass))
+	for class, summary := range byClass {
+		summary.Capacity = capacity[class].String()
+		volumes = append(volumes, *summary)
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].StorageClass < volumes[j].StorageClass })
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Name < problems[j].Name })
+	return volumes, problems
+}
+
+// volumeZones returns the zones a PersistentVolume is pinned to by its node affinity.
+func volumeZones(pv *corev1.PersistentVolume) []string {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return nil
+	}
+	var zones []string
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			// CSI drivers publish their own zone key, e.g. topology.ebs.csi.aws.com/zone.
+			if expr.Key == corev1.LabelTopologyZone || expr.Key == corev1.LabelFailureDomainBetaZone || strings.HasSuffix(expr.Key, "/zone") {
+				zones = append(zones, expr.Values...)
+			}
+		}
+	}
+	return zones
+}
+
+func hasUnboundClaims(pvcs []corev1.PersistentVolumeClaim) bool {
+	for i := range pvcs {
+		if pvcs[i].Status.Phase != corev1.ClaimBound {
+			return true
+		}
+	}
+	return false
+}
+
+// unboundClaims lists the claims that are not bound. The reason is taken from the newest event of
+// the claim, or derived from its StorageClass when there is no event.
+func unboundClaims(pvcs []corev1.PersistentVolumeClaim, classes []StorageClassSummary, events []EventSummary, now time.Time) []UnboundClaim {
+	byName := map[string]StorageClassSummary{}
+	defaultClass := ""
+	for _, c := range classes {
+		byName[c.Name] = c
+		if c.Default {
+			defaultClass = c.Name
+		}
+	}
+	newestEvent := map[string]EventSummary{}
+	for _, ev := range events {
+		key := ev.Namespace + "/" + strings.TrimPrefix(ev.Object, "PersistentVolumeClaim/")
+		if _, ok := newestEvent[key]; !ok {
+			newestEvent[key] = ev
+		}
+	}
+
+	var unbound []UnboundClaim
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		if pvc.Status.Phase == corev1.ClaimBound {
+			continue
+		}
+		claim := UnboundClaim{
+			Namespace:    pvc.Namespace,
+			Name:         pvc.Name,
+			StorageClass: noStorageClass,
+			Phase:        string(pvc.Status.Phase),
+			Age:          now.Sub(pvc.CreationTimestamp.Time).Round(time.Second).String(),
+		}
+		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+			claim.StorageClass = *pvc.Spec.StorageClassName
+		}
+		if size, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			claim.Request = size.String()
+		}
+
+		class, classExists := byName[claim.StorageClass]
+		switch ev, ok := newestEvent[pvc.Namespace+"/"+pvc.Name]; {
+		case ok:
+			claim.Reason, claim.Message = ev.Reason, ev.Message
+		case claim.StorageClass != noStorageClass && !classExists:
+			claim.Reason, claim.Message = "StorageClassNotFound", fmt.Sprintf("StorageClass %s does not exist", claim.StorageClass)
+		case claim.StorageClass == noStorageClass && pvc.Spec.StorageClassName == nil && defaultClass == "":
+			claim.Reason, claim.Message = "NoDefaultStorageClass", "the claim sets no StorageClass and the cluster has no default one"
+		case classExists && class.VolumeBindingMode == string(storagev1.VolumeBindingWaitForFirstConsumer):
+			claim.Reason, claim.Message = "WaitForFirstConsumer", "the volume is provisioned once a pod using the claim is scheduled"
+		}
+		unbound = append(unbound, claim)
+	}
+	sort.SliceStable(unbound, func(i, j int) bool {
+		if unbound[i].Namespace != unbound[j].Namespace {
+			return unbound[i].Namespace < unbound[j].Namespace
+		}
+		return unbound[i].Name < unbound[j].Name
+	})
+	return unbound
+}
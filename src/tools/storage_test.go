@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStorageClassSummary(t *testing.T) {
+	expansion, reclaim, binding := true, corev1.PersistentVolumeReclaimDelete, storagev1.VolumeBindingWaitForFirstConsumer
+	sc := &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "gp3", Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}},
+		Provisioner:          "ebs.csi.aws.com",
+		AllowVolumeExpansion: &expansion,
+		ReclaimPolicy:        &reclaim,
+		VolumeBindingMode:    &binding,
+		AllowedTopologies: []corev1.TopologySelectorTerm{{MatchLabelExpressions: []corev1.TopologySelectorLabelRequirement{
+			{Key: "topology.ebs.csi.aws.com/zone", Values: []string{"us-east-1a", "us-east-1b"}},
+		}}},
+	}
+
+	assert.Equal(t, StorageClassSummary{
+		Name:                 "gp3",
+		Provisioner:          "ebs.csi.aws.com",
+		Default:              true,
+		AllowVolumeExpansion: true,
+		ReclaimPolicy:        "Delete",
+		VolumeBindingMode:    "WaitForFirstConsumer",
+		AllowedTopologies:    []string{"topology.ebs.csi.aws.com/zone in (us-east-1a, us-east-1b)"},
+	}, storageClassSummary(sc))
+	assert.Equal(t, StorageClassSummary{Name: "local", Provisioner: "kubernetes.io/no-provisioner"}, storageClassSummary(&storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "local"},
+		Provisioner: "kubernetes.io/no-provisioner",
+	}))
+}
+
+func TestSummarizeVolumes(t *testing.T) {
+	volume := func(name, class string, size string, phase corev1.PersistentVolumePhase, zone string) corev1.PersistentVolume {
+		pv := corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				StorageClassName:              class,
+				Capacity:                      corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			},
+			Status: corev1.PersistentVolumeStatus{Phase: phase},
+		}
+		if zone != "" {
+			pv.Spec.NodeAffinity = &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "topology.ebs.csi.aws.com/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{zone}}},
+			}}}}
+		}
+		return pv
+	}
+	released := volume("pv-c", "gp3", "5Gi", corev1.VolumeReleased, "us-east-1b")
+	released.Spec.ClaimRef = &corev1.ObjectReference{Namespace: "db", Name: "data-postgres-0"}
+
+	volumes, problems := summarizeVolumes([]corev1.PersistentVolume{
+		volume("pv-a", "gp3", "10Gi", corev1.VolumeBound, "us-east-1a"),
+		volume("pv-b", "gp3", "10Gi", corev1.VolumeBound, "us-east-1a"),
+		released,
+		volume("pv-d", "", "1Gi", corev1.VolumeAvailable, ""),
+	})
+
+	assert.Equal(t, []VolumeClassSummary{
+		{StorageClass: noStorageClass, Volumes: 1, Capacity: "1Gi", Phases: map[string]int{"Available": 1}},
+		{StorageClass: "gp3", Volumes: 3, Capacity: "25Gi", Phases: map[string]int{"Bound": 2, "Released": 1}, Zones: map[string]int{"us-east-1a": 2, "us-east-1b": 1}},
+	}, volumes)
+	assert.Equal(t, []VolumeProblem{
+		{Name: "pv-c", StorageClass: "gp3", Phase: "Released", Claim: "db/data-postgres-0", ReclaimPolicy: "Retain"},
+	}, problems)
+}
+
+func TestUnboundClaims(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	claim := func(name string, class *string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "app", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Minute))},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: class,
+				Resources:        corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		}
+	}
+	gp3, fast, gp2 := "gp3", "fast", "gp2"
+	bound := claim("bound", &gp3)
+	bound.Status.Phase = corev1.ClaimBound
+	classes := []StorageClassSummary{
+		{Name: "gp3", VolumeBindingMode: "WaitForFirstConsumer"},
+		{Name: "fast", VolumeBindingMode: "Immediate"},
+	}
+	events := []EventSummary{
+		{Reason: "ProvisioningFailed", Message: "quota exceeded", Object: "PersistentVolumeClaim/failing", Namespace: "app"},
+		{Reason: "Provisioning", Message: "started", Object: "PersistentVolumeClaim/failing", Namespace: "app"},
+	}
+
+	unbound := unboundClaims([]corev1.PersistentVolumeClaim{
+		bound,
+		claim("waiting", &gp3),
+		claim("failing", &fast),
+		claim("typo", &gp2),
+		claim("classless", nil),
+	}, classes, events, now)
+
+	assert.Len(t, unbound, 4)
+	reasons := map[string]string{}
+	for _, c := range unbound {
+		reasons[c.Name] = c.Reason
+		assert.Equal(t, "1Gi", c.Request)
+		assert.Equal(t, "2m0s", c.Age)
+	}
+	assert.Equal(t, map[string]string{
+		"classless": "NoDefaultStorageClass",
+		"failing":   "ProvisioningFailed",
+		"typo":      "StorageClassNotFound",
+		"waiting":   "WaitForFirstConsumer",
+	}, reasons)
+	assert.Equal(t, "classless", unbound[0].Name)
+	assert.Equal(t, noStorageClass, unbound[0].StorageClass)
+	assert.Equal(t, "quota exceeded", unbound[1].Message)
+}
@@ -5,20 +5,119 @@ import (
 )
 
 // RegisterTools registers all the tools with the MCP server.
-// It takes an MCP server instance and a Kubernetes client as parameters.
+// It takes an MCP server instance, a Kubernetes client and the session defaults store as parameters.
 // Each tool is created and added to the server with its corresponding handler.
 // This allows the server to handle requests for each tool defined in the tools package.
-func RegisterTools(s *server.MCPServer, client Client) {
-	tools := []Tools{
-		NewListTool(client),     // Register the list tool
-		NewLogTool(client),      // Register the log tool
-		NewDescribeTool(client), // Register the describe tool
-		NewRolloutTool(client),  // Register the new rollout tool
-		// NewChangeEnvTool(),              // Register the new change_env tool
-		// NewListGCPSecretTool(),          // Register the new list_gcp_secret tool
-		NewListIngressPathsTool(client), // Register the new list ingress paths tool
+// Calls of a session that selected a kubeconfig context with set_defaults run against tools built for
+// that context.
+func RegisterTools(s *server.MCPServer, client Client, defaults *SessionDefaults) {
+	build := func(client Client) []Tools { return newTools(s, client, defaults) }
+	contexts := newContextTools(build)
+	confirmTools := confirmToolsFromEnv()
+	confirm := newConfirmer()
+	for _, t := range build(client) {
+		tool := t.Tool()
+		handler := contexts.wrap(tool.Name, t.Handler)
+		if confirmTools[tool.Name] {
+			tool, handler = confirm.wrap(tool, handler)
+		}
+		defaults.register(tool)
+		s.AddTool(tool, handler)
 	}
-	for _, t := range tools {
-		s.AddTool(t.Tool(), t.Handler)
+}
+
+// newTools creates the tools around a Kubernetes client.
+func newTools(s *server.MCPServer, client Client, defaults *SessionDefaults) []Tools {
+	return []Tools{
+		NewListTool(client),                   // Register the list tool
+		NewLogTool(client),                    // Register the log tool
+		NewDescribeTool(client),               // Register the describe tool
+		NewRolloutTool(client),                // Register the new rollout tool
+		NewChangeEnvTool(client),              // Register the new change_env tool
+		NewSecretListTool(client),             // Register the new secret_list tool
+		NewListIngressPathsTool(client),       // Register the new list ingress paths tool
+		NewFindRouteTool(client),              // Register the find_route tool
+		NewRefreshDiscoveryTool(client),       // Register the refresh_discovery tool
+		NewCordonTool(client),                 // Register the cordon_node tool
+		NewUncordonTool(client),               // Register the uncordon_node tool
+		NewDrainTool(client),                  // Register the drain_node tool
+		NewCronJobControlTool(client),         // Register the cronjob_control tool
+		NewJobControlTool(client),             // Register the job_control tool
+		NewCSRManageTool(client),              // Register the csr_manage tool
+		NewCreateNamespaceTool(client),        // Register the create_namespace tool
+		NewConfigMapEditTool(client),          // Register the configmap_edit tool
+		NewK8sSecretTool(client),              // Register the k8s_secret tool
+		NewTopPodsTool(client),                // Register the top_pods tool
+		NewTopNodesTool(client),               // Register the top_nodes tool
+		NewClusterCapacityTool(client),        // Register the cluster_capacity tool
+		NewWhyPendingTool(client),             // Register the why_pending tool
+		NewDaemonSetCoverageTool(client),      // Register the daemonset_coverage tool
+		NewDiagnosePodTool(client),            // Register the diagnose_pod tool
+		NewNamespaceHealthTool(client),        // Register the namespace_health tool
+		NewClusterHealthTool(client),          // Register the cluster_health tool
+		NewControlPlaneStatusTool(client),     // Register the control_plane_status tool
+		NewListLeasesTool(client),             // Register the list_leases tool
+		NewFindPodsTool(client),               // Register the find_pods tool
+		NewRestartReportTool(client),          // Register the restart_report tool
+		NewProbeAuditTool(client),             // Register the probe_audit tool
+		NewSecurityAuditTool(client),          // Register the security_audit tool
+		NewResourcesAuditTool(client),         // Register the resources_audit tool
+		NewCostReportTool(client),             // Register the cost_report tool
+		NewListImagesTool(client),             // Register the list_images tool
+		NewVulnReportTool(client),             // Register the vuln_report tool
+		NewCheckImagePullTool(client),         // Register the check_image_pull tool
+		NewPDBCheckTool(client),               // Register the pdb_check tool
+		NewCheckServiceTool(client),           // Register the check_service tool
+		NewDNSHealthTool(client),              // Register the dns_health tool
+		NewValidateIngressTool(client),        // Register the validate_ingress tool
+		NewNetpolAnalyzeTool(client),          // Register the netpol_analyze tool
+		NewCanITool(client),                   // Register the can_i tool
+		NewWhoCanTool(client),                 // Register the who_can tool
+		NewSAAuditTool(client),                // Register the sa_audit tool
+		NewCertExpiryTool(client),             // Register the cert_expiry tool
+		NewWebhookAuditTool(client),           // Register the webhook_audit tool
+		NewVersionReportTool(client),          // Register the version_report tool
+		NewObjectTreeTool(client),             // Register the object_tree tool
+		NewExportNamespaceTool(client),        // Register the export_namespace tool
+		NewDiffEnvironmentsTool(client),       // Register the diff_environments tool
+		NewValidateManifestTool(client),       // Register the validate_manifest tool
+		NewGenerateManifestTool(client),       // Register the generate_manifest tool
+		NewDiffApplyTool(client),              // Register the diff_apply tool
+		NewWatchResourcesTool(client),         // Register the watch_resources tool
+		NewFluxReconcileTool(client),          // Register the flux_reconcile tool
+		NewFluxSuspendTool(client),            // Register the flux_suspend tool
+		NewFluxResumeTool(client),             // Register the flux_resume tool
+		NewFluxStatusTool(client),             // Register the flux_status tool
+		NewFluxTreeTool(client),               // Register the flux_tree tool
+		NewGitDriftTool(client),               // Register the git_drift tool
+		NewKustomizeBuildTool(client),         // Register the kustomize_build tool
+		NewIstioAnalyzeTool(client),           // Register the istio_analyze tool
+		NewCertManagerStatusTool(client),      // Register the certmanager_status tool
+		NewCertManagerRenewTool(client),       // Register the certmanager_renew tool
+		NewExternalSecretsStatusTool(client),  // Register the externalsecrets_status tool
+		NewExternalSecretsRefreshTool(client), // Register the externalsecrets_refresh tool
+		NewSealSecretTool(client),             // Register the seal_secret tool
+		NewKedaStatusTool(client),             // Register the keda_status tool
+		NewNodeProvisioningStatusTool(client), // Register the node_provisioning_status tool
+		NewStorageReportTool(client),          // Register the storage_report tool
+		NewPVCUsageTool(client),               // Register the pvc_usage tool
+		NewVeleroBackupsTool(client),          // Register the velero_backups tool
+		NewVeleroBackupCreateTool(client),     // Register the velero_backup_create tool
+		NewVeleroRestoreTool(client),          // Register the velero_restore tool
+		NewPolicyViolationsTool(client),       // Register the policy_violations tool
+		NewKnativeRollbackTool(client),        // Register the knative_rollback tool
+		NewHelmRollbackTool(client),           // Register the helm_rollback tool
+		NewHelmSetValuesTool(client),          // Register the helm_set_values tool
+		NewLokiQueryTool(client),              // Register the loki_query tool
+		NewGKEClusterInfoTool(),               // Register the gke_cluster_info tool
+		NewGCPLogsQueryTool(),                 // Register the gcp_logs_query tool
+		NewGCPListImageTagsTool(),             // Register the gcp_list_image_tags tool
+		NewSecretVersionsTool(client),         // Register the secret_versions tool
+		NewSecretDiffTool(client),             // Register the secret_diff tool
+		NewSecretRollbackTool(client),         // Register the secret_rollback tool
+		NewGCPSecretCreateTool(),              // Register the gcp_secret_create tool
+		NewGCPSecretDeleteTool(),              // Register the gcp_secret_delete tool
+		NewSetDefaultsTool(defaults),          // Register the set_defaults tool
+		NewBatchQueryTool(s),                  // Register the batch_query tool
 	}
 }
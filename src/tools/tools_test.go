@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/k4mrul/kubernetes-mcp/src/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterTools(t *testing.T) {
+	s := server.NewMCPServer("test", "0", server.WithToolCapabilities(false))
+	RegisterTools(s, FakeKubernetesClient{}, NewSessionDefaults())
+
+	message, err := json.Marshal(map[string]any{"jsonrpc": mcp.JSONRPC_VERSION, "id": 1, "method": string(mcp.MethodToolsList)})
+	require.NoError(t, err)
+	response, ok := s.HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	result, ok := response.Result.(mcp.ListToolsResult)
+	require.True(t, ok)
+
+	schemas := map[string]mcp.ToolInputSchema{}
+	for _, tool := range result.Tools {
+		schemas[tool.Name] = tool.InputSchema
+	}
+	for _, name := range []string{"change_env", "secret_list"} {
+		require.Contains(t, schemas, name)
+		assert.Contains(t, schemas[name].Properties, "backend")
+	}
+	for name := range schemas {
+		assert.True(t, policy.Classified(name), "tool %s must be classified as read or write in policy.Access", name)
+	}
+}
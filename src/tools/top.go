@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TopPodsInput represents the input for reporting pod resource usage.
+type TopPodsInput struct {
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+	SortBy        string `json:"sortBy,omitempty"`
+	Limit         int    `json:"limit,omitempty"`
+}
+
+// PodUsage reports live CPU/memory usage of a pod compared to its requests and limits.
+type PodUsage struct {
+	Name                 string  `json:"name"`
+	Namespace            string  `json:"namespace"`
+	CPU                  string  `json:"cpu"`
+	Memory               string  `json:"memory"`
+	CPUMillicores        int64   `json:"cpuMillicores"`
+	MemoryBytes          int64   `json:"memoryBytes"`
+	CPURequestPercent    float64 `json:"cpuRequestPercent,omitempty"`
+	CPULimitPercent      float64 `json:"cpuLimitPercent,omitempty"`
+	MemoryRequestPercent float64 `json:"memoryRequestPercent,omitempty"`
+	MemoryLimitPercent   float64 `json:"memoryLimitPercent,omitempty"`
+}
+
+// resourceTotals holds CPU in millicores and memory in bytes.
+type resourceTotals struct {
+	CPU    int64
+	Memory int64
+}
+
+// TopPodsTool reports live pod resource usage from the metrics.k8s.io API.
+type TopPodsTool struct {
+	client Client
+}
+
+// NewTopPodsTool creates a new TopPodsTool with the provided Kubernetes client.
+func NewTopPodsTool(client Client) *TopPodsTool {
+	return &TopPodsTool{client: client}
+}
+
+// Tool returns the MCP tool definition for top_pods.
+func (t *TopPodsTool) Tool() mcp.Tool {
+	return mcp.NewTool("top_pods",
+		mcp.WithDescription("Show live CPU and memory usage of pods from the metrics API (like 'kubectl top pods'), with utilization as a percentage of requests and limits. Requires metrics-server."),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to report on (leave empty for all namespaces)"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Filter pods by label selector (e.g., 'app=nginx')"),
+		),
+		mcp.WithString("sortBy",
+			mcp.Description("Sort by 'cpu' or 'memory' usage, highest first (default: cpu)"),
+			mcp.Enum("cpu", "memory"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of pods to return (default: 20)"),
+		),
+	)
+}
+
+// Handler lists pod metrics and joins them with the pods' requests and limits.
+func (t *TopPodsTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateTopPodsParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate top pods params: %w", err)
+	}
+
+	ri, err := t.client.ResourceInterface(metricsGVR("pods"), true, input.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource interface: %w", err)
+	}
+	metricsList, err := ri.List(ctx, metav1.ListOptions{LabelSelector: input.LabelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod metrics (is metrics-server installed?): %w", err)
+	}
+
+	clientset, err := t.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(input.Namespace).List(ctx, metav1.ListOptions{LabelSelector: input.LabelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	podsByKey := make(map[string]*corev1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		podsByKey[pod.Namespace+"/"+pod.Name] = pod
+	}
+
+	usages := make([]PodUsage, 0, len(metricsList.Items))
+	for _, item := range metricsList.Items {
+		used := podMetricsUsage(&item)
+		usage := PodUsage{
+			Name:          item.GetName(),
+			Namespace:     item.GetNamespace(),
+			CPU:           formatCPU(used.CPU),
+			Memory:        formatMemory(used.Memory),
+			CPUMillicores: used.CPU,
+			MemoryBytes:   used.Memory,
+		}
+		if pod, ok := podsByKey[item.GetNamespace()+"/"+item.GetName()]; ok {
+			requests, limits := podRequestsAndLimits(pod)
+			usage.CPURequestPercent = percentOf(used.CPU, requests.CPU)
+			usage.CPULimitPercent = percentOf(used.CPU, limits.CPU)
+			usage.MemoryRequestPercent = percentOf(used.Memory, requests.Memory)
+			usage.MemoryLimitPercent = percentOf(used.Memory, limits.Memory)
+		}
+		usages = append(usages, usage)
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if input.SortBy == "memory" {
+			return usages[i].MemoryBytes > usages[j].MemoryBytes
+		}
+		return usages[i].CPUMillicores > usages[j].CPUMillicores
+	})
+	total := len(usages)
+	if input.Limit > 0 && len(usages) > input.Limit {
+		usages = usages[:input.Limit]
+	}
+
+	result := map[string]any{
+		"pods":       usages,
+		"totalPods":  total,
+		"sortedBy":   input.SortBy,
+		"namespace":  input.Namespace,
+		"selector":   input.LabelSelector,
+		"truncated":  total > len(usages),
+		"metricsAPI": metricsGVR("pods").GroupVersion().String(),
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// metricsGVR returns the metrics.k8s.io resource for pods or nodes.
+func metricsGVR(resourceName string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: resourceName}
+}
+
+// podMetricsUsage sums the usage of all containers in a PodMetrics object.
+func podMetricsUsage(item *unstructured.Unstructured) resourceTotals {
+	var totals resourceTotals
+	containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+	for _, c := range containers {
+		cMap, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		usage, _, _ := unstructured.NestedStringMap(cMap, "usage")
+		totals.add(usageTotals(usage))
+	}
+	return totals
+}
+
+// usageTotals parses a metrics usage map such as {"cpu": "250m", "memory": "128Mi"}.
+func usageTotals(usage map[string]string) resourceTotals {
+	var totals resourceTotals
+	if q, err := resource.ParseQuantity(usage["cpu"]); err == nil {
+		totals.CPU = q.MilliValue()
+	}
+	if q, err := resource.ParseQuantity(usage["memory"]); err == nil {
+		totals.Memory = q.Value()
+	}
+	return totals
+}
+
+// podRequestsAndLimits sums the requests and limits of a pod's app containers.
+func podRequestsAndLimits(pod *corev1.Pod) (resourceTotals, resourceTotals) {
+	var requests, limits resourceTotals
+	for _, c := range pod.Spec.Containers {
+		requests.add(resourceListTotals(c.Resources.Requests))
+		limits.add(resourceListTotals(c.Resources.Limits))
+	}
+	return requests, limits
+}
+
+// resourceListTotals extracts CPU and memory from a ResourceList.
+func resourceListTotals(list corev1.ResourceList) resourceTotals {
+	var totals resourceTotals
+	if q, ok := list[corev1.ResourceCPU]; ok {
+		totals.CPU = q.MilliValue()
+	}
+	if q, ok := list[corev1.ResourceMemory]; ok {
+		totals.Memory = q.Value()
+	}
+	return totals
+}
+
+func (r *resourceTotals) add(other resourceTotals) {
+	r.CPU += other.CPU
+	r.Memory += other.Memory
+}
+
+// percentOf returns used/total as a percentage rounded to one decimal, or 0 when total is unknown.
+func percentOf(used, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(float64(used)/float64(total)*1000) / 10
+}
+
+// formatCPU renders millicores the way kubectl top does, e.g. "250m".
+func formatCPU(millicores int64) string {
+	return fmt.Sprintf("%dm", millicores)
+}
+
+// formatMemory renders bytes as mebibytes, e.g. "128Mi".
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
+
+// parseAndValidateTopPodsParams validates and parses the input parameters.
+func parseAndValidateTopPodsParams(args map[string]any) (*TopPodsInput, error) {
+	input := &TopPodsInput{SortBy: "cpu", Limit: 20}
+
+	if ns, ok := args["namespace"].(string); ok {
+		input.Namespace = ns
+		if err := validation.ValidateNamespace(input.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+	}
+
+	if selector, ok := args["labelSelector"].(string); ok {
+		input.LabelSelector = selector
+		if err := validation.ValidateLabelSelector(input.LabelSelector); err != nil {
+			return nil, fmt.Errorf("invalid labelSelector: %w", err)
+		}
+	}
+
+	if sortBy, ok := args["sortBy"].(string); ok && sortBy != "" {
+		input.SortBy = strings.ToLower(sortBy)
+	}
+	if input.SortBy != "cpu" && input.SortBy != "memory" {
+		return nil, fmt.Errorf("sortBy must be 'cpu' or 'memory'")
+	}
+
+	if limit, ok := args["limit"].(float64); ok && limit > 0 {
+		input.Limit = int(limit)
+	}
+
+	return input, nil
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPodMetricsUsage(t *testing.T) {
+	item := &unstructured.Unstructured{Object: map[string]any{
+		"containers": []any{
+			map[string]any{"name": "app", "usage": map[string]any{"cpu": "250m", "memory": "128Mi"}},
+			map[string]any{"name": "sidecar", "usage": map[string]any{"cpu": "1500000n", "memory": "1024Ki"}},
+		},
+	}}
+
+	totals := podMetricsUsage(item)
+
+	assert.Equal(t, int64(252), totals.CPU)
+	assert.Equal(t, int64(129*1024*1024), totals.Memory)
+}
+
+func TestPodRequestsAndLimits(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		}},
+		{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		}},
+	}}}
+
+	requests, limits := podRequestsAndLimits(pod)
+
+	assert.Equal(t, resourceTotals{CPU: 1100, Memory: 64 * 1024 * 1024}, requests)
+	assert.Equal(t, resourceTotals{CPU: 500}, limits)
+}
+
+func TestPercentOf(t *testing.T) {
+	assert.Equal(t, 50.0, percentOf(50, 100))
+	assert.Equal(t, 33.3, percentOf(1, 3))
+	assert.Equal(t, 0.0, percentOf(10, 0))
+}
+
+func TestParseAndValidateTopPodsParams(t *testing.T) {
+	input, err := parseAndValidateTopPodsParams(map[string]any{})
+	assert.NoError(t, err)
+	assert.Equal(t, "cpu", input.SortBy)
+	assert.Equal(t, 20, input.Limit)
+
+	input, err = parseAndValidateTopPodsParams(map[string]any{"sortBy": "Memory", "limit": float64(5)})
+	assert.NoError(t, err)
+	assert.Equal(t, "memory", input.SortBy)
+	assert.Equal(t, 5, input.Limit)
+
+	_, err = parseAndValidateTopPodsParams(map[string]any{"sortBy": "disk"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,269 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// validateFieldManager owns the dry-run applies; nothing is persisted under it.
+	validateFieldManager = "kubernetes-mcp-validate"
+	maxManifestBytes     = 1 << 20
+	maxManifestDocuments = 100
+)
+
+// podTemplatePaths locate the pod template of the kinds that run pods, by Kind.
+var podTemplatePaths = map[string][]string{
+	"Pod":         nil,
+	"Deployment":  {"spec", "template"},
+	"StatefulSet": {"spec", "template"},
+	"DaemonSet":   {"spec", "template"},
+	"ReplicaSet":  {"spec", "template"},
+	"Job":         {"spec", "template"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template"},
+}
+
+// ManifestDocumentResult is the outcome of validating one document of a manifest.
+type ManifestDocumentResult struct {
+	Index      int      `json:"index"`
+	APIVersion string   `json:"apiVersion,omitempty"`
+	Kind       string   `json:"kind,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Valid      bool     `json:"valid"`
+	Errors     []string `json:"errors,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// ValidateManifestInput represents the input for validate_manifest.
+type ValidateManifestInput struct {
+	Manifest   string `json:"manifest"`
+	Namespace  string `json:"namespace"`
+	ServerSide bool   `json:"serverSide"`
+}
+
+// ValidateManifestTool checks manifests against the cluster's schemas and common best practices.
+type ValidateManifestTool struct {
+	client Client
+}
+
+// NewValidateManifestTool creates a new ValidateManifestTool with the provided Kubernetes client.
+func NewValidateManifestTool(client Client) *ValidateManifestTool {
+	return &ValidateManifestTool{client: client}
+}
+
+// Tool returns the MCP tool definition for validate_manifest.
+func (v *ValidateManifestTool) Tool() mcp.Tool {
+	return mcp.NewTool("validate_manifest",
+		mcp.WithDescription("Validate a YAML manifest before applying it: each document is checked against the kinds the cluster serves and, with a server-side dry-run apply, against its OpenAPI and CRD schemas and admission webhooks. Workloads are also linted for images without a tag or tagged latest, containers without resource requests or limits, and missing readiness or liveness probes. Nothing is changed in the cluster"),
+		mcp.WithString("manifest",
+			mcp.Required(),
+			mcp.Description("YAML or JSON manifest; several documents are separated by ---"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for documents that do not set one (default: default)"),
+		),
+		mcp.WithBoolean("serverSide",
+			mcp.Description("Validate with a server-side dry-run apply; set false to only check kinds and lint (default: true)"),
+		),
+	)
+}
+
+// Handler validates every document and reports errors and warnings per document.
+func (v *ValidateManifestTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateValidateManifestParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate validate_manifest params: %w", err)
+	}
+	mapper, err := v.client.RESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+
+	results := []ManifestDocumentResult{}
+	for i, doc := range yamlDocumentSeparator.Split(input.Manifest, -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		if len(results) == maxManifestDocuments {
+			return nil, fmt.Errorf("manifest has more than %d documents", maxManifestDocuments)
+		}
+		obj := map[string]any{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			results = append(results, ManifestDocumentResult{Index: i, Errors: []string{fmt.Sprintf("invalid YAML: %v", err)}})
+			continue
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		results = append(results, v.validateDocument(ctx, mapper, i, &unstructured.Unstructured{Object: obj}, input))
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("manifest has no documents")
+	}
+
+	invalid, warnings := 0, 0
+	for _, r := range results {
+		if !r.Valid {
+			invalid++
+		}
+		warnings += len(r.Warnings)
+	}
+	out, err := json.Marshal(map[string]any{
+		"documents":  results,
+		"valid":      invalid == 0,
+		"invalid":    invalid,
+		"warnings":   warnings,
+		"serverSide": input.ServerSide,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// validateDocument checks one object: required fields, whether the cluster serves its kind, the
+// dry-run apply and the workload lint.
+func (v *ValidateManifestTool) validateDocument(ctx context.Context, mapper meta.RESTMapper, index int, obj *unstructured.Unstructured, input *ValidateManifestInput) ManifestDocumentResult {
+	result := ManifestDocumentResult{Index: index, APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(), Name: obj.GetName()}
+	if result.APIVersion == "" || result.Kind == "" {
+		result.Errors = append(result.Errors, "apiVersion and kind are required")
+		return result
+	}
+	if result.Name == "" {
+		result.Errors = append(result.Errors, "metadata.name is required")
+	}
+	result.Warnings = lintManifestObject(obj)
+
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s is not served by the cluster: %v", gvk.String(), err))
+		return result
+	}
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if namespaced {
+		result.Namespace = obj.GetNamespace()
+		if result.Namespace == "" {
+			result.Namespace = input.Namespace
+		}
+	} else if obj.GetNamespace() != "" {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s is cluster-scoped; metadata.namespace is ignored", result.Kind))
+	}
+
+	if input.ServerSide && result.Name != "" {
+		ri, err := v.client.ResourceInterface(mapping.Resource, namespaced, result.Namespace)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to create resource interface: %v", err))
+		} else {
+			_, err := ri.Apply(ctx, result.Name, obj, metav1.ApplyOptions{
+				DryRun:       []string{metav1.DryRunAll},
+				FieldManager: validateFieldManager,
+				Force:        true,
+			})
+			result.Errors = append(result.Errors, dryRunErrors(err)...)
+		}
+	}
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// dryRunErrors turns the error of a dry-run apply into messages, one per invalid field.
+func dryRunErrors(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Details != nil && len(status.Status().Details.Causes) > 0 {
+		var messages []string
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Field != "" {
+				messages = append(messages, cause.Field+": "+cause.Message)
+			} else {
+				messages = append(messages, cause.Message)
+			}
+		}
+		return messages
+	}
+	return []string{err.Error()}
+}
+
+// lintManifestObject warns about images without a pinned tag, containers without resource
+// requests or limits, and long-running containers without probes.
+func lintManifestObject(obj *unstructured.Unstructured) []string {
+	path, ok := podTemplatePaths[obj.GetKind()]
+	if !ok {
+		return nil
+	}
+	var spec corev1.PodSpec
+	raw, found, _ := unstructured.NestedMap(obj.Object, append(path, "spec")...)
+	if !found {
+		return nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &spec); err != nil {
+		return []string{fmt.Sprintf("pod spec could not be read: %v", err)}
+	}
+
+	runsToCompletion := obj.GetKind() == "Job" || obj.GetKind() == "CronJob"
+	var warnings []string
+	containers := append([]corev1.Container{}, spec.InitContainers...)
+	for i, c := range append(containers, spec.Containers...) {
+		where := fmt.Sprintf("container %s", c.Name)
+		if i < len(spec.InitContainers) {
+			where = fmt.Sprintf("init container %s", c.Name)
+		}
+		if ref := parseImageRef(c.Image); ref.Digest == "" && ref.Tag == "latest" {
+			warnings = append(warnings, fmt.Sprintf("%s: image %s uses the latest tag; pin a version or digest", where, c.Image))
+		}
+		if len(c.Resources.Requests) == 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: no resource requests; the scheduler cannot place it reliably", where))
+		}
+		if c.Resources.Limits.Memory().IsZero() {
+			warnings = append(warnings, fmt.Sprintf("%s: no memory limit", where))
+		}
+		if runsToCompletion || i < len(spec.InitContainers) {
+			continue
+		}
+		if c.ReadinessProbe == nil {
+			warnings = append(warnings, fmt.Sprintf("%s: no readiness probe; it receives traffic before it is ready", where))
+		}
+		if c.LivenessProbe == nil {
+			warnings = append(warnings, fmt.Sprintf("%s: no liveness probe; a hung process is not restarted", where))
+		}
+	}
+	return warnings
+}
+
+func parseAndValidateValidateManifestParams(args map[string]any) (*ValidateManifestInput, error) {
+	input := &ValidateManifestInput{Namespace: "default", ServerSide: true}
+	input.Manifest, _ = args["manifest"].(string)
+	if strings.TrimSpace(input.Manifest) == "" {
+		return nil, fmt.Errorf("manifest is required")
+	}
+	if len(input.Manifest) > maxManifestBytes {
+		return nil, fmt.Errorf("manifest is larger than %d bytes", maxManifestBytes)
+	}
+	if namespace, ok := args["namespace"].(string); ok && namespace != "" {
+		if err := validation.ValidateNamespace(namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = namespace
+	}
+	if serverSide, ok := args["serverSide"].(bool); ok {
+		input.ServerSide = serverSide
+	}
+	return input, nil
+}
@@ -0,0 +1,212 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+)
+
+// stubApplyInterface records dry-run applies and fails those of objects named "invalid".
+type stubApplyInterface struct {
+	dynamic.ResourceInterface
+	namespace string
+	applied   *[]string
+}
+
+func (s *stubApplyInterface) Apply(ctx context.Context, name string, obj *unstructured.Unstructured, opts metav1.ApplyOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	*s.applied = append(*s.applied, s.namespace+"/"+name)
+	if len(opts.DryRun) == 0 {
+		panic("apply without dry run")
+	}
+	if name == "invalid" {
+		return nil, apierrors.NewInvalid(obj.GroupVersionKind().GroupKind(), name, field.ErrorList{
+			field.Invalid(field.NewPath("spec", "replicas"), "two", "must be an integer"),
+		})
+	}
+	return obj, nil
+}
+
+type fakeValidateClient struct {
+	FakeKubernetesClient
+	applied []string
+}
+
+func (f *fakeValidateClient) RESTMapper() (meta.RESTMapper, error) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	return mapper, nil
+}
+
+func (f *fakeValidateClient) ResourceInterface(gvr schema.GroupVersionResource, namespaced bool, ns string) (dynamic.ResourceInterface, error) {
+	return &stubApplyInterface{namespace: ns, applied: &f.applied}, nil
+}
+
+const validDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.27
+        resources:
+          requests: {cpu: 100m}
+          limits: {memory: 128Mi}
+        readinessProbe: {httpGet: {path: /, port: 80}}
+        livenessProbe: {httpGet: {path: /, port: 80}}
+`
+
+func runValidateManifest(t *testing.T, client Client, args map[string]any) map[string]any {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	result, err := NewValidateManifestTool(client).Handler(context.Background(), req)
+	assert.NoError(t, err)
+	var out map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+	return out
+}
+
+func TestValidateManifest(t *testing.T) {
+	client := &fakeValidateClient{}
+	manifest := validDeployment + `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: invalid
+  namespace: prod
+spec:
+  replicas: two
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: w
+---
+kind: [broken
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: team
+  namespace: default
+`
+	out := runValidateManifest(t, client, map[string]any{"manifest": manifest, "namespace": "staging"})
+
+	assert.Equal(t, false, out["valid"])
+	assert.Equal(t, float64(3), out["invalid"])
+	docs := out["documents"].([]any)
+	assert.Len(t, docs, 5)
+
+	web := docs[0].(map[string]any)
+	assert.Equal(t, true, web["valid"])
+	assert.Equal(t, "staging", web["namespace"])
+	assert.Nil(t, web["warnings"])
+
+	invalid := docs[1].(map[string]any)
+	assert.Equal(t, false, invalid["valid"])
+	assert.Equal(t, []any{"spec.replicas: Invalid value: \"two\": must be an integer"}, invalid["errors"])
+
+	widget := docs[2].(map[string]any)
+	assert.Contains(t, widget["errors"].([]any)[0], "example.com/v1, Kind=Widget is not served by the cluster")
+
+	broken := docs[3].(map[string]any)
+	assert.Equal(t, float64(3), broken["index"])
+	assert.Contains(t, broken["errors"].([]any)[0], "invalid YAML")
+
+	ns := docs[4].(map[string]any)
+	assert.Equal(t, true, ns["valid"])
+	assert.Equal(t, []any{"Namespace is cluster-scoped; metadata.namespace is ignored"}, ns["warnings"])
+
+	assert.Equal(t, []string{"staging/web", "prod/invalid", "/team"}, client.applied)
+}
+
+func TestValidateManifestWithoutServerSide(t *testing.T) {
+	client := &fakeValidateClient{}
+	out := runValidateManifest(t, client, map[string]any{"manifest": validDeployment, "serverSide": false})
+	assert.Equal(t, true, out["valid"])
+	assert.Empty(t, client.applied)
+}
+
+func TestLintManifestObject(t *testing.T) {
+	testCases := []struct {
+		name     string
+		yaml     string
+		expected []string
+	}{
+		{
+			name: "deployment without probes, resources or tag",
+			yaml: `apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web}
+spec:
+  template:
+    spec:
+      initContainers:
+      - {name: init, image: "busybox@sha256:0123", resources: {requests: {cpu: 10m}, limits: {memory: 16Mi}}}
+      containers:
+      - {name: web, image: nginx}
+`,
+			expected: []string{
+				"container web: image nginx uses the latest tag; pin a version or digest",
+				"container web: no resource requests; the scheduler cannot place it reliably",
+				"container web: no memory limit",
+				"container web: no readiness probe; it receives traffic before it is ready",
+				"container web: no liveness probe; a hung process is not restarted",
+			},
+		},
+		{
+			name: "job needs no probes",
+			yaml: `apiVersion: batch/v1
+kind: Job
+metadata: {name: migrate}
+spec:
+  template:
+    spec:
+      containers:
+      - {name: migrate, image: "app:latest", resources: {requests: {cpu: 10m}, limits: {memory: 16Mi}}}
+`,
+			expected: []string{"container migrate: image app:latest uses the latest tag; pin a version or digest"},
+		},
+		{
+			name:     "kind without pods",
+			yaml:     "apiVersion: v1\nkind: ConfigMap\nmetadata: {name: c}\n",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			objects, err := parseYAMLObjects([]byte(tc.yaml))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, lintManifestObject(objects[0]))
+		})
+	}
+}
+
+func TestParseAndValidateValidateManifestParams(t *testing.T) {
+	_, err := parseAndValidateValidateManifestParams(map[string]any{})
+	assert.EqualError(t, err, "manifest is required")
+
+	input, err := parseAndValidateValidateManifestParams(map[string]any{"manifest": validDeployment})
+	assert.NoError(t, err)
+	assert.Equal(t, "default", input.Namespace)
+	assert.True(t, input.ServerSide)
+
+	_, err = parseAndValidateValidateManifestParams(map[string]any{"manifest": validDeployment, "namespace": "Bad_NS"})
+	assert.Error(t, err)
+}
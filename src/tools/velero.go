@@ -0,0 +1,645 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Velero kinds; the served version is resolved through the RESTMapper.
+var (
+	veleroBackupGK   = schema.GroupKind{Group: "velero.io", Kind: "Backup"}
+	veleroRestoreGK  = schema.GroupKind{Group: "velero.io", Kind: "Restore"}
+	veleroScheduleGK = schema.GroupKind{Group: "velero.io", Kind: "Schedule"}
+)
+
+const (
+	// defaultVeleroNamespace is where Velero is installed by default and where its objects must be created.
+	defaultVeleroNamespace = "velero"
+	// veleroScheduleNameLabel links a Backup to the Schedule that created it.
+	veleroScheduleNameLabel = "velero.io/schedule-name"
+	// veleroTimestampFormat is the suffix the velero CLI appends to generated backup and restore names.
+	veleroTimestampFormat = "20060102150405"
+)
+
+// VeleroBackup is the status of one Velero Backup.
+type VeleroBackup struct {
+	Name               string   `json:"name"`
+	Phase              string   `json:"phase"`
+	Schedule           string   `json:"schedule,omitempty"`
+	StorageLocation    string   `json:"storageLocation,omitempty"`
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+	Started            string   `json:"started,omitempty"`
+	Completed          string   `json:"completed,omitempty"`
+	Expiration         string   `json:"expiration,omitempty"`
+	ExpiresIn          string   `json:"expiresIn,omitempty"`
+	Items              string   `json:"items,omitempty"`
+	Errors             int64    `json:"errors,omitempty"`
+	Warnings           int64    `json:"warnings,omitempty"`
+	FailureReason      string   `json:"failureReason,omitempty"`
+	ValidationErrors   []string `json:"validationErrors,omitempty"`
+
+	created time.Time
+}
+
+// VeleroSchedule is the state of one Velero Schedule.
+type VeleroSchedule struct {
+	Name       string `json:"name"`
+	Schedule   string `json:"schedule"`
+	Paused     bool   `json:"paused,omitempty"`
+	Phase      string `json:"phase,omitempty"`
+	LastBackup string `json:"lastBackup,omitempty"`
+}
+
+// veleroBackupStatus reads the phase, timing, progress and problems of a Backup.
+func veleroBackupStatus(obj *unstructured.Unstructured, now time.Time) VeleroBackup {
+	backup := VeleroBackup{
+		Name:     obj.GetName(),
+		Schedule: obj.GetLabels()[veleroScheduleNameLabel],
+		created:  obj.GetCreationTimestamp().Time,
+	}
+	backup.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+	if backup.Phase == "" {
+		backup.Phase = "New"
+	}
+	backup.StorageLocation, _, _ = unstructured.NestedString(obj.Object, "spec", "storageLocation")
+	backup.IncludedNamespaces, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "includedNamespaces")
+	backup.Started, _, _ = unstructured.NestedString(obj.Object, "status", "startTimestamp")
+	backup.Completed, _, _ = unstructured.NestedString(obj.Object, "status", "completionTimestamp")
+	backup.Expiration, _, _ = unstructured.NestedString(obj.Object, "status", "expiration")
+	if expiration, err := time.Parse(time.RFC3339, backup.Expiration); err == nil {
+		backup.ExpiresIn = expiration.Sub(now).Round(time.Minute).String()
+	}
+	backedUp, _, _ := unstructured.NestedInt64(obj.Object, "status", "progress", "itemsBackedUp")
+	total, found, _ := unstructured.NestedInt64(obj.Object, "status", "progress", "totalItems")
+	if found {
+		backup.Items = fmt.Sprintf("%d/%d", backedUp, total)
+	}
+	backup.Errors, _, _ = unstructured.NestedInt64(obj.Object, "status", "errors")
+	backup.Warnings, _, _ = unstructured.NestedInt64(obj.Object, "status", "warnings")
+	backup.FailureReason, _, _ = unstructured.NestedString(obj.Object, "status", "failureReason")
+	backup.ValidationErrors, _, _ = unstructured.NestedStringSlice(obj.Object, "status", "validationErrors")
+	return backup
+}
+
+// veleroScheduleStatus reads the cron expression, pause state and last backup of a Schedule.
+func veleroScheduleStatus(obj *unstructured.Unstructured) VeleroSchedule {
+	schedule := VeleroSchedule{Name: obj.GetName()}
+	schedule.Schedule, _, _ = unstructured.NestedString(obj.Object, "spec", "schedule")
+	schedule.Paused, _, _ = unstructured.NestedBool(obj.Object, "spec", "paused")
+	schedule.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+	schedule.LastBackup, _, _ = unstructured.NestedString(obj.Object, "status", "lastBackup")
+	return schedule
+}
+
+// veleroResourceInterface resolves a Velero kind in the Velero namespace.
+func veleroResourceInterface(client Client, gk schema.GroupKind, namespace string) (dynamic.ResourceInterface, error) {
+	ri, err := groupKindResourceInterface(client, gk, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("%w (is Velero installed?)", err)
+	}
+	return ri, nil
+}
+
+// parseVeleroNamespace reads the veleroNamespace argument.
+func parseVeleroNamespace(args map[string]any) (string, error) {
+	namespace, _ := args["veleroNamespace"].(string)
+	if err := validation.ValidateNamespace(namespace); err != nil {
+		return "", fmt.Errorf("invalid veleroNamespace: %w", err)
+	}
+	if namespace == "" {
+		namespace = defaultVeleroNamespace
+	}
+	return namespace, nil
+}
+
+// VeleroBackupsTool lists Velero Backups and Schedules.
+type VeleroBackupsTool struct {
+	client Client
+}
+
+// NewVeleroBackupsTool creates a new VeleroBackupsTool with the provided Kubernetes client.
+func NewVeleroBackupsTool(client Client) *VeleroBackupsTool {
+	return &VeleroBackupsTool{client: client}
+}
+
+// Tool returns the MCP tool definition for velero_backups.
+func (v *VeleroBackupsTool) Tool() mcp.Tool {
+	return mcp.NewTool("velero_backups",
+		mcp.WithDescription("List Velero Backups newest first with phase, start/completion time, expiry, items backed up, errors, warnings and failure reason, plus the Schedules with their last backup, to check that disaster-recovery backups are running"),
+		mcp.WithString("veleroNamespace",
+			mcp.Description("Namespace Velero is installed in (default: velero)"),
+		),
+		mcp.WithString("schedule",
+			mcp.Description("Only list backups created by this Schedule"),
+		),
+		mcp.WithString("phase",
+			mcp.Description("Only list backups in this phase, e.g. Completed, PartiallyFailed, Failed, InProgress"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of backups to return (default: 50)"),
+		),
+	)
+}
+
+// Handler lists the Backups and Schedules in the Velero namespace.
+func (v *VeleroBackupsTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.Params.Arguments
+	namespace, err := parseVeleroNamespace(args)
+	if err != nil {
+		return nil, err
+	}
+	schedule, _ := args["schedule"].(string)
+	phase, _ := args["phase"].(string)
+	limit := 50
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	backupRI, err := veleroResourceInterface(v.client, veleroBackupGK, namespace)
+	if err != nil {
+		return nil, err
+	}
+	opts := metav1.ListOptions{}
+	if schedule != "" {
+		opts.LabelSelector = veleroScheduleNameLabel + "=" + schedule
+	}
+	backupItems, err := backupRI.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+	scheduleRI, err := veleroResourceInterface(v.client, veleroScheduleGK, namespace)
+	if err != nil {
+		return nil, err
+	}
+	scheduleItems, err := scheduleRI.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	now := time.Now()
+	backups := make([]VeleroBackup, 0, len(backupItems.Items))
+	counts := map[string]int{}
+	for i := range backupItems.Items {
+		backup := veleroBackupStatus(&backupItems.Items[i], now)
+		counts[backup.Phase]++
+		if phase != "" && !strings.EqualFold(backup.Phase, phase) {
+			continue
+		}
+		backups = append(backups, backup)
+	}
+	sort.SliceStable(backups, func(i, j int) bool { return backups[i].created.After(backups[j].created) })
+	truncated := len(backups) > limit
+	if truncated {
+		backups = backups[:limit]
+	}
+
+	schedules := make([]VeleroSchedule, 0, len(scheduleItems.Items))
+	for i := range scheduleItems.Items {
+		schedules = append(schedules, veleroScheduleStatus(&scheduleItems.Items[i]))
+	}
+
+	result := map[string]any{
+		"veleroNamespace": namespace,
+		"backups":         backups,
+		"counts":          counts,
+		"schedules":       schedules,
+	}
+	if truncated {
+		result["truncated"] = true
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// VeleroBackupInput represents the input for creating a Velero Backup.
+type VeleroBackupInput struct {
+	VeleroNamespace    string
+	Name               string
+	FromSchedule       string
+	IncludedNamespaces []string
+	ExcludedNamespaces []string
+	LabelSelector      *metav1.LabelSelector
+	TTL                time.Duration
+	StorageLocation    string
+	SnapshotVolumes    *bool
+}
+
+// veleroBackupSpec builds the spec of a Backup from the input, on top of a Schedule template if one is given.
+func veleroBackupSpec(input *VeleroBackupInput, template map[string]any) (map[string]any, error) {
+	spec := map[string]any{}
+	for k, v := range template {
+		spec[k] = v
+	}
+	if len(input.IncludedNamespaces) > 0 {
+		spec["includedNamespaces"] = stringsToAny(input.IncludedNamespaces)
+	}
+	if len(input.ExcludedNamespaces) > 0 {
+		spec["excludedNamespaces"] = stringsToAny(input.ExcludedNamespaces)
+	}
+	if input.LabelSelector != nil {
+		selector, err := runtime.DefaultUnstructuredConverter.ToUnstructured(input.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert label selector: %w", err)
+		}
+		spec["labelSelector"] = selector
+	}
+	if input.TTL > 0 {
+		spec["ttl"] = input.TTL.String()
+	}
+	if input.StorageLocation != "" {
+		spec["storageLocation"] = input.StorageLocation
+	}
+	if input.SnapshotVolumes != nil {
+		spec["snapshotVolumes"] = *input.SnapshotVolumes
+	}
+	return spec, nil
+}
+
+// stringsToAny converts a string slice to the []any form unstructured objects use.
+func stringsToAny(values []string) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+// VeleroBackupCreateTool requests an on-demand Velero Backup.
+type VeleroBackupCreateTool struct {
+	client Client
+}
+
+// NewVeleroBackupCreateTool creates a new VeleroBackupCreateTool with the provided Kubernetes client.
+func NewVeleroBackupCreateTool(client Client) *VeleroBackupCreateTool {
+	return &VeleroBackupCreateTool{client: client}
+}
+
+// Tool returns the MCP tool definition for velero_backup_create.
+func (v *VeleroBackupCreateTool) Tool() mcp.Tool {
+	return mcp.NewTool("velero_backup_create",
+		mcp.WithDescription("Request an on-demand Velero Backup (like 'velero backup create') by creating a Backup object, optionally from an existing Schedule's template; check progress with velero_backups"),
+		mcp.WithString("veleroNamespace",
+			mcp.Description("Namespace Velero is installed in (default: velero)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the Backup (default: '<schedule or backup>-<timestamp>')"),
+		),
+		mcp.WithString("fromSchedule",
+			mcp.Description("Create the backup from this Schedule's template; other settings override the template"),
+		),
+		mcp.WithArray("includedNamespaces",
+			mcp.Description("Namespaces to back up (default: all)"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("excludedNamespaces",
+			mcp.Description("Namespaces to leave out of the backup"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Only back up resources matching this label selector, e.g. 'app=db'"),
+		),
+		mcp.WithString("ttl",
+			mcp.Description("How long to keep the backup, e.g. '720h' (default: Velero's server default of 30 days)"),
+		),
+		mcp.WithString("storageLocation",
+			mcp.Description("BackupStorageLocation to store the backup in (default: the default location)"),
+		),
+		mcp.WithBoolean("snapshotVolumes",
+			mcp.Description("Whether to take snapshots of persistent volumes"),
+		),
+	)
+}
+
+// Handler creates the Backup object.
+func (v *VeleroBackupCreateTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateVeleroBackupParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate velero_backup_create params: %w", err)
+	}
+
+	var template map[string]any
+	labels := map[string]any{}
+	if input.FromSchedule != "" {
+		scheduleRI, err := veleroResourceInterface(v.client, veleroScheduleGK, input.VeleroNamespace)
+		if err != nil {
+			return nil, err
+		}
+		schedule, err := scheduleRI.Get(ctx, input.FromSchedule, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get schedule %s: %w", input.FromSchedule, err)
+		}
+		template, _, _ = unstructured.NestedMap(schedule.Object, "spec", "template")
+		labels[veleroScheduleNameLabel] = input.FromSchedule
+	}
+	spec, err := veleroBackupSpec(input, template)
+	if err != nil {
+		return nil, err
+	}
+
+	backupRI, err := veleroResourceInterface(v.client, veleroBackupGK, input.VeleroNamespace)
+	if err != nil {
+		return nil, err
+	}
+	backup := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "velero.io/v1",
+		"kind":       veleroBackupGK.Kind,
+		"metadata": map[string]any{
+			"name":      input.Name,
+			"namespace": input.VeleroNamespace,
+			"labels":    labels,
+		},
+		"spec": spec,
+	}}
+	created, err := backupRI.Create(ctx, backup, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup %s: %w", input.Name, err)
+	}
+
+	result := map[string]any{
+		"status":          "Backup requested",
+		"backup":          created.GetName(),
+		"veleroNamespace": input.VeleroNamespace,
+		"spec":            spec,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseAndValidateVeleroBackupParams validates and parses the input parameters.
+func parseAndValidateVeleroBackupParams(args map[string]any) (*VeleroBackupInput, error) {
+	input := &VeleroBackupInput{}
+	var err error
+	if input.VeleroNamespace, err = parseVeleroNamespace(args); err != nil {
+		return nil, err
+	}
+
+	input.FromSchedule, _ = args["fromSchedule"].(string)
+	if input.FromSchedule != "" {
+		if err := validation.ValidateResourceName(input.FromSchedule); err != nil {
+			return nil, fmt.Errorf("invalid fromSchedule: %w", err)
+		}
+	}
+	input.Name, _ = args["name"].(string)
+	if input.Name == "" {
+		prefix := "backup"
+		if input.FromSchedule != "" {
+			prefix = input.FromSchedule
+		}
+		input.Name = prefix + "-" + time.Now().UTC().Format(veleroTimestampFormat)
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid backup name: %w", err)
+	}
+
+	if input.IncludedNamespaces, err = stringSliceArg(args, "includedNamespaces"); err != nil {
+		return nil, err
+	}
+	if input.ExcludedNamespaces, err = stringSliceArg(args, "excludedNamespaces"); err != nil {
+		return nil, err
+	}
+	if selector, _ := args["labelSelector"].(string); selector != "" {
+		if input.LabelSelector, err = metav1.ParseToLabelSelector(selector); err != nil {
+			return nil, fmt.Errorf("invalid labelSelector: %w", err)
+		}
+	}
+	if ttl, _ := args["ttl"].(string); ttl != "" {
+		if input.TTL, err = time.ParseDuration(ttl); err != nil || input.TTL <= 0 {
+			return nil, fmt.Errorf("invalid ttl %q: must be a positive duration such as 720h", ttl)
+		}
+	}
+	input.StorageLocation, _ = args["storageLocation"].(string)
+	if snapshot, ok := args["snapshotVolumes"].(bool); ok {
+		input.SnapshotVolumes = &snapshot
+	}
+
+	return input, nil
+}
+
+// VeleroRestoreInput represents the input for creating a Velero Restore.
+type VeleroRestoreInput struct {
+	VeleroNamespace        string
+	Name                   string
+	BackupName             string
+	ScheduleName           string
+	IncludedNamespaces     []string
+	ExcludedNamespaces     []string
+	NamespaceMapping       map[string]string
+	RestorePVs             *bool
+	ExistingResourcePolicy string
+}
+
+// veleroRestoreSpec builds the spec of a Restore from the input.
+func veleroRestoreSpec(input *VeleroRestoreInput) map[string]any {
+	spec := map[string]any{}
+	if input.BackupName != "" {
+		spec["backupName"] = input.BackupName
+	} else {
+		spec["scheduleName"] = input.ScheduleName
+	}
+	if len(input.IncludedNamespaces) > 0 {
+		spec["includedNamespaces"] = stringsToAny(input.IncludedNamespaces)
+	}
+	if len(input.ExcludedNamespaces) > 0 {
+		spec["excludedNamespaces"] = stringsToAny(input.ExcludedNamespaces)
+	}
+	if len(input.NamespaceMapping) > 0 {
+		mapping := make(map[string]any, len(input.NamespaceMapping))
+		for from, to := range input.NamespaceMapping {
+			mapping[from] = to
+		}
+		spec["namespaceMapping"] = mapping
+	}
+	if input.RestorePVs != nil {
+		spec["restorePVs"] = *input.RestorePVs
+	}
+	if input.ExistingResourcePolicy != "" {
+		spec["existingResourcePolicy"] = input.ExistingResourcePolicy
+	}
+	return spec
+}
+
+// VeleroRestoreTool requests a Velero Restore from a Backup or the latest Backup of a Schedule.
+type VeleroRestoreTool struct {
+	client Client
+}
+
+// NewVeleroRestoreTool creates a new VeleroRestoreTool with the provided Kubernetes client.
+func NewVeleroRestoreTool(client Client) *VeleroRestoreTool {
+	return &VeleroRestoreTool{client: client}
+}
+
+// Tool returns the MCP tool definition for velero_restore.
+func (v *VeleroRestoreTool) Tool() mcp.Tool {
+	return mcp.NewTool("velero_restore",
+		mcp.WithDescription("Request a Velero Restore (like 'velero restore create') from a Backup, or from the latest successful Backup of a Schedule, optionally limited to some namespaces or restoring them under new names"),
+		mcp.WithString("veleroNamespace",
+			mcp.Description("Namespace Velero is installed in (default: velero)"),
+		),
+		mcp.WithString("backupName",
+			mcp.Description("Backup to restore from (either backupName or scheduleName is required)"),
+		),
+		mcp.WithString("scheduleName",
+			mcp.Description("Restore from the latest successful Backup of this Schedule"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the Restore (default: '<backup or schedule>-<timestamp>')"),
+		),
+		mcp.WithArray("includedNamespaces",
+			mcp.Description("Namespaces to restore (default: all in the backup)"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("excludedNamespaces",
+			mcp.Description("Namespaces to skip"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithObject("namespaceMapping",
+			mcp.Description("Restore namespaces under new names, e.g. {\"prod\": \"prod-restore\"}"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("restorePVs",
+			mcp.Description("Whether to restore persistent volumes from snapshots"),
+		),
+		mcp.WithString("existingResourcePolicy",
+			mcp.Description("What to do with resources that already exist: 'none' leaves them (default), 'update' patches them"),
+			mcp.Enum("none", "update"),
+		),
+	)
+}
+
+// Handler creates the Restore object.
+func (v *VeleroRestoreTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateVeleroRestoreParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate velero_restore params: %w", err)
+	}
+
+	if input.BackupName != "" {
+		backupRI, err := veleroResourceInterface(v.client, veleroBackupGK, input.VeleroNamespace)
+		if err != nil {
+			return nil, err
+		}
+		backup, err := backupRI.Get(ctx, input.BackupName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get backup %s: %w", input.BackupName, err)
+		}
+		// Velero rejects restores from backups that did not finish.
+		if phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase"); phase != "Completed" && phase != "PartiallyFailed" {
+			return nil, fmt.Errorf("backup %s is in phase %q; only Completed or PartiallyFailed backups can be restored", input.BackupName, phase)
+		}
+	}
+
+	restoreRI, err := veleroResourceInterface(v.client, veleroRestoreGK, input.VeleroNamespace)
+	if err != nil {
+		return nil, err
+	}
+	spec := veleroRestoreSpec(input)
+	restore := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "velero.io/v1",
+		"kind":       veleroRestoreGK.Kind,
+		"metadata": map[string]any{
+			"name":      input.Name,
+			"namespace": input.VeleroNamespace,
+		},
+		"spec": spec,
+	}}
+	created, err := restoreRI.Create(ctx, restore, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create restore %s: %w", input.Name, err)
+	}
+
+	result := map[string]any{
+		"status":          "Restore requested",
+		"restore":         created.GetName(),
+		"veleroNamespace": input.VeleroNamespace,
+		"spec":            spec,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// parseAndValidateVeleroRestoreParams validates and parses the input parameters.
+func parseAndValidateVeleroRestoreParams(args map[string]any) (*VeleroRestoreInput, error) {
+	input := &VeleroRestoreInput{}
+	var err error
+	if input.VeleroNamespace, err = parseVeleroNamespace(args); err != nil {
+		return nil, err
+	}
+
+	input.BackupName, _ = args["backupName"].(string)
+	input.ScheduleName, _ = args["scheduleName"].(string)
+	source := input.BackupName
+	switch {
+	case input.BackupName != "" && input.ScheduleName != "":
+		return nil, errors.New("only one of backupName or scheduleName may be provided")
+	case input.BackupName != "":
+		if err := validation.ValidateResourceName(input.BackupName); err != nil {
+			return nil, fmt.Errorf("invalid backupName: %w", err)
+		}
+	case input.ScheduleName != "":
+		if err := validation.ValidateResourceName(input.ScheduleName); err != nil {
+			return nil, fmt.Errorf("invalid scheduleName: %w", err)
+		}
+		source = input.ScheduleName
+	default:
+		return nil, errors.New("backupName or scheduleName must be provided")
+	}
+
+	input.Name, _ = args["name"].(string)
+	if input.Name == "" {
+		input.Name = source + "-" + time.Now().UTC().Format(veleroTimestampFormat)
+	}
+	if err := validation.ValidateResourceName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid restore name: %w", err)
+	}
+
+	if input.IncludedNamespaces, err = stringSliceArg(args, "includedNamespaces"); err != nil {
+		return nil, err
+	}
+	if input.ExcludedNamespaces, err = stringSliceArg(args, "excludedNamespaces"); err != nil {
+		return nil, err
+	}
+	if input.NamespaceMapping, err = stringMapArg(args, "namespaceMapping"); err != nil {
+		return nil, err
+	}
+	for from, to := range input.NamespaceMapping {
+		if err := validation.ValidateNamespace(to); err != nil || to == "" {
+			return nil, fmt.Errorf("invalid namespaceMapping target %q for %s", to, from)
+		}
+	}
+	if restorePVs, ok := args["restorePVs"].(bool); ok {
+		input.RestorePVs = &restorePVs
+	}
+	if policy, _ := args["existingResourcePolicy"].(string); policy != "" {
+		input.ExistingResourcePolicy = strings.ToLower(policy)
+		if input.ExistingResourcePolicy != "none" && input.ExistingResourcePolicy != "update" {
+			return nil, errors.New("existingResourcePolicy must be none or update")
+		}
+	}
+
+	return input, nil
+}
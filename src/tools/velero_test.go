@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestVeleroBackupStatus(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"name":   "daily-20241231000000",
+			"labels": map[string]any{veleroScheduleNameLabel: "daily"},
+		},
+		"spec": map[string]any{"storageLocation": "default", "includedNamespaces": []any{"prod"}},
+		"status": map[string]any{
+			"phase":               "PartiallyFailed",
+			"startTimestamp":      "2024-12-31T00:00:00Z",
+			"completionTimestamp": "2024-12-31T00:05:00Z",
+			"expiration":          "2025-01-31T00:00:00Z",
+			"progress":            map[string]any{"itemsBackedUp": int64(120), "totalItems": int64(124)},
+			"errors":              int64(4),
+			"warnings":            int64(1),
+		},
+	}}
+
+	backup := veleroBackupStatus(obj, now)
+	assert.Equal(t, "daily", backup.Schedule)
+	assert.Equal(t, "PartiallyFailed", backup.Phase)
+	assert.Equal(t, []string{"prod"}, backup.IncludedNamespaces)
+	assert.Equal(t, "720h0m0s", backup.ExpiresIn)
+	assert.Equal(t, "120/124", backup.Items)
+	assert.Equal(t, int64(4), backup.Errors)
+
+	unstructured.RemoveNestedField(obj.Object, "status")
+	backup = veleroBackupStatus(obj, now)
+	assert.Equal(t, "New", backup.Phase)
+	assert.Empty(t, backup.Items)
+}
+
+func TestVeleroBackupSpec(t *testing.T) {
+	input, err := parseAndValidateVeleroBackupParams(map[string]any{
+		"fromSchedule":       "daily",
+		"includedNamespaces": []any{"prod"},
+		"labelSelector":      "app=db",
+		"ttl":                "72h",
+		"snapshotVolumes":    false,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultVeleroNamespace, input.VeleroNamespace)
+	assert.Regexp(t, `^daily-\d{14}$`, input.Name)
+
+	template := map[string]any{"includedNamespaces": []any{"*"}, "storageLocation": "aws"}
+	spec, err := veleroBackupSpec(input, template)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"includedNamespaces": []any{"prod"},
+		"labelSelector":      map[string]any{"matchLabels": map[string]any{"app": "db"}},
+		"ttl":                "72h0m0s",
+		"storageLocation":    "aws",
+		"snapshotVolumes":    false,
+	}, spec)
+	assert.Equal(t, []any{"*"}, template["includedNamespaces"], "the schedule template must not be modified")
+
+	_, err = parseAndValidateVeleroBackupParams(map[string]any{"ttl": "forever"})
+	assert.Error(t, err)
+}
+
+func TestVeleroRestoreSpec(t *testing.T) {
+	input, err := parseAndValidateVeleroRestoreParams(map[string]any{
+		"backupName":             "daily-20241231000000",
+		"namespaceMapping":       map[string]any{"prod": "prod-restore"},
+		"restorePVs":             true,
+		"existingResourcePolicy": "update",
+	})
+	assert.NoError(t, err)
+	assert.Regexp(t, `^daily-20241231000000-\d{14}$`, input.Name)
+	assert.Equal(t, map[string]any{
+		"backupName":             "daily-20241231000000",
+		"namespaceMapping":       map[string]any{"prod": "prod-restore"},
+		"restorePVs":             true,
+		"existingResourcePolicy": "update",
+	}, veleroRestoreSpec(input))
+
+	input, err = parseAndValidateVeleroRestoreParams(map[string]any{"scheduleName": "daily"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"scheduleName": "daily"}, veleroRestoreSpec(input))
+
+	_, err = parseAndValidateVeleroRestoreParams(map[string]any{})
+	assert.Error(t, err)
+	_, err = parseAndValidateVeleroRestoreParams(map[string]any{"backupName": "a", "scheduleName": "b"})
+	assert.Error(t, err)
+}
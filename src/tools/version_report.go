@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// maxKubeletMinorSkew is how many minor versions a kubelet may trail the API server (Kubernetes 1.28+).
+const maxKubeletMinorSkew = 3
+
+// NodeVersion is the version information reported by one node.
+type NodeVersion struct {
+	Name             string `json:"name"`
+	Kubelet          string `json:"kubelet"`
+	KubeProxy        string `json:"kubeProxy,omitempty"`
+	ContainerRuntime string `json:"containerRuntime"`
+	OSImage          string `json:"osImage"`
+	KernelVersion    string `json:"kernelVersion"`
+	Architecture     string `json:"architecture"`
+	Skew             string `json:"skew,omitempty"`
+}
+
+// VersionReportTool reports control plane and node versions and flags unsupported skew.
+type VersionReportTool struct {
+	client Client
+}
+
+// NewVersionReportTool creates a new VersionReportTool with the provided Kubernetes client.
+func NewVersionReportTool(client Client) *VersionReportTool {
+	return &VersionReportTool{client: client}
+}
+
+// Tool returns the MCP tool definition for version_report.
+func (v *VersionReportTool) Tool() mcp.Tool {
+	return mcp.NewTool("version_report",
+		mcp.WithDescription("Report the API server version, kubelet/kube-proxy/container runtime versions of every node, the detected managed provider (GKE, EKS, AKS) and any kubelet version skew outside the supported bounds; useful before upgrades"),
+	)
+}
+
+// Handler gathers the versions and evaluates the skew policy.
+func (v *VersionReportTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	disco, err := v.client.DiscoClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	serverInfo, err := disco.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+
+	clientset, err := v.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	server, err := utilversion.ParseGeneric(serverInfo.GitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server version %q: %w", serverInfo.GitVersion, err)
+	}
+
+	nodeVersions := make([]NodeVersion, 0, len(nodes.Items))
+	kubeletVersions := map[string]int{}
+	var skewed []string
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		info := node.Status.NodeInfo
+		nv := NodeVersion{
+			Name:             node.Name,
+			Kubelet:          info.KubeletVersion,
+			KubeProxy:        info.KubeProxyVersion,
+			ContainerRuntime: info.ContainerRuntimeVersion,
+			OSImage:          info.OSImage,
+			KernelVersion:    info.KernelVersion,
+			Architecture:     info.Architecture,
+		}
+		nv.Skew = kubeletSkew(server, info.KubeletVersion)
+		if nv.Skew != "" {
+			skewed = append(skewed, fmt.Sprintf("%s: %s", node.Name, nv.Skew))
+		}
+		kubeletVersions[info.KubeletVersion]++
+		nodeVersions = append(nodeVersions, nv)
+	}
+	sort.Slice(nodeVersions, func(i, j int) bool { return nodeVersions[i].Name < nodeVersions[j].Name })
+
+	result := map[string]any{
+		"server": map[string]string{
+			"gitVersion": serverInfo.GitVersion,
+			"platform":   serverInfo.Platform,
+			"goVersion":  serverInfo.GoVersion,
+			"buildDate":  serverInfo.BuildDate,
+		},
+		"provider":        detectProvider(serverInfo.GitVersion, nodes.Items),
+		"nodes":           nodeVersions,
+		"kubeletVersions": kubeletVersions,
+		"skewIssues":      skewed,
+		"supportedSkew":   fmt.Sprintf("kubelet may be up to %d minor versions older than the API server and never newer", maxKubeletMinorSkew),
+	}
+	if len(kubeletVersions) > 1 {
+		result["mixedKubeletVersions"] = true
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// kubeletSkew describes a kubelet version outside the supported skew from the API server, or returns "".
+func kubeletSkew(server *utilversion.Version, kubelet string) string {
+	kv, err := utilversion.ParseGeneric(kubelet)
+	if err != nil {
+		return fmt.Sprintf("unparseable kubelet version %q", kubelet)
+	}
+	if kv.Major() != server.Major() {
+		return fmt.Sprintf("kubelet major version %d differs from API server %d", kv.Major(), server.Major())
+	}
+	switch diff := int(server.Minor()) - int(kv.Minor()); {
+	case diff < 0:
+		return fmt.Sprintf("kubelet v%d.%d is newer than API server v%d.%d", kv.Major(), kv.Minor(), server.Major(), server.Minor())
+	case diff > maxKubeletMinorSkew:
+		return fmt.Sprintf("kubelet v%d.%d is %d minor versions behind API server v%d.%d (max %d)", kv.Major(), kv.Minor(), diff, server.Major(), server.Minor(), maxKubeletMinorSkew)
+	}
+	return ""
+}
+
+// detectProvider guesses the managed Kubernetes offering from the server version suffix,
+// node providerIDs and provider-specific node labels.
+func detectProvider(gitVersion string, nodes []corev1.Node) string {
+	switch {
+	case strings.Contains(gitVersion, "-gke."):
+		return "GKE"
+	case strings.Contains(gitVersion, "-eks-"):
+		return "EKS"
+	}
+	for _, node := range nodes {
+		for label := range node.Labels {
+			switch {
+			case strings.HasPrefix(label, "cloud.google.com/gke-"):
+				return "GKE"
+			case strings.HasPrefix(label, "eks.amazonaws.com/"):
+				return "EKS"
+			case strings.HasPrefix(label, "kubernetes.azure.com/"):
+				return "AKS"
+			}
+		}
+	}
+	for _, node := range nodes {
+		switch {
+		case strings.HasPrefix(node.Spec.ProviderID, "gce://"):
+			return "GCE (self-managed)"
+		case strings.HasPrefix(node.Spec.ProviderID, "aws://"):
+			return "AWS (self-managed)"
+		case strings.HasPrefix(node.Spec.ProviderID, "azure://"):
+			return "Azure (self-managed)"
+		}
+	}
+	return "unknown"
+}
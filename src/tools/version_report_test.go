@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestKubeletSkew(t *testing.T) {
+	server := utilversion.MustParseGeneric("v1.30.2-gke.1000")
+
+	assert.Empty(t, kubeletSkew(server, "v1.30.1"))
+	assert.Empty(t, kubeletSkew(server, "v1.27.9-eks-abc"))
+	assert.Equal(t, "kubelet v1.26 is 4 minor versions behind API server v1.30 (max 3)", kubeletSkew(server, "v1.26.0"))
+	assert.Equal(t, "kubelet v1.31 is newer than API server v1.30", kubeletSkew(server, "v1.31.0"))
+}
+
+func TestDetectProvider(t *testing.T) {
+	node := func(labels map[string]string, providerID string) corev1.Node {
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: labels}, Spec: corev1.NodeSpec{ProviderID: providerID}}
+	}
+
+	assert.Equal(t, "GKE", detectProvider("v1.30.2-gke.1000", nil))
+	assert.Equal(t, "EKS", detectProvider("v1.29.4-eks-036c24b", nil))
+	assert.Equal(t, "AKS", detectProvider("v1.29.4", []corev1.Node{
+		node(nil, "azure:///subscriptions/x"),
+		node(map[string]string{"kubernetes.azure.com/cluster": "rg"}, "azure:///subscriptions/y"),
+	}))
+	assert.Equal(t, "AWS (self-managed)", detectProvider("v1.29.4", []corev1.Node{node(nil, "aws:///us-east-1a/i-123")}))
+	assert.Equal(t, "unknown", detectProvider("v1.29.4", []corev1.Node{node(nil, "kind://docker/kind/kind-control-plane")}))
+}
@@ -0,0 +1,532 @@
+// Environment variables used by this tool:
+// Optional:
+//   VULN_SCANNER_URL               - Scanner endpoint used when trivy-operator is not installed; it is called as
+//                                    GET <url>?image=<image> and must return `trivy image --format json` output
+//   VULN_SCANNER_TOKEN             - Bearer token sent to VULN_SCANNER_URL
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// vulnerabilityReportGK is written by trivy-operator, one report per workload container.
+var vulnerabilityReportGK = schema.GroupKind{Group: "aquasecurity.github.io", Kind: "VulnerabilityReport"}
+
+const (
+	defaultVulnCVELimit   = 10
+	maxScannedImages      = 50
+	vulnScannerTimeout    = 2 * time.Minute
+	maxVulnScannerPayload = 32 << 20
+)
+
+var vulnSeverityRank = map[string]int{"CRITICAL": 0, "HIGH": 1, "MEDIUM": 2, "LOW": 3, "UNKNOWN": 4}
+
+// CVE is one vulnerability found in an image.
+type CVE struct {
+	ID        string `json:"id"`
+	Severity  string `json:"severity"`
+	Package   string `json:"package"`
+	Installed string `json:"installedVersion,omitempty"`
+	Fixed     string `json:"fixedVersion,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Link      string `json:"link,omitempty"`
+}
+
+// ImageVulnerabilities summarizes the vulnerabilities of one image and the workloads running it.
+type ImageVulnerabilities struct {
+	Image     string   `json:"image"`
+	Workloads []string `json:"workloads"`
+	Running   bool     `json:"running"`
+	Critical  int      `json:"critical"`
+	High      int      `json:"high"`
+	Medium    int      `json:"medium"`
+	Low       int      `json:"low"`
+	Fixable   int      `json:"fixable"`
+	ScannedAt string   `json:"scannedAt,omitempty"`
+	CVEs      []CVE    `json:"cves,omitempty"`
+	Truncated bool     `json:"cvesTruncated,omitempty"`
+
+	seen map[string]bool
+}
+
+// add records a CVE once per ID and package.
+func (v *ImageVulnerabilities) add(cve CVE) {
+	key := cve.ID + "/" + cve.Package
+	if v.seen[key] {
+		return
+	}
+	if v.seen == nil {
+		v.seen = map[string]bool{}
+	}
+	v.seen[key] = true
+	switch cve.Severity {
+	case "CRITICAL":
+		v.Critical++
+	case "HIGH":
+		v.High++
+	case "MEDIUM":
+		v.Medium++
+	case "LOW":
+		v.Low++
+	}
+	if cve.Fixed != "" && vulnSeverityRank[cve.Severity] <= vulnSeverityRank["HIGH"] {
+		v.Fixable++
+	}
+	v.CVEs = append(v.CVEs, cve)
+}
+
+// addWorkload records a workload running the image once.
+func (v *ImageVulnerabilities) addWorkload(workload string) {
+	for _, w := range v.Workloads {
+		if w == workload {
+			return
+		}
+	}
+	v.Workloads = append(v.Workloads, workload)
+}
+
+// VulnReportInput represents the input for vuln_report.
+type VulnReportInput struct {
+	Namespace   string `json:"namespace,omitempty"`
+	Source      string `json:"source"`
+	MinSeverity string `json:"minSeverity"`
+	FixableOnly bool   `json:"fixableOnly,omitempty"`
+	IncludeIdle bool   `json:"includeNotRunning,omitempty"`
+	Limit       int    `json:"limit"`
+}
+
+// VulnReportTool summarizes critical and high CVEs per workload image.
+type VulnReportTool struct {
+	client Client
+}
+
+// NewVulnReportTool creates a new VulnReportTool with the provided Kubernetes client.
+func NewVulnReportTool(client Client) *VulnReportTool {
+	return &VulnReportTool{client: client}
+}
+
+// Tool returns the MCP tool definition for vuln_report.
+func (v *VulnReportTool) Tool() mcp.Tool {
+	return mcp.NewTool("vuln_report",
+		mcp.WithDescription("Summarize image vulnerabilities per workload image from trivy-operator VulnerabilityReports, or from the scanner at VULN_SCANNER_URL for the images of running pods when trivy-operator is not installed. Lists each image with its critical/high/medium/low CVE counts, how many critical and high CVEs have a fixed version, the workloads running it and the worst CVEs, so 'is anything critical running in prod?' can be answered. Reports of images no pod runs anymore are skipped unless includeNotRunning is set"),
+		mcp.WithString("namespace",
+			mcp.Description("Only report this namespace (default: all namespaces)"),
+		),
+		mcp.WithString("source",
+			mcp.Description("Where vulnerabilities come from: 'trivy-operator', 'scanner' (VULN_SCANNER_URL) or 'auto' for trivy-operator when installed (default: auto)"),
+			mcp.Enum("auto", "trivy-operator", "scanner"),
+		),
+		mcp.WithString("minSeverity",
+			mcp.Description("Only list CVEs and images at this severity or worse (default: high)"),
+			mcp.Enum("critical", "high", "medium", "low"),
+		),
+		mcp.WithBoolean("fixableOnly",
+			mcp.Description("Only list CVEs that have a fixed version (default: false)"),
+		),
+		mcp.WithBoolean("includeNotRunning",
+			mcp.Description("Include trivy-operator reports of images no pod currently runs (default: false)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of CVEs listed per image (default: %d)", defaultVulnCVELimit)),
+		),
+	)
+}
+
+// Handler collects the vulnerabilities and summarizes them per image.
+func (v *VulnReportTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateVulnReportParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate vuln_report params: %w", err)
+	}
+	clientset, err := v.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(input.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	replicaSets := replicaSetsByKey(ctx, clientset, input.Namespace)
+	running := runningImages(pods.Items, replicaSets)
+
+	source := input.Source
+	var reports []unstructured.Unstructured
+	if source != "scanner" {
+		ri, err := groupKindResourceInterface(v.client, vulnerabilityReportGK, input.Namespace)
+		if err == nil {
+			var list *unstructured.UnstructuredList
+			if list, err = ri.List(ctx, metav1.ListOptions{}); err == nil {
+				reports, source = list.Items, "trivy-operator"
+			}
+		}
+		if err != nil && (source == "trivy-operator" || os.Getenv("VULN_SCANNER_URL") == "") {
+			return nil, fmt.Errorf("failed to read trivy-operator vulnerability reports (is trivy-operator installed, or VULN_SCANNER_URL set?): %w", err)
+		}
+	}
+
+	result := map[string]any{}
+	var images map[string]*ImageVulnerabilities
+	if source == "trivy-operator" {
+		images = vulnerabilitiesFromReports(reports, running, replicaSets)
+	} else {
+		source = "scanner"
+		var notes []string
+		images, notes = v.scanRunningImages(ctx, running)
+		if len(notes) > 0 {
+			result["errors"] = notes
+		}
+	}
+	result["source"] = source
+
+	summaries := summarizeImageVulnerabilities(images, input)
+	totals := map[string]int{}
+	for _, s := range summaries {
+		if s.Running {
+			totals["critical"] += s.Critical
+			totals["high"] += s.High
+			if s.Critical > 0 {
+				totals["imagesWithCritical"]++
+			}
+		}
+	}
+	result["runningTotals"] = totals
+	result["images"] = summaries
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// runningImages maps the image keys of every container of the running pods to the workloads
+// running them. Both the tag and the resolved digest of an image are keys.
+func runningImages(pods []corev1.Pod, replicaSets map[string]*appsv1.ReplicaSet) map[string][]string {
+	running := map[string][]string{}
+	add := func(key, workload string) {
+		for _, w := range running[key] {
+			if w == workload {
+				return
+			}
+		}
+		running[key] = append(running[key], workload)
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		kind, name := workloadOwner(pod, replicaSets)
+		workload := pod.Namespace + "/" + kind + "/" + name
+		containers := append([]corev1.Container{}, pod.Spec.InitContainers...)
+		for _, c := range append(containers, pod.Spec.Containers...) {
+			add(imageKey(parseImageRef(c.Image)), workload)
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if _, digest, ok := strings.Cut(status.ImageID, "@"); ok {
+				ref := parseImageRef(status.Image)
+				ref.Tag, ref.Digest = "", digest
+				add(imageKey(ref), workload)
+			}
+		}
+	}
+	return running
+}
+
+// imageKey identifies an image by registry, repository and digest, or tag when no digest is known.
+func imageKey(ref imageRef) string {
+	ref.Registry = normalizeRegistry(ref.Registry)
+	if ref.Registry == defaultRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Digest != "" {
+		return ref.Registry + "/" + ref.Repository + "@" + ref.Digest
+	}
+	return ref.Registry + "/" + ref.Repository + ":" + ref.Tag
+}
+
+// normalizeRegistry maps the Docker Hub aliases scanners report to the registry name pods use.
+func normalizeRegistry(registry string) string {
+	switch registry {
+	case "", "index.docker.io", "registry-1.docker.io":
+		return defaultRegistry
+	}
+	return registry
+}
+
+// vulnerabilitiesFromReports merges trivy-operator reports by image. Reports name the ReplicaSet
+// of a Deployment; it is traced to the Deployment.
+func vulnerabilitiesFromReports(reports []unstructured.Unstructured, running map[string][]string, replicaSets map[string]*appsv1.ReplicaSet) map[string]*ImageVulnerabilities {
+	images := map[string]*ImageVulnerabilities{}
+	for i := range reports {
+		r := &reports[i]
+		report, _, _ := unstructured.NestedMap(r.Object, "report")
+		server, _, _ := unstructured.NestedString(report, "registry", "server")
+		repository, _, _ := unstructured.NestedString(report, "artifact", "repository")
+		tag, _, _ := unstructured.NestedString(report, "artifact", "tag")
+		digest, _, _ := unstructured.NestedString(report, "artifact", "digest")
+		if repository == "" {
+			continue
+		}
+		ref := imageRef{Registry: normalizeRegistry(server), Repository: repository, Tag: tag, Digest: digest}
+		if ref.Tag == "" && ref.Digest == "" {
+			ref.Tag = "latest"
+		}
+		tagKey := imageKey(imageRef{Registry: ref.Registry, Repository: ref.Repository, Tag: ref.Tag})
+		key := tagKey
+		if ref.Digest != "" {
+			key = imageKey(ref)
+		}
+
+		image, ok := images[key]
+		if !ok {
+			image = &ImageVulnerabilities{Image: displayImage(ref), Workloads: []string{}}
+			images[key] = image
+		}
+		workloads, isRunning := running[key]
+		if !isRunning && ref.Tag != "" {
+			workloads, isRunning = running[tagKey]
+		}
+		image.Running = image.Running || isRunning
+		for _, w := range workloads {
+			image.addWorkload(w)
+		}
+		if !isRunning {
+			labels := r.GetLabels()
+			kind, name := labels["trivy-operator.resource.kind"], labels["trivy-operator.resource.name"]
+			if rs, ok := replicaSets[r.GetNamespace()+"/"+name]; ok && kind == "ReplicaSet" {
+				if owner := metav1.GetControllerOf(rs); owner != nil {
+					kind, name = owner.Kind, owner.Name
+				}
+			}
+			if kind != "" {
+				image.addWorkload(r.GetNamespace() + "/" + kind + "/" + name)
+			}
+		}
+		if updated, _, _ := unstructured.NestedString(report, "updateTimestamp"); updated > image.ScannedAt {
+			image.ScannedAt = updated
+		}
+
+		vulns, _, _ := unstructured.NestedSlice(report, "vulnerabilities")
+		for _, item := range vulns {
+			vuln, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			str := func(field string) string { s, _ := vuln[field].(string); return s }
+			image.add(CVE{
+				ID:        str("vulnerabilityID"),
+				Severity:  strings.ToUpper(str("severity")),
+				Package:   str("resource"),
+				Installed: str("installedVersion"),
+				Fixed:     str("fixedVersion"),
+				Title:     str("title"),
+				Link:      str("primaryLink"),
+			})
+		}
+	}
+	return images
+}
+
+// displayImage formats an image reference the way it is usually written.
+func displayImage(ref imageRef) string {
+	image := ref.Registry + "/" + ref.Repository
+	if ref.Tag != "" {
+		image += ":" + ref.Tag
+	}
+	if ref.Digest != "" {
+		image += "@" + ref.Digest
+	}
+	return image
+}
+
+// trivyImageReport is the part of `trivy image --format json` output the tool reads.
+type trivyImageReport struct {
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string
+			PkgName          string
+			InstalledVersion string
+			FixedVersion     string
+			Severity         string
+			Title            string
+			PrimaryURL       string
+		}
+	}
+}
+
+// scanRunningImages asks VULN_SCANNER_URL for the vulnerabilities of each running image, by tag.
+func (v *VulnReportTool) scanRunningImages(ctx context.Context, running map[string][]string) (map[string]*ImageVulnerabilities, []string) {
+	scannerURL := os.Getenv("VULN_SCANNER_URL")
+	if scannerURL == "" {
+		return nil, []string{"no scanner configured: set VULN_SCANNER_URL or install trivy-operator"}
+	}
+	var keys []string
+	for key := range running {
+		if !strings.Contains(key, "@") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var notes []string
+	if len(keys) > maxScannedImages {
+		notes = append(notes, fmt.Sprintf("only the first %d of %d images were scanned", maxScannedImages, len(keys)))
+		keys = keys[:maxScannedImages]
+	}
+
+	images := map[string]*ImageVulnerabilities{}
+	for _, key := range keys {
+		report, err := queryVulnScanner(ctx, scannerURL, key)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		image := &ImageVulnerabilities{Image: key, Workloads: running[key], Running: true}
+		for _, result := range report.Results {
+			for _, vuln := range result.Vulnerabilities {
+				image.add(CVE{
+					ID:        vuln.VulnerabilityID,
+					Severity:  strings.ToUpper(vuln.Severity),
+					Package:   vuln.PkgName,
+					Installed: vuln.InstalledVersion,
+					Fixed:     vuln.FixedVersion,
+					Title:     vuln.Title,
+					Link:      vuln.PrimaryURL,
+				})
+			}
+		}
+		images[key] = image
+	}
+	return images, notes
+}
+
+// queryVulnScanner fetches the trivy JSON report of one image from the scanner.
+func queryVulnScanner(ctx context.Context, scannerURL, image string) (*trivyImageReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, vulnScannerTimeout)
+	defer cancel()
+	u, err := url.Parse(scannerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VULN_SCANNER_URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("image", image)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build scanner request: %w", err)
+	}
+	if token := os.Getenv("VULN_SCANNER_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scanner: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxVulnScannerPayload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scanner response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scanner returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	report := &trivyImageReport{}
+	if err := json.Unmarshal(body, report); err != nil {
+		return nil, fmt.Errorf("failed to decode scanner response: %w", err)
+	}
+	return report, nil
+}
+
+// summarizeImageVulnerabilities filters the images and their CVEs by the input and orders them
+// by critical, then high CVE count.
+func summarizeImageVulnerabilities(images map[string]*ImageVulnerabilities, input *VulnReportInput) []ImageVulnerabilities {
+	minRank := vulnSeverityRank[strings.ToUpper(input.MinSeverity)]
+	summaries := []ImageVulnerabilities{}
+	for _, image := range images {
+		if !image.Running && !input.IncludeIdle {
+			continue
+		}
+		var cves []CVE
+		for _, cve := range image.CVEs {
+			if vulnSeverityRank[cve.Severity] <= minRank && (!input.FixableOnly || cve.Fixed != "") {
+				cves = append(cves, cve)
+			}
+		}
+		if len(cves) == 0 {
+			continue
+		}
+		sort.SliceStable(cves, func(i, j int) bool {
+			if vulnSeverityRank[cves[i].Severity] != vulnSeverityRank[cves[j].Severity] {
+				return vulnSeverityRank[cves[i].Severity] < vulnSeverityRank[cves[j].Severity]
+			}
+			return cves[i].ID > cves[j].ID
+		})
+		summary := *image
+		summary.seen = nil
+		sort.Strings(summary.Workloads)
+		if len(cves) > input.Limit {
+			cves, summary.Truncated = cves[:input.Limit], true
+		}
+		summary.CVEs = cves
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.Critical != b.Critical {
+			return a.Critical > b.Critical
+		}
+		if a.High != b.High {
+			return a.High > b.High
+		}
+		return a.Image < b.Image
+	})
+	return summaries
+}
+
+func parseAndValidateVulnReportParams(args map[string]any) (*VulnReportInput, error) {
+	input := &VulnReportInput{Source: "auto", MinSeverity: "high", Limit: defaultVulnCVELimit}
+	input.Namespace, _ = args["namespace"].(string)
+	if err := validation.ValidateNamespace(input.Namespace); err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+	if source, ok := args["source"].(string); ok && source != "" {
+		switch source {
+		case "auto", "trivy-operator", "scanner":
+			input.Source = source
+		default:
+			return nil, errors.New("source must be one of auto, trivy-operator or scanner")
+		}
+	}
+	if severity, ok := args["minSeverity"].(string); ok && severity != "" {
+		severity = strings.ToLower(severity)
+		if _, ok := vulnSeverityRank[strings.ToUpper(severity)]; !ok || severity == "unknown" {
+			return nil, errors.New("minSeverity must be one of critical, high, medium or low")
+		}
+		input.MinSeverity = severity
+	}
+	input.FixableOnly, _ = args["fixableOnly"].(bool)
+	input.IncludeIdle, _ = args["includeNotRunning"].(bool)
+	if limit, ok := args["limit"].(float64); ok && limit > 0 {
+		input.Limit = int(limit)
+	}
+	return input, nil
+}
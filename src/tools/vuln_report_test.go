@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func vulnerabilityReport(namespace, kind, name, server, repository, tag string, vulns ...map[string]any) unstructured.Unstructured {
+	items := make([]any, 0, len(vulns))
+	for _, v := range vulns {
+		items = append(items, v)
+	}
+	report := unstructured.Unstructured{Object: map[string]any{
+		"report": map[string]any{
+			"registry":        map[string]any{"server": server},
+			"artifact":        map[string]any{"repository": repository, "tag": tag},
+			"updateTimestamp": "2026-10-01T00:00:00Z",
+			"vulnerabilities": items,
+		},
+	}}
+	report.SetNamespace(namespace)
+	report.SetLabels(map[string]string{"trivy-operator.resource.kind": kind, "trivy-operator.resource.name": name})
+	return report
+}
+
+func TestRunningImages(t *testing.T) {
+	controller := true
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "api-abc-1", OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-abc", Controller: &controller}}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx:1.25"}}},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Image: "docker.io/library/nginx:1.25", ImageID: "docker.io/library/nginx@sha256:abc"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "done"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "busybox"}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+	replicaSets := map[string]*appsv1.ReplicaSet{"web/api-abc": {ObjectMeta: metav1.ObjectMeta{
+		Name: "api-abc", OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "api", Controller: &controller}},
+	}}}
+
+	assert.Equal(t, map[string][]string{
+		"docker.io/library/nginx:1.25":       {"web/Deployment/api"},
+		"docker.io/library/nginx@sha256:abc": {"web/Deployment/api"},
+	}, runningImages(pods, replicaSets))
+}
+
+func TestVulnerabilitiesFromReports(t *testing.T) {
+	critical := map[string]any{"vulnerabilityID": "CVE-2026-0001", "severity": "CRITICAL", "resource": "openssl", "installedVersion": "3.0.1", "fixedVersion": "3.0.9"}
+	high := map[string]any{"vulnerabilityID": "CVE-2026-0002", "severity": "HIGH", "resource": "zlib", "installedVersion": "1.2"}
+	low := map[string]any{"vulnerabilityID": "CVE-2026-0003", "severity": "LOW", "resource": "curl"}
+	reports := []unstructured.Unstructured{
+		vulnerabilityReport("web", "ReplicaSet", "api-abc", "index.docker.io", "library/nginx", "1.25", critical, high, low),
+		vulnerabilityReport("web", "ReplicaSet", "worker-def", "index.docker.io", "library/nginx", "1.25", critical),
+		vulnerabilityReport("web", "ReplicaSet", "old-123", "ghcr.io", "acme/old", "v1", critical),
+	}
+	controller := true
+	replicaSets := map[string]*appsv1.ReplicaSet{"web/old-123": {ObjectMeta: metav1.ObjectMeta{
+		Name: "old-123", OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "old", Controller: &controller}},
+	}}}
+	running := map[string][]string{"docker.io/library/nginx:1.25": {"web/Deployment/api", "web/Deployment/worker"}}
+
+	images := vulnerabilitiesFromReports(reports, running, replicaSets)
+
+	require.Len(t, images, 2)
+	nginx := images["docker.io/library/nginx:1.25"]
+	require.NotNil(t, nginx)
+	assert.True(t, nginx.Running)
+	assert.Equal(t, []string{"web/Deployment/api", "web/Deployment/worker"}, nginx.Workloads)
+	assert.Equal(t, 1, nginx.Critical)
+	assert.Equal(t, 1, nginx.High)
+	assert.Equal(t, 1, nginx.Low)
+	assert.Equal(t, 1, nginx.Fixable)
+	assert.Equal(t, "2026-10-01T00:00:00Z", nginx.ScannedAt)
+
+	old := images["ghcr.io/acme/old:v1"]
+	require.NotNil(t, old)
+	assert.False(t, old.Running)
+	assert.Equal(t, []string{"web/Deployment/old"}, old.Workloads)
+
+	summaries := summarizeImageVulnerabilities(images, &VulnReportInput{MinSeverity: "high", Limit: 1})
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "docker.io/library/nginx:1.25", summaries[0].Image)
+	assert.Equal(t, []CVE{{ID: "CVE-2026-0001", Severity: "CRITICAL", Package: "openssl", Installed: "3.0.1", Fixed: "3.0.9"}}, summaries[0].CVEs)
+	assert.True(t, summaries[0].Truncated)
+
+	summaries = summarizeImageVulnerabilities(images, &VulnReportInput{MinSeverity: "critical", IncludeIdle: true, FixableOnly: true, Limit: 10})
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "ghcr.io/acme/old:v1", summaries[1].Image)
+}
+
+func TestQueryVulnScanner(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "docker.io/library/nginx:1.25", r.URL.Query().Get("image"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"ArtifactName":"nginx:1.25","Results":[{"Target":"debian","Vulnerabilities":[{"VulnerabilityID":"CVE-2026-0001","PkgName":"openssl","Severity":"CRITICAL","FixedVersion":"3.0.9"}]}]}`))
+	}))
+	defer server.Close()
+	t.Setenv("VULN_SCANNER_URL", server.URL+"/scan")
+	t.Setenv("VULN_SCANNER_TOKEN", "secret")
+
+	tool := NewVulnReportTool(nil)
+	images, notes := tool.scanRunningImages(context.Background(), map[string][]string{
+		"docker.io/library/nginx:1.25":       {"web/Deployment/api"},
+		"docker.io/library/nginx@sha256:abc": {"web/Deployment/api"},
+	})
+
+	assert.Empty(t, notes)
+	require.Len(t, images, 1)
+	image := images["docker.io/library/nginx:1.25"]
+	assert.Equal(t, 1, image.Critical)
+	assert.Equal(t, 1, image.Fixable)
+	assert.Equal(t, []string{"web/Deployment/api"}, image.Workloads)
+}
+
+func TestParseAndValidateVulnReportParams(t *testing.T) {
+	input, err := parseAndValidateVulnReportParams(map[string]any{"namespace": "prod", "minSeverity": "CRITICAL"})
+	require.NoError(t, err)
+	assert.Equal(t, &VulnReportInput{Namespace: "prod", Source: "auto", MinSeverity: "critical", Limit: defaultVulnCVELimit}, input)
+
+	_, err = parseAndValidateVulnReportParams(map[string]any{"minSeverity": "unknown"})
+	assert.Error(t, err)
+	_, err = parseAndValidateVulnReportParams(map[string]any{"source": "grype"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,293 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k4mrul/kubernetes-mcp/src/validation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Bounds for watch_resources so a single call cannot hold a watch open indefinitely.
+const (
+	defaultWatchDuration = 60 * time.Second
+	maxWatchDuration     = 10 * time.Minute
+	defaultWatchEvents   = 200
+)
+
+// WatchResourcesInput represents the input for watching resources.
+type WatchResourcesInput struct {
+	Kind          string        `json:"kind"`
+	Namespace     string        `json:"namespace,omitempty"`
+	LabelSelector string        `json:"labelSelector,omitempty"`
+	FieldSelector string        `json:"fieldSelector,omitempty"`
+	Duration      time.Duration `json:"duration"`
+	MaxEvents     int           `json:"maxEvents"`
+	Notify        bool          `json:"notify"`
+}
+
+// WatchEvent is one change observed during a watch.
+type WatchEvent struct {
+	Type      string `json:"type"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Time      string `json:"time"`
+	Summary   string `json:"summary,omitempty"`
+}
+
+// watchDigest buffers the events of a watch and keeps the latest state of every object.
+type watchDigest struct {
+	maxEvents int
+	Events    []WatchEvent      `json:"events"`
+	Dropped   int               `json:"droppedEvents,omitempty"`
+	Counts    map[string]int    `json:"counts"`
+	Latest    map[string]string `json:"latestState"`
+}
+
+func newWatchDigest(maxEvents int) *watchDigest {
+	return &watchDigest{maxEvents: maxEvents, Events: []WatchEvent{}, Counts: map[string]int{}, Latest: map[string]string{}}
+}
+
+// record adds an event, dropping it from the buffer (but not from the counts) once maxEvents is reached.
+func (d *watchDigest) record(ev WatchEvent) {
+	d.Counts[ev.Type]++
+	key := ev.Name
+	if ev.Namespace != "" {
+		key = ev.Namespace + "/" + ev.Name
+	}
+	if ev.Type == string(watch.Deleted) {
+		d.Latest[key] = "deleted"
+	} else {
+		d.Latest[key] = ev.Summary
+	}
+	if len(d.Events) >= d.maxEvents {
+		d.Dropped++
+		return
+	}
+	d.Events = append(d.Events, ev)
+}
+
+// WatchResourcesTool watches a kind for a bounded time and streams the changes to the client.
+type WatchResourcesTool struct {
+	client Client
+}
+
+// NewWatchResourcesTool creates a new WatchResourcesTool with the provided Kubernetes client.
+func NewWatchResourcesTool(client Client) *WatchResourcesTool {
+	return &WatchResourcesTool{client: client}
+}
+
+// Tool returns the MCP tool definition for watch_resources.
+func (w *WatchResourcesTool) Tool() mcp.Tool {
+	return mcp.NewTool("watch_resources",
+		mcp.WithDescription("Watch a resource kind (optionally filtered by namespace and label/field selectors) for a bounded duration. Each ADDED/MODIFIED/DELETED change is sent to the client as a notifications/message log notification as it happens, and the call returns a digest of all changes and the latest state of every object when the duration ends. Use it to monitor a rollout or incident in near real time."),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description("Kind, resource name or short name to watch, e.g. Pod, deployments, ev"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to watch (leave empty for all namespaces)"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Label selector, e.g. app=web"),
+		),
+		mcp.WithString("fieldSelector",
+			mcp.Description("Field selector, e.g. metadata.name=web-0"),
+		),
+		mcp.WithNumber("durationSeconds",
+			mcp.Description("How long to watch (default: 60, max: 600)"),
+		),
+		mcp.WithNumber("maxEvents",
+			mcp.Description("Maximum number of events kept in the returned digest (default: 200); later events are still counted"),
+		),
+		mcp.WithBoolean("notify",
+			mcp.Description("Send a notification to the client for every change (default: true); set false to only return the digest"),
+		),
+	)
+}
+
+// Handler runs the watch until the duration ends or the request is cancelled.
+func (w *WatchResourcesTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := parseAndValidateWatchResourcesParams(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate watch_resources params: %w", err)
+	}
+
+	discoClient, err := w.client.DiscoClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	apiResourceLists, err := discoClient.ServerPreferredResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
+	}
+	match, err := findGVRByKind(apiResourceLists, input.Kind)
+	if err != nil {
+		return nil, err
+	}
+	ri, err := w.client.ResourceInterface(*match.ToGroupVersionResource(), match.namespaced, input.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource interface: %w", err)
+	}
+
+	// List first so the watch starts at the current state instead of replaying every object as ADDED.
+	opts := metav1.ListOptions{LabelSelector: input.LabelSelector, FieldSelector: input.FieldSelector}
+	initial, err := ri.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", match.apiRes.Name, err)
+	}
+
+	watchCtx, cancel := context.WithTimeout(ctx, input.Duration)
+	defer cancel()
+
+	srv := server.ServerFromContext(ctx)
+	digest := newWatchDigest(input.MaxEvents)
+	notified, notifyFailures := 0, 0
+	resourceVersion := initial.GetResourceVersion()
+	var watchErr string
+
+	for watchErr == "" && watchCtx.Err() == nil {
+		opts.ResourceVersion = resourceVersion
+		watcher, err := ri.Watch(watchCtx, opts)
+		if err != nil {
+			if watchCtx.Err() == nil {
+				watchErr = err.Error()
+			}
+			break
+		}
+		// The server may close a watch early; resume from the last seen resourceVersion until the deadline.
+		for event := range watcher.ResultChan() {
+			if event.Type == watch.Error {
+				watchErr = fmt.Sprintf("watch error: %v", event.Object)
+				break
+			}
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			resourceVersion = obj.GetResourceVersion()
+			if event.Type == watch.Bookmark {
+				continue
+			}
+			ev := WatchEvent{
+				Type:      string(event.Type),
+				Kind:      obj.GetKind(),
+				Name:      obj.GetName(),
+				Namespace: obj.GetNamespace(),
+				Time:      time.Now().UTC().Format(time.RFC3339),
+				Summary:   watchObjectSummary(obj),
+			}
+			digest.record(ev)
+			if input.Notify && srv != nil {
+				err := srv.SendNotificationToClient(ctx, "notifications/message", map[string]any{
+					"level":  "info",
+					"logger": "watch_resources",
+					"data":   ev,
+				})
+				if err != nil {
+					notifyFailures++
+				} else {
+					notified++
+				}
+			}
+		}
+		watcher.Stop()
+	}
+
+	result := map[string]any{
+		"kind":            match.apiRes.Kind,
+		"namespace":       input.Namespace,
+		"labelSelector":   input.LabelSelector,
+		"fieldSelector":   input.FieldSelector,
+		"durationSeconds": int(input.Duration.Seconds()),
+		"initialObjects":  len(initial.Items),
+		"digest":          digest,
+		"notified":        notified,
+	}
+	if notifyFailures > 0 {
+		result["notificationFailures"] = notifyFailures
+	}
+	if watchErr != "" {
+		result["error"] = watchErr
+	}
+	if ctx.Err() != nil {
+		result["cancelled"] = true
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// watchObjectSummary renders the state of common kinds in one line: the pod phase, ready/desired
+// replicas of workloads, or the conditions that are currently true.
+func watchObjectSummary(obj *unstructured.Unstructured) string {
+	if phase, found, _ := unstructured.NestedString(obj.Object, "status", "phase"); found {
+		return phase
+	}
+	if replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+		return fmt.Sprintf("%d/%d ready, %d updated", ready, replicas, updated)
+	}
+	if reason, found, _ := unstructured.NestedString(obj.Object, "reason"); found && obj.GetKind() == "Event" {
+		message, _, _ := unstructured.NestedString(obj.Object, "message")
+		return reason + ": " + message
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var trueConditions []string
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if ok && cond["status"] == "True" {
+			if t, ok := cond["type"].(string); ok {
+				trueConditions = append(trueConditions, t)
+			}
+		}
+	}
+	return strings.Join(trueConditions, ",")
+}
+
+// parseAndValidateWatchResourcesParams validates and parses the input parameters.
+func parseAndValidateWatchResourcesParams(args map[string]any) (*WatchResourcesInput, error) {
+	input := &WatchResourcesInput{Duration: defaultWatchDuration, MaxEvents: defaultWatchEvents, Notify: true}
+
+	input.Kind, _ = args["kind"].(string)
+	if input.Kind == "" {
+		return nil, errors.New("kind must be provided")
+	}
+
+	if ns, ok := args["namespace"].(string); ok {
+		if err := validation.ValidateNamespace(ns); err != nil {
+			return nil, fmt.Errorf("invalid namespace: %w", err)
+		}
+		input.Namespace = ns
+	}
+	input.LabelSelector, _ = args["labelSelector"].(string)
+	input.FieldSelector, _ = args["fieldSelector"].(string)
+
+	if v, ok := args["durationSeconds"].(float64); ok && v > 0 {
+		input.Duration = time.Duration(v) * time.Second
+	}
+	if input.Duration > maxWatchDuration {
+		return nil, fmt.Errorf("durationSeconds must not exceed %d", int(maxWatchDuration.Seconds()))
+	}
+	if v, ok := args["maxEvents"].(float64); ok && v > 0 {
+		input.MaxEvents = int(v)
+	}
+	if v, ok := args["notify"].(bool); ok {
+		input.Notify = v
+	}
+
+	return input, nil
+}
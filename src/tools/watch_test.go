@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestWatchObjectSummary(t *testing.T) {
+	pod := &unstructured.Unstructured{Object: map[string]any{"kind": "Pod", "status": map[string]any{"phase": "Running"}}}
+	assert.Equal(t, "Running", watchObjectSummary(pod))
+
+	deployment := &unstructured.Unstructured{Object: map[string]any{
+		"kind":   "Deployment",
+		"spec":   map[string]any{"replicas": int64(3)},
+		"status": map[string]any{"readyReplicas": int64(2), "updatedReplicas": int64(3)},
+	}}
+	assert.Equal(t, "2/3 ready, 3 updated", watchObjectSummary(deployment))
+
+	node := &unstructured.Unstructured{Object: map[string]any{
+		"kind": "Node",
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "MemoryPressure", "status": "False"},
+			map[string]any{"type": "Ready", "status": "True"},
+		}},
+	}}
+	assert.Equal(t, "Ready", watchObjectSummary(node))
+}
+
+func TestWatchDigestRecord(t *testing.T) {
+	digest := newWatchDigest(2)
+
+	digest.record(WatchEvent{Type: "ADDED", Name: "web-1", Namespace: "app", Summary: "Pending"})
+	digest.record(WatchEvent{Type: "MODIFIED", Name: "web-1", Namespace: "app", Summary: "Running"})
+	digest.record(WatchEvent{Type: "DELETED", Name: "web-0", Namespace: "app", Summary: "Running"})
+
+	assert.Len(t, digest.Events, 2)
+	assert.Equal(t, 1, digest.Dropped)
+	assert.Equal(t, map[string]int{"ADDED": 1, "MODIFIED": 1, "DELETED": 1}, digest.Counts)
+	assert.Equal(t, map[string]string{"app/web-1": "Running", "app/web-0": "deleted"}, digest.Latest)
+}
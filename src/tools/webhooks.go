@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxWebhookTimeoutSeconds is the timeout above which a webhook is flagged as adding admission latency.
+const maxWebhookTimeoutSeconds = 10
+
+// webhookSpec is the part of a validating or mutating webhook the audit looks at.
+type webhookSpec struct {
+	Configuration     string
+	Kind              string
+	Name              string
+	FailurePolicy     *admissionregistrationv1.FailurePolicyType
+	TimeoutSeconds    *int32
+	NamespaceSelector *metav1.LabelSelector
+	ObjectSelector    *metav1.LabelSelector
+	Rules             []admissionregistrationv1.RuleWithOperations
+	ClientConfig      admissionregistrationv1.WebhookClientConfig
+}
+
+// WebhookAudit describes one admission webhook and the risks it poses to the API server.
+type WebhookAudit struct {
+	Configuration     string          `json:"configuration"`
+	Kind              string          `json:"kind"`
+	Webhook           string          `json:"webhook"`
+	FailurePolicy     string          `json:"failurePolicy"`
+	TimeoutSeconds    int32           `json:"timeoutSeconds"`
+	NamespaceSelector string          `json:"namespaceSelector,omitempty"`
+	ObjectSelector    string          `json:"objectSelector,omitempty"`
+	Rules             []string        `json:"rules"`
+	Backend           string          `json:"backend"`
+	Endpoints         *EndpointCounts `json:"endpoints,omitempty"`
+	Severity          string          `json:"severity"`
+	Issues            []string        `json:"issues"`
+}
+
+// WebhookAuditTool lists admission webhooks and flags the ones that can block the cluster.
+type WebhookAuditTool struct {
+	client Client
+}
+
+// NewWebhookAuditTool creates a new WebhookAuditTool with the provided Kubernetes client.
+func NewWebhookAuditTool(client Client) *WebhookAuditTool {
+	return &WebhookAuditTool{client: client}
+}
+
+// Tool returns the MCP tool definition for webhook_audit.
+func (w *WebhookAuditTool) Tool() mcp.Tool {
+	return mcp.NewTool("webhook_audit",
+		mcp.WithDescription("List Validating and Mutating admission webhooks with their failurePolicy, timeouts, namespace/object selectors, rules and backing Service, flagging webhooks whose Service is missing or has zero ready endpoints (with failurePolicy Fail this blocks every matching API request), long timeouts and webhooks that also intercept kube-system"),
+		mcp.WithBoolean("onlyIssues",
+			mcp.Description("Only return webhooks with at least one issue (default: false)"),
+		),
+	)
+}
+
+// Handler audits every webhook configuration in the cluster.
+func (w *WebhookAuditTool) Handler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	onlyIssues, _ := req.Params.Arguments["onlyIssues"].(bool)
+
+	clientset, err := w.client.Clientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clientset: %w", err)
+	}
+
+	webhooks, err := listWebhookSpecs(ctx, clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	audits := []WebhookAudit{}
+	for i := range webhooks {
+		audit := auditWebhookSpec(&webhooks[i])
+		if svc := webhooks[i].ClientConfig.Service; svc != nil {
+			endpoints, issue := webhookServiceEndpoints(ctx, clientset, svc)
+			audit.Endpoints = endpoints
+			if issue != "" {
+				audit.Issues = append(audit.Issues, issue)
+				audit.Severity = "warning"
+				if audit.FailurePolicy == string(admissionregistrationv1.Fail) {
+					audit.Severity = "critical"
+					audit.Issues = append(audit.Issues, "failurePolicy Fail: matching API requests are rejected until the backend recovers")
+				}
+			}
+		}
+		if onlyIssues && len(audit.Issues) == 0 {
+			continue
+		}
+		audits = append(audits, audit)
+	}
+
+	severityRank := map[string]int{"critical": 0, "warning": 1, "info": 2, "ok": 3}
+	sort.SliceStable(audits, func(i, j int) bool {
+		return severityRank[audits[i].Severity] < severityRank[audits[j].Severity]
+	})
+	counts := map[string]int{}
+	for _, a := range audits {
+		counts[a.Severity]++
+	}
+
+	result := map[string]any{
+		"webhooks": audits,
+		"total":    len(webhooks),
+		"counts":   counts,
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+// listWebhookSpecs flattens all validating and mutating webhook configurations.
+func listWebhookSpecs(ctx context.Context, clientset kubernetes.Interface) ([]webhookSpec, error) {
+	var specs []webhookSpec
+
+	validating, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list validatingwebhookconfigurations: %w", err)
+	}
+	for _, cfg := range validating.Items {
+		for _, wh := range cfg.Webhooks {
+			specs = append(specs, webhookSpec{
+				Configuration: cfg.Name, Kind: "Validating", Name: wh.Name,
+				FailurePolicy: wh.FailurePolicy, TimeoutSeconds: wh.TimeoutSeconds,
+				NamespaceSelector: wh.NamespaceSelector, ObjectSelector: wh.ObjectSelector,
+				Rules: wh.Rules, ClientConfig: wh.ClientConfig,
+			})
+		}
+	}
+
+	mutating, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mutatingwebhookconfigurations: %w", err)
+	}
+	for _, cfg := range mutating.Items {
+		for _, wh := range cfg.Webhooks {
+			specs = append(specs, webhookSpec{
+				Configuration: cfg.Name, Kind: "Mutating", Name: wh.Name,
+				FailurePolicy: wh.FailurePolicy, TimeoutSeconds: wh.TimeoutSeconds,
+				NamespaceSelector: wh.NamespaceSelector, ObjectSelector: wh.ObjectSelector,
+				Rules: wh.Rules, ClientConfig: wh.ClientConfig,
+			})
+		}
+	}
+
+	return specs, nil
+}
+
+// auditWebhookSpec checks the static configuration of a webhook. Backend availability is checked separately.
+func auditWebhookSpec(wh *webhookSpec) WebhookAudit {
+	audit := WebhookAudit{
+		Configuration:  wh.Configuration,
+		Kind:           wh.Kind,
+		Webhook:        wh.Name,
+		FailurePolicy:  string(admissionregistrationv1.Fail),
+		TimeoutSeconds: 10,
+		Rules:          []string{},
+		Severity:       "ok",
+		Issues:         []string{},
+	}
+	if wh.FailurePolicy != nil {
+		audit.FailurePolicy = string(*wh.FailurePolicy)
+	}
+	if wh.TimeoutSeconds != nil {
+		audit.TimeoutSeconds = *wh.TimeoutSeconds
+	}
+	audit.NamespaceSelector = labelSelectorString(wh.NamespaceSelector)
+	audit.ObjectSelector = labelSelectorString(wh.ObjectSelector)
+
+	allResources := false
+	for _, r := range wh.Rules {
+		ops := make([]string, 0, len(r.Operations))
+		for _, op := range r.Operations {
+			ops = append(ops, string(op))
+		}
+		audit.Rules = append(audit.Rules, fmt.Sprintf("%s %s/%s", strings.Join(ops, ","), strings.Join(r.APIGroups, ","), strings.Join(r.Resources, ",")))
+		if containsString(r.Resources, "*") || containsString(r.Resources, "*/*") {
+			allResources = true
+		}
+	}
+
+	switch {
+	case wh.ClientConfig.Service != nil:
+		svc := wh.ClientConfig.Service
+		port := int32(443)
+		if svc.Port != nil {
+			port = *svc.Port
+		}
+		audit.Backend = fmt.Sprintf("service %s/%s:%d", svc.Namespace, svc.Name, port)
+		if svc.Path != nil {
+			audit.Backend += *svc.Path
+		}
+	case wh.ClientConfig.URL != nil:
+		audit.Backend = "url " + *wh.ClientConfig.URL
+	}
+
+	flag := func(severity, issue string) {
+		audit.Issues = append(audit.Issues, issue)
+		if audit.Severity == "ok" || severity == "warning" {
+			audit.Severity = severity
+		}
+	}
+	failClosed := audit.FailurePolicy == string(admissionregistrationv1.Fail)
+	if audit.TimeoutSeconds > maxWebhookTimeoutSeconds {
+		flag("warning", fmt.Sprintf("timeoutSeconds %d adds up to %ds to every matching request", audit.TimeoutSeconds, audit.TimeoutSeconds))
+	}
+	if failClosed && !selectorExcludesKubeSystem(wh.NamespaceSelector) {
+		flag("info", "failurePolicy Fail without a namespaceSelector excluding kube-system; an outage can block system components")
+	}
+	if failClosed && allResources {
+		flag("warning", "intercepts all resources with failurePolicy Fail")
+	}
+	return audit
+}
+
+// webhookServiceEndpoints counts the ready endpoints of a webhook's Service and describes any problem.
+func webhookServiceEndpoints(ctx context.Context, clientset kubernetes.Interface, ref *admissionregistrationv1.ServiceReference) (*EndpointCounts, string) {
+	svc, err := clientset.CoreV1().Services(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Sprintf("backend Service %s/%s does not exist", ref.Namespace, ref.Name)
+	}
+	if err != nil {
+		return nil, fmt.Sprintf("failed to get backend Service %s/%s: %v", ref.Namespace, ref.Name, err)
+	}
+	counts, err := serviceEndpointCounts(ctx, clientset, svc)
+	if err != nil {
+		return nil, err.Error()
+	}
+	if counts.Ready == 0 {
+		return counts, fmt.Sprintf("backend Service %s/%s has zero ready endpoints", ref.Namespace, ref.Name)
+	}
+	return counts, ""
+}
+
+// selectorExcludesKubeSystem reports whether a namespaceSelector keeps the webhook away from kube-system.
+func selectorExcludesKubeSystem(selector *metav1.LabelSelector) bool {
+	if selector == nil {
+		return false
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil || s.Empty() {
+		return false
+	}
+	return !s.Matches(kubeSystemLabels)
+}
+
+// kubeSystemLabels are the labels every kube-system namespace carries.
+var kubeSystemLabels = labels.Set{"kubernetes.io/metadata.name": metav1.NamespaceSystem}
+
+// labelSelectorString renders a label selector, or "" when it is nil or empty.
+func labelSelectorString(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return ""
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return err.Error()
+	}
+	return s.String()
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAuditWebhookSpec(t *testing.T) {
+	ignore := admissionregistrationv1.Ignore
+	timeout := int32(30)
+	wh := &webhookSpec{
+		Configuration: "policy",
+		Kind:          "Validating",
+		Name:          "validate.policy.example.com",
+		Rules: []admissionregistrationv1.RuleWithOperations{{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+			Rule:       admissionregistrationv1.Rule{APIGroups: []string{"*"}, Resources: []string{"*"}},
+		}},
+		ClientConfig: admissionregistrationv1.WebhookClientConfig{
+			Service: &admissionregistrationv1.ServiceReference{Namespace: "policy", Name: "webhook"},
+		},
+	}
+
+	audit := auditWebhookSpec(wh)
+	assert.Equal(t, "Fail", audit.FailurePolicy)
+	assert.Equal(t, int32(10), audit.TimeoutSeconds)
+	assert.Equal(t, "service policy/webhook:443", audit.Backend)
+	assert.Equal(t, []string{"CREATE,UPDATE */*"}, audit.Rules)
+	assert.Equal(t, "warning", audit.Severity)
+	assert.Len(t, audit.Issues, 2)
+
+	wh.FailurePolicy = &ignore
+	wh.TimeoutSeconds = &timeout
+	wh.NamespaceSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+		Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"kube-system"},
+	}}}
+	audit = auditWebhookSpec(wh)
+	assert.Equal(t, "kubernetes.io/metadata.name notin (kube-system)", audit.NamespaceSelector)
+	assert.Equal(t, []string{"timeoutSeconds 30 adds up to 30s to every matching request"}, audit.Issues)
+}
+
+func TestSelectorExcludesKubeSystem(t *testing.T) {
+	assert.False(t, selectorExcludesKubeSystem(nil))
+	assert.False(t, selectorExcludesKubeSystem(&metav1.LabelSelector{}))
+	assert.True(t, selectorExcludesKubeSystem(&metav1.LabelSelector{MatchLabels: map[string]string{"webhooks": "enabled"}}))
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// workloadTemplate is the pod template of a Deployment, StatefulSet or DaemonSet.
+type workloadTemplate struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Template  *corev1.PodTemplateSpec
+}
+
+// listWorkloadTemplates returns the pod templates of all Deployments, StatefulSets and DaemonSets in the namespace.
+func listWorkloadTemplates(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]workloadTemplate, error) {
+	var workloads []workloadTemplate
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		workloads = append(workloads, workloadTemplate{Kind: "Deployment", Name: d.Name, Namespace: d.Namespace, Template: &d.Spec.Template})
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		workloads = append(workloads, workloadTemplate{Kind: "StatefulSet", Name: s.Name, Namespace: s.Namespace, Template: &s.Spec.Template})
+	}
+
+	daemonSets, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for i := range daemonSets.Items {
+		d := &daemonSets.Items[i]
+		workloads = append(workloads, workloadTemplate{Kind: "DaemonSet", Name: d.Name, Namespace: d.Namespace, Template: &d.Spec.Template})
+	}
+
+	return workloads, nil
+}
+
+// replicaSetsByKey indexes the namespace's ReplicaSets by "namespace/name" so pods can be traced to their
+// Deployment with workloadOwner. A listing failure yields an empty index and workloadOwner falls back to names.
+func replicaSetsByKey(ctx context.Context, clientset kubernetes.Interface, namespace string) map[string]*appsv1.ReplicaSet {
+	replicaSets := map[string]*appsv1.ReplicaSet{}
+	rsList, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return replicaSets
+	}
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		replicaSets[rs.Namespace+"/"+rs.Name] = rs
+	}
+	return replicaSets
+}